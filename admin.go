@@ -0,0 +1,150 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/zikaeroh/codies/internal/adminapi"
+	"github.com/zikaeroh/codies/internal/responder"
+	"github.com/zikaeroh/codies/internal/server"
+	"github.com/zikaeroh/ctxlog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// topIPs is the number of IPs listed by /admin/conns/top.
+const topIPs = 20
+
+// adminHandler serves the operator API, which is only accessible with the
+// configured bearer token.
+func adminHandler(srv *server.Server, stale *staleVersions, seen *seenVersions, logs *logRing, start time.Time, token string) http.Handler {
+	r := chi.NewMux()
+	r.Use(middleware.NoCache)
+	r.Use(requireToken(token))
+
+	r.Get("/overview", overviewHandler(srv, stale, seen, logs, start))
+
+	r.Get("/stats", func(w http.ResponseWriter, r *http.Request) {
+		responder.Respond(w, responder.Body(srv.Stats()), responder.Pretty(true))
+	})
+
+	r.Get("/conns/top", func(w http.ResponseWriter, r *http.Request) {
+		responder.Respond(w, responder.Body(srv.TopIPs(topIPs)), responder.Pretty(true))
+	})
+
+	r.Get("/versions/stale", func(w http.ResponseWriter, r *http.Request) {
+		responder.Respond(w, responder.Body(stale.list()), responder.Pretty(true))
+	})
+
+	r.Get("/rooms/closed", func(w http.ResponseWriter, r *http.Request) {
+		responder.Respond(w, responder.Body(srv.ClosedRooms()), responder.Pretty(true))
+	})
+
+	r.Get("/feedback", func(w http.ResponseWriter, r *http.Request) {
+		responder.Respond(w, responder.Body(srv.Feedback()), responder.Pretty(true))
+	})
+
+	r.Get("/suggestions.csv", wordSuggestionsHandler(srv))
+
+	r.Get("/rooms/{roomID}", func(w http.ResponseWriter, r *http.Request) {
+		stats, ok := srv.RoomStats(chi.URLParam(r, "roomID"))
+		if !ok {
+			responder.Respond(w, responder.Status(http.StatusNotFound))
+			return
+		}
+		responder.Respond(w, responder.Body(stats), responder.Pretty(true))
+	})
+
+	r.Get("/rooms/{roomID}/conns", func(w http.ResponseWriter, r *http.Request) {
+		conns, ok := srv.RoomConns(chi.URLParam(r, "roomID"))
+		if !ok {
+			responder.Respond(w, responder.Status(http.StatusNotFound))
+			return
+		}
+		responder.Respond(w, responder.Body(conns), responder.Pretty(true))
+	})
+
+	r.Post("/rooms/{roomID}/loglevel", logLevelHandler(srv))
+
+	r.Get("/rooms/{roomID}/audit", func(w http.ResponseWriter, r *http.Request) {
+		room := srv.FindRoomByID(chi.URLParam(r, "roomID"))
+		if room == nil {
+			responder.Respond(w, responder.Status(http.StatusNotFound))
+			return
+		}
+		responder.Respond(w, responder.Body(room.AuditLog()), responder.Pretty(true))
+	})
+
+	r.Delete("/rooms/{roomID}", func(w http.ResponseWriter, r *http.Request) {
+		if !srv.DeleteRoom(r.Context(), chi.URLParam(r, "roomID")) {
+			responder.Respond(w, responder.Status(http.StatusNotFound))
+			return
+		}
+		responder.Respond(w, responder.Status(http.StatusOK))
+	})
+
+	return r
+}
+
+func logLevelHandler(srv *server.Server) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+
+		room := srv.FindRoomByID(chi.URLParam(r, "roomID"))
+		if room == nil {
+			responder.Respond(w, responder.Status(http.StatusNotFound))
+			return
+		}
+
+		req := &adminapi.LogLevelRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			responder.Respond(w, responder.Status(http.StatusBadRequest))
+			return
+		}
+
+		if req.Level == "" {
+			room.ClearLogLevel()
+			responder.Respond(w, responder.Status(http.StatusOK))
+			return
+		}
+
+		var level zapcore.Level
+		if err := level.UnmarshalText([]byte(req.Level)); err != nil {
+			responder.Respond(w, responder.Status(http.StatusBadRequest))
+			return
+		}
+
+		d := server.LogLevelDuration
+		if req.Duration != "" {
+			var err error
+			d, err = time.ParseDuration(req.Duration)
+			if err != nil || d <= 0 {
+				responder.Respond(w, responder.Status(http.StatusBadRequest))
+				return
+			}
+		}
+
+		override := room.SetLogLevel(level, d)
+		ctxlog.Info(r.Context(), "overrode room log level", zap.String("roomID", room.ID), zap.Stringer("level", level), zap.Time("until", override.Until))
+		responder.Respond(w, responder.Body(override), responder.Pretty(true))
+	}
+}
+
+func requireToken(token string) func(http.Handler) http.Handler {
+	want := []byte("Bearer " + token)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got := []byte(r.Header.Get("Authorization"))
+			if subtle.ConstantTimeCompare(got, want) != 1 {
+				responder.Respond(w, responder.Status(http.StatusUnauthorized))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
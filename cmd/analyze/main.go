@@ -0,0 +1,91 @@
+// Command analyze summarizes a codies analytics file, as written by the
+// server with --analytics-file, or with --feed, validates and summarizes a
+// game feed file, as written with --game-feed-file.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/zikaeroh/codies/internal/analytics"
+	"github.com/zikaeroh/codies/internal/gamefeed"
+)
+
+var args = struct {
+	JSON bool `long:"json" description:"Print the report as JSON"`
+	Feed bool `long:"feed" description:"Read a game feed file, and fail if any line is invalid"`
+
+	Positional struct {
+		File string `positional-arg-name:"FILE" description:"Analytics file to summarize (default: stdin)"`
+	} `positional-args:"yes"`
+}{}
+
+func main() {
+	if _, err := flags.Parse(&args); err != nil {
+		// Default flag parser prints messages, so just exit.
+		os.Exit(1)
+	}
+
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	var r io.Reader = os.Stdin
+
+	if name := args.Positional.File; name != "" && name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	if args.Feed {
+		return runFeed(r)
+	}
+
+	report, err := analytics.Summarize(r)
+	if err != nil {
+		return err
+	}
+
+	if args.JSON {
+		return writeJSON(report)
+	}
+
+	return report.WriteText(os.Stdout)
+}
+
+func runFeed(r io.Reader) error {
+	report, err := gamefeed.Summarize(r)
+	if err != nil {
+		return err
+	}
+
+	if args.JSON {
+		err = writeJSON(report)
+	} else {
+		err = report.WriteText(os.Stdout)
+	}
+	if err != nil {
+		return err
+	}
+
+	if report.Invalid > 0 {
+		return fmt.Errorf("%d invalid lines", report.Invalid)
+	}
+	return nil
+}
+
+func writeJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
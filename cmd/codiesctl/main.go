@@ -0,0 +1,395 @@
+// Command codiesctl administers a running codies server through its admin API,
+// for operators without a browser or curl at hand. The address and token are
+// given with --addr and --token, or CODIES_ADMIN_ADDR and CODIES_ADMIN_TOKEN.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/zikaeroh/codies/internal/adminapi"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"github.com/zikaeroh/codies/internal/server"
+)
+
+type options struct {
+	Addr  string `long:"addr" env:"CODIES_ADMIN_ADDR" default:"http://localhost:5000" description:"URL of the server"`
+	Token string `long:"token" env:"CODIES_ADMIN_TOKEN" required:"true" description:"Bearer token for the admin API"`
+	JSON  bool   `long:"json" description:"Print JSON instead of text, for scripts"`
+}
+
+// app is what every command shares.
+type app struct {
+	ctx  context.Context
+	out  io.Writer
+	opts options
+}
+
+func (a *app) client() *adminapi.Client {
+	return adminapi.NewClient(a.opts.Addr, a.opts.Token, nil)
+}
+
+// writeJSON writes v indented, or on one line for a stream of values.
+func (a *app) writeJSON(v interface{}, compact bool) error {
+	enc := json.NewEncoder(a.out)
+	if !compact {
+		enc.SetIndent("", "  ")
+	}
+	return enc.Encode(v)
+}
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		cancel()
+	}()
+
+	if err := run(ctx, os.Args[1:], os.Stdout); err != nil {
+		var flagsErr *flags.Error
+		if errors.As(err, &flagsErr) && flagsErr.Type == flags.ErrHelp {
+			fmt.Println(err)
+			return
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, argv []string, out io.Writer) error {
+	a := &app{ctx: ctx, out: out}
+
+	p := flags.NewParser(&a.opts, flags.HelpFlag|flags.PassDoubleDash)
+	commands := []struct {
+		name, short string
+		data        flags.Commander
+	}{
+		{"rooms", "List rooms", &roomsCommand{app: a}},
+		{"room", "Inspect a room", &roomCommand{app: a}},
+		{"delete", "Delete rooms, disconnecting everyone in them", &deleteCommand{app: a}},
+		{"overview", "Show the server overview, or follow it", &overviewCommand{app: a}},
+	}
+	for _, c := range commands {
+		if _, err := p.AddCommand(c.name, c.short, c.short+".", c.data); err != nil {
+			return err
+		}
+	}
+
+	_, err := p.ParseArgs(argv)
+	return err
+}
+
+type roomsCommand struct {
+	app *app
+
+	Sort    string `long:"sort" choice:"clients" choice:"name" choice:"id" choice:"lastSeen" default:"clients" description:"Column to sort by"`
+	Reverse bool   `long:"reverse" description:"Reverse the order"`
+}
+
+func (c *roomsCommand) Execute([]string) error {
+	stats, err := c.app.client().Stats(c.app.ctx)
+	if err != nil {
+		return err
+	}
+
+	rooms := stats.Details
+	sortRooms(rooms, c.Sort)
+	if c.Reverse {
+		for i, j := 0, len(rooms)-1; i < j; i, j = i+1, j-1 {
+			rooms[i], rooms[j] = rooms[j], rooms[i]
+		}
+	}
+
+	if c.app.opts.JSON {
+		return c.app.writeJSON(rooms, false)
+	}
+
+	tw := tabwriter.NewWriter(c.app.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID\tNAME\tCLIENTS\tSPECTATORS\tMIRRORS\tLAST SEEN\n")
+	for _, r := range rooms {
+		name := r.Name
+		if r.Unlisted {
+			name += " (unlisted)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%s\n", r.ID, name, r.Clients, r.Spectators, r.Mirrors, r.LastSeen.Format(time.RFC3339))
+	}
+	return tw.Flush()
+}
+
+// sortRooms sorts rooms by the column: the busiest or most recently seen
+// first, or alphabetically. Ties are broken by ID, so the order is stable
+// between runs.
+func sortRooms(rooms []server.RoomStats, by string) {
+	sort.Slice(rooms, func(i, j int) bool {
+		a, b := rooms[i], rooms[j]
+		switch by {
+		case "clients":
+			if a.Clients != b.Clients {
+				return a.Clients > b.Clients
+			}
+		case "name":
+			if a.Name != b.Name {
+				return a.Name < b.Name
+			}
+		case "lastSeen":
+			if !a.LastSeen.Equal(b.LastSeen) {
+				return a.LastSeen.After(b.LastSeen)
+			}
+		}
+		return a.ID < b.ID
+	})
+}
+
+type roomCommand struct {
+	app *app
+
+	Positional struct {
+		RoomID string `positional-arg-name:"ROOM" required:"true"`
+	} `positional-args:"yes"`
+}
+
+// roomDetails is what the room command prints with --json.
+type roomDetails struct {
+	Stats *server.RoomStats      `json:"stats"`
+	Conns []server.ConnStats     `json:"conns"`
+	Audit []*protocol.AuditEntry `json:"audit"`
+}
+
+func (c *roomCommand) Execute([]string) error {
+	client := c.app.client()
+	id := c.Positional.RoomID
+
+	stats, err := client.Room(c.app.ctx, id)
+	if err != nil {
+		return roomError(id, err)
+	}
+
+	conns, err := client.Conns(c.app.ctx, id)
+	if err != nil {
+		return roomError(id, err)
+	}
+
+	audit, err := client.Audit(c.app.ctx, id)
+	if err != nil {
+		return roomError(id, err)
+	}
+
+	if c.app.opts.JSON {
+		return c.app.writeJSON(&roomDetails{Stats: stats, Conns: conns, Audit: audit}, false)
+	}
+
+	tw := tabwriter.NewWriter(c.app.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID:\t%s\n", stats.ID)
+	fmt.Fprintf(tw, "Name:\t%s\n", stats.Name)
+	fmt.Fprintf(tw, "Unlisted:\t%t\n", stats.Unlisted)
+	fmt.Fprintf(tw, "Clients:\t%d\n", stats.Clients)
+	fmt.Fprintf(tw, "Spectators:\t%d\n", stats.Spectators)
+	fmt.Fprintf(tw, "Mirrors:\t%d\n", stats.Mirrors)
+	fmt.Fprintf(tw, "Bans:\t%d\n", stats.Bans)
+	fmt.Fprintf(tw, "Last seen:\t%s\n", stats.LastSeen.Format(time.RFC3339))
+	if l := stats.LogLevel; l != nil {
+		fmt.Fprintf(tw, "Log level:\t%s until %s\n", l.Level, l.Until.Format(time.RFC3339))
+	}
+
+	if len(conns) != 0 {
+		fmt.Fprintf(tw, "\nCONN\tNICKNAME\tVERSION\tCONNECTED\n")
+		for _, conn := range conns {
+			nickname := conn.Nickname
+			if conn.Spectator {
+				nickname = "(spectator)"
+			}
+			version := conn.ClientVersion
+			if version == "" {
+				version = "-"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", conn.ID, nickname, version, conn.Connected.Format(time.RFC3339))
+		}
+	}
+
+	if len(audit) != 0 {
+		fmt.Fprintf(tw, "\nTIME\tACTION\tACTOR\tTARGET\n")
+		for _, e := range audit {
+			actor := e.ActorName
+			if actor == "" {
+				actor = string(e.Actor)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", e.Time.Format(time.RFC3339), e.Action, actor, e.Target)
+		}
+	}
+	return tw.Flush()
+}
+
+type deleteCommand struct {
+	app *app
+
+	Positional struct {
+		RoomIDs []string `positional-arg-name:"ROOM" required:"1"`
+	} `positional-args:"yes"`
+}
+
+// Execute deletes every room it can, then fails if any couldn't be. With
+// --json, it prints the IDs of those deleted.
+func (c *deleteCommand) Execute([]string) error {
+	client := c.app.client()
+
+	deleted := []string{}
+	for _, id := range c.Positional.RoomIDs {
+		if err := client.DeleteRoom(c.app.ctx, id); err != nil {
+			fmt.Fprintln(os.Stderr, roomError(id, err))
+			continue
+		}
+		deleted = append(deleted, id)
+		if !c.app.opts.JSON {
+			fmt.Fprintf(c.app.out, "deleted %s\n", id)
+		}
+	}
+
+	if c.app.opts.JSON {
+		if err := c.app.writeJSON(deleted, false); err != nil {
+			return err
+		}
+	}
+
+	if failed := len(c.Positional.RoomIDs) - len(deleted); failed > 0 {
+		return fmt.Errorf("%d of %d rooms not deleted", failed, len(c.Positional.RoomIDs))
+	}
+	return nil
+}
+
+func roomError(id string, err error) error {
+	if errors.Is(err, adminapi.ErrNotFound) {
+		return fmt.Errorf("room %q not found", id)
+	}
+	return err
+}
+
+type overviewCommand struct {
+	app *app
+
+	Follow   bool          `long:"follow" short:"f" description:"Keep polling, printing a line per poll and new errors as they're logged"`
+	Interval time.Duration `long:"interval" default:"5s" description:"Time between polls with --follow"`
+}
+
+func (c *overviewCommand) Execute([]string) error {
+	client := c.app.client()
+
+	o, err := client.Overview(c.app.ctx)
+	if err != nil {
+		return err
+	}
+
+	if !c.Follow {
+		if c.app.opts.JSON {
+			return c.app.writeJSON(o, false)
+		}
+		return writeOverview(c.app.out, o)
+	}
+
+	if c.Interval <= 0 {
+		return errors.New("--interval must be positive")
+	}
+
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	var seen time.Time
+	for {
+		if c.app.opts.JSON {
+			if err := c.app.writeJSON(o, true); err != nil {
+				return err
+			}
+		} else {
+			seen = writeOverviewLine(c.app.out, o, seen)
+		}
+
+		select {
+		case <-c.app.ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if o, err = client.Overview(c.app.ctx); err != nil {
+			if c.app.ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func writeOverview(w io.Writer, o *adminapi.Overview) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	p := o.Process
+	fmt.Fprintf(tw, "Version:\t%s\n", p.Version)
+	fmt.Fprintf(tw, "Uptime:\t%s\n", time.Duration(p.UptimeSeconds)*time.Second)
+	fmt.Fprintf(tw, "Goroutines:\t%d\n", p.Goroutines)
+	fmt.Fprintf(tw, "Heap:\t%d bytes in %d objects\n", p.HeapAlloc, p.HeapObjects)
+	if s := o.Stats; s != nil {
+		fmt.Fprintf(tw, "Rooms:\t%d\n", s.Rooms)
+		fmt.Fprintf(tw, "Clients:\t%d (%d spectators, %d mirrors, %d matchmaking)\n", s.Clients, s.Spectators, s.Mirrors, s.Matchmaking)
+	}
+	fmt.Fprintf(tw, "IPs at connection limit:\t%d of %d\n", o.Conns.AtLimit, o.Conns.IPs)
+
+	if len(o.StaleVersions) != 0 {
+		fmt.Fprintf(tw, "\nSTALE VERSION\tSTALENESS\tCOUNT\tLAST SEEN\n")
+		for _, v := range o.StaleVersions {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", v.Version, v.Staleness, v.Count, v.LastSeen.Format(time.RFC3339))
+		}
+	}
+
+	if len(o.ClientVersions) != 0 {
+		fmt.Fprintf(tw, "\nCLIENT VERSION\tSTALENESS\tLAST HOUR\tLAST SEEN\n")
+		for _, v := range o.ClientVersions {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", v.Version, v.Staleness, v.Count, v.LastSeen.Format(time.RFC3339))
+		}
+	}
+
+	if len(o.Errors) != 0 {
+		fmt.Fprintf(tw, "\nTIME\tLEVEL\tMESSAGE\n")
+		for _, e := range o.Errors {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", e.Time.Format(time.RFC3339), e.Level, e.Message)
+		}
+	}
+	return tw.Flush()
+}
+
+// writeOverviewLine writes a one line summary of the overview, then any
+// errors logged after seen. It returns the time of the newest error written.
+func writeOverviewLine(w io.Writer, o *adminapi.Overview, seen time.Time) time.Time {
+	var counts []string
+	if s := o.Stats; s != nil {
+		counts = append(counts,
+			fmt.Sprintf("rooms=%d", s.Rooms),
+			fmt.Sprintf("clients=%d", s.Clients),
+			fmt.Sprintf("spectators=%d", s.Spectators),
+			fmt.Sprintf("matchmaking=%d", s.Matchmaking),
+		)
+	}
+	counts = append(counts,
+		fmt.Sprintf("goroutines=%d", o.Process.Goroutines),
+		fmt.Sprintf("heap=%d", o.Process.HeapAlloc),
+	)
+	fmt.Fprintf(w, "%s %s\n", time.Now().Format(time.RFC3339), strings.Join(counts, " "))
+
+	for _, e := range o.Errors {
+		if e.Time.After(seen) {
+			fmt.Fprintf(w, "%s %s %s\n", e.Time.Format(time.RFC3339), strings.ToUpper(e.Level), e.Message)
+			seen = e.Time
+		}
+	}
+	return seen
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/zikaeroh/codies/internal/adminapi"
+	"github.com/zikaeroh/codies/internal/server"
+	"github.com/zikaeroh/codies/internal/version"
+	"gotest.tools/v3/assert"
+)
+
+var testTime = time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// fakeAdmin serves the parts of the admin API the commands use, from its
+// fields.
+type fakeAdmin struct {
+	mu       sync.Mutex
+	rooms    []server.RoomStats
+	conns    []server.ConnStats // Of every room.
+	versions []adminapi.ClientVersion
+	errors   []*adminapi.LogEntry
+	overview int // Requests served.
+}
+
+func (f *fakeAdmin) handler() http.Handler {
+	reply := func(w http.ResponseWriter, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+
+	r := chi.NewMux()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			next.ServeHTTP(w, r)
+		})
+	})
+
+	r.Get("/admin/stats", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, &server.Stats{Rooms: len(f.rooms), Details: f.rooms})
+	})
+
+	r.Get("/admin/overview", func(w http.ResponseWriter, r *http.Request) {
+		f.overview++
+		reply(w, &adminapi.Overview{
+			Process:        adminapi.ProcessInfo{Version: "test", Goroutines: f.overview},
+			Stats:          &server.Stats{Rooms: len(f.rooms)},
+			ClientVersions: f.versions,
+			Errors:         f.errors,
+		})
+	})
+
+	find := func(id string) *server.RoomStats {
+		for i := range f.rooms {
+			if f.rooms[i].ID == id {
+				return &f.rooms[i]
+			}
+		}
+		return nil
+	}
+
+	r.Get("/admin/rooms/{roomID}", func(w http.ResponseWriter, r *http.Request) {
+		if room := find(chi.URLParam(r, "roomID")); room != nil {
+			reply(w, room)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	r.Get("/admin/rooms/{roomID}/conns", func(w http.ResponseWriter, r *http.Request) {
+		if find(chi.URLParam(r, "roomID")) == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		reply(w, f.conns)
+	})
+
+	r.Get("/admin/rooms/{roomID}/audit", func(w http.ResponseWriter, r *http.Request) {
+		if find(chi.URLParam(r, "roomID")) == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		reply(w, []interface{}{})
+	})
+
+	r.Delete("/admin/rooms/{roomID}", func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "roomID")
+		for i := range f.rooms {
+			if f.rooms[i].ID == id {
+				f.rooms = append(f.rooms[:i], f.rooms[i+1:]...)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	return r
+}
+
+func newFakeAdmin(t *testing.T) (*fakeAdmin, string) {
+	t.Helper()
+
+	f := &fakeAdmin{
+		rooms: []server.RoomStats{
+			{ID: "a", Name: "zebra", Clients: 1, LastSeen: testTime},
+			{ID: "b", Name: "apple", Clients: 4, LastSeen: testTime.Add(-time.Hour)},
+			{ID: "c", Name: "mango", Clients: 2, LastSeen: testTime.Add(time.Hour), Unlisted: true},
+		},
+	}
+	ts := httptest.NewServer(f.handler())
+	t.Cleanup(ts.Close)
+	return f, ts.URL
+}
+
+func runTest(ctx context.Context, t *testing.T, addr string, argv ...string) (string, error) {
+	t.Helper()
+
+	var out bytes.Buffer
+	err := run(ctx, append([]string{"--addr", addr, "--token", "secret"}, argv...), &out)
+	return out.String(), err
+}
+
+func TestRooms(t *testing.T) {
+	_, addr := newFakeAdmin(t)
+	ctx := context.Background()
+
+	ids := func(argv ...string) []string {
+		t.Helper()
+
+		out, err := runTest(ctx, t, addr, append([]string{"--json", "rooms"}, argv...)...)
+		assert.NilError(t, err)
+
+		var rooms []server.RoomStats
+		assert.NilError(t, json.Unmarshal([]byte(out), &rooms))
+		var ids []string
+		for _, r := range rooms {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+
+	assert.DeepEqual(t, ids(), []string{"b", "c", "a"})
+	assert.DeepEqual(t, ids("--sort", "name"), []string{"b", "c", "a"})
+	assert.DeepEqual(t, ids("--sort", "name", "--reverse"), []string{"a", "c", "b"})
+	assert.DeepEqual(t, ids("--sort", "lastSeen"), []string{"c", "a", "b"})
+	assert.DeepEqual(t, ids("--sort", "id"), []string{"a", "b", "c"})
+
+	out, err := runTest(ctx, t, addr, "rooms", "--sort", "id")
+	assert.NilError(t, err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	assert.Equal(t, len(lines), 4)
+	assert.Assert(t, strings.HasPrefix(lines[0], "ID "))
+	assert.Assert(t, strings.Contains(lines[3], "mango (unlisted)"))
+
+	_, err = runTest(ctx, t, addr, "rooms", "--sort", "size")
+	assert.ErrorContains(t, err, "size")
+}
+
+func TestRoom(t *testing.T) {
+	f, addr := newFakeAdmin(t)
+	f.conns = []server.ConnStats{
+		{ID: "p1", Nickname: "amy", ClientVersion: "v1.2.3", Connected: testTime},
+		{ID: "s1", Spectator: true, Connected: testTime},
+	}
+	ctx := context.Background()
+
+	out, err := runTest(ctx, t, addr, "room", "b")
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(out, "apple"))
+	assert.Assert(t, strings.Contains(out, "amy          v1.2.3"), out)
+	assert.Assert(t, strings.Contains(out, "(spectator)  -"), out)
+
+	out, err = runTest(ctx, t, addr, "--json", "room", "b")
+	assert.NilError(t, err)
+	var details roomDetails
+	assert.NilError(t, json.Unmarshal([]byte(out), &details))
+	assert.Equal(t, details.Stats.Clients, 4)
+	assert.DeepEqual(t, details.Conns, f.conns)
+
+	_, err = runTest(ctx, t, addr, "room", "missing")
+	assert.Error(t, err, `room "missing" not found`)
+}
+
+func TestDelete(t *testing.T) {
+	f, addr := newFakeAdmin(t)
+	ctx := context.Background()
+
+	out, err := runTest(ctx, t, addr, "delete", "a", "missing", "c")
+	assert.Error(t, err, "1 of 3 rooms not deleted")
+	assert.Equal(t, out, "deleted a\ndeleted c\n")
+	assert.Equal(t, len(f.rooms), 1)
+
+	out, err = runTest(ctx, t, addr, "--json", "delete", "b")
+	assert.NilError(t, err)
+	var deleted []string
+	assert.NilError(t, json.Unmarshal([]byte(out), &deleted))
+	assert.DeepEqual(t, deleted, []string{"b"})
+	assert.Equal(t, len(f.rooms), 0)
+}
+
+func TestUnauthorized(t *testing.T) {
+	_, addr := newFakeAdmin(t)
+
+	var out bytes.Buffer
+	err := run(context.Background(), []string{"--addr", addr, "--token", "wrong", "rooms"}, &out)
+	assert.ErrorContains(t, err, "unauthorized")
+}
+
+func TestOverview(t *testing.T) {
+	f, addr := newFakeAdmin(t)
+	f.versions = []adminapi.ClientVersion{{Version: "v1.2.3", Staleness: version.Current, Count: 7, LastSeen: testTime}}
+
+	out, err := runTest(context.Background(), t, addr, "overview")
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(out, "v1.2.3          current    7"), out)
+}
+
+func TestOverviewFollow(t *testing.T) {
+	f, addr := newFakeAdmin(t)
+	f.errors = []*adminapi.LogEntry{{Time: testTime, Level: "error", Message: "first"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Log another error once the first poll has been made, then stop after
+	// the third.
+	go func() {
+		for {
+			time.Sleep(time.Millisecond)
+			f.mu.Lock()
+			polls := f.overview
+			if polls == 1 && len(f.errors) == 1 {
+				f.errors = append(f.errors, &adminapi.LogEntry{Time: testTime.Add(time.Second), Level: "warn", Message: "second"})
+			}
+			f.mu.Unlock()
+			if polls >= 3 {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	out, err := runTest(ctx, t, addr, "overview", "--follow", "--interval", "10ms")
+	assert.NilError(t, err)
+
+	// Each error is printed once, however many polls include it.
+	assert.Equal(t, strings.Count(out, "ERROR first"), 1)
+	assert.Equal(t, strings.Count(out, "WARN second"), 1)
+	assert.Assert(t, strings.Count(out, "rooms=3") >= 2)
+}
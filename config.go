@@ -0,0 +1,68 @@
+package main
+
+import (
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"github.com/zikaeroh/codies/internal/responder"
+	"github.com/zikaeroh/codies/internal/version"
+)
+
+// effectiveConfig gathers the configuration from the parsed arguments. Any
+// optional behavior should register itself as a feature here, so that it's
+// reported consistently at startup, by --print-config, and by /api/info.
+func effectiveConfig(mode *runMode) *protocol.Config {
+	cfg := &protocol.Config{
+		Version:         version.Version(),
+		ProtocolVersion: protocol.Version,
+		BuildTime:       version.BuildTime(),
+		GoVersion:       runtime.Version(),
+		Mode:            mode.Name,
+		Limits: protocol.ConfigLimits{
+			MaxRooms:           args.MaxRooms,
+			MaxConnsPerIP:      args.MaxConnsPerIP,
+			MaxCustomPacks:     args.MaxCustomPacks,
+			MaxCustomPackBytes: args.MaxCustomPackBytes,
+			MinRevealPlayers:   args.MinRevealPlayers,
+			MatchSize:          args.MatchSize,
+		},
+	}
+
+	cfg.Register("versionCheck", mode.VersionCheck)
+	cfg.Register("originCheck", mode.OriginCheck)
+	cfg.Register("metrics", mode.Metrics)
+	cfg.Register("packsDir", args.PacksDir != "")
+	cfg.Register("remotePacks", !args.DisableRemotePacks)
+	cfg.Register("webhookAllowlist", len(args.WebhookAllowlist) != 0)
+	cfg.Register("admin", args.AdminToken != "")
+	cfg.Register("realIP", args.RealIP)
+	cfg.Register("redactIPs", args.RedactIPs)
+	cfg.Register("privacyStrict", args.PrivacyStrict)
+	cfg.Register("versionGrace", args.VersionGrace > 0)
+	cfg.Register("seed", args.Seed != 0)
+	cfg.Register("analytics", args.AnalyticsFile != "")
+	cfg.Register("gameFeed", args.GameFeedFile != "")
+	cfg.Register("wordSuggestionsFile", args.WordSuggestionsFile != "")
+	cfg.Register("resumeManifest", args.ResumeManifest != "")
+	cfg.Register("heapThreshold", args.HeapThreshold != 0)
+	cfg.Register("seatGrace", args.SeatGrace > 0)
+	cfg.Register("spectatorOverflow", args.SpectatorOverflow)
+	cfg.Register("h2c", args.H2C)
+
+	return cfg
+}
+
+func infoHandler(cfg *protocol.Config, start time.Time) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		responder.Respond(w,
+			responder.Body(&protocol.InfoResponse{
+				Config:        *cfg,
+				StartTime:     start,
+				UptimeSeconds: int64(time.Since(start) / time.Second),
+			}),
+			responder.Pretty(true),
+		)
+	}
+}
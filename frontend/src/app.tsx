@@ -9,7 +9,15 @@ import { StaticView } from './pages/staticView';
 export const App = () => {
     const [gameProps, setGameProps] = React.useState<GameProps | undefined>();
     const leave = React.useCallback(() => setGameProps(undefined), []);
-    const onLogin = React.useCallback((roomID, nickname) => setGameProps({ roomID, nickname, leave }), [leave]);
+    const rejoin = React.useCallback(
+        (roomID: string, nickname: string, merge?: string) =>
+            setGameProps({ roomID, nickname, merge, leave, rejoin }),
+        [leave]
+    );
+    const onLogin = React.useCallback((roomID, nickname) => setGameProps({ roomID, nickname, leave, rejoin }), [
+        leave,
+        rejoin,
+    ]);
 
     if (process.env.NODE_ENV === 'development') {
         const query = querystring.parse(window.location.search.substring(1));
@@ -21,7 +29,7 @@ export const App = () => {
     if (gameProps) {
         return (
             <ServerTimeProvider>
-                <Game {...gameProps} />
+                <Game key={gameProps.roomID} {...gameProps} />
             </ServerTimeProvider>
         );
     }
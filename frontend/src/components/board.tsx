@@ -46,6 +46,17 @@ function tileStyle(tile: StateTile, spymaster: boolean): React.CSSProperties {
     return teamStyle(teamHue, tile.revealed, spymaster);
 }
 
+// Each size smaller than full shrinks the word by this much, relative to the
+// tile's font size, which already depends on the screen.
+const fitStep = 0.15;
+
+function fitStyle(tile: StateTile): React.CSSProperties | undefined {
+    if (!tile.fit?.size) {
+        return undefined;
+    }
+    return { fontSize: `${Math.max(1 - tile.fit.size * fitStep, 0.4)}em` };
+}
+
 const useTileStyles = makeStyles((theme: Theme) =>
     createStyles({
         button: {
@@ -129,7 +140,7 @@ const Tile = React.memo(function Tile({ row, col, onClick, tile, spymaster, myTu
                 disabled={disabled}
             >
                 <Typography variant="h6" className={classes.typo}>
-                    {tile.word}
+                    <span style={fitStyle(tile)}>{tile.word}</span>
                 </Typography>
             </Button>
             {explode ? (
@@ -6,7 +6,16 @@ import { DeepReadonly } from 'ts-essentials';
 import { assertIsDefined, assertNever, noop, reloadOutdatedPage, websocketUrl } from '../common';
 import { useServerTime } from '../hooks';
 import { version as codiesVersion } from '../metadata.json';
-import { ClientNote, PartialClientNote, ServerNote, State, StatePlayer, TimeResponse, WordPack } from '../protocol';
+import {
+    ClientNote,
+    parseCloseHint,
+    PartialClientNote,
+    ServerNote,
+    State,
+    StatePlayer,
+    TimeResponse,
+    WordPack,
+} from '../protocol';
 import { GameView, Sender } from './gameView';
 import { Loading } from './loading';
 
@@ -33,8 +42,11 @@ function useSender(dispatch: (action: PartialClientNote) => void): Sender {
             changeTurnMode: (timed: boolean) => dispatch({ method: 'changeTurnMode', params: { timed } }),
             changeTurnTime: (seconds: number) => dispatch({ method: 'changeTurnTime', params: { seconds } }),
             addPacks: (packs: WordPack[]) => dispatch({ method: 'addPacks', params: { packs } }),
+            addPackURL: (url: string) => dispatch({ method: 'addPackURL', params: { url } }),
             removePack: (num: number) => dispatch({ method: 'removePack', params: { num } }),
             changeHideBomb: (hideBomb: boolean) => dispatch({ method: 'changeHideBomb', params: { hideBomb } }),
+            kick: (playerID: string) => dispatch({ method: 'kick', params: { playerID } }),
+            ban: (playerID: string) => dispatch({ method: 'ban', params: { playerID } }),
         };
     }, [dispatch]);
 }
@@ -58,9 +70,21 @@ function usePlayer(state?: State): { pState: StatePlayer; pTeam: number } | unde
 
 const reconnectAttempts = 2;
 
-function useWS(roomID: string, nickname: string, dead: () => void, onOpen: () => void) {
+// How long to wait before reconnecting, when the close didn't say.
+const defaultReconnectInterval = 5000;
+
+function useWS(
+    roomID: string,
+    nickname: string,
+    merge: string | undefined,
+    resume: React.MutableRefObject<string | undefined>,
+    dead: () => void,
+    onOpen: () => void,
+    onMerged: (roomID: string) => void
+) {
     const didUnmount = React.useRef(false);
     const retry = React.useRef(0);
+    const interval = React.useRef(defaultReconnectInterval);
 
     return useWebSocket(socketUrl, {
         // The names here matter; explicitly naming them so that renaming
@@ -68,8 +92,24 @@ function useWS(roomID: string, nickname: string, dead: () => void, onOpen: () =>
         //
         // X-CODIES-VERSION would be cleaner, but the WS hook doesn't
         // support anything but query params.
-        queryParams: { roomID: roomID, nickname: nickname, codiesVersion: codiesVersion },
+        //
+        // Read by the hook as each connection opens, so that a reconnect
+        // carries the latest resume token, and gets its seat back.
+        get queryParams() {
+            return {
+                roomID: roomID,
+                nickname: nickname,
+                codiesVersion: codiesVersion,
+                ...(merge !== undefined ? { merge: merge } : {}),
+                ...(resume.current !== undefined ? { resume: resume.current } : {}),
+            };
+        },
         reconnectAttempts,
+        // Read by the hook once shouldReconnect has said yes, so that the
+        // wait can follow the close's hint.
+        get reconnectInterval() {
+            return interval.current;
+        },
         onMessage: () => {
             retry.current = 0;
         },
@@ -78,9 +118,19 @@ function useWS(roomID: string, nickname: string, dead: () => void, onOpen: () =>
             if (e.code === 4418) {
                 reloadOutdatedPage();
             }
+
+            // The server hints whether to reconnect, and where; closes
+            // without a hint, like a dropped connection, are retried.
+            const hint = parseCloseHint(e.reason);
+            if (hint?.roomId !== undefined) {
+                onMerged(hint.roomId);
+            } else if (hint?.retry === false) {
+                dead();
+            }
         },
-        shouldReconnect: () => {
-            if (didUnmount.current) {
+        shouldReconnect: (e: CloseEvent) => {
+            const hint = parseCloseHint(e.reason);
+            if (didUnmount.current || hint?.retry === false || hint?.roomId !== undefined) {
                 return false;
             }
 
@@ -91,6 +141,7 @@ function useWS(roomID: string, nickname: string, dead: () => void, onOpen: () =>
                 return false;
             }
 
+            interval.current = hint?.afterMs ?? defaultReconnectInterval;
             return true;
         },
     });
@@ -172,14 +223,34 @@ function useStateReducer(sendNote: (r: ClientNote) => void) {
 export interface GameProps {
     roomID: string;
     nickname: string;
+    merge?: string; // A seat kept by a merge; see the merged note.
     leave: () => void;
+    rejoin: (roomID: string, nickname: string, merge?: string) => void;
 }
 
 export const Game = (props: DeepReadonly<GameProps>) => {
     const nickname = React.useRef(props.nickname); // Preserve a nickname for use in reconnects.
+    const mergeToken = React.useRef<string | undefined>();
+    const resumeToken = React.useRef<string | undefined>();
+
+    const { rejoin } = props;
+    const onMerged = React.useCallback(
+        (roomID: string) => {
+            rejoin(roomID, nickname.current, mergeToken.current);
+        },
+        [rejoin]
+    );
 
     const syncTime = useSyncedServerTime();
-    const { sendJsonMessage, lastJsonMessage } = useWS(props.roomID, nickname.current, props.leave, syncTime);
+    const { sendJsonMessage, lastJsonMessage } = useWS(
+        props.roomID,
+        nickname.current,
+        props.merge,
+        resumeToken,
+        props.leave,
+        syncTime,
+        onMerged
+    );
 
     const reducer = useStateReducer(sendJsonMessage);
     const [state, dispatch] = React.useReducer(reducer, undefined);
@@ -197,6 +268,36 @@ export const Game = (props: DeepReadonly<GameProps>) => {
             case 'state':
                 dispatch({ method: 'setState', state: note.params });
                 break;
+            case 'error':
+                console.warn(note.params.message);
+                break;
+            case 'merged':
+                mergeToken.current = note.params.token;
+                break;
+            case 'resumeToken':
+                resumeToken.current = note.params.token;
+                break;
+            case 'notification':
+            case 'debugInfo':
+            case 'bandwidth':
+            case 'clueSuggestions':
+            case 'mirrorToken':
+            case 'webhook':
+            case 'chat':
+            case 'poll':
+            case 'optionsChanged':
+            case 'auditLog':
+            case 'bans':
+            case 'playerJoined':
+            case 'playerLeft':
+            case 'playerUpdated':
+            case 'ack':
+            case 'qualityDegraded':
+            case 'qualityRestored':
+            case 'timeSync':
+            case 'mergeRequest':
+            case 'feedbackPrompt':
+                break;
             default:
                 assertNever(note.method);
         }
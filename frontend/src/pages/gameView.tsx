@@ -19,6 +19,8 @@ import { green, orange } from '@material-ui/core/colors';
 import {
     Add,
     ArrowBack,
+    Block,
+    Close,
     Delete,
     Link,
     Person,
@@ -41,7 +43,15 @@ import { isDefined, nameofFactory, noComplete } from '../common';
 import { Board } from '../components/board';
 import { ClipboardButton } from '../components/clipboard';
 import { useServerTime } from '../hooks';
-import { RoomState, StatePlayer, StateTeams, StateTimer, StateWordList, WordPack } from '../protocol';
+import {
+    RoomState,
+    StateBackfill,
+    StatePlayer,
+    StateTeams,
+    StateTimer,
+    StateWordList,
+    WordPack,
+} from '../protocol';
 import { teamSpecs } from '../teams';
 
 export interface Sender {
@@ -56,8 +66,11 @@ export interface Sender {
     changeTurnMode: (timed: boolean) => void;
     changeTurnTime: (seconds: number) => void;
     addPacks: (packs: { name: string; words: string[] }[]) => void;
+    addPackURL: (url: string) => void;
     removePack: (num: number) => void;
     changeHideBomb: (HideBomb: boolean) => void;
+    kick: (playerID: string) => void;
+    ban: (playerID: string) => void;
 }
 
 const useCenterStyles = makeStyles((_theme: Theme) =>
@@ -154,6 +167,7 @@ interface HeaderProps {
     turn: number;
     wordsLeft: number[];
     timer: StateTimer | undefined | null;
+    spectators: number;
 }
 
 const Header = React.memo(function Header({
@@ -164,6 +178,7 @@ const Header = React.memo(function Header({
     turn,
     wordsLeft,
     timer,
+    spectators,
 }: DeepReadonly<HeaderProps>) {
     return (
         <Grid container direction="row" justify="space-between" alignItems="center" spacing={2}>
@@ -185,6 +200,7 @@ const Header = React.memo(function Header({
                         );
                     })}
                 </h1>
+                {spectators > 0 ? <Typography variant="caption">{spectators} watching</Typography> : null}
             </Grid>
             <Grid item xs style={{ textAlign: 'center' }}>
                 <CenterText winner={winner} timer={timer} turn={turn} myTurn={myTurn} />
@@ -366,6 +382,7 @@ interface SidebarTeamsProps {
     teams: StateTeams;
     pTeam: number;
     playerID: string;
+    host: boolean;
 }
 
 const SidebarTeams = React.memo(function SidebarTeams({
@@ -373,6 +390,7 @@ const SidebarTeams = React.memo(function SidebarTeams({
     teams,
     pTeam,
     playerID,
+    host,
 }: DeepReadonly<SidebarTeamsProps>) {
     const theme = useTheme();
     const nameShade = theme.palette.type === 'dark' ? 400 : 600;
@@ -414,9 +432,30 @@ const SidebarTeams = React.memo(function SidebarTeams({
                                         gridColumn: i + 1,
                                         color: teamSpecs[i].hue[nameShade],
                                         fontStyle: member.playerID === playerID ? 'italic' : undefined,
+                                        // Held for them while they reconnect.
+                                        opacity: member.disconnected ? 0.5 : undefined,
                                     }}
+                                    title={member.disconnected ? 'Reconnecting' : undefined}
                                 >
                                     {member.spymaster ? `[${member.nickname}]` : member.nickname}
+                                    {host && member.playerID !== playerID ? (
+                                        <IconButton
+                                            size="small"
+                                            aria-label={`Kick ${member.nickname}`}
+                                            onClick={() => send.kick(member.playerID)}
+                                        >
+                                            <Close fontSize="inherit" />
+                                        </IconButton>
+                                    ) : null}
+                                    {host && member.playerID !== playerID ? (
+                                        <IconButton
+                                            size="small"
+                                            aria-label={`Ban ${member.nickname}`}
+                                            onClick={() => send.ban(member.playerID)}
+                                        >
+                                            <Block fontSize="inherit" />
+                                        </IconButton>
+                                    ) : null}
                                 </span>
                             ))}
                         </React.Fragment>
@@ -449,12 +488,27 @@ const useSidebarPacksStyles = makeStyles((_theme: Theme) =>
     })
 );
 
+// See game.MaxPacks.
+const maxPacks = 13;
+
+function packLabel(pack: DeepReadonly<StateWordList>): string {
+    if (pack.custom) {
+        return `Custom: ${pack.name}`;
+    }
+    if (pack.language) {
+        return `${pack.name} (${pack.language.toUpperCase()})`;
+    }
+    return pack.name;
+}
+
 interface SidebarPacksProps {
     send: Sender;
     lists: StateWordList[];
+    backfill: StateBackfill | undefined;
+    host: boolean;
 }
 
-const SidebarPacks = React.memo(function SidebarPacks({ send, lists }: DeepReadonly<SidebarPacksProps>) {
+const SidebarPacks = React.memo(function SidebarPacks({ send, lists, backfill, host }: DeepReadonly<SidebarPacksProps>) {
     const classes = useSidebarPacksStyles();
 
     const wordCount = React.useMemo(
@@ -469,11 +523,18 @@ const SidebarPacks = React.memo(function SidebarPacks({ send, lists }: DeepReado
     );
 
     const [uploadOpen, setUploadOpen] = React.useState(false);
+    const [packURL, setPackURL] = React.useState('');
 
     return (
         <>
             <h2>Packs</h2>
             <p style={{ fontStyle: 'italic' }}>{wordCount} words in the selected packs.</p>
+            {!backfill ? null : (
+                <p style={{ fontStyle: 'italic' }}>
+                    {backfill.count} cards drawn from {backfill.pack}
+                    {backfill.language ? ` (${backfill.language.toUpperCase()})` : ''}.
+                </p>
+            )}
             <div style={{ display: 'grid', gridGap: '0.5rem' }}>
                 {lists.map((pack, i) => (
                     <div key={i} style={{ gridRow: i + 1 }}>
@@ -481,19 +542,19 @@ const SidebarPacks = React.memo(function SidebarPacks({ send, lists }: DeepReado
                             type="button"
                             variant={pack.enabled ? 'contained' : 'outlined'}
                             size="small"
-                            style={{ width: pack.custom && !pack.enabled ? '90%' : '100%' }}
+                            style={{ width: host && pack.custom ? '90%' : '100%' }}
                             onClick={() => send.changePack(i, !pack.enabled)}
                         >
-                            {pack.custom ? `Custom: ${pack.name}` : pack.name}
+                            {packLabel(pack)}
                         </Button>
-                        {pack.custom && !pack.enabled ? (
+                        {host && pack.custom ? (
                             <IconButton size="small" style={{ width: '10%' }} onClick={() => send.removePack(i)}>
                                 <Delete />
                             </IconButton>
                         ) : null}
                     </div>
                 ))}
-                {lists.length >= 10 ? null : (
+                {!host || lists.length >= maxPacks ? null : (
                     <>
                         <Button
                             type="button"
@@ -504,6 +565,25 @@ const SidebarPacks = React.memo(function SidebarPacks({ send, lists }: DeepReado
                         >
                             Upload packs
                         </Button>
+                        <div style={{ gridRow: lists.length + 3, display: 'flex' }}>
+                            <TextField
+                                size="small"
+                                placeholder="Or a pack's URL"
+                                value={packURL}
+                                onChange={(e) => setPackURL(e.target.value)}
+                                style={{ flexGrow: 1 }}
+                            />
+                            <IconButton
+                                size="small"
+                                disabled={!packURL}
+                                onClick={() => {
+                                    send.addPackURL(packURL);
+                                    setPackURL('');
+                                }}
+                            >
+                                <Link />
+                            </IconButton>
+                        </div>
                         <DropzoneDialog
                             acceptedFiles={['.txt']}
                             cancelButtonText={'cancel'}
@@ -551,17 +631,19 @@ interface SidebarProps {
     send: Sender;
     teams: StateTeams;
     lists: StateWordList[];
+    backfill: StateBackfill | undefined;
     pTeam: number;
     playerID: string;
+    host: boolean;
     version: number;
     timer: StateTimer | undefined | null;
 }
 
-const Sidebar = ({ send, teams, lists, pTeam, playerID, version, timer }: DeepReadonly<SidebarProps>) => {
+const Sidebar = ({ send, teams, lists, backfill, pTeam, playerID, host, version, timer }: DeepReadonly<SidebarProps>) => {
     return (
         <>
-            <SidebarTeams send={send} teams={teams} pTeam={pTeam} playerID={playerID} />
-            <SidebarPacks send={send} lists={lists} />
+            <SidebarTeams send={send} teams={teams} pTeam={pTeam} playerID={playerID} host={host} />
+            <SidebarPacks send={send} lists={lists} backfill={backfill} host={host} />
             {!isDefined(timer) ? null : (
                 <div style={{ textAlign: 'left', marginTop: '1rem' }}>
                     <TimerSlider version={version} timer={timer} onCommit={send.changeTurnTime} />
@@ -775,13 +857,14 @@ export const GameView = ({ roomID, leave, send, state, pState, pTeam }: DeepRead
                         winner={state.winner}
                         spymaster={pState.spymaster}
                         turn={state.turn}
-                        wordsLeft={state.wordsLeft}
+                        wordsLeft={state.wordsLeft ?? []}
                         timer={state.timer}
+                        spectators={state.spectators}
                     />
                 </div>
                 <div className={classes.board}>
                     <Board
-                        words={state.board}
+                        words={state.board ?? []}
                         onClick={send.reveal}
                         spymaster={pState.spymaster}
                         myTurn={myTurn}
@@ -801,9 +884,11 @@ export const GameView = ({ roomID, leave, send, state, pState, pTeam }: DeepRead
                     <Sidebar
                         send={send}
                         teams={state.teams}
-                        lists={state.lists}
+                        lists={state.lists ?? []}
+                        backfill={state.backfill}
                         pTeam={pTeam}
                         playerID={pState.playerID}
+                        host={pState.host}
                         version={state.version}
                         timer={state.timer}
                     />
@@ -94,6 +94,12 @@ export const Login = (props: LoginProps) => {
 
                             await response.text();
 
+                            if (response.status === 410) {
+                                setErrorMessage('Room has closed.');
+                                setRoomID(undefined);
+                                return;
+                            }
+
                             if (!response.ok) {
                                 setErrorMessage('Room does not exist.');
                                 setRoomID(undefined);
@@ -108,6 +114,7 @@ export const Login = (props: LoginProps) => {
                                     roomName: d.roomName,
                                     roomPass: d.roomPass,
                                     create: d.create,
+                                    wait: d.create,
                                 });
                                 response = await fetch('/api/room', { method: 'POST', body: reqBody, headers });
 
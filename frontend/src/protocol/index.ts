@@ -11,12 +11,19 @@ const WordPack = myzod.object({
     words: myzod.array(myzod.string()),
 });
 
+export type TeamConstraint = Infer<typeof TeamConstraint>;
+const TeamConstraint = myzod.object({
+    a: myzod.string(),
+    b: myzod.string(),
+    apart: myzod.boolean().optional(),
+});
+
 export type PartialClientNote = Infer<typeof PartialClientNote>;
 export type PartialClientNoteSender = (r: PartialClientNote) => void;
 const PartialClientNote = myzod.union([
     myzod.object({
         method: myzod.literal('newGame'),
-        params: myzod.object({}),
+        params: myzod.object({ force: myzod.boolean().optional() }),
     }),
     myzod.object({
         method: myzod.literal('endTurn'),
@@ -26,9 +33,18 @@ const PartialClientNote = myzod.union([
         method: myzod.literal('randomizeTeams'),
         params: myzod.object({}),
     }),
+    myzod.object({
+        method: myzod.literal('changeTeamConstraints'),
+        params: myzod.object({ constraints: myzod.array(TeamConstraint) }),
+    }),
     myzod.object({
         method: myzod.literal('reveal'),
-        params: myzod.object({ row: myzod.number(), col: myzod.number() }),
+        params: myzod.object({
+            board: myzod.number().optional(),
+            row: myzod.number(),
+            col: myzod.number(),
+            force: myzod.boolean().optional(),
+        }),
     }),
     myzod.object({
         method: myzod.literal('changeTeam'),
@@ -54,12 +70,40 @@ const PartialClientNote = myzod.union([
         method: myzod.literal('changeTurnTime'),
         params: myzod.object({ seconds: myzod.number() }),
     }),
+    myzod.object({
+        method: myzod.literal('changePenalties'),
+        params: myzod.object({ limit: myzod.number() }),
+    }),
+    myzod.object({
+        method: myzod.literal('useHint'),
+        params: myzod.object({ board: myzod.number().optional() }),
+    }),
+    myzod.object({
+        method: myzod.literal('score'),
+        params: myzod.object({ team: myzod.number(), delta: myzod.number() }),
+    }),
+    myzod.object({
+        method: myzod.literal('declareWinner'),
+        params: myzod.object({ team: myzod.number() }),
+    }),
+    myzod.object({
+        method: myzod.literal('resetWins'),
+        params: myzod.object({}),
+    }),
+    myzod.object({
+        method: myzod.literal('undo'),
+        params: myzod.object({}),
+    }),
     myzod.object({
         method: myzod.literal('addPacks'),
         params: myzod.object({
             packs: myzod.array(WordPack),
         }),
     }),
+    myzod.object({
+        method: myzod.literal('addPackURL'),
+        params: myzod.object({ name: myzod.string().optional(), url: myzod.string() }),
+    }),
     myzod.object({
         method: myzod.literal('removePack'),
         params: myzod.object({ num: myzod.number() }),
@@ -68,40 +112,234 @@ const PartialClientNote = myzod.union([
         method: myzod.literal('changeHideBomb'),
         params: myzod.object({ hideBomb: myzod.boolean() }),
     }),
+    myzod.object({
+        method: myzod.literal('rateGame'),
+        params: myzod.object({
+            game: myzod.number(),
+            rating: myzod.number(),
+            tag: myzod.string().optional(), // tooLong, tooEasy, or fun.
+        }),
+    }),
+    myzod.object({
+        method: myzod.literal('suggestWord'),
+        params: myzod.object({
+            word: myzod.string(),
+            pack: myzod.string(),
+        }),
+    }),
+    myzod.object({
+        method: myzod.literal('chat'),
+        params: myzod.object({ text: myzod.string() }),
+    }),
+    myzod.object({
+        method: myzod.literal('openPoll'),
+        params: myzod.object({
+            question: myzod.string(),
+            options: myzod.array(myzod.string()),
+            hidden: myzod.boolean(),
+            seconds: myzod.number(),
+        }),
+    }),
+    myzod.object({
+        method: myzod.literal('vote'),
+        params: myzod.object({ poll: myzod.number(), option: myzod.number() }),
+    }),
+    myzod.object({
+        method: myzod.literal('closePoll'),
+        params: myzod.object({ poll: myzod.number() }),
+    }),
+    myzod.object({
+        method: myzod.literal('giveClue'),
+        params: myzod.object({ board: myzod.number().optional(), word: myzod.string(), count: myzod.number() }),
+    }),
+    myzod.object({
+        method: myzod.literal('changeBoundClues'),
+        params: myzod.object({ boundClues: myzod.boolean() }),
+    }),
+    myzod.object({
+        method: myzod.literal('changeSuggestClues'),
+        params: myzod.object({ suggestClues: myzod.boolean() }),
+    }),
+    myzod.object({
+        method: myzod.literal('changeMirrorDelay'),
+        params: myzod.object({ seconds: myzod.number() }),
+    }),
+    myzod.object({
+        method: myzod.literal('changeSpymasters'),
+        params: myzod.object({ limit: myzod.number(), confirmClues: myzod.boolean() }),
+    }),
+    myzod.object({
+        method: myzod.literal('changeNotifications'),
+        params: myzod.object({ off: myzod.boolean(), disabled: myzod.array(myzod.string()) }),
+    }),
+    myzod.object({
+        method: myzod.literal('updateOptions'),
+        params: myzod.object({
+            timed: myzod.boolean().optional(),
+            turnTime: myzod.number().optional(),
+            hideBomb: myzod.boolean().optional(),
+            boundClues: myzod.boolean().optional(),
+            enforceGuessLimit: myzod.boolean().optional(),
+            suggestClues: myzod.boolean().optional(),
+            penaltyLimit: myzod.number().optional(),
+            spymasterLimit: myzod.number().optional(),
+            confirmClues: myzod.boolean().optional(),
+            mirrorDelay: myzod.number().optional(),
+            boards: myzod.number().optional(),
+            rows: myzod.number().optional(),
+            cols: myzod.number().optional(),
+            bombs: myzod.number().optional(),
+            minSpymasters: myzod.number().optional(),
+            minGuessers: myzod.number().optional(),
+            hintBudget: myzod.array(myzod.number()).optional(),
+            feedback: myzod.boolean().optional(),
+            openPolls: myzod.boolean().optional(),
+            backfillWords: myzod.boolean().optional(),
+        }),
+    }),
+    myzod.object({
+        method: myzod.literal('reportDesync'),
+        params: myzod.object({ hash: myzod.string() }),
+    }),
+    myzod.object({
+        method: myzod.literal('mintMirrorToken'),
+        params: myzod.object({}),
+    }),
+    myzod.object({
+        method: myzod.literal('revokeMirrorToken'),
+        params: myzod.object({ token: myzod.string() }),
+    }),
+    myzod.object({
+        method: myzod.literal('getAuditLog'),
+        params: myzod.object({}),
+    }),
+    myzod.object({
+        method: myzod.literal('setWebhook'),
+        params: myzod.object({ url: myzod.string() }),
+    }),
+    myzod.object({
+        method: myzod.literal('getWebhook'),
+        params: myzod.object({}),
+    }),
+    myzod.object({
+        method: myzod.literal('requestMerge'),
+        params: myzod.object({ roomID: myzod.string() }),
+    }),
+    myzod.object({
+        method: myzod.literal('answerMerge'),
+        params: myzod.object({ id: myzod.string(), accept: myzod.boolean() }),
+    }),
+    myzod.object({
+        method: myzod.literal('kick'),
+        params: myzod.object({ playerID: myzod.string() }),
+    }),
+    myzod.object({
+        method: myzod.literal('ban'),
+        params: myzod.object({ playerID: myzod.string() }),
+    }),
+    myzod.object({
+        method: myzod.literal('unban'),
+        params: myzod.object({ playerID: myzod.string() }),
+    }),
+    myzod.object({
+        method: myzod.literal('getBans'),
+        params: myzod.object({}),
+    }),
+    myzod.object({
+        method: myzod.literal('debugInfo'),
+        params: myzod.object({}),
+    }),
+    myzod.object({
+        method: myzod.literal('timeSync'),
+        params: myzod.object({ clientTime: myzod.number() }),
+    }),
 ]);
 
 export type ClientNote = Infer<typeof ClientNote>;
 export const ClientNote = myzod
     .object({
         version: myzod.number(),
+        id: myzod.string().optional(),
     })
     .and(PartialClientNote);
 
 // Messages sent from server to client.
 
+export type FieldError = DeepReadonly<Infer<typeof FieldError>>;
+const FieldError = myzod.object({
+    field: myzod.string(),
+    code: myzod.string(),
+    message: myzod.string(),
+});
+
 export type RoomResponse = DeepReadonly<Infer<typeof RoomResponse>>;
 export const RoomResponse = myzod.object({
     id: myzod.string().optional().nullable(),
     error: myzod.string().optional().nullable(),
+    errors: myzod.array(FieldError).optional().nullable(),
 });
 
+export type ListedRooms = DeepReadonly<Infer<typeof ListedRooms>>;
+export const ListedRooms = myzod.array(
+    myzod.object({
+        name: myzod.string(),
+        players: myzod.number(),
+        passwordRequired: myzod.boolean(),
+    })
+);
+
+export type BuiltinPacks = DeepReadonly<Infer<typeof BuiltinPacks>>;
+export const BuiltinPacks = myzod.array(
+    myzod.object({
+        name: myzod.string(),
+        language: myzod.string().optional(),
+        count: myzod.number(),
+    })
+);
+
 export type TimeResponse = DeepReadonly<Infer<typeof TimeResponse>>;
 export const TimeResponse = myzod.object({
     time: myzod.date(),
 });
 
+// The reason of every close the server sends.
+export type CloseHint = DeepReadonly<Infer<typeof CloseHint>>;
+const CloseHint = myzod.object({
+    retry: myzod.boolean(),
+    afterMs: myzod.number().optional(),
+    roomId: myzod.string().optional(),
+    reason: myzod.string().optional(),
+});
+
+// parseCloseHint returns the hint in a close's reason, or undefined if there
+// isn't one, as for closes from anything but the server.
+export function parseCloseHint(reason: string): CloseHint | undefined {
+    try {
+        return CloseHint.parse(JSON.parse(reason));
+    } catch {
+        return undefined;
+    }
+}
+
+export type StateView = DeepReadonly<Infer<typeof StateView>>;
+const StateView = myzod.object({
+    team: myzod.number(),
+    neutral: myzod.boolean(),
+    bomb: myzod.boolean(),
+});
+
 export type StateTile = DeepReadonly<Infer<typeof StateTile>>;
 const StateTile = myzod.object({
     word: myzod.string(),
     revealed: myzod.boolean(),
-    view: myzod
+    view: StateView.optional().nullable(),
+    fit: myzod
         .object({
-            team: myzod.number(),
-            neutral: myzod.boolean(),
-            bomb: myzod.boolean(),
+            class: myzod.union([myzod.literal('text'), myzod.literal('emoji')]),
+            length: myzod.number(),
+            size: myzod.number(),
         })
-        .optional()
-        .nullable(),
+        .optional(),
 });
 
 export type StateBoard = DeepReadonly<Infer<typeof StateBoard>>;
@@ -112,6 +350,10 @@ const StatePlayer = myzod.object({
     playerID: myzod.string(),
     nickname: myzod.string(),
     spymaster: myzod.boolean(),
+    color: myzod.number(),
+    host: myzod.boolean(),
+    impersonator: myzod.boolean(),
+    disconnected: myzod.boolean(),
 });
 
 export type StateTeams = DeepReadonly<Infer<typeof StateTeams>>;
@@ -123,25 +365,142 @@ const StateTimer = myzod.object({
     turnEnd: myzod.date(),
 });
 
+const StatePenalties = myzod.object({
+    limit: myzod.number(),
+    counts: myzod.array(myzod.number()),
+    forfeit: myzod.boolean(),
+});
+
+const StateHints = myzod.object({
+    budget: myzod.array(myzod.number()),
+    left: myzod.array(myzod.number()),
+    used: myzod.array(myzod.number()),
+});
+
+const StateLoggedClue = myzod.object({
+    team: myzod.number(),
+    board: myzod.number().optional(),
+    word: myzod.string(),
+    count: myzod.number(),
+});
+
+const StateTracker = myzod.object({
+    scores: myzod.array(myzod.number()),
+    clues: myzod.array(StateLoggedClue),
+});
+
+const StateSpymasters = myzod.object({
+    limit: myzod.number(),
+    confirmClues: myzod.boolean(),
+    pending: myzod
+        .object({
+            board: myzod.number().optional(),
+            word: myzod.string(),
+            count: myzod.number(),
+            confirmed: myzod.array(myzod.string()),
+        })
+        .optional()
+        .nullable(),
+});
+
 export type StateWordList = DeepReadonly<Infer<typeof StateWordList>>;
 const StateWordList = myzod.object({
     name: myzod.string(),
+    language: myzod.string().optional(),
     count: myzod.number(),
     custom: myzod.boolean(),
     enabled: myzod.boolean(),
 });
 
+export type StateBackfill = DeepReadonly<Infer<typeof StateBackfill>>;
+const StateBackfill = myzod.object({
+    pack: myzod.string(),
+    language: myzod.string().optional(),
+    count: myzod.number(),
+});
+
+export type StateClue = DeepReadonly<Infer<typeof StateClue>>;
+const StateClue = myzod.object({
+    board: myzod.number().optional(),
+    word: myzod.string(),
+    count: myzod.number(),
+});
+
+export type StateStart = DeepReadonly<Infer<typeof StateStart>>;
+const StateStart = myzod.object({
+    minSpymasters: myzod.number(),
+    minGuessers: myzod.number(),
+});
+
+export type StateNotifications = DeepReadonly<Infer<typeof StateNotifications>>;
+const StateNotifications = myzod.object({
+    off: myzod.boolean(),
+    disabled: myzod.array(myzod.string()),
+});
+
+export type StateGameOver = DeepReadonly<Infer<typeof StateGameOver>>;
+const StateGameOver = myzod.object({
+    winner: myzod.number(),
+    reason: myzod.string(),
+    seconds: myzod.number(),
+    wordsLeft: myzod.array(myzod.array(myzod.number())).optional(),
+    key: myzod.array(myzod.array(myzod.array(StateView))).optional(),
+});
+
 export type RoomState = DeepReadonly<Infer<typeof RoomState>>;
 export const RoomState = myzod.object({
     version: myzod.number(),
     teams: StateTeams,
     turn: myzod.number(),
     winner: myzod.number().optional().nullable(),
-    board: StateBoard,
-    wordsLeft: myzod.array(myzod.number()),
-    lists: myzod.array(StateWordList),
+    wins: myzod.array(myzod.number()).optional().nullable(),
+    gameOver: StateGameOver.optional(),
+    board: StateBoard.optional(),
+    wordsLeft: myzod.array(myzod.number()).optional(),
+    boards: myzod
+        .array(
+            myzod.object({
+                board: StateBoard,
+                wordsLeft: myzod.array(myzod.number()),
+            })
+        )
+        .optional(),
+    turnBoard: myzod.number().optional(),
+    boardOptions: myzod
+        .object({
+            count: myzod.number(),
+            rows: myzod.number(),
+            cols: myzod.number(),
+            bombs: myzod.number().optional(),
+        })
+        .optional(),
+    lists: myzod.array(StateWordList).optional(),
     timer: StateTimer.optional().nullable(),
     hideBomb: myzod.boolean(),
+    feedback: myzod.boolean(),
+    openPolls: myzod.boolean(),
+    mirrorDelay: myzod.number(),
+    spectators: myzod.number(),
+    clue: StateClue.optional().nullable(),
+    clueLog: myzod.array(StateLoggedClue).optional(),
+    boundClues: myzod.boolean(),
+    suggestClues: myzod.boolean(),
+    penalties: StatePenalties.optional().nullable(),
+    hints: StateHints.optional().nullable(),
+    spymasters: StateSpymasters.optional().nullable(),
+    start: StateStart.optional().nullable(),
+    enforceGuessLimit: myzod.boolean().optional(),
+    guessesLeft: myzod.number().optional(),
+    canUndo: myzod.boolean().optional(),
+    backfillWords: myzod.boolean().optional(),
+    backfill: StateBackfill.optional(),
+    webhook: myzod.boolean().optional(),
+    teamConstraints: myzod.array(TeamConstraint).optional(),
+    keyChecksum: myzod.string().optional(),
+    boardHash: myzod.string().optional(),
+    ruleset: myzod.string().optional(),
+    tracker: StateTracker.optional(),
+    notifications: StateNotifications.optional().nullable(),
 });
 
 export type State = DeepReadonly<Infer<typeof State>>;
@@ -150,10 +509,221 @@ export const State = myzod.object({
     roomState: RoomState,
 });
 
+export type ServerError = DeepReadonly<Infer<typeof ServerError>>;
+export const ServerError = myzod.object({
+    code: myzod.string(),
+    message: myzod.string(),
+    limit: myzod.number().optional().nullable(),
+    shortfalls: myzod
+        .array(
+            myzod.object({
+                team: myzod.number(),
+                spymaster: myzod.boolean(),
+                have: myzod.number(),
+                need: myzod.number(),
+            })
+        )
+        .optional(),
+    conflicts: myzod.array(TeamConstraint).optional(),
+    retryAfter: myzod.number().optional(),
+});
+
+export type Notification = DeepReadonly<Infer<typeof Notification>>;
+export const Notification = myzod.object({
+    event: myzod.string(),
+    team: myzod.number(),
+});
+
+export type Bandwidth = DeepReadonly<Infer<typeof Bandwidth>>;
+export const Bandwidth = myzod.object({
+    bytesSent: myzod.number(),
+    bytesReceived: myzod.number(),
+});
+
+export type ClueSuggestions = DeepReadonly<Infer<typeof ClueSuggestions>>;
+export const ClueSuggestions = myzod.object({
+    suggestions: myzod.array(
+        myzod.object({
+            board: myzod.number().optional(),
+            word: myzod.string(),
+            count: myzod.number(),
+        })
+    ),
+});
+
+export type ChatMessage = DeepReadonly<Infer<typeof ChatMessage>>;
+export const ChatMessage = myzod.object({
+    playerID: myzod.string(),
+    nickname: myzod.string(),
+    text: myzod.string(),
+    time: myzod.date(),
+});
+
+export type Poll = DeepReadonly<Infer<typeof Poll>>;
+export const Poll = myzod.object({
+    id: myzod.number(),
+    question: myzod.string(),
+    options: myzod.array(myzod.string()),
+    hidden: myzod.boolean(),
+    opener: myzod.string(),
+    closes: myzod.date(),
+    closed: myzod.boolean(),
+    votes: myzod.number(),
+    tallies: myzod.array(myzod.number()).optional(), // Omitted while a hidden poll is open.
+    vote: myzod.number().nullable(),
+});
+
+export type DebugInfo = DeepReadonly<Infer<typeof DebugInfo>>;
+export const DebugInfo = myzod
+    .object({
+        playerID: myzod.string(),
+    })
+    .and(Bandwidth);
+
 export type ServerNote = DeepReadonly<Infer<typeof ServerNote>>;
 export const ServerNote = myzod.union([
     myzod.object({
         method: myzod.literal('state'),
         params: State,
+        sent: myzod.date().optional(),
+    }),
+    myzod.object({
+        method: myzod.literal('error'),
+        params: ServerError,
+    }),
+    myzod.object({
+        method: myzod.literal('notification'),
+        params: Notification,
+    }),
+    myzod.object({
+        method: myzod.literal('debugInfo'),
+        params: DebugInfo,
+    }),
+    myzod.object({
+        method: myzod.literal('bandwidth'),
+        params: Bandwidth,
+    }),
+    myzod.object({
+        method: myzod.literal('clueSuggestions'),
+        params: ClueSuggestions,
+    }),
+    myzod.object({
+        method: myzod.literal('mirrorToken'),
+        params: myzod.object({ token: myzod.string() }),
+    }),
+    myzod.object({
+        method: myzod.literal('webhook'),
+        params: myzod.object({ url: myzod.string() }),
+    }),
+    myzod.object({
+        method: myzod.literal('chat'),
+        params: myzod.object({ messages: myzod.array(ChatMessage) }),
+    }),
+    myzod.object({
+        method: myzod.literal('poll'),
+        params: Poll,
+    }),
+    myzod.object({
+        method: myzod.literal('mergeRequest'),
+        params: myzod.object({ id: myzod.string(), roomName: myzod.string(), players: myzod.number() }),
+    }),
+    myzod.object({
+        method: myzod.literal('feedbackPrompt'),
+        params: myzod.object({ game: myzod.number() }),
+    }),
+    myzod.object({
+        method: myzod.literal('merged'),
+        params: myzod.object({ roomID: myzod.string(), token: myzod.string() }),
+    }),
+    myzod.object({
+        method: myzod.literal('resumeToken'),
+        params: myzod.object({ token: myzod.string() }),
+    }),
+    myzod.object({
+        method: myzod.literal('optionsChanged'),
+        params: myzod.object({ fields: myzod.array(myzod.string()) }),
+    }),
+    myzod.object({
+        method: myzod.literal('qualityDegraded'),
+        params: myzod.object({}),
+    }),
+    myzod.object({
+        method: myzod.literal('qualityRestored'),
+        params: myzod.object({}),
+    }),
+    myzod.object({
+        method: myzod.literal('bans'),
+        params: myzod.object({
+            bans: myzod.array(myzod.object({ playerID: myzod.string(), nickname: myzod.string() })),
+        }),
+    }),
+    myzod.object({
+        method: myzod.literal('auditLog'),
+        params: myzod.object({
+            entries: myzod.array(
+                myzod.object({
+                    time: myzod.date(),
+                    action: myzod.string(),
+                    actor: myzod.string().optional(),
+                    actorName: myzod.string().optional(),
+                    target: myzod.string().optional(),
+                    changes: myzod
+                        .array(
+                            myzod.object({
+                                field: myzod.string(),
+                                before: myzod.string(),
+                                after: myzod.string(),
+                            })
+                        )
+                        .optional(),
+                })
+            ),
+        }),
+    }),
+    myzod.object({
+        method: myzod.literal('playerJoined'),
+        params: myzod.object({
+            since: myzod.number(),
+            version: myzod.number(),
+            boardHash: myzod.string().optional(),
+            team: myzod.number(),
+            player: StatePlayer,
+        }),
+    }),
+    myzod.object({
+        method: myzod.literal('playerLeft'),
+        params: myzod.object({
+            since: myzod.number(),
+            version: myzod.number(),
+            boardHash: myzod.string().optional(),
+            playerID: myzod.string(),
+        }),
+    }),
+    myzod.object({
+        method: myzod.literal('playerUpdated'),
+        params: myzod.object({
+            since: myzod.number(),
+            version: myzod.number(),
+            boardHash: myzod.string().optional(),
+            team: myzod.number(),
+            player: StatePlayer,
+        }),
+    }),
+    myzod.object({
+        method: myzod.literal('ack'),
+        params: myzod.object({
+            id: myzod.string(),
+            ok: myzod.boolean(),
+            error: ServerError.optional(),
+            version: myzod.number(),
+        }),
+    }),
+    myzod.object({
+        method: myzod.literal('timeSync'),
+        params: myzod.object({
+            clientTime: myzod.number(),
+            received: myzod.date(),
+        }),
+        sent: myzod.date(),
     }),
 ]);
@@ -9,13 +9,17 @@ require (
 	github.com/markbates/pkger v0.17.1
 	github.com/posener/ctxutil v1.0.0
 	github.com/prometheus/client_golang v1.8.0
+	github.com/prometheus/client_model v0.2.0
 	github.com/speps/go-hashids v2.0.0+incompatible
 	github.com/tomwright/queryparam/v4 v4.1.0
 	github.com/zikaeroh/ctxjoin v0.0.0-20200613235025-e3d47af29310
 	github.com/zikaeroh/ctxlog v0.0.0-20200613043947-8791c8613223
 	go.uber.org/atomic v1.7.0
 	go.uber.org/zap v1.16.0
+	golang.org/x/crypto v0.0.0-20200622213623-75b288015ac9
+	golang.org/x/net v0.0.0-20200625001655-4c5254603344
 	golang.org/x/sync v0.0.0-20201020160332-67f06af15bc9
+	golang.org/x/text v0.3.4
 	gotest.tools/v3 v3.0.3
 	nhooyr.io/websocket v1.8.6
 )
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// httpOptions tune the HTTP servers. Only reading headers is bounded in time;
+// WebSocket connections last as long as a player stays, so reads and writes
+// after the headers never time out.
+type httpOptions struct {
+	ReadHeaderTimeout time.Duration
+	IdleTimeout       time.Duration // Between requests on a kept-alive connection.
+	MaxHeaderBytes    int
+
+	// H2C serves HTTP/2 without TLS, to proxies which speak it, either by
+	// prior knowledge or by upgrading. WebSockets need HTTP/1.1, so their
+	// path is never upgraded.
+	H2C bool
+}
+
+func argsHTTPOptions() httpOptions {
+	return httpOptions{
+		ReadHeaderTimeout: args.ReadHeaderTimeout,
+		IdleTimeout:       args.IdleTimeout,
+		MaxHeaderBytes:    args.MaxHeaderBytes,
+		H2C:               args.H2C,
+	}
+}
+
+func newHTTPServer(handler http.Handler, opts httpOptions) *http.Server {
+	if opts.H2C {
+		handler = withH2C(handler, &http2.Server{IdleTimeout: opts.IdleTimeout})
+	}
+
+	return &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: opts.ReadHeaderTimeout,
+		IdleTimeout:       opts.IdleTimeout,
+		MaxHeaderBytes:    opts.MaxHeaderBytes,
+	}
+}
+
+// withH2C serves HTTP/2 connections without TLS, other than for WebSockets.
+func withH2C(handler http.Handler, h2s *http2.Server) http.Handler {
+	h2 := h2c.NewHandler(handler, h2s)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == wsPath {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		h2.ServeHTTP(w, r)
+	})
+}
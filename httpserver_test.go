@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
+	"gotest.tools/v3/assert"
+	"nhooyr.io/websocket"
+)
+
+var testHTTPOptions = httpOptions{
+	ReadHeaderTimeout: 10 * time.Second,
+	IdleTimeout:       time.Minute,
+	MaxHeaderBytes:    1 << 10,
+	H2C:               true,
+}
+
+// serveTest serves the API time route and an echoing WebSocket with the
+// options, returning the server's base URL.
+func serveTest(t *testing.T, opts httpOptions) string {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/time", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto)) //nolint:errcheck
+	})
+	mux.HandleFunc(wsPath, func(w http.ResponseWriter, r *http.Request) {
+		c, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close(websocket.StatusNormalClosure, "")
+
+		typ, b, err := c.Read(r.Context())
+		if err != nil {
+			return
+		}
+		c.Write(r.Context(), typ, b) //nolint:errcheck
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+
+	srv := newHTTPServer(mux, opts)
+	go srv.Serve(ln) //nolint:errcheck
+	t.Cleanup(func() { srv.Close() })
+
+	return "http://" + ln.Addr().String()
+}
+
+func TestHTTPServerH2C(t *testing.T) {
+	base := serveTest(t, testHTTPOptions)
+
+	// HTTP/2 by prior knowledge, as a proxy which speaks h2c would.
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+			return net.Dial(network, addr)
+		},
+	}}
+
+	resp, err := client.Get(base + "/api/time")
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+	assert.Equal(t, resp.ProtoMajor, 2)
+
+	// Without the flag, it isn't spoken.
+	opts := testHTTPOptions
+	opts.H2C = false
+	_, err = client.Get(serveTest(t, opts) + "/api/time")
+	assert.Assert(t, err != nil)
+}
+
+func TestHTTPServerHeaderTooLarge(t *testing.T) {
+	base := serveTest(t, testHTTPOptions)
+
+	req, err := http.NewRequest(http.MethodGet, base+"/api/time", nil)
+	assert.NilError(t, err)
+	req.Header.Set("X-Padding", strings.Repeat("a", 8<<10))
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusRequestHeaderFieldsTooLarge)
+}
+
+func TestHTTPServerWebSocket(t *testing.T) {
+	base := serveTest(t, testHTTPOptions)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c, _, err := websocket.Dial(ctx, base+wsPath, nil)
+	assert.NilError(t, err)
+	defer c.Close(websocket.StatusNormalClosure, "")
+
+	assert.NilError(t, c.Write(ctx, websocket.MessageText, []byte("hello")))
+	_, b, err := c.Read(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), "hello")
+
+	// Asking to upgrade the WebSocket path to h2c gets plain HTTP/1.1.
+	req, err := http.NewRequest(http.MethodGet, base+wsPath, nil)
+	assert.NilError(t, err)
+	req.Header.Set("Connection", "Upgrade, HTTP2-Settings")
+	req.Header.Set("Upgrade", "h2c")
+	req.Header.Set("HTTP2-Settings", "AAMAAABkAARAAAAAAAIAAAAA")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+	assert.Assert(t, resp.StatusCode != http.StatusSwitchingProtocols)
+	assert.Equal(t, resp.ProtoMajor, 1)
+}
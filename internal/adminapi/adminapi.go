@@ -0,0 +1,74 @@
+// Package adminapi has the requests and responses of the operator API, which
+// the server serves under /admin, and a client for it. Both sides use these
+// types, so they can't drift apart.
+package adminapi
+
+import (
+	"time"
+
+	"github.com/zikaeroh/codies/internal/server"
+	"github.com/zikaeroh/codies/internal/version"
+)
+
+// Overview gathers what operators would otherwise collect from several
+// admin endpoints.
+type Overview struct {
+	Process        ProcessInfo     `json:"process"`
+	Stats          *server.Stats   `json:"stats"`
+	Conns          ConnsPressure   `json:"conns"`
+	StaleVersions  []StaleVersion  `json:"staleVersions"`
+	ClientVersions []ClientVersion `json:"clientVersions"`
+	Errors         []*LogEntry     `json:"errors"`
+}
+
+// ProcessInfo describes the running server process.
+type ProcessInfo struct {
+	Version       string    `json:"version"`
+	StartTime     time.Time `json:"startTime"`
+	UptimeSeconds int64     `json:"uptimeSeconds"`
+	Goroutines    int       `json:"goroutines"`
+	HeapAlloc     uint64    `json:"heapAlloc"`
+	HeapObjects   uint64    `json:"heapObjects"`
+}
+
+// ConnsPressure summarizes how close clients are to the per-IP connection
+// limit.
+type ConnsPressure struct {
+	MaxPerIP int              `json:"maxPerIP"`
+	IPs      int              `json:"ips"`
+	AtLimit  int              `json:"atLimit"`
+	Top      []server.IPConns `json:"top"`
+}
+
+// StaleVersion is a version claimed by clients which were rejected.
+type StaleVersion struct {
+	Version   string            `json:"version"`
+	Staleness version.Staleness `json:"staleness"`
+	Count     int64             `json:"count"`
+	LastSeen  time.Time         `json:"lastSeen"`
+}
+
+// ClientVersion is a version claimed by clients in the last hour, and how
+// many requests claimed it.
+type ClientVersion struct {
+	Version   string            `json:"version"`
+	Staleness version.Staleness `json:"staleness"`
+	Count     int64             `json:"count"`
+	LastSeen  time.Time         `json:"lastSeen"`
+}
+
+// LogEntry is a warning or error kept by the server's log ring.
+type LogEntry struct {
+	Time    time.Time              `json:"time"`
+	Level   string                 `json:"level"`
+	Message string                 `json:"message"`
+	Caller  string                 `json:"caller,omitempty"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
+
+// LogLevelRequest overrides a room's log level. An empty level removes the
+// override; an empty duration uses server.LogLevelDuration.
+type LogLevelRequest struct {
+	Level    string `json:"level"`
+	Duration string `json:"duration"`
+}
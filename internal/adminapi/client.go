@@ -0,0 +1,140 @@
+package adminapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"github.com/zikaeroh/codies/internal/server"
+)
+
+// ErrNotFound is returned for rooms which don't exist.
+var ErrNotFound = errors.New("not found")
+
+// StatusError is returned for any other unsuccessful response.
+type StatusError struct {
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	switch e.Code {
+	case http.StatusUnauthorized:
+		return "unauthorized; check the admin token"
+	default:
+		return fmt.Sprintf("unexpected status %d %s", e.Code, http.StatusText(e.Code))
+	}
+}
+
+// Client calls the admin API of a server.
+type Client struct {
+	base  string
+	token string
+	http  *http.Client
+}
+
+// NewClient creates a client for the server at addr, which is a URL like
+// "http://localhost:5000"; the admin API is under its /admin path. If hc is
+// nil, http.DefaultClient is used.
+func NewClient(addr, token string, hc *http.Client) *Client {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	return &Client{
+		base:  strings.TrimSuffix(addr, "/") + "/admin",
+		token: token,
+		http:  hc,
+	}
+}
+
+// Overview fetches the server's overview.
+func (c *Client) Overview(ctx context.Context) (*Overview, error) {
+	o := &Overview{}
+	return o, c.do(ctx, http.MethodGet, "/overview", nil, o)
+}
+
+// Stats fetches a stats snapshot, which describes every room.
+func (c *Client) Stats(ctx context.Context) (*server.Stats, error) {
+	stats := &server.Stats{}
+	return stats, c.do(ctx, http.MethodGet, "/stats", nil, stats)
+}
+
+// Room fetches the stats of one room.
+func (c *Client) Room(ctx context.Context, roomID string) (*server.RoomStats, error) {
+	stats := &server.RoomStats{}
+	return stats, c.do(ctx, http.MethodGet, roomPath(roomID), nil, stats)
+}
+
+// Conns fetches a room's connections.
+func (c *Client) Conns(ctx context.Context, roomID string) ([]server.ConnStats, error) {
+	var conns []server.ConnStats
+	return conns, c.do(ctx, http.MethodGet, roomPath(roomID)+"/conns", nil, &conns)
+}
+
+// Audit fetches a room's audit log.
+func (c *Client) Audit(ctx context.Context, roomID string) ([]*protocol.AuditEntry, error) {
+	var entries []*protocol.AuditEntry
+	return entries, c.do(ctx, http.MethodGet, roomPath(roomID)+"/audit", nil, &entries)
+}
+
+// SetLogLevel overrides a room's log level.
+func (c *Client) SetLogLevel(ctx context.Context, roomID string, req *LogLevelRequest) error {
+	return c.do(ctx, http.MethodPost, roomPath(roomID)+"/loglevel", req, nil)
+}
+
+// DeleteRoom closes a room, disconnecting everyone in it.
+func (c *Client) DeleteRoom(ctx context.Context, roomID string) error {
+	return c.do(ctx, http.MethodDelete, roomPath(roomID), nil, nil)
+}
+
+func roomPath(roomID string) string {
+	return "/rooms/" + url.PathEscape(roomID)
+}
+
+// do makes a request, with req encoded as its body if not nil, and decodes
+// the response into resp if not nil.
+func (c *Client) do(ctx context.Context, method, path string, req, resp interface{}) error {
+	var body io.Reader
+	if req != nil {
+		b, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	r, err := http.NewRequestWithContext(ctx, method, c.base+path, body)
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Authorization", "Bearer "+c.token)
+	if req != nil {
+		r.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.http.Do(r)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case res.StatusCode != http.StatusOK:
+		return &StatusError{Code: res.StatusCode}
+	case resp == nil:
+		_, err := io.Copy(ioutil.Discard, res.Body)
+		return err
+	}
+
+	return json.NewDecoder(res.Body).Decode(resp)
+}
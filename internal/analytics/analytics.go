@@ -0,0 +1,311 @@
+// Package analytics records anonymized usage events to a local file, for
+// self-hosters who want to know how their server is used without sending
+// data anywhere.
+//
+// Events never contain room names, player IDs, nicknames, clues, or words.
+// Room IDs are hashed with a salt which is generated once per install and
+// kept next to the events file, so events about the same room can be tied
+// together, but not to the room itself, nor to the same room on another
+// install.
+package analytics
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/idgen"
+)
+
+// SchemaVersion is the version of the Event schema. It's written with every
+// event, and increases with any change which isn't only an added field.
+const SchemaVersion = 1
+
+// EventType is the kind of an event.
+type EventType string
+
+const (
+	RoomCreated  = EventType("roomCreated")
+	RoomClosed   = EventType("roomClosed")
+	GameStarted  = EventType("gameStarted")
+	GameFinished = EventType("gameFinished")
+	PeakPlayers  = EventType("peakPlayers")
+	GameRated    = EventType("gameRated")
+)
+
+// Event is one line of the events file. Fields are only set for the types
+// they apply to.
+type Event struct {
+	Schema int       `json:"v"`
+	Type   EventType `json:"type"`
+	Time   time.Time `json:"time"`
+
+	// Room is the salted hash of the room's ID.
+	Room string `json:"room,omitempty"`
+
+	// Mode and Options describe a game (or, for roomCreated, the room's
+	// first game). Options is a fingerprint of the room's settings; games
+	// with the same settings have the same fingerprint.
+	Mode    string `json:"mode,omitempty"`
+	Options string `json:"options,omitempty"`
+
+	// Ruleset is the game's ruleset hash, for gameStarted, gameFinished, and
+	// gameRated.
+	Ruleset string `json:"ruleset,omitempty"`
+
+	// Seconds is the length of a finished game, or the lifetime of a closed
+	// room.
+	Seconds int64 `json:"seconds,omitempty"`
+
+	Forfeit bool   `json:"forfeit,omitempty"` // gameFinished
+	Reason  string `json:"reason,omitempty"`  // roomClosed
+
+	// Rating and Tag are a player's answer to a feedback prompt. Ratings
+	// carry no room, so they can't be tied to a room's other events, let
+	// alone to a player.
+	Rating int    `json:"rating,omitempty"`
+	Tag    string `json:"tag,omitempty"`
+
+	// Day (as YYYY-MM-DD, in UTC) and Players are the most players connected
+	// at once that day. A server which restarts during the day writes more
+	// than one peak for it.
+	Day     string `json:"day,omitempty"`
+	Players int    `json:"players,omitempty"`
+}
+
+// Game describes a game for analytics.
+type Game struct {
+	Mode    string
+	Options string
+	Ruleset string
+}
+
+// Log appends events to a file. A nil Log records nothing, so callers needn't
+// check whether analytics are enabled.
+type Log struct {
+	salt []byte
+	now  func() time.Time
+
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+	err    error // The first write error.
+	day    string
+	peak   int
+}
+
+// Open opens the events file at path for appending, creating it if needed.
+// The install's salt is read from path + ".salt", and created there the first
+// time.
+func Open(path string) (*Log, error) {
+	salt, err := LoadSalt(path + ".salt")
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	l := newLog(f, salt)
+	l.closer = f
+	return l, nil
+}
+
+func newLog(w io.Writer, salt []byte) *Log {
+	return &Log{
+		salt: salt,
+		now:  time.Now,
+		w:    w,
+	}
+}
+
+// LoadSalt reads an install's salt from path, creating it there the first
+// time. Other files which hash identifiers keep their salts the same way.
+func LoadSalt(path string) ([]byte, error) {
+	salt, err := ioutil.ReadFile(path)
+	switch {
+	case err == nil:
+		salt = []byte(strings.TrimSpace(string(salt)))
+		if len(salt) == 0 {
+			return nil, errors.New("salt file is empty: " + path)
+		}
+		return salt, nil
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	salt = []byte(idgen.Token())
+	if err := ioutil.WriteFile(path, append(salt, '\n'), 0o600); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// Close writes the current day's peak and closes the file, returning the
+// first error encountered while writing, if any.
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.flushPeak()
+
+	if l.closer != nil {
+		if err := l.closer.Close(); err != nil && l.err == nil {
+			l.err = err
+		}
+	}
+	return l.err
+}
+
+// hashRoom hashes a room ID with the install's salt.
+func (l *Log) hashRoom(id string) string {
+	mac := hmac.New(sha256.New, l.salt)
+	mac.Write([]byte(id)) //nolint:errcheck
+	return hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// Must be called with l.mu locked.
+func (l *Log) write(e *Event) {
+	if l.err != nil {
+		return
+	}
+
+	e.Schema = SchemaVersion
+	b, err := json.Marshal(e)
+	if err != nil {
+		l.err = err
+		return
+	}
+
+	if _, err := l.w.Write(append(b, '\n')); err != nil {
+		l.err = err
+	}
+}
+
+func (l *Log) record(e *Event) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e.Time = l.now().UTC()
+	l.rollDay(e.Time)
+	l.write(e)
+}
+
+// RoomCreated records a new room.
+func (l *Log) RoomCreated(id string, g Game) {
+	if l == nil {
+		return
+	}
+	l.record(&Event{Type: RoomCreated, Room: l.hashRoom(id), Mode: g.Mode, Options: g.Options})
+}
+
+// RoomClosed records a room being closed, and why.
+func (l *Log) RoomClosed(id string, reason string, lifetime time.Duration) {
+	if l == nil {
+		return
+	}
+	l.record(&Event{Type: RoomClosed, Room: l.hashRoom(id), Reason: reason, Seconds: seconds(lifetime)})
+}
+
+// GameStarted records a new game.
+func (l *Log) GameStarted(id string, g Game) {
+	if l == nil {
+		return
+	}
+	l.record(&Event{Type: GameStarted, Room: l.hashRoom(id), Mode: g.Mode, Options: g.Options, Ruleset: g.Ruleset})
+}
+
+// GameFinished records a game being won. Games abandoned for a new game
+// aren't recorded as finished.
+func (l *Log) GameFinished(id string, g Game, length time.Duration, forfeit bool) {
+	if l == nil {
+		return
+	}
+	l.record(&Event{
+		Type:    GameFinished,
+		Room:    l.hashRoom(id),
+		Mode:    g.Mode,
+		Options: g.Options,
+		Ruleset: g.Ruleset,
+		Seconds: seconds(length),
+		Forfeit: forfeit,
+	})
+}
+
+// GameRated records a rating given to a finished game, with its optional tag.
+// Only the game's mode and ruleset are kept with it.
+func (l *Log) GameRated(g Game, rating int, tag string) {
+	if l == nil {
+		return
+	}
+	l.record(&Event{Type: GameRated, Mode: g.Mode, Ruleset: g.Ruleset, Rating: rating, Tag: tag})
+}
+
+// Players notes the number of players connected now, for the day's peak.
+func (l *Log) Players(n int) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rollDay(l.now().UTC())
+	if n > l.peak {
+		l.peak = n
+	}
+}
+
+// rollDay writes the previous day's peak once the day changes.
+//
+// Must be called with l.mu locked.
+func (l *Log) rollDay(now time.Time) {
+	day := now.Format("2006-01-02")
+	if day == l.day {
+		return
+	}
+
+	l.flushPeak()
+	l.day = day
+	l.peak = 0
+}
+
+// Must be called with l.mu locked.
+func (l *Log) flushPeak() {
+	if l.day == "" || l.peak == 0 {
+		return
+	}
+
+	l.write(&Event{Type: PeakPlayers, Time: l.now().UTC(), Day: l.day, Players: l.peak})
+	l.peak = 0
+}
+
+// Fingerprint returns a short fingerprint of a room's settings, given as
+// name=value pairs in a fixed order. It isn't salted, so the same settings
+// have the same fingerprint on every install.
+func Fingerprint(settings ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(settings, "\n")))
+	return hex.EncodeToString(sum[:])[:8]
+}
+
+func seconds(d time.Duration) int64 {
+	return int64(d / time.Second)
+}
@@ -0,0 +1,128 @@
+package analytics
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func readEvents(t *testing.T, b []byte) []*Event {
+	t.Helper()
+
+	var events []*Event
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		var e Event
+		assert.NilError(t, json.Unmarshal(scanner.Bytes(), &e))
+		events = append(events, &e)
+	}
+	assert.NilError(t, scanner.Err())
+	return events
+}
+
+func TestLogAnonymized(t *testing.T) {
+	var buf bytes.Buffer
+	l := newLog(&buf, []byte("salt"))
+
+	g := Game{Mode: "classic", Options: Fingerprint("timed=false")}
+	l.RoomCreated("abcdefghijklmnop", g)
+	l.GameStarted("abcdefghijklmnop", g)
+	l.GameFinished("abcdefghijklmnop", g, 90*time.Second, true)
+	l.RoomClosed("abcdefghijklmnop", "expired", time.Hour)
+	assert.NilError(t, l.Close())
+
+	assert.Assert(t, !strings.Contains(buf.String(), "abcdefghijklmnop"))
+
+	events := readEvents(t, buf.Bytes())
+	assert.Equal(t, len(events), 4)
+
+	room := events[0].Room
+	assert.Equal(t, len(room), 16)
+	for _, e := range events {
+		assert.Equal(t, e.Schema, SchemaVersion)
+		assert.Equal(t, e.Room, room)
+	}
+
+	assert.Equal(t, events[2].Type, GameFinished)
+	assert.Equal(t, events[2].Seconds, int64(90))
+	assert.Assert(t, events[2].Forfeit)
+	assert.Equal(t, events[3].Reason, "expired")
+	assert.Equal(t, events[3].Seconds, int64(3600))
+
+	// Ratings aren't tied to a room at all.
+	buf.Reset()
+	l = newLog(&buf, []byte("salt"))
+	l.GameRated(g, 5, "fun")
+	assert.NilError(t, l.Close())
+
+	events = readEvents(t, buf.Bytes())
+	assert.Equal(t, len(events), 1)
+	assert.Equal(t, events[0].Room, "")
+	assert.Equal(t, events[0].Rating, 5)
+	assert.Equal(t, events[0].Tag, "fun")
+
+	// Another install can't tie its rooms to these.
+	other := newLog(&buf, []byte("other"))
+	assert.Assert(t, other.hashRoom("abcdefghijklmnop") != room)
+}
+
+func TestLogPeakPlayers(t *testing.T) {
+	var buf bytes.Buffer
+	l := newLog(&buf, []byte("salt"))
+
+	now := time.Date(2020, 5, 1, 23, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	l.Players(3)
+	l.Players(7)
+	l.Players(2)
+
+	now = now.Add(2 * time.Hour)
+	l.Players(1)
+	assert.NilError(t, l.Close())
+
+	events := readEvents(t, buf.Bytes())
+	assert.Equal(t, len(events), 2)
+	assert.Equal(t, events[0].Type, PeakPlayers)
+	assert.Equal(t, events[0].Day, "2020-05-01")
+	assert.Equal(t, events[0].Players, 7)
+	assert.Equal(t, events[1].Day, "2020-05-02")
+	assert.Equal(t, events[1].Players, 1)
+}
+
+func TestLogNil(t *testing.T) {
+	var l *Log
+	l.RoomCreated("room", Game{})
+	l.GameStarted("room", Game{})
+	l.GameFinished("room", Game{}, time.Minute, false)
+	l.RoomClosed("room", "expired", time.Minute)
+	l.Players(1)
+	assert.NilError(t, l.Close())
+}
+
+func TestOpenKeepsSalt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	l, err := Open(path)
+	assert.NilError(t, err)
+	first := l.hashRoom("room")
+	l.RoomCreated("room", Game{Mode: "classic"})
+	assert.NilError(t, l.Close())
+
+	l, err = Open(path)
+	assert.NilError(t, err)
+	assert.Equal(t, l.hashRoom("room"), first)
+	l.RoomCreated("room", Game{Mode: "classic"})
+	assert.NilError(t, l.Close())
+
+	b, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Equal(t, len(readEvents(t, b)), 2)
+}
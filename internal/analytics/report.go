@@ -0,0 +1,266 @@
+package analytics
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+)
+
+// Report summarizes an events file.
+type Report struct {
+	Days []*DayReport `json:"days"`
+
+	// Modes counts the games started in each mode.
+	Modes map[string]int `json:"modes"`
+
+	// Options counts the games started with each fingerprint of settings,
+	// most popular first.
+	Options []*OptionsCount `json:"options"`
+
+	GamesStarted       int     `json:"gamesStarted"`
+	GamesFinished      int     `json:"gamesFinished"`
+	Forfeits           int     `json:"forfeits"`
+	AverageGameSeconds float64 `json:"averageGameSeconds"`
+
+	// Ratings counts the ratings given to games with each ruleset, most
+	// rated first.
+	Ratings []*RatingCount `json:"ratings"`
+
+	RoomsCreated       int            `json:"roomsCreated"`
+	RoomsClosed        map[string]int `json:"roomsClosed"` // By reason.
+	AverageRoomSeconds float64        `json:"averageRoomSeconds"`
+
+	// Skipped counts lines which couldn't be read, or were written with a
+	// newer schema.
+	Skipped int `json:"skipped"`
+}
+
+// DayReport summarizes a single day, in UTC.
+type DayReport struct {
+	Day           string `json:"day"`
+	RoomsCreated  int    `json:"roomsCreated"`
+	GamesStarted  int    `json:"gamesStarted"`
+	GamesFinished int    `json:"gamesFinished"`
+	PeakPlayers   int    `json:"peakPlayers"`
+}
+
+type OptionsCount struct {
+	Options string `json:"options"`
+	Mode    string `json:"mode"`
+	Games   int    `json:"games"`
+}
+
+// maxRating is the highest rating a game may be given; the lowest is one.
+const maxRating = 5
+
+type RatingCount struct {
+	Ruleset string         `json:"ruleset"`
+	Mode    string         `json:"mode"`
+	Ratings []int          `json:"ratings"` // Ratings[i] counts ratings of i+1.
+	Tags    map[string]int `json:"tags"`
+	Total   int            `json:"total"`
+}
+
+// Average is the mean rating.
+func (c *RatingCount) Average() float64 {
+	if c.Total == 0 {
+		return 0
+	}
+
+	sum := 0
+	for i, n := range c.Ratings {
+		sum += (i + 1) * n
+	}
+	return float64(sum) / float64(c.Total)
+}
+
+// Summarize reads events, one per line, and summarizes them.
+func Summarize(r io.Reader) (*Report, error) {
+	report := &Report{
+		Days:        []*DayReport{},
+		Modes:       make(map[string]int),
+		Options:     []*OptionsCount{},
+		Ratings:     []*RatingCount{},
+		RoomsClosed: make(map[string]int),
+	}
+
+	days := make(map[string]*DayReport)
+	day := func(name string) *DayReport {
+		d := days[name]
+		if d == nil {
+			d = &DayReport{Day: name}
+			days[name] = d
+			report.Days = append(report.Days, d)
+		}
+		return d
+	}
+
+	options := make(map[string]*OptionsCount)
+	ratings := make(map[string]*RatingCount)
+	var gameSeconds, roomSeconds int64
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var e Event
+		if err := json.Unmarshal(line, &e); err != nil || e.Schema > SchemaVersion {
+			report.Skipped++
+			continue
+		}
+
+		switch e.Type {
+		case RoomCreated:
+			report.RoomsCreated++
+			day(e.Time.Format("2006-01-02")).RoomsCreated++
+
+		case RoomClosed:
+			report.RoomsClosed[e.Reason]++
+			roomSeconds += e.Seconds
+
+		case GameStarted:
+			report.GamesStarted++
+			report.Modes[e.Mode]++
+			day(e.Time.Format("2006-01-02")).GamesStarted++
+
+			key := e.Mode + "/" + e.Options
+			if options[key] == nil {
+				options[key] = &OptionsCount{Options: e.Options, Mode: e.Mode}
+				report.Options = append(report.Options, options[key])
+			}
+			options[key].Games++
+
+		case GameFinished:
+			report.GamesFinished++
+			if e.Forfeit {
+				report.Forfeits++
+			}
+			gameSeconds += e.Seconds
+			day(e.Time.Format("2006-01-02")).GamesFinished++
+
+		case PeakPlayers:
+			if d := day(e.Day); e.Players > d.PeakPlayers {
+				d.PeakPlayers = e.Players
+			}
+
+		case GameRated:
+			if e.Rating < 1 || e.Rating > maxRating {
+				report.Skipped++
+				continue
+			}
+
+			c := ratings[e.Ruleset]
+			if c == nil {
+				c = &RatingCount{
+					Ruleset: e.Ruleset,
+					Mode:    e.Mode,
+					Ratings: make([]int, maxRating),
+					Tags:    make(map[string]int),
+				}
+				ratings[e.Ruleset] = c
+				report.Ratings = append(report.Ratings, c)
+			}
+			c.Ratings[e.Rating-1]++
+			c.Total++
+			if e.Tag != "" {
+				c.Tags[e.Tag]++
+			}
+
+		default:
+			report.Skipped++
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if report.GamesFinished > 0 {
+		report.AverageGameSeconds = float64(gameSeconds) / float64(report.GamesFinished)
+	}
+
+	closed := 0
+	for _, n := range report.RoomsClosed {
+		closed += n
+	}
+	if closed > 0 {
+		report.AverageRoomSeconds = float64(roomSeconds) / float64(closed)
+	}
+
+	sort.Slice(report.Days, func(i, j int) bool {
+		return report.Days[i].Day < report.Days[j].Day
+	})
+
+	sort.SliceStable(report.Options, func(i, j int) bool {
+		return report.Options[i].Games > report.Options[j].Games
+	})
+
+	sort.SliceStable(report.Ratings, func(i, j int) bool {
+		return report.Ratings[i].Total > report.Ratings[j].Total
+	})
+
+	return report, nil
+}
+
+// WriteText writes the report for people to read.
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Rooms created:\t%d\n", r.RoomsCreated)
+	fmt.Fprintf(tw, "Average room lifetime:\t%s\n", formatSeconds(r.AverageRoomSeconds))
+	fmt.Fprintf(tw, "Games started:\t%d\n", r.GamesStarted)
+	fmt.Fprintf(tw, "Games finished:\t%d (%d by forfeit)\n", r.GamesFinished, r.Forfeits)
+	fmt.Fprintf(tw, "Average game length:\t%s\n", formatSeconds(r.AverageGameSeconds))
+	if r.Skipped > 0 {
+		fmt.Fprintf(tw, "Skipped lines:\t%d\n", r.Skipped)
+	}
+
+	fmt.Fprintf(tw, "\nDay\tRooms\tGames\tFinished\tPeak players\n")
+	for _, d := range r.Days {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\n", d.Day, d.RoomsCreated, d.GamesStarted, d.GamesFinished, d.PeakPlayers)
+	}
+
+	modes := make([]string, 0, len(r.Modes))
+	for mode := range r.Modes {
+		modes = append(modes, mode)
+	}
+	sort.Strings(modes)
+
+	fmt.Fprintf(tw, "\nMode\tGames\n")
+	for _, mode := range modes {
+		fmt.Fprintf(tw, "%s\t%d\n", mode, r.Modes[mode])
+	}
+
+	fmt.Fprintf(tw, "\nSettings\tMode\tGames\n")
+	for _, o := range r.Options {
+		fmt.Fprintf(tw, "%s\t%s\t%d\n", o.Options, o.Mode, o.Games)
+	}
+
+	if len(r.Ratings) > 0 {
+		fmt.Fprintf(tw, "\nRuleset\tMode\tRatings\tAverage\n")
+		for _, c := range r.Ratings {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%.1f\n", shortRuleset(c.Ruleset), c.Mode, c.Total, c.Average())
+		}
+	}
+
+	return tw.Flush()
+}
+
+func formatSeconds(s float64) string {
+	return fmt.Sprintf("%dm%02ds", int(s)/60, int(s)%60)
+}
+
+// shortRuleset shortens a ruleset hash for reading; the full hash is in the
+// JSON report.
+func shortRuleset(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
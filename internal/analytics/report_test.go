@@ -0,0 +1,85 @@
+package analytics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSummarize(t *testing.T) {
+	var buf bytes.Buffer
+	l := newLog(&buf, []byte("salt"))
+
+	now := time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	classic := Game{Mode: "classic", Options: Fingerprint("timed=false"), Ruleset: "classichash"}
+	timed := Game{Mode: "classic", Options: Fingerprint("timed=true")}
+	tracker := Game{Mode: "tracker", Options: Fingerprint("timed=false"), Ruleset: "trackerhash"}
+
+	l.RoomCreated("a", classic)
+	l.GameStarted("a", classic)
+	l.Players(4)
+	l.GameFinished("a", classic, 10*time.Minute, false)
+	l.GameStarted("a", timed)
+	l.GameFinished("a", timed, 20*time.Minute, true)
+	l.RoomClosed("a", "expired", time.Hour)
+
+	now = now.Add(24 * time.Hour)
+	l.RoomCreated("b", tracker)
+	l.GameStarted("b", tracker)
+	l.GameStarted("b", tracker)
+	l.Players(2)
+	l.GameRated(classic, 4, "fun")
+	l.GameRated(classic, 2, "")
+	l.GameRated(tracker, 5, "")
+	assert.NilError(t, l.Close())
+
+	buf.WriteString("not json\n")
+	buf.WriteString(`{"v":99,"type":"gameStarted"}` + "\n")
+	buf.WriteString(`{"v":1,"type":"gameRated","rating":6}` + "\n")
+
+	report, err := Summarize(&buf)
+	assert.NilError(t, err)
+
+	assert.Equal(t, report.RoomsCreated, 2)
+	assert.DeepEqual(t, report.RoomsClosed, map[string]int{"expired": 1})
+	assert.Equal(t, report.AverageRoomSeconds, float64(3600))
+	assert.Equal(t, report.GamesStarted, 4)
+	assert.Equal(t, report.GamesFinished, 2)
+	assert.Equal(t, report.Forfeits, 1)
+	assert.Equal(t, report.AverageGameSeconds, float64(15*60))
+	assert.DeepEqual(t, report.Modes, map[string]int{"classic": 2, "tracker": 2})
+	assert.Equal(t, report.Skipped, 3)
+
+	assert.DeepEqual(t, report.Days, []*DayReport{
+		{Day: "2020-05-01", RoomsCreated: 1, GamesStarted: 2, GamesFinished: 2, PeakPlayers: 4},
+		{Day: "2020-05-02", RoomsCreated: 1, GamesStarted: 2, PeakPlayers: 2},
+	})
+
+	assert.Equal(t, len(report.Options), 3)
+	assert.DeepEqual(t, report.Options[0], &OptionsCount{Options: tracker.Options, Mode: "tracker", Games: 2})
+
+	assert.DeepEqual(t, report.Ratings, []*RatingCount{
+		{Ruleset: "classichash", Mode: "classic", Ratings: []int{0, 1, 0, 1, 0}, Tags: map[string]int{"fun": 1}, Total: 2},
+		{Ruleset: "trackerhash", Mode: "tracker", Ratings: []int{0, 0, 0, 0, 1}, Tags: map[string]int{}, Total: 1},
+	})
+	assert.Equal(t, report.Ratings[0].Average(), float64(3))
+
+	var text bytes.Buffer
+	assert.NilError(t, report.WriteText(&text))
+	assert.Assert(t, strings.Contains(text.String(), "15m00s"), text.String())
+	assert.Assert(t, strings.Contains(text.String(), "2020-05-02"))
+	assert.Assert(t, strings.Contains(text.String(), "classichash"))
+}
+
+func TestSummarizeEmpty(t *testing.T) {
+	report, err := Summarize(strings.NewReader(""))
+	assert.NilError(t, err)
+	assert.Equal(t, report.GamesStarted, 0)
+	assert.Equal(t, report.AverageGameSeconds, float64(0))
+	assert.Equal(t, len(report.Days), 0)
+}
@@ -0,0 +1,206 @@
+// Package fit measures words for display on a card, so that every client
+// shrinks the same words by the same amount.
+package fit
+
+import (
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
+)
+
+// Class is the kind of word being measured, which decides how it's measured.
+type Class string
+
+const (
+	// Text is measured in columns: one for each user-perceived character,
+	// and two for those which are wide, like CJK ideographs.
+	Text = Class("text")
+
+	// Emoji are measured in grapheme clusters, as each renders as a single
+	// glyph however many runes it's made of.
+	Emoji = Class("emoji")
+)
+
+// Thresholds are, for each class, the lengths past which a word is shown a
+// size smaller. They're in increasing order.
+type Thresholds struct {
+	Text  []int
+	Emoji []int
+}
+
+// DefaultThresholds suit the default card size.
+var DefaultThresholds = Thresholds{
+	Text:  []int{8, 11, 14},
+	Emoji: []int{3, 5},
+}
+
+func (t Thresholds) of(c Class) []int {
+	if c == Emoji {
+		return t.Emoji
+	}
+	return t.Text
+}
+
+// Fit is a word's display metadata.
+type Fit struct {
+	Class  Class
+	Length int
+
+	// Size is the number of sizes smaller than full the word should be shown
+	// at. Zero is full size.
+	Size int
+}
+
+// Measure measures a word, and buckets it by the thresholds. Words made only
+// of emoji, and spaces between them, are measured as Emoji; anything else,
+// even with emoji in it, as Text.
+func Measure(word string, t Thresholds) Fit {
+	clusters := Graphemes(word)
+
+	class := Text
+	for _, g := range clusters {
+		if isSpace(g) {
+			continue
+		}
+		if !isEmoji(g) {
+			class = Text
+			break
+		}
+		class = Emoji
+	}
+
+	length := 0
+	for _, g := range clusters {
+		switch {
+		case class == Emoji:
+			if !isSpace(g) {
+				length++
+			}
+		case isWide([]rune(g)[0]):
+			length += 2
+		default:
+			length++
+		}
+	}
+
+	size := 0
+	for _, max := range t.of(class) {
+		if length > max {
+			size++
+		}
+	}
+
+	return Fit{Class: class, Length: length, Size: size}
+}
+
+// Graphemes splits a word into grapheme clusters, after composing it to NFC.
+//
+// x/text has no segmenter, so this implements the extended grapheme cluster
+// rules which matter for words: combining marks and other extenders, emoji
+// ZWJ sequences, flags, and Hangul jamo. Its clusters match UAX #29 for the
+// words packs have; it doesn't handle control characters or Indic conjuncts.
+func Graphemes(word string) []string {
+	runes := []rune(norm.NFC.String(word))
+
+	var clusters []string
+	start := 0
+	ri := 0 // Regional indicators in the current cluster.
+	for i, r := range runes {
+		if i > 0 && breaksBetween(runes[i-1], r, ri) {
+			clusters = append(clusters, string(runes[start:i]))
+			start = i
+			ri = 0
+		}
+		if isRegional(r) {
+			ri++
+		}
+	}
+
+	if start < len(runes) {
+		clusters = append(clusters, string(runes[start:]))
+	}
+	return clusters
+}
+
+// breaksBetween reports whether there's a cluster boundary between prev and
+// r, where ri regional indicators are in the cluster so far.
+func breaksBetween(prev, r rune, ri int) bool {
+	switch {
+	case isExtend(r) || r == zwj:
+		return false // GB9, GB9a
+	case prev == zwj && isPictographic(r):
+		return false // GB11
+	case isRegional(prev) && isRegional(r):
+		return ri%2 == 0 // GB12, GB13
+	case isJamo(prev) && isJamo(r):
+		return false // GB6 to GB8, loosely
+	}
+	return true
+}
+
+const (
+	zwj  = '\u200d'
+	vs15 = '\ufe0e' // Text presentation.
+	vs16 = '\ufe0f' // Emoji presentation.
+)
+
+// isExtend reports runes which extend the cluster before them: marks, which
+// include combining characters, variation selectors, and the keycap, as well
+// as emoji skin tone modifiers and tags.
+func isExtend(r rune) bool {
+	return unicode.Is(unicode.M, r) ||
+		(r >= 0x1F3FB && r <= 0x1F3FF) ||
+		(r >= 0xE0020 && r <= 0xE007F)
+}
+
+func isRegional(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// isPictographic approximates Extended_Pictographic, which the unicode
+// package doesn't have, by the blocks which hold emoji.
+func isPictographic(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF:
+	case r >= 0x2600 && r <= 0x27BF:
+	case r >= 0x2300 && r <= 0x23FF:
+	case r >= 0x2B00 && r <= 0x2BFF:
+	case r == 0x00A9 || r == 0x00AE || r == 0x203C || r == 0x2049 || r == 0x2122:
+	default:
+		return false
+	}
+	return true
+}
+
+func isJamo(r rune) bool {
+	return (r >= 0x1100 && r <= 0x11FF) || (r >= 0xA960 && r <= 0xA97F) || (r >= 0xD7B0 && r <= 0xD7FF)
+}
+
+// isEmoji reports whether a cluster renders as an emoji: a pictograph or flag
+// which isn't given text presentation, or anything given emoji presentation,
+// like a keycap.
+func isEmoji(g string) bool {
+	runes := []rune(g)
+	for _, r := range runes[1:] {
+		switch r {
+		case vs15:
+			return false
+		case vs16:
+			return true
+		}
+	}
+	return isRegional(runes[0]) || isPictographic(runes[0])
+}
+
+func isSpace(g string) bool {
+	return unicode.IsSpace([]rune(g)[0])
+}
+
+func isWide(r rune) bool {
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return true
+	}
+	return false
+}
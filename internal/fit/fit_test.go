@@ -0,0 +1,90 @@
+package fit
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestGraphemes(t *testing.T) {
+	tests := []struct {
+		name string
+		word string
+		want []string
+	}{
+		{"ascii", "CAT", []string{"C", "A", "T"}},
+		{"precomposed", "CAFÉ", []string{"C", "A", "F", "É"}},
+		{"combining", "CAFÉ", []string{"C", "A", "F", "É"}},
+		{"uncomposable", "X̣́", []string{"X̣́"}},
+		{"stacked", "À́̂̃B", []string{"À́̂̃", "B"}},
+		{"cjk", "東京", []string{"東", "京"}},
+		{"jamo", "한글", []string{"한", "글"}},
+		{"old jamo", "ᄀꥠᅡ", []string{"ᄀꥠᅡ"}},
+		{"devanagari", "नमस्ते", []string{"न", "म", "स्", "ते"}},
+		{"zwj family", "👨‍👩‍👧‍👦", []string{"👨‍👩‍👧‍👦"}},
+		{"skin tone", "👍🏽👍", []string{"👍🏽", "👍"}},
+		{"zwj and skin tone", "👩🏾‍🚀", []string{"👩🏾‍🚀"}},
+		{"flags", "🇩🇪🇫🇷🇪", []string{"🇩🇪", "🇫🇷", "🇪"}},
+		{"subdivision flag", "🏴\U000e0067\U000e0062\U000e0065\U000e006e\U000e0067\U000e007f", []string{"🏴\U000e0067\U000e0062\U000e0065\U000e006e\U000e0067\U000e007f"}},
+		{"keycap", "1️⃣", []string{"1️⃣"}},
+		{"text presentation", "❤︎", []string{"❤︎"}},
+		{"space", "ICE CREAM", []string{"I", "C", "E", " ", "C", "R", "E", "A", "M"}},
+		{"empty", "", nil},
+	}
+
+	for _, test := range tests {
+		assert.DeepEqual(t, Graphemes(test.word), test.want)
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	tests := []struct {
+		word string
+		want Fit
+	}{
+		{"APPLE", Fit{Class: Text, Length: 5}},
+		{"FLUGHAFEN", Fit{Class: Text, Length: 9, Size: 1}},
+		{"SCHIFFFAHRT", Fit{Class: Text, Length: 11, Size: 1}},
+		{"STRASSENBAHN", Fit{Class: Text, Length: 12, Size: 2}},
+		{"KREDITKARTENBETRUG", Fit{Class: Text, Length: 18, Size: 3}},
+
+		// Combining characters don't count.
+		{"ÉÉÉÉÉÉÉÉ", Fit{Class: Text, Length: 8}},
+		{"Z̴̗͑͂Ằ̈́L͛͊G̈O", Fit{Class: Text, Length: 5}},
+
+		// Wide characters count twice.
+		{"東京", Fit{Class: Text, Length: 4}},
+		{"東京タワー", Fit{Class: Text, Length: 10, Size: 1}},
+		{"한글", Fit{Class: Text, Length: 4}},
+		{"ｆｕｌｌ", Fit{Class: Text, Length: 8}},
+		{"ﾊﾝｶｸ", Fit{Class: Text, Length: 4}}, // Halfwidth.
+
+		// Emoji count once per cluster, however long.
+		{"🍎", Fit{Class: Emoji, Length: 1}},
+		{"🍎🍌🍒", Fit{Class: Emoji, Length: 3}},
+		{"👨‍👩‍👧‍👦👨‍👩‍👧‍👦👨‍👩‍👧‍👦👨‍👩‍👧‍👦", Fit{Class: Emoji, Length: 4, Size: 1}},
+		{"🇩🇪 🇫🇷 🇪🇸 🇮🇹 🇬🇧 🇺🇸", Fit{Class: Emoji, Length: 6, Size: 2}},
+		{"1️⃣", Fit{Class: Emoji, Length: 1}},
+		{"❤️", Fit{Class: Emoji, Length: 1}},
+
+		// Words mixing emoji and text are text, and wide emoji are wide.
+		{"ICE 🧊", Fit{Class: Text, Length: 6}},
+		{"❤︎", Fit{Class: Text, Length: 1}},
+	}
+
+	for _, test := range tests {
+		assert.DeepEqual(t, Measure(test.word, DefaultThresholds), test.want)
+	}
+}
+
+func TestMeasureThresholds(t *testing.T) {
+	thresholds := Thresholds{Text: []int{3}, Emoji: []int{1, 2}}
+
+	assert.DeepEqual(t, Measure("CAT", thresholds), Fit{Class: Text, Length: 3})
+	assert.DeepEqual(t, Measure("BIRD", thresholds), Fit{Class: Text, Length: 4, Size: 1})
+	assert.DeepEqual(t, Measure("KREDITKARTENBETRUG", thresholds), Fit{Class: Text, Length: 18, Size: 1})
+	assert.DeepEqual(t, Measure("🍎🍌🍒", thresholds), Fit{Class: Emoji, Length: 3, Size: 2})
+
+	// Without thresholds, everything is full size.
+	assert.DeepEqual(t, Measure("KREDITKARTENBETRUG", Thresholds{}), Fit{Class: Text, Length: 18})
+}
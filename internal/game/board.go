@@ -1,6 +1,7 @@
 package game
 
 import (
+	"github.com/zikaeroh/codies/internal/fit"
 	"github.com/zikaeroh/codies/internal/words"
 )
 
@@ -17,6 +18,7 @@ type Tile struct {
 	Team    Team
 	Neutral bool
 	Bomb    bool
+	Fit     fit.Fit // How to display the word; set by the room.
 
 	// Mutable
 	Revealed bool
@@ -25,16 +27,25 @@ type Tile struct {
 type Board struct {
 	Rows, Cols int
 	WordCounts []int
-	tiles      []*Tile // len(items)=rows*cols, access via items[row*rows + col]
+	tiles      []*Tile // len(items)=rows*cols, access via items[row*cols + col]
 }
 
-func newBoard(rows, cols int, words words.List, startingTeam Team, numTeams int, rand Rand) *Board {
+// NewBoard generates a board of rows*cols tiles using words from the list. The
+// starting team is given the most words. NewBoard depends only on its inputs,
+// so a board can be reproduced from the same Rand.
+func NewBoard(rows, cols, bombs int, words words.List, startingTeam Team, numTeams int, rand Rand) *Board {
+	return newBoard(rows, cols, bombs, words, startingTeam, numTeams, rand, make(map[int]struct{}, rows*cols))
+}
+
+// newBoard is NewBoard, but skips the words in seen, and adds the words it
+// picks to seen. Boards generated with the same seen never share words.
+func newBoard(rows, cols, bombs int, words words.List, startingTeam Team, numTeams int, rand Rand, seen map[int]struct{}) *Board {
 	if startingTeam < 0 || int(startingTeam) >= numTeams {
 		panic("invalid starting team")
 	}
 
 	n := rows * cols
-	layout, ok := layouts[layoutKey{boardSize: n, numTeams: numTeams}]
+	layout, ok := newLayout(n, numTeams, bombs)
 	if !ok {
 		panic("invalid board dimension")
 	}
@@ -46,7 +57,6 @@ func newBoard(rows, cols int, words words.List, startingTeam Team, numTeams int,
 	wordCounts := append([]int(nil), layout.teams...)
 
 	items := make([]*Tile, n)
-	seen := make(map[int]struct{}, n)
 
 	for i := range items {
 		var w string
@@ -100,6 +110,28 @@ func newBoard(rows, cols int, words words.List, startingTeam Team, numTeams int,
 	}
 }
 
+// Bounds on the number of rows and columns in a board.
+const (
+	MinBoardSide = 3
+	MaxBoardSide = 6
+)
+
+// ValidBoardSize returns true if boards of the given size can be generated.
+func ValidBoardSize(rows, cols, numTeams int) bool {
+	if rows < MinBoardSide || rows > MaxBoardSide || cols < MinBoardSide || cols > MaxBoardSide {
+		return false
+	}
+	_, ok := newLayout(rows*cols, numTeams, 0)
+	return ok
+}
+
+// measure sets the fit of each tile's word.
+func (b *Board) measure(t fit.Thresholds) {
+	for _, tile := range b.tiles {
+		tile.Fit = fit.Measure(tile.Word, t)
+	}
+}
+
 func (b *Board) Get(row, col int) *Tile {
 	switch {
 	case row < 0:
@@ -107,7 +139,7 @@ func (b *Board) Get(row, col int) *Tile {
 	case row >= b.Rows:
 	case col >= b.Cols:
 	default:
-		i := row*b.Rows + col
+		i := row*b.Cols + col
 		return b.tiles[i]
 	}
 
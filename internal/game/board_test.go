@@ -0,0 +1,176 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/words"
+	"gotest.tools/v3/assert"
+)
+
+// These tests check the board generator for bias. Each uses a fixed RNG
+// stream, so they're deterministic; the thresholds are a little above the
+// chi-square critical values at p=0.001, so a failure means a real bias
+// rather than bad luck.
+
+const (
+	boardSamples = 20000
+	boardSize    = 5
+)
+
+func testWords(n int) words.List {
+	list := make([]string, n)
+	for i := range list {
+		list[i] = fmt.Sprintf("WORD%d", i)
+	}
+	return words.NewList(list)
+}
+
+// chiSquare returns the chi-square statistic of the observed counts against a
+// uniform distribution.
+func chiSquare(observed []int) float64 {
+	total := 0
+	for _, o := range observed {
+		total += o
+	}
+	expected := float64(total) / float64(len(observed))
+
+	var x float64
+	for _, o := range observed {
+		d := float64(o) - expected
+		x += d * d / expected
+	}
+	return x
+}
+
+func TestBoardColorPlacementUniform(t *testing.T) {
+	list := testWords(100)
+	rng := rand.New(rand.NewSource(1)) //nolint:gosec
+
+	n := boardSize * boardSize
+	bombs := make([]int, n)
+	neutrals := make([]int, n)
+	starting := make([]int, n)
+
+	for i := 0; i < boardSamples; i++ {
+		b := NewBoard(boardSize, boardSize, 1, list, 0, 2, rng)
+		for pos, tile := range b.tiles {
+			switch {
+			case tile.Bomb:
+				bombs[pos]++
+			case tile.Neutral:
+				neutrals[pos]++
+			case tile.Team == 0:
+				starting[pos]++
+			}
+		}
+	}
+
+	// 24 degrees of freedom; the critical value is 51.2.
+	const limit = 60
+	assert.Assert(t, chiSquare(bombs) < limit, "bomb placement: %v", bombs)
+	assert.Assert(t, chiSquare(neutrals) < limit, "neutral placement: %v", neutrals)
+	assert.Assert(t, chiSquare(starting) < limit, "starting team placement: %v", starting)
+}
+
+func TestBoardBombNeighbors(t *testing.T) {
+	list := testWords(100)
+	rng := rand.New(rand.NewSource(2)) //nolint:gosec
+
+	neighbors, startingNeighbors := 0, 0
+
+	for i := 0; i < boardSamples; i++ {
+		b := NewBoard(boardSize, boardSize, 1, list, 1, 2, rng)
+		for row := 0; row < b.Rows; row++ {
+			for col := 0; col < b.Cols; col++ {
+				if !b.Get(row, col).Bomb {
+					continue
+				}
+
+				for _, d := range [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+					tile := b.Get(row+d[0], col+d[1])
+					if tile == nil {
+						continue
+					}
+					neighbors++
+					if !tile.Neutral && tile.Team == 1 {
+						startingNeighbors++
+					}
+				}
+			}
+		}
+	}
+
+	// 9 of the 24 other tiles belong to the starting team; a bomb's
+	// neighbors should be no different.
+	got := float64(startingNeighbors) / float64(neighbors)
+	want := 9.0 / 24.0
+	assert.Assert(t, got > want-0.01 && got < want+0.01, "starting team neighbors %.4f, want %.4f", got, want)
+}
+
+func TestBoardWordSelectionUniform(t *testing.T) {
+	const numWords = 100
+	list := testWords(numWords)
+	rng := rand.New(rand.NewSource(3)) //nolint:gosec
+
+	index := make(map[string]int, numWords)
+	for i := 0; i < numWords; i++ {
+		index[list.Get(i)] = i
+	}
+
+	counts := make([]int, numWords)
+	for i := 0; i < boardSamples; i++ {
+		b := NewBoard(boardSize, boardSize, 1, list, 0, 2, rng)
+
+		seen := make(map[string]bool, len(b.tiles))
+		for _, tile := range b.tiles {
+			assert.Assert(t, !seen[tile.Word], "duplicate word %s", tile.Word)
+			seen[tile.Word] = true
+			counts[index[tile.Word]]++
+		}
+	}
+
+	// 99 degrees of freedom; the critical value is 148.2.
+	assert.Assert(t, chiSquare(counts) < 160, "word selection: %v", counts)
+}
+
+func TestBoardIndependentOfSeedLowBits(t *testing.T) {
+	list := testWords(100)
+
+	// Seeds which differ only in their low bits should still produce unrelated
+	// boards; look at the first board from each.
+	n := boardSize * boardSize
+	bombs := make([]int, n)
+	firstWords := make(map[string]int)
+
+	for seed := int64(0); seed < boardSamples; seed++ {
+		rng := rand.New(rand.NewSource(seed)) //nolint:gosec
+		b := NewBoard(boardSize, boardSize, 1, list, 0, 2, rng)
+
+		for pos, tile := range b.tiles {
+			if tile.Bomb {
+				bombs[pos]++
+			}
+		}
+		firstWords[b.tiles[0].Word]++
+	}
+
+	assert.Assert(t, chiSquare(bombs) < 60, "bomb placement: %v", bombs)
+
+	counts := make([]int, 0, len(firstWords))
+	for i := 0; i < list.Len(); i++ {
+		counts = append(counts, firstWords[list.Get(i)])
+	}
+	assert.Assert(t, chiSquare(counts) < 160, "first word: %v", counts)
+}
+
+func TestBoardReproducible(t *testing.T) {
+	list := testWords(100)
+	a := NewBoard(boardSize, boardSize, 1, list, 0, 2, rand.New(rand.NewSource(4))) //nolint:gosec
+	b := NewBoard(boardSize, boardSize, 1, list, 0, 2, rand.New(rand.NewSource(4))) //nolint:gosec
+
+	for i := range a.tiles {
+		assert.Equal(t, *a.tiles[i], *b.tiles[i])
+	}
+}
@@ -0,0 +1,229 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MaxTeamConstraints is the most team constraints a room may have.
+const MaxTeamConstraints = 50
+
+// TeamConstraint keeps two players on the same team when teams are
+// randomized, or on different teams if Apart is set.
+type TeamConstraint struct {
+	A, B  PlayerID
+	Apart bool
+}
+
+// constraintEdge is a constraint as seen from one of its players.
+type constraintEdge struct {
+	to    PlayerID
+	index int // Into the constraints.
+}
+
+// constraintForest is a spanning forest of a set of constraints: between any
+// two players, at most one path of constraints, which decides whether they
+// must be together or apart.
+type constraintForest map[PlayerID][]constraintEdge
+
+// path returns the indexes of the constraints on the path from a to b, or
+// false if the two aren't connected.
+func (f constraintForest) path(a, b PlayerID) ([]int, bool) {
+	if a == b {
+		return nil, true
+	}
+
+	// The edge each player was first reached by, from a.
+	via := map[PlayerID]constraintEdge{a: {index: -1}}
+	queue := []PlayerID{a}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		for _, e := range f[id] {
+			if _, ok := via[e.to]; ok {
+				continue
+			}
+			via[e.to] = constraintEdge{to: id, index: e.index}
+			queue = append(queue, e.to)
+		}
+	}
+
+	if _, ok := via[b]; !ok {
+		return nil, false
+	}
+
+	var path []int
+	for id := b; id != a; id = via[id].to {
+		path = append(path, via[id].index)
+	}
+	return path, true
+}
+
+func (f constraintForest) add(a, b PlayerID, index int) {
+	f[a] = append(f[a], constraintEdge{to: b, index: index})
+	f[b] = append(f[b], constraintEdge{to: a, index: index})
+}
+
+// newConstraintForest builds the forest of the constraints, returning the
+// first set of them which contradict each other, if any.
+//
+// Each constraint relates the "sides" of two players: together keeps them on
+// the same side, apart puts them on opposite sides. A set of constraints can
+// be satisfied by two teams exactly when no cycle of them has an odd number of
+// apart constraints.
+func newConstraintForest(cs []TeamConstraint) (constraintForest, []int) {
+	f := make(constraintForest)
+	for i, c := range cs {
+		path, ok := f.path(c.A, c.B)
+		if !ok {
+			f.add(c.A, c.B, i)
+			continue
+		}
+
+		apart := c.Apart
+		for _, j := range path {
+			if cs[j].Apart {
+				apart = !apart
+			}
+		}
+
+		if apart {
+			conflict := append(path, i)
+			sort.Ints(conflict)
+			return nil, conflict
+		}
+	}
+	return f, nil
+}
+
+// ChangeTeamConstraints replaces the constraints RandomizeTeams follows. They
+// must be satisfiable; otherwise, the constraints are left as they were, and
+// the error's Conflicts are the ones which contradict each other.
+//
+// Constraints are kept until they're changed, even if the players they name
+// leave, so that players who drop and rejoin keep theirs. New constraints may
+// only name players in the room.
+func (r *Room) ChangeTeamConstraints(cs []TeamConstraint) error {
+	if max := MaxTeamConstraints; len(cs) > max {
+		return &Error{
+			Code:    "tooManyConstraints",
+			Message: fmt.Sprintf("Rooms may have at most %d team constraints.", max),
+			Limit:   &max,
+		}
+	}
+
+	for _, c := range cs {
+		if c.A == c.B || (!r.hasTeamConstraint(c) && (r.Players[c.A] == nil || r.Players[c.B] == nil)) {
+			return &Error{
+				Code:    "invalidConstraint",
+				Message: "Team constraints must name two different players in the room.",
+			}
+		}
+	}
+
+	if _, conflict := newConstraintForest(cs); conflict != nil {
+		conflicts := make([]TeamConstraint, len(conflict))
+		for i, j := range conflict {
+			conflicts[i] = cs[j]
+		}
+
+		return &Error{
+			Code:      "unsatisfiableConstraints",
+			Message:   "Some team constraints contradict each other.",
+			Conflicts: conflicts,
+		}
+	}
+
+	if sameConstraints(cs, r.TeamConstraints) {
+		return nil
+	}
+
+	r.TeamConstraints = append([]TeamConstraint(nil), cs...)
+	r.Version++
+	return nil
+}
+
+func (r *Room) hasTeamConstraint(c TeamConstraint) bool {
+	for _, have := range r.TeamConstraints {
+		if have == c {
+			return true
+		}
+	}
+	return false
+}
+
+func sameConstraints(a, b []TeamConstraint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// constraintGroup is a set of players who must be placed together: the
+// players on one side, and the players on the other, who must all be on
+// another team.
+type constraintGroup struct {
+	sides [2][]PlayerID
+}
+
+// imbalance is how much placing the group makes one team bigger than the
+// other.
+func (g *constraintGroup) imbalance() int {
+	d := len(g.sides[0]) - len(g.sides[1])
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// constraintGroups groups the players by the constraints between them, in a
+// fixed order. Constraints naming players not in the room are ignored; the
+// rest are known to be satisfiable.
+func (r *Room) constraintGroups(players []PlayerID) []*constraintGroup {
+	var present []TeamConstraint
+	for _, c := range r.TeamConstraints {
+		if r.Players[c.A] != nil && r.Players[c.B] != nil {
+			present = append(present, c)
+		}
+	}
+
+	f, _ := newConstraintForest(present)
+
+	side := make(map[PlayerID]int, len(players))
+	var groups []*constraintGroup
+	for _, id := range players {
+		if _, ok := side[id]; ok {
+			continue
+		}
+
+		// Walk the tree from id, placing each player on a side.
+		g := &constraintGroup{}
+		side[id] = 0
+		queue := []PlayerID{id}
+		for len(queue) > 0 {
+			p := queue[0]
+			queue = queue[1:]
+			g.sides[side[p]] = append(g.sides[side[p]], p)
+
+			for _, e := range f[p] {
+				if _, ok := side[e.to]; ok {
+					continue
+				}
+				s := side[p]
+				if present[e.index].Apart {
+					s = 1 - s
+				}
+				side[e.to] = s
+				queue = append(queue, e.to)
+			}
+		}
+		groups = append(groups, g)
+	}
+	return groups
+}
@@ -0,0 +1,208 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+// newConstraintRoom returns a seeded room with the players p0 through p(n-1).
+func newConstraintRoom(n int) *Room {
+	r := NewRoom(NewRand(1))
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("p%d", i)
+		r.AddPlayer(id, id)
+	}
+	return r
+}
+
+func together(a, b PlayerID) TeamConstraint {
+	return TeamConstraint{A: a, B: b}
+}
+
+func apart(a, b PlayerID) TeamConstraint {
+	return TeamConstraint{A: a, B: b, Apart: true}
+}
+
+func TestChangeTeamConstraintsConflicts(t *testing.T) {
+	tests := []struct {
+		name      string
+		cs        []TeamConstraint
+		conflicts []TeamConstraint
+	}{
+		{
+			name: "together and apart",
+			cs:   []TeamConstraint{together("p0", "p1"), apart("p1", "p0")},
+			conflicts: []TeamConstraint{
+				together("p0", "p1"),
+				apart("p1", "p0"),
+			},
+		},
+		{
+			name: "apart from someone together",
+			cs:   []TeamConstraint{together("p0", "p1"), apart("p2", "p3"), together("p1", "p2"), apart("p0", "p2")},
+			conflicts: []TeamConstraint{
+				together("p0", "p1"),
+				together("p1", "p2"),
+				apart("p0", "p2"),
+			},
+		},
+		{
+			// Three players can't all be on different teams.
+			name: "odd cycle",
+			cs:   []TeamConstraint{apart("p0", "p1"), apart("p1", "p2"), together("p3", "p4"), apart("p2", "p0")},
+			conflicts: []TeamConstraint{
+				apart("p0", "p1"),
+				apart("p1", "p2"),
+				apart("p2", "p0"),
+			},
+		},
+		{
+			name: "even cycle",
+			cs:   []TeamConstraint{apart("p0", "p1"), apart("p1", "p2"), apart("p2", "p3"), apart("p3", "p0"), together("p0", "p2")},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			r := newConstraintRoom(5)
+			version := r.Version
+
+			err := r.ChangeTeamConstraints(test.cs)
+			if test.conflicts == nil {
+				assert.NilError(t, err)
+				assert.DeepEqual(t, r.TeamConstraints, test.cs)
+				assert.Equal(t, r.Version, version+1)
+				return
+			}
+
+			var gErr *Error
+			assert.Assert(t, errors.As(err, &gErr))
+			assert.Equal(t, gErr.Code, "unsatisfiableConstraints")
+			assert.DeepEqual(t, gErr.Conflicts, test.conflicts)
+			assert.Assert(t, r.TeamConstraints == nil)
+			assert.Equal(t, r.Version, version)
+		})
+	}
+}
+
+func TestChangeTeamConstraintsInvalid(t *testing.T) {
+	r := newConstraintRoom(3)
+
+	var gErr *Error
+	err := r.ChangeTeamConstraints([]TeamConstraint{together("p0", "p0")})
+	assert.Assert(t, errors.As(err, &gErr))
+	assert.Equal(t, gErr.Code, "invalidConstraint")
+
+	err = r.ChangeTeamConstraints([]TeamConstraint{together("p0", "nobody")})
+	assert.Assert(t, errors.As(err, &gErr))
+	assert.Equal(t, gErr.Code, "invalidConstraint")
+
+	cs := make([]TeamConstraint, MaxTeamConstraints+1)
+	for i := range cs {
+		cs[i] = together("p0", "p1")
+	}
+	err = r.ChangeTeamConstraints(cs)
+	assert.Assert(t, errors.As(err, &gErr))
+	assert.Equal(t, gErr.Code, "tooManyConstraints")
+	assert.Equal(t, *gErr.Limit, MaxTeamConstraints)
+}
+
+func TestChangeTeamConstraintsPlayerLeft(t *testing.T) {
+	r := newConstraintRoom(3)
+	assert.NilError(t, r.ChangeTeamConstraints([]TeamConstraint{together("p0", "p1")}))
+
+	r.RemovePlayer("p1")
+	r.NewGame()
+	assert.DeepEqual(t, r.TeamConstraints, []TeamConstraint{together("p0", "p1")})
+
+	// Constraints already kept may still name them, but new ones can't.
+	assert.NilError(t, r.ChangeTeamConstraints([]TeamConstraint{together("p0", "p1"), apart("p0", "p2")}))
+	assert.ErrorContains(t, r.ChangeTeamConstraints([]TeamConstraint{apart("p1", "p2")}), "players in the room")
+
+	// Clearing them is always allowed.
+	assert.NilError(t, r.ChangeTeamConstraints(nil))
+	assert.Equal(t, len(r.TeamConstraints), 0)
+}
+
+func teamOf(r *Room, id PlayerID) Team {
+	return r.Players[id].Team
+}
+
+func TestRandomizeTeamsConstraints(t *testing.T) {
+	r := newConstraintRoom(8)
+	assert.NilError(t, r.ChangeTeamConstraints([]TeamConstraint{
+		together("p0", "p1"),
+		together("p1", "p2"),
+		apart("p0", "p3"),
+		apart("p4", "p5"),
+	}))
+
+	for i := 0; i < 100; i++ {
+		r.RandomizeTeams()
+
+		assert.Equal(t, teamOf(r, "p1"), teamOf(r, "p0"))
+		assert.Equal(t, teamOf(r, "p2"), teamOf(r, "p0"))
+		assert.Assert(t, teamOf(r, "p3") != teamOf(r, "p0"))
+		assert.Assert(t, teamOf(r, "p5") != teamOf(r, "p4"))
+
+		assert.Equal(t, len(r.Teams[0]), 4)
+		assert.Equal(t, len(r.Teams[1]), 4)
+		for team, ids := range r.Teams {
+			for _, id := range ids {
+				assert.Equal(t, teamOf(r, id), Team(team))
+			}
+		}
+	}
+}
+
+func TestRandomizeTeamsConstraintsSeeded(t *testing.T) {
+	r := newConstraintRoom(6)
+	assert.NilError(t, r.ChangeTeamConstraints([]TeamConstraint{
+		together("p0", "p1"),
+		apart("p0", "p2"),
+	}))
+
+	r.RandomizeTeams()
+	assert.DeepEqual(t, r.Teams, [][]PlayerID{{"p5", "p2", "p4"}, {"p0", "p1", "p3"}})
+
+	r.RandomizeTeams()
+	assert.DeepEqual(t, r.Teams, [][]PlayerID{{"p0", "p1", "p4"}, {"p2", "p3", "p5"}})
+}
+
+func TestRandomizeTeamsConstraintsUnbalanced(t *testing.T) {
+	// A group bigger than half the room can't be split up.
+	r := newConstraintRoom(4)
+	assert.NilError(t, r.ChangeTeamConstraints([]TeamConstraint{
+		together("p0", "p1"),
+		together("p1", "p2"),
+	}))
+
+	r.RandomizeTeams()
+	big := teamOf(r, "p0")
+	assert.Equal(t, len(r.Teams[big]), 3)
+	assert.Assert(t, teamOf(r, "p3") != big)
+}
+
+func TestRandomizeTeamsConstraintsAbsent(t *testing.T) {
+	r := newConstraintRoom(4)
+	assert.NilError(t, r.ChangeTeamConstraints([]TeamConstraint{
+		together("p0", "p1"),
+		together("p1", "p2"),
+	}))
+
+	// With p1 gone, p0 and p2 are no longer tied together.
+	r.RemovePlayer("p1")
+	split := false
+	for i := 0; i < 50; i++ {
+		r.RandomizeTeams()
+		assert.Equal(t, len(r.Teams[0])+len(r.Teams[1]), 3)
+		if teamOf(r, "p0") != teamOf(r, "p2") {
+			split = true
+		}
+	}
+	assert.Assert(t, split)
+}
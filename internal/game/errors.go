@@ -0,0 +1,27 @@
+package game
+
+// Error is a rule violation which should be reported back to the player who
+// attempted the action, rather than silently ignored.
+type Error struct {
+	Code    string
+	Message string
+
+	// Limit is set when the violation is the result of exceeding some bound.
+	Limit *int
+
+	// Shortfalls is set when a new game can't start, listing each team which
+	// is missing players.
+	Shortfalls []*Shortfall
+
+	// Conflicts is set when team constraints can't all be satisfied, listing
+	// a set of them which contradict each other.
+	Conflicts []TeamConstraint
+
+	// RetryAfter is set when the action may succeed if retried later, in
+	// seconds.
+	RetryAfter *int
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
@@ -0,0 +1,143 @@
+package game
+
+import "fmt"
+
+// MaxHints is the most hints a team may be given per game.
+const MaxHints = 3
+
+// CheckHintBudget returns an error unless the budget gives each team between
+// zero and MaxHints hints.
+func (r *Room) CheckHintBudget(budget []int) error {
+	if len(budget) != len(r.Teams) {
+		return &Error{
+			Code:    "invalidHints",
+			Message: fmt.Sprintf("Hint budgets must be given for all %d teams.", len(r.Teams)),
+		}
+	}
+
+	for _, n := range budget {
+		if n < 0 || n > MaxHints {
+			max := MaxHints
+			return &Error{
+				Code:    "invalidHints",
+				Message: fmt.Sprintf("Teams may have between 0 and %d hints.", MaxHints),
+				Limit:   &max,
+			}
+		}
+	}
+
+	return nil
+}
+
+// ChangeHintBudget sets the number of hints each team gets, starting with the
+// next new game.
+func (r *Room) ChangeHintBudget(budget []int) error {
+	if err := r.CheckHintBudget(budget); err != nil {
+		return err
+	}
+
+	if intsEqual(r.HintBudget, budget) {
+		return nil
+	}
+
+	r.HintBudget = append([]int(nil), budget...)
+	r.Version++
+	return nil
+}
+
+// HintsEnabled returns true if any team has hints in the current or next
+// game.
+func (r *Room) HintsEnabled() bool {
+	for _, n := range r.HintBudget {
+		if n > 0 {
+			return true
+		}
+	}
+	for _, n := range r.HintsUsed {
+		if n > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Room) hintBudget(team Team) int {
+	if int(team) >= len(r.HintBudget) {
+		return 0
+	}
+	return r.HintBudget[team]
+}
+
+func (r *Room) resetHints() {
+	r.Hints = make([]int, len(r.Teams))
+	r.HintsUsed = make([]int, len(r.Teams))
+	for team := range r.Hints {
+		r.Hints[team] = r.hintBudget(Team(team))
+	}
+}
+
+// UseHint spends one of the guesser's team's hints to reveal a random
+// unrevealed neutral tile on a board, chosen with the room's random source.
+// The turn continues afterward.
+func (r *Room) UseHint(id PlayerID, board int) error {
+	if r.Winner != nil {
+		return nil
+	}
+
+	p := r.Players[id]
+	if p == nil {
+		return nil
+	}
+
+	if p.Spymaster || p.Team != r.Turn {
+		return nil
+	}
+
+	b := r.board(board)
+	if b == nil || !r.turnAllows(board) {
+		return nil
+	}
+
+	if r.Hints[p.Team] == 0 {
+		return &Error{
+			Code:    "noHints",
+			Message: "Your team has no hints left.",
+		}
+	}
+
+	var neutrals []*Tile
+	for row := 0; row < b.Rows; row++ {
+		for col := 0; col < b.Cols; col++ {
+			if tile := b.Get(row, col); tile.Neutral && !tile.Revealed {
+				neutrals = append(neutrals, tile)
+			}
+		}
+	}
+
+	if len(neutrals) == 0 {
+		return &Error{
+			Code:    "noHintTiles",
+			Message: "There are no neutral cards left to reveal.",
+		}
+	}
+
+	neutrals[r.rand.Intn(len(neutrals))].Revealed = true
+	r.forgetReveal()
+	r.Hints[p.Team]--
+	r.HintsUsed[p.Team]++
+	r.chooseBoard(board)
+	r.Version++
+	return nil
+}
+
+func intsEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,140 @@
+package game
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func unrevealedNeutrals(b *Board) int {
+	n := 0
+	for row := 0; row < b.Rows; row++ {
+		for col := 0; col < b.Cols; col++ {
+			if tile := b.Get(row, col); tile.Neutral && !tile.Revealed {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func newHintRoom(t *testing.T, budget ...int) *Room {
+	t.Helper()
+
+	r := newTestRoom(t)
+	assert.NilError(t, r.ChangeHintBudget(budget))
+	r.NewGame()
+	assert.NilError(t, r.ChangeRole("spy0", true))
+	assert.NilError(t, r.ChangeRole("spy1", true))
+	r.Turn = 0
+	return r
+}
+
+func TestUseHint(t *testing.T) {
+	r := newHintRoom(t, 2, 0)
+	assert.DeepEqual(t, r.Hints, []int{2, 0})
+
+	counts := append([]int(nil), r.Board.WordCounts...)
+	neutrals := unrevealedNeutrals(r.Board)
+
+	assert.NilError(t, r.UseHint("guess0", 0))
+	assert.Equal(t, unrevealedNeutrals(r.Board), neutrals-1)
+	assert.DeepEqual(t, r.Board.WordCounts, counts)
+	assert.DeepEqual(t, r.Hints, []int{1, 0})
+	assert.DeepEqual(t, r.HintsUsed, []int{1, 0})
+
+	// The turn continues.
+	assert.Equal(t, r.Turn, Team(0))
+	assert.Assert(t, r.Winner == nil)
+
+	assert.NilError(t, r.UseHint("guess0", 0))
+	var gErr *Error
+	assert.Assert(t, errors.As(r.UseHint("guess0", 0), &gErr))
+	assert.Equal(t, gErr.Code, "noHints")
+	assert.DeepEqual(t, r.HintsUsed, []int{2, 0})
+
+	// A new game restores the budget.
+	r.NewGame()
+	assert.DeepEqual(t, r.Hints, []int{2, 0})
+	assert.DeepEqual(t, r.HintsUsed, []int{0, 0})
+}
+
+func TestUseHintIgnored(t *testing.T) {
+	r := newHintRoom(t, 1, 1)
+	version := r.Version
+
+	assert.NilError(t, r.UseHint("spy0", 0))   // Spymaster.
+	assert.NilError(t, r.UseHint("guess1", 0)) // Not their turn.
+	assert.NilError(t, r.UseHint("guess0", 1)) // No such board.
+	assert.NilError(t, r.UseHint("missing", 0))
+	assert.Equal(t, r.Version, version)
+	assert.DeepEqual(t, r.Hints, []int{1, 1})
+}
+
+func TestUseHintNoNeutrals(t *testing.T) {
+	r := newHintRoom(t, 1, 0)
+
+	for row := 0; row < r.Board.Rows; row++ {
+		for col := 0; col < r.Board.Cols; col++ {
+			if tile := r.Board.Get(row, col); tile.Neutral {
+				tile.Revealed = true
+			}
+		}
+	}
+
+	var gErr *Error
+	assert.Assert(t, errors.As(r.UseHint("guess0", 0), &gErr))
+	assert.Equal(t, gErr.Code, "noHintTiles")
+	assert.DeepEqual(t, r.Hints, []int{1, 0})
+}
+
+func TestUseHintReproducible(t *testing.T) {
+	revealed := func() []string {
+		r := NewRoom(rand.New(rand.NewSource(7))) //nolint:gosec
+		r.AddPlayer("guess", "Guess")
+		assert.NilError(t, r.ChangeHintBudget([]int{MaxHints, MaxHints}))
+		r.NewGame()
+		r.ChangeTeam("guess", r.Turn)
+
+		var words []string
+		for i := 0; i < MaxHints; i++ {
+			assert.NilError(t, r.UseHint("guess", 0))
+		}
+		for row := 0; row < r.Board.Rows; row++ {
+			for col := 0; col < r.Board.Cols; col++ {
+				if tile := r.Board.Get(row, col); tile.Revealed {
+					assert.Assert(t, tile.Neutral)
+					words = append(words, tile.Word)
+				}
+			}
+		}
+		return words
+	}
+
+	first := revealed()
+	assert.Equal(t, len(first), MaxHints)
+	assert.DeepEqual(t, revealed(), first)
+}
+
+func TestChangeHintBudget(t *testing.T) {
+	r := newTestRoom(t)
+	version := r.Version
+
+	var gErr *Error
+	assert.Assert(t, errors.As(r.ChangeHintBudget([]int{1}), &gErr))
+	assert.Assert(t, errors.As(r.ChangeHintBudget([]int{MaxHints + 1, 0}), &gErr))
+	assert.Assert(t, errors.As(r.ChangeHintBudget([]int{-1, 0}), &gErr))
+	assert.Equal(t, r.Version, version)
+	assert.Assert(t, !r.HintsEnabled())
+
+	// The budget applies from the next game.
+	assert.NilError(t, r.ChangeHintBudget([]int{0, 1}))
+	assert.Equal(t, r.Version, version+1)
+	assert.DeepEqual(t, r.Hints, []int{0, 0})
+	assert.Assert(t, r.HintsEnabled())
+
+	assert.NilError(t, r.ChangeHintBudget([]int{0, 1}))
+	assert.Equal(t, r.Version, version+1)
+}
@@ -0,0 +1,68 @@
+package game
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// CanonicalKey serializes the current game's key: the seed, then every tile
+// of every board with its team, whether it's neutral, and whether it's the
+// bomb. Whether tiles have been revealed isn't included, so the key is the
+// same for the whole game.
+//
+// The format is one field per line:
+//
+//	s<seed>
+//	b<board>
+//	<row>,<col>:<word>:<team>/<0 or 1 if neutral>/<0 or 1 if bomb>
+//
+// with boards in order and tiles in row-major order.
+func (r *Room) CanonicalKey() string {
+	var b strings.Builder
+
+	b.WriteString("s")
+	b.WriteString(strconv.Itoa(r.Seed))
+	b.WriteString("\n")
+
+	for i, board := range r.Boards {
+		b.WriteString("b")
+		b.WriteString(strconv.Itoa(i))
+		b.WriteString("\n")
+
+		for row := 0; row < board.Rows; row++ {
+			for col := 0; col < board.Cols; col++ {
+				tile := board.Get(row, col)
+				b.WriteString(strconv.Itoa(row))
+				b.WriteString(",")
+				b.WriteString(strconv.Itoa(col))
+				b.WriteString(":")
+				b.WriteString(tile.Word)
+				b.WriteString(":")
+				b.WriteString(strconv.Itoa(int(tile.Team)))
+				b.WriteString("/")
+				b.WriteString(boolDigit(tile.Neutral))
+				b.WriteString("/")
+				b.WriteString(boolDigit(tile.Bomb))
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	return b.String()
+}
+
+func boolDigit(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// KeyChecksum is a short checksum of CanonicalKey, which spymasters can read
+// aloud to one another to check that they're looking at the same key.
+func (r *Room) KeyChecksum() string {
+	sum := sha256.Sum256([]byte(r.CanonicalKey()))
+	return hex.EncodeToString(sum[:])[:6]
+}
@@ -0,0 +1,59 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCanonicalKey(t *testing.T) {
+	r := newTestRoom(t)
+	r.Seed = 42
+
+	key := r.CanonicalKey()
+	lines := strings.Split(strings.TrimSuffix(key, "\n"), "\n")
+	assert.Equal(t, len(lines), 2+r.Board.Rows*r.Board.Cols)
+	assert.Equal(t, lines[0], "s42")
+	assert.Equal(t, lines[1], "b0")
+
+	tile := r.Board.Get(0, 0)
+	want := fmt.Sprintf("0,0:%s:%d/%s/%s", tile.Word, tile.Team, boolDigit(tile.Neutral), boolDigit(tile.Bomb))
+	assert.Equal(t, lines[2], want)
+}
+
+func TestKeyChecksum(t *testing.T) {
+	r := newTestRoom(t)
+
+	sum := r.KeyChecksum()
+	assert.Equal(t, len(sum), 6)
+
+	// Revealing tiles doesn't change the key.
+	row, col := findTile(t, r, func(tile *Tile) bool { return tile.Team == 0 && !tile.Neutral && !tile.Bomb })
+	r.Reveal("guess0", 0, row, col)
+	assert.Assert(t, r.Board.Get(row, col).Revealed)
+	assert.Equal(t, r.KeyChecksum(), sum)
+
+	// The seed is part of the key.
+	r.Seed++
+	assert.Assert(t, r.KeyChecksum() != sum)
+	r.Seed--
+
+	r.NewGame()
+	assert.Assert(t, r.KeyChecksum() != sum)
+}
+
+func TestKeyChecksumGauntlet(t *testing.T) {
+	r := newTestRoom(t)
+	assert.NilError(t, r.ChangeBoards(2, 4, 4, 1))
+	r.NewGame()
+
+	sum := r.KeyChecksum()
+	assert.Assert(t, strings.Contains(r.CanonicalKey(), "\nb1\n"))
+
+	// Every board is part of the key.
+	tile := r.Boards[1].Get(3, 3)
+	tile.Bomb = !tile.Bomb
+	assert.Assert(t, r.KeyChecksum() != sum)
+}
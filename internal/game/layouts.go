@@ -1,14 +1,30 @@
 package game
 
-type layoutKey struct {
-	boardSize int
-	numTeams  int
-}
-
-var layouts = map[layoutKey]struct {
+type layout struct {
 	bomb    int
 	neutral int
 	teams   []int
-}{
-	{25, 2}: {1, 7, []int{9, 8}},
+}
+
+// newLayout returns the layout of a board of n tiles with the given number of
+// bombs. The team tiles are scaled from the standard 25 tile board's 9 and 8,
+// and the rest are neutral. Only two teams are supported.
+func newLayout(n, numTeams, bombs int) (layout, bool) {
+	if numTeams != 2 || n < MinBoardSide*MinBoardSide || bombs < 0 {
+		return layout{}, false
+	}
+
+	// Rounded to the nearest tile.
+	second := (n*8 + 12) / 25
+
+	neutral := n - bombs - 2*second - 1
+	if neutral < 0 {
+		return layout{}, false
+	}
+
+	return layout{
+		bomb:    bombs,
+		neutral: neutral,
+		teams:   []int{second + 1, second},
+	}, true
 }
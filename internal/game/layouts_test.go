@@ -8,18 +8,72 @@ import (
 )
 
 func TestLayouts(t *testing.T) {
-	for key, layout := range layouts {
-		assert.Equal(t, len(layout.teams), key.numTeams)
+	for rows := MinBoardSide; rows <= MaxBoardSide; rows++ {
+		for cols := MinBoardSide; cols <= MaxBoardSide; cols++ {
+			n := rows * cols
+			layout, ok := newLayout(n, 2, 1)
+			assert.Assert(t, ok, n)
+			assert.Equal(t, len(layout.teams), 2)
+			assert.Equal(t, layout.bomb, 1)
+			assert.Assert(t, layout.neutral > 0, n)
 
-		sum := layout.bomb + layout.neutral
-		for _, x := range layout.teams {
-			sum += x
+			sum := layout.bomb + layout.neutral
+			for _, x := range layout.teams {
+				sum += x
+			}
+
+			assert.Equal(t, sum, n)
+
+			assert.Assert(t, sort.SliceIsSorted(layout.teams, func(i, j int) bool {
+				return layout.teams[i] >= layout.teams[j] //nolint:scopelint
+			}))
 		}
+	}
+}
 
-		assert.Equal(t, sum, key.boardSize)
+func TestLayoutsStandard(t *testing.T) {
+	tests := map[int]layout{
+		9:  {1, 1, []int{4, 3}},
+		12: {1, 2, []int{5, 4}},
+		16: {1, 4, []int{6, 5}},
+		20: {1, 6, []int{7, 6}},
+		25: {1, 7, []int{9, 8}},
+		36: {1, 10, []int{13, 12}},
+	}
 
-		assert.Assert(t, sort.SliceIsSorted(layout.teams, func(i, j int) bool {
-			return layout.teams[i] >= layout.teams[j] //nolint:scopelint
-		}))
+	for n, want := range tests {
+		got, ok := newLayout(n, 2, 1)
+		assert.Assert(t, ok)
+		assert.Equal(t, got.bomb, want.bomb, n)
+		assert.Equal(t, got.neutral, want.neutral, n)
+		assert.DeepEqual(t, got.teams, want.teams)
 	}
 }
+
+func TestLayoutsBombs(t *testing.T) {
+	// Bombs take the place of neutral tiles.
+	for bombs := 0; bombs <= 3; bombs++ {
+		layout, ok := newLayout(25, 2, bombs)
+		assert.Assert(t, ok)
+		assert.Equal(t, layout.bomb, bombs)
+		assert.Equal(t, layout.neutral, 8-bombs)
+		assert.DeepEqual(t, layout.teams, []int{9, 8})
+	}
+
+	// A 3x3 board has 1 neutral tile to spare.
+	_, ok := newLayout(9, 2, 2)
+	assert.Assert(t, ok)
+	_, ok = newLayout(9, 2, 3)
+	assert.Assert(t, !ok)
+}
+
+func TestLayoutsUnsupported(t *testing.T) {
+	_, ok := newLayout(25, 3, 1)
+	assert.Assert(t, !ok)
+
+	_, ok = newLayout(4, 2, 1)
+	assert.Assert(t, !ok)
+
+	_, ok = newLayout(25, 2, -1)
+	assert.Assert(t, !ok)
+}
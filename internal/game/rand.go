@@ -1,20 +1,24 @@
 package game
 
-import "math/rand"
+import (
+	"math/rand"
+
+	"github.com/zikaeroh/codies/internal/idgen"
+)
 
 type Rand interface {
 	Intn(n int) int
 	Shuffle(n int, swap func(i, j int))
 }
 
-type globalRand struct{}
-
-var _ Rand = globalRand{}
-
-func (globalRand) Intn(n int) int {
-	return rand.Intn(n) //nolint:gosec
-}
+var _ Rand = (*rand.Rand)(nil)
 
-func (globalRand) Shuffle(n int, swap func(i, j int)) {
-	rand.Shuffle(n, swap)
+// NewRand returns a source of game randomness for one room. Given a seed, the
+// room's games are reproducible; if zero, the seed comes from idgen. Game
+// randomness is never used for anything which must be unguessable.
+func NewRand(seed int64) Rand {
+	if seed == 0 {
+		seed = idgen.Seed()
+	}
+	return rand.New(rand.NewSource(seed)) //nolint:gosec
 }
@@ -1,6 +1,14 @@
 package game
 
 import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zikaeroh/codies/internal/fit"
+	"github.com/zikaeroh/codies/internal/names"
 	"github.com/zikaeroh/codies/internal/words"
 	"github.com/zikaeroh/codies/internal/words/static"
 )
@@ -8,9 +16,10 @@ import (
 type PlayerID = string
 
 type WordList struct {
-	Name   string
-	Custom bool
-	List   words.List
+	Name     string
+	Language string // The code of a built-in pack's language, if known.
+	Custom   bool
+	List     words.List
 
 	Enabled bool
 }
@@ -18,17 +27,20 @@ type WordList struct {
 func defaultWords() []*WordList {
 	return []*WordList{
 		{
-			Name:    "Base",
-			List:    static.Default,
-			Enabled: true,
+			Name:     "Base",
+			Language: static.DefaultLanguage,
+			List:     static.Default,
+			Enabled:  true,
 		},
 		{
-			Name: "Duet",
-			List: static.Duet,
+			Name:     "Duet",
+			Language: static.DefaultLanguage,
+			List:     static.Duet,
 		},
 		{
-			Name: "Undercover",
-			List: static.Undercover,
+			Name:     "Undercover",
+			Language: static.DefaultLanguage,
+			List:     static.Undercover,
 		},
 	}
 }
@@ -38,29 +50,246 @@ type Room struct {
 
 	// Configuration for the next new game.
 	Rows, Cols int
+	NumBoards  int
+	Bombs      int // On each board.
+
+	// Version increases with every change to the room. It's capped at
+	// MaxVersion; see RolloverVersion.
+	Version int64
+
+	// Games counts the games started in the room.
+	Games int
+
+	// Tracker is set for rooms without boards; see NewTrackerRoom. Tracker
+	// games have Scores instead of boards.
+	Tracker bool
+	Scores  []int
+
+	// ClueLog is the clues given in the current game, oldest first.
+	ClueLog []*LoggedClue
+
+	// Boards are the boards in play. Most games have one; a gauntlet has
+	// more, and is won by the first team to clear any of them. Board is
+	// always the first.
+	Boards []*Board
+	Board  *Board
+
+	// TurnBoard is the board the current turn is being played on, once the
+	// turn's clue or first reveal has chosen it. It's only set when there's
+	// more than one board.
+	TurnBoard *int
+
+	// Seed is drawn at the start of each game, so that games with the same
+	// key can be told apart.
+	Seed int
 
-	Version   int
-	Board     *Board
 	Turn      Team
 	Winner    *Team
 	Players   map[PlayerID]*Player
 	Teams     [][]PlayerID // To preserve the ordering of teams.
 	WordLists []*WordList
+
+	// Clue is the clue given for the current turn, or nil if none has been given.
+	Clue *Clue
+
+	// BoundClues requires clue numbers to not exceed the number of words the
+	// spymaster's team has left to find.
+	BoundClues bool
+
+	// EnforceGuessLimit ends a turn once its team has made one more guess
+	// than the clue's number. Zero and unlimited clues allow any number of
+	// guesses, as does a turn without a clue.
+	EnforceGuessLimit bool
+
+	// SuggestClues offers spymasters suggested clues. Only the host may change
+	// it.
+	SuggestClues bool
+
+	// BackfillWords lets games start when the enabled packs are short of
+	// words, drawing the rest from the base pack of the room's language.
+	// Without it, the game can't be started until more packs are enabled.
+	BackfillWords bool
+
+	// Language is the language of the room's built-in packs, whose first pack
+	// is the base pack backfill draws from. If empty, it's the default
+	// language.
+	Language string
+
+	// Backfill is what the current game's boards drew from the base pack, or
+	// nil if they drew nothing.
+	Backfill *Backfill
+
+	// Host is the player in charge of the room. When the host leaves, the
+	// longest present player becomes the host.
+	Host PlayerID
+
+	// PackBudget limits the custom packs the room may hold.
+	PackBudget PackBudget
+
+	// Fit decides the display size of the words on new boards.
+	Fit fit.Thresholds
+
+	// PenaltyLimit is the number of turns a team may let expire without a
+	// reveal before forfeiting. Zero disables penalties.
+	PenaltyLimit int
+
+	// Penalties counts each team's expired turns in the current game.
+	Penalties []int
+
+	// Forfeit is set when the winner won because the other team reached the
+	// penalty limit.
+	Forfeit bool
+
+	// Wins counts the games each team has won in the room. It's kept across
+	// games, whoever comes and goes, until the host resets it.
+	Wins []int
+
+	// SpymasterLimit is the most spymasters each team may have. Zero is
+	// unlimited.
+	SpymasterLimit int
+
+	// ConfirmClues requires every spymaster on a team to give the same clue
+	// before it's accepted. Until they have, it's the PendingClue.
+	ConfirmClues bool
+
+	// PendingClue is a clue waiting on confirmation from the rest of the
+	// team's spymasters, or nil.
+	PendingClue *PendingClue
+
+	// MinSpymasters and MinGuessers are the fewest spymasters and guessers
+	// each team needs before a new game may be started, unless it's forced.
+	MinSpymasters int
+	MinGuessers   int
+
+	// MinRevealPlayers is the fewest players the room needs before the first
+	// tile of a game may be revealed, unless it's forced. Zero disables the
+	// check.
+	MinRevealPlayers int
+
+	// TeamConstraints are pairs of players RandomizeTeams keeps together or
+	// apart. They're kept across games until changed.
+	TeamConstraints []TeamConstraint
+
+	// HintBudget is the number of hints each team gets in each new game. A
+	// hint reveals a random neutral tile for a team that needs a handicap.
+	HintBudget []int
+
+	// Hints are the hints each team has left in the current game, and
+	// HintsUsed the hints each has used.
+	Hints     []int
+	HintsUsed []int
+
+	revealedThisTurn bool
+	revealedThisGame bool
+
+	// guesses counts the reveals made since the current turn's clue.
+	guesses int
+
+	// turns counts the current game's turns, the current one included.
+	turns int
+
+	// lastReveal is the reveal Undo would take back, or nil.
+	lastReveal *revealUndo
+
+	joined int
+}
+
+// PackBudget is a limit on a room's custom packs. Zero fields are unlimited.
+type PackBudget struct {
+	Packs int // Number of custom packs.
+	Bytes int // Total length of all words in custom packs.
+}
+
+// Backfill is the tiles a game's boards drew from the base pack, as the
+// enabled packs were short of words.
+type Backfill struct {
+	Pack     string
+	Language string
+	Count    int
+}
+
+// ClueUnlimited is the clue number for an "unlimited" clue.
+const ClueUnlimited = -1
+
+type Clue struct {
+	Board int
+	Word  string
+	Count int
+}
+
+// MaxClueLog is the most clues a game keeps; older clues are dropped.
+const MaxClueLog = 100
+
+// LoggedClue is a clue given in the current game, and the team it was given
+// to.
+type LoggedClue struct {
+	Team Team
+	Clue
+}
+
+// MaxVersion is the highest room version. Versions are sent to clients as
+// JSON numbers, which JavaScript only represents exactly up to 2^53-1.
+const MaxVersion = 1<<53 - 1
+
+// RolloverVersion restarts the version once it's passed MaxVersion, returning
+// true if it did. Clients can't tell a restarted version apart from an old
+// one, so after a rollover, everyone must be sent the full state.
+func (r *Room) RolloverVersion() bool {
+	if r.Version <= MaxVersion {
+		return false
+	}
+	r.Version = 1
+	return true
+}
+
+// MaxBoards is the most boards a gauntlet may have.
+const MaxBoards = 3
+
+// MaxBombs is the most bombs a board may have.
+const MaxBombs = 3
+
+// MaxSpymasters is the highest per-team spymaster limit.
+const MaxSpymasters = 3
+
+// PendingClue is a clue which some of a team's spymasters have given.
+type PendingClue struct {
+	Clue
+	Confirmed []PlayerID // In the order they gave the clue.
 }
 
+func (pc *PendingClue) confirmedBy(id PlayerID) bool {
+	for _, c := range pc.Confirmed {
+		if c == id {
+			return true
+		}
+	}
+	return false
+}
+
+const defaultRows, defaultCols = 5, 5
+
 func NewRoom(rand Rand) *Room {
 	if rand == nil {
-		rand = globalRand{}
+		rand = NewRand(0)
 	}
 
-	return &Room{
+	r := &Room{
 		rand:      rand,
-		Rows:      5,
-		Cols:      5,
+		Rows:      defaultRows,
+		Cols:      defaultCols,
+		NumBoards: 1,
+		Bombs:     1,
 		Players:   make(map[PlayerID]*Player),
 		Teams:     make([][]PlayerID, 2), // TODO: support more than 2 teams
 		WordLists: defaultWords(),
+
+		MinSpymasters: 1,
+		MinGuessers:   1,
+
+		Fit: fit.DefaultThresholds,
 	}
+	r.Wins = make([]int, len(r.Teams))
+	return r
 }
 
 type Player struct {
@@ -68,31 +297,134 @@ type Player struct {
 	Nickname  string
 	Team      Team
 	Spymaster bool
+
+	// Color is an index into the client's palette of NumColors colors. It's
+	// assigned once when the player joins and never changes afterward.
+	Color int
+
+	// Impersonator is set when the player's nickname looks like the host's.
+	// New nicknames like this are rejected, but a player can end up in this
+	// state when the host changes.
+	Impersonator bool
+
+	// Disconnected is set while the player's seat is held for them to
+	// reconnect to. Nothing waits on a disconnected player.
+	Disconnected bool
+
+	joined int
 }
 
+// NumColors is the size of the client's player color palette.
+const NumColors = 12
+
 func (r *Room) AddPlayer(id PlayerID, nickname string) {
+	r.AddPlayerOnTeam(id, nickname, -1)
+}
+
+// AddPlayerOnTeam is like AddPlayer, but a new player joins the given team
+// instead of the smallest one, if the team exists.
+func (r *Room) AddPlayerOnTeam(id PlayerID, nickname string, team Team) {
 	if p, ok := r.Players[id]; ok {
 		if p.Nickname == nickname {
 			return
 		}
 
 		p.Nickname = nickname
+		if id == r.Host {
+			r.flagImpersonators()
+		} else {
+			p.Impersonator = r.impersonatesHost(id, nickname)
+		}
 		r.Version++
 		return
 	}
 
-	team := r.smallestTeam()
+	r.joined++
+
+	if team < 0 || int(team) >= len(r.Teams) {
+		team = r.smallestTeam()
+	}
 	p := &Player{
 		ID:       id,
 		Nickname: nickname,
 		Team:     team,
+		Color:    r.pickColor(team),
+		joined:   r.joined,
 	}
 
 	r.Players[id] = p
 	r.Teams[team] = append(r.Teams[team], id)
+
+	if r.Host == "" {
+		r.Host = id
+	} else {
+		p.Impersonator = r.impersonatesHost(id, nickname)
+	}
+
 	r.Version++
 }
 
+// NicknameAllowed checks that a player may use the nickname, i.e. that they
+// aren't attempting to impersonate the host.
+func (r *Room) NicknameAllowed(id PlayerID, nickname string) error {
+	if r.impersonatesHost(id, nickname) {
+		return &Error{
+			Code:    "nicknameTaken",
+			Message: "Nickname is too similar to the host's.",
+		}
+	}
+	return nil
+}
+
+func (r *Room) ChangeNickname(id PlayerID, nickname string) error {
+	if r.Players[id] == nil {
+		return nil
+	}
+
+	if err := r.NicknameAllowed(id, nickname); err != nil {
+		return err
+	}
+
+	r.AddPlayer(id, nickname)
+	return nil
+}
+
+func (r *Room) impersonatesHost(id PlayerID, nickname string) bool {
+	if id == r.Host {
+		return false
+	}
+
+	host := r.Players[r.Host]
+	if host == nil {
+		return false
+	}
+
+	return names.Confusable(host.Nickname, nickname)
+}
+
+func (r *Room) flagImpersonators() {
+	for id, p := range r.Players {
+		p.Impersonator = r.impersonatesHost(id, p.Nickname)
+	}
+}
+
+func (r *Room) migrateHost() {
+	r.Host = ""
+
+	var next *Player
+	for _, p := range r.Players {
+		if next == nil || p.joined < next.joined {
+			next = p
+		}
+	}
+
+	if next != nil {
+		r.Host = next.ID
+	}
+
+	r.flagImpersonators()
+}
+
 func (r *Room) smallestTeam() Team {
 	min := Team(0)
 	minLen := len(r.Teams[0])
@@ -107,6 +439,40 @@ func (r *Room) smallestTeam() Team {
 	return min
 }
 
+// pickColor picks the lowest unused color which isn't next to any color
+// already on the team in the palette, so teammates look distinct. If no such
+// color exists, the lowest unused color is picked, and if all colors are in
+// use, the least used color is shared.
+func (r *Room) pickColor(team Team) int {
+	used := make([]int, NumColors)
+	teamUsed := make([]bool, NumColors)
+
+	for _, p := range r.Players {
+		used[p.Color]++
+		if p.Team == team {
+			teamUsed[p.Color] = true
+		}
+	}
+
+	adjacent := func(c int) bool {
+		return teamUsed[(c+1)%NumColors] || teamUsed[(c+NumColors-1)%NumColors]
+	}
+
+	for c := 0; c < NumColors; c++ {
+		if used[c] == 0 && !adjacent(c) {
+			return c
+		}
+	}
+
+	best := 0
+	for c := 1; c < NumColors; c++ {
+		if used[c] < used[best] {
+			best = c
+		}
+	}
+	return best
+}
+
 func (r *Room) words() (list words.List) {
 	for _, w := range r.WordLists {
 		if w.Enabled {
@@ -116,16 +482,132 @@ func (r *Room) words() (list words.List) {
 	return list
 }
 
-func (r *Room) NewGame() {
-	words := r.words()
+// CheckWords returns an error if the enabled packs don't have enough words to
+// deal a board. Packs may be disabled after the boards are configured, so this
+// must be checked before each new game.
+func (r *Room) CheckWords() error {
+	if r.Tracker {
+		return nil
+	}
 
-	if r.Rows*r.Cols > words.Len() {
-		panic("not enough words")
+	if need, have := r.Rows*r.Cols, r.wordCount(); need > have {
+		return &Error{
+			Code:    "tooFewWords",
+			Message: fmt.Sprintf("%s, but %dx%d boards need %d.", r.describeWords(have), r.Rows, r.Cols, need),
+			Limit:   &need,
+		}
 	}
 
+	return nil
+}
+
+// wordCount returns the number of words boards may be dealt from: those of the
+// enabled packs, and those backfill would add.
+func (r *Room) wordCount() int {
+	list := r.words()
+	_, backfill := r.backfillWords(list)
+	return list.Len() + len(backfill)
+}
+
+// describeWords describes the number of words boards may be dealt from, for
+// errors.
+func (r *Room) describeWords(have int) string {
+	if r.BackfillWords {
+		return fmt.Sprintf("The enabled packs and backfill have %d words", have)
+	}
+	return fmt.Sprintf("The enabled packs have %d words", have)
+}
+
+// backfillWords returns the base pack, the first built-in pack in the room's
+// language, and those of its words which aren't in list. If BackfillWords is
+// off or the base pack is enabled, there's nothing to backfill.
+func (r *Room) backfillWords(list words.List) (*WordList, []string) {
+	if !r.BackfillWords {
+		return nil, nil
+	}
+
+	language := r.Language
+	if language == "" {
+		language = static.DefaultLanguage
+	}
+
+	var base *WordList
+	for _, w := range r.WordLists {
+		if !w.Custom && w.Language == language {
+			base = w
+			break
+		}
+	}
+
+	if base == nil || base.Enabled {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool, list.Len()+base.List.Len())
+	for i := 0; i < list.Len(); i++ {
+		seen[list.Get(i)] = true
+	}
+
+	var backfill []string
+	for i := 0; i < base.List.Len(); i++ {
+		if w := base.List.Get(i); !seen[w] {
+			seen[w] = true
+			backfill = append(backfill, w)
+		}
+	}
+	return base, backfill
+}
+
+// drawBackfill adds words from the base pack to list until it has need words,
+// or the base pack has no more. Like the boards, it draws them at random
+// without replacement. It returns the new list and the words it drew.
+func (r *Room) drawBackfill(list words.List, need int) (words.List, *WordList, []string) {
+	if list.Len() >= need {
+		return list, nil, nil
+	}
+
+	base, backfill := r.backfillWords(list)
+	if len(backfill) == 0 {
+		return list, nil, nil
+	}
+
+	n := need - list.Len()
+	if n > len(backfill) {
+		n = len(backfill)
+	}
+
+	for i := 0; i < n; i++ {
+		j := i + r.rand.Intn(len(backfill)-i)
+		backfill[i], backfill[j] = backfill[j], backfill[i]
+	}
+	backfill = backfill[:n]
+
+	return list.Concat(words.NewList(backfill)), base, backfill
+}
+
+func (r *Room) NewGame() {
 	r.Winner = nil
+	r.Clue = nil
+	r.PendingClue = nil
+	r.Penalties = make([]int, len(r.Teams))
+	r.Forfeit = false
+	r.resetHints()
+	r.revealedThisTurn = false
+	r.revealedThisGame = false
+	r.guesses = 0
+	r.turns = 1
+	r.forgetReveal()
 	r.Turn = Team(r.rand.Intn(len(r.Teams)))
-	r.Board = newBoard(r.Rows, r.Cols, words, r.Turn, len(r.Teams), r.rand)
+	r.TurnBoard = nil
+	r.Games++
+
+	r.ClueLog = nil
+	if r.Tracker {
+		r.Scores = make([]int, len(r.Teams))
+	} else {
+		r.newBoards()
+	}
+	r.Seed = r.rand.Intn(math.MaxInt32)
 
 	for _, p := range r.Players {
 		p.Spymaster = false
@@ -134,6 +616,63 @@ func (r *Room) NewGame() {
 	r.Version++
 }
 
+// newBoards deals the boards for a new game.
+func (r *Room) newBoards() {
+	words, base, drawn := r.drawBackfill(r.words(), r.NumBoards*r.Rows*r.Cols)
+
+	if r.Rows*r.Cols > words.Len() {
+		panic("not enough words")
+	}
+
+	// The packs may have changed since the boards were configured; play as
+	// many boards as there are words for.
+	numBoards := r.NumBoards
+	for numBoards > 1 && numBoards*r.Rows*r.Cols > words.Len() {
+		numBoards--
+	}
+
+	if numBoards == 1 {
+		r.Boards = []*Board{NewBoard(r.Rows, r.Cols, r.Bombs, words, r.Turn, len(r.Teams), r.rand)}
+	} else {
+		seen := make(map[int]struct{}, numBoards*r.Rows*r.Cols)
+		r.Boards = make([]*Board, numBoards)
+		for i := range r.Boards {
+			r.Boards[i] = newBoard(r.Rows, r.Cols, r.Bombs, words, r.Turn, len(r.Teams), r.rand, seen)
+		}
+	}
+	for _, b := range r.Boards {
+		b.measure(r.Fit)
+	}
+	r.Board = r.Boards[0]
+
+	r.Backfill = nil
+	if len(drawn) != 0 {
+		r.Backfill = &Backfill{
+			Pack:     base.Name,
+			Language: base.Language,
+			Count:    countTiles(r.Boards, drawn),
+		}
+	}
+}
+
+// countTiles counts the tiles on the boards with any of the words.
+func countTiles(boards []*Board, wds []string) int {
+	want := make(map[string]bool, len(wds))
+	for _, w := range wds {
+		want[w] = true
+	}
+
+	count := 0
+	for _, b := range boards {
+		for _, tile := range b.tiles {
+			if want[tile.Word] {
+				count++
+			}
+		}
+	}
+	return count
+}
+
 func (r *Room) EndTurn(id PlayerID) {
 	if r.Winner != nil {
 		return
@@ -157,13 +696,100 @@ func (r *Room) nextTeam() Team {
 
 func (r *Room) nextTurn() {
 	r.Turn = r.nextTeam()
+	r.Clue = nil
+	r.PendingClue = nil
+	r.TurnBoard = nil
+	r.revealedThisTurn = false
+	r.guesses = 0
+	r.turns++
+}
+
+// Turns returns the number of turns in the current game, the current one
+// included.
+func (r *Room) Turns() int {
+	return r.turns
+}
+
+// chooseBoard sets the board the current turn is played on.
+func (r *Room) chooseBoard(board int) {
+	if len(r.Boards) > 1 && r.TurnBoard == nil {
+		r.TurnBoard = &board
+	}
+}
+
+// turnAllows returns true if the current turn may be played on the board.
+func (r *Room) turnAllows(board int) bool {
+	return r.TurnBoard == nil || *r.TurnBoard == board
+}
+
+func (r *Room) board(i int) *Board {
+	if i < 0 || i >= len(r.Boards) {
+		return nil
+	}
+	return r.Boards[i]
+}
+
+// win ends the game, and counts it for the winner.
+func (r *Room) win(team Team) {
+	r.Winner = &team
+	r.Wins[team]++
+}
+
+// ResetWins clears the room's tally of wins. Only the host may reset it.
+func (r *Room) ResetWins(id PlayerID) {
+	if id != r.Host {
+		return
+	}
+
+	reset := false
+	for team, wins := range r.Wins {
+		if wins != 0 {
+			r.Wins[team] = 0
+			reset = true
+		}
+	}
+	if reset {
+		r.Version++
+	}
 }
 
 func (r *Room) ForceEndTurn() {
 	r.Version++
+	r.forgetReveal()
 	r.nextTurn()
 }
 
+// ExpireTurn ends a turn whose time ran out. If penalties are enabled and the
+// team didn't reveal anything, they're penalized, and forfeit once they reach
+// the limit.
+func (r *Room) ExpireTurn() {
+	if r.Winner != nil {
+		return
+	}
+
+	if r.PenaltyLimit > 0 && !r.revealedThisTurn {
+		r.Penalties[r.Turn]++
+		if r.Penalties[r.Turn] >= r.PenaltyLimit {
+			r.win(r.nextTeam())
+			r.Forfeit = true
+			r.forgetReveal()
+			r.Version++
+			return
+		}
+	}
+
+	r.ForceEndTurn()
+}
+
+func (r *Room) ChangePenaltyLimit(limit int) {
+	if limit < 0 || r.PenaltyLimit == limit {
+		return
+	}
+
+	r.PenaltyLimit = limit
+	r.Version++
+}
+
 func (r *Room) RemovePlayer(id PlayerID) {
 	p := r.Players[id]
 	if p == nil {
@@ -174,9 +800,30 @@ func (r *Room) RemovePlayer(id PlayerID) {
 	delete(r.Players, id)
 
 	r.Teams[p.Team] = removePlayer(r.Teams[p.Team], id)
+
+	if id == r.Host {
+		r.migrateHost()
+	}
+
+	r.settlePendingClue()
 }
 
-func (r *Room) Reveal(id PlayerID, row, col int) {
+// SetDisconnected marks a player as disconnected, or as back again. A pending
+// clue waiting only on spymasters who've disconnected is given.
+func (r *Room) SetDisconnected(id PlayerID, disconnected bool) {
+	p := r.Players[id]
+	if p == nil || p.Disconnected == disconnected {
+		return
+	}
+
+	p.Disconnected = disconnected
+	r.Version++
+	r.settlePendingClue()
+}
+
+// Reveal reveals a tile on one of the boards. Once a turn's board has been
+// chosen, only that board may be played.
+func (r *Room) Reveal(id PlayerID, board, row, col int) {
 	if r.Winner != nil {
 		return
 	}
@@ -190,7 +837,12 @@ func (r *Room) Reveal(id PlayerID, row, col int) {
 		return
 	}
 
-	tile := r.Board.Get(row, col)
+	b := r.board(board)
+	if b == nil || !r.turnAllows(board) {
+		return
+	}
+
+	tile := b.Get(row, col)
 	if tile == nil {
 		return
 	}
@@ -199,7 +851,12 @@ func (r *Room) Reveal(id PlayerID, row, col int) {
 		return
 	}
 
+	r.saveReveal(board, row, col)
 	tile.Revealed = true
+	r.revealedThisTurn = true
+	r.revealedThisGame = true
+	r.guesses++
+	r.chooseBoard(board)
 
 	switch {
 	case tile.Neutral:
@@ -207,14 +864,12 @@ func (r *Room) Reveal(id PlayerID, row, col int) {
 	case tile.Bomb:
 		// TODO: Who wins when there's more than one team?
 		// Maybe eliminate the team who clicked?
-		winner := r.nextTeam()
-		r.Winner = &winner
+		r.win(r.nextTeam())
 	default:
-		r.Board.WordCounts[tile.Team]--
-		if r.Board.WordCounts[tile.Team] == 0 {
-			winner := tile.Team
-			r.Winner = &winner
-		} else if tile.Team != p.Team {
+		b.WordCounts[tile.Team]--
+		if b.WordCounts[tile.Team] == 0 {
+			r.win(tile.Team)
+		} else if left, ok := r.GuessesLeft(); tile.Team != p.Team || (ok && left == 0) {
 			r.nextTurn()
 		}
 	}
@@ -222,22 +877,330 @@ func (r *Room) Reveal(id PlayerID, row, col int) {
 	r.Version++
 }
 
-func (r *Room) ChangeRole(id PlayerID, spymaster bool) {
-	if r.Winner != nil {
-		return
+// GuessesLeft returns the number of guesses the team whose turn it is has
+// left on the clue, if the room enforces a limit and the clue has one.
+func (r *Room) GuessesLeft() (int, bool) {
+	if !r.EnforceGuessLimit || r.Tracker || r.Clue == nil || r.Clue.Count <= 0 {
+		return 0, false
+	}
+
+	left := r.Clue.Count + 1 - r.guesses
+	if left < 0 {
+		left = 0
+	}
+	return left, true
+}
+
+// GiveClue gives a clue for one of the boards, which chooses the board the
+// turn is played on. Tracker games ignore the board.
+func (r *Room) GiveClue(id PlayerID, board int, word string, count int) error {
+	if r.Winner != nil || r.Clue != nil {
+		return nil
 	}
 
 	p := r.Players[id]
 	if p == nil {
+		return nil
+	}
+
+	if !p.Spymaster || p.Team != r.Turn {
+		return nil
+	}
+
+	if word == "" || count < ClueUnlimited {
+		return nil
+	}
+
+	if r.Tracker {
+		board = 0
+	} else {
+		b := r.board(board)
+		if b == nil || !r.turnAllows(board) {
+			return nil
+		}
+
+		// Zero and unlimited are always allowed.
+		if max := b.WordCounts[p.Team]; r.BoundClues && count > max {
+			return &Error{
+				Code:    "clueTooHigh",
+				Message: fmt.Sprintf("Clue number may not exceed %d.", max),
+				Limit:   &max,
+			}
+		}
+
+		if w, ok := r.unrevealedWord(word); ok {
+			return &Error{
+				Code:    "clueOnBoard",
+				Message: fmt.Sprintf("%q is a word on the board.", w),
+			}
+		}
+	}
+
+	clue := Clue{
+		Board: board,
+		Word:  word,
+		Count: count,
+	}
+	r.Version++
+	r.forgetReveal()
+
+	if !r.ConfirmClues {
+		r.giveClue(clue)
+		return nil
+	}
+
+	if pc := r.PendingClue; pc != nil && pc.Clue == clue {
+		if !pc.confirmedBy(id) {
+			pc.Confirmed = append(pc.Confirmed, id)
+		}
+	} else {
+		// A different clue replaces the pending one; the others must confirm
+		// again.
+		r.PendingClue = &PendingClue{Clue: clue, Confirmed: []PlayerID{id}}
+	}
+
+	r.settlePendingClue()
+	return nil
+}
+
+// settlePendingClue gives the pending clue once every spymaster of the team
+// whose turn it is has confirmed it, but for those who've disconnected. If
+// none of the players who confirmed it are still spymasters of that team,
+// it's dropped.
+func (r *Room) settlePendingClue() {
+	pc := r.PendingClue
+	if pc == nil {
 		return
 	}
 
-	if p.Spymaster == spymaster {
+	confirmed, waiting := 0, 0
+	for _, id := range r.spymasters(r.Turn) {
+		switch {
+		case pc.confirmedBy(id):
+			confirmed++
+		case !r.Players[id].Disconnected:
+			waiting++
+		}
+	}
+
+	switch {
+	case confirmed == 0:
+		r.PendingClue = nil
+		r.forgetReveal()
+	case waiting == 0:
+		r.giveClue(pc.Clue)
+		r.forgetReveal()
+	}
+}
+
+// giveClue accepts a pending clue.
+func (r *Room) giveClue(clue Clue) {
+	r.Clue = &clue
+	r.PendingClue = nil
+	r.guesses = 0
+	r.chooseBoard(clue.Board)
+	r.logClue(r.Turn, clue)
+}
+
+func (r *Room) logClue(team Team, clue Clue) {
+	if len(r.ClueLog) >= MaxClueLog {
+		copy(r.ClueLog, r.ClueLog[1:])
+		r.ClueLog = r.ClueLog[:len(r.ClueLog)-1]
+	}
+
+	r.ClueLog = append(r.ClueLog, &LoggedClue{Team: team, Clue: clue})
+}
+
+// unrevealedWord returns the unrevealed word on any board in play which is
+// the given word, ignoring case.
+func (r *Room) unrevealedWord(word string) (string, bool) {
+	for _, b := range r.Boards {
+		for _, tile := range b.tiles {
+			if !tile.Revealed && strings.EqualFold(tile.Word, word) {
+				return tile.Word, true
+			}
+		}
+	}
+	return "", false
+}
+
+// spymasters returns a team's spymasters in team order.
+func (r *Room) spymasters(team Team) []PlayerID {
+	var ids []PlayerID
+	for _, id := range r.Teams[team] {
+		if r.Players[id].Spymaster {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// enforceSpymasterLimit demotes spymasters past the limit, latest in team
+// order first.
+func (r *Room) enforceSpymasterLimit() {
+	if r.SpymasterLimit == 0 {
 		return
 	}
 
+	for team := range r.Teams {
+		spymasters := r.spymasters(Team(team))
+		if len(spymasters) <= r.SpymasterLimit {
+			continue
+		}
+		for _, id := range spymasters[r.SpymasterLimit:] {
+			r.Players[id].Spymaster = false
+		}
+	}
+}
+
+// ChangeSpymasters sets the per-team spymaster limit and whether clues must
+// be confirmed by all spymasters. Lowering the limit demotes the spymasters
+// past it.
+func (r *Room) ChangeSpymasters(limit int, confirm bool) {
+	if r.Winner != nil || limit < 0 || limit > MaxSpymasters {
+		return
+	}
+
+	if r.SpymasterLimit == limit && r.ConfirmClues == confirm {
+		return
+	}
+
+	r.SpymasterLimit = limit
+	r.ConfirmClues = confirm
+	r.enforceSpymasterLimit()
+
+	if confirm {
+		r.settlePendingClue()
+	} else if pc := r.PendingClue; pc != nil {
+		// Without confirmation, the first spymaster's clue would have stood.
+		r.giveClue(pc.Clue)
+	}
+
+	r.Version++
+}
+
+// CheckBoards returns an error if games can't be played with the given number
+// and size of boards and number of bombs on each, including if the enabled
+// packs don't have enough words.
+func (r *Room) CheckBoards(count, rows, cols, bombs int) error {
+	if count < 1 || count > MaxBoards {
+		return &Error{
+			Code:    "invalidBoards",
+			Message: fmt.Sprintf("Games may have between 1 and %d boards.", MaxBoards),
+		}
+	}
+
+	if !ValidBoardSize(rows, cols, len(r.Teams)) {
+		return &Error{
+			Code:    "invalidBoards",
+			Message: fmt.Sprintf("Boards can't be %dx%d.", rows, cols),
+		}
+	}
+
+	if bombs < 0 || bombs > MaxBombs {
+		return &Error{
+			Code:    "invalidBoards",
+			Message: fmt.Sprintf("Boards may have between 0 and %d bombs.", MaxBombs),
+		}
+	}
+
+	if _, ok := newLayout(rows*cols, len(r.Teams), bombs); !ok {
+		return &Error{
+			Code:    "invalidBoards",
+			Message: fmt.Sprintf("%dx%d boards don't have room for %d bombs.", rows, cols, bombs),
+		}
+	}
+
+	if need, have := count*rows*cols, r.wordCount(); need > have {
+		return &Error{
+			Code:    "invalidBoards",
+			Message: fmt.Sprintf("%s, but the boards need %d.", r.describeWords(have), need),
+		}
+	}
+
+	return nil
+}
+
+// ChangeBoards configures the boards for the next new game.
+func (r *Room) ChangeBoards(count, rows, cols, bombs int) error {
+	if err := r.CheckBoards(count, rows, cols, bombs); err != nil {
+		return err
+	}
+
+	if r.NumBoards == count && r.Rows == rows && r.Cols == cols && r.Bombs == bombs {
+		return nil
+	}
+
+	r.NumBoards = count
+	r.Rows = rows
+	r.Cols = cols
+	r.Bombs = bombs
+	r.Version++
+	return nil
+}
+
+func (r *Room) ChangeBoundClues(bound bool) {
+	if r.BoundClues == bound {
+		return
+	}
+
+	r.BoundClues = bound
+	r.Version++
+}
+
+func (r *Room) ChangeBackfillWords(backfill bool) {
+	if r.BackfillWords == backfill {
+		return
+	}
+
+	r.BackfillWords = backfill
+	r.Version++
+}
+
+func (r *Room) ChangeEnforceGuessLimit(enforce bool) {
+	if r.EnforceGuessLimit == enforce {
+		return
+	}
+
+	r.EnforceGuessLimit = enforce
+	r.Version++
+}
+
+func (r *Room) ChangeSuggestClues(id PlayerID, suggest bool) {
+	if id != r.Host || r.SuggestClues == suggest {
+		return
+	}
+
+	r.SuggestClues = suggest
+	r.Version++
+}
+
+func (r *Room) ChangeRole(id PlayerID, spymaster bool) error {
+	if r.Winner != nil {
+		return nil
+	}
+
+	p := r.Players[id]
+	if p == nil {
+		return nil
+	}
+
+	if p.Spymaster == spymaster {
+		return nil
+	}
+
+	if max := r.SpymasterLimit; spymaster && max > 0 && len(r.spymasters(p.Team)) >= max {
+		return &Error{
+			Code:    "spymasterLimit",
+			Message: fmt.Sprintf("Teams may have at most %d spymasters.", max),
+			Limit:   &max,
+		}
+	}
+
 	p.Spymaster = spymaster
+	r.settlePendingClue()
 	r.Version++
+	return nil
 }
 
 func (r *Room) ChangeTeam(id PlayerID, team Team) {
@@ -257,6 +1220,8 @@ func (r *Room) ChangeTeam(id PlayerID, team Team) {
 	r.Teams[p.Team] = removePlayer(r.Teams[p.Team], id)
 	r.Teams[team] = append(r.Teams[team], id)
 	p.Team = team
+	r.enforceSpymasterLimit()
+	r.settlePendingClue()
 	r.Version++
 }
 
@@ -270,25 +1235,48 @@ func removePlayer(team []PlayerID, remove PlayerID) []PlayerID {
 	return newTeam
 }
 
+// RandomizeTeams shuffles the players onto teams as evenly as the room's
+// TeamConstraints allow.
 func (r *Room) RandomizeTeams() {
 	players := make([]PlayerID, 0, len(r.Players))
 	for id := range r.Players {
 		players = append(players, id)
 	}
+	sort.Strings(players) // Map order isn't seeded.
 
 	r.rand.Shuffle(len(players), func(i, j int) {
 		players[i], players[j] = players[j], players[i]
 	})
 
+	// Groups of constrained players are placed first, the most lopsided
+	// first, so that everyone else can even out the teams.
+	groups := r.constraintGroups(players)
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].imbalance() > groups[j].imbalance()
+	})
+
 	numTeams := len(r.Teams)
 	newTeams := make([][]PlayerID, numTeams)
 	for i := range newTeams {
-		newTeams[i] = make([]PlayerID, 0, len(players)/numTeams)
+		newTeams[i] = make([]PlayerID, 0, len(players)/numTeams+1)
 	}
 
-	for i, id := range players {
-		team := i % numTeams
-		newTeams[team] = append(newTeams[team], id)
+	for _, g := range groups {
+		big, small := g.sides[0], g.sides[1]
+		if len(big) < len(small) {
+			big, small = small, big
+		}
+
+		team := 0
+		for t := range newTeams {
+			if len(newTeams[t]) < len(newTeams[team]) {
+				team = t
+			}
+		}
+		other := (team + 1) % numTeams
+
+		newTeams[team] = append(newTeams[team], big...)
+		newTeams[other] = append(newTeams[other], small...)
 	}
 
 	r.rand.Shuffle(numTeams, func(i, j int) {
@@ -302,6 +1290,8 @@ func (r *Room) RandomizeTeams() {
 	}
 
 	r.Teams = newTeams
+	r.enforceSpymasterLimit()
+	r.settlePendingClue()
 	r.Version++
 }
 
@@ -333,9 +1323,36 @@ func (r *Room) ChangePack(num int, enable bool) {
 	r.Version++
 }
 
-func (r *Room) AddPack(name string, wds []string) {
-	if len(r.WordLists) >= 10 {
-		return
+// MaxPacks is the most packs, built-in and custom, a room may hold.
+const MaxPacks = 13
+
+// AddPack adds a custom pack, disabled, returning an error if the room has
+// too many packs or it wouldn't fit in the room's pack budget.
+func (r *Room) AddPack(name string, wds []string) error {
+	if max := MaxPacks; len(r.WordLists) >= max {
+		return &Error{
+			Code:    "packLimit",
+			Message: fmt.Sprintf("Rooms may have at most %d packs.", max),
+			Limit:   &max,
+		}
+	}
+
+	packs, size := r.customPackUsage()
+
+	if max := r.PackBudget.Packs; max > 0 && packs+1 > max {
+		return &Error{
+			Code:    "packLimit",
+			Message: fmt.Sprintf("Rooms may have at most %d custom packs.", max),
+			Limit:   &max,
+		}
+	}
+
+	if max := r.PackBudget.Bytes; max > 0 && size+wordsSize(wds) > max {
+		return &Error{
+			Code:    "packSizeLimit",
+			Message: fmt.Sprintf("Custom packs may not total more than %d bytes.", max),
+			Limit:   &max,
+		}
 	}
 
 	list := &WordList{
@@ -345,15 +1362,104 @@ func (r *Room) AddPack(name string, wds []string) {
 	}
 	r.WordLists = append(r.WordLists, list)
 	r.Version++
+	return nil
+}
+
+// customPackUsage returns the number and total size of the custom packs.
+func (r *Room) customPackUsage() (packs, size int) {
+	for _, p := range r.WordLists {
+		if !p.Custom {
+			continue
+		}
+		packs++
+		for i := 0; i < p.List.Len(); i++ {
+			size += len(p.List.Get(i))
+		}
+	}
+	return packs, size
 }
 
-func (r *Room) RemovePack(num int) {
+// MaxWordLength is the longest word, in characters, allowed in a custom word
+// list given with CleanWords.
+const MaxWordLength = 30
+
+// CleanWords prepares a custom word list for a new room: words are trimmed,
+// blank ones dropped, and duplicates removed ignoring case. It returns an
+// error describing the first problem found if a word is too long, or if there
+// aren't enough words left to fill a default board.
+func CleanWords(wds []string) ([]string, error) {
+	cleaned := make([]string, 0, len(wds))
+	seen := make(map[string]bool, len(wds))
+	duplicates := 0
+
+	for _, w := range wds {
+		w = strings.TrimSpace(w)
+		if w == "" {
+			continue
+		}
+
+		if utf8.RuneCountInString(w) > MaxWordLength {
+			max := MaxWordLength
+			return nil, &Error{
+				Code:    "wordTooLong",
+				Message: fmt.Sprintf("%q is longer than %d characters.", w, max),
+				Limit:   &max,
+			}
+		}
+
+		key := strings.ToUpper(w)
+		if seen[key] {
+			duplicates++
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, w)
+	}
+
+	if min := defaultRows * defaultCols; len(cleaned) < min {
+		msg := fmt.Sprintf("Custom word lists need at least %d different words, but only %d were given", min, len(cleaned))
+		if duplicates > 0 {
+			msg += fmt.Sprintf(" after removing %d duplicates", duplicates)
+		}
+		return nil, &Error{
+			Code:    "tooFewWords",
+			Message: msg + ".",
+			Limit:   &min,
+		}
+	}
+
+	return cleaned, nil
+}
+
+func wordsSize(wds []string) int {
+	size := 0
+	for _, w := range wds {
+		size += len(w)
+	}
+	return size
+}
+
+// RemovePack removes a custom pack. An enabled pack may only be removed if
+// the packs left enabled still have the words for a board.
+func (r *Room) RemovePack(num int) error {
 	if num < 0 || num >= len(r.WordLists) {
-		return
+		return nil
 	}
 
-	if pack := r.WordLists[num]; !pack.Custom || pack.Enabled {
-		return
+	pack := r.WordLists[num]
+	if !pack.Custom {
+		return nil
+	}
+
+	if pack.Enabled {
+		need := r.Rows * r.Cols
+		if have := r.wordCount() - pack.List.Len(); have < need {
+			return &Error{
+				Code:    "packNeeded",
+				Message: fmt.Sprintf("Removing %q would leave %d words in the enabled packs, but a board needs %d.", pack.Name, have, need),
+				Limit:   &need,
+			}
+		}
 	}
 
 	// https://github.com/golang/go/wiki/SliceTricks
@@ -364,4 +1470,5 @@ func (r *Room) RemovePack(num int) {
 	r.WordLists = lists
 
 	r.Version++
+	return nil
 }
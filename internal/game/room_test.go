@@ -0,0 +1,1135 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/fit"
+	"github.com/zikaeroh/codies/internal/words"
+	"gotest.tools/v3/assert"
+)
+
+func newTestRoom(t *testing.T) *Room {
+	t.Helper()
+
+	r := NewRoom(rand.New(rand.NewSource(1))) //nolint:gosec
+	r.AddPlayer("spy0", "Spy0")
+	r.AddPlayer("spy1", "Spy1")
+	r.AddPlayer("guess0", "Guess0")
+	r.AddPlayer("guess1", "Guess1")
+	r.NewGame()
+	assert.NilError(t, r.ChangeRole("spy0", true))
+	assert.NilError(t, r.ChangeRole("spy1", true))
+
+	for _, id := range []PlayerID{"spy0", "guess0"} {
+		r.ChangeTeam(id, 0)
+	}
+	for _, id := range []PlayerID{"spy1", "guess1"} {
+		r.ChangeTeam(id, 1)
+	}
+
+	r.Turn = 0
+	return r
+}
+
+// findTile returns the position of an unrevealed tile matching the predicate.
+func findTile(t *testing.T, r *Room, fn func(*Tile) bool) (row, col int) {
+	t.Helper()
+
+	for row := 0; row < r.Board.Rows; row++ {
+		for col := 0; col < r.Board.Cols; col++ {
+			if tile := r.Board.Get(row, col); !tile.Revealed && fn(tile) {
+				return row, col
+			}
+		}
+	}
+
+	t.Fatal("no matching tile")
+	return 0, 0
+}
+
+func teamTile(team Team) func(*Tile) bool {
+	return func(tile *Tile) bool {
+		return !tile.Neutral && !tile.Bomb && tile.Team == team
+	}
+}
+
+func TestGiveClueBounded(t *testing.T) {
+	r := newTestRoom(t)
+	r.ChangeBoundClues(true)
+
+	max := r.Board.WordCounts[0]
+
+	err := r.GiveClue("spy0", 0, "ANIMAL", max+1)
+	var gErr *Error
+	assert.Assert(t, errors.As(err, &gErr))
+	assert.Equal(t, gErr.Code, "clueTooHigh")
+	assert.Equal(t, *gErr.Limit, max)
+	assert.Assert(t, r.Clue == nil)
+
+	assert.NilError(t, r.GiveClue("spy0", 0, "ANIMAL", max))
+	assert.DeepEqual(t, r.Clue, &Clue{Word: "ANIMAL", Count: max})
+}
+
+func TestGiveClueBoundedZeroUnlimited(t *testing.T) {
+	for _, count := range []int{0, ClueUnlimited} {
+		r := newTestRoom(t)
+		r.ChangeBoundClues(true)
+		r.Board.WordCounts[0] = 1
+
+		assert.NilError(t, r.GiveClue("spy0", 0, "ANIMAL", count))
+		assert.Equal(t, r.Clue.Count, count)
+	}
+}
+
+func TestGiveClueBoundRecalculated(t *testing.T) {
+	r := newTestRoom(t)
+	r.ChangeBoundClues(true)
+
+	max := r.Board.WordCounts[0]
+	row, col := findTile(t, r, teamTile(0))
+	r.Reveal("guess0", 0, row, col)
+	assert.Equal(t, r.Turn, Team(0))
+
+	err := r.GiveClue("spy0", 0, "ANIMAL", max)
+	assert.ErrorContains(t, err, "may not exceed")
+	assert.NilError(t, r.GiveClue("spy0", 0, "ANIMAL", max-1))
+}
+
+func TestGiveClueUnbounded(t *testing.T) {
+	r := newTestRoom(t)
+	assert.NilError(t, r.GiveClue("spy0", 0, "ANIMAL", 20))
+	assert.Equal(t, r.Clue.Count, 20)
+}
+
+func TestGuessLimit(t *testing.T) {
+	r := newTestRoom(t)
+	r.ChangeEnforceGuessLimit(true)
+
+	// Guesses before the clue don't count against it.
+	row, col := findTile(t, r, teamTile(0))
+	r.Reveal("guess0", 0, row, col)
+	_, ok := r.GuessesLeft()
+	assert.Assert(t, !ok)
+
+	assert.NilError(t, r.GiveClue("spy0", 0, "ANIMAL", 2))
+	for want := 2; want > 0; want-- {
+		row, col := findTile(t, r, teamTile(0))
+		r.Reveal("guess0", 0, row, col)
+		left, ok := r.GuessesLeft()
+		assert.Assert(t, ok)
+		assert.Equal(t, left, want)
+		assert.Equal(t, r.Turn, Team(0))
+	}
+
+	// The extra guess ends the turn, even when it's right.
+	row, col = findTile(t, r, teamTile(0))
+	r.Reveal("guess0", 0, row, col)
+	assert.Equal(t, r.Turn, Team(1))
+	assert.Assert(t, r.Clue == nil)
+}
+
+func TestGuessLimitWrongGuess(t *testing.T) {
+	r := newTestRoom(t)
+	r.ChangeEnforceGuessLimit(true)
+
+	assert.NilError(t, r.GiveClue("spy0", 0, "ANIMAL", 3))
+	row, col := findTile(t, r, teamTile(1))
+	r.Reveal("guess0", 0, row, col)
+	assert.Equal(t, r.Turn, Team(1))
+
+	// The next team's clue starts its own count.
+	assert.NilError(t, r.GiveClue("spy1", 0, "OCEAN", 1))
+	left, ok := r.GuessesLeft()
+	assert.Assert(t, ok)
+	assert.Equal(t, left, 2)
+}
+
+func TestGuessLimitZeroUnlimited(t *testing.T) {
+	for _, count := range []int{0, ClueUnlimited} {
+		r := newTestRoom(t)
+		r.ChangeEnforceGuessLimit(true)
+		assert.NilError(t, r.GiveClue("spy0", 0, "ANIMAL", count))
+
+		for i := 0; i < 3; i++ {
+			row, col := findTile(t, r, teamTile(0))
+			r.Reveal("guess0", 0, row, col)
+			_, ok := r.GuessesLeft()
+			assert.Assert(t, !ok)
+			assert.Equal(t, r.Turn, Team(0), "count %d", count)
+		}
+	}
+}
+
+func TestGuessLimitOff(t *testing.T) {
+	r := newTestRoom(t)
+	assert.NilError(t, r.GiveClue("spy0", 0, "ANIMAL", 1))
+
+	for i := 0; i < 3; i++ {
+		row, col := findTile(t, r, teamTile(0))
+		r.Reveal("guess0", 0, row, col)
+	}
+	assert.Equal(t, r.Turn, Team(0))
+	_, ok := r.GuessesLeft()
+	assert.Assert(t, !ok)
+}
+
+func TestGiveClueWrongPlayer(t *testing.T) {
+	r := newTestRoom(t)
+	assert.NilError(t, r.GiveClue("guess0", 0, "ANIMAL", 1))
+	assert.NilError(t, r.GiveClue("spy1", 0, "ANIMAL", 1))
+	assert.Assert(t, r.Clue == nil)
+}
+
+func TestClueClearedOnTurnEnd(t *testing.T) {
+	r := newTestRoom(t)
+	assert.NilError(t, r.GiveClue("spy0", 0, "ANIMAL", 2))
+	r.EndTurn("guess0")
+	assert.Assert(t, r.Clue == nil)
+}
+
+func TestGiveClueOnBoard(t *testing.T) {
+	r := newTestRoom(t)
+
+	row, col := findTile(t, r, teamTile(1))
+	word := r.Board.Get(row, col).Word
+	err := r.GiveClue("spy0", 0, strings.ToLower(word), 1)
+	var gErr *Error
+	assert.Assert(t, errors.As(err, &gErr))
+	assert.Equal(t, gErr.Code, "clueOnBoard")
+	assert.Equal(t, gErr.Message, fmt.Sprintf("%q is a word on the board.", word))
+	assert.Assert(t, r.Clue == nil)
+	assert.Equal(t, len(r.ClueLog), 0)
+
+	// Revealed words may be given.
+	row, col = findTile(t, r, teamTile(0))
+	word = r.Board.Get(row, col).Word
+	r.Reveal("guess0", 0, row, col)
+	assert.NilError(t, r.GiveClue("spy0", 0, word, 1))
+	assert.Equal(t, r.Clue.Word, word)
+}
+
+func TestGiveClueOnOtherBoard(t *testing.T) {
+	r := newTestRoom(t)
+	assert.NilError(t, r.ChangeBoards(2, 3, 3, 1))
+	r.NewGame()
+	assert.NilError(t, r.ChangeRole("spy0", true))
+	assert.NilError(t, r.ChangeRole("spy1", true))
+
+	row, col := findTileOn(t, r, 1, teamTile(0))
+	spy := []PlayerID{"spy0", "spy1"}[r.Turn]
+	err := r.GiveClue(spy, 0, r.Boards[1].Get(row, col).Word, 1)
+	assert.ErrorContains(t, err, "is a word on the board")
+}
+
+func TestClueLog(t *testing.T) {
+	r := newTestRoom(t)
+
+	assert.NilError(t, r.GiveClue("spy0", 0, "ANIMAL", 2))
+	r.EndTurn("guess0")
+	assert.NilError(t, r.GiveClue("spy1", 0, "OCEAN", ClueUnlimited))
+
+	assert.DeepEqual(t, r.ClueLog, []*LoggedClue{
+		{Team: 0, Clue: Clue{Word: "ANIMAL", Count: 2}},
+		{Team: 1, Clue: Clue{Word: "OCEAN", Count: ClueUnlimited}},
+	})
+
+	r.NewGame()
+	assert.Equal(t, len(r.ClueLog), 0)
+}
+
+// newCoSpymasterRoom returns a test room where team 0 has a second spymaster.
+func newCoSpymasterRoom(t *testing.T, confirm bool) *Room {
+	t.Helper()
+
+	r := newTestRoom(t)
+	r.ChangeSpymasters(2, confirm)
+	r.AddPlayer("spy2", "Spy2")
+	r.ChangeTeam("spy2", 0)
+	assert.NilError(t, r.ChangeRole("spy2", true))
+	return r
+}
+
+func TestSpymasterLimit(t *testing.T) {
+	r := newCoSpymasterRoom(t, false)
+
+	err := r.ChangeRole("guess0", true)
+	var gErr *Error
+	assert.Assert(t, errors.As(err, &gErr))
+	assert.Equal(t, gErr.Code, "spymasterLimit")
+	assert.Equal(t, *gErr.Limit, 2)
+	assert.Assert(t, !r.Players["guess0"].Spymaster)
+
+	// The other team still has room.
+	assert.NilError(t, r.ChangeRole("guess1", true))
+}
+
+func TestSpymasterLimitLowered(t *testing.T) {
+	r := newCoSpymasterRoom(t, false)
+
+	r.ChangeSpymasters(1, false)
+	assert.Assert(t, r.Players["spy0"].Spymaster)
+	assert.Assert(t, !r.Players["spy2"].Spymaster)
+}
+
+func TestSpymasterLimitChangeTeam(t *testing.T) {
+	r := newCoSpymasterRoom(t, false)
+
+	// A spymaster joining a full team becomes a guesser.
+	assert.NilError(t, r.ChangeRole("guess1", true))
+	r.ChangeTeam("guess1", 0)
+	assert.Assert(t, !r.Players["guess1"].Spymaster)
+}
+
+func TestCoSpymasterClueWithoutConfirm(t *testing.T) {
+	r := newCoSpymasterRoom(t, false)
+
+	assert.NilError(t, r.GiveClue("spy2", 0, "ANIMAL", 2))
+	assert.DeepEqual(t, r.Clue, &Clue{Word: "ANIMAL", Count: 2})
+	assert.Assert(t, r.PendingClue == nil)
+}
+
+func TestCoSpymasterClueConfirm(t *testing.T) {
+	r := newCoSpymasterRoom(t, true)
+
+	assert.NilError(t, r.GiveClue("spy0", 0, "ANIMAL", 2))
+	assert.Assert(t, r.Clue == nil)
+	assert.DeepEqual(t, r.PendingClue, &PendingClue{Clue: Clue{Word: "ANIMAL", Count: 2}, Confirmed: []PlayerID{"spy0"}})
+
+	// Giving it again doesn't count twice.
+	assert.NilError(t, r.GiveClue("spy0", 0, "ANIMAL", 2))
+	assert.Assert(t, r.Clue == nil)
+
+	assert.NilError(t, r.GiveClue("spy2", 0, "ANIMAL", 2))
+	assert.DeepEqual(t, r.Clue, &Clue{Word: "ANIMAL", Count: 2})
+	assert.Assert(t, r.PendingClue == nil)
+}
+
+func TestCoSpymasterClueConfirmDisagree(t *testing.T) {
+	r := newCoSpymasterRoom(t, true)
+
+	assert.NilError(t, r.GiveClue("spy0", 0, "ANIMAL", 2))
+	assert.NilError(t, r.GiveClue("spy2", 0, "PLANT", 1))
+	assert.Assert(t, r.Clue == nil)
+	assert.DeepEqual(t, r.PendingClue.Confirmed, []PlayerID{"spy2"})
+
+	assert.NilError(t, r.GiveClue("spy0", 0, "PLANT", 1))
+	assert.DeepEqual(t, r.Clue, &Clue{Word: "PLANT", Count: 1})
+}
+
+func TestCoSpymasterClueConfirmStepDown(t *testing.T) {
+	r := newCoSpymasterRoom(t, true)
+
+	assert.NilError(t, r.GiveClue("spy0", 0, "ANIMAL", 2))
+
+	// Once the other spymaster steps down, the clue has been confirmed by
+	// everyone left.
+	assert.NilError(t, r.ChangeRole("spy2", false))
+	assert.DeepEqual(t, r.Clue, &Clue{Word: "ANIMAL", Count: 2})
+}
+
+func TestCoSpymasterClueConfirmProposerLeaves(t *testing.T) {
+	r := newCoSpymasterRoom(t, true)
+
+	assert.NilError(t, r.GiveClue("spy0", 0, "ANIMAL", 2))
+	r.RemovePlayer("spy0")
+	assert.Assert(t, r.Clue == nil)
+	assert.Assert(t, r.PendingClue == nil)
+}
+
+func TestCoSpymasterClueConfirmDisconnected(t *testing.T) {
+	r := newCoSpymasterRoom(t, true)
+
+	// A spymaster who's disconnected isn't waited on.
+	r.SetDisconnected("spy0", true)
+	assert.NilError(t, r.GiveClue("spy2", 0, "ANIMAL", 2))
+	assert.DeepEqual(t, r.Clue, &Clue{Word: "ANIMAL", Count: 2})
+	assert.Assert(t, r.PendingClue == nil)
+}
+
+func TestCoSpymasterClueConfirmSpymasterDisconnects(t *testing.T) {
+	r := newCoSpymasterRoom(t, true)
+
+	assert.NilError(t, r.GiveClue("spy0", 0, "ANIMAL", 2))
+	version := r.Version
+	r.SetDisconnected("spy2", true)
+	assert.DeepEqual(t, r.Clue, &Clue{Word: "ANIMAL", Count: 2})
+	assert.Assert(t, r.Version > version)
+
+	// Nor does anything change as they come back.
+	version = r.Version
+	r.SetDisconnected("spy2", false)
+	r.SetDisconnected("spy2", false)
+	assert.Equal(t, r.Version, version+1)
+	assert.DeepEqual(t, r.Clue, &Clue{Word: "ANIMAL", Count: 2})
+}
+
+func TestCoSpymasterClueConfirmTurnEnds(t *testing.T) {
+	r := newCoSpymasterRoom(t, true)
+
+	assert.NilError(t, r.GiveClue("spy0", 0, "ANIMAL", 2))
+	r.ForceEndTurn()
+	assert.Assert(t, r.PendingClue == nil)
+}
+
+func TestCoSpymasterConfirmDisabled(t *testing.T) {
+	r := newCoSpymasterRoom(t, true)
+
+	assert.NilError(t, r.GiveClue("spy0", 0, "ANIMAL", 2))
+	r.ChangeSpymasters(2, false)
+	assert.DeepEqual(t, r.Clue, &Clue{Word: "ANIMAL", Count: 2})
+	assert.Assert(t, r.PendingClue == nil)
+}
+
+func TestAddPlayerOnTeam(t *testing.T) {
+	r := NewRoom(nil)
+	r.AddPlayerOnTeam("a", "Alex", 1)
+	r.AddPlayerOnTeam("b", "Blair", 1)
+	assert.DeepEqual(t, r.Teams, [][]PlayerID{nil, {"a", "b"}})
+	assert.Equal(t, r.Host, "a")
+
+	// Teams which don't exist fall back to the smallest.
+	r.AddPlayerOnTeam("c", "Casey", 5)
+	assert.Equal(t, r.Players["c"].Team, Team(0))
+
+	// Players already in the room stay where they are.
+	r.AddPlayerOnTeam("a", "Alexander", 0)
+	assert.Equal(t, r.Players["a"].Team, Team(1))
+	assert.Equal(t, r.Players["a"].Nickname, "Alexander")
+}
+
+func TestPlayerColorsStable(t *testing.T) {
+	r := NewRoom(nil)
+	r.AddPlayer("a", "Alex")
+	color := r.Players["a"].Color
+
+	r.AddPlayer("a", "Alexander")
+	r.ChangeTeam("a", 1)
+	r.RandomizeTeams()
+	assert.Equal(t, r.Players["a"].Color, color)
+}
+
+func TestPlayerColorsDistinct(t *testing.T) {
+	r := NewRoom(nil)
+	r.AddPlayer("a", "Alex")
+	r.AddPlayer("b", "Alex")
+	r.AddPlayer("c", "Alex")
+	r.AddPlayer("d", "Alex")
+
+	seen := make(map[int]bool)
+	for _, p := range r.Players {
+		assert.Assert(t, !seen[p.Color])
+		seen[p.Color] = true
+	}
+
+	for _, team := range r.Teams {
+		for _, x := range team {
+			for _, y := range team {
+				diff := r.Players[x].Color - r.Players[y].Color
+				assert.Assert(t, diff != 1 && diff != -1, "teammates %s and %s have adjacent colors", x, y)
+			}
+		}
+	}
+}
+
+func TestPlayerColorsReused(t *testing.T) {
+	r := NewRoom(nil)
+	r.AddPlayer("a", "A")
+	r.AddPlayer("b", "B")
+	color := r.Players["a"].Color
+
+	r.RemovePlayer("a")
+	r.AddPlayer("c", "C")
+	assert.Equal(t, r.Players["c"].Color, color)
+}
+
+func TestPlayerColorsExhausted(t *testing.T) {
+	r := NewRoom(nil)
+	for i := 0; i < NumColors+3; i++ {
+		r.AddPlayer(PlayerID(rune('a'+i)), "X")
+	}
+
+	counts := make(map[int]int)
+	for _, p := range r.Players {
+		assert.Assert(t, p.Color >= 0 && p.Color < NumColors)
+		counts[p.Color]++
+	}
+	assert.Equal(t, len(counts), NumColors)
+}
+
+func TestHostMigration(t *testing.T) {
+	r := NewRoom(nil)
+	r.AddPlayer("a", "A")
+	r.AddPlayer("b", "B")
+	r.AddPlayer("c", "C")
+	assert.Equal(t, r.Host, "a")
+
+	r.RemovePlayer("b")
+	assert.Equal(t, r.Host, "a")
+
+	r.RemovePlayer("a")
+	assert.Equal(t, r.Host, "c")
+
+	r.RemovePlayer("c")
+	assert.Equal(t, r.Host, "")
+
+	r.AddPlayer("d", "D")
+	assert.Equal(t, r.Host, "d")
+}
+
+func TestNicknameImpersonation(t *testing.T) {
+	r := NewRoom(nil)
+	r.AddPlayer("host", "Alex")
+	r.AddPlayer("other", "Sam")
+
+	var gErr *Error
+	assert.Assert(t, errors.As(r.NicknameAllowed("new", "A1ex"), &gErr))
+	assert.Equal(t, gErr.Code, "nicknameTaken")
+
+	assert.ErrorContains(t, r.ChangeNickname("other", "ALEX"), "host")
+	assert.Equal(t, r.Players["other"].Nickname, "Sam")
+
+	assert.NilError(t, r.ChangeNickname("other", "Sammy"))
+	assert.NilError(t, r.ChangeNickname("host", "Alexander"))
+	assert.NilError(t, r.ChangeNickname("other", "Alex"))
+}
+
+func TestNicknameImpersonationHostMigration(t *testing.T) {
+	r := NewRoom(nil)
+	r.AddPlayer("host", "Alex")
+	r.AddPlayer("next", "Sam")
+	r.AddPlayer("copy", "5am")
+	r.AddPlayer("fine", "Bob")
+
+	for _, p := range r.Players {
+		assert.Assert(t, !p.Impersonator)
+	}
+
+	r.RemovePlayer("host")
+	assert.Equal(t, r.Host, "next")
+
+	// Existing collisions are flagged rather than renamed.
+	assert.Assert(t, r.Players["copy"].Impersonator)
+	assert.Equal(t, r.Players["copy"].Nickname, "5am")
+	assert.Assert(t, !r.Players["next"].Impersonator)
+	assert.Assert(t, !r.Players["fine"].Impersonator)
+
+	assert.NilError(t, r.ChangeNickname("copy", "Max"))
+	assert.Assert(t, !r.Players["copy"].Impersonator)
+
+	// The host taking a similar name flags the existing player.
+	assert.NilError(t, r.ChangeNickname("next", "B0b"))
+	assert.Assert(t, r.Players["fine"].Impersonator)
+}
+
+// testPack returns a minimum size pack of 25 copies of word.
+func testPack(word string) []string {
+	wds := make([]string, 25)
+	for i := range wds {
+		wds[i] = word
+	}
+	return wds
+}
+
+func TestAddPackCountLimit(t *testing.T) {
+	r := newTestRoom(t)
+	r.PackBudget = PackBudget{Packs: 2}
+
+	assert.NilError(t, r.AddPack("one", testPack("a")))
+	assert.NilError(t, r.AddPack("two", testPack("b")))
+
+	err := r.AddPack("three", testPack("c"))
+	var gErr *Error
+	assert.Assert(t, errors.As(err, &gErr))
+	assert.Equal(t, gErr.Code, "packLimit")
+	assert.Equal(t, *gErr.Limit, 2)
+	assert.Equal(t, len(r.WordLists), 5)
+}
+
+func TestAddPackSizeLimit(t *testing.T) {
+	r := newTestRoom(t)
+	r.PackBudget = PackBudget{Bytes: 100}
+
+	// Exactly at the limit is allowed.
+	assert.NilError(t, r.AddPack("one", testPack("ab")))
+	assert.NilError(t, r.AddPack("two", testPack("ab")))
+
+	err := r.AddPack("three", testPack("a"))
+	var gErr *Error
+	assert.Assert(t, errors.As(err, &gErr))
+	assert.Equal(t, gErr.Code, "packSizeLimit")
+	assert.Equal(t, *gErr.Limit, 100)
+}
+
+func TestRemovePackFreesBudget(t *testing.T) {
+	r := newTestRoom(t)
+	r.PackBudget = PackBudget{Packs: 1, Bytes: 50}
+
+	assert.NilError(t, r.AddPack("one", testPack("ab")))
+	assert.Assert(t, r.AddPack("two", testPack("ab")) != nil)
+
+	r.RemovePack(len(r.WordLists) - 1)
+	assert.NilError(t, r.AddPack("two", testPack("ab")))
+	assert.Equal(t, r.WordLists[len(r.WordLists)-1].Name, "two")
+}
+
+func TestAddPackUnlimited(t *testing.T) {
+	r := newTestRoom(t)
+
+	for i := 0; i < 7; i++ {
+		assert.NilError(t, r.AddPack("pack", testPack("word")))
+	}
+	assert.Equal(t, len(r.WordLists), 10)
+}
+
+func TestAddPackMax(t *testing.T) {
+	r := newTestRoom(t)
+
+	for len(r.WordLists) < MaxPacks {
+		assert.NilError(t, r.AddPack("pack", testPack("word")))
+	}
+
+	err := r.AddPack("one more", testPack("word"))
+	var gErr *Error
+	assert.Assert(t, errors.As(err, &gErr))
+	assert.Equal(t, gErr.Code, "packLimit")
+	assert.Equal(t, *gErr.Limit, MaxPacks)
+	assert.Equal(t, len(r.WordLists), MaxPacks)
+}
+
+func TestRemoveEnabledPack(t *testing.T) {
+	r := newTestRoom(t)
+	assert.NilError(t, r.AddPack("custom", customWords(25)))
+	custom := len(r.WordLists) - 1
+	r.ChangePack(custom, true)
+
+	version := r.Version
+	assert.NilError(t, r.RemovePack(custom))
+	assert.Equal(t, len(r.WordLists), custom)
+	assert.Equal(t, r.Version, version+1)
+}
+
+func TestRemovePackNeeded(t *testing.T) {
+	r := newTestRoom(t)
+	assert.NilError(t, r.AddPack("custom", customWords(25)))
+	custom := len(r.WordLists) - 1
+	r.ChangePack(custom, true)
+	r.ChangePack(0, false)
+
+	version := r.Version
+	err := r.RemovePack(custom)
+	var gErr *Error
+	assert.Assert(t, errors.As(err, &gErr))
+	assert.Equal(t, gErr.Code, "packNeeded")
+	assert.Equal(t, *gErr.Limit, 25)
+	assert.Equal(t, len(r.WordLists), custom+1)
+	assert.Equal(t, r.Version, version)
+
+	// Built-in packs are only ever disabled.
+	assert.NilError(t, r.RemovePack(0))
+	assert.Equal(t, len(r.WordLists), custom+1)
+}
+
+// newBackfillRoom returns a room in German whose one enabled pack has n words,
+// the first of which is also in the German base pack.
+func newBackfillRoom(t *testing.T, n int, backfill bool) *Room {
+	t.Helper()
+
+	english := make([]string, 50)
+	base := make([]string, 50)
+	for i := range base {
+		english[i] = fmt.Sprintf("base%d", i)
+		base[i] = fmt.Sprintf("basis%d", i)
+	}
+	duet := customWords(n)
+	duet[0] = base[0]
+
+	r := newTestRoom(t)
+	r.WordLists = []*WordList{
+		{Name: "Base", Language: "en", List: words.NewList(english)},
+		{Name: "Basis", Language: "de", List: words.NewList(base)},
+		{Name: "Duett", Language: "de", List: words.NewList(duet), Enabled: true},
+	}
+	r.Language = "de"
+	r.BackfillWords = backfill
+	return r
+}
+
+// countWords counts the board's tiles with words from the pack.
+func countWords(b *Board, pack *WordList) int {
+	count := 0
+	for _, tile := range b.tiles {
+		for i := 0; i < pack.List.Len(); i++ {
+			if pack.List.Get(i) == tile.Word {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+func TestBackfillExactFit(t *testing.T) {
+	r := newBackfillRoom(t, 25, true)
+	assert.NilError(t, r.CheckWords())
+	r.NewGame()
+
+	assert.Assert(t, r.Backfill == nil)
+	assert.Equal(t, countWords(r.Board, r.WordLists[2]), 25)
+}
+
+func TestBackfillPartial(t *testing.T) {
+	r := newBackfillRoom(t, 19, true)
+	assert.NilError(t, r.CheckWords())
+	r.NewGame()
+
+	assert.DeepEqual(t, r.Backfill, &Backfill{Pack: "Basis", Language: "de", Count: 6})
+	assert.Equal(t, countWords(r.Board, r.WordLists[2]), 19)
+	assert.Equal(t, countWords(r.Board, r.WordLists[1]), 7) // With the word in both.
+	assert.Equal(t, countWords(r.Board, r.WordLists[0]), 0)
+
+	// Backfilled words are on the board like any other.
+	assert.NilError(t, r.ChangeRole("spy0", true))
+	r.Turn = 0
+	row, col := findTile(t, r, func(tile *Tile) bool {
+		return strings.HasPrefix(tile.Word, "BASIS") && tile.Word != "BASIS0"
+	})
+	err := r.GiveClue("spy0", 0, r.Board.Get(row, col).Word, 1)
+	assert.ErrorContains(t, err, "is a word on the board")
+
+	// The next game draws again, or not at all.
+	r.ChangePack(1, true)
+	r.NewGame()
+	assert.Assert(t, r.Backfill == nil)
+}
+
+func TestBackfillStrict(t *testing.T) {
+	r := newBackfillRoom(t, 19, false)
+	err := r.CheckWords()
+	var gErr *Error
+	assert.Assert(t, errors.As(err, &gErr))
+	assert.Equal(t, gErr.Code, "tooFewWords")
+	assert.Equal(t, gErr.Message, "The enabled packs have 19 words, but 5x5 boards need 25.")
+
+	// Nor does backfill go past the base pack.
+	r.BackfillWords = true
+	assert.NilError(t, r.CheckBoards(1, 6, 6, 1))
+	assert.ErrorContains(t, r.CheckBoards(2, 6, 6, 1), "The enabled packs and backfill have 68 words, but the boards need 72.")
+}
+
+// customWords returns n different words.
+func customWords(n int) []string {
+	wds := make([]string, n)
+	for i := range wds {
+		wds[i] = fmt.Sprintf("word%d", i)
+	}
+	return wds
+}
+
+func TestCleanWords(t *testing.T) {
+	wds := customWords(25)
+	wds[0] = "  " + wds[0] + "\t"
+	wds = append(wds, "", "   ")
+
+	cleaned, err := CleanWords(wds)
+	assert.NilError(t, err)
+	assert.Equal(t, len(cleaned), 25)
+	assert.Equal(t, cleaned[0], strings.TrimSpace(wds[0]))
+}
+
+func TestCleanWordsTooFew(t *testing.T) {
+	_, err := CleanWords(customWords(24))
+	var gErr *Error
+	assert.Assert(t, errors.As(err, &gErr))
+	assert.Equal(t, gErr.Code, "tooFewWords")
+	assert.Equal(t, *gErr.Limit, 25)
+	assert.Equal(t, gErr.Message, "Custom word lists need at least 25 different words, but only 24 were given.")
+}
+
+func TestCleanWordsDuplicates(t *testing.T) {
+	// Plenty of words, but only a handful of different ones.
+	var wds []string
+	for i := 0; i < 20; i++ {
+		wds = append(wds, "apple", "APPLE", " Apple ", "banana", "cherry")
+	}
+
+	_, err := CleanWords(wds)
+	var gErr *Error
+	assert.Assert(t, errors.As(err, &gErr))
+	assert.Equal(t, gErr.Code, "tooFewWords")
+	assert.Equal(t, gErr.Message, "Custom word lists need at least 25 different words, but only 3 were given after removing 97 duplicates.")
+
+	// The first spelling of a word is the one kept.
+	cleaned, err := CleanWords(append(customWords(25), "apple", "APPLE"))
+	assert.NilError(t, err)
+	assert.Equal(t, len(cleaned), 26)
+	assert.Equal(t, cleaned[25], "apple")
+}
+
+func TestCleanWordsTooLong(t *testing.T) {
+	long := strings.Repeat("é", MaxWordLength+1)
+	_, err := CleanWords(append(customWords(25), long))
+	var gErr *Error
+	assert.Assert(t, errors.As(err, &gErr))
+	assert.Equal(t, gErr.Code, "wordTooLong")
+	assert.Equal(t, *gErr.Limit, MaxWordLength)
+
+	// Length is in characters, not bytes.
+	_, err = CleanWords(append(customWords(25), long[len("é"):]))
+	assert.NilError(t, err)
+}
+
+// newGauntletRoom returns a test room playing three 4x4 boards.
+func newGauntletRoom(t *testing.T) *Room {
+	t.Helper()
+
+	r := newTestRoom(t)
+	assert.NilError(t, r.ChangeBoards(3, 4, 4, 1))
+	r.NewGame()
+	assert.NilError(t, r.ChangeRole("spy0", true))
+	assert.NilError(t, r.ChangeRole("spy1", true))
+	r.Turn = 0
+	return r
+}
+
+// findTileOn is findTile for one of several boards.
+func findTileOn(t *testing.T, r *Room, board int, fn func(*Tile) bool) (row, col int) {
+	t.Helper()
+
+	b := r.Boards[board]
+	for row := 0; row < b.Rows; row++ {
+		for col := 0; col < b.Cols; col++ {
+			if tile := b.Get(row, col); !tile.Revealed && fn(tile) {
+				return row, col
+			}
+		}
+	}
+
+	t.Fatal("no matching tile")
+	return 0, 0
+}
+
+func TestGauntletBoards(t *testing.T) {
+	r := newGauntletRoom(t)
+	assert.Equal(t, len(r.Boards), 3)
+	assert.Assert(t, r.Board == r.Boards[0])
+
+	seen := make(map[string]bool)
+	for _, b := range r.Boards {
+		assert.Equal(t, b.Rows, 4)
+		assert.Equal(t, b.Cols, 4)
+		assert.DeepEqual(t, b.WordCounts, r.Boards[0].WordCounts)
+		assert.Equal(t, b.WordCounts[0]+b.WordCounts[1], 11)
+
+		for row := 0; row < b.Rows; row++ {
+			for col := 0; col < b.Cols; col++ {
+				word := b.Get(row, col).Word
+				assert.Assert(t, !seen[word], "%s is on more than one board", word)
+				seen[word] = true
+			}
+		}
+	}
+}
+
+func TestGauntletRevealChoosesBoard(t *testing.T) {
+	r := newGauntletRoom(t)
+	assert.Assert(t, r.TurnBoard == nil)
+
+	row, col := findTileOn(t, r, 1, teamTile(0))
+	r.Reveal("guess0", 1, row, col)
+	assert.Assert(t, r.Boards[1].Get(row, col).Revealed)
+	assert.Equal(t, *r.TurnBoard, 1)
+
+	// The rest of the turn is played on the same board.
+	row, col = findTileOn(t, r, 2, teamTile(0))
+	r.Reveal("guess0", 2, row, col)
+	assert.Assert(t, !r.Boards[2].Get(row, col).Revealed)
+
+	r.EndTurn("guess0")
+	assert.Assert(t, r.TurnBoard == nil)
+}
+
+func TestGauntletClueChoosesBoard(t *testing.T) {
+	r := newGauntletRoom(t)
+
+	assert.NilError(t, r.GiveClue("spy0", 5, "ANIMAL", 1))
+	assert.Assert(t, r.Clue == nil)
+
+	assert.NilError(t, r.GiveClue("spy0", 2, "ANIMAL", 1))
+	assert.DeepEqual(t, r.Clue, &Clue{Board: 2, Word: "ANIMAL", Count: 1})
+	assert.Equal(t, *r.TurnBoard, 2)
+
+	row, col := findTileOn(t, r, 0, teamTile(0))
+	r.Reveal("guess0", 0, row, col)
+	assert.Assert(t, !r.Boards[0].Get(row, col).Revealed)
+}
+
+func TestGauntletBoundCluesPerBoard(t *testing.T) {
+	r := newGauntletRoom(t)
+	r.ChangeBoundClues(true)
+	r.Boards[1].WordCounts[0] = 1
+
+	err := r.GiveClue("spy0", 1, "ANIMAL", 2)
+	assert.ErrorContains(t, err, "may not exceed 1")
+	assert.NilError(t, r.GiveClue("spy0", 0, "ANIMAL", 2))
+}
+
+func TestGauntletWinOnAnyBoard(t *testing.T) {
+	r := newGauntletRoom(t)
+	r.Boards[2].WordCounts[0] = 1
+
+	row, col := findTileOn(t, r, 2, teamTile(0))
+	r.Reveal("guess0", 2, row, col)
+	assert.Assert(t, r.Winner != nil)
+	assert.Equal(t, *r.Winner, Team(0))
+}
+
+func TestGauntletBomb(t *testing.T) {
+	r := newGauntletRoom(t)
+
+	row, col := findTileOn(t, r, 1, func(tile *Tile) bool { return tile.Bomb })
+	r.Reveal("guess0", 1, row, col)
+	assert.Assert(t, r.Winner != nil)
+	assert.Equal(t, *r.Winner, Team(1))
+}
+
+func TestSingleBoardTurnBoard(t *testing.T) {
+	r := newTestRoom(t)
+	assert.NilError(t, r.GiveClue("spy0", 0, "ANIMAL", 1))
+	assert.Assert(t, r.TurnBoard == nil)
+
+	row, col := findTile(t, r, teamTile(0))
+	r.Reveal("guess0", 1, row, col)
+	assert.Assert(t, !r.Board.Get(row, col).Revealed)
+}
+
+func TestCheckBoards(t *testing.T) {
+	r := newTestRoom(t)
+
+	assert.NilError(t, r.CheckBoards(1, 5, 5, 1))
+	assert.NilError(t, r.CheckBoards(3, 3, 4, 1))
+	assert.ErrorContains(t, r.CheckBoards(0, 5, 5, 1), "between 1 and 3")
+	assert.ErrorContains(t, r.CheckBoards(4, 3, 3, 1), "between 1 and 3")
+	assert.ErrorContains(t, r.CheckBoards(1, 2, 5, 1), "can't be 2x5")
+	assert.ErrorContains(t, r.CheckBoards(1, 7, 5, 1), "can't be 7x5")
+	assert.NilError(t, r.CheckBoards(1, 5, 3, 1))
+	assert.NilError(t, r.CheckBoards(1, 6, 6, 1))
+	assert.NilError(t, r.CheckBoards(1, 5, 5, 0))
+	assert.NilError(t, r.CheckBoards(1, 3, 3, 2))
+	assert.ErrorContains(t, r.CheckBoards(1, 5, 5, 4), "between 0 and 3 bombs")
+	assert.ErrorContains(t, r.CheckBoards(1, 3, 3, 3), "3x3 boards don't have room for 3 bombs")
+
+	// A small pack can't fill many boards.
+	assert.NilError(t, r.AddPack("small", []string{"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N", "O", "P", "Q", "R", "S", "T"}))
+	r.ChangePack(len(r.WordLists)-1, true)
+	r.ChangePack(0, false)
+	assert.NilError(t, r.CheckBoards(1, 4, 4, 1))
+	assert.ErrorContains(t, r.CheckBoards(2, 4, 4, 1), "have 20 words, but the boards need 32")
+}
+
+func TestGauntletFewerWords(t *testing.T) {
+	r := newTestRoom(t)
+	assert.NilError(t, r.ChangeBoards(3, 3, 3, 1))
+
+	// Packs changed after the boards were configured.
+	assert.NilError(t, r.AddPack("small", []string{"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N", "O", "P", "Q", "R", "S", "T"}))
+	r.ChangePack(len(r.WordLists)-1, true)
+	r.ChangePack(0, false)
+
+	r.NewGame()
+	assert.Equal(t, len(r.Boards), 2)
+}
+
+func TestCheckWords(t *testing.T) {
+	r := newTestRoom(t)
+	assert.NilError(t, r.ChangeBoards(1, 6, 6, 1))
+	assert.NilError(t, r.CheckWords())
+
+	// Packs changed after the boards were configured.
+	assert.NilError(t, r.AddPack("small", []string{"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N", "O", "P", "Q", "R", "S", "T"}))
+	r.ChangePack(len(r.WordLists)-1, true)
+	r.ChangePack(0, false)
+
+	err := r.CheckWords()
+	assert.ErrorContains(t, err, "have 20 words, but 6x6 boards need 36")
+
+	var gErr *Error
+	assert.Assert(t, errors.As(err, &gErr))
+	assert.Equal(t, gErr.Code, "tooFewWords")
+	assert.Equal(t, *gErr.Limit, 36)
+}
+
+func TestNewGameBombs(t *testing.T) {
+	for bombs := 0; bombs <= MaxBombs; bombs++ {
+		r := newTestRoom(t)
+		assert.NilError(t, r.ChangeBoards(1, 5, 5, bombs))
+		r.NewGame()
+
+		count := 0
+		for row := 0; row < r.Board.Rows; row++ {
+			for col := 0; col < r.Board.Cols; col++ {
+				if r.Board.Get(row, col).Bomb {
+					count++
+				}
+			}
+		}
+		assert.Equal(t, count, bombs)
+		assert.Equal(t, r.Board.WordCounts[0]+r.Board.WordCounts[1], 17)
+	}
+}
+
+func TestRevealAnyBomb(t *testing.T) {
+	r := newTestRoom(t)
+	assert.NilError(t, r.ChangeBoards(1, 5, 5, 3))
+
+	for i := 0; i < 3; i++ {
+		r.NewGame()
+		r.Turn = 0
+
+		// Skip the bombs before this one.
+		seen := 0
+		for row := 0; row < r.Board.Rows; row++ {
+			for col := 0; col < r.Board.Cols; col++ {
+				if !r.Board.Get(row, col).Bomb {
+					continue
+				}
+				if seen == i {
+					r.Reveal("guess0", 0, row, col)
+				}
+				seen++
+			}
+		}
+
+		assert.Assert(t, r.Winner != nil)
+		assert.Equal(t, *r.Winner, Team(1))
+	}
+}
+
+func TestNewGameLargeBoard(t *testing.T) {
+	r := newTestRoom(t)
+	assert.NilError(t, r.ChangeBoards(1, 6, 6, 1))
+	r.NewGame()
+
+	assert.Equal(t, r.Board.Rows, 6)
+	assert.Equal(t, r.Board.Cols, 6)
+	assert.Equal(t, r.Board.WordCounts[r.Turn], 13)
+	assert.Equal(t, r.Board.WordCounts[r.Turn.next(2)], 12)
+}
+
+func TestNewGameFit(t *testing.T) {
+	r := newTestRoom(t)
+	r.Fit = fit.Thresholds{Text: []int{0}}
+	r.NewGame()
+
+	for row := 0; row < r.Rows; row++ {
+		for col := 0; col < r.Cols; col++ {
+			tile := r.Board.Get(row, col)
+			assert.Equal(t, tile.Fit.Class, fit.Text)
+			assert.Equal(t, tile.Fit.Length, len(tile.Word))
+			assert.Equal(t, tile.Fit.Size, 1)
+		}
+	}
+}
+
+func TestRolloverVersion(t *testing.T) {
+	r := newTestRoom(t)
+
+	r.Version = MaxVersion
+	assert.Assert(t, !r.RolloverVersion())
+	assert.Equal(t, r.Version, int64(MaxVersion))
+
+	r.EndTurn("guess0")
+	assert.Assert(t, r.RolloverVersion())
+	assert.Equal(t, r.Version, int64(1))
+}
+
+func TestNewRandSeeded(t *testing.T) {
+	words := func(seed int64) []string {
+		r := NewRoom(NewRand(seed))
+		r.NewGame()
+
+		var words []string
+		for row := 0; row < r.Rows; row++ {
+			for col := 0; col < r.Cols; col++ {
+				words = append(words, r.Board.Get(row, col).Word)
+			}
+		}
+		return words
+	}
+
+	assert.DeepEqual(t, words(42), words(42))
+	assert.Assert(t, !reflect.DeepEqual(words(42), words(43)))
+}
+
+func TestWins(t *testing.T) {
+	r := newTestRoom(t)
+	assert.DeepEqual(t, r.Wins, []int{0, 0})
+
+	// A bomb credits the other team.
+	row, col := findTile(t, r, func(tile *Tile) bool { return tile.Bomb })
+	r.Reveal("guess0", 0, row, col)
+	assert.DeepEqual(t, r.Wins, []int{0, 1})
+
+	// Winning on words, in the next game.
+	r.NewGame()
+	r.Turn = 0
+	r.Board.WordCounts[0] = 1
+	row, col = findTile(t, r, teamTile(0))
+	r.Reveal("guess0", 0, row, col)
+	assert.DeepEqual(t, r.Wins, []int{1, 1})
+
+	// A finished game is only counted once.
+	row, col = findTile(t, r, teamTile(0))
+	r.Reveal("guess0", 0, row, col)
+	assert.DeepEqual(t, r.Wins, []int{1, 1})
+
+	// Forfeits count, too.
+	r.NewGame()
+	r.Turn = 1
+	r.ChangePenaltyLimit(1)
+	r.ExpireTurn()
+	assert.DeepEqual(t, r.Wins, []int{2, 1})
+
+	// The tally belongs to the room, not the players.
+	r.RemovePlayer("guess0")
+	r.RemovePlayer("guess1")
+	r.AddPlayer("guess0", "Guess0")
+	assert.DeepEqual(t, r.Wins, []int{2, 1})
+}
+
+func TestResetWins(t *testing.T) {
+	r := newTestRoom(t)
+	r.Wins = []int{3, 2}
+
+	r.ResetWins("guess0")
+	assert.DeepEqual(t, r.Wins, []int{3, 2})
+
+	version := r.Version
+	r.ResetWins(r.Host)
+	assert.DeepEqual(t, r.Wins, []int{0, 0})
+	assert.Equal(t, r.Version, version+1)
+
+	r.ResetWins(r.Host)
+	assert.Equal(t, r.Version, version+1)
+}
+
+func TestTurns(t *testing.T) {
+	r := newTestRoom(t)
+	assert.Equal(t, r.Turns(), 1)
+
+	r.EndTurn("guess0")
+	row, col := findTile(t, r, func(tile *Tile) bool { return tile.Neutral })
+	r.Reveal("guess1", 0, row, col)
+	assert.Equal(t, r.Turns(), 3)
+
+	// An undone reveal takes back the turn it ended.
+	assert.NilError(t, r.Undo(r.Host))
+	assert.Equal(t, r.Turns(), 2)
+
+	r.NewGame()
+	assert.Equal(t, r.Turns(), 1)
+}
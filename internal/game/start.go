@@ -0,0 +1,127 @@
+package game
+
+import "fmt"
+
+// MaxStartMinimum is the highest number of spymasters or guessers a team may
+// be required to have.
+const MaxStartMinimum = 5
+
+// Shortfall is a team which has fewer players in a role than a new game
+// requires.
+type Shortfall struct {
+	Team      Team
+	Spymaster bool // Otherwise, the team is missing guessers.
+	Have      int
+	Need      int
+}
+
+// CheckStart returns an error if any team has fewer spymasters or guessers
+// than MinSpymasters and MinGuessers. Only connected players count; those
+// whose seats are held while they reconnect don't.
+func (r *Room) CheckStart() error {
+	var shortfalls []*Shortfall
+
+	for team, ids := range r.Teams {
+		var spymasters, guessers int
+		for _, id := range ids {
+			p := r.Players[id]
+			switch {
+			case p.Disconnected:
+			case p.Spymaster:
+				spymasters++
+			default:
+				guessers++
+			}
+		}
+
+		if spymasters < r.MinSpymasters {
+			shortfalls = append(shortfalls, &Shortfall{Team: Team(team), Spymaster: true, Have: spymasters, Need: r.MinSpymasters})
+		}
+		if guessers < r.MinGuessers {
+			shortfalls = append(shortfalls, &Shortfall{Team: Team(team), Have: guessers, Need: r.MinGuessers})
+		}
+	}
+
+	if len(shortfalls) == 0 {
+		return nil
+	}
+
+	return &Error{
+		Code:       "teamsNotReady",
+		Message:    fmt.Sprintf("Each team needs at least %d spymaster(s) and %d guesser(s) to start.", r.MinSpymasters, r.MinGuessers),
+		Shortfalls: shortfalls,
+	}
+}
+
+// ChangeStartMinimums sets the fewest spymasters and guessers each team needs
+// for a new game to start.
+func (r *Room) ChangeStartMinimums(spymasters, guessers int) {
+	if spymasters < 0 || spymasters > MaxStartMinimum || guessers < 0 || guessers > MaxStartMinimum {
+		return
+	}
+
+	if r.MinSpymasters == spymasters && r.MinGuessers == guessers {
+		return
+	}
+
+	r.MinSpymasters = spymasters
+	r.MinGuessers = guessers
+	r.Version++
+}
+
+// CheckReveal returns an error if nothing has been revealed yet this game and
+// the teams have fewer than MinRevealPlayers connected players between them.
+// This keeps a lone player from starting a game and spoiling the board before
+// anyone else arrives. Hints don't count as reveals.
+func (r *Room) CheckReveal() error {
+	if r.MinRevealPlayers == 0 || r.revealedThisGame {
+		return nil
+	}
+
+	players := 0
+	for _, ids := range r.Teams {
+		for _, id := range ids {
+			if !r.Players[id].Disconnected {
+				players++
+			}
+		}
+	}
+
+	if players >= r.MinRevealPlayers {
+		return nil
+	}
+
+	limit := r.MinRevealPlayers
+	return &Error{
+		Code:    "notEnoughPlayers",
+		Message: fmt.Sprintf("At least %d players must be in the room before the first card is revealed.", limit),
+		Limit:   &limit,
+	}
+}
+
+// Started reports whether the current game is underway: a card has been
+// revealed or hinted, a clue given, or a point scored, and nobody has won yet.
+// Until then, the room is between games.
+func (r *Room) Started() bool {
+	if r.Winner != nil {
+		return false
+	}
+
+	if r.revealedThisGame || r.Clue != nil || r.PendingClue != nil || len(r.ClueLog) != 0 {
+		return true
+	}
+
+	for _, used := range r.HintsUsed {
+		if used != 0 {
+			return true
+		}
+	}
+
+	for _, score := range r.Scores {
+		if score != 0 {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,211 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCheckStart(t *testing.T) {
+	type member struct {
+		id        PlayerID
+		team      Team
+		spymaster bool
+	}
+
+	tests := []struct {
+		name    string
+		members []member
+		want    []*Shortfall
+	}{
+		{
+			name: "ready",
+			members: []member{
+				{"spy0", 0, true}, {"guess0", 0, false},
+				{"spy1", 1, true}, {"guess1", 1, false},
+			},
+		},
+		{
+			name: "no spymaster",
+			members: []member{
+				{"spy0", 0, true}, {"guess0", 0, false},
+				{"guess1", 1, false},
+			},
+			want: []*Shortfall{{Team: 1, Spymaster: true, Have: 0, Need: 1}},
+		},
+		{
+			name: "no guesser",
+			members: []member{
+				{"spy0", 0, true},
+				{"spy1", 1, true}, {"guess1", 1, false},
+			},
+			want: []*Shortfall{{Team: 0, Have: 0, Need: 1}},
+		},
+		{
+			name: "empty team",
+			members: []member{
+				{"spy0", 0, true}, {"guess0", 0, false},
+			},
+			want: []*Shortfall{
+				{Team: 1, Spymaster: true, Have: 0, Need: 1},
+				{Team: 1, Have: 0, Need: 1},
+			},
+		},
+		{
+			name: "only spymasters",
+			members: []member{
+				{"spy0", 0, true},
+				{"spy1", 1, true},
+			},
+			want: []*Shortfall{
+				{Team: 0, Have: 0, Need: 1},
+				{Team: 1, Have: 0, Need: 1},
+			},
+		},
+		{
+			name: "empty room",
+			want: []*Shortfall{
+				{Team: 0, Spymaster: true, Have: 0, Need: 1},
+				{Team: 0, Have: 0, Need: 1},
+				{Team: 1, Spymaster: true, Have: 0, Need: 1},
+				{Team: 1, Have: 0, Need: 1},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			r := NewRoom(rand.New(rand.NewSource(1))) //nolint:gosec
+			for _, m := range test.members {
+				r.AddPlayer(m.id, string(m.id))
+				r.ChangeTeam(m.id, m.team)
+				assert.NilError(t, r.ChangeRole(m.id, m.spymaster))
+			}
+
+			err := r.CheckStart()
+			if test.want == nil {
+				assert.NilError(t, err)
+				return
+			}
+
+			var gErr *Error
+			assert.Assert(t, errors.As(err, &gErr))
+			assert.Equal(t, gErr.Code, "teamsNotReady")
+			assert.DeepEqual(t, gErr.Shortfalls, test.want)
+		})
+	}
+}
+
+func TestCheckStartMinimums(t *testing.T) {
+	r := newTestRoom(t)
+	assert.NilError(t, r.CheckStart())
+
+	r.ChangeStartMinimums(1, 2)
+	var gErr *Error
+	assert.Assert(t, errors.As(r.CheckStart(), &gErr))
+	assert.DeepEqual(t, gErr.Shortfalls, []*Shortfall{
+		{Team: 0, Have: 1, Need: 2},
+		{Team: 1, Have: 1, Need: 2},
+	})
+
+	// Players who leave stop counting.
+	r.ChangeStartMinimums(1, 1)
+	r.RemovePlayer("guess1")
+	assert.Assert(t, errors.As(r.CheckStart(), &gErr))
+	assert.DeepEqual(t, gErr.Shortfalls, []*Shortfall{{Team: 1, Have: 0, Need: 1}})
+
+	// Nor do those who've disconnected, until they're back.
+	r.SetDisconnected("spy0", true)
+	assert.Assert(t, errors.As(r.CheckStart(), &gErr))
+	assert.DeepEqual(t, gErr.Shortfalls, []*Shortfall{
+		{Team: 0, Spymaster: true, Have: 0, Need: 1},
+		{Team: 1, Have: 0, Need: 1},
+	})
+	r.SetDisconnected("spy0", false)
+
+	// No minimums, no requirements.
+	r.ChangeStartMinimums(0, 0)
+	assert.NilError(t, r.CheckStart())
+}
+
+func TestChangeStartMinimums(t *testing.T) {
+	r := newTestRoom(t)
+	version := r.Version
+
+	r.ChangeStartMinimums(1, 1)
+	assert.Equal(t, r.Version, version)
+
+	r.ChangeStartMinimums(-1, 1)
+	r.ChangeStartMinimums(1, MaxStartMinimum+1)
+	assert.Equal(t, r.Version, version)
+
+	r.ChangeStartMinimums(2, 3)
+	assert.Equal(t, r.MinSpymasters, 2)
+	assert.Equal(t, r.MinGuessers, 3)
+	assert.Equal(t, r.Version, version+1)
+}
+
+func TestCheckReveal(t *testing.T) {
+	r := newTestRoom(t)
+	assert.NilError(t, r.CheckReveal())
+
+	r.MinRevealPlayers = 5
+	var gErr *Error
+	assert.Assert(t, errors.As(r.CheckReveal(), &gErr))
+	assert.Equal(t, gErr.Code, "notEnoughPlayers")
+	assert.Equal(t, *gErr.Limit, 5)
+
+	r.AddPlayer("late", "Late")
+	assert.NilError(t, r.CheckReveal())
+
+	// Once a tile is revealed, players leaving don't matter.
+	row, col := findTile(t, r, teamTile(0))
+	r.Reveal("guess0", 0, row, col)
+	r.RemovePlayer("late")
+	r.RemovePlayer("guess1")
+	assert.NilError(t, r.CheckReveal())
+
+	// Until the next game.
+	r.NewGame()
+	assert.Assert(t, errors.As(r.CheckReveal(), &gErr))
+}
+
+func TestStarted(t *testing.T) {
+	r := newTestRoom(t)
+	assert.Assert(t, !r.Started())
+
+	// Passing the turn isn't playing.
+	r.EndTurn("guess0")
+	assert.Assert(t, !r.Started())
+
+	assert.NilError(t, r.GiveClue("spy1", 0, "ANIMAL", 2))
+	assert.Assert(t, r.Started())
+
+	r.NewGame()
+	assert.Assert(t, !r.Started())
+
+	row, col := findTile(t, r, teamTile(r.Turn))
+	r.Reveal(fmt.Sprint("guess", r.Turn), 0, row, col)
+	assert.Assert(t, r.Started())
+
+	// Once someone's won, it's between games again.
+	row, col = findTile(t, r, func(tile *Tile) bool { return tile.Bomb })
+	r.Reveal(fmt.Sprint("guess", r.Turn), 0, row, col)
+	assert.Assert(t, r.Winner != nil)
+	assert.Assert(t, !r.Started())
+}
+
+func TestStartedTracker(t *testing.T) {
+	r := newTestTrackerRoom(t)
+	assert.Assert(t, !r.Started())
+
+	r.Score("guess0", 1, 1)
+	assert.Assert(t, r.Started())
+
+	r.NewGame()
+	assert.Assert(t, !r.Started())
+}
@@ -0,0 +1,50 @@
+package game
+
+// NewTrackerRoom creates a room for groups playing with a physical board. A
+// tracker room has no boards or words; it only keeps the teams, turns,
+// scores, and the log of the clues given. Whether a room is a tracker room is
+// fixed when it's created.
+func NewTrackerRoom(rand Rand) *Room {
+	r := NewRoom(rand)
+	r.Tracker = true
+	r.WordLists = nil
+	return r
+}
+
+// Score changes a team's score in a tracker game by one, as the players find
+// their words on the physical board. Scores never go below zero. A point for
+// the team whose turn it is counts as a reveal for penalties.
+func (r *Room) Score(id PlayerID, team Team, delta int) {
+	if !r.Tracker || r.Winner != nil || r.Players[id] == nil {
+		return
+	}
+
+	if team < 0 || int(team) >= len(r.Teams) || (delta != 1 && delta != -1) {
+		return
+	}
+
+	if r.Scores[team]+delta < 0 {
+		return
+	}
+
+	r.Scores[team] += delta
+	if team == r.Turn && delta > 0 {
+		r.revealedThisTurn = true
+	}
+	r.Version++
+}
+
+// DeclareWinner ends a tracker game, which can't tell on its own when a team
+// has won. Only the host may declare the winner.
+func (r *Room) DeclareWinner(id PlayerID, team Team) {
+	if !r.Tracker || r.Winner != nil || id != r.Host {
+		return
+	}
+
+	if team < 0 || int(team) >= len(r.Teams) {
+		return
+	}
+
+	r.win(team)
+	r.Version++
+}
@@ -0,0 +1,154 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func newTestTrackerRoom(t *testing.T) *Room {
+	t.Helper()
+
+	r := NewTrackerRoom(rand.New(rand.NewSource(1))) //nolint:gosec
+	r.AddPlayer("spy0", "Spy0")
+	r.AddPlayer("spy1", "Spy1")
+	r.AddPlayer("guess0", "Guess0")
+	r.AddPlayer("guess1", "Guess1")
+	r.NewGame()
+
+	for _, id := range []PlayerID{"spy0", "guess0"} {
+		r.ChangeTeam(id, 0)
+	}
+	for _, id := range []PlayerID{"spy1", "guess1"} {
+		r.ChangeTeam(id, 1)
+	}
+	assert.NilError(t, r.ChangeRole("spy0", true))
+	assert.NilError(t, r.ChangeRole("spy1", true))
+
+	r.Turn = 0
+	return r
+}
+
+func TestTrackerNewGame(t *testing.T) {
+	r := newTestTrackerRoom(t)
+
+	assert.Assert(t, r.Board == nil)
+	assert.Equal(t, len(r.Boards), 0)
+	assert.DeepEqual(t, r.Scores, []int{0, 0})
+
+	r.Score("guess0", 0, 1)
+	assert.NilError(t, r.GiveClue("spy0", 0, "ANIMAL", 2))
+
+	r.NewGame()
+	assert.DeepEqual(t, r.Scores, []int{0, 0})
+	assert.Equal(t, len(r.ClueLog), 0)
+	assert.Equal(t, r.Games, 2)
+}
+
+func TestTrackerScore(t *testing.T) {
+	r := newTestTrackerRoom(t)
+
+	r.Score("guess0", 0, 1)
+	r.Score("guess1", 0, 1)
+	r.Score("guess0", 1, 1)
+	assert.DeepEqual(t, r.Scores, []int{2, 1})
+
+	// Scores move one at a time and never go negative.
+	r.Score("guess0", 0, 5)
+	r.Score("guess0", 1, -1)
+	r.Score("guess0", 1, -1)
+	r.Score("guess0", 2, 1)
+	r.Score("nobody", 0, 1)
+	assert.DeepEqual(t, r.Scores, []int{2, 0})
+
+	// Scores don't change once the game is over.
+	r.DeclareWinner("spy0", 0)
+	r.Score("guess0", 0, 1)
+	assert.DeepEqual(t, r.Scores, []int{2, 0})
+}
+
+func TestTrackerScoreAvoidsPenalty(t *testing.T) {
+	r := newTestTrackerRoom(t)
+	r.ChangePenaltyLimit(1)
+
+	r.Score("guess0", 0, 1)
+	r.ExpireTurn()
+	assert.Assert(t, r.Winner == nil)
+	assert.Equal(t, r.Turn, Team(1))
+
+	// The other team scoring doesn't count for the team whose turn it is.
+	r.Score("guess0", 0, 1)
+	r.ExpireTurn()
+	assert.Assert(t, r.Winner != nil)
+	assert.Equal(t, *r.Winner, Team(0))
+	assert.Assert(t, r.Forfeit)
+}
+
+func TestTrackerDeclareWinner(t *testing.T) {
+	r := newTestTrackerRoom(t)
+	assert.Equal(t, r.Host, PlayerID("spy0"))
+
+	r.DeclareWinner("guess1", 1)
+	r.DeclareWinner("spy0", 2)
+	assert.Assert(t, r.Winner == nil)
+
+	r.DeclareWinner("spy0", 1)
+	assert.Assert(t, r.Winner != nil)
+	assert.Equal(t, *r.Winner, Team(1))
+
+	// Only tracker rooms may declare a winner.
+	b := newTestRoom(t)
+	b.DeclareWinner(b.Host, 1)
+	assert.Assert(t, b.Winner == nil)
+}
+
+func TestTrackerClueLog(t *testing.T) {
+	r := newTestTrackerRoom(t)
+	r.BoundClues = true
+
+	assert.NilError(t, r.GiveClue("spy0", 2, "ANIMAL", 9))
+	assert.DeepEqual(t, r.Clue, &Clue{Word: "ANIMAL", Count: 9})
+	r.EndTurn("guess0")
+	assert.NilError(t, r.GiveClue("spy1", 0, "OCEAN", ClueUnlimited))
+
+	assert.DeepEqual(t, r.ClueLog, []*LoggedClue{
+		{Team: 0, Clue: Clue{Word: "ANIMAL", Count: 9}},
+		{Team: 1, Clue: Clue{Word: "OCEAN", Count: ClueUnlimited}},
+	})
+}
+
+func TestTrackerClueLogBounded(t *testing.T) {
+	r := newTestTrackerRoom(t)
+
+	for i := 0; i < MaxClueLog+5; i++ {
+		spy := PlayerID("spy0")
+		if r.Turn == 1 {
+			spy = "spy1"
+		}
+		assert.NilError(t, r.GiveClue(spy, 0, "WORD", i))
+		r.ForceEndTurn()
+	}
+
+	assert.Equal(t, len(r.ClueLog), MaxClueLog)
+	assert.Equal(t, r.ClueLog[0].Count, 5)
+	assert.Equal(t, r.ClueLog[MaxClueLog-1].Count, MaxClueLog+4)
+}
+
+func TestTrackerNoBoard(t *testing.T) {
+	r := newTestTrackerRoom(t)
+	version := r.Version
+
+	// Commands which need a board are ignored.
+	r.Reveal("guess0", 0, 0, 0)
+	assert.NilError(t, r.UseHint("guess0", 0))
+	assert.Equal(t, r.Version, version)
+	assert.Assert(t, r.CheckBoards(1, 5, 5, 1) != nil)
+}
+
+func TestDeclareWinnerCountsWin(t *testing.T) {
+	r := newTestTrackerRoom(t)
+
+	r.DeclareWinner(r.Host, 1)
+	assert.DeepEqual(t, r.Wins, []int{0, 1})
+}
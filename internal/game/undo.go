@@ -0,0 +1,88 @@
+package game
+
+// revealUndo is what Undo needs to take back a reveal: the tile, and the turn
+// as it was just before the reveal. Reveals can't be made once the game is
+// won, so the game was always still open.
+type revealUndo struct {
+	board, row, col int
+
+	turn             Team
+	clue             *Clue
+	pendingClue      *PendingClue
+	turnBoard        *int
+	revealedThisTurn bool
+	revealedThisGame bool
+	guesses          int
+	turns            int
+}
+
+// saveReveal records the state a reveal of the tile is about to change.
+func (r *Room) saveReveal(board, row, col int) {
+	r.lastReveal = &revealUndo{
+		board:            board,
+		row:              row,
+		col:              col,
+		turn:             r.Turn,
+		clue:             r.Clue,
+		pendingClue:      r.PendingClue,
+		turnBoard:        r.TurnBoard,
+		revealedThisTurn: r.revealedThisTurn,
+		revealedThisGame: r.revealedThisGame,
+		guesses:          r.guesses,
+		turns:            r.turns,
+	}
+}
+
+// forgetReveal drops the reveal Undo would take back. It's called for any
+// play after the reveal, which the restored turn would contradict.
+func (r *Room) forgetReveal() {
+	r.lastReveal = nil
+}
+
+// CanUndo returns true if there's a reveal for Undo to take back.
+func (r *Room) CanUndo() bool {
+	return r.lastReveal != nil
+}
+
+// Undo takes back the most recent reveal, restoring the turn as it was just
+// before, and reopening the game if the reveal ended it. Only the host may
+// undo, and only the one reveal; once anything else has been played since,
+// there's nothing to undo.
+func (r *Room) Undo(id PlayerID) error {
+	if id != r.Host {
+		return nil
+	}
+
+	u := r.lastReveal
+	if u == nil {
+		return &Error{
+			Code:    "nothingToUndo",
+			Message: "There is no reveal to undo.",
+		}
+	}
+	r.lastReveal = nil
+
+	b := r.Boards[u.board]
+	tile := b.Get(u.row, u.col)
+	tile.Revealed = false
+	if !tile.Neutral && !tile.Bomb {
+		b.WordCounts[tile.Team]++
+	}
+
+	if r.Winner != nil {
+		r.Wins[*r.Winner]--
+		r.Winner = nil
+	}
+
+	r.Turn = u.turn
+	r.Clue = u.clue
+	r.PendingClue = u.pendingClue
+	r.TurnBoard = u.turnBoard
+	r.revealedThisTurn = u.revealedThisTurn
+	r.revealedThisGame = u.revealedThisGame
+	r.guesses = u.guesses
+	r.turns = u.turns
+
+	r.Version++
+	return nil
+}
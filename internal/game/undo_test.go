@@ -0,0 +1,109 @@
+package game
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestUndo(t *testing.T) {
+	r := newTestRoom(t)
+	r.ChangeEnforceGuessLimit(true)
+	assert.NilError(t, r.GiveClue("spy0", 0, "ANIMAL", 1))
+
+	// A wrong guess ends the turn; undoing it gives it back.
+	left := r.Board.WordCounts[1]
+	row, col := findTile(t, r, teamTile(1))
+	r.Reveal("guess0", 0, row, col)
+	assert.Equal(t, r.Turn, Team(1))
+	assert.Assert(t, r.CanUndo())
+
+	version := r.Version
+	assert.NilError(t, r.Undo(r.Host))
+	assert.Equal(t, r.Version, version+1)
+	assert.Assert(t, !r.Board.Get(row, col).Revealed)
+	assert.Equal(t, r.Board.WordCounts[1], left)
+	assert.Equal(t, r.Turn, Team(0))
+	assert.DeepEqual(t, r.Clue, &Clue{Word: "ANIMAL", Count: 1})
+
+	guesses, ok := r.GuessesLeft()
+	assert.Assert(t, ok)
+	assert.Equal(t, guesses, 2)
+
+	// Only one reveal is kept.
+	var gErr *Error
+	assert.Assert(t, errors.As(r.Undo(r.Host), &gErr))
+	assert.Equal(t, gErr.Code, "nothingToUndo")
+}
+
+func TestUndoBomb(t *testing.T) {
+	r := newTestRoom(t)
+
+	row, col := findTile(t, r, func(tile *Tile) bool { return tile.Bomb })
+	r.Reveal("guess0", 0, row, col)
+	assert.Equal(t, *r.Winner, Team(1))
+	assert.DeepEqual(t, r.Wins, []int{0, 1})
+
+	assert.NilError(t, r.Undo(r.Host))
+	assert.Assert(t, r.Winner == nil)
+	assert.DeepEqual(t, r.Wins, []int{0, 0})
+	assert.Assert(t, !r.Board.Get(row, col).Revealed)
+	assert.Equal(t, r.Turn, Team(0))
+
+	// The game goes on.
+	row, col = findTile(t, r, teamTile(0))
+	r.Reveal("guess0", 0, row, col)
+	assert.Assert(t, r.Board.Get(row, col).Revealed)
+}
+
+func TestUndoLastWord(t *testing.T) {
+	r := newTestRoom(t)
+	r.Board.WordCounts[0] = 1
+
+	row, col := findTile(t, r, teamTile(0))
+	r.Reveal("guess0", 0, row, col)
+	assert.Equal(t, *r.Winner, Team(0))
+
+	assert.NilError(t, r.Undo(r.Host))
+	assert.Assert(t, r.Winner == nil)
+	assert.DeepEqual(t, r.Wins, []int{0, 0})
+	assert.Equal(t, r.Board.WordCounts[0], 1)
+}
+
+func TestUndoHostOnly(t *testing.T) {
+	r := newTestRoom(t)
+
+	row, col := findTile(t, r, teamTile(0))
+	r.Reveal("guess0", 0, row, col)
+
+	version := r.Version
+	assert.NilError(t, r.Undo("guess1"))
+	assert.Equal(t, r.Version, version)
+	assert.Assert(t, r.Board.Get(row, col).Revealed)
+	assert.Assert(t, r.CanUndo())
+}
+
+func TestUndoForgotten(t *testing.T) {
+	plays := map[string]func(r *Room){
+		"clue":    func(r *Room) { assert.NilError(t, r.GiveClue("spy0", 0, "ANIMAL", 1)) },
+		"endTurn": func(r *Room) { r.EndTurn("guess0") },
+		"newGame": func(r *Room) { r.NewGame() },
+	}
+
+	for name, play := range plays {
+		play := play
+		t.Run(name, func(t *testing.T) {
+			r := newTestRoom(t)
+			row, col := findTile(t, r, teamTile(0))
+			r.Reveal("guess0", 0, row, col)
+
+			play(r)
+			assert.Assert(t, !r.CanUndo())
+
+			var gErr *Error
+			assert.Assert(t, errors.As(r.Undo(r.Host), &gErr))
+			assert.Equal(t, gErr.Code, "nothingToUndo")
+		})
+	}
+}
@@ -0,0 +1,344 @@
+// Package gamefeed appends a line to a file for every finished game, for
+// operators who want to archive games without the analytics events.
+//
+// Lines never contain words, clues, room names, nicknames, or chat. Teams are
+// identified by a salted hash of their players' IDs, so the same players can
+// be followed across games on one install, but not tied to anyone.
+package gamefeed
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/analytics"
+	"go.uber.org/atomic"
+)
+
+// SchemaVersion is the version of the Entry schema. It's written with every
+// entry, and increases with any change which isn't only an added field.
+const SchemaVersion = 1
+
+// Outcome is how a game was won.
+type Outcome string
+
+const (
+	Words    = Outcome("words")    // The winner found all their words.
+	Bomb     = Outcome("bomb")     // The other team revealed the bomb.
+	Forfeit  = Outcome("forfeit")  // The other team let too many turns expire.
+	Declared = Outcome("declared") // The host declared the winner of a tracker game.
+)
+
+func (o Outcome) valid() bool {
+	switch o {
+	case Words, Bomb, Forfeit, Declared:
+		return true
+	default:
+		return false
+	}
+}
+
+// Entry is one line of a feed file.
+type Entry struct {
+	Schema int       `json:"v"`
+	Time   time.Time `json:"time"` // When the game ended, in UTC.
+
+	Ruleset string `json:"ruleset"`
+	Seed    int    `json:"seed"`
+
+	// Teams are the salted hashes of each team's players, in team order. A
+	// team without players has an empty hash.
+	Teams   []string `json:"teams"`
+	Winner  int      `json:"winner"`
+	Outcome Outcome  `json:"outcome"`
+
+	Seconds int64 `json:"seconds"`
+	Turns   int   `json:"turns"`
+}
+
+// Game is a finished game, as the server describes it.
+type Game struct {
+	Ruleset string
+	Seed    int
+	Teams   [][]string // Player IDs, by team; they're hashed before writing.
+	Winner  int
+	Outcome Outcome
+	Length  time.Duration
+	Turns   int
+}
+
+// SyncPolicy is when the feed file is synced to disk.
+type SyncPolicy string
+
+const (
+	SyncAlways = SyncPolicy("always") // After every entry.
+	SyncRotate = SyncPolicy("rotate") // Before each rotation, and on close.
+	SyncNever  = SyncPolicy("never")  // Left to the OS.
+)
+
+// ParseSyncPolicy parses a sync policy, as given on the command line.
+func ParseSyncPolicy(s string) (SyncPolicy, error) {
+	switch p := SyncPolicy(s); p {
+	case SyncAlways, SyncRotate, SyncNever:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown sync policy %q", s)
+	}
+}
+
+// Options configure a feed.
+type Options struct {
+	// MaxBytes rotates the file before an entry would grow it past this
+	// size. Zero never rotates.
+	MaxBytes int64
+
+	// Keep is the number of rotated files kept, as path.1 (the newest)
+	// through path.Keep. Older files are removed.
+	Keep int
+
+	Sync SyncPolicy
+
+	// Buffer is the number of entries waiting to be written before further
+	// entries are dropped.
+	Buffer int
+}
+
+// Feed appends entries to a file, from its own goroutine, so that recording a
+// game never waits on the disk. A nil Feed records nothing.
+type Feed struct {
+	path string
+	opts Options
+	salt []byte
+	now  func() time.Time
+
+	mu      sync.RWMutex // Held for writing only to close entries.
+	closed  bool
+	entries chan *Entry
+	done    chan struct{}
+	dropped atomic.Int64
+
+	// Only used by the writer goroutine, until done is closed.
+	f    *os.File
+	size int64
+	err  error // The first write error.
+}
+
+// Open opens the feed file at path for appending, creating it if needed, and
+// starts writing to it. The install's salt is read from path + ".salt", and
+// created there the first time.
+func Open(path string, opts Options) (*Feed, error) {
+	f, err := open(path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	go f.run()
+	return f, nil
+}
+
+func open(path string, opts Options) (*Feed, error) {
+	salt, err := analytics.LoadSalt(path + ".salt")
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &Feed{
+		path:    path,
+		opts:    opts,
+		salt:    salt,
+		now:     time.Now,
+		entries: make(chan *Entry, opts.Buffer),
+		done:    make(chan struct{}),
+	}
+
+	if err := feed.openFile(); err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// Record queues an entry for the finished game. If the queue is full, the
+// entry is dropped and counted.
+func (f *Feed) Record(g Game) {
+	if f == nil {
+		return
+	}
+
+	e := &Entry{
+		Schema:  SchemaVersion,
+		Time:    f.now().UTC(),
+		Ruleset: g.Ruleset,
+		Seed:    g.Seed,
+		Teams:   make([]string, len(g.Teams)),
+		Winner:  g.Winner,
+		Outcome: g.Outcome,
+		Seconds: int64(g.Length / time.Second),
+		Turns:   g.Turns,
+	}
+	for i, players := range g.Teams {
+		e.Teams[i] = f.hashTeam(players)
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.closed {
+		return
+	}
+
+	select {
+	case f.entries <- e:
+	default:
+		f.dropped.Inc()
+	}
+}
+
+// Dropped returns the number of entries dropped because the queue was full.
+func (f *Feed) Dropped() int64 {
+	if f == nil {
+		return 0
+	}
+	return f.dropped.Load()
+}
+
+// Close writes the queued entries and closes the file, returning the first
+// error encountered while writing, if any.
+func (f *Feed) Close() error {
+	if f == nil {
+		return nil
+	}
+
+	f.mu.Lock()
+	if !f.closed {
+		f.closed = true
+		close(f.entries)
+	}
+	f.mu.Unlock()
+
+	<-f.done
+	return f.err
+}
+
+// hashTeam hashes a team's player IDs, in any order, with the install's salt.
+func (f *Feed) hashTeam(players []string) string {
+	if len(players) == 0 {
+		return ""
+	}
+
+	sorted := append([]string(nil), players...)
+	sort.Strings(sorted)
+
+	mac := hmac.New(sha256.New, f.salt)
+	mac.Write([]byte(strings.Join(sorted, "\n"))) //nolint:errcheck
+	return hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+func (f *Feed) run() {
+	defer close(f.done)
+
+	for e := range f.entries {
+		f.write(e)
+	}
+
+	if f.opts.Sync != SyncNever {
+		f.setErr(f.f.Sync())
+	}
+	f.setErr(f.f.Close())
+}
+
+func (f *Feed) write(e *Entry) {
+	if f.err != nil {
+		return
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		f.setErr(err)
+		return
+	}
+	b = append(b, '\n')
+
+	if max := f.opts.MaxBytes; max > 0 && f.size > 0 && f.size+int64(len(b)) > max {
+		if err := f.rotate(); err != nil {
+			f.setErr(err)
+			return
+		}
+	}
+
+	n, err := f.f.Write(b)
+	f.size += int64(n)
+	if err != nil {
+		f.setErr(err)
+		return
+	}
+
+	if f.opts.Sync == SyncAlways {
+		f.setErr(f.f.Sync())
+	}
+}
+
+// rotate moves the file to path.1, shifting the older rotated files along and
+// removing the oldest, and starts a new file.
+func (f *Feed) rotate() error {
+	if f.opts.Sync != SyncNever {
+		if err := f.f.Sync(); err != nil {
+			return err
+		}
+	}
+	if err := f.f.Close(); err != nil {
+		return err
+	}
+
+	if f.opts.Keep <= 0 {
+		if err := os.Remove(f.path); err != nil {
+			return err
+		}
+		return f.openFile()
+	}
+
+	for i := f.opts.Keep - 1; i >= 1; i-- {
+		err := os.Rename(f.rotated(i), f.rotated(i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(f.path, f.rotated(1)); err != nil {
+		return err
+	}
+	return f.openFile()
+}
+
+func (f *Feed) rotated(i int) string {
+	return f.path + "." + strconv.Itoa(i)
+}
+
+func (f *Feed) openFile() error {
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.f = file
+	f.size = info.Size()
+	return nil
+}
+
+func (f *Feed) setErr(err error) {
+	if err != nil && f.err == nil {
+		f.err = err
+	}
+}
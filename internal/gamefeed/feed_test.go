@@ -0,0 +1,220 @@
+package gamefeed
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+var testTime = time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func testGame(seed int) Game {
+	return Game{
+		Ruleset: "abcdef0123456789",
+		Seed:    seed,
+		Teams:   [][]string{{"alice", "bob"}, {"carol"}},
+		Winner:  1,
+		Outcome: Bomb,
+		Length:  90 * time.Second,
+		Turns:   4,
+	}
+}
+
+func openTest(t *testing.T, opts Options) (*Feed, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "feed.jsonl")
+	f, err := open(path, opts)
+	assert.NilError(t, err)
+	f.now = func() time.Time { return testTime }
+	return f, path
+}
+
+// readSeeds reads the seeds of the entries in a feed file, or nil if it
+// doesn't exist.
+func readSeeds(t *testing.T, path string) []int {
+	t.Helper()
+
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	assert.NilError(t, err)
+
+	var seeds []int
+	for _, e := range readEntries(t, b) {
+		seeds = append(seeds, e.Seed)
+	}
+	return seeds
+}
+
+func readEntries(t *testing.T, b []byte) []*Entry {
+	t.Helper()
+
+	var entries []*Entry
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		var e Entry
+		assert.NilError(t, json.Unmarshal(scanner.Bytes(), &e))
+		assert.NilError(t, e.Validate())
+		entries = append(entries, &e)
+	}
+	assert.NilError(t, scanner.Err())
+	return entries
+}
+
+// lineLength is the length of the line written for testGame with a one digit
+// seed.
+func lineLength(t *testing.T) int64 {
+	t.Helper()
+
+	f, path := openTest(t, Options{Buffer: 1, Sync: SyncNever})
+	go f.run()
+	f.Record(testGame(1))
+	assert.NilError(t, f.Close())
+
+	info, err := os.Stat(path)
+	assert.NilError(t, err)
+	return info.Size()
+}
+
+func TestFeedAnonymized(t *testing.T) {
+	f, path := openTest(t, Options{Buffer: 4, Sync: SyncAlways})
+	go f.run()
+
+	f.Record(testGame(7))
+	g := testGame(8)
+	g.Teams = [][]string{{"bob", "alice"}, {}}
+	f.Record(g)
+	assert.NilError(t, f.Close())
+
+	b, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	for _, id := range []string{"alice", "bob", "carol"} {
+		assert.Assert(t, !strings.Contains(string(b), id))
+	}
+
+	entries := readEntries(t, b)
+	assert.Equal(t, len(entries), 2)
+
+	e := entries[0]
+	assert.Equal(t, e.Schema, SchemaVersion)
+	assert.Assert(t, e.Time.Equal(testTime))
+	assert.Equal(t, e.Ruleset, "abcdef0123456789")
+	assert.Equal(t, e.Seed, 7)
+	assert.Equal(t, e.Winner, 1)
+	assert.Equal(t, e.Outcome, Bomb)
+	assert.Equal(t, e.Seconds, int64(90))
+	assert.Equal(t, e.Turns, 4)
+	assert.Equal(t, len(e.Teams[0]), 16)
+
+	// The same players make the same team, in any order.
+	assert.Equal(t, entries[1].Teams[0], e.Teams[0])
+	assert.Equal(t, entries[1].Teams[1], "")
+}
+
+func TestFeedRotation(t *testing.T) {
+	line := lineLength(t)
+
+	// Two lines fill a file exactly; the third starts the next.
+	f, path := openTest(t, Options{MaxBytes: 2 * line, Keep: 2, Buffer: 16, Sync: SyncRotate})
+	go f.run()
+	for seed := 1; seed <= 7; seed++ {
+		f.Record(testGame(seed))
+	}
+	assert.NilError(t, f.Close())
+
+	assert.DeepEqual(t, readSeeds(t, path), []int{7})
+	assert.DeepEqual(t, readSeeds(t, path+".1"), []int{5, 6})
+	assert.DeepEqual(t, readSeeds(t, path+".2"), []int{3, 4})
+	assert.Assert(t, readSeeds(t, path+".3") == nil)
+}
+
+func TestFeedRotationOneByteShort(t *testing.T) {
+	line := lineLength(t)
+
+	f, path := openTest(t, Options{MaxBytes: 2*line - 1, Keep: 1, Buffer: 16, Sync: SyncNever})
+	go f.run()
+	for seed := 1; seed <= 3; seed++ {
+		f.Record(testGame(seed))
+	}
+	assert.NilError(t, f.Close())
+
+	assert.DeepEqual(t, readSeeds(t, path), []int{3})
+	assert.DeepEqual(t, readSeeds(t, path+".1"), []int{2})
+}
+
+func TestFeedRotationOversizedLine(t *testing.T) {
+	// A line longer than the limit still gets written, on its own.
+	f, path := openTest(t, Options{MaxBytes: 10, Keep: 0, Buffer: 16, Sync: SyncNever})
+	go f.run()
+	f.Record(testGame(1))
+	f.Record(testGame(2))
+	assert.NilError(t, f.Close())
+
+	assert.DeepEqual(t, readSeeds(t, path), []int{2})
+	assert.Assert(t, readSeeds(t, path+".1") == nil)
+}
+
+func TestFeedRotationResumes(t *testing.T) {
+	line := lineLength(t)
+
+	f, path := openTest(t, Options{MaxBytes: 2 * line, Keep: 1, Buffer: 16, Sync: SyncNever})
+	go f.run()
+	f.Record(testGame(1))
+	assert.NilError(t, f.Close())
+
+	// The size of the existing file counts toward the limit.
+	f, err := open(path, f.opts)
+	assert.NilError(t, err)
+	f.now = func() time.Time { return testTime }
+	go f.run()
+	f.Record(testGame(2))
+	f.Record(testGame(3))
+	assert.NilError(t, f.Close())
+
+	assert.DeepEqual(t, readSeeds(t, path), []int{3})
+	assert.DeepEqual(t, readSeeds(t, path+".1"), []int{1, 2})
+}
+
+func TestFeedOverflow(t *testing.T) {
+	// Until the writer starts, nothing leaves the queue.
+	f, path := openTest(t, Options{Buffer: 2, Sync: SyncNever})
+
+	for seed := 1; seed <= 5; seed++ {
+		f.Record(testGame(seed))
+	}
+	assert.Equal(t, f.Dropped(), int64(3))
+
+	go f.run()
+	assert.NilError(t, f.Close())
+	assert.DeepEqual(t, readSeeds(t, path), []int{1, 2})
+
+	// Games recorded after closing are ignored, not dropped.
+	f.Record(testGame(6))
+	assert.Equal(t, f.Dropped(), int64(3))
+}
+
+func TestFeedNil(t *testing.T) {
+	var f *Feed
+	f.Record(testGame(1))
+	assert.Equal(t, f.Dropped(), int64(0))
+	assert.NilError(t, f.Close())
+}
+
+func TestParseSyncPolicy(t *testing.T) {
+	p, err := ParseSyncPolicy("always")
+	assert.NilError(t, err)
+	assert.Equal(t, p, SyncAlways)
+
+	_, err = ParseSyncPolicy("sometimes")
+	assert.ErrorContains(t, err, "sometimes")
+}
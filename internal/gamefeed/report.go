@@ -0,0 +1,170 @@
+package gamefeed
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+)
+
+// maxReportedErrors bounds the invalid lines a report describes; the rest are
+// only counted.
+const maxReportedErrors = 10
+
+// Report summarizes and validates a feed file.
+type Report struct {
+	Games          int             `json:"games"`
+	Outcomes       map[Outcome]int `json:"outcomes"`
+	Wins           []int           `json:"wins"` // By team.
+	AverageSeconds float64         `json:"averageSeconds"`
+	AverageTurns   float64         `json:"averageTurns"`
+
+	// Rulesets counts the games played with each ruleset, most played first.
+	Rulesets []*RulesetCount `json:"rulesets"`
+
+	// Invalid counts the lines which couldn't be read, or which aren't valid
+	// entries. Errors describes the first few.
+	Invalid int      `json:"invalid"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+type RulesetCount struct {
+	Ruleset string `json:"ruleset"`
+	Games   int    `json:"games"`
+}
+
+// Summarize reads entries, one per line, validating and summarizing them.
+func Summarize(r io.Reader) (*Report, error) {
+	report := &Report{
+		Outcomes: make(map[Outcome]int),
+		Wins:     []int{},
+		Rulesets: []*RulesetCount{},
+	}
+
+	rulesets := make(map[string]*RulesetCount)
+	var seconds int64
+	var turns int
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var e Entry
+		err := json.Unmarshal(line, &e)
+		if err == nil {
+			err = e.Validate()
+		}
+		if err != nil {
+			report.Invalid++
+			if len(report.Errors) < maxReportedErrors {
+				report.Errors = append(report.Errors, fmt.Sprintf("line %d: %v", n, err))
+			}
+			continue
+		}
+
+		report.Games++
+		report.Outcomes[e.Outcome]++
+		for len(report.Wins) < len(e.Teams) {
+			report.Wins = append(report.Wins, 0)
+		}
+		report.Wins[e.Winner]++
+		seconds += e.Seconds
+		turns += e.Turns
+
+		c := rulesets[e.Ruleset]
+		if c == nil {
+			c = &RulesetCount{Ruleset: e.Ruleset}
+			rulesets[e.Ruleset] = c
+			report.Rulesets = append(report.Rulesets, c)
+		}
+		c.Games++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if report.Games > 0 {
+		report.AverageSeconds = float64(seconds) / float64(report.Games)
+		report.AverageTurns = float64(turns) / float64(report.Games)
+	}
+
+	sort.SliceStable(report.Rulesets, func(i, j int) bool {
+		return report.Rulesets[i].Games > report.Rulesets[j].Games
+	})
+
+	return report, nil
+}
+
+// Validate returns an error if the entry isn't one the server could have
+// written.
+func (e *Entry) Validate() error {
+	switch {
+	case e.Schema < 1 || e.Schema > SchemaVersion:
+		return fmt.Errorf("unknown schema version %d", e.Schema)
+	case e.Time.IsZero():
+		return errors.New("missing time")
+	case e.Ruleset == "":
+		return errors.New("missing ruleset")
+	case len(e.Teams) < 2:
+		return fmt.Errorf("%d teams, want at least 2", len(e.Teams))
+	case e.Winner < 0 || e.Winner >= len(e.Teams):
+		return fmt.Errorf("winner %d isn't one of the teams", e.Winner)
+	case !e.Outcome.valid():
+		return fmt.Errorf("unknown outcome %q", e.Outcome)
+	case e.Seconds < 0:
+		return errors.New("negative length")
+	case e.Turns < 1:
+		return errors.New("no turns")
+	default:
+		return nil
+	}
+}
+
+// WriteText writes the report for people to read.
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Games:\t%d\n", r.Games)
+	fmt.Fprintf(tw, "Average game length:\t%dm%02ds\n", int(r.AverageSeconds)/60, int(r.AverageSeconds)%60)
+	fmt.Fprintf(tw, "Average turns:\t%.1f\n", r.AverageTurns)
+	if r.Invalid > 0 {
+		fmt.Fprintf(tw, "Invalid lines:\t%d\n", r.Invalid)
+	}
+
+	fmt.Fprintf(tw, "\nOutcome\tGames\n")
+	for _, o := range []Outcome{Words, Bomb, Forfeit, Declared} {
+		if n := r.Outcomes[o]; n > 0 {
+			fmt.Fprintf(tw, "%s\t%d\n", o, n)
+		}
+	}
+
+	fmt.Fprintf(tw, "\nTeam\tWins\n")
+	for team, n := range r.Wins {
+		fmt.Fprintf(tw, "%d\t%d\n", team, n)
+	}
+
+	fmt.Fprintf(tw, "\nRuleset\tGames\n")
+	for _, c := range r.Rulesets {
+		ruleset := c.Ruleset
+		if len(ruleset) > 12 {
+			ruleset = ruleset[:12]
+		}
+		fmt.Fprintf(tw, "%s\t%d\n", ruleset, c.Games)
+	}
+
+	if len(r.Errors) > 0 {
+		fmt.Fprintf(tw, "\nInvalid lines\n")
+		for _, err := range r.Errors {
+			fmt.Fprintln(tw, err)
+		}
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,64 @@
+package gamefeed
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSummarize(t *testing.T) {
+	var buf bytes.Buffer
+	write := func(e *Entry) {
+		b, err := json.Marshal(e)
+		assert.NilError(t, err)
+		buf.Write(append(b, '\n'))
+	}
+
+	entry := func(ruleset string, winner int, outcome Outcome, seconds int64, turns int) *Entry {
+		return &Entry{
+			Schema:  SchemaVersion,
+			Time:    testTime,
+			Ruleset: ruleset,
+			Teams:   []string{"a", "b"},
+			Winner:  winner,
+			Outcome: outcome,
+			Seconds: seconds,
+			Turns:   turns,
+		}
+	}
+
+	write(entry("r1", 0, Words, 60, 4))
+	write(entry("r2", 1, Bomb, 120, 6))
+	write(entry("r2", 1, Forfeit, 180, 8))
+
+	buf.WriteString("not json\n\n")
+	write(entry("r1", 2, Words, 60, 4)) // No such team.
+	write(entry("r1", 0, "draw", 60, 4))
+	newer := entry("r1", 0, Words, 60, 4)
+	newer.Schema = SchemaVersion + 1
+	write(newer)
+
+	report, err := Summarize(&buf)
+	assert.NilError(t, err)
+
+	assert.Equal(t, report.Games, 3)
+	assert.DeepEqual(t, report.Outcomes, map[Outcome]int{Words: 1, Bomb: 1, Forfeit: 1})
+	assert.DeepEqual(t, report.Wins, []int{1, 2})
+	assert.Equal(t, report.AverageSeconds, 120.0)
+	assert.Equal(t, report.AverageTurns, 6.0)
+	assert.DeepEqual(t, report.Rulesets, []*RulesetCount{{Ruleset: "r2", Games: 2}, {Ruleset: "r1", Games: 1}})
+
+	assert.Equal(t, report.Invalid, 4)
+	assert.Equal(t, len(report.Errors), 4)
+	assert.Assert(t, strings.HasPrefix(report.Errors[0], "line 4: "))
+	assert.Equal(t, report.Errors[1], "line 6: winner 2 isn't one of the teams")
+	assert.Equal(t, report.Errors[2], `line 7: unknown outcome "draw"`)
+	assert.Equal(t, report.Errors[3], "line 8: unknown schema version 2")
+
+	var text bytes.Buffer
+	assert.NilError(t, report.WriteText(&text))
+	assert.Assert(t, strings.Contains(text.String(), "line 8: unknown schema version 2\n"))
+}
@@ -0,0 +1,43 @@
+// Package idgen generates identifiers which must not be guessable, like room
+// IDs and tokens. They all come from crypto/rand; nothing else in the module
+// should read randomness for identifiers.
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+)
+
+const (
+	roomIDBytes = 10 // 80 bits, 16 characters.
+	tokenBytes  = 16 // 128 bits, 32 characters.
+)
+
+var roomIDEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// RoomID returns a new room ID, which is lowercase letters and digits.
+func RoomID() string {
+	return roomIDEncoding.EncodeToString(read(roomIDBytes))
+}
+
+// Token returns a new token, as hex. Tokens are secrets which grant access,
+// like mirror tokens.
+func Token() string {
+	return hex.EncodeToString(read(tokenBytes))
+}
+
+// Seed returns a seed for a math/rand source. Game randomness needn't be
+// unpredictable, but it shouldn't be shared between rooms either.
+func Seed() int64 {
+	return int64(binary.LittleEndian.Uint64(read(8)) >> 1)
+}
+
+func read(n int) []byte {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return b
+}
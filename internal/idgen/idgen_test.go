@@ -0,0 +1,113 @@
+package idgen
+
+import (
+	"go/parser"
+	"go/token"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRoomID(t *testing.T) {
+	valid := regexp.MustCompile(`^[a-z2-7]{16}$`)
+	seen := make(map[string]bool)
+
+	for i := 0; i < 10000; i++ {
+		id := RoomID()
+		assert.Assert(t, valid.MatchString(id), "invalid room ID %q", id)
+		assert.Assert(t, !seen[id], "duplicate room ID %q", id)
+		seen[id] = true
+	}
+}
+
+func TestToken(t *testing.T) {
+	const n = 10000
+	var ones [tokenBytes * 8]int
+
+	for i := 0; i < n; i++ {
+		token := Token()
+		assert.Equal(t, len(token), tokenBytes*2)
+
+		b, err := strconv.ParseUint(token[:16], 16, 64)
+		assert.NilError(t, err)
+		for j := 0; j < 64; j++ {
+			ones[j] += int(b >> j & 1)
+		}
+		b, err = strconv.ParseUint(token[16:], 16, 64)
+		assert.NilError(t, err)
+		for j := 0; j < 64; j++ {
+			ones[64+j] += int(b >> j & 1)
+		}
+	}
+
+	// Every bit should be set about half the time; a stuck or heavily biased
+	// bit is far outside this.
+	for i, count := range ones {
+		assert.Assert(t, count > n*45/100 && count < n*55/100, "bit %d set %d of %d times", i, count, n)
+	}
+}
+
+func TestSeed(t *testing.T) {
+	var or uint64
+	for i := 0; i < 100; i++ {
+		seed := Seed()
+		assert.Assert(t, seed >= 0)
+		or |= uint64(seed)
+	}
+	assert.Equal(t, bits.OnesCount64(or), 63)
+}
+
+// allowedRand are the only places randomness may be read; everything else
+// must go through this package, or through the game's per-room Rand.
+var allowedRand = map[string]bool{
+	"internal/idgen/idgen.go": true,
+	"internal/game/rand.go":   true,
+}
+
+func TestRandImports(t *testing.T) {
+	root := filepath.Join("..", "..")
+	fset := token.NewFileSet()
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if name := info.Name(); name == "node_modules" || (strings.HasPrefix(name, ".") && path != root) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		// Tests use fixed seeds on purpose.
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		f, err := parser.ParseFile(fset, path, nil, parser.ImportsOnly)
+		if err != nil {
+			return err
+		}
+
+		for _, imp := range f.Imports {
+			p, _ := strconv.Unquote(imp.Path.Value)
+			if p == "math/rand" || p == "crypto/rand" {
+				assert.Assert(t, allowedRand[rel], "%s imports %s; use idgen or game.NewRand", rel, p)
+			}
+		}
+		return nil
+	})
+	assert.NilError(t, err)
+}
@@ -0,0 +1,101 @@
+// Package names compares user-provided names.
+package names
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// confusables maps runes to a common lookalike. This is intentionally not the
+// full Unicode confusables table; it covers the lookalikes that are easy to
+// type or paste into a nickname field.
+var confusables = map[rune]rune{
+	// Digits and symbols
+	'0': 'o',
+	'1': 'l',
+	'3': 'e',
+	'4': 'a',
+	'5': 's',
+	'7': 't',
+	'8': 'b',
+	'|': 'l',
+	'!': 'l',
+	'@': 'a',
+	'$': 's',
+
+	// Latin lookalikes
+	'i': 'l',
+	'ı': 'l',
+	'ł': 'l',
+	'ø': 'o',
+	'đ': 'd',
+	'ħ': 'h',
+
+	// Cyrillic
+	'а': 'a',
+	'в': 'b',
+	'е': 'e',
+	'ё': 'e',
+	'і': 'l',
+	'ј': 'j',
+	'к': 'k',
+	'м': 'm',
+	'н': 'h',
+	'о': 'o',
+	'р': 'p',
+	'с': 'c',
+	'т': 't',
+	'у': 'y',
+	'х': 'x',
+	'ѕ': 's',
+	'ԁ': 'd',
+
+	// Greek
+	'α': 'a',
+	'β': 'b',
+	'ε': 'e',
+	'η': 'n',
+	'ι': 'l',
+	'κ': 'k',
+	'ν': 'v',
+	'ο': 'o',
+	'ρ': 'p',
+	'τ': 't',
+	'υ': 'u',
+	'χ': 'x',
+}
+
+// Skeleton returns a form of s in which names which look alike are equal. Case,
+// accents, spacing, punctuation, and common homoglyphs are ignored. The result
+// is only meant for comparison with other skeletons.
+func Skeleton(s string) string {
+	var b strings.Builder
+
+	for _, r := range norm.NFKD.String(s) {
+		if unicode.Is(unicode.Mn, r) || unicode.IsSpace(r) || unicode.IsControl(r) || unicode.In(r, unicode.Cf) {
+			continue
+		}
+
+		r = unicode.ToLower(r)
+
+		if c, ok := confusables[r]; ok {
+			r = c
+		}
+
+		if unicode.IsPunct(r) {
+			continue
+		}
+
+		b.WriteRune(r)
+	}
+
+	// "rn" and "m" are only distinguishable with a careful eye.
+	return strings.ReplaceAll(b.String(), "rn", "m")
+}
+
+// Confusable returns true if a and b look alike.
+func Confusable(a, b string) bool {
+	return Skeleton(a) == Skeleton(b)
+}
@@ -0,0 +1,35 @@
+package names_test
+
+import (
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/names"
+	"gotest.tools/v3/assert"
+)
+
+func TestConfusable(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"Alex", "Alex", true},
+		{"Alex", "alex", true},
+		{"Alex", "A l e x", true},
+		{"Alex", "Аlex", true}, // Cyrillic A
+		{"Alex", "A1ex", true},
+		{"Alex", "Ålex", true},
+		{"Alex", "Ａｌｅｘ", true}, // Fullwidth
+		{"Alex", "Alex.", true},
+		{"Alex", "Al​ex", true}, // Zero width space
+		{"Sam", "5am", true},
+		{"Bill", "B|ll", true},
+		{"Emma", "Ernma", true},
+		{"Alex", "Alexa", false},
+		{"Alex", "Sam", false},
+		{"Bob", "Rob", false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, names.Confusable(test.a, test.b), test.want, "%q vs %q", test.a, test.b)
+	}
+}
@@ -0,0 +1,55 @@
+// Package netguard keeps requests the server makes on behalf of its users, to
+// URLs they give, from reaching the server's own network.
+package netguard
+
+import (
+	"errors"
+	"net"
+	"syscall"
+)
+
+// ErrPrivate is returned by Control for addresses which aren't public.
+var ErrPrivate = errors.New("netguard: address is not public")
+
+var privateNets = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8",      // "This network", which some systems route to themselves.
+		"10.0.0.0/8",     // Private.
+		"100.64.0.0/10",  // Carrier-grade NAT.
+		"172.16.0.0/12",  // Private.
+		"192.168.0.0/16", // Private.
+		"fc00::/7",       // Unique local.
+		"64:ff9b::/96",   // NAT64, which reaches whatever IPv4 address is inside.
+	} {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}()
+
+// Control is a net.Dialer Control function which refuses connections to
+// loopback, link-local, and private addresses. It's checked as each connection
+// is made, after name resolution, so a name can't resolve to a public address
+// for one check and a private one for the connection.
+func Control(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() || ip.IsMulticast() {
+		return ErrPrivate
+	}
+
+	for _, n := range privateNets {
+		if n.Contains(ip) {
+			return ErrPrivate
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package netguard
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestControl(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		public  bool
+	}{
+		{"loopback", "127.0.0.1:80", false},
+		{"loopbackRange", "127.8.9.10:80", false},
+		{"loopback6", "[::1]:80", false},
+		{"thisNetwork", "0.1.2.3:80", false},
+		{"unspecified", "0.0.0.0:80", false},
+		{"unspecified6", "[::]:80", false},
+		{"private10", "10.1.2.3:80", false},
+		{"private172", "172.16.0.1:80", false},
+		{"private172End", "172.31.255.255:80", false},
+		{"private192", "192.168.1.1:80", false},
+		{"cgnat", "100.64.0.1:80", false},
+		{"cgnatEnd", "100.127.255.255:80", false},
+		{"linkLocal", "169.254.169.254:80", false},
+		{"linkLocal6", "[fe80::1]:80", false},
+		{"ula", "[fd00::1]:80", false},
+		{"multicast", "224.0.0.1:80", false},
+		{"multicast6", "[ff02::1]:80", false},
+		{"nat64", "[64:ff9b::a00:1]:80", false},
+		{"mapped", "[::ffff:10.0.0.1]:80", false},
+		{"name", "example.com:80", false},
+		{"public", "93.184.216.34:443", true},
+		{"publicNextToPrivate", "172.32.0.1:443", true},
+		{"publicNextToCGNAT", "100.128.0.1:443", true},
+		{"public6", "[2606:2800:220:1:248:1893:25c8:1946]:443", true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			err := Control("tcp", test.address, nil)
+			if test.public {
+				assert.NilError(t, err)
+			} else {
+				assert.Equal(t, err, ErrPrivate)
+			}
+		})
+	}
+}
+
+func TestControlBadAddress(t *testing.T) {
+	err := Control("tcp", "10.0.0.1", nil)
+	var addrErr *net.AddrError
+	assert.Assert(t, errors.As(err, &addrErr))
+}
@@ -0,0 +1,80 @@
+package packs
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func writeBuiltin(t *testing.T, dir, path string, n int) {
+	t.Helper()
+
+	words := make([]string, n)
+	for i := range words {
+		words[i] = "word" + strconv.Itoa(i)
+	}
+
+	path = filepath.Join(dir, filepath.FromSlash(path))
+	assert.NilError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	assert.NilError(t, ioutil.WriteFile(path, []byte(strings.Join(words, "\n")), 0o600))
+}
+
+func TestLoadBuiltin(t *testing.T) {
+	dir := t.TempDir()
+	writeBuiltin(t, dir, "de/Zoo.txt", 30)
+	writeBuiltin(t, dir, "en/Base.txt", 30)
+	writeBuiltin(t, dir, "en/Animals.txt", 30)
+	writeBuiltin(t, dir, "README.md", 1)
+
+	packs, err := loadBuiltin(http.Dir(dir))
+	assert.NilError(t, err)
+
+	var got []string
+	for _, p := range packs {
+		got = append(got, p.Language+"/"+p.Name)
+	}
+	assert.DeepEqual(t, got, []string{"en/Animals", "en/Base", "de/Zoo"})
+}
+
+func TestLoadBuiltinMalformed(t *testing.T) {
+	tests := map[string]func(t *testing.T, dir string){
+		"too few words": func(t *testing.T, dir string) {
+			writeBuiltin(t, dir, "de/Small.txt", 3)
+		},
+		"invalid utf8": func(t *testing.T, dir string) {
+			assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "en", "Bad.txt"), []byte{0xff, 0xfe, '\n'}, 0o600))
+		},
+		"duplicate name": func(t *testing.T, dir string) {
+			writeBuiltin(t, dir, "de/base.txt", 30)
+		},
+		"not a language": func(t *testing.T, dir string) {
+			writeBuiltin(t, dir, "English/Other.txt", 30)
+		},
+		"not a pack": func(t *testing.T, dir string) {
+			writeBuiltin(t, dir, "en/notes.md", 30)
+		},
+	}
+
+	for name, breakDir := range tests {
+		breakDir := breakDir
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeBuiltin(t, dir, "en/Base.txt", 30)
+			breakDir(t, dir)
+
+			_, err := loadBuiltin(http.Dir(dir))
+			assert.Assert(t, err != nil)
+		})
+	}
+}
+
+func TestBuiltinValid(t *testing.T) {
+	assert.NilError(t, errBuiltin)
+	assert.Equal(t, builtinPacks[0].Name, "Base")
+}
@@ -0,0 +1,250 @@
+// Package packs manages the set of built-in word packs.
+package packs
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"github.com/zikaeroh/codies/internal/words"
+	"github.com/zikaeroh/codies/internal/words/static"
+)
+
+// MinWords is the minimum number of words in a pack.
+const MinWords = 25
+
+// Pack is a named, immutable word list.
+type Pack struct {
+	Name     string
+	Language string // The code of the pack's language, if known.
+	List     words.List
+}
+
+// The built-in packs are loaded once, as the process starts; NewRegistry
+// fails if any of them is malformed.
+var builtinPacks, errBuiltin = loadBuiltin(static.Dir)
+
+func builtin() []*Pack {
+	return append([]*Pack(nil), builtinPacks...)
+}
+
+// validLanguage matches the language codes built-in packs are organized by.
+var validLanguage = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// loadBuiltin loads the packs in dir, which holds a directory for each
+// language, named by its code, of packs. The default language's packs come
+// first, so that its first pack is the one rooms enable; the rest follow by
+// language. Every pack must be valid, and no two may share a name.
+func loadBuiltin(dir http.FileSystem) ([]*Pack, error) {
+	langs, err := readDir(dir, "/")
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(langs, func(i, j int) bool {
+		return langs[i].Name() == static.DefaultLanguage && langs[j].Name() != static.DefaultLanguage
+	})
+
+	var packs []*Pack
+	seen := make(map[string]bool)
+
+	for _, lang := range langs {
+		if !lang.IsDir() {
+			continue
+		}
+
+		code := lang.Name()
+		if !validLanguage.MatchString(code) {
+			return nil, fmt.Errorf("packs: built-in directory %q isn't a language code", code)
+		}
+
+		files, err := readDir(dir, "/"+code)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			path := "/" + code + "/" + file.Name()
+			if file.IsDir() || filepath.Ext(path) != ".txt" {
+				return nil, fmt.Errorf("packs: built-in %s isn't a pack", path)
+			}
+
+			b, err := readFile(dir, path)
+			if err != nil {
+				return nil, err
+			}
+
+			p, err := parsePack(path, b)
+			if err != nil {
+				return nil, err
+			}
+			p.Language = code
+
+			key := strings.ToLower(p.Name)
+			if seen[key] {
+				return nil, fmt.Errorf("packs: duplicate pack name %q", p.Name)
+			}
+			seen[key] = true
+
+			packs = append(packs, p)
+		}
+	}
+
+	return packs, nil
+}
+
+// readDir lists a directory, sorted by name.
+func readDir(dir http.FileSystem, path string) ([]os.FileInfo, error) {
+	f, err := dir.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	infos, err := f.Readdir(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name() < infos[j].Name() })
+	return infos, nil
+}
+
+func readFile(dir http.FileSystem, path string) ([]byte, error) {
+	f, err := dir.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ioutil.ReadAll(f)
+}
+
+// Registry holds the current set of built-in packs, which are the static packs
+// plus those loaded from an optional directory. The set is replaced as a
+// whole, so readers never see a partially loaded set. Packs aren't modified
+// once loaded, so neither reading the set nor dealing from its packs takes a
+// lock, however many rooms do so at once.
+//
+// Rooms take their own copy of the packs when they are created, so a pack
+// removed from the directory remains available to the rooms which already
+// had it.
+type Registry struct {
+	dir     string
+	current atomic.Value // []*Pack
+}
+
+// NewRegistry creates a registry, loading packs from dir if it is non-empty.
+// Packs loaded from dir have no language.
+func NewRegistry(dir string) (*Registry, error) {
+	if errBuiltin != nil {
+		return nil, errBuiltin
+	}
+
+	r := &Registry{dir: dir}
+	r.current.Store(builtin())
+
+	if dir != "" {
+		if err := r.Reload(); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// Packs returns the current set of packs. The returned slice must not be
+// modified.
+func (r *Registry) Packs() []*Pack {
+	if r == nil {
+		return builtin()
+	}
+	return r.current.Load().([]*Pack)
+}
+
+// Reload reloads the pack directory. Either every pack is loaded and the set
+// is replaced, or an error is returned and the previous set remains.
+func (r *Registry) Reload() error {
+	if r.dir == "" {
+		return nil
+	}
+
+	loaded, err := loadDir(r.dir)
+	if err != nil {
+		return err
+	}
+
+	candidate := builtin()
+	seen := make(map[string]bool, len(candidate)+len(loaded))
+	for _, p := range candidate {
+		seen[strings.ToLower(p.Name)] = true
+	}
+
+	for _, p := range loaded {
+		key := strings.ToLower(p.Name)
+		if seen[key] {
+			return fmt.Errorf("packs: duplicate pack name %q", p.Name)
+		}
+		seen[key] = true
+		candidate = append(candidate, p)
+	}
+
+	r.current.Store(candidate)
+	return nil
+}
+
+func loadDir(dir string) ([]*Pack, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	packs := make([]*Pack, 0, len(paths))
+
+	for _, path := range paths {
+		p, err := loadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		packs = append(packs, p)
+	}
+
+	return packs, nil
+}
+
+func loadFile(path string) (*Pack, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePack(path, b)
+}
+
+// parsePack parses the pack in the file at path, named by the file.
+func parsePack(path string, b []byte) (*Pack, error) {
+	if !utf8.Valid(b) {
+		return nil, fmt.Errorf("packs: %s is not valid UTF-8", path)
+	}
+
+	list := words.NewListFromLines(bytes.NewReader(b))
+	if list.Len() < MinWords {
+		return nil, fmt.Errorf("packs: %s has %d words, need at least %d", path, list.Len(), MinWords)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	return &Pack{
+		Name: name,
+		List: list,
+	}, nil
+}
@@ -0,0 +1,170 @@
+package packs_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/packs"
+	"gotest.tools/v3/assert"
+)
+
+func writePack(t *testing.T, dir, name string, n int) {
+	t.Helper()
+
+	words := make([]string, n)
+	for i := range words {
+		words[i] = name + strconv.Itoa(i)
+	}
+
+	err := ioutil.WriteFile(filepath.Join(dir, name+".txt"), []byte(strings.Join(words, "\n")), 0o600)
+	assert.NilError(t, err)
+}
+
+// builtinNames are the built-in packs, which come before any others.
+var builtinNames = []string{"Base", "Duet", "Undercover", "Deutsch", "Español", "Français"}
+
+func withBuiltin(names ...string) []string {
+	return append(append([]string(nil), builtinNames...), names...)
+}
+
+func names(reg *packs.Registry) []string {
+	var names []string
+	for _, p := range reg.Packs() {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func TestRegistryStatic(t *testing.T) {
+	reg, err := packs.NewRegistry("")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, names(reg), builtinNames)
+	assert.NilError(t, reg.Reload())
+
+	var nilReg *packs.Registry
+	assert.Equal(t, len(nilReg.Packs()), len(builtinNames))
+}
+
+func TestRegistryLoad(t *testing.T) {
+	dir := t.TempDir()
+	writePack(t, dir, "animals", 30)
+	writePack(t, dir, "food", 25)
+
+	reg, err := packs.NewRegistry(dir)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, names(reg), withBuiltin("animals", "food"))
+}
+
+func TestRegistryBrokenFiles(t *testing.T) {
+	tests := map[string]func(t *testing.T, dir string){
+		"too few words": func(t *testing.T, dir string) {
+			writePack(t, dir, "small", 3)
+		},
+		"invalid utf8": func(t *testing.T, dir string) {
+			assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "bad.txt"), []byte{0xff, 0xfe, '\n'}, 0o600))
+		},
+		"duplicate name": func(t *testing.T, dir string) {
+			writePack(t, dir, "base", 30)
+		},
+		"unreadable": func(t *testing.T, dir string) {
+			assert.NilError(t, os.Mkdir(filepath.Join(dir, "dir.txt"), 0o700))
+		},
+	}
+
+	for name, breakDir := range tests {
+		breakDir := breakDir
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writePack(t, dir, "animals", 30)
+
+			reg, err := packs.NewRegistry(dir)
+			assert.NilError(t, err)
+			before := reg.Packs()
+
+			breakDir(t, dir)
+			assert.Assert(t, reg.Reload() != nil)
+			assert.DeepEqual(t, names(reg), withBuiltin("animals"))
+			assert.Assert(t, reg.Packs()[len(builtinNames)] == before[len(builtinNames)])
+
+			_, err = packs.NewRegistry(dir)
+			assert.Assert(t, err != nil)
+		})
+	}
+}
+
+func TestRegistryRemovedPackStillReferenced(t *testing.T) {
+	dir := t.TempDir()
+	writePack(t, dir, "animals", 30)
+
+	reg, err := packs.NewRegistry(dir)
+	assert.NilError(t, err)
+
+	held := reg.Packs()
+
+	assert.NilError(t, os.Remove(filepath.Join(dir, "animals.txt")))
+	assert.NilError(t, reg.Reload())
+	assert.DeepEqual(t, names(reg), builtinNames)
+
+	// Anything which took the old set keeps a usable pack.
+	animals := held[len(builtinNames)]
+	assert.Equal(t, animals.Name, "animals")
+	assert.Equal(t, animals.List.Len(), 30)
+	assert.Equal(t, animals.List.Get(29), "ANIMALS29")
+}
+
+func TestRegistryConcurrentReload(t *testing.T) {
+	dir := t.TempDir()
+	writePack(t, dir, "animals", 30)
+
+	reg, err := packs.NewRegistry(dir)
+	assert.NilError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				for _, p := range reg.Packs() {
+					_ = p.List.Get(p.List.Len() - 1)
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 20; i++ {
+		writePack(t, dir, "food"+strconv.Itoa(i), 25)
+		assert.NilError(t, reg.Reload())
+	}
+
+	wg.Wait()
+	assert.Equal(t, len(reg.Packs()), len(builtinNames)+21)
+}
+
+func TestRegistryLanguages(t *testing.T) {
+	dir := t.TempDir()
+	writePack(t, dir, "animals", 30)
+
+	reg, err := packs.NewRegistry(dir)
+	assert.NilError(t, err)
+
+	languages := make(map[string]string)
+	for _, p := range reg.Packs() {
+		languages[p.Name] = p.Language
+	}
+
+	assert.DeepEqual(t, languages, map[string]string{
+		"Base":       "en",
+		"Duet":       "en",
+		"Undercover": "en",
+		"Deutsch":    "de",
+		"Español":    "es",
+		"Français":   "fr",
+		"animals":    "",
+	})
+}
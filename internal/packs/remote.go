@@ -0,0 +1,206 @@
+package packs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/zikaeroh/codies/internal/netguard"
+	"golang.org/x/sync/singleflight"
+)
+
+// Remote packs are plain text files, one word per line, fetched over HTTP.
+// Fetches are cached by URL, so rooms sharing a pack fetch it once.
+const (
+	RemoteMaxBytes = 256 << 10
+
+	remoteTimeout   = 10 * time.Second
+	remoteCacheTTL  = time.Hour
+	maxRemoteCached = 100
+)
+
+var (
+	ErrRemoteURL     = errors.New("packs: remote pack URL must be http or https")
+	ErrRemoteAddress = errors.New("packs: remote pack address is not public")
+	ErrRemoteTimeout = errors.New("packs: remote pack fetch timed out")
+	ErrRemoteTooBig  = errors.New("packs: remote pack too large")
+	ErrRemoteNotText = errors.New("packs: remote pack is not UTF-8 text")
+)
+
+// StatusError is returned when a remote pack's server responds with anything
+// but 200 OK.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("packs: remote pack fetch returned %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Fetcher fetches remote packs. It's safe for concurrent use.
+type Fetcher struct {
+	client *http.Client
+	now    func() time.Time
+	group  singleflight.Group
+
+	mu    sync.Mutex
+	cache map[string]*remotePack
+}
+
+type remotePack struct {
+	lines   []string
+	expires time.Time
+}
+
+// NewFetcher creates a fetcher. If client is nil, one is used which times out
+// and only connects to public addresses, so that a pack's URL can't be used
+// to reach the server's own network.
+func NewFetcher(client *http.Client) *Fetcher {
+	if client == nil {
+		dialer := &net.Dialer{Timeout: remoteTimeout, Control: netguard.Control}
+		client = &http.Client{
+			Timeout: remoteTimeout,
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: remoteTimeout,
+			},
+		}
+	}
+
+	return &Fetcher{
+		client: client,
+		now:    time.Now,
+		cache:  make(map[string]*remotePack),
+	}
+}
+
+// Fetch returns the lines of the pack at rawURL, unvalidated. The returned
+// slice is shared and must not be modified.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) ([]string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrRemoteURL
+	}
+	key := u.String()
+
+	if lines, ok := f.cached(key); ok {
+		return lines, nil
+	}
+
+	// The fetch isn't tied to any one caller, so a caller giving up doesn't
+	// fail the others waiting on it.
+	ch := f.group.DoChan(key, func() (interface{}, error) {
+		lines, err := f.fetch(key)
+		if err != nil {
+			return nil, err
+		}
+		f.store(key, lines)
+		return lines, nil
+	})
+
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.([]string), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (f *Fetcher) fetch(rawURL string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, ErrRemoteURL
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fetchError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{StatusCode: resp.StatusCode}
+	}
+
+	if resp.ContentLength > RemoteMaxBytes {
+		return nil, ErrRemoteTooBig
+	}
+
+	b, err := ioutil.ReadAll(io.LimitReader(resp.Body, RemoteMaxBytes+1))
+	if err != nil {
+		return nil, fetchError(err)
+	}
+
+	if len(b) > RemoteMaxBytes {
+		return nil, ErrRemoteTooBig
+	}
+
+	if !utf8.Valid(b) {
+		return nil, ErrRemoteNotText
+	}
+
+	return strings.Split(string(b), "\n"), nil
+}
+
+func fetchError(err error) error {
+	if errors.Is(err, netguard.ErrPrivate) {
+		return ErrRemoteAddress
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return ErrRemoteTimeout
+	}
+	return err
+}
+
+func (f *Fetcher) cached(key string) ([]string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p := f.cache[key]
+	if p == nil || !f.now().Before(p.expires) {
+		return nil, false
+	}
+	return p.lines, true
+}
+
+func (f *Fetcher) store(key string, lines []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now()
+	for k, p := range f.cache {
+		if !now.Before(p.expires) {
+			delete(f.cache, k)
+		}
+	}
+
+	// Make way by dropping the pack which would expire first.
+	if len(f.cache) >= maxRemoteCached {
+		var oldest string
+		for k, p := range f.cache {
+			if oldest == "" || p.expires.Before(f.cache[oldest].expires) {
+				oldest = k
+			}
+		}
+		delete(f.cache, oldest)
+	}
+
+	f.cache[key] = &remotePack{lines: lines, expires: now.Add(remoteCacheTTL)}
+}
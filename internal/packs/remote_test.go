@@ -0,0 +1,108 @@
+package packs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/atomic"
+	"gotest.tools/v3/assert"
+)
+
+func newRemoteServer(t *testing.T) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+
+	var hits atomic.Int64
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pack.txt", func(w http.ResponseWriter, r *http.Request) {
+		hits.Inc()
+		_, _ = w.Write([]byte("apple\r\nbanana\n\ncherry\n"))
+	})
+	mux.HandleFunc("/big.txt", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("word\n", RemoteMaxBytes/5+1)))
+	})
+	mux.HandleFunc("/binary.txt", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte{0xff, 0xfe, 0xfd})
+	})
+	mux.HandleFunc("/slow.txt", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+func TestFetcherCache(t *testing.T) {
+	ctx := context.Background()
+	srv, hits := newRemoteServer(t)
+
+	f := NewFetcher(srv.Client())
+	now := time.Now()
+	f.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		lines, err := f.Fetch(ctx, srv.URL+"/pack.txt")
+		assert.NilError(t, err)
+		assert.DeepEqual(t, lines, []string{"apple\r", "banana", "", "cherry", ""})
+	}
+	assert.Equal(t, hits.Load(), int64(1))
+
+	now = now.Add(remoteCacheTTL)
+	_, err := f.Fetch(ctx, srv.URL+"/pack.txt")
+	assert.NilError(t, err)
+	assert.Equal(t, hits.Load(), int64(2))
+}
+
+func TestFetcherCacheBounded(t *testing.T) {
+	ctx := context.Background()
+	srv, _ := newRemoteServer(t)
+	f := NewFetcher(srv.Client())
+
+	for i := 0; i < maxRemoteCached+10; i++ {
+		_, err := f.Fetch(ctx, srv.URL+"/pack.txt?"+strings.Repeat("x", i))
+		assert.NilError(t, err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	assert.Equal(t, len(f.cache), maxRemoteCached)
+}
+
+func TestFetcherErrors(t *testing.T) {
+	srv, _ := newRemoteServer(t)
+
+	client := srv.Client()
+	client.Timeout = 50 * time.Millisecond
+	f := NewFetcher(client)
+
+	tests := map[string]error{
+		"ftp://example.com/pack.txt": ErrRemoteURL,
+		"/pack.txt":                  ErrRemoteURL,
+		srv.URL + "/big.txt":         ErrRemoteTooBig,
+		srv.URL + "/binary.txt":      ErrRemoteNotText,
+		srv.URL + "/slow.txt":        ErrRemoteTimeout,
+	}
+
+	for u, want := range tests {
+		_, err := f.Fetch(context.Background(), u)
+		assert.Equal(t, err, want, u)
+	}
+
+	_, err := f.Fetch(context.Background(), srv.URL+"/missing.txt")
+	assert.DeepEqual(t, err, &StatusError{StatusCode: http.StatusNotFound})
+}
+
+func TestFetcherRefusesPrivate(t *testing.T) {
+	srv, hits := newRemoteServer(t)
+
+	_, err := NewFetcher(nil).Fetch(context.Background(), srv.URL+"/pack.txt")
+	assert.Equal(t, err, ErrRemoteAddress)
+	assert.Equal(t, hits.Load(), int64(0))
+}
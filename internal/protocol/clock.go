@@ -0,0 +1,76 @@
+package protocol
+
+import "time"
+
+// clockSamples is how many of the latest round trips ClockOffset considers.
+const clockSamples = 8
+
+// ClockOffset estimates how far the server's clock is ahead of the client's,
+// from timeSync round trips, the same way NTP does. For each round trip, with
+// the client's clock as it sent the command and received the answer, and the
+// server's as it received the command and sent the answer:
+//
+//	offset = ((received - clientSent) + (sent - clientReceived)) / 2
+//	delay  = (clientReceived - clientSent) - (sent - received)
+//
+// The estimate is exact when the trip there took as long as the trip back, so
+// of the latest round trips, the one with the shortest delay (and so the least
+// room for asymmetry) is trusted. The zero value is ready to use.
+type ClockOffset struct {
+	samples [clockSamples]clockSample
+	n       int
+	next    int
+}
+
+type clockSample struct {
+	offset time.Duration
+	delay  time.Duration
+}
+
+// Add records a round trip: clientSent and clientReceived are from the
+// client's clock, and received and sent from the server's (TimeSync.Received
+// and the reply note's Sent).
+func (c *ClockOffset) Add(clientSent, received, sent, clientReceived time.Time) {
+	s := clockSample{
+		offset: (received.Sub(clientSent) + sent.Sub(clientReceived)) / 2,
+		delay:  clientReceived.Sub(clientSent) - sent.Sub(received),
+	}
+
+	c.samples[c.next] = s
+	c.next = (c.next + 1) % clockSamples
+	if c.n < clockSamples {
+		c.n++
+	}
+}
+
+// Offset returns the best estimate of how far the server's clock is ahead of
+// the client's, and the round trip delay it was measured with. It returns
+// false if there haven't been any round trips.
+func (c *ClockOffset) Offset() (offset, delay time.Duration, ok bool) {
+	if c.n == 0 {
+		return 0, 0, false
+	}
+
+	best := c.samples[0]
+	for _, s := range c.samples[1:c.n] {
+		if s.delay < best.delay {
+			best = s
+		}
+	}
+	return best.offset, best.delay, true
+}
+
+// ServerNow converts a time on the client's clock to the server's.
+func (c *ClockOffset) ServerNow(now time.Time) time.Time {
+	offset, _, _ := c.Offset()
+	return now.Add(offset)
+}
+
+// Remaining returns how long is left until a deadline set by the server, at
+// now on the client's clock. It's never negative.
+func (c *ClockOffset) Remaining(deadline, now time.Time) time.Duration {
+	if d := deadline.Sub(c.ServerNow(now)); d > 0 {
+		return d
+	}
+	return 0
+}
@@ -0,0 +1,71 @@
+package protocol
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestClockOffset(t *testing.T) {
+	var c ClockOffset
+
+	_, _, ok := c.Offset()
+	assert.Assert(t, !ok)
+
+	client := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	const ahead = 3 * time.Second
+
+	// trip simulates a round trip taking there and back each way, with the
+	// server's clock ahead of the client's.
+	trip := func(there, back time.Duration) {
+		clientSent := client
+		received := clientSent.Add(there + ahead)
+		sent := received.Add(time.Millisecond)
+		clientReceived := sent.Add(back - ahead)
+		c.Add(clientSent, received, sent, clientReceived)
+		client = clientReceived.Add(time.Second)
+	}
+
+	// A symmetric trip is exact.
+	trip(50*time.Millisecond, 50*time.Millisecond)
+	offset, delay, ok := c.Offset()
+	assert.Assert(t, ok)
+	assert.Equal(t, offset, ahead)
+	assert.Equal(t, delay, 100*time.Millisecond)
+
+	// A slower, lopsided trip is ignored in favor of the faster one.
+	trip(400*time.Millisecond, 20*time.Millisecond)
+	offset, delay, _ = c.Offset()
+	assert.Equal(t, offset, ahead)
+	assert.Equal(t, delay, 100*time.Millisecond)
+
+	// Until it's aged out.
+	for i := 0; i < clockSamples; i++ {
+		trip(100*time.Millisecond, 60*time.Millisecond)
+	}
+	offset, delay, _ = c.Offset()
+	assert.Equal(t, offset, ahead+20*time.Millisecond)
+	assert.Equal(t, delay, 160*time.Millisecond)
+}
+
+func TestClockOffsetRemaining(t *testing.T) {
+	var c ClockOffset
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.Add(now, now.Add(2*time.Second), now.Add(2*time.Second), now)
+
+	deadline := now.Add(32 * time.Second)
+	assert.Equal(t, c.ServerNow(now), now.Add(2*time.Second))
+	assert.Equal(t, c.Remaining(deadline, now), 30*time.Second)
+	assert.Equal(t, c.Remaining(deadline, now.Add(time.Minute)), time.Duration(0))
+}
+
+func TestServerNoteHasTimer(t *testing.T) {
+	timed := &RoomState{Timer: &StateTimer{TurnTime: 60}}
+
+	assert.Assert(t, (&ServerNote{Params: &State{RoomState: timed}}).HasTimer())
+	assert.Assert(t, (&ServerNote{Params: &TimeSync{}}).HasTimer())
+	assert.Assert(t, !(&ServerNote{Params: &State{RoomState: &RoomState{}}}).HasTimer())
+	assert.Assert(t, !(&ServerNote{Params: &Ack{}}).HasTimer())
+}
@@ -0,0 +1,89 @@
+package protocol
+
+import (
+	"io"
+
+	"github.com/mailru/easyjson"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// Encode encodes the state once, for every state note which carries it, so
+// that a broadcast encodes each of its states once rather than once for each
+// player. It returns the size of the encoding. The state mustn't change once
+// it's encoded.
+//
+// A state which can't be encoded is left as it is, and fails as it's written.
+func (s *RoomState) Encode() int {
+	b, err := easyjson.Marshal(s)
+	if err != nil {
+		return 0
+	}
+	s.encoded = b
+	return len(b)
+}
+
+// Unencoded returns a copy of the state without its encoding, for comparing
+// states by what they hold.
+func (s *RoomState) Unencoded() RoomState {
+	c := *s
+	c.encoded = nil
+	return c
+}
+
+// WriteTo writes the note as it's sent, a line of JSON, exactly as
+// encoding/json's Encoder would write it. A state note whose room state has
+// been encoded reuses that encoding; only the rest of the note is encoded.
+//
+// The note may be written in several pieces, so w should be a buffer rather
+// than a connection.
+func (n *ServerNote) WriteTo(w io.Writer) (int64, error) {
+	s, ok := n.Params.(*State)
+	if !ok || s.RoomState == nil || s.RoomState.encoded == nil {
+		var out jwriter.Writer
+		n.MarshalEasyJSON(&out)
+		out.RawByte('\n')
+		return dump(&out, w)
+	}
+
+	// Sync with the encoding of ServerNote and State.
+	var head jwriter.Writer
+	head.RawString(`{"method":`)
+	head.String(string(n.Method))
+	head.RawString(`,"params":{"playerID":`)
+	head.String(string(s.PlayerID))
+	head.RawString(`,"roomState":`)
+
+	var tail jwriter.Writer
+	tail.RawByte('}')
+	if n.Sent != nil {
+		tail.RawString(`,"sent":`)
+		tail.Raw(n.Sent.MarshalJSON())
+	}
+	tail.RawString("}\n")
+
+	if tail.Error != nil {
+		return 0, tail.Error
+	}
+
+	written, err := dump(&head, w)
+	if err != nil {
+		return written, err
+	}
+
+	m, err := w.Write(s.RoomState.encoded)
+	written += int64(m)
+	if err != nil {
+		return written, err
+	}
+
+	m64, err := dump(&tail, w)
+	return written + m64, err
+}
+
+func dump(out *jwriter.Writer, w io.Writer) (int64, error) {
+	if out.Error != nil {
+		return 0, out.Error
+	}
+	written, err := out.DumpTo(w)
+	return int64(written), err
+}
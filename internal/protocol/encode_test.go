@@ -0,0 +1,49 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestWriteTo(t *testing.T) {
+	notes := goldenNotes()
+
+	// Characters which encoding/json escapes.
+	escaped := goldenRoomState()
+	escaped.Teams[0][0].Nickname = "<b>& "
+	notes = append(notes, goldenNote{"escaped", stamped(NewStateNote("<p1>", escaped))})
+
+	for _, g := range notes {
+		var want bytes.Buffer
+		assert.NilError(t, json.NewEncoder(&want).Encode(g.note), g.name)
+
+		var got bytes.Buffer
+		n, err := g.note.WriteTo(&got)
+		assert.NilError(t, err, g.name)
+		assert.Equal(t, got.String(), want.String(), g.name)
+		assert.Equal(t, n, int64(got.Len()), g.name)
+
+		s, ok := g.note.Params.(*State)
+		if !ok {
+			continue
+		}
+
+		// An encoded state is written the same, by every note which carries it.
+		assert.Assert(t, s.RoomState.Encode() > 0, g.name)
+		for _, playerID := range []string{"p2", "<p1>", ""} {
+			note := NewStateNote(playerID, s.RoomState)
+			note.Sent = g.note.Sent
+
+			want.Reset()
+			assert.NilError(t, json.NewEncoder(&want).Encode(note), g.name)
+
+			got.Reset()
+			_, err := note.WriteTo(&got)
+			assert.NilError(t, err, g.name)
+			assert.Equal(t, got.String(), want.String(), g.name)
+		}
+	}
+}
@@ -0,0 +1,459 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+)
+
+// The wire format of every note the server sends is pinned by a golden file in
+// testdata/v{Version}, named after the note's method. Changing the format is a
+// two-step process: TestGolden fails until the goldens are regenerated with
+//
+//	go test ./internal/protocol -test.update-golden
+//
+// so that every wire change shows up in review. Adding fields is compatible,
+// and regenerates in place. Removing a field or changing its type isn't: the
+// goldens are only overwritten once Version has been bumped, which starts a
+// new directory and leaves the old one as a record of the previous version.
+
+var goldenTime = time.Date(2020, time.May, 1, 12, 30, 0, 0, time.UTC)
+
+func goldenPlayer(id, nickname string, spymaster, host bool) *StatePlayer {
+	return &StatePlayer{
+		PlayerID:  game.PlayerID(id),
+		Nickname:  nickname,
+		Spymaster: spymaster,
+		Color:     2,
+		Host:      host,
+	}
+}
+
+func goldenRoomState() *RoomState {
+	view := func(team game.Team, neutral, bomb bool) *StateView {
+		return &StateView{Team: team, Neutral: neutral, Bomb: bomb}
+	}
+	turnBoard := 0
+	guessesLeft := 2
+	bob := goldenPlayer("p2", "bob", false, false)
+	bob.Disconnected = true
+
+	return &RoomState{
+		Version: 12,
+		Teams: [][]*StatePlayer{
+			{goldenPlayer("p1", "alice", true, true), bob},
+			{goldenPlayer("p3", "carol", true, false)},
+		},
+		Turn: 1,
+		Wins: []int{2, 1},
+		Board: [][]*StateTile{
+			{
+				{Word: "APPLE", Revealed: true, View: view(0, false, false)},
+				{Word: "BANK", View: view(1, false, false), Fit: &StateFit{Class: "text", Length: 4}},
+			},
+			{
+				{Word: "CAT", Revealed: true, View: view(0, true, false)},
+				{Word: "DOG", View: view(0, false, true), Fit: &StateFit{Class: "text", Length: 3}},
+			},
+		},
+		WordsLeft: []int{0, 1},
+		Boards: []*StateBoard{
+			{
+				Board:     [][]*StateTile{{{Word: "APPLE", Revealed: true, View: view(0, false, false)}}},
+				WordsLeft: []int{0, 1},
+			},
+		},
+		TurnBoard:    &turnBoard,
+		BoardOptions: &StateBoardOptions{Count: 1, Rows: 5, Cols: 5, Bombs: 1},
+		Lists: []*StateWordList{
+			{Name: "Base", Language: "en", Count: 400, Enabled: true},
+			{Name: "Animals", Count: 25, Custom: true},
+		},
+		Timer:        &StateTimer{TurnTime: 60, TurnEnd: goldenTime.Add(time.Minute)},
+		MirrorDelay:  5,
+		Spectators:   3,
+		Clue:         &StateClue{Word: "FRUIT", Count: 1},
+		ClueLog:      []*StateLoggedClue{{Team: 1, Word: "TREE", Count: 2}, {Team: 0, Word: "FRUIT", Count: 1}},
+		BoundClues:   true,
+		SuggestClues: true,
+		Penalties:    &StatePenalties{Limit: 3, Counts: []int{1, 0}},
+		Hints:        &StateHints{Budget: []int{2, 2}, Left: []int{1, 2}, Used: []int{1, 0}},
+		Spymasters: &StateSpymasters{
+			Limit:        2,
+			ConfirmClues: true,
+			Pending:      &StatePendingClue{Word: "PET", Count: 2, Confirmed: []game.PlayerID{"p3"}},
+		},
+		Start:             &StateStart{MinSpymasters: 1, MinGuessers: 1},
+		EnforceGuessLimit: true,
+		GuessesLeft:       &guessesLeft,
+		BackfillWords:     true,
+		Backfill:          &StateBackfill{Pack: "Base", Language: "en", Count: 6},
+		KeyChecksum:       "3f9a",
+		Ruleset:           "b5d4c3e2",
+		BoardHash:         "a1b2c3d4",
+		Notifications: &StateNotifications{
+			Disabled: []NotificationEvent{NotifyOneCardLeft},
+		},
+	}
+}
+
+// goldenGameOverState is the golden state, once the game is over.
+func goldenGameOverState() *RoomState {
+	view := func(team game.Team, neutral, bomb bool) *StateView {
+		return &StateView{Team: team, Neutral: neutral, Bomb: bomb}
+	}
+	winner := game.Team(0)
+
+	s := goldenRoomState()
+	s.Winner = &winner
+	s.Timer = nil
+	s.Clue = nil
+	s.GuessesLeft = nil
+	s.Spymasters.Pending = nil
+	s.GameOver = &StateGameOver{
+		Winner:    0,
+		Reason:    "words",
+		Seconds:   754,
+		WordsLeft: [][]int{{0, 1}},
+		Key: [][][]*StateView{{
+			{view(0, false, false), view(1, false, false)},
+			{view(0, true, false), view(0, false, true)},
+		}},
+	}
+	return s
+}
+
+func goldenTrackerState() *RoomState {
+	return &RoomState{
+		Version: 3,
+		Teams: [][]*StatePlayer{
+			{goldenPlayer("p1", "alice", true, true)},
+			{goldenPlayer("p2", "bob", true, false)},
+		},
+		Wins:  []int{1, 0},
+		Start: &StateStart{MinSpymasters: 1, MinGuessers: 0},
+		Tracker: &StateTracker{
+			Scores: []int{4, 2},
+			Clues:  []*StateLoggedClue{{Team: 0, Word: "RIVER", Count: 2}},
+		},
+		Notifications: &StateNotifications{Off: true},
+	}
+}
+
+// stamped sets a note's send time, as the server does for notes with timers.
+func stamped(n ServerNote) ServerNote {
+	sent := goldenTime.Add(time.Second)
+	n.Sent = &sent
+	return n
+}
+
+type goldenNote struct {
+	name string
+	note ServerNote
+}
+
+// goldenNotes returns a note of every method the server sends; a method may
+// appear more than once, with the variant in the name after a dash.
+func goldenNotes() []goldenNote {
+	limit := 8
+	retryAfter := 30
+	vote := 0
+
+	return []goldenNote{
+		{"state", stamped(NewStateNote("p1", goldenRoomState()))},
+		{"state-tracker", NewStateNote("p1", goldenTrackerState())},
+		{"state-gameOver", NewStateNote("p1", goldenGameOverState())},
+		{"error", NewErrorNote(&Error{
+			Code:    "notEnoughPlayers",
+			Message: "Each team needs a spymaster.",
+			Shortfalls: []*ErrorShortfall{
+				{Team: 1, Spymaster: true, Have: 0, Need: 1},
+			},
+		})},
+		{"error-limit", NewErrorNote(&Error{
+			Code:       "tooManyPacks",
+			Message:    "Too many packs.",
+			Limit:      &limit,
+			RetryAfter: &retryAfter,
+		})},
+		{"ack", NewAckNote("c7", 12, nil)},
+		{"ack-error", NewAckNote("c8", 12, &Error{Code: "versionMismatch", Message: "The room has changed."})},
+		{"optionsChanged", NewOptionsChangedNote([]string{"hideBomb", "turnTime"})},
+		{"timeSync", stamped(NewTimeSyncNote(1588336200000, goldenTime))},
+		{"qualityDegraded", NewQualityNote(true)},
+		{"qualityRestored", NewQualityNote(false)},
+		{"playerJoined", NewPlayerJoinedNote(11, 12, "a1b2c3d4", 1, goldenPlayer("p4", "dave", false, false))},
+		{"playerLeft", NewPlayerLeftNote(11, 12, "a1b2c3d4", "p4")},
+		{"playerUpdated", NewPlayerUpdatedNote(11, 12, "a1b2c3d4", 0, goldenPlayer("p2", "bobby", false, false))},
+		{"notification", NewNotificationNote(NotifyYourTurn, 1)},
+		{"auditLog", NewAuditLogNote([]*AuditEntry{
+			{Time: goldenTime, Action: "kick", Actor: "p1", ActorName: "alice", Target: "dave"},
+			{
+				Time:    goldenTime.Add(time.Minute),
+				Action:  "updateOptions",
+				Changes: []*AuditChange{{Field: "turnTime", Before: "60", After: "90"}},
+			},
+		})},
+		{"bans", NewBansNote([]*BannedPlayer{{PlayerID: "p4", Nickname: "dave"}})},
+		{"feedbackPrompt", NewFeedbackPromptNote(3)},
+		{"clueSuggestions", NewClueSuggestionsNote([]*ClueSuggestion{
+			{Word: "FRUIT", Count: 2},
+			{Board: 1, Word: "PET", Count: 1},
+		})},
+		{"mirrorToken", NewMirrorTokenNote("mirror-token")},
+		{"webhook", NewWebhookNote("https://example.com/hook")},
+		{"chat", NewChatNote([]*ChatMessage{
+			{PlayerID: "p1", Nickname: "alice", Text: "good luck!", Time: goldenTime},
+			{PlayerID: "p2", Nickname: "bob", Text: "you too", Time: goldenTime.Add(time.Second)},
+		})},
+		{"poll", NewPollNote(&Poll{
+			ID:       1,
+			Question: "Rematch?",
+			Options:  []string{"yes", "no"},
+			Opener:   "p1",
+			Closes:   goldenTime.Add(time.Minute),
+			Votes:    3,
+			Tallies:  []int{2, 1},
+			Vote:     &vote,
+		})},
+		{"mergeRequest", NewMergeRequestNote("merge-id", "friday game", 4)},
+		{"merged", NewMergedNote("room-id", "seat-token")},
+		{"resumeToken", NewResumeTokenNote("resume-token")},
+		{"debugInfo", NewDebugInfoNote("p1", 2048, 512)},
+		{"bandwidth", NewBandwidthNote(2048, 512)},
+	}
+}
+
+func goldenDir(version int) string {
+	return "v" + strconv.Itoa(version)
+}
+
+// encodeGolden encodes a note as it's sent, indented for review.
+func encodeGolden(t *testing.T, note ServerNote) []byte {
+	t.Helper()
+
+	b, err := json.Marshal(note)
+	assert.NilError(t, err)
+
+	var buf bytes.Buffer
+	assert.NilError(t, json.Indent(&buf, b, "", "\t"))
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
+
+func TestGolden(t *testing.T) {
+	// Bumping Version starts a new directory, so a golden which exists is
+	// one for the current version, which may only change compatibly.
+	dir := goldenDir(Version)
+
+	for _, g := range goldenNotes() {
+		g := g
+		t.Run(g.name, func(t *testing.T) {
+			filename := filepath.Join(dir, g.name+".json")
+			got := encodeGolden(t, g.note)
+
+			want, err := ioutil.ReadFile(golden.Path(filename))
+			if err != nil && !os.IsNotExist(err) {
+				t.Fatal(err)
+			}
+
+			if err == nil {
+				if bytes.Equal(got, want) {
+					return
+				}
+
+				if problems := incompatible(t, want, got); len(problems) != 0 {
+					t.Fatalf("%s changed incompatibly; bump protocol.Version, then regenerate with -test.update-golden:\n\t%s",
+						filename, strings.Join(problems, "\n\t"))
+				}
+			}
+
+			if !golden.FlagUpdate() {
+				t.Fatalf("%s is out of date; if the change is intended, regenerate it with -test.update-golden\n%s\n%s",
+					filename, want, got)
+			}
+
+			assert.NilError(t, os.MkdirAll(golden.Path(dir), 0777))
+			assert.NilError(t, ioutil.WriteFile(golden.Path(filename), got, 0666))
+		})
+	}
+}
+
+// incompatible returns the ways in which a client expecting the old encoding
+// of a note could fail to read the new one: a field which is gone, or which
+// has a different type. New fields are fine, as they're ignored by older
+// clients.
+func incompatible(t *testing.T, old, new []byte) []string {
+	t.Helper()
+
+	var o, n interface{}
+	assert.NilError(t, json.Unmarshal(old, &o))
+	assert.NilError(t, json.Unmarshal(new, &n))
+
+	var problems []string
+	compareJSON("$", o, n, &problems)
+	return problems
+}
+
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func compareJSON(path string, old, new interface{}, problems *[]string) {
+	// A field which was null in the fixture says nothing about its type.
+	if old == nil {
+		return
+	}
+
+	if ot, nt := jsonType(old), jsonType(new); ot != nt {
+		*problems = append(*problems, fmt.Sprintf("%s: was %s, now %s", path, ot, nt))
+		return
+	}
+
+	switch old := old.(type) {
+	case map[string]interface{}:
+		new := new.(map[string]interface{})
+		keys := make([]string, 0, len(old))
+		for k := range old {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			v, ok := new[k]
+			if !ok {
+				*problems = append(*problems, fmt.Sprintf("%s.%s: removed", path, k))
+				continue
+			}
+			compareJSON(path+"."+k, old[k], v, problems)
+		}
+
+	case []interface{}:
+		new := new.([]interface{})
+		for i := 0; i < len(old) && i < len(new); i++ {
+			compareJSON(fmt.Sprintf("%s[%d]", path, i), old[i], new[i], problems)
+		}
+	}
+}
+
+func TestGoldenIncompatible(t *testing.T) {
+	old := []byte(`{"method":"x","params":{"a":1,"b":"s","c":[{"d":true}],"e":null}}`)
+
+	tests := []struct {
+		new  string
+		want []string
+	}{
+		{`{"method":"x","params":{"a":2,"b":"t","c":[{"d":false}],"e":{}}}`, nil},
+		{`{"method":"x","params":{"a":1,"b":"s","c":[],"e":null,"f":3}}`, nil},
+		{`{"method":"x","params":{"b":"s","c":[{"d":true}],"e":null}}`, []string{"$.params.a: removed"}},
+		{`{"method":"x","params":{"a":"1","b":"s","c":[{"d":1}],"e":null}}`, []string{
+			"$.params.a: was number, now string",
+			"$.params.c[0].d: was boolean, now number",
+		}},
+	}
+
+	for _, test := range tests {
+		assert.DeepEqual(t, incompatible(t, old, []byte(test.new)), test.want)
+	}
+}
+
+// serverMethods returns every method named by the note constructors in
+// protocol.go, which are the functions returning a ServerNote.
+func serverMethods(t *testing.T) []string {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "protocol.go", nil, 0)
+	assert.NilError(t, err)
+
+	seen := make(map[string]bool)
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Type.Results == nil || len(fn.Type.Results.List) != 1 {
+			continue
+		}
+		if ident, ok := fn.Type.Results.List[0].Type.(*ast.Ident); !ok || ident.Name != "ServerNote" {
+			continue
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if lit, ok := n.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				method, err := strconv.Unquote(lit.Value)
+				assert.NilError(t, err)
+				seen[method] = true
+			}
+			return true
+		})
+	}
+
+	methods := make([]string, 0, len(seen))
+	for m := range seen {
+		methods = append(methods, m)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+func TestGoldenCoverage(t *testing.T) {
+	covered := make(map[string]bool)
+	names := make(map[string]bool)
+	for _, g := range goldenNotes() {
+		assert.Equal(t, string(g.note.Method), strings.SplitN(g.name, "-", 2)[0], "golden %s", g.name)
+		assert.Assert(t, !names[g.name+".json"], "golden %s is repeated", g.name)
+		covered[string(g.note.Method)] = true
+		names[g.name+".json"] = true
+	}
+
+	for _, m := range serverMethods(t) {
+		assert.Assert(t, covered[m], "%s has no golden; add one to goldenNotes", m)
+	}
+
+	// Goldens for notes which are no longer sent are left behind.
+	files, err := ioutil.ReadDir(golden.Path(goldenDir(Version)))
+	assert.NilError(t, err)
+	for _, f := range files {
+		assert.Assert(t, names[f.Name()], "%s has no fixture; remove it, and bump protocol.Version if the note is gone", f.Name())
+	}
+}
+
+func TestGoldenVersions(t *testing.T) {
+	files, err := ioutil.ReadDir(golden.Path(""))
+	assert.NilError(t, err)
+
+	for _, f := range files {
+		if !f.IsDir() || !strings.HasPrefix(f.Name(), "v") {
+			continue
+		}
+		v, err := strconv.Atoi(strings.TrimPrefix(f.Name(), "v"))
+		assert.NilError(t, err, "testdata/%s", f.Name())
+		assert.Assert(t, v <= Version, "testdata/%s is newer than protocol.Version %d", f.Name(), Version)
+	}
+}
@@ -0,0 +1,100 @@
+package protocol
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// CanonicalBoard serializes the parts of a state which every player sees the
+// same way: the version, turn, winner, and board. Tile views are only
+// included for revealed tiles, so a spymaster's state and a guesser's state
+// serialize identically.
+//
+// The format is one field per line:
+//
+//	v<version>
+//	t<turn>
+//	w<winner, or - if none>
+//	<row>,<col>:<word>:<h if hidden, or team/neutral/bomb as 0 or 1 if revealed>
+//
+// with tiles in row-major order. In games with more than one board, each board
+// after the first follows as a line b<index>, then its tiles. Tracker rooms
+// have no board, so only the first three lines are written.
+func CanonicalBoard(s *RoomState) string {
+	var b strings.Builder
+
+	b.WriteString("v")
+	b.WriteString(strconv.FormatInt(s.Version, 10))
+	b.WriteString("\nt")
+	b.WriteString(strconv.Itoa(int(s.Turn)))
+	b.WriteString("\nw")
+	if s.Winner != nil {
+		b.WriteString(strconv.Itoa(int(*s.Winner)))
+	} else {
+		b.WriteString("-")
+	}
+	b.WriteString("\n")
+
+	writeTiles(&b, s.Board)
+
+	for i := 1; i < len(s.Boards); i++ {
+		b.WriteString("b")
+		b.WriteString(strconv.Itoa(i))
+		b.WriteString("\n")
+		writeTiles(&b, s.Boards[i].Board)
+	}
+
+	return b.String()
+}
+
+func writeTiles(b *strings.Builder, board [][]*StateTile) {
+	for row, tiles := range board {
+		for col, tile := range tiles {
+			b.WriteString(strconv.Itoa(row))
+			b.WriteString(",")
+			b.WriteString(strconv.Itoa(col))
+			b.WriteString(":")
+			b.WriteString(tile.Word)
+			b.WriteString(":")
+
+			if !tile.Revealed || tile.View == nil {
+				b.WriteString("h\n")
+				continue
+			}
+
+			b.WriteString(strconv.Itoa(int(tile.View.Team)))
+			b.WriteString("/")
+			b.WriteString(boolDigit(tile.View.Neutral))
+			b.WriteString("/")
+			b.WriteString(boolDigit(tile.View.Bomb))
+			b.WriteString("\n")
+		}
+	}
+}
+
+func boolDigit(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// BoardHash is the hex encoded SHA-256 of CanonicalBoard, which clients
+// include when reporting a desync.
+func BoardHash(s *RoomState) string {
+	sum := sha256.Sum256([]byte(CanonicalBoard(s)))
+	return hex.EncodeToString(sum[:])
+}
+
+// ShortBoardHashLength is the length of ShortBoardHash.
+const ShortBoardHashLength = 16
+
+// ShortBoardHash is a prefix of BoardHash, sent with every state and roster
+// delta so that clients can cheaply check their own state and report a
+// desync before it shows. Roster deltas don't change the board, so it holds
+// for every delta of a change, as well as the state after the last one.
+func ShortBoardHash(s *RoomState) string {
+	return BoardHash(s)[:ShortBoardHashLength]
+}
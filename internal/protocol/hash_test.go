@@ -0,0 +1,97 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"gotest.tools/v3/assert"
+)
+
+func testState(spymaster bool) *RoomState {
+	view := func(team game.Team, neutral, bomb bool) *StateView {
+		return &StateView{Team: team, Neutral: neutral, Bomb: bomb}
+	}
+
+	s := &RoomState{
+		Version: 7,
+		Turn:    1,
+		Board: [][]*StateTile{
+			{
+				{Word: "APPLE", Revealed: true, View: view(0, false, false)},
+				{Word: "BANK"},
+			},
+			{
+				{Word: "CAT", Revealed: true, View: view(0, true, false)},
+				{Word: "DOG"},
+			},
+		},
+	}
+
+	if spymaster {
+		s.Board[0][1].View = view(1, false, false)
+		s.Board[1][1].View = view(0, false, true)
+	}
+	return s
+}
+
+func TestCanonicalBoard(t *testing.T) {
+	want := "v7\nt1\nw-\n0,0:APPLE:0/0/0\n0,1:BANK:h\n1,0:CAT:0/1/0\n1,1:DOG:h\n"
+	assert.Equal(t, CanonicalBoard(testState(false)), want)
+}
+
+func TestCanonicalBoardGauntlet(t *testing.T) {
+	s := testState(false)
+	second := testState(false).Board
+	second[0][0].Word = "PEAR"
+	s.Boards = []*StateBoard{{Board: s.Board}, {Board: second}}
+
+	want := "v7\nt1\nw-\n0,0:APPLE:0/0/0\n0,1:BANK:h\n1,0:CAT:0/1/0\n1,1:DOG:h\n" +
+		"b1\n0,0:PEAR:0/0/0\n0,1:BANK:h\n1,0:CAT:0/1/0\n1,1:DOG:h\n"
+	assert.Equal(t, CanonicalBoard(s), want)
+}
+
+func TestBoardHashIgnoresRedaction(t *testing.T) {
+	assert.Equal(t, BoardHash(testState(true)), BoardHash(testState(false)))
+}
+
+func TestBoardHashStable(t *testing.T) {
+	// The hash is part of the protocol; changing it breaks clients.
+	assert.Equal(t, BoardHash(testState(false)), "f0a5513730ef5e6b1d7ac6a8bf009d8037c71ffe4e0666a0d595dc96fa05149a")
+}
+
+func TestBoardHashChanges(t *testing.T) {
+	base := BoardHash(testState(false))
+
+	changes := map[string]func(s *RoomState){
+		"version": func(s *RoomState) { s.Version++ },
+		"turn":    func(s *RoomState) { s.Turn = 0 },
+		"winner": func(s *RoomState) {
+			w := game.Team(0)
+			s.Winner = &w
+		},
+		"reveal": func(s *RoomState) {
+			s.Board[0][1].Revealed = true
+			s.Board[0][1].View = &StateView{Team: 1}
+		},
+		"view": func(s *RoomState) { s.Board[0][0].View.Team = 1 },
+		"word": func(s *RoomState) { s.Board[1][1].Word = "DOGS" },
+		"swap": func(s *RoomState) {
+			s.Board[0][1], s.Board[1][1] = s.Board[1][1], s.Board[0][1]
+		},
+	}
+
+	for name, change := range changes {
+		s := testState(false)
+		change(s)
+		assert.Assert(t, BoardHash(s) != base, "%s didn't change the hash", name)
+	}
+}
+
+func TestShortBoardHash(t *testing.T) {
+	// Pinned for the same reason as BoardHash.
+	assert.Equal(t, ShortBoardHash(testState(false)), "f0a5513730ef5e6b")
+	assert.Equal(t, ShortBoardHash(testState(true)), ShortBoardHash(testState(false)))
+
+	tracker := &RoomState{Version: 3, Turn: 1, Tracker: &StateTracker{Scores: []int{1, 2}}}
+	assert.Equal(t, ShortBoardHash(tracker), ShortBoardHash(&RoomState{Version: 3, Turn: 1}))
+}
@@ -2,6 +2,8 @@ package protocol
 
 import (
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/mailru/easyjson"
 	"github.com/zikaeroh/codies/internal/game"
@@ -9,39 +11,307 @@ import (
 
 // See protocol/index.ts.
 
+// Version is the version of the protocol described by this package. It should
+// be incremented whenever the protocol changes incompatibly; the goldens in
+// testdata enforce this for the notes the server sends.
+const Version = 1
+
 //go:generate go run github.com/mailru/easyjson/easyjson -disallow_unknown_fields protocol.go
 
 type ExistsQuery struct {
 	RoomID string `queryparam:"roomID"`
 }
 
+//easyjson:json
+type ClosedResponse struct {
+	Reason string `json:"reason"`
+}
+
+// RulesetVersion is the version of the Ruleset document. It changes whenever
+// the document does, so that a hash always describes the same rules.
+const RulesetVersion = 3
+
+// Ruleset describes the rules a game started with: its mode, options, and
+// packs, with anything which can't affect play left out. Options and packs are
+// sorted, and list every option rather than only those changed from the
+// defaults, so the JSON encoding of a Ruleset is canonical; a game's ruleset
+// hash is the SHA-256 of that encoding. Anyone holding the document can
+// recompute the hash to check it.
+//
+// GET /api/ruleset/{hash} returns the document for a recently seen hash.
+//
+//easyjson:json
+type Ruleset struct {
+	Version int              `json:"version"`
+	Mode    string           `json:"mode"`
+	Options []*RulesetOption `json:"options"` // Sorted by name.
+	Packs   []*RulesetPack   `json:"packs"`   // Sorted by name, built-in packs first.
+}
+
+//easyjson:json
+type RulesetOption struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// RulesetPack is an enabled pack. Its hash covers its words, in any order.
+//
+//easyjson:json
+type RulesetPack struct {
+	Name   string `json:"name"`
+	Custom bool   `json:"custom"`
+	Hash   string `json:"hash"`
+}
+
+type GameLogQuery struct {
+	PlayerID string `queryparam:"playerID"`
+}
+
+// GameLog records a room's last finished game, for archiving. Words are by
+// board, row, and column, and Result has the key in the same shape; tracker
+// games have neither, nor any reveals. Only the most recent reveals are kept
+// if a game somehow has too many.
+//
+// GET /api/room/{roomID}/log?playerID=... returns it, to players in the room.
+//
+//easyjson:json
+type GameLog struct {
+	Started time.Time          `json:"started"`
+	Ended   time.Time          `json:"ended"`
+	Ruleset string             `json:"ruleset,omitempty"` // See Ruleset.
+	Players []*GameLogPlayer   `json:"players"`           // By team, as the game ended.
+	Words   [][][]string       `json:"words,omitempty"`
+	Reveals []*GameLogReveal   `json:"reveals"` // Oldest first.
+	Clues   []*StateLoggedClue `json:"clues"`   // Oldest first.
+	Result  *StateGameOver     `json:"result"`
+}
+
+//easyjson:json
+type GameLogPlayer struct {
+	PlayerID  game.PlayerID `json:"playerID"`
+	Nickname  string        `json:"nickname"`
+	Team      game.Team     `json:"team"`
+	Spymaster bool          `json:"spymaster"`
+}
+
+// GameLogReveal is a card revealed during a game, and who revealed it. Hint is
+// set for cards revealed by a hint rather than clicked.
+//
+//easyjson:json
+type GameLogReveal struct {
+	Time     time.Time     `json:"time"`
+	PlayerID game.PlayerID `json:"playerID"`
+	Nickname string        `json:"nickname"`
+	Board    int           `json:"board"`
+	Row      int           `json:"row"`
+	Col      int           `json:"col"`
+	Word     string        `json:"word"`
+	View     *StateView    `json:"view"`
+	Hint     bool          `json:"hint,omitempty"`
+}
+
+// TooManyConnsResponse is sent with a 429 when a WebSocket connection is
+// refused because its IP already has too many.
+//
+//easyjson:json
+type TooManyConnsResponse struct {
+	Error string `json:"error"`
+	Limit int    `json:"limit"`
+}
+
+// MaxCloseReason is the most bytes a WebSocket close reason may hold: a close
+// frame's payload is at most 125 bytes, two of which are the code.
+const MaxCloseReason = 123
+
+// CloseHint is the reason of every close the server sends, telling the client
+// whether reconnecting may work. Retry is always present; AfterMS is how long
+// to wait first, and RoomID is set when the room to reconnect to has changed,
+// as in a merge. Reason is only for people.
+//
+//easyjson:json
+type CloseHint struct {
+	Retry   bool   `json:"retry"`
+	AfterMS int    `json:"afterMs,omitempty"`
+	RoomID  string `json:"roomId,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// Encode encodes the hint as a close reason, within MaxCloseReason. Only the
+// reason is cut, a rune at a time, as the rest is what clients act on. A room
+// ID can't be cut and still be of use, so a hint too long even without a
+// reason drops it, and says not to retry.
+func (h CloseHint) Encode() string {
+	for {
+		b, _ := easyjson.Marshal(h)
+		if len(b) <= MaxCloseReason {
+			return string(b)
+		}
+
+		if h.Reason == "" {
+			h = CloseHint{}
+			continue
+		}
+
+		_, size := utf8.DecodeLastRuneInString(h.Reason)
+		h.Reason = h.Reason[:len(h.Reason)-size]
+	}
+}
+
 //easyjson:json
 type RoomRequest struct {
 	RoomName string `json:"roomName"`
 	RoomPass string `json:"roomPass"`
 	Create   bool   `json:"create"`
+
+	// Tracker creates a room without a board, for groups playing with a
+	// physical board. It's ignored unless creating a room.
+	Tracker bool `json:"tracker,omitempty"`
+
+	// Unlisted hides the room from GET /api/rooms. It's ignored unless
+	// creating a room.
+	Unlisted bool `json:"unlisted,omitempty"`
+
+	// Words creates the room with a custom pack of these words, used instead
+	// of the built-in packs, which may be enabled later. It's ignored unless
+	// creating a room, and for trackers.
+	Words []string `json:"words,omitempty"`
+
+	// Language creates the room with the built-in packs in this language
+	// enabled, rather than the default pack; see GET /api/packs. It's ignored
+	// unless creating a room, and for trackers and rooms created with Words.
+	Language string `json:"language,omitempty"`
+
+	// Rows and Cols create the room with boards of this size, rather than
+	// 5x5; either may be left out to keep its default. It's ignored unless
+	// creating a room, and for trackers.
+	Rows int `json:"rows,omitempty"`
+	Cols int `json:"cols,omitempty"`
+
+	// Wait asks the server to briefly wait for capacity if it's full.
+	Wait bool `json:"wait"`
+}
+
+// Validation error codes.
+const (
+	ErrorRequired     = "required"
+	ErrorTooLong      = "tooLong"
+	ErrorInvalidChars = "invalidChars"
+)
+
+//easyjson:json
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
 }
 
-func (r *RoomRequest) Valid() (msg string, valid bool) {
-	if len(r.RoomName) == 0 {
-		return "Room name cannot be empty.", false
+// MaxRoomPass is the longest room password, in bytes. Room passwords are
+// hashed with bcrypt, which ignores anything past this.
+const MaxRoomPass = 72
+
+// Valid validates the request, returning every violation found.
+func (r *RoomRequest) Valid() (errs []*FieldError, valid bool) {
+	add := func(field, code, message string) {
+		errs = append(errs, &FieldError{Field: field, Code: code, Message: message})
 	}
 
-	if len(r.RoomName) > 20 {
-		return "Room name too long.", false
+	switch {
+	case len(r.RoomName) == 0:
+		add("roomName", ErrorRequired, "Room name cannot be empty.")
+	case len(r.RoomName) > 20:
+		add("roomName", ErrorTooLong, "Room name too long.")
+	case !printable(r.RoomName):
+		add("roomName", ErrorInvalidChars, "Room name contains invalid characters.")
 	}
 
-	if len(r.RoomPass) == 0 {
-		return "Room pass cannot be empty.", false
+	switch {
+	case len(r.RoomPass) == 0:
+		add("roomPass", ErrorRequired, "Room pass cannot be empty.")
+	case len(r.RoomPass) > MaxRoomPass:
+		add("roomPass", ErrorTooLong, "Room pass too long.")
 	}
 
-	return "", true
+	return errs, len(errs) == 0
+}
+
+// MatchmakeRequest enters a solo player into the matchmaking pool, with
+// POST /api/matchmake.
+//
+//easyjson:json
+type MatchmakeRequest struct {
+	Nickname string `json:"nickname"`
+}
+
+// Valid validates the request, returning every violation found.
+func (r *MatchmakeRequest) Valid() (errs []*FieldError, valid bool) {
+	add := func(field, code, message string) {
+		errs = append(errs, &FieldError{Field: field, Code: code, Message: message})
+	}
+
+	switch {
+	case len(r.Nickname) == 0:
+		add("nickname", ErrorRequired, "Nickname cannot be empty.")
+	case len(r.Nickname) > 16:
+		add("nickname", ErrorTooLong, "Nickname too long.")
+	case !printable(r.Nickname):
+		add("nickname", ErrorInvalidChars, "Nickname contains invalid characters.")
+	}
+
+	return errs, len(errs) == 0
+}
+
+// MatchmakeQuery names a ticket, for polling it with GET /api/matchmake or
+// cancelling it with DELETE /api/matchmake.
+type MatchmakeQuery struct {
+	Ticket string `queryparam:"ticket"`
+}
+
+// Matchmaking ticket statuses.
+const (
+	MatchWaiting = "waiting"
+	MatchFound   = "found"
+)
+
+// MatchmakeResponse describes a matchmaking ticket. It's returned when
+// entering the pool, and by GET /api/matchmake?ticket=..., which clients poll
+// until the status is MatchFound. The player then joins RoomID with Token as
+// the WebSocket's merge parameter, which seats them on the team they were
+// assigned. A ticket which isn't polled is dropped from the pool.
+//
+//easyjson:json
+type MatchmakeResponse struct {
+	Ticket  string  `json:"ticket,omitempty"`
+	Status  string  `json:"status,omitempty"`
+	Waiting int     `json:"waiting"` // Players in the pool, including this one.
+	RoomID  *string `json:"roomID,omitempty"`
+	Token   *string `json:"token,omitempty"`
+
+	// Error is the first error in Errors, for consistency with RoomResponse.
+	Error  *string       `json:"error,omitempty"`
+	Errors []*FieldError `json:"errors,omitempty"`
+}
+
+func printable(s string) bool {
+	if !utf8.ValidString(s) {
+		return false
+	}
+
+	for _, r := range s {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+
+	return true
 }
 
 //easyjson:json
 type RoomResponse struct {
-	ID    *string `json:"id,omitempty"`
-	Error *string `json:"error,omitempty"`
+	ID *string `json:"id,omitempty"`
+
+	// Error is the first error in Errors, for older clients.
+	Error  *string       `json:"error,omitempty"`
+	Errors []*FieldError `json:"errors,omitempty"`
 }
 
 //easyjson:json
@@ -49,15 +319,129 @@ type TimeResponse struct {
 	Time time.Time `json:"time"`
 }
 
+// ListedRoom is a room in GET /api/rooms. Rooms are joined with their name and
+// password, as usual. Neither the password nor the room's ID is listed: the ID
+// is what a WebSocket connects with, so listing it would let anyone skip the
+// password.
+//
+//easyjson:json
+type ListedRoom struct {
+	Name             string `json:"name"`
+	Players          int    `json:"players"`
+	PasswordRequired bool   `json:"passwordRequired"`
+}
+
+// BuiltinPack is a pack every room has, in GET /api/packs. Language is the
+// code of the pack's language, if known.
+//
+//easyjson:json
+type BuiltinPack struct {
+	Name     string `json:"name"`
+	Language string `json:"language,omitempty"`
+	Count    int    `json:"count"`
+}
+
 //easyjson:json
 type StatsResponse struct {
-	Rooms   int `json:"rooms"`
-	Clients int `json:"clients"`
+	Rooms       int `json:"rooms"`
+	Clients     int `json:"clients"`
+	Spectators  int `json:"spectators"`
+	Matchmaking int `json:"matchmaking"` // Players waiting to be matched.
+}
+
+//easyjson:json
+type InfoResponse struct {
+	Config
+	StartTime     time.Time `json:"startTime"`
+	UptimeSeconds int64     `json:"uptimeSeconds"`
+}
+
+// Config is the effective configuration of the server. It must never contain
+// secrets, as it is public.
+//
+//easyjson:json
+type Config struct {
+	Version         string          `json:"version"`
+	ProtocolVersion int             `json:"protocolVersion"`
+	BuildTime       string          `json:"buildTime,omitempty"`
+	GoVersion       string          `json:"goVersion"`
+	Mode            string          `json:"mode"`
+	Limits          ConfigLimits    `json:"limits"`
+	Features        map[string]bool `json:"features"`
+}
+
+// Register records whether or not a feature is enabled.
+func (c *Config) Register(feature string, enabled bool) {
+	if c.Features == nil {
+		c.Features = make(map[string]bool)
+	}
+	c.Features[feature] = enabled
+}
+
+// ConfigLimits holds configured limits. Zero means unlimited.
+//
+//easyjson:json
+type ConfigLimits struct {
+	MaxRooms       int `json:"maxRooms"`
+	MaxPlayers     int `json:"maxPlayers"`
+	MaxConnections int `json:"maxConnections"`
+	MaxConnsPerIP  int `json:"maxConnsPerIP"`
+
+	MaxCustomPacks     int `json:"maxCustomPacks"`
+	MaxCustomPackBytes int `json:"maxCustomPackBytes"`
+
+	MinRevealPlayers int `json:"minRevealPlayers"` // Zero disables the minimum.
+	MatchSize        int `json:"matchSize"`        // Zero disables matchmaking.
 }
 
 type WSQuery struct {
-	RoomID   string `queryparam:"roomID"`
-	Nickname string `queryparam:"nickname"`
+	RoomID       string   `queryparam:"roomID"`
+	Nickname     string   `queryparam:"nickname"`
+	Capabilities []string `queryparam:"capabilities"`
+
+	// View is empty for players, or ViewMirror with a Token minted by the host.
+	View  string `queryparam:"view"`
+	Token string `queryparam:"token"`
+
+	// Merge claims the seat kept for a player whose room was merged into
+	// this one; it's the token from their merged note.
+	Merge string `queryparam:"merge"`
+
+	// Resume claims the seat a player had before the server restarted, or
+	// reclaims one held since they disconnected; it's the token from their
+	// latest resumeToken note.
+	Resume string `queryparam:"resume"`
+
+	// Spectate connects a spectator, who watches without joining a team and
+	// needs no nickname.
+	Spectate bool `queryparam:"spectate"`
+
+	// Version is the client's build, which is checked against the server's
+	// before the connection is accepted.
+	Version string `queryparam:"codiesVersion"`
+}
+
+// ViewMirror connects a read-only mirror of the room, which isn't a player.
+const ViewMirror = "mirror"
+
+// Client capabilities, which opt clients into optional protocol features.
+const (
+	// CapabilityBandwidth enables periodic bandwidth notes.
+	CapabilityBandwidth = "bandwidth"
+
+	// CapabilityDeltas sends changes which only affect the player list as
+	// playerJoined, playerLeft, and playerUpdated notes instead of full states.
+	CapabilityDeltas = "deltas"
+)
+
+// Has returns true if the client declared the capability.
+func (w *WSQuery) Has(capability string) bool {
+	for _, c := range w.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
 }
 
 func (w *WSQuery) Valid() (msg string, valid bool) {
@@ -65,6 +449,24 @@ func (w *WSQuery) Valid() (msg string, valid bool) {
 		return "Room ID cannot be empty.", false
 	}
 
+	switch w.View {
+	case "":
+	case ViewMirror:
+		if w.Spectate {
+			return "Mirrors cannot spectate.", false
+		}
+		if w.Token == "" {
+			return "Mirror token cannot be empty.", false
+		}
+		return "", true
+	default:
+		return "Unknown view.", false
+	}
+
+	if w.Spectate {
+		return "", true
+	}
+
 	if len(w.Nickname) == 0 {
 		return "Nickname cannot be empty.", false
 	}
@@ -79,16 +481,25 @@ func (w *WSQuery) Valid() (msg string, valid bool) {
 //easyjson:json
 type ClientNote struct {
 	Method  ClientMethod        `json:"method,intern"` //nolint:staticcheck
-	Version int                 `json:"version"`
+	Version int64               `json:"version"`
 	Params  easyjson.RawMessage `json:"params"`
+
+	// ID, if set, asks the server to acknowledge the command with an ack note
+	// carrying the same ID.
+	ID string `json:"id,omitempty"`
 }
 
 type ClientMethod string
 
 const NewGameMethod = ClientMethod("newGame")
 
+// NewGameParams starts a new game. Unless the host forces it, every team must
+// have the room's minimum number of spymasters and guessers.
+//
 //easyjson:json
-type NewGameParams struct{}
+type NewGameParams struct {
+	Force bool `json:"force,omitempty"`
+}
 
 const EndTurnMethod = ClientMethod("endTurn")
 
@@ -100,12 +511,38 @@ const RandomizeTeamsMethod = ClientMethod("randomizeTeams")
 //easyjson:json
 type RandomizeTeamsParams struct{}
 
+// ChangeTeamConstraintsMethod replaces the pairs of players randomizeTeams
+// keeps together or apart. Only the host may change them; an empty list
+// clears them. Constraints which can't all be satisfied are rejected with an
+// unsatisfiableConstraints error listing the ones which conflict.
+const ChangeTeamConstraintsMethod = ClientMethod("changeTeamConstraints")
+
+//easyjson:json
+type ChangeTeamConstraintsParams struct {
+	Constraints []*TeamConstraint `json:"constraints"`
+}
+
+// TeamConstraint keeps two players on the same team when teams are
+// randomized, or on different teams if Apart is set.
+//
+//easyjson:json
+type TeamConstraint struct {
+	A     game.PlayerID `json:"a"`
+	B     game.PlayerID `json:"b"`
+	Apart bool          `json:"apart,omitempty"`
+}
+
 const RevealMethod = ClientMethod("reveal")
 
+// RevealParams reveals a tile. Unless the host forces it, the first reveal of
+// a game needs the server's minimum number of players in the room.
+//
 //easyjson:json
 type RevealParams struct {
-	Row int `json:"row"`
-	Col int `json:"col"`
+	Board int  `json:"board"` // Always zero unless the game has more than one board.
+	Row   int  `json:"row"`
+	Col   int  `json:"col"`
+	Force bool `json:"force,omitempty"`
 }
 
 const ChangeTeamMethod = ClientMethod("changeTeam")
@@ -151,6 +588,57 @@ type ChangeTurnTimeParams struct {
 	Seconds int `json:"seconds"`
 }
 
+const ChangePenaltiesMethod = ClientMethod("changePenalties")
+
+//easyjson:json
+type ChangePenaltiesParams struct {
+	Limit int `json:"limit"`
+}
+
+// UseHintMethod spends one of the team's hints to reveal a random neutral
+// tile on a board.
+const UseHintMethod = ClientMethod("useHint")
+
+//easyjson:json
+type UseHintParams struct {
+	Board int `json:"board,omitempty"`
+}
+
+// ScoreMethod changes a team's score by one in a tracker room.
+const ScoreMethod = ClientMethod("score")
+
+//easyjson:json
+type ScoreParams struct {
+	Team  game.Team `json:"team"`
+	Delta int       `json:"delta"` // 1 or -1.
+}
+
+// DeclareWinnerMethod ends the game in a tracker room. Only the host may
+// declare the winner.
+const DeclareWinnerMethod = ClientMethod("declareWinner")
+
+//easyjson:json
+type DeclareWinnerParams struct {
+	Team game.Team `json:"team"`
+}
+
+// ResetWinsMethod clears the room's tally of wins. Only the host may reset
+// it.
+const ResetWinsMethod = ClientMethod("resetWins")
+
+//easyjson:json
+type ResetWinsParams struct{}
+
+// UndoMethod takes back the most recent reveal, reopening the game if it
+// ended it. Only the host may undo, and only until anything else is played.
+const UndoMethod = ClientMethod("undo")
+
+//easyjson:json
+type UndoParams struct{}
+
+// AddPacksMethod adds custom packs, disabled. Only the host may add packs.
+// Each pack's words are cleaned as a new room's are, and if any pack is
+// refused, none are added.
 const AddPacksMethod = ClientMethod("addPacks")
 
 //easyjson:json
@@ -161,6 +649,21 @@ type AddPacksParams struct {
 	} `json:"packs"`
 }
 
+// AddPackURLMethod adds a custom pack, disabled, fetched from a URL as plain
+// text with one word per line. Only the host may add packs, and a room fetches
+// one at a time. The words are cleaned as a new room's are. The command is
+// acked, or its error sent, once the fetch is done, rather than when it's
+// received.
+const AddPackURLMethod = ClientMethod("addPackURL")
+
+//easyjson:json
+type AddPackURLParams struct {
+	Name string `json:"name,omitempty"` // If empty, the pack's named for the URL's file.
+	URL  string `json:"url"`
+}
+
+// RemovePackMethod removes a custom pack. Only the host may remove packs, and
+// an enabled pack can't be removed if the rest wouldn't fill a board.
 const RemovePackMethod = ClientMethod("removePack")
 
 //easyjson:json
@@ -170,10 +673,33 @@ type RemovePackParams struct {
 
 type ServerMethod string
 
+// ServerNote is a note sent by the server.
+//
+// Sent is the server's clock as the note was written to the connection. It's
+// set on every note which carries a timer, and on timeSync replies, so that
+// clients can tell how long a timer's deadline is from the moment it was sent
+// rather than from whenever the note arrived. Clients should render a timer
+// as its deadline less the server's current time, estimated with ClockOffset;
+// the server's own expiry decisions never depend on a client's clock.
+//
 //easyjson:json
 type ServerNote struct {
 	Method ServerMethod `json:"method"`
 	Params interface{}  `json:"params"`
+	Sent   *time.Time   `json:"sent,omitempty"`
+}
+
+// HasTimer reports whether the note carries a timer, and so must be stamped
+// with the time it was sent.
+func (n *ServerNote) HasTimer() bool {
+	switch params := n.Params.(type) {
+	case *TimeSync:
+		return true
+	case *State:
+		return params.RoomState != nil && params.RoomState.Timer != nil
+	default:
+		return false
+	}
 }
 
 const ChangeHideBombMethod = ClientMethod("changeHideBomb")
@@ -183,66 +709,1084 @@ type ChangeHideBombParams struct {
 	HideBomb bool `json:"hideBomb"`
 }
 
-func NewStateNote(playerID game.PlayerID, s *RoomState) ServerNote {
-	return ServerNote{
-		Method: "state",
-		Params: &State{
-			PlayerID:  playerID,
-			RoomState: s,
-		},
-	}
+const GiveClueMethod = ClientMethod("giveClue")
+
+//easyjson:json
+type GiveClueParams struct {
+	Board int    `json:"board"`
+	Word  string `json:"word"`
+	Count int    `json:"count"`
 }
 
+const ChangeBoundCluesMethod = ClientMethod("changeBoundClues")
+
 //easyjson:json
-type State struct {
-	PlayerID  game.PlayerID `json:"playerID"`
-	RoomState *RoomState    `json:"roomState"`
+type ChangeBoundCluesParams struct {
+	BoundClues bool `json:"boundClues"`
 }
 
+const ChangeNotificationsMethod = ClientMethod("changeNotifications")
+
 //easyjson:json
-type RoomState struct {
-	Version   int              `json:"version"`
-	Teams     [][]*StatePlayer `json:"teams"`
-	Turn      game.Team        `json:"turn"`
-	Winner    *game.Team       `json:"winner"`
-	Board     [][]*StateTile   `json:"board"`
-	WordsLeft []int            `json:"wordsLeft"`
-	Lists     []*StateWordList `json:"lists"`
-	Timer     *StateTimer      `json:"timer"`
-	HideBomb  bool             `json:"hideBomb"`
+type ChangeNotificationsParams struct {
+	Off      bool                `json:"off"`
+	Disabled []NotificationEvent `json:"disabled"`
 }
 
+const ChangeSuggestCluesMethod = ClientMethod("changeSuggestClues")
+
 //easyjson:json
-type StatePlayer struct {
-	PlayerID  game.PlayerID `json:"playerID"`
-	Nickname  string        `json:"nickname"`
-	Spymaster bool          `json:"spymaster"`
+type ChangeSuggestCluesParams struct {
+	SuggestClues bool `json:"suggestClues"`
 }
 
+const ChangeSpymastersMethod = ClientMethod("changeSpymasters")
+
 //easyjson:json
-type StateTile struct {
-	Word     string     `json:"word"`
-	Revealed bool       `json:"revealed"`
-	View     *StateView `json:"view"`
+type ChangeSpymastersParams struct {
+	Limit        int  `json:"limit"`
+	ConfirmClues bool `json:"confirmClues"`
 }
 
+const ChangeMirrorDelayMethod = ClientMethod("changeMirrorDelay")
+
 //easyjson:json
-type StateView struct {
-	Team    game.Team `json:"team"`
-	Neutral bool      `json:"neutral"`
-	Bomb    bool      `json:"bomb"`
+type ChangeMirrorDelayParams struct {
+	Seconds int `json:"seconds"`
 }
 
+const UpdateOptionsMethod = ClientMethod("updateOptions")
+
+// UpdateOptionsParams changes any number of room options at once. Omitted
+// options are left as they are. The options are validated together and
+// either all are applied or none are.
+//
 //easyjson:json
-type StateWordList struct {
-	Name    string `json:"name"`
-	Count   int    `json:"count"`
-	Custom  bool   `json:"custom"`
-	Enabled bool   `json:"enabled"`
+type UpdateOptionsParams struct {
+	Timed          *bool `json:"timed,omitempty"`
+	TurnTime       *int  `json:"turnTime,omitempty"`
+	HideBomb       *bool `json:"hideBomb,omitempty"`
+	BoundClues     *bool `json:"boundClues,omitempty"`
+	SuggestClues   *bool `json:"suggestClues,omitempty"`
+	PenaltyLimit   *int  `json:"penaltyLimit,omitempty"`
+	SpymasterLimit *int  `json:"spymasterLimit,omitempty"`
+	ConfirmClues   *bool `json:"confirmClues,omitempty"`
+	MirrorDelay    *int  `json:"mirrorDelay,omitempty"`
+
+	// EnforceGuessLimit ends each turn once the team has guessed one more
+	// than the clue's number.
+	EnforceGuessLimit *bool `json:"enforceGuessLimit,omitempty"`
+
+	// Board options apply from the next new game.
+	Boards *int `json:"boards,omitempty"`
+	Rows   *int `json:"rows,omitempty"`
+	Cols   *int `json:"cols,omitempty"`
+	Bombs  *int `json:"bombs,omitempty"` // On each board; see game.MaxBombs.
+
+	// The fewest spymasters and guessers each team needs for a new game.
+	MinSpymasters *int `json:"minSpymasters,omitempty"`
+	MinGuessers   *int `json:"minGuessers,omitempty"`
+
+	// Hints for each team, from the next new game.
+	HintBudget []int `json:"hintBudget,omitempty"`
+
+	// Feedback prompts players to rate each game as it ends. Only the host
+	// may change it.
+	Feedback *bool `json:"feedback,omitempty"`
+
+	// OpenPolls lets any player open polls, rather than only the host. Only
+	// the host may change it.
+	OpenPolls *bool `json:"openPolls,omitempty"`
+
+	// BackfillWords lets new games start when the enabled packs are short of
+	// words, drawing the rest from the base pack of the room's language.
+	BackfillWords *bool `json:"backfillWords,omitempty"`
 }
 
+// ReportDesyncMethod reports that a client suspects its state differs from
+// the server's. The hash is BoardHash of the client's state at the note's
+// version.
+const ReportDesyncMethod = ClientMethod("reportDesync")
+
 //easyjson:json
-type StateTimer struct {
-	TurnTime int       `json:"turnTime"`
-	TurnEnd  time.Time `json:"turnEnd"`
+type ReportDesyncParams struct {
+	Hash string `json:"hash"`
+}
+
+const MintMirrorTokenMethod = ClientMethod("mintMirrorToken")
+
+//easyjson:json
+type MintMirrorTokenParams struct{}
+
+const RevokeMirrorTokenMethod = ClientMethod("revokeMirrorToken")
+
+//easyjson:json
+type RevokeMirrorTokenParams struct {
+	Token string `json:"token"`
+}
+
+// GetAuditLogMethod requests the room's audit log. Only the host may read it.
+const GetAuditLogMethod = ClientMethod("getAuditLog")
+
+//easyjson:json
+type GetAuditLogParams struct{}
+
+// SetWebhookMethod sets the URL the room posts the results of finished games
+// to, or clears it if empty. Only the host may set it.
+const SetWebhookMethod = ClientMethod("setWebhook")
+
+//easyjson:json
+type SetWebhookParams struct {
+	URL string `json:"url"`
+}
+
+// GetWebhookMethod requests the room's webhook URL, which is sent in a
+// webhook note. Only the host may read it; everyone else only sees whether
+// one is set.
+const GetWebhookMethod = ClientMethod("getWebhook")
+
+//easyjson:json
+type GetWebhookParams struct{}
+
+// RequestMergeMethod asks to merge the room into another, moving its players
+// there. Only the host may ask, and only between games. The other room's host
+// is sent a mergeRequest note, and answers with answerMerge.
+const RequestMergeMethod = ClientMethod("requestMerge")
+
+//easyjson:json
+type RequestMergeParams struct {
+	RoomID string `json:"roomID"`
+}
+
+// AnswerMergeMethod accepts or declines a mergeRequest. Only the host may
+// answer.
+const AnswerMergeMethod = ClientMethod("answerMerge")
+
+//easyjson:json
+type AnswerMergeParams struct {
+	ID     string `json:"id"`
+	Accept bool   `json:"accept"`
+}
+
+// KickMethod removes a player from the room and closes their connection. Only
+// the host may kick, and not themselves. Kicking isn't banning; the player
+// may join again.
+const KickMethod = ClientMethod("kick")
+
+//easyjson:json
+type KickParams struct {
+	PlayerID game.PlayerID `json:"playerID"`
+}
+
+// BanMethod kicks a player and keeps them out of the room, including after
+// they reconnect. Only the host may ban, and not themselves. The host is sent
+// a bans note with the room's ban list.
+const BanMethod = ClientMethod("ban")
+
+//easyjson:json
+type BanParams struct {
+	PlayerID game.PlayerID `json:"playerID"`
+}
+
+// UnbanMethod lifts a ban, by the ID the player had when banned. Only the host
+// may unban. The host is sent a bans note with the room's ban list.
+const UnbanMethod = ClientMethod("unban")
+
+//easyjson:json
+type UnbanParams struct {
+	PlayerID game.PlayerID `json:"playerID"`
+}
+
+// GetBansMethod requests the room's ban list. Only the host may read it.
+const GetBansMethod = ClientMethod("getBans")
+
+//easyjson:json
+type GetBansParams struct{}
+
+// RateGameMethod answers a feedbackPrompt, rating the game it named, with an
+// optional tag. Each prompted player may answer once. Answers are only kept as
+// counts per ruleset, never by player.
+const RateGameMethod = ClientMethod("rateGame")
+
+//easyjson:json
+type RateGameParams struct {
+	Game   int         `json:"game"`
+	Rating int         `json:"rating"` // Between MinRating and MaxRating.
+	Tag    FeedbackTag `json:"tag,omitempty"`
+}
+
+const (
+	MinRating = 1
+	MaxRating = 5
+)
+
+// SuggestWordMethod suggests a word for one of the room's built-in packs.
+// Suggestions are only collected for operators to review; they never reach a
+// board. Each player may make MaxWordSuggestions a day.
+const SuggestWordMethod = ClientMethod("suggestWord")
+
+//easyjson:json
+type SuggestWordParams struct {
+	Word string `json:"word"`
+	Pack string `json:"pack"` // The name of a built-in pack.
+}
+
+const MaxWordSuggestions = 10
+
+// ChatMethod sends a chat message to everyone in the room, spymasters'
+// included. It's relayed in a chat note; the room doesn't need to be at the
+// note's version, as chat doesn't depend on the game.
+const ChatMethod = ClientMethod("chat")
+
+//easyjson:json
+type ChatParams struct {
+	Text string `json:"text"` // At most MaxChatLength characters.
+}
+
+const MaxChatLength = 500
+
+// OpenPollMethod opens a poll, which players vote on until it closes, after
+// Seconds or when the host closes it. Only the host may open polls, unless the
+// room's OpenPolls option is set. A room has at most one open poll. Polls have
+// no effect on the game, so, like chat, their commands needn't be at the
+// room's version; each change is sent in a poll note.
+const OpenPollMethod = ClientMethod("openPoll")
+
+//easyjson:json
+type OpenPollParams struct {
+	Question string   `json:"question"` // At most MaxPollQuestionLength characters.
+	Options  []string `json:"options"`  // MinPollOptions to MaxPollOptions, each at most MaxPollOptionLength characters.
+
+	// Hidden hides the tallies until the poll closes.
+	Hidden bool `json:"hidden"`
+
+	// Seconds is how long the poll stays open, at most MaxPollSeconds; zero
+	// is DefaultPollSeconds.
+	Seconds int `json:"seconds"`
+}
+
+const (
+	MaxPollQuestionLength = 200
+	MaxPollOptionLength   = 50
+	MinPollOptions        = 2
+	MaxPollOptions        = 5
+	DefaultPollSeconds    = 60
+	MaxPollSeconds        = 600
+)
+
+// VoteMethod votes in the open poll, replacing the player's vote if they've
+// already voted.
+const VoteMethod = ClientMethod("vote")
+
+//easyjson:json
+type VoteParams struct {
+	Poll   int `json:"poll"`   // The poll's ID.
+	Option int `json:"option"` // Index into the poll's options.
+}
+
+// ClosePollMethod closes the open poll early. Only the host may close polls.
+const ClosePollMethod = ClientMethod("closePoll")
+
+//easyjson:json
+type ClosePollParams struct {
+	Poll int `json:"poll"` // The poll's ID.
+}
+
+// FeedbackTag is a reason which may be given with a rating. There's no free
+// text, so there's nothing to moderate.
+type FeedbackTag string
+
+const (
+	FeedbackTooLong = FeedbackTag("tooLong")
+	FeedbackTooEasy = FeedbackTag("tooEasy")
+	FeedbackFun     = FeedbackTag("fun")
+)
+
+var FeedbackTags = []FeedbackTag{
+	FeedbackTooLong,
+	FeedbackTooEasy,
+	FeedbackFun,
+}
+
+func (t FeedbackTag) Valid() bool {
+	for _, v := range FeedbackTags {
+		if t == v {
+			return true
+		}
+	}
+	return false
+}
+
+// TimeSyncMethod asks the server for its clock, for estimating the offset
+// between the client's clock and the server's over the WebSocket itself; see
+// ClockOffset. The reply is a timeSync note. It may be sent by any connection,
+// including mirrors, at any time.
+const TimeSyncMethod = ClientMethod("timeSync")
+
+//easyjson:json
+type TimeSyncParams struct {
+	// ClientTime is the client's clock as it sent the command, in whatever
+	// units it likes; it's echoed back unchanged.
+	ClientTime int64 `json:"clientTime"`
+}
+
+const DebugInfoMethod = ClientMethod("debugInfo")
+
+//easyjson:json
+type DebugInfoParams struct{}
+
+func NewStateNote(playerID game.PlayerID, s *RoomState) ServerNote {
+	return ServerNote{
+		Method: "state",
+		Params: &State{
+			PlayerID:  playerID,
+			RoomState: s,
+		},
+	}
+}
+
+func NewErrorNote(e *Error) ServerNote {
+	return ServerNote{
+		Method: "error",
+		Params: e,
+	}
+}
+
+//easyjson:json
+type Error struct {
+	Code       string            `json:"code"`
+	Message    string            `json:"message"`
+	Limit      *int              `json:"limit,omitempty"`
+	Shortfalls []*ErrorShortfall `json:"shortfalls,omitempty"`
+	Conflicts  []*TeamConstraint `json:"conflicts,omitempty"`
+	RetryAfter *int              `json:"retryAfter,omitempty"` // In seconds.
+}
+
+// ErrorShortfall is a team missing players needed to start a new game.
+//
+//easyjson:json
+type ErrorShortfall struct {
+	Team      game.Team `json:"team"`
+	Spymaster bool      `json:"spymaster"`
+	Have      int       `json:"have"`
+	Need      int       `json:"need"`
+}
+
+type NotificationEvent string
+
+const (
+	NotifyYourTurn    = NotificationEvent("yourTurn")
+	NotifyOneCardLeft = NotificationEvent("oneCardLeft")
+)
+
+var NotificationEvents = []NotificationEvent{
+	NotifyYourTurn,
+	NotifyOneCardLeft,
+}
+
+func (e NotificationEvent) Valid() bool {
+	for _, v := range NotificationEvents {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func NewAckNote(id string, version int64, err *Error) ServerNote {
+	return ServerNote{
+		Method: "ack",
+		Params: &Ack{
+			ID:      id,
+			OK:      err == nil,
+			Error:   err,
+			Version: version,
+		},
+	}
+}
+
+// Ack acknowledges a client command. Version is the room's version after the
+// command was handled; if it changed the room, the state note for that
+// version follows the ack.
+//
+//easyjson:json
+type Ack struct {
+	ID      string `json:"id"`
+	OK      bool   `json:"ok"`
+	Error   *Error `json:"error,omitempty"`
+	Version int64  `json:"version"`
+}
+
+// NewOptionsChangedNote creates a note listing the room options which were
+// changed by a single command, by their UpdateOptionsParams names. It follows
+// the state containing the new options.
+func NewOptionsChangedNote(fields []string) ServerNote {
+	return ServerNote{
+		Method: "optionsChanged",
+		Params: &OptionsChanged{
+			Fields: fields,
+		},
+	}
+}
+
+//easyjson:json
+type OptionsChanged struct {
+	Fields []string `json:"fields"`
+}
+
+// NewQualityNote tells a client whether its connection has been degraded. A
+// degraded connection has fallen behind, so only the latest state is sent to
+// it; other broadcasts, like roster deltas and notifications, are dropped until
+// it catches up and a restored note is sent.
+func NewTimeSyncNote(clientTime int64, received time.Time) ServerNote {
+	return ServerNote{
+		Method: "timeSync",
+		Params: &TimeSync{
+			ClientTime: clientTime,
+			Received:   received,
+		},
+	}
+}
+
+// TimeSync answers a timeSync command. Received is the server's clock as the
+// command arrived; the note's Sent is the server's clock as the answer left.
+//
+//easyjson:json
+type TimeSync struct {
+	ClientTime int64     `json:"clientTime"`
+	Received   time.Time `json:"received"`
+}
+
+func NewQualityNote(degraded bool) ServerNote {
+	method := ServerMethod("qualityRestored")
+	if degraded {
+		method = "qualityDegraded"
+	}
+
+	return ServerNote{
+		Method: method,
+		Params: &Quality{},
+	}
+}
+
+//easyjson:json
+type Quality struct{}
+
+// Roster deltas carry the version they apply on top of (Since) and the version
+// of the room once applied (Version). One change to the room may send more
+// than one delta, and all of them share the same versions; a client whose
+// version is neither Since nor Version has missed a change and should resync.
+// BoardHash is the room's ShortBoardHash at Version.
+//
+// Versions never exceed 2^53-1, so they stay exact as JavaScript numbers. A
+// room which reaches that restarts at 1 and sends everyone a full state, never
+// a delta, so clients should take a state's version as-is rather than expect
+// versions to only increase.
+
+// NewPlayerJoinedNote creates a note adding a player to the end of a team.
+func NewPlayerJoinedNote(since, version int64, boardHash string, team game.Team, player *StatePlayer) ServerNote {
+	return ServerNote{
+		Method: "playerJoined",
+		Params: &PlayerJoined{
+			Since:     since,
+			Version:   version,
+			BoardHash: boardHash,
+			Team:      team,
+			Player:    player,
+		},
+	}
+}
+
+//easyjson:json
+type PlayerJoined struct {
+	Since     int64        `json:"since"`
+	Version   int64        `json:"version"`
+	BoardHash string       `json:"boardHash,omitempty"`
+	Team      game.Team    `json:"team"`
+	Player    *StatePlayer `json:"player"`
+}
+
+// NewPlayerLeftNote creates a note removing a player from the room.
+func NewPlayerLeftNote(since, version int64, boardHash string, playerID game.PlayerID) ServerNote {
+	return ServerNote{
+		Method: "playerLeft",
+		Params: &PlayerLeft{
+			Since:     since,
+			Version:   version,
+			BoardHash: boardHash,
+			PlayerID:  playerID,
+		},
+	}
+}
+
+//easyjson:json
+type PlayerLeft struct {
+	Since     int64         `json:"since"`
+	Version   int64         `json:"version"`
+	BoardHash string        `json:"boardHash,omitempty"`
+	PlayerID  game.PlayerID `json:"playerID"`
+}
+
+// NewPlayerUpdatedNote creates a note replacing a player in place; their team
+// doesn't change.
+func NewPlayerUpdatedNote(since, version int64, boardHash string, team game.Team, player *StatePlayer) ServerNote {
+	return ServerNote{
+		Method: "playerUpdated",
+		Params: &PlayerUpdated{
+			Since:     since,
+			Version:   version,
+			BoardHash: boardHash,
+			Team:      team,
+			Player:    player,
+		},
+	}
+}
+
+//easyjson:json
+type PlayerUpdated struct {
+	Since     int64        `json:"since"`
+	Version   int64        `json:"version"`
+	BoardHash string       `json:"boardHash,omitempty"`
+	Team      game.Team    `json:"team"`
+	Player    *StatePlayer `json:"player"`
+}
+
+func NewNotificationNote(event NotificationEvent, team game.Team) ServerNote {
+	return ServerNote{
+		Method: "notification",
+		Params: &Notification{
+			Event: event,
+			Team:  team,
+		},
+	}
+}
+
+//easyjson:json
+type Notification struct {
+	Event NotificationEvent `json:"event"`
+	Team  game.Team         `json:"team"`
+}
+
+// NewAuditLogNote creates a note with a room's audit log, oldest first.
+func NewAuditLogNote(entries []*AuditEntry) ServerNote {
+	return ServerNote{
+		Method: "auditLog",
+		Params: &AuditLog{
+			Entries: entries,
+		},
+	}
+}
+
+//easyjson:json
+type AuditLog struct {
+	Entries []*AuditEntry `json:"entries"`
+}
+
+// AuditEntry records a privileged action taken in a room. Actor is empty for
+// actions taken by an operator or by the server itself.
+//
+//easyjson:json
+type AuditEntry struct {
+	Time      time.Time      `json:"time"`
+	Action    string         `json:"action"`
+	Actor     game.PlayerID  `json:"actor,omitempty"`
+	ActorName string         `json:"actorName,omitempty"`
+	Target    string         `json:"target,omitempty"`
+	Changes   []*AuditChange `json:"changes,omitempty"`
+}
+
+// AuditChange is a value changed by an audited action.
+type AuditChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// NewBansNote creates a note with a room's ban list, oldest first. It's only
+// ever sent to the host.
+func NewBansNote(bans []*BannedPlayer) ServerNote {
+	return ServerNote{
+		Method: "bans",
+		Params: &Bans{
+			Bans: bans,
+		},
+	}
+}
+
+//easyjson:json
+type Bans struct {
+	Bans []*BannedPlayer `json:"bans"`
+}
+
+// BannedPlayer is a player banned from a room, by the ID and nickname they
+// had when banned.
+type BannedPlayer struct {
+	PlayerID game.PlayerID `json:"playerID"`
+	Nickname string        `json:"nickname"`
+}
+
+// NewFeedbackPromptNote asks a player to rate the game which just ended, with
+// a rateGame command. It's sent, in rooms with feedback enabled, to each
+// player in the room as the game ends.
+func NewFeedbackPromptNote(game int) ServerNote {
+	return ServerNote{
+		Method: "feedbackPrompt",
+		Params: &FeedbackPrompt{
+			Game: game,
+		},
+	}
+}
+
+//easyjson:json
+type FeedbackPrompt struct {
+	Game int `json:"game"`
+}
+
+// NewClueSuggestionsNote creates a note with suggested clues. These are only
+// ever sent to the spymasters of the team whose turn it is.
+func NewClueSuggestionsNote(suggestions []*ClueSuggestion) ServerNote {
+	return ServerNote{
+		Method: "clueSuggestions",
+		Params: &ClueSuggestions{
+			Suggestions: suggestions,
+		},
+	}
+}
+
+//easyjson:json
+type ClueSuggestions struct {
+	Suggestions []*ClueSuggestion `json:"suggestions"`
+}
+
+// ClueSuggestion is a clue the server thinks may be good; it's never
+// automatically given.
+type ClueSuggestion struct {
+	Board int    `json:"board,omitempty"`
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+}
+
+func NewMirrorTokenNote(token string) ServerNote {
+	return ServerNote{
+		Method: "mirrorToken",
+		Params: &MirrorToken{
+			Token: token,
+		},
+	}
+}
+
+//easyjson:json
+type MirrorToken struct {
+	Token string `json:"token"`
+}
+
+// NewWebhookNote creates a note with the room's webhook URL, which is empty if
+// there isn't one, for the host.
+func NewWebhookNote(url string) ServerNote {
+	return ServerNote{
+		Method: "webhook",
+		Params: &Webhook{
+			URL: url,
+		},
+	}
+}
+
+//easyjson:json
+type Webhook struct {
+	URL string `json:"url"`
+}
+
+// NewChatNote creates a note with chat messages, oldest first. Each message is
+// sent to everyone in the room as it's sent; a player joining is sent the
+// room's most recent messages at once.
+func NewChatNote(messages []*ChatMessage) ServerNote {
+	return ServerNote{
+		Method: "chat",
+		Params: &Chat{
+			Messages: messages,
+		},
+	}
+}
+
+//easyjson:json
+type Chat struct {
+	Messages []*ChatMessage `json:"messages"`
+}
+
+// ChatMessage is a chat message, with the nickname its sender had when they
+// sent it and the time the server received it.
+type ChatMessage struct {
+	PlayerID game.PlayerID `json:"playerID"`
+	Nickname string        `json:"nickname"`
+	Text     string        `json:"text"`
+	Time     time.Time     `json:"time"`
+}
+
+// NewPollNote sends a player the room's poll, as it's opened, voted on, and
+// closed, and as they join while it's open.
+func NewPollNote(poll *Poll) ServerNote {
+	return ServerNote{
+		Method: "poll",
+		Params: poll,
+	}
+}
+
+// Poll is a room's poll. Tallies, the votes for each option, are omitted while
+// a hidden poll is open; Votes, the number of players who've voted, never is.
+// Vote is the index of the recipient's own vote, if they've voted.
+//
+//easyjson:json
+type Poll struct {
+	ID       int           `json:"id"`
+	Question string        `json:"question"`
+	Options  []string      `json:"options"`
+	Hidden   bool          `json:"hidden"`
+	Opener   game.PlayerID `json:"opener"`
+	Closes   time.Time     `json:"closes"`
+	Closed   bool          `json:"closed"`
+	Votes    int           `json:"votes"`
+	Tallies  []int         `json:"tallies,omitempty"`
+	Vote     *int          `json:"vote"`
+}
+
+// NewMergeRequestNote asks the host to accept players from another room.
+func NewMergeRequestNote(id, roomName string, players int) ServerNote {
+	return ServerNote{
+		Method: "mergeRequest",
+		Params: &MergeRequest{
+			ID:       id,
+			RoomName: roomName,
+			Players:  players,
+		},
+	}
+}
+
+//easyjson:json
+type MergeRequest struct {
+	ID       string `json:"id"`
+	RoomName string `json:"roomName"`
+	Players  int    `json:"players"`
+}
+
+// NewResumeTokenNote gives a player a token to reconnect with, as the resume
+// query parameter, if their connection drops or the server restarts. It's sent
+// as they join, on servers which hold seats or keep rooms across restarts, and
+// replaces any token sent before.
+func NewResumeTokenNote(token string) ServerNote {
+	return ServerNote{
+		Method: "resumeToken",
+		Params: &ResumeToken{
+			Token: token,
+		},
+	}
+}
+
+//easyjson:json
+type ResumeToken struct {
+	Token string `json:"token"`
+}
+
+// NewMergedNote tells a player their room has been merged into another. The
+// connection is closed right after; the client should reconnect to RoomID
+// with Token as the merge query parameter to keep its seat.
+func NewMergedNote(roomID, token string) ServerNote {
+	return ServerNote{
+		Method: "merged",
+		Params: &Merged{
+			RoomID: roomID,
+			Token:  token,
+		},
+	}
+}
+
+//easyjson:json
+type Merged struct {
+	RoomID string `json:"roomID"`
+	Token  string `json:"token"`
+}
+
+func NewDebugInfoNote(playerID game.PlayerID, bytesSent, bytesReceived int64) ServerNote {
+	return ServerNote{
+		Method: "debugInfo",
+		Params: &DebugInfo{
+			PlayerID: playerID,
+			Bandwidth: Bandwidth{
+				BytesSent:     bytesSent,
+				BytesReceived: bytesReceived,
+			},
+		},
+	}
+}
+
+//easyjson:json
+type DebugInfo struct {
+	PlayerID game.PlayerID `json:"playerID"`
+	Bandwidth
+}
+
+func NewBandwidthNote(bytesSent, bytesReceived int64) ServerNote {
+	return ServerNote{
+		Method: "bandwidth",
+		Params: &Bandwidth{
+			BytesSent:     bytesSent,
+			BytesReceived: bytesReceived,
+		},
+	}
+}
+
+// Bandwidth is the number of bytes on the wire, after compression, from the
+// server's point of view.
+//
+//easyjson:json
+type Bandwidth struct {
+	BytesSent     int64 `json:"bytesSent"`
+	BytesReceived int64 `json:"bytesReceived"`
+}
+
+//easyjson:json
+type State struct {
+	PlayerID  game.PlayerID `json:"playerID"`
+	RoomState *RoomState    `json:"roomState"`
+}
+
+//easyjson:json
+type RoomState struct {
+	Version      int64              `json:"version"`
+	Teams        [][]*StatePlayer   `json:"teams"`
+	Turn         game.Team          `json:"turn"`
+	Winner       *game.Team         `json:"winner"`
+	Wins         []int              `json:"wins"` // Games won in the room, by team.
+	GameOver     *StateGameOver     `json:"gameOver,omitempty"`
+	Board        [][]*StateTile     `json:"board,omitempty"`
+	WordsLeft    []int              `json:"wordsLeft,omitempty"`
+	Boards       []*StateBoard      `json:"boards,omitempty"`
+	TurnBoard    *int               `json:"turnBoard,omitempty"`
+	BoardOptions *StateBoardOptions `json:"boardOptions,omitempty"`
+	Lists        []*StateWordList   `json:"lists,omitempty"`
+	Timer        *StateTimer        `json:"timer"`
+	HideBomb     bool               `json:"hideBomb"`
+	MirrorDelay  int                `json:"mirrorDelay"`
+	Spectators   int                `json:"spectators"` // Updated every few seconds.
+	Clue         *StateClue         `json:"clue"`
+	ClueLog      []*StateLoggedClue `json:"clueLog,omitempty"` // Oldest first; tracker games have theirs in Tracker.
+	BoundClues   bool               `json:"boundClues"`
+	SuggestClues bool               `json:"suggestClues"`
+	Feedback     bool               `json:"feedback"`
+	OpenPolls    bool               `json:"openPolls"`
+	Penalties    *StatePenalties    `json:"penalties"`
+	Hints        *StateHints        `json:"hints"`
+	Spymasters   *StateSpymasters   `json:"spymasters"`
+	Start        *StateStart        `json:"start"`
+
+	// GuessesLeft is the number of guesses left on the clue when the room
+	// enforces guess limits. It's omitted when guesses are unlimited.
+	EnforceGuessLimit bool `json:"enforceGuessLimit"`
+	GuessesLeft       *int `json:"guessesLeft,omitempty"`
+
+	// CanUndo is set while there's a reveal the host may undo.
+	CanUndo bool `json:"canUndo"`
+
+	// BackfillWords is the room's option; Backfill is set when it let the
+	// current game's boards draw words from the base pack.
+	BackfillWords bool           `json:"backfillWords"`
+	Backfill      *StateBackfill `json:"backfill,omitempty"`
+
+	// Webhook is set if the room has a webhook; only the host may read its
+	// URL, with getWebhook.
+	Webhook bool `json:"webhook"`
+
+	// TeamConstraints are the room's team constraints, which may name
+	// players who have left.
+	TeamConstraints []*TeamConstraint `json:"teamConstraints,omitempty"`
+
+	// KeyChecksum is a short checksum of the game's key, only sent to
+	// spymasters so they can compare keys aloud.
+	KeyChecksum string `json:"keyChecksum,omitempty"`
+
+	// Ruleset is the hash of the rules the current game started with; see
+	// Ruleset.
+	Ruleset string `json:"ruleset,omitempty"`
+
+	// BoardHash is ShortBoardHash of this state. It's the same for every
+	// player, whatever their role.
+	BoardHash string `json:"boardHash,omitempty"`
+
+	// Tracker is only set in tracker rooms, which have no board; Board,
+	// WordsLeft, Boards, TurnBoard, BoardOptions, and Lists are omitted.
+	Tracker *StateTracker `json:"tracker,omitempty"`
+
+	Notifications *StateNotifications `json:"notifications"`
+
+	encoded []byte // See Encode.
+}
+
+// StateGameOver summarizes a finished game; it's only set once the game is
+// won. Reason is "words", "bomb", "forfeit", or, in tracker games, "declared".
+// WordsLeft and Key are by board, and omitted for tracker games; Key has every
+// card's view, revealed or not.
+//
+//easyjson:json
+type StateGameOver struct {
+	Winner    game.Team        `json:"winner"`
+	Reason    string           `json:"reason"`
+	Seconds   int64            `json:"seconds"`
+	WordsLeft [][]int          `json:"wordsLeft,omitempty"`
+	Key       [][][]*StateView `json:"key,omitempty"`
+}
+
+// StateBoard is one of the boards in a game with more than one. When there's
+// more than one board, Boards lists all of them, and Board and WordsLeft
+// duplicate the first for clients which only know of one.
+//
+//easyjson:json
+type StateBoard struct {
+	Board     [][]*StateTile `json:"board"`
+	WordsLeft []int          `json:"wordsLeft"`
+}
+
+// StateBoardOptions describes the boards the next new game will have.
+//
+//easyjson:json
+type StateBoardOptions struct {
+	Count int `json:"count"`
+	Rows  int `json:"rows"`
+	Cols  int `json:"cols"`
+	Bombs int `json:"bombs"`
+}
+
+// StateHints describes the teams' hints. It's only included if any team has
+// hints or has used them.
+//
+//easyjson:json
+type StateHints struct {
+	Budget []int `json:"budget"`
+	Left   []int `json:"left"`
+	Used   []int `json:"used"`
+}
+
+// StateTracker is the state of a tracker game: each team's score, and the
+// clues given so far, oldest first.
+//
+//easyjson:json
+type StateTracker struct {
+	Scores []int              `json:"scores"`
+	Clues  []*StateLoggedClue `json:"clues"`
+}
+
+//easyjson:json
+type StateLoggedClue struct {
+	Team  game.Team `json:"team"`
+	Board int       `json:"board,omitempty"`
+	Word  string    `json:"word"`
+	Count int       `json:"count"`
+}
+
+// StateStart describes the players each team needs for a new game to start.
+//
+//easyjson:json
+type StateStart struct {
+	MinSpymasters int `json:"minSpymasters"`
+	MinGuessers   int `json:"minGuessers"`
+}
+
+// StatePenalties describes turn-skip penalties. It's only included if
+// penalties are enabled or the game was forfeited.
+//
+//easyjson:json
+type StatePenalties struct {
+	Limit   int   `json:"limit"`
+	Counts  []int `json:"counts"`
+	Forfeit bool  `json:"forfeit"`
+}
+
+// StateSpymasters describes the spymaster options. Pending is only sent to
+// spymasters.
+//
+//easyjson:json
+type StateSpymasters struct {
+	Limit        int               `json:"limit"`
+	ConfirmClues bool              `json:"confirmClues"`
+	Pending      *StatePendingClue `json:"pending"`
+}
+
+//easyjson:json
+type StatePendingClue struct {
+	Board     int             `json:"board,omitempty"`
+	Word      string          `json:"word"`
+	Count     int             `json:"count"`
+	Confirmed []game.PlayerID `json:"confirmed"`
+}
+
+//easyjson:json
+type StateNotifications struct {
+	Off      bool                `json:"off"`
+	Disabled []NotificationEvent `json:"disabled"`
+}
+
+//easyjson:json
+type StatePlayer struct {
+	PlayerID     game.PlayerID `json:"playerID"`
+	Nickname     string        `json:"nickname"`
+	Spymaster    bool          `json:"spymaster"`
+	Color        int           `json:"color"`
+	Host         bool          `json:"host"`
+	Impersonator bool          `json:"impersonator"`
+	Disconnected bool          `json:"disconnected"` // While their seat is held for them to reconnect to.
+}
+
+//easyjson:json
+type StateTile struct {
+	Word     string     `json:"word"`
+	Revealed bool       `json:"revealed"`
+	View     *StateView `json:"view"`
+	Fit      *StateFit  `json:"fit,omitempty"`
+}
+
+// StateFit is how a tile's word should be displayed, so that every client
+// sizes it the same way.
+//
+//easyjson:json
+type StateFit struct {
+	Class  string `json:"class"`  // "text" or "emoji".
+	Length int    `json:"length"` // In columns for text, clusters for emoji.
+	Size   int    `json:"size"`   // Sizes smaller than full; zero is full size.
+}
+
+//easyjson:json
+type StateView struct {
+	Team    game.Team `json:"team"`
+	Neutral bool      `json:"neutral"`
+	Bomb    bool      `json:"bomb"`
+}
+
+// StateBackfill is the tiles the boards drew from a pack which wasn't enabled,
+// as the enabled packs were short of words.
+//
+//easyjson:json
+type StateBackfill struct {
+	Pack     string `json:"pack"`
+	Language string `json:"language,omitempty"`
+	Count    int    `json:"count"`
+}
+
+//easyjson:json
+type StateWordList struct {
+	Name     string `json:"name"`
+	Language string `json:"language,omitempty"` // See BuiltinPack.
+	Count    int    `json:"count"`
+	Custom   bool   `json:"custom"`
+	Enabled  bool   `json:"enabled"`
+}
+
+//easyjson:json
+type StateTimer struct {
+	TurnTime int       `json:"turnTime"`
+	TurnEnd  time.Time `json:"turnEnd"`
+}
+
+//easyjson:json
+type StateClue struct {
+	Board int    `json:"board,omitempty"`
+	Word  string `json:"word"`
+	Count int    `json:"count"`
 }
@@ -8,6 +8,7 @@ import (
 	jlexer "github.com/mailru/easyjson/jlexer"
 	jwriter "github.com/mailru/easyjson/jwriter"
 	game "github.com/zikaeroh/codies/internal/game"
+	time "time"
 )
 
 // suppress unused package warning
@@ -18,7 +19,7 @@ var (
 	_ easyjson.Marshaler
 )
 
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol(in *jlexer.Lexer, out *TimeResponse) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol(in *jlexer.Lexer, out *Webhook) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -37,10 +38,8 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol(in *jlexer.Le
 			continue
 		}
 		switch key {
-		case "time":
-			if data := in.Raw(); in.Ok() {
-				in.AddError((out.Time).UnmarshalJSON(data))
-			}
+		case "url":
+			out.URL = string(in.String())
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -55,42 +54,42 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol(in *jlexer.Le
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol(out *jwriter.Writer, in TimeResponse) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol(out *jwriter.Writer, in Webhook) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"time\":"
+		const prefix string = ",\"url\":"
 		out.RawString(prefix[1:])
-		out.Raw((in.Time).MarshalJSON())
+		out.String(string(in.URL))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v TimeResponse) MarshalJSON() ([]byte, error) {
+func (v Webhook) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v TimeResponse) MarshalEasyJSON(w *jwriter.Writer) {
+func (v Webhook) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *TimeResponse) UnmarshalJSON(data []byte) error {
+func (v *Webhook) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *TimeResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *Webhook) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol(l, v)
 }
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol1(in *jlexer.Lexer, out *StatsResponse) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol1(in *jlexer.Lexer, out *VoteParams) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -109,10 +108,10 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol1(in *jlexer.L
 			continue
 		}
 		switch key {
-		case "rooms":
-			out.Rooms = int(in.Int())
-		case "clients":
-			out.Clients = int(in.Int())
+		case "poll":
+			out.Poll = int(in.Int())
+		case "option":
+			out.Option = int(in.Int())
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -127,47 +126,47 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol1(in *jlexer.L
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol1(out *jwriter.Writer, in StatsResponse) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol1(out *jwriter.Writer, in VoteParams) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"rooms\":"
+		const prefix string = ",\"poll\":"
 		out.RawString(prefix[1:])
-		out.Int(int(in.Rooms))
+		out.Int(int(in.Poll))
 	}
 	{
-		const prefix string = ",\"clients\":"
+		const prefix string = ",\"option\":"
 		out.RawString(prefix)
-		out.Int(int(in.Clients))
+		out.Int(int(in.Option))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v StatsResponse) MarshalJSON() ([]byte, error) {
+func (v VoteParams) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol1(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v StatsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+func (v VoteParams) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol1(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *StatsResponse) UnmarshalJSON(data []byte) error {
+func (v *VoteParams) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol1(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *StatsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *VoteParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol1(l, v)
 }
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol2(in *jlexer.Lexer, out *StateWordList) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol2(in *jlexer.Lexer, out *UseHintParams) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -186,14 +185,8 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol2(in *jlexer.L
 			continue
 		}
 		switch key {
-		case "name":
-			out.Name = string(in.String())
-		case "count":
-			out.Count = int(in.Int())
-		case "custom":
-			out.Custom = bool(in.Bool())
-		case "enabled":
-			out.Enabled = bool(in.Bool())
+		case "board":
+			out.Board = int(in.Int())
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -208,57 +201,43 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol2(in *jlexer.L
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol2(out *jwriter.Writer, in StateWordList) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol2(out *jwriter.Writer, in UseHintParams) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	{
-		const prefix string = ",\"name\":"
+	if in.Board != 0 {
+		const prefix string = ",\"board\":"
+		first = false
 		out.RawString(prefix[1:])
-		out.String(string(in.Name))
-	}
-	{
-		const prefix string = ",\"count\":"
-		out.RawString(prefix)
-		out.Int(int(in.Count))
-	}
-	{
-		const prefix string = ",\"custom\":"
-		out.RawString(prefix)
-		out.Bool(bool(in.Custom))
-	}
-	{
-		const prefix string = ",\"enabled\":"
-		out.RawString(prefix)
-		out.Bool(bool(in.Enabled))
+		out.Int(int(in.Board))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v StateWordList) MarshalJSON() ([]byte, error) {
+func (v UseHintParams) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol2(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v StateWordList) MarshalEasyJSON(w *jwriter.Writer) {
+func (v UseHintParams) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol2(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *StateWordList) UnmarshalJSON(data []byte) error {
+func (v *UseHintParams) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol2(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *StateWordList) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *UseHintParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol2(l, v)
 }
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol3(in *jlexer.Lexer, out *StateView) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol3(in *jlexer.Lexer, out *UpdateOptionsParams) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -277,12 +256,219 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol3(in *jlexer.L
 			continue
 		}
 		switch key {
-		case "team":
-			out.Team = game.Team(in.Int())
-		case "neutral":
-			out.Neutral = bool(in.Bool())
-		case "bomb":
-			out.Bomb = bool(in.Bool())
+		case "timed":
+			if in.IsNull() {
+				in.Skip()
+				out.Timed = nil
+			} else {
+				if out.Timed == nil {
+					out.Timed = new(bool)
+				}
+				*out.Timed = bool(in.Bool())
+			}
+		case "turnTime":
+			if in.IsNull() {
+				in.Skip()
+				out.TurnTime = nil
+			} else {
+				if out.TurnTime == nil {
+					out.TurnTime = new(int)
+				}
+				*out.TurnTime = int(in.Int())
+			}
+		case "hideBomb":
+			if in.IsNull() {
+				in.Skip()
+				out.HideBomb = nil
+			} else {
+				if out.HideBomb == nil {
+					out.HideBomb = new(bool)
+				}
+				*out.HideBomb = bool(in.Bool())
+			}
+		case "boundClues":
+			if in.IsNull() {
+				in.Skip()
+				out.BoundClues = nil
+			} else {
+				if out.BoundClues == nil {
+					out.BoundClues = new(bool)
+				}
+				*out.BoundClues = bool(in.Bool())
+			}
+		case "suggestClues":
+			if in.IsNull() {
+				in.Skip()
+				out.SuggestClues = nil
+			} else {
+				if out.SuggestClues == nil {
+					out.SuggestClues = new(bool)
+				}
+				*out.SuggestClues = bool(in.Bool())
+			}
+		case "penaltyLimit":
+			if in.IsNull() {
+				in.Skip()
+				out.PenaltyLimit = nil
+			} else {
+				if out.PenaltyLimit == nil {
+					out.PenaltyLimit = new(int)
+				}
+				*out.PenaltyLimit = int(in.Int())
+			}
+		case "spymasterLimit":
+			if in.IsNull() {
+				in.Skip()
+				out.SpymasterLimit = nil
+			} else {
+				if out.SpymasterLimit == nil {
+					out.SpymasterLimit = new(int)
+				}
+				*out.SpymasterLimit = int(in.Int())
+			}
+		case "confirmClues":
+			if in.IsNull() {
+				in.Skip()
+				out.ConfirmClues = nil
+			} else {
+				if out.ConfirmClues == nil {
+					out.ConfirmClues = new(bool)
+				}
+				*out.ConfirmClues = bool(in.Bool())
+			}
+		case "mirrorDelay":
+			if in.IsNull() {
+				in.Skip()
+				out.MirrorDelay = nil
+			} else {
+				if out.MirrorDelay == nil {
+					out.MirrorDelay = new(int)
+				}
+				*out.MirrorDelay = int(in.Int())
+			}
+		case "enforceGuessLimit":
+			if in.IsNull() {
+				in.Skip()
+				out.EnforceGuessLimit = nil
+			} else {
+				if out.EnforceGuessLimit == nil {
+					out.EnforceGuessLimit = new(bool)
+				}
+				*out.EnforceGuessLimit = bool(in.Bool())
+			}
+		case "boards":
+			if in.IsNull() {
+				in.Skip()
+				out.Boards = nil
+			} else {
+				if out.Boards == nil {
+					out.Boards = new(int)
+				}
+				*out.Boards = int(in.Int())
+			}
+		case "rows":
+			if in.IsNull() {
+				in.Skip()
+				out.Rows = nil
+			} else {
+				if out.Rows == nil {
+					out.Rows = new(int)
+				}
+				*out.Rows = int(in.Int())
+			}
+		case "cols":
+			if in.IsNull() {
+				in.Skip()
+				out.Cols = nil
+			} else {
+				if out.Cols == nil {
+					out.Cols = new(int)
+				}
+				*out.Cols = int(in.Int())
+			}
+		case "bombs":
+			if in.IsNull() {
+				in.Skip()
+				out.Bombs = nil
+			} else {
+				if out.Bombs == nil {
+					out.Bombs = new(int)
+				}
+				*out.Bombs = int(in.Int())
+			}
+		case "minSpymasters":
+			if in.IsNull() {
+				in.Skip()
+				out.MinSpymasters = nil
+			} else {
+				if out.MinSpymasters == nil {
+					out.MinSpymasters = new(int)
+				}
+				*out.MinSpymasters = int(in.Int())
+			}
+		case "minGuessers":
+			if in.IsNull() {
+				in.Skip()
+				out.MinGuessers = nil
+			} else {
+				if out.MinGuessers == nil {
+					out.MinGuessers = new(int)
+				}
+				*out.MinGuessers = int(in.Int())
+			}
+		case "hintBudget":
+			if in.IsNull() {
+				in.Skip()
+				out.HintBudget = nil
+			} else {
+				in.Delim('[')
+				if out.HintBudget == nil {
+					if !in.IsDelim(']') {
+						out.HintBudget = make([]int, 0, 8)
+					} else {
+						out.HintBudget = []int{}
+					}
+				} else {
+					out.HintBudget = (out.HintBudget)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 int
+					v1 = int(in.Int())
+					out.HintBudget = append(out.HintBudget, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "feedback":
+			if in.IsNull() {
+				in.Skip()
+				out.Feedback = nil
+			} else {
+				if out.Feedback == nil {
+					out.Feedback = new(bool)
+				}
+				*out.Feedback = bool(in.Bool())
+			}
+		case "openPolls":
+			if in.IsNull() {
+				in.Skip()
+				out.OpenPolls = nil
+			} else {
+				if out.OpenPolls == nil {
+					out.OpenPolls = new(bool)
+				}
+				*out.OpenPolls = bool(in.Bool())
+			}
+		case "backfillWords":
+			if in.IsNull() {
+				in.Skip()
+				out.BackfillWords = nil
+			} else {
+				if out.BackfillWords == nil {
+					out.BackfillWords = new(bool)
+				}
+				*out.BackfillWords = bool(in.Bool())
+			}
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -297,76 +483,260 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol3(in *jlexer.L
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol3(out *jwriter.Writer, in StateView) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol3(out *jwriter.Writer, in UpdateOptionsParams) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	{
-		const prefix string = ",\"team\":"
+	if in.Timed != nil {
+		const prefix string = ",\"timed\":"
+		first = false
 		out.RawString(prefix[1:])
-		out.Int(int(in.Team))
+		out.Bool(bool(*in.Timed))
 	}
-	{
-		const prefix string = ",\"neutral\":"
-		out.RawString(prefix)
-		out.Bool(bool(in.Neutral))
+	if in.TurnTime != nil {
+		const prefix string = ",\"turnTime\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(*in.TurnTime))
 	}
-	{
-		const prefix string = ",\"bomb\":"
-		out.RawString(prefix)
-		out.Bool(bool(in.Bomb))
+	if in.HideBomb != nil {
+		const prefix string = ",\"hideBomb\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(*in.HideBomb))
 	}
-	out.RawByte('}')
-}
-
-// MarshalJSON supports json.Marshaler interface
-func (v StateView) MarshalJSON() ([]byte, error) {
-	w := jwriter.Writer{}
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol3(&w, v)
-	return w.Buffer.BuildBytes(), w.Error
-}
-
-// MarshalEasyJSON supports easyjson.Marshaler interface
-func (v StateView) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol3(w, v)
-}
-
-// UnmarshalJSON supports json.Unmarshaler interface
-func (v *StateView) UnmarshalJSON(data []byte) error {
-	r := jlexer.Lexer{Data: data}
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol3(&r, v)
-	return r.Error()
-}
-
-// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *StateView) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol3(l, v)
-}
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol4(in *jlexer.Lexer, out *StateTimer) {
-	isTopLevel := in.IsStart()
-	if in.IsNull() {
-		if isTopLevel {
-			in.Consumed()
+	if in.BoundClues != nil {
+		const prefix string = ",\"boundClues\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
 		}
-		in.Skip()
-		return
+		out.Bool(bool(*in.BoundClues))
 	}
-	in.Delim('{')
-	for !in.IsDelim('}') {
-		key := in.UnsafeFieldName(false)
-		in.WantColon()
-		if in.IsNull() {
-			in.Skip()
-			in.WantComma()
-			continue
+	if in.SuggestClues != nil {
+		const prefix string = ",\"suggestClues\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
 		}
-		switch key {
-		case "turnTime":
-			out.TurnTime = int(in.Int())
-		case "turnEnd":
-			if data := in.Raw(); in.Ok() {
-				in.AddError((out.TurnEnd).UnmarshalJSON(data))
+		out.Bool(bool(*in.SuggestClues))
+	}
+	if in.PenaltyLimit != nil {
+		const prefix string = ",\"penaltyLimit\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(*in.PenaltyLimit))
+	}
+	if in.SpymasterLimit != nil {
+		const prefix string = ",\"spymasterLimit\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(*in.SpymasterLimit))
+	}
+	if in.ConfirmClues != nil {
+		const prefix string = ",\"confirmClues\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(*in.ConfirmClues))
+	}
+	if in.MirrorDelay != nil {
+		const prefix string = ",\"mirrorDelay\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(*in.MirrorDelay))
+	}
+	if in.EnforceGuessLimit != nil {
+		const prefix string = ",\"enforceGuessLimit\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(*in.EnforceGuessLimit))
+	}
+	if in.Boards != nil {
+		const prefix string = ",\"boards\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(*in.Boards))
+	}
+	if in.Rows != nil {
+		const prefix string = ",\"rows\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(*in.Rows))
+	}
+	if in.Cols != nil {
+		const prefix string = ",\"cols\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(*in.Cols))
+	}
+	if in.Bombs != nil {
+		const prefix string = ",\"bombs\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(*in.Bombs))
+	}
+	if in.MinSpymasters != nil {
+		const prefix string = ",\"minSpymasters\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(*in.MinSpymasters))
+	}
+	if in.MinGuessers != nil {
+		const prefix string = ",\"minGuessers\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(*in.MinGuessers))
+	}
+	if len(in.HintBudget) != 0 {
+		const prefix string = ",\"hintBudget\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v2, v3 := range in.HintBudget {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				out.Int(int(v3))
 			}
+			out.RawByte(']')
+		}
+	}
+	if in.Feedback != nil {
+		const prefix string = ",\"feedback\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(*in.Feedback))
+	}
+	if in.OpenPolls != nil {
+		const prefix string = ",\"openPolls\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(*in.OpenPolls))
+	}
+	if in.BackfillWords != nil {
+		const prefix string = ",\"backfillWords\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(*in.BackfillWords))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v UpdateOptionsParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol3(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UpdateOptionsParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol3(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *UpdateOptionsParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol3(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UpdateOptionsParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol3(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol4(in *jlexer.Lexer, out *UndoParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -381,47 +751,37 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol4(in *jlexer.L
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol4(out *jwriter.Writer, in StateTimer) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol4(out *jwriter.Writer, in UndoParams) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	{
-		const prefix string = ",\"turnTime\":"
-		out.RawString(prefix[1:])
-		out.Int(int(in.TurnTime))
-	}
-	{
-		const prefix string = ",\"turnEnd\":"
-		out.RawString(prefix)
-		out.Raw((in.TurnEnd).MarshalJSON())
-	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v StateTimer) MarshalJSON() ([]byte, error) {
+func (v UndoParams) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol4(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v StateTimer) MarshalEasyJSON(w *jwriter.Writer) {
+func (v UndoParams) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol4(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *StateTimer) UnmarshalJSON(data []byte) error {
+func (v *UndoParams) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol4(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *StateTimer) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *UndoParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol4(l, v)
 }
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol5(in *jlexer.Lexer, out *StateTile) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol5(in *jlexer.Lexer, out *UnbanParams) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -440,20 +800,8 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol5(in *jlexer.L
 			continue
 		}
 		switch key {
-		case "word":
-			out.Word = string(in.String())
-		case "revealed":
-			out.Revealed = bool(in.Bool())
-		case "view":
-			if in.IsNull() {
-				in.Skip()
-				out.View = nil
-			} else {
-				if out.View == nil {
-					out.View = new(StateView)
-				}
-				(*out.View).UnmarshalEasyJSON(in)
-			}
+		case "playerID":
+			out.PlayerID = string(in.String())
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -468,56 +816,42 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol5(in *jlexer.L
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol5(out *jwriter.Writer, in StateTile) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol5(out *jwriter.Writer, in UnbanParams) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"word\":"
+		const prefix string = ",\"playerID\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Word))
-	}
-	{
-		const prefix string = ",\"revealed\":"
-		out.RawString(prefix)
-		out.Bool(bool(in.Revealed))
-	}
-	{
-		const prefix string = ",\"view\":"
-		out.RawString(prefix)
-		if in.View == nil {
-			out.RawString("null")
-		} else {
-			(*in.View).MarshalEasyJSON(out)
-		}
+		out.String(string(in.PlayerID))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v StateTile) MarshalJSON() ([]byte, error) {
+func (v UnbanParams) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol5(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v StateTile) MarshalEasyJSON(w *jwriter.Writer) {
+func (v UnbanParams) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol5(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *StateTile) UnmarshalJSON(data []byte) error {
+func (v *UnbanParams) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol5(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *StateTile) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *UnbanParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol5(l, v)
 }
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol6(in *jlexer.Lexer, out *StatePlayer) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol6(in *jlexer.Lexer, out *TooManyConnsResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -536,12 +870,10 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol6(in *jlexer.L
 			continue
 		}
 		switch key {
-		case "playerID":
-			out.PlayerID = string(in.String())
-		case "nickname":
-			out.Nickname = string(in.String())
-		case "spymaster":
-			out.Spymaster = bool(in.Bool())
+		case "error":
+			out.Error = string(in.String())
+		case "limit":
+			out.Limit = int(in.Int())
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -556,52 +888,47 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol6(in *jlexer.L
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol6(out *jwriter.Writer, in StatePlayer) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol6(out *jwriter.Writer, in TooManyConnsResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"playerID\":"
+		const prefix string = ",\"error\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.PlayerID))
-	}
-	{
-		const prefix string = ",\"nickname\":"
-		out.RawString(prefix)
-		out.String(string(in.Nickname))
+		out.String(string(in.Error))
 	}
 	{
-		const prefix string = ",\"spymaster\":"
+		const prefix string = ",\"limit\":"
 		out.RawString(prefix)
-		out.Bool(bool(in.Spymaster))
+		out.Int(int(in.Limit))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v StatePlayer) MarshalJSON() ([]byte, error) {
+func (v TooManyConnsResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol6(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v StatePlayer) MarshalEasyJSON(w *jwriter.Writer) {
+func (v TooManyConnsResponse) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol6(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *StatePlayer) UnmarshalJSON(data []byte) error {
+func (v *TooManyConnsResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol6(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *StatePlayer) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *TooManyConnsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol6(l, v)
 }
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol7(in *jlexer.Lexer, out *State) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol7(in *jlexer.Lexer, out *TimeSyncParams) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -620,18 +947,8 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol7(in *jlexer.L
 			continue
 		}
 		switch key {
-		case "playerID":
-			out.PlayerID = string(in.String())
-		case "roomState":
-			if in.IsNull() {
-				in.Skip()
-				out.RoomState = nil
-			} else {
-				if out.RoomState == nil {
-					out.RoomState = new(RoomState)
-				}
-				(*out.RoomState).UnmarshalEasyJSON(in)
-			}
+		case "clientTime":
+			out.ClientTime = int64(in.Int64())
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -646,51 +963,42 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol7(in *jlexer.L
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol7(out *jwriter.Writer, in State) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol7(out *jwriter.Writer, in TimeSyncParams) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"playerID\":"
+		const prefix string = ",\"clientTime\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.PlayerID))
-	}
-	{
-		const prefix string = ",\"roomState\":"
-		out.RawString(prefix)
-		if in.RoomState == nil {
-			out.RawString("null")
-		} else {
-			(*in.RoomState).MarshalEasyJSON(out)
-		}
+		out.Int64(int64(in.ClientTime))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v State) MarshalJSON() ([]byte, error) {
+func (v TimeSyncParams) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol7(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v State) MarshalEasyJSON(w *jwriter.Writer) {
+func (v TimeSyncParams) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol7(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *State) UnmarshalJSON(data []byte) error {
+func (v *TimeSyncParams) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol7(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *State) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *TimeSyncParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol7(l, v)
 }
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol8(in *jlexer.Lexer, out *ServerNote) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol8(in *jlexer.Lexer, out *TimeSync) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -709,15 +1017,11 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol8(in *jlexer.L
 			continue
 		}
 		switch key {
-		case "method":
-			out.Method = ServerMethod(in.String())
-		case "params":
-			if m, ok := out.Params.(easyjson.Unmarshaler); ok {
-				m.UnmarshalEasyJSON(in)
-			} else if m, ok := out.Params.(json.Unmarshaler); ok {
-				_ = m.UnmarshalJSON(in.Raw())
-			} else {
-				out.Params = in.Interface()
+		case "clientTime":
+			out.ClientTime = int64(in.Int64())
+		case "received":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Received).UnmarshalJSON(data))
 			}
 		default:
 			in.AddError(&jlexer.LexerError{
@@ -733,53 +1037,47 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol8(in *jlexer.L
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol8(out *jwriter.Writer, in ServerNote) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol8(out *jwriter.Writer, in TimeSync) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"method\":"
+		const prefix string = ",\"clientTime\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Method))
+		out.Int64(int64(in.ClientTime))
 	}
 	{
-		const prefix string = ",\"params\":"
+		const prefix string = ",\"received\":"
 		out.RawString(prefix)
-		if m, ok := in.Params.(easyjson.Marshaler); ok {
-			m.MarshalEasyJSON(out)
-		} else if m, ok := in.Params.(json.Marshaler); ok {
-			out.Raw(m.MarshalJSON())
-		} else {
-			out.Raw(json.Marshal(in.Params))
-		}
+		out.Raw((in.Received).MarshalJSON())
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ServerNote) MarshalJSON() ([]byte, error) {
+func (v TimeSync) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol8(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ServerNote) MarshalEasyJSON(w *jwriter.Writer) {
+func (v TimeSync) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol8(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ServerNote) UnmarshalJSON(data []byte) error {
+func (v *TimeSync) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol8(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ServerNote) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *TimeSync) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol8(l, v)
 }
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol9(in *jlexer.Lexer, out *RoomState) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol9(in *jlexer.Lexer, out *TimeResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -798,190 +1096,10 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol9(in *jlexer.L
 			continue
 		}
 		switch key {
-		case "version":
-			out.Version = int(in.Int())
-		case "teams":
-			if in.IsNull() {
-				in.Skip()
-				out.Teams = nil
-			} else {
-				in.Delim('[')
-				if out.Teams == nil {
-					if !in.IsDelim(']') {
-						out.Teams = make([][]*StatePlayer, 0, 2)
-					} else {
-						out.Teams = [][]*StatePlayer{}
-					}
-				} else {
-					out.Teams = (out.Teams)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v1 []*StatePlayer
-					if in.IsNull() {
-						in.Skip()
-						v1 = nil
-					} else {
-						in.Delim('[')
-						if v1 == nil {
-							if !in.IsDelim(']') {
-								v1 = make([]*StatePlayer, 0, 8)
-							} else {
-								v1 = []*StatePlayer{}
-							}
-						} else {
-							v1 = (v1)[:0]
-						}
-						for !in.IsDelim(']') {
-							var v2 *StatePlayer
-							if in.IsNull() {
-								in.Skip()
-								v2 = nil
-							} else {
-								if v2 == nil {
-									v2 = new(StatePlayer)
-								}
-								(*v2).UnmarshalEasyJSON(in)
-							}
-							v1 = append(v1, v2)
-							in.WantComma()
-						}
-						in.Delim(']')
-					}
-					out.Teams = append(out.Teams, v1)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
-		case "turn":
-			out.Turn = game.Team(in.Int())
-		case "winner":
-			if in.IsNull() {
-				in.Skip()
-				out.Winner = nil
-			} else {
-				if out.Winner == nil {
-					out.Winner = new(game.Team)
-				}
-				*out.Winner = game.Team(in.Int())
-			}
-		case "board":
-			if in.IsNull() {
-				in.Skip()
-				out.Board = nil
-			} else {
-				in.Delim('[')
-				if out.Board == nil {
-					if !in.IsDelim(']') {
-						out.Board = make([][]*StateTile, 0, 2)
-					} else {
-						out.Board = [][]*StateTile{}
-					}
-				} else {
-					out.Board = (out.Board)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v3 []*StateTile
-					if in.IsNull() {
-						in.Skip()
-						v3 = nil
-					} else {
-						in.Delim('[')
-						if v3 == nil {
-							if !in.IsDelim(']') {
-								v3 = make([]*StateTile, 0, 8)
-							} else {
-								v3 = []*StateTile{}
-							}
-						} else {
-							v3 = (v3)[:0]
-						}
-						for !in.IsDelim(']') {
-							var v4 *StateTile
-							if in.IsNull() {
-								in.Skip()
-								v4 = nil
-							} else {
-								if v4 == nil {
-									v4 = new(StateTile)
-								}
-								(*v4).UnmarshalEasyJSON(in)
-							}
-							v3 = append(v3, v4)
-							in.WantComma()
-						}
-						in.Delim(']')
-					}
-					out.Board = append(out.Board, v3)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
-		case "wordsLeft":
-			if in.IsNull() {
-				in.Skip()
-				out.WordsLeft = nil
-			} else {
-				in.Delim('[')
-				if out.WordsLeft == nil {
-					if !in.IsDelim(']') {
-						out.WordsLeft = make([]int, 0, 8)
-					} else {
-						out.WordsLeft = []int{}
-					}
-				} else {
-					out.WordsLeft = (out.WordsLeft)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v5 int
-					v5 = int(in.Int())
-					out.WordsLeft = append(out.WordsLeft, v5)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
-		case "lists":
-			if in.IsNull() {
-				in.Skip()
-				out.Lists = nil
-			} else {
-				in.Delim('[')
-				if out.Lists == nil {
-					if !in.IsDelim(']') {
-						out.Lists = make([]*StateWordList, 0, 8)
-					} else {
-						out.Lists = []*StateWordList{}
-					}
-				} else {
-					out.Lists = (out.Lists)[:0]
-				}
-				for !in.IsDelim(']') {
-					var v6 *StateWordList
-					if in.IsNull() {
-						in.Skip()
-						v6 = nil
-					} else {
-						if v6 == nil {
-							v6 = new(StateWordList)
-						}
-						(*v6).UnmarshalEasyJSON(in)
-					}
-					out.Lists = append(out.Lists, v6)
-					in.WantComma()
-				}
-				in.Delim(']')
-			}
-		case "timer":
-			if in.IsNull() {
-				in.Skip()
-				out.Timer = nil
-			} else {
-				if out.Timer == nil {
-					out.Timer = new(StateTimer)
-				}
-				(*out.Timer).UnmarshalEasyJSON(in)
+		case "time":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Time).UnmarshalJSON(data))
 			}
-		case "hideBomb":
-			out.HideBomb = bool(in.Bool())
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -996,168 +1114,10074 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol9(in *jlexer.L
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol9(out *jwriter.Writer, in RoomState) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol9(out *jwriter.Writer, in TimeResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"time\":"
+		out.RawString(prefix[1:])
+		out.Raw((in.Time).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v TimeResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol9(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v TimeResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol9(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *TimeResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol9(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *TimeResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol9(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol10(in *jlexer.Lexer, out *TeamConstraint) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "a":
+			out.A = string(in.String())
+		case "b":
+			out.B = string(in.String())
+		case "apart":
+			out.Apart = bool(in.Bool())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol10(out *jwriter.Writer, in TeamConstraint) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"a\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.A))
+	}
+	{
+		const prefix string = ",\"b\":"
+		out.RawString(prefix)
+		out.String(string(in.B))
+	}
+	if in.Apart {
+		const prefix string = ",\"apart\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Apart))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v TeamConstraint) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol10(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v TeamConstraint) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol10(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *TeamConstraint) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol10(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *TeamConstraint) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol10(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol11(in *jlexer.Lexer, out *SuggestWordParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "word":
+			out.Word = string(in.String())
+		case "pack":
+			out.Pack = string(in.String())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol11(out *jwriter.Writer, in SuggestWordParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"word\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Word))
+	}
+	{
+		const prefix string = ",\"pack\":"
+		out.RawString(prefix)
+		out.String(string(in.Pack))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SuggestWordParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol11(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SuggestWordParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol11(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SuggestWordParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol11(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SuggestWordParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol11(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol12(in *jlexer.Lexer, out *StatsResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "rooms":
+			out.Rooms = int(in.Int())
+		case "clients":
+			out.Clients = int(in.Int())
+		case "spectators":
+			out.Spectators = int(in.Int())
+		case "matchmaking":
+			out.Matchmaking = int(in.Int())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol12(out *jwriter.Writer, in StatsResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"rooms\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Rooms))
+	}
+	{
+		const prefix string = ",\"clients\":"
+		out.RawString(prefix)
+		out.Int(int(in.Clients))
+	}
+	{
+		const prefix string = ",\"spectators\":"
+		out.RawString(prefix)
+		out.Int(int(in.Spectators))
+	}
+	{
+		const prefix string = ",\"matchmaking\":"
+		out.RawString(prefix)
+		out.Int(int(in.Matchmaking))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StatsResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol12(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StatsResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol12(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StatsResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol12(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StatsResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol12(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol13(in *jlexer.Lexer, out *StateWordList) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "name":
+			out.Name = string(in.String())
+		case "language":
+			out.Language = string(in.String())
+		case "count":
+			out.Count = int(in.Int())
+		case "custom":
+			out.Custom = bool(in.Bool())
+		case "enabled":
+			out.Enabled = bool(in.Bool())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol13(out *jwriter.Writer, in StateWordList) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	if in.Language != "" {
+		const prefix string = ",\"language\":"
+		out.RawString(prefix)
+		out.String(string(in.Language))
+	}
+	{
+		const prefix string = ",\"count\":"
+		out.RawString(prefix)
+		out.Int(int(in.Count))
+	}
+	{
+		const prefix string = ",\"custom\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Custom))
+	}
+	{
+		const prefix string = ",\"enabled\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Enabled))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StateWordList) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol13(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StateWordList) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol13(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StateWordList) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol13(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StateWordList) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol13(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol14(in *jlexer.Lexer, out *StateView) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "team":
+			out.Team = game.Team(in.Int())
+		case "neutral":
+			out.Neutral = bool(in.Bool())
+		case "bomb":
+			out.Bomb = bool(in.Bool())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol14(out *jwriter.Writer, in StateView) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"team\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Team))
+	}
+	{
+		const prefix string = ",\"neutral\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Neutral))
+	}
+	{
+		const prefix string = ",\"bomb\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Bomb))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StateView) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol14(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StateView) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol14(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StateView) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol14(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StateView) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol14(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol15(in *jlexer.Lexer, out *StateTracker) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "scores":
+			if in.IsNull() {
+				in.Skip()
+				out.Scores = nil
+			} else {
+				in.Delim('[')
+				if out.Scores == nil {
+					if !in.IsDelim(']') {
+						out.Scores = make([]int, 0, 8)
+					} else {
+						out.Scores = []int{}
+					}
+				} else {
+					out.Scores = (out.Scores)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 int
+					v4 = int(in.Int())
+					out.Scores = append(out.Scores, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "clues":
+			if in.IsNull() {
+				in.Skip()
+				out.Clues = nil
+			} else {
+				in.Delim('[')
+				if out.Clues == nil {
+					if !in.IsDelim(']') {
+						out.Clues = make([]*StateLoggedClue, 0, 8)
+					} else {
+						out.Clues = []*StateLoggedClue{}
+					}
+				} else {
+					out.Clues = (out.Clues)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v5 *StateLoggedClue
+					if in.IsNull() {
+						in.Skip()
+						v5 = nil
+					} else {
+						if v5 == nil {
+							v5 = new(StateLoggedClue)
+						}
+						(*v5).UnmarshalEasyJSON(in)
+					}
+					out.Clues = append(out.Clues, v5)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol15(out *jwriter.Writer, in StateTracker) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"scores\":"
+		out.RawString(prefix[1:])
+		if in.Scores == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v6, v7 := range in.Scores {
+				if v6 > 0 {
+					out.RawByte(',')
+				}
+				out.Int(int(v7))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"clues\":"
+		out.RawString(prefix)
+		if in.Clues == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v8, v9 := range in.Clues {
+				if v8 > 0 {
+					out.RawByte(',')
+				}
+				if v9 == nil {
+					out.RawString("null")
+				} else {
+					(*v9).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StateTracker) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol15(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StateTracker) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol15(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StateTracker) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol15(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StateTracker) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol15(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol16(in *jlexer.Lexer, out *StateTimer) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "turnTime":
+			out.TurnTime = int(in.Int())
+		case "turnEnd":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.TurnEnd).UnmarshalJSON(data))
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol16(out *jwriter.Writer, in StateTimer) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"turnTime\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.TurnTime))
+	}
+	{
+		const prefix string = ",\"turnEnd\":"
+		out.RawString(prefix)
+		out.Raw((in.TurnEnd).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StateTimer) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol16(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StateTimer) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol16(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StateTimer) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol16(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StateTimer) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol16(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol17(in *jlexer.Lexer, out *StateTile) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "word":
+			out.Word = string(in.String())
+		case "revealed":
+			out.Revealed = bool(in.Bool())
+		case "view":
+			if in.IsNull() {
+				in.Skip()
+				out.View = nil
+			} else {
+				if out.View == nil {
+					out.View = new(StateView)
+				}
+				(*out.View).UnmarshalEasyJSON(in)
+			}
+		case "fit":
+			if in.IsNull() {
+				in.Skip()
+				out.Fit = nil
+			} else {
+				if out.Fit == nil {
+					out.Fit = new(StateFit)
+				}
+				(*out.Fit).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol17(out *jwriter.Writer, in StateTile) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"word\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Word))
+	}
+	{
+		const prefix string = ",\"revealed\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Revealed))
+	}
+	{
+		const prefix string = ",\"view\":"
+		out.RawString(prefix)
+		if in.View == nil {
+			out.RawString("null")
+		} else {
+			(*in.View).MarshalEasyJSON(out)
+		}
+	}
+	if in.Fit != nil {
+		const prefix string = ",\"fit\":"
+		out.RawString(prefix)
+		(*in.Fit).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StateTile) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol17(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StateTile) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol17(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StateTile) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol17(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StateTile) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol17(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol18(in *jlexer.Lexer, out *StateStart) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "minSpymasters":
+			out.MinSpymasters = int(in.Int())
+		case "minGuessers":
+			out.MinGuessers = int(in.Int())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol18(out *jwriter.Writer, in StateStart) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"minSpymasters\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.MinSpymasters))
+	}
+	{
+		const prefix string = ",\"minGuessers\":"
+		out.RawString(prefix)
+		out.Int(int(in.MinGuessers))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StateStart) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol18(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StateStart) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol18(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StateStart) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol18(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StateStart) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol18(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol19(in *jlexer.Lexer, out *StateSpymasters) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "limit":
+			out.Limit = int(in.Int())
+		case "confirmClues":
+			out.ConfirmClues = bool(in.Bool())
+		case "pending":
+			if in.IsNull() {
+				in.Skip()
+				out.Pending = nil
+			} else {
+				if out.Pending == nil {
+					out.Pending = new(StatePendingClue)
+				}
+				(*out.Pending).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol19(out *jwriter.Writer, in StateSpymasters) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"limit\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"confirmClues\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.ConfirmClues))
+	}
+	{
+		const prefix string = ",\"pending\":"
+		out.RawString(prefix)
+		if in.Pending == nil {
+			out.RawString("null")
+		} else {
+			(*in.Pending).MarshalEasyJSON(out)
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StateSpymasters) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol19(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StateSpymasters) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol19(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StateSpymasters) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol19(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StateSpymasters) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol19(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol20(in *jlexer.Lexer, out *StatePlayer) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "playerID":
+			out.PlayerID = string(in.String())
+		case "nickname":
+			out.Nickname = string(in.String())
+		case "spymaster":
+			out.Spymaster = bool(in.Bool())
+		case "color":
+			out.Color = int(in.Int())
+		case "host":
+			out.Host = bool(in.Bool())
+		case "impersonator":
+			out.Impersonator = bool(in.Bool())
+		case "disconnected":
+			out.Disconnected = bool(in.Bool())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol20(out *jwriter.Writer, in StatePlayer) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"playerID\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.PlayerID))
+	}
+	{
+		const prefix string = ",\"nickname\":"
+		out.RawString(prefix)
+		out.String(string(in.Nickname))
+	}
+	{
+		const prefix string = ",\"spymaster\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Spymaster))
+	}
+	{
+		const prefix string = ",\"color\":"
+		out.RawString(prefix)
+		out.Int(int(in.Color))
+	}
+	{
+		const prefix string = ",\"host\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Host))
+	}
+	{
+		const prefix string = ",\"impersonator\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Impersonator))
+	}
+	{
+		const prefix string = ",\"disconnected\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Disconnected))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StatePlayer) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol20(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StatePlayer) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol20(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StatePlayer) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol20(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StatePlayer) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol20(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol21(in *jlexer.Lexer, out *StatePendingClue) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "board":
+			out.Board = int(in.Int())
+		case "word":
+			out.Word = string(in.String())
+		case "count":
+			out.Count = int(in.Int())
+		case "confirmed":
+			if in.IsNull() {
+				in.Skip()
+				out.Confirmed = nil
+			} else {
+				in.Delim('[')
+				if out.Confirmed == nil {
+					if !in.IsDelim(']') {
+						out.Confirmed = make([]string, 0, 4)
+					} else {
+						out.Confirmed = []string{}
+					}
+				} else {
+					out.Confirmed = (out.Confirmed)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v10 string
+					v10 = string(in.String())
+					out.Confirmed = append(out.Confirmed, v10)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol21(out *jwriter.Writer, in StatePendingClue) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Board != 0 {
+		const prefix string = ",\"board\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.Int(int(in.Board))
+	}
+	{
+		const prefix string = ",\"word\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Word))
+	}
+	{
+		const prefix string = ",\"count\":"
+		out.RawString(prefix)
+		out.Int(int(in.Count))
+	}
+	{
+		const prefix string = ",\"confirmed\":"
+		out.RawString(prefix)
+		if in.Confirmed == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v11, v12 := range in.Confirmed {
+				if v11 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v12))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StatePendingClue) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol21(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StatePendingClue) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol21(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StatePendingClue) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol21(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StatePendingClue) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol21(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol22(in *jlexer.Lexer, out *StatePenalties) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "limit":
+			out.Limit = int(in.Int())
+		case "counts":
+			if in.IsNull() {
+				in.Skip()
+				out.Counts = nil
+			} else {
+				in.Delim('[')
+				if out.Counts == nil {
+					if !in.IsDelim(']') {
+						out.Counts = make([]int, 0, 8)
+					} else {
+						out.Counts = []int{}
+					}
+				} else {
+					out.Counts = (out.Counts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v13 int
+					v13 = int(in.Int())
+					out.Counts = append(out.Counts, v13)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "forfeit":
+			out.Forfeit = bool(in.Bool())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol22(out *jwriter.Writer, in StatePenalties) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"limit\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"counts\":"
+		out.RawString(prefix)
+		if in.Counts == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v14, v15 := range in.Counts {
+				if v14 > 0 {
+					out.RawByte(',')
+				}
+				out.Int(int(v15))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"forfeit\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Forfeit))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StatePenalties) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol22(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StatePenalties) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol22(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StatePenalties) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol22(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StatePenalties) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol22(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol23(in *jlexer.Lexer, out *StateNotifications) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "off":
+			out.Off = bool(in.Bool())
+		case "disabled":
+			if in.IsNull() {
+				in.Skip()
+				out.Disabled = nil
+			} else {
+				in.Delim('[')
+				if out.Disabled == nil {
+					if !in.IsDelim(']') {
+						out.Disabled = make([]NotificationEvent, 0, 4)
+					} else {
+						out.Disabled = []NotificationEvent{}
+					}
+				} else {
+					out.Disabled = (out.Disabled)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v16 NotificationEvent
+					v16 = NotificationEvent(in.String())
+					out.Disabled = append(out.Disabled, v16)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol23(out *jwriter.Writer, in StateNotifications) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"off\":"
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.Off))
+	}
+	{
+		const prefix string = ",\"disabled\":"
+		out.RawString(prefix)
+		if in.Disabled == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v17, v18 := range in.Disabled {
+				if v17 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v18))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StateNotifications) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol23(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StateNotifications) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol23(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StateNotifications) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol23(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StateNotifications) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol23(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol24(in *jlexer.Lexer, out *StateLoggedClue) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "team":
+			out.Team = game.Team(in.Int())
+		case "board":
+			out.Board = int(in.Int())
+		case "word":
+			out.Word = string(in.String())
+		case "count":
+			out.Count = int(in.Int())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol24(out *jwriter.Writer, in StateLoggedClue) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"team\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Team))
+	}
+	if in.Board != 0 {
+		const prefix string = ",\"board\":"
+		out.RawString(prefix)
+		out.Int(int(in.Board))
+	}
+	{
+		const prefix string = ",\"word\":"
+		out.RawString(prefix)
+		out.String(string(in.Word))
+	}
+	{
+		const prefix string = ",\"count\":"
+		out.RawString(prefix)
+		out.Int(int(in.Count))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StateLoggedClue) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol24(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StateLoggedClue) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol24(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StateLoggedClue) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol24(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StateLoggedClue) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol24(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol25(in *jlexer.Lexer, out *StateHints) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "budget":
+			if in.IsNull() {
+				in.Skip()
+				out.Budget = nil
+			} else {
+				in.Delim('[')
+				if out.Budget == nil {
+					if !in.IsDelim(']') {
+						out.Budget = make([]int, 0, 8)
+					} else {
+						out.Budget = []int{}
+					}
+				} else {
+					out.Budget = (out.Budget)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v19 int
+					v19 = int(in.Int())
+					out.Budget = append(out.Budget, v19)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "left":
+			if in.IsNull() {
+				in.Skip()
+				out.Left = nil
+			} else {
+				in.Delim('[')
+				if out.Left == nil {
+					if !in.IsDelim(']') {
+						out.Left = make([]int, 0, 8)
+					} else {
+						out.Left = []int{}
+					}
+				} else {
+					out.Left = (out.Left)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v20 int
+					v20 = int(in.Int())
+					out.Left = append(out.Left, v20)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "used":
+			if in.IsNull() {
+				in.Skip()
+				out.Used = nil
+			} else {
+				in.Delim('[')
+				if out.Used == nil {
+					if !in.IsDelim(']') {
+						out.Used = make([]int, 0, 8)
+					} else {
+						out.Used = []int{}
+					}
+				} else {
+					out.Used = (out.Used)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v21 int
+					v21 = int(in.Int())
+					out.Used = append(out.Used, v21)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol25(out *jwriter.Writer, in StateHints) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"budget\":"
+		out.RawString(prefix[1:])
+		if in.Budget == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v22, v23 := range in.Budget {
+				if v22 > 0 {
+					out.RawByte(',')
+				}
+				out.Int(int(v23))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"left\":"
+		out.RawString(prefix)
+		if in.Left == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v24, v25 := range in.Left {
+				if v24 > 0 {
+					out.RawByte(',')
+				}
+				out.Int(int(v25))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"used\":"
+		out.RawString(prefix)
+		if in.Used == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v26, v27 := range in.Used {
+				if v26 > 0 {
+					out.RawByte(',')
+				}
+				out.Int(int(v27))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StateHints) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol25(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StateHints) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol25(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StateHints) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol25(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StateHints) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol25(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol26(in *jlexer.Lexer, out *StateGameOver) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "winner":
+			out.Winner = game.Team(in.Int())
+		case "reason":
+			out.Reason = string(in.String())
+		case "seconds":
+			out.Seconds = int64(in.Int64())
+		case "wordsLeft":
+			if in.IsNull() {
+				in.Skip()
+				out.WordsLeft = nil
+			} else {
+				in.Delim('[')
+				if out.WordsLeft == nil {
+					if !in.IsDelim(']') {
+						out.WordsLeft = make([][]int, 0, 2)
+					} else {
+						out.WordsLeft = [][]int{}
+					}
+				} else {
+					out.WordsLeft = (out.WordsLeft)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v28 []int
+					if in.IsNull() {
+						in.Skip()
+						v28 = nil
+					} else {
+						in.Delim('[')
+						if v28 == nil {
+							if !in.IsDelim(']') {
+								v28 = make([]int, 0, 8)
+							} else {
+								v28 = []int{}
+							}
+						} else {
+							v28 = (v28)[:0]
+						}
+						for !in.IsDelim(']') {
+							var v29 int
+							v29 = int(in.Int())
+							v28 = append(v28, v29)
+							in.WantComma()
+						}
+						in.Delim(']')
+					}
+					out.WordsLeft = append(out.WordsLeft, v28)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "key":
+			if in.IsNull() {
+				in.Skip()
+				out.Key = nil
+			} else {
+				in.Delim('[')
+				if out.Key == nil {
+					if !in.IsDelim(']') {
+						out.Key = make([][][]*StateView, 0, 2)
+					} else {
+						out.Key = [][][]*StateView{}
+					}
+				} else {
+					out.Key = (out.Key)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v30 [][]*StateView
+					if in.IsNull() {
+						in.Skip()
+						v30 = nil
+					} else {
+						in.Delim('[')
+						if v30 == nil {
+							if !in.IsDelim(']') {
+								v30 = make([][]*StateView, 0, 2)
+							} else {
+								v30 = [][]*StateView{}
+							}
+						} else {
+							v30 = (v30)[:0]
+						}
+						for !in.IsDelim(']') {
+							var v31 []*StateView
+							if in.IsNull() {
+								in.Skip()
+								v31 = nil
+							} else {
+								in.Delim('[')
+								if v31 == nil {
+									if !in.IsDelim(']') {
+										v31 = make([]*StateView, 0, 8)
+									} else {
+										v31 = []*StateView{}
+									}
+								} else {
+									v31 = (v31)[:0]
+								}
+								for !in.IsDelim(']') {
+									var v32 *StateView
+									if in.IsNull() {
+										in.Skip()
+										v32 = nil
+									} else {
+										if v32 == nil {
+											v32 = new(StateView)
+										}
+										(*v32).UnmarshalEasyJSON(in)
+									}
+									v31 = append(v31, v32)
+									in.WantComma()
+								}
+								in.Delim(']')
+							}
+							v30 = append(v30, v31)
+							in.WantComma()
+						}
+						in.Delim(']')
+					}
+					out.Key = append(out.Key, v30)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol26(out *jwriter.Writer, in StateGameOver) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"winner\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Winner))
+	}
+	{
+		const prefix string = ",\"reason\":"
+		out.RawString(prefix)
+		out.String(string(in.Reason))
+	}
+	{
+		const prefix string = ",\"seconds\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Seconds))
+	}
+	if len(in.WordsLeft) != 0 {
+		const prefix string = ",\"wordsLeft\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v33, v34 := range in.WordsLeft {
+				if v33 > 0 {
+					out.RawByte(',')
+				}
+				if v34 == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+					out.RawString("null")
+				} else {
+					out.RawByte('[')
+					for v35, v36 := range v34 {
+						if v35 > 0 {
+							out.RawByte(',')
+						}
+						out.Int(int(v36))
+					}
+					out.RawByte(']')
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Key) != 0 {
+		const prefix string = ",\"key\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v37, v38 := range in.Key {
+				if v37 > 0 {
+					out.RawByte(',')
+				}
+				if v38 == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+					out.RawString("null")
+				} else {
+					out.RawByte('[')
+					for v39, v40 := range v38 {
+						if v39 > 0 {
+							out.RawByte(',')
+						}
+						if v40 == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+							out.RawString("null")
+						} else {
+							out.RawByte('[')
+							for v41, v42 := range v40 {
+								if v41 > 0 {
+									out.RawByte(',')
+								}
+								if v42 == nil {
+									out.RawString("null")
+								} else {
+									(*v42).MarshalEasyJSON(out)
+								}
+							}
+							out.RawByte(']')
+						}
+					}
+					out.RawByte(']')
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StateGameOver) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol26(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StateGameOver) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol26(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StateGameOver) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol26(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StateGameOver) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol26(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol27(in *jlexer.Lexer, out *StateFit) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "class":
+			out.Class = string(in.String())
+		case "length":
+			out.Length = int(in.Int())
+		case "size":
+			out.Size = int(in.Int())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol27(out *jwriter.Writer, in StateFit) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"class\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Class))
+	}
+	{
+		const prefix string = ",\"length\":"
+		out.RawString(prefix)
+		out.Int(int(in.Length))
+	}
+	{
+		const prefix string = ",\"size\":"
+		out.RawString(prefix)
+		out.Int(int(in.Size))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StateFit) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol27(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StateFit) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol27(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StateFit) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol27(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StateFit) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol27(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol28(in *jlexer.Lexer, out *StateClue) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "board":
+			out.Board = int(in.Int())
+		case "word":
+			out.Word = string(in.String())
+		case "count":
+			out.Count = int(in.Int())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol28(out *jwriter.Writer, in StateClue) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Board != 0 {
+		const prefix string = ",\"board\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.Int(int(in.Board))
+	}
+	{
+		const prefix string = ",\"word\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Word))
+	}
+	{
+		const prefix string = ",\"count\":"
+		out.RawString(prefix)
+		out.Int(int(in.Count))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StateClue) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol28(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StateClue) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol28(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StateClue) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol28(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StateClue) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol28(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol29(in *jlexer.Lexer, out *StateBoardOptions) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "count":
+			out.Count = int(in.Int())
+		case "rows":
+			out.Rows = int(in.Int())
+		case "cols":
+			out.Cols = int(in.Int())
+		case "bombs":
+			out.Bombs = int(in.Int())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol29(out *jwriter.Writer, in StateBoardOptions) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"count\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Count))
+	}
+	{
+		const prefix string = ",\"rows\":"
+		out.RawString(prefix)
+		out.Int(int(in.Rows))
+	}
+	{
+		const prefix string = ",\"cols\":"
+		out.RawString(prefix)
+		out.Int(int(in.Cols))
+	}
+	{
+		const prefix string = ",\"bombs\":"
+		out.RawString(prefix)
+		out.Int(int(in.Bombs))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StateBoardOptions) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol29(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StateBoardOptions) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol29(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StateBoardOptions) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol29(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StateBoardOptions) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol29(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol30(in *jlexer.Lexer, out *StateBoard) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "board":
+			if in.IsNull() {
+				in.Skip()
+				out.Board = nil
+			} else {
+				in.Delim('[')
+				if out.Board == nil {
+					if !in.IsDelim(']') {
+						out.Board = make([][]*StateTile, 0, 2)
+					} else {
+						out.Board = [][]*StateTile{}
+					}
+				} else {
+					out.Board = (out.Board)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v43 []*StateTile
+					if in.IsNull() {
+						in.Skip()
+						v43 = nil
+					} else {
+						in.Delim('[')
+						if v43 == nil {
+							if !in.IsDelim(']') {
+								v43 = make([]*StateTile, 0, 8)
+							} else {
+								v43 = []*StateTile{}
+							}
+						} else {
+							v43 = (v43)[:0]
+						}
+						for !in.IsDelim(']') {
+							var v44 *StateTile
+							if in.IsNull() {
+								in.Skip()
+								v44 = nil
+							} else {
+								if v44 == nil {
+									v44 = new(StateTile)
+								}
+								(*v44).UnmarshalEasyJSON(in)
+							}
+							v43 = append(v43, v44)
+							in.WantComma()
+						}
+						in.Delim(']')
+					}
+					out.Board = append(out.Board, v43)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "wordsLeft":
+			if in.IsNull() {
+				in.Skip()
+				out.WordsLeft = nil
+			} else {
+				in.Delim('[')
+				if out.WordsLeft == nil {
+					if !in.IsDelim(']') {
+						out.WordsLeft = make([]int, 0, 8)
+					} else {
+						out.WordsLeft = []int{}
+					}
+				} else {
+					out.WordsLeft = (out.WordsLeft)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v45 int
+					v45 = int(in.Int())
+					out.WordsLeft = append(out.WordsLeft, v45)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol30(out *jwriter.Writer, in StateBoard) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"board\":"
+		out.RawString(prefix[1:])
+		if in.Board == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v46, v47 := range in.Board {
+				if v46 > 0 {
+					out.RawByte(',')
+				}
+				if v47 == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+					out.RawString("null")
+				} else {
+					out.RawByte('[')
+					for v48, v49 := range v47 {
+						if v48 > 0 {
+							out.RawByte(',')
+						}
+						if v49 == nil {
+							out.RawString("null")
+						} else {
+							(*v49).MarshalEasyJSON(out)
+						}
+					}
+					out.RawByte(']')
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"wordsLeft\":"
+		out.RawString(prefix)
+		if in.WordsLeft == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v50, v51 := range in.WordsLeft {
+				if v50 > 0 {
+					out.RawByte(',')
+				}
+				out.Int(int(v51))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StateBoard) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol30(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StateBoard) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol30(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StateBoard) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol30(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StateBoard) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol30(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol31(in *jlexer.Lexer, out *StateBackfill) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "pack":
+			out.Pack = string(in.String())
+		case "language":
+			out.Language = string(in.String())
+		case "count":
+			out.Count = int(in.Int())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol31(out *jwriter.Writer, in StateBackfill) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"pack\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Pack))
+	}
+	if in.Language != "" {
+		const prefix string = ",\"language\":"
+		out.RawString(prefix)
+		out.String(string(in.Language))
+	}
+	{
+		const prefix string = ",\"count\":"
+		out.RawString(prefix)
+		out.Int(int(in.Count))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StateBackfill) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol31(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StateBackfill) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol31(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StateBackfill) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol31(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StateBackfill) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol31(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol32(in *jlexer.Lexer, out *State) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "playerID":
+			out.PlayerID = string(in.String())
+		case "roomState":
+			if in.IsNull() {
+				in.Skip()
+				out.RoomState = nil
+			} else {
+				if out.RoomState == nil {
+					out.RoomState = new(RoomState)
+				}
+				(*out.RoomState).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol32(out *jwriter.Writer, in State) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"playerID\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.PlayerID))
+	}
+	{
+		const prefix string = ",\"roomState\":"
+		out.RawString(prefix)
+		if in.RoomState == nil {
+			out.RawString("null")
+		} else {
+			(*in.RoomState).MarshalEasyJSON(out)
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v State) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol32(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v State) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol32(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *State) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol32(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *State) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol32(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol33(in *jlexer.Lexer, out *SetWebhookParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "url":
+			out.URL = string(in.String())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol33(out *jwriter.Writer, in SetWebhookParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"url\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.URL))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SetWebhookParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol33(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SetWebhookParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol33(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SetWebhookParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol33(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SetWebhookParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol33(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol34(in *jlexer.Lexer, out *ServerNote) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "method":
+			out.Method = ServerMethod(in.String())
+		case "params":
+			if m, ok := out.Params.(easyjson.Unmarshaler); ok {
+				m.UnmarshalEasyJSON(in)
+			} else if m, ok := out.Params.(json.Unmarshaler); ok {
+				_ = m.UnmarshalJSON(in.Raw())
+			} else {
+				out.Params = in.Interface()
+			}
+		case "sent":
+			if in.IsNull() {
+				in.Skip()
+				out.Sent = nil
+			} else {
+				if out.Sent == nil {
+					out.Sent = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.Sent).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol34(out *jwriter.Writer, in ServerNote) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"method\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Method))
+	}
+	{
+		const prefix string = ",\"params\":"
+		out.RawString(prefix)
+		if m, ok := in.Params.(easyjson.Marshaler); ok {
+			m.MarshalEasyJSON(out)
+		} else if m, ok := in.Params.(json.Marshaler); ok {
+			out.Raw(m.MarshalJSON())
+		} else {
+			out.Raw(json.Marshal(in.Params))
+		}
+	}
+	if in.Sent != nil {
+		const prefix string = ",\"sent\":"
+		out.RawString(prefix)
+		out.Raw((*in.Sent).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ServerNote) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol34(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ServerNote) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol34(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ServerNote) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol34(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ServerNote) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol34(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol35(in *jlexer.Lexer, out *ScoreParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "team":
+			out.Team = game.Team(in.Int())
+		case "delta":
+			out.Delta = int(in.Int())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol35(out *jwriter.Writer, in ScoreParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"team\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Team))
+	}
+	{
+		const prefix string = ",\"delta\":"
+		out.RawString(prefix)
+		out.Int(int(in.Delta))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ScoreParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol35(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ScoreParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol35(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ScoreParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol35(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ScoreParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol35(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol36(in *jlexer.Lexer, out *RulesetPack) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "name":
+			out.Name = string(in.String())
+		case "custom":
+			out.Custom = bool(in.Bool())
+		case "hash":
+			out.Hash = string(in.String())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol36(out *jwriter.Writer, in RulesetPack) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"custom\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Custom))
+	}
+	{
+		const prefix string = ",\"hash\":"
+		out.RawString(prefix)
+		out.String(string(in.Hash))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RulesetPack) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol36(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RulesetPack) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol36(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RulesetPack) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol36(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RulesetPack) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol36(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol37(in *jlexer.Lexer, out *RulesetOption) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "name":
+			out.Name = string(in.String())
+		case "value":
+			out.Value = string(in.String())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol37(out *jwriter.Writer, in RulesetOption) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"value\":"
+		out.RawString(prefix)
+		out.String(string(in.Value))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RulesetOption) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol37(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RulesetOption) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol37(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RulesetOption) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol37(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RulesetOption) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol37(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol38(in *jlexer.Lexer, out *Ruleset) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "version":
+			out.Version = int(in.Int())
+		case "mode":
+			out.Mode = string(in.String())
+		case "options":
+			if in.IsNull() {
+				in.Skip()
+				out.Options = nil
+			} else {
+				in.Delim('[')
+				if out.Options == nil {
+					if !in.IsDelim(']') {
+						out.Options = make([]*RulesetOption, 0, 8)
+					} else {
+						out.Options = []*RulesetOption{}
+					}
+				} else {
+					out.Options = (out.Options)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v52 *RulesetOption
+					if in.IsNull() {
+						in.Skip()
+						v52 = nil
+					} else {
+						if v52 == nil {
+							v52 = new(RulesetOption)
+						}
+						(*v52).UnmarshalEasyJSON(in)
+					}
+					out.Options = append(out.Options, v52)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "packs":
+			if in.IsNull() {
+				in.Skip()
+				out.Packs = nil
+			} else {
+				in.Delim('[')
+				if out.Packs == nil {
+					if !in.IsDelim(']') {
+						out.Packs = make([]*RulesetPack, 0, 8)
+					} else {
+						out.Packs = []*RulesetPack{}
+					}
+				} else {
+					out.Packs = (out.Packs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v53 *RulesetPack
+					if in.IsNull() {
+						in.Skip()
+						v53 = nil
+					} else {
+						if v53 == nil {
+							v53 = new(RulesetPack)
+						}
+						(*v53).UnmarshalEasyJSON(in)
+					}
+					out.Packs = append(out.Packs, v53)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol38(out *jwriter.Writer, in Ruleset) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"version\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Version))
+	}
+	{
+		const prefix string = ",\"mode\":"
+		out.RawString(prefix)
+		out.String(string(in.Mode))
+	}
+	{
+		const prefix string = ",\"options\":"
+		out.RawString(prefix)
+		if in.Options == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v54, v55 := range in.Options {
+				if v54 > 0 {
+					out.RawByte(',')
+				}
+				if v55 == nil {
+					out.RawString("null")
+				} else {
+					(*v55).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"packs\":"
+		out.RawString(prefix)
+		if in.Packs == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v56, v57 := range in.Packs {
+				if v56 > 0 {
+					out.RawByte(',')
+				}
+				if v57 == nil {
+					out.RawString("null")
+				} else {
+					(*v57).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Ruleset) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol38(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Ruleset) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol38(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Ruleset) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol38(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Ruleset) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol38(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol39(in *jlexer.Lexer, out *RoomState) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "version":
+			out.Version = int64(in.Int64())
+		case "teams":
+			if in.IsNull() {
+				in.Skip()
+				out.Teams = nil
+			} else {
+				in.Delim('[')
+				if out.Teams == nil {
+					if !in.IsDelim(']') {
+						out.Teams = make([][]*StatePlayer, 0, 2)
+					} else {
+						out.Teams = [][]*StatePlayer{}
+					}
+				} else {
+					out.Teams = (out.Teams)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v58 []*StatePlayer
+					if in.IsNull() {
+						in.Skip()
+						v58 = nil
+					} else {
+						in.Delim('[')
+						if v58 == nil {
+							if !in.IsDelim(']') {
+								v58 = make([]*StatePlayer, 0, 8)
+							} else {
+								v58 = []*StatePlayer{}
+							}
+						} else {
+							v58 = (v58)[:0]
+						}
+						for !in.IsDelim(']') {
+							var v59 *StatePlayer
+							if in.IsNull() {
+								in.Skip()
+								v59 = nil
+							} else {
+								if v59 == nil {
+									v59 = new(StatePlayer)
+								}
+								(*v59).UnmarshalEasyJSON(in)
+							}
+							v58 = append(v58, v59)
+							in.WantComma()
+						}
+						in.Delim(']')
+					}
+					out.Teams = append(out.Teams, v58)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "turn":
+			out.Turn = game.Team(in.Int())
+		case "winner":
+			if in.IsNull() {
+				in.Skip()
+				out.Winner = nil
+			} else {
+				if out.Winner == nil {
+					out.Winner = new(game.Team)
+				}
+				*out.Winner = game.Team(in.Int())
+			}
+		case "wins":
+			if in.IsNull() {
+				in.Skip()
+				out.Wins = nil
+			} else {
+				in.Delim('[')
+				if out.Wins == nil {
+					if !in.IsDelim(']') {
+						out.Wins = make([]int, 0, 8)
+					} else {
+						out.Wins = []int{}
+					}
+				} else {
+					out.Wins = (out.Wins)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v60 int
+					v60 = int(in.Int())
+					out.Wins = append(out.Wins, v60)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "gameOver":
+			if in.IsNull() {
+				in.Skip()
+				out.GameOver = nil
+			} else {
+				if out.GameOver == nil {
+					out.GameOver = new(StateGameOver)
+				}
+				(*out.GameOver).UnmarshalEasyJSON(in)
+			}
+		case "board":
+			if in.IsNull() {
+				in.Skip()
+				out.Board = nil
+			} else {
+				in.Delim('[')
+				if out.Board == nil {
+					if !in.IsDelim(']') {
+						out.Board = make([][]*StateTile, 0, 2)
+					} else {
+						out.Board = [][]*StateTile{}
+					}
+				} else {
+					out.Board = (out.Board)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v61 []*StateTile
+					if in.IsNull() {
+						in.Skip()
+						v61 = nil
+					} else {
+						in.Delim('[')
+						if v61 == nil {
+							if !in.IsDelim(']') {
+								v61 = make([]*StateTile, 0, 8)
+							} else {
+								v61 = []*StateTile{}
+							}
+						} else {
+							v61 = (v61)[:0]
+						}
+						for !in.IsDelim(']') {
+							var v62 *StateTile
+							if in.IsNull() {
+								in.Skip()
+								v62 = nil
+							} else {
+								if v62 == nil {
+									v62 = new(StateTile)
+								}
+								(*v62).UnmarshalEasyJSON(in)
+							}
+							v61 = append(v61, v62)
+							in.WantComma()
+						}
+						in.Delim(']')
+					}
+					out.Board = append(out.Board, v61)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "wordsLeft":
+			if in.IsNull() {
+				in.Skip()
+				out.WordsLeft = nil
+			} else {
+				in.Delim('[')
+				if out.WordsLeft == nil {
+					if !in.IsDelim(']') {
+						out.WordsLeft = make([]int, 0, 8)
+					} else {
+						out.WordsLeft = []int{}
+					}
+				} else {
+					out.WordsLeft = (out.WordsLeft)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v63 int
+					v63 = int(in.Int())
+					out.WordsLeft = append(out.WordsLeft, v63)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "boards":
+			if in.IsNull() {
+				in.Skip()
+				out.Boards = nil
+			} else {
+				in.Delim('[')
+				if out.Boards == nil {
+					if !in.IsDelim(']') {
+						out.Boards = make([]*StateBoard, 0, 8)
+					} else {
+						out.Boards = []*StateBoard{}
+					}
+				} else {
+					out.Boards = (out.Boards)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v64 *StateBoard
+					if in.IsNull() {
+						in.Skip()
+						v64 = nil
+					} else {
+						if v64 == nil {
+							v64 = new(StateBoard)
+						}
+						(*v64).UnmarshalEasyJSON(in)
+					}
+					out.Boards = append(out.Boards, v64)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "turnBoard":
+			if in.IsNull() {
+				in.Skip()
+				out.TurnBoard = nil
+			} else {
+				if out.TurnBoard == nil {
+					out.TurnBoard = new(int)
+				}
+				*out.TurnBoard = int(in.Int())
+			}
+		case "boardOptions":
+			if in.IsNull() {
+				in.Skip()
+				out.BoardOptions = nil
+			} else {
+				if out.BoardOptions == nil {
+					out.BoardOptions = new(StateBoardOptions)
+				}
+				(*out.BoardOptions).UnmarshalEasyJSON(in)
+			}
+		case "lists":
+			if in.IsNull() {
+				in.Skip()
+				out.Lists = nil
+			} else {
+				in.Delim('[')
+				if out.Lists == nil {
+					if !in.IsDelim(']') {
+						out.Lists = make([]*StateWordList, 0, 8)
+					} else {
+						out.Lists = []*StateWordList{}
+					}
+				} else {
+					out.Lists = (out.Lists)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v65 *StateWordList
+					if in.IsNull() {
+						in.Skip()
+						v65 = nil
+					} else {
+						if v65 == nil {
+							v65 = new(StateWordList)
+						}
+						(*v65).UnmarshalEasyJSON(in)
+					}
+					out.Lists = append(out.Lists, v65)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "timer":
+			if in.IsNull() {
+				in.Skip()
+				out.Timer = nil
+			} else {
+				if out.Timer == nil {
+					out.Timer = new(StateTimer)
+				}
+				(*out.Timer).UnmarshalEasyJSON(in)
+			}
+		case "hideBomb":
+			out.HideBomb = bool(in.Bool())
+		case "mirrorDelay":
+			out.MirrorDelay = int(in.Int())
+		case "spectators":
+			out.Spectators = int(in.Int())
+		case "clue":
+			if in.IsNull() {
+				in.Skip()
+				out.Clue = nil
+			} else {
+				if out.Clue == nil {
+					out.Clue = new(StateClue)
+				}
+				(*out.Clue).UnmarshalEasyJSON(in)
+			}
+		case "clueLog":
+			if in.IsNull() {
+				in.Skip()
+				out.ClueLog = nil
+			} else {
+				in.Delim('[')
+				if out.ClueLog == nil {
+					if !in.IsDelim(']') {
+						out.ClueLog = make([]*StateLoggedClue, 0, 8)
+					} else {
+						out.ClueLog = []*StateLoggedClue{}
+					}
+				} else {
+					out.ClueLog = (out.ClueLog)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v66 *StateLoggedClue
+					if in.IsNull() {
+						in.Skip()
+						v66 = nil
+					} else {
+						if v66 == nil {
+							v66 = new(StateLoggedClue)
+						}
+						(*v66).UnmarshalEasyJSON(in)
+					}
+					out.ClueLog = append(out.ClueLog, v66)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "boundClues":
+			out.BoundClues = bool(in.Bool())
+		case "suggestClues":
+			out.SuggestClues = bool(in.Bool())
+		case "feedback":
+			out.Feedback = bool(in.Bool())
+		case "openPolls":
+			out.OpenPolls = bool(in.Bool())
+		case "penalties":
+			if in.IsNull() {
+				in.Skip()
+				out.Penalties = nil
+			} else {
+				if out.Penalties == nil {
+					out.Penalties = new(StatePenalties)
+				}
+				(*out.Penalties).UnmarshalEasyJSON(in)
+			}
+		case "hints":
+			if in.IsNull() {
+				in.Skip()
+				out.Hints = nil
+			} else {
+				if out.Hints == nil {
+					out.Hints = new(StateHints)
+				}
+				(*out.Hints).UnmarshalEasyJSON(in)
+			}
+		case "spymasters":
+			if in.IsNull() {
+				in.Skip()
+				out.Spymasters = nil
+			} else {
+				if out.Spymasters == nil {
+					out.Spymasters = new(StateSpymasters)
+				}
+				(*out.Spymasters).UnmarshalEasyJSON(in)
+			}
+		case "start":
+			if in.IsNull() {
+				in.Skip()
+				out.Start = nil
+			} else {
+				if out.Start == nil {
+					out.Start = new(StateStart)
+				}
+				(*out.Start).UnmarshalEasyJSON(in)
+			}
+		case "enforceGuessLimit":
+			out.EnforceGuessLimit = bool(in.Bool())
+		case "guessesLeft":
+			if in.IsNull() {
+				in.Skip()
+				out.GuessesLeft = nil
+			} else {
+				if out.GuessesLeft == nil {
+					out.GuessesLeft = new(int)
+				}
+				*out.GuessesLeft = int(in.Int())
+			}
+		case "canUndo":
+			out.CanUndo = bool(in.Bool())
+		case "backfillWords":
+			out.BackfillWords = bool(in.Bool())
+		case "backfill":
+			if in.IsNull() {
+				in.Skip()
+				out.Backfill = nil
+			} else {
+				if out.Backfill == nil {
+					out.Backfill = new(StateBackfill)
+				}
+				(*out.Backfill).UnmarshalEasyJSON(in)
+			}
+		case "webhook":
+			out.Webhook = bool(in.Bool())
+		case "teamConstraints":
+			if in.IsNull() {
+				in.Skip()
+				out.TeamConstraints = nil
+			} else {
+				in.Delim('[')
+				if out.TeamConstraints == nil {
+					if !in.IsDelim(']') {
+						out.TeamConstraints = make([]*TeamConstraint, 0, 8)
+					} else {
+						out.TeamConstraints = []*TeamConstraint{}
+					}
+				} else {
+					out.TeamConstraints = (out.TeamConstraints)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v67 *TeamConstraint
+					if in.IsNull() {
+						in.Skip()
+						v67 = nil
+					} else {
+						if v67 == nil {
+							v67 = new(TeamConstraint)
+						}
+						(*v67).UnmarshalEasyJSON(in)
+					}
+					out.TeamConstraints = append(out.TeamConstraints, v67)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "keyChecksum":
+			out.KeyChecksum = string(in.String())
+		case "ruleset":
+			out.Ruleset = string(in.String())
+		case "boardHash":
+			out.BoardHash = string(in.String())
+		case "tracker":
+			if in.IsNull() {
+				in.Skip()
+				out.Tracker = nil
+			} else {
+				if out.Tracker == nil {
+					out.Tracker = new(StateTracker)
+				}
+				(*out.Tracker).UnmarshalEasyJSON(in)
+			}
+		case "notifications":
+			if in.IsNull() {
+				in.Skip()
+				out.Notifications = nil
+			} else {
+				if out.Notifications == nil {
+					out.Notifications = new(StateNotifications)
+				}
+				(*out.Notifications).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol39(out *jwriter.Writer, in RoomState) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"version\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.Version))
+	}
+	{
+		const prefix string = ",\"teams\":"
+		out.RawString(prefix)
+		if in.Teams == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v68, v69 := range in.Teams {
+				if v68 > 0 {
+					out.RawByte(',')
+				}
+				if v69 == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+					out.RawString("null")
+				} else {
+					out.RawByte('[')
+					for v70, v71 := range v69 {
+						if v70 > 0 {
+							out.RawByte(',')
+						}
+						if v71 == nil {
+							out.RawString("null")
+						} else {
+							(*v71).MarshalEasyJSON(out)
+						}
+					}
+					out.RawByte(']')
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"turn\":"
+		out.RawString(prefix)
+		out.Int(int(in.Turn))
+	}
+	{
+		const prefix string = ",\"winner\":"
+		out.RawString(prefix)
+		if in.Winner == nil {
+			out.RawString("null")
+		} else {
+			out.Int(int(*in.Winner))
+		}
+	}
+	{
+		const prefix string = ",\"wins\":"
+		out.RawString(prefix)
+		if in.Wins == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v72, v73 := range in.Wins {
+				if v72 > 0 {
+					out.RawByte(',')
+				}
+				out.Int(int(v73))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.GameOver != nil {
+		const prefix string = ",\"gameOver\":"
+		out.RawString(prefix)
+		(*in.GameOver).MarshalEasyJSON(out)
+	}
+	if len(in.Board) != 0 {
+		const prefix string = ",\"board\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v74, v75 := range in.Board {
+				if v74 > 0 {
+					out.RawByte(',')
+				}
+				if v75 == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+					out.RawString("null")
+				} else {
+					out.RawByte('[')
+					for v76, v77 := range v75 {
+						if v76 > 0 {
+							out.RawByte(',')
+						}
+						if v77 == nil {
+							out.RawString("null")
+						} else {
+							(*v77).MarshalEasyJSON(out)
+						}
+					}
+					out.RawByte(']')
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.WordsLeft) != 0 {
+		const prefix string = ",\"wordsLeft\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v78, v79 := range in.WordsLeft {
+				if v78 > 0 {
+					out.RawByte(',')
+				}
+				out.Int(int(v79))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Boards) != 0 {
+		const prefix string = ",\"boards\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v80, v81 := range in.Boards {
+				if v80 > 0 {
+					out.RawByte(',')
+				}
+				if v81 == nil {
+					out.RawString("null")
+				} else {
+					(*v81).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.TurnBoard != nil {
+		const prefix string = ",\"turnBoard\":"
+		out.RawString(prefix)
+		out.Int(int(*in.TurnBoard))
+	}
+	if in.BoardOptions != nil {
+		const prefix string = ",\"boardOptions\":"
+		out.RawString(prefix)
+		(*in.BoardOptions).MarshalEasyJSON(out)
+	}
+	if len(in.Lists) != 0 {
+		const prefix string = ",\"lists\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v82, v83 := range in.Lists {
+				if v82 > 0 {
+					out.RawByte(',')
+				}
+				if v83 == nil {
+					out.RawString("null")
+				} else {
+					(*v83).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"timer\":"
+		out.RawString(prefix)
+		if in.Timer == nil {
+			out.RawString("null")
+		} else {
+			(*in.Timer).MarshalEasyJSON(out)
+		}
+	}
+	{
+		const prefix string = ",\"hideBomb\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.HideBomb))
+	}
+	{
+		const prefix string = ",\"mirrorDelay\":"
+		out.RawString(prefix)
+		out.Int(int(in.MirrorDelay))
+	}
+	{
+		const prefix string = ",\"spectators\":"
+		out.RawString(prefix)
+		out.Int(int(in.Spectators))
+	}
+	{
+		const prefix string = ",\"clue\":"
+		out.RawString(prefix)
+		if in.Clue == nil {
+			out.RawString("null")
+		} else {
+			(*in.Clue).MarshalEasyJSON(out)
+		}
+	}
+	if len(in.ClueLog) != 0 {
+		const prefix string = ",\"clueLog\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v84, v85 := range in.ClueLog {
+				if v84 > 0 {
+					out.RawByte(',')
+				}
+				if v85 == nil {
+					out.RawString("null")
+				} else {
+					(*v85).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"boundClues\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.BoundClues))
+	}
+	{
+		const prefix string = ",\"suggestClues\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.SuggestClues))
+	}
+	{
+		const prefix string = ",\"feedback\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Feedback))
+	}
+	{
+		const prefix string = ",\"openPolls\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.OpenPolls))
+	}
+	{
+		const prefix string = ",\"penalties\":"
+		out.RawString(prefix)
+		if in.Penalties == nil {
+			out.RawString("null")
+		} else {
+			(*in.Penalties).MarshalEasyJSON(out)
+		}
+	}
+	{
+		const prefix string = ",\"hints\":"
+		out.RawString(prefix)
+		if in.Hints == nil {
+			out.RawString("null")
+		} else {
+			(*in.Hints).MarshalEasyJSON(out)
+		}
+	}
+	{
+		const prefix string = ",\"spymasters\":"
+		out.RawString(prefix)
+		if in.Spymasters == nil {
+			out.RawString("null")
+		} else {
+			(*in.Spymasters).MarshalEasyJSON(out)
+		}
+	}
+	{
+		const prefix string = ",\"start\":"
+		out.RawString(prefix)
+		if in.Start == nil {
+			out.RawString("null")
+		} else {
+			(*in.Start).MarshalEasyJSON(out)
+		}
+	}
+	{
+		const prefix string = ",\"enforceGuessLimit\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.EnforceGuessLimit))
+	}
+	if in.GuessesLeft != nil {
+		const prefix string = ",\"guessesLeft\":"
+		out.RawString(prefix)
+		out.Int(int(*in.GuessesLeft))
+	}
+	{
+		const prefix string = ",\"canUndo\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.CanUndo))
+	}
+	{
+		const prefix string = ",\"backfillWords\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.BackfillWords))
+	}
+	if in.Backfill != nil {
+		const prefix string = ",\"backfill\":"
+		out.RawString(prefix)
+		(*in.Backfill).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"webhook\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Webhook))
+	}
+	if len(in.TeamConstraints) != 0 {
+		const prefix string = ",\"teamConstraints\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v86, v87 := range in.TeamConstraints {
+				if v86 > 0 {
+					out.RawByte(',')
+				}
+				if v87 == nil {
+					out.RawString("null")
+				} else {
+					(*v87).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.KeyChecksum != "" {
+		const prefix string = ",\"keyChecksum\":"
+		out.RawString(prefix)
+		out.String(string(in.KeyChecksum))
+	}
+	if in.Ruleset != "" {
+		const prefix string = ",\"ruleset\":"
+		out.RawString(prefix)
+		out.String(string(in.Ruleset))
+	}
+	if in.BoardHash != "" {
+		const prefix string = ",\"boardHash\":"
+		out.RawString(prefix)
+		out.String(string(in.BoardHash))
+	}
+	if in.Tracker != nil {
+		const prefix string = ",\"tracker\":"
+		out.RawString(prefix)
+		(*in.Tracker).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"notifications\":"
+		out.RawString(prefix)
+		if in.Notifications == nil {
+			out.RawString("null")
+		} else {
+			(*in.Notifications).MarshalEasyJSON(out)
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RoomState) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol39(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RoomState) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol39(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RoomState) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol39(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RoomState) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol39(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol40(in *jlexer.Lexer, out *RoomResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			if in.IsNull() {
+				in.Skip()
+				out.ID = nil
+			} else {
+				if out.ID == nil {
+					out.ID = new(string)
+				}
+				*out.ID = string(in.String())
+			}
+		case "error":
+			if in.IsNull() {
+				in.Skip()
+				out.Error = nil
+			} else {
+				if out.Error == nil {
+					out.Error = new(string)
+				}
+				*out.Error = string(in.String())
+			}
+		case "errors":
+			if in.IsNull() {
+				in.Skip()
+				out.Errors = nil
+			} else {
+				in.Delim('[')
+				if out.Errors == nil {
+					if !in.IsDelim(']') {
+						out.Errors = make([]*FieldError, 0, 8)
+					} else {
+						out.Errors = []*FieldError{}
+					}
+				} else {
+					out.Errors = (out.Errors)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v88 *FieldError
+					if in.IsNull() {
+						in.Skip()
+						v88 = nil
+					} else {
+						if v88 == nil {
+							v88 = new(FieldError)
+						}
+						(*v88).UnmarshalEasyJSON(in)
+					}
+					out.Errors = append(out.Errors, v88)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol40(out *jwriter.Writer, in RoomResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.ID != nil {
+		const prefix string = ",\"id\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(*in.ID))
+	}
+	if in.Error != nil {
+		const prefix string = ",\"error\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(*in.Error))
+	}
+	if len(in.Errors) != 0 {
+		const prefix string = ",\"errors\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v89, v90 := range in.Errors {
+				if v89 > 0 {
+					out.RawByte(',')
+				}
+				if v90 == nil {
+					out.RawString("null")
+				} else {
+					(*v90).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RoomResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol40(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RoomResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol40(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RoomResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol40(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RoomResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol40(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol41(in *jlexer.Lexer, out *RoomRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "roomName":
+			out.RoomName = string(in.String())
+		case "roomPass":
+			out.RoomPass = string(in.String())
+		case "create":
+			out.Create = bool(in.Bool())
+		case "tracker":
+			out.Tracker = bool(in.Bool())
+		case "unlisted":
+			out.Unlisted = bool(in.Bool())
+		case "words":
+			if in.IsNull() {
+				in.Skip()
+				out.Words = nil
+			} else {
+				in.Delim('[')
+				if out.Words == nil {
+					if !in.IsDelim(']') {
+						out.Words = make([]string, 0, 4)
+					} else {
+						out.Words = []string{}
+					}
+				} else {
+					out.Words = (out.Words)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v91 string
+					v91 = string(in.String())
+					out.Words = append(out.Words, v91)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "language":
+			out.Language = string(in.String())
+		case "rows":
+			out.Rows = int(in.Int())
+		case "cols":
+			out.Cols = int(in.Int())
+		case "wait":
+			out.Wait = bool(in.Bool())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol41(out *jwriter.Writer, in RoomRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"roomName\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.RoomName))
+	}
+	{
+		const prefix string = ",\"roomPass\":"
+		out.RawString(prefix)
+		out.String(string(in.RoomPass))
+	}
+	{
+		const prefix string = ",\"create\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Create))
+	}
+	if in.Tracker {
+		const prefix string = ",\"tracker\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Tracker))
+	}
+	if in.Unlisted {
+		const prefix string = ",\"unlisted\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Unlisted))
+	}
+	if len(in.Words) != 0 {
+		const prefix string = ",\"words\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v92, v93 := range in.Words {
+				if v92 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v93))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Language != "" {
+		const prefix string = ",\"language\":"
+		out.RawString(prefix)
+		out.String(string(in.Language))
+	}
+	if in.Rows != 0 {
+		const prefix string = ",\"rows\":"
+		out.RawString(prefix)
+		out.Int(int(in.Rows))
+	}
+	if in.Cols != 0 {
+		const prefix string = ",\"cols\":"
+		out.RawString(prefix)
+		out.Int(int(in.Cols))
+	}
+	{
+		const prefix string = ",\"wait\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Wait))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RoomRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol41(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RoomRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol41(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RoomRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol41(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RoomRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol41(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol42(in *jlexer.Lexer, out *RevokeMirrorTokenParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "token":
+			out.Token = string(in.String())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol42(out *jwriter.Writer, in RevokeMirrorTokenParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"token\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Token))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RevokeMirrorTokenParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol42(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RevokeMirrorTokenParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol42(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RevokeMirrorTokenParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol42(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RevokeMirrorTokenParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol42(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol43(in *jlexer.Lexer, out *RevealParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "board":
+			out.Board = int(in.Int())
+		case "row":
+			out.Row = int(in.Int())
+		case "col":
+			out.Col = int(in.Int())
+		case "force":
+			out.Force = bool(in.Bool())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol43(out *jwriter.Writer, in RevealParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"board\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Board))
+	}
+	{
+		const prefix string = ",\"row\":"
+		out.RawString(prefix)
+		out.Int(int(in.Row))
+	}
+	{
+		const prefix string = ",\"col\":"
+		out.RawString(prefix)
+		out.Int(int(in.Col))
+	}
+	if in.Force {
+		const prefix string = ",\"force\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Force))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RevealParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol43(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RevealParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol43(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RevealParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol43(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RevealParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol43(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol44(in *jlexer.Lexer, out *ResumeToken) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "token":
+			out.Token = string(in.String())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol44(out *jwriter.Writer, in ResumeToken) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"token\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Token))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ResumeToken) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol44(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ResumeToken) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol44(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ResumeToken) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol44(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ResumeToken) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol44(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol45(in *jlexer.Lexer, out *ResetWinsParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol45(out *jwriter.Writer, in ResetWinsParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ResetWinsParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol45(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ResetWinsParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol45(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ResetWinsParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol45(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ResetWinsParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol45(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol46(in *jlexer.Lexer, out *RequestMergeParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "roomID":
+			out.RoomID = string(in.String())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol46(out *jwriter.Writer, in RequestMergeParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"roomID\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.RoomID))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RequestMergeParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol46(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RequestMergeParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol46(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RequestMergeParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol46(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RequestMergeParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol46(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol47(in *jlexer.Lexer, out *ReportDesyncParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "hash":
+			out.Hash = string(in.String())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol47(out *jwriter.Writer, in ReportDesyncParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"hash\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Hash))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ReportDesyncParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol47(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ReportDesyncParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol47(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ReportDesyncParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol47(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ReportDesyncParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol47(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol48(in *jlexer.Lexer, out *RemovePackParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "num":
+			out.Num = int(in.Int())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol48(out *jwriter.Writer, in RemovePackParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"num\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Num))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RemovePackParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol48(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RemovePackParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol48(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RemovePackParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol48(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RemovePackParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol48(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol49(in *jlexer.Lexer, out *RateGameParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "game":
+			out.Game = int(in.Int())
+		case "rating":
+			out.Rating = int(in.Int())
+		case "tag":
+			out.Tag = FeedbackTag(in.String())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol49(out *jwriter.Writer, in RateGameParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"game\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Game))
+	}
+	{
+		const prefix string = ",\"rating\":"
+		out.RawString(prefix)
+		out.Int(int(in.Rating))
+	}
+	if in.Tag != "" {
+		const prefix string = ",\"tag\":"
+		out.RawString(prefix)
+		out.String(string(in.Tag))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RateGameParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol49(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RateGameParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol49(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RateGameParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol49(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RateGameParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol49(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol50(in *jlexer.Lexer, out *RandomizeTeamsParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol50(out *jwriter.Writer, in RandomizeTeamsParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RandomizeTeamsParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol50(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RandomizeTeamsParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol50(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RandomizeTeamsParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol50(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RandomizeTeamsParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol50(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol51(in *jlexer.Lexer, out *Quality) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol51(out *jwriter.Writer, in Quality) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Quality) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol51(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Quality) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol51(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Quality) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol51(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Quality) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol51(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol52(in *jlexer.Lexer, out *Poll) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = int(in.Int())
+		case "question":
+			out.Question = string(in.String())
+		case "options":
+			if in.IsNull() {
+				in.Skip()
+				out.Options = nil
+			} else {
+				in.Delim('[')
+				if out.Options == nil {
+					if !in.IsDelim(']') {
+						out.Options = make([]string, 0, 4)
+					} else {
+						out.Options = []string{}
+					}
+				} else {
+					out.Options = (out.Options)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v94 string
+					v94 = string(in.String())
+					out.Options = append(out.Options, v94)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "hidden":
+			out.Hidden = bool(in.Bool())
+		case "opener":
+			out.Opener = string(in.String())
+		case "closes":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Closes).UnmarshalJSON(data))
+			}
+		case "closed":
+			out.Closed = bool(in.Bool())
+		case "votes":
+			out.Votes = int(in.Int())
+		case "tallies":
+			if in.IsNull() {
+				in.Skip()
+				out.Tallies = nil
+			} else {
+				in.Delim('[')
+				if out.Tallies == nil {
+					if !in.IsDelim(']') {
+						out.Tallies = make([]int, 0, 8)
+					} else {
+						out.Tallies = []int{}
+					}
+				} else {
+					out.Tallies = (out.Tallies)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v95 int
+					v95 = int(in.Int())
+					out.Tallies = append(out.Tallies, v95)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "vote":
+			if in.IsNull() {
+				in.Skip()
+				out.Vote = nil
+			} else {
+				if out.Vote == nil {
+					out.Vote = new(int)
+				}
+				*out.Vote = int(in.Int())
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol52(out *jwriter.Writer, in Poll) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.ID))
+	}
+	{
+		const prefix string = ",\"question\":"
+		out.RawString(prefix)
+		out.String(string(in.Question))
+	}
+	{
+		const prefix string = ",\"options\":"
+		out.RawString(prefix)
+		if in.Options == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v96, v97 := range in.Options {
+				if v96 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v97))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"hidden\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Hidden))
+	}
+	{
+		const prefix string = ",\"opener\":"
+		out.RawString(prefix)
+		out.String(string(in.Opener))
+	}
+	{
+		const prefix string = ",\"closes\":"
+		out.RawString(prefix)
+		out.Raw((in.Closes).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"closed\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Closed))
+	}
+	{
+		const prefix string = ",\"votes\":"
+		out.RawString(prefix)
+		out.Int(int(in.Votes))
+	}
+	if len(in.Tallies) != 0 {
+		const prefix string = ",\"tallies\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v98, v99 := range in.Tallies {
+				if v98 > 0 {
+					out.RawByte(',')
+				}
+				out.Int(int(v99))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"vote\":"
+		out.RawString(prefix)
+		if in.Vote == nil {
+			out.RawString("null")
+		} else {
+			out.Int(int(*in.Vote))
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Poll) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol52(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Poll) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol52(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Poll) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol52(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Poll) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol52(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol53(in *jlexer.Lexer, out *PlayerUpdated) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "since":
+			out.Since = int64(in.Int64())
+		case "version":
+			out.Version = int64(in.Int64())
+		case "boardHash":
+			out.BoardHash = string(in.String())
+		case "team":
+			out.Team = game.Team(in.Int())
+		case "player":
+			if in.IsNull() {
+				in.Skip()
+				out.Player = nil
+			} else {
+				if out.Player == nil {
+					out.Player = new(StatePlayer)
+				}
+				(*out.Player).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol53(out *jwriter.Writer, in PlayerUpdated) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"since\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.Since))
+	}
+	{
+		const prefix string = ",\"version\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Version))
+	}
+	if in.BoardHash != "" {
+		const prefix string = ",\"boardHash\":"
+		out.RawString(prefix)
+		out.String(string(in.BoardHash))
+	}
+	{
+		const prefix string = ",\"team\":"
+		out.RawString(prefix)
+		out.Int(int(in.Team))
+	}
+	{
+		const prefix string = ",\"player\":"
+		out.RawString(prefix)
+		if in.Player == nil {
+			out.RawString("null")
+		} else {
+			(*in.Player).MarshalEasyJSON(out)
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PlayerUpdated) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol53(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PlayerUpdated) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol53(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PlayerUpdated) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol53(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PlayerUpdated) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol53(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol54(in *jlexer.Lexer, out *PlayerLeft) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "since":
+			out.Since = int64(in.Int64())
+		case "version":
+			out.Version = int64(in.Int64())
+		case "boardHash":
+			out.BoardHash = string(in.String())
+		case "playerID":
+			out.PlayerID = string(in.String())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol54(out *jwriter.Writer, in PlayerLeft) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"since\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.Since))
+	}
+	{
+		const prefix string = ",\"version\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Version))
+	}
+	if in.BoardHash != "" {
+		const prefix string = ",\"boardHash\":"
+		out.RawString(prefix)
+		out.String(string(in.BoardHash))
+	}
+	{
+		const prefix string = ",\"playerID\":"
+		out.RawString(prefix)
+		out.String(string(in.PlayerID))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PlayerLeft) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol54(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PlayerLeft) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol54(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PlayerLeft) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol54(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PlayerLeft) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol54(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol55(in *jlexer.Lexer, out *PlayerJoined) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "since":
+			out.Since = int64(in.Int64())
+		case "version":
+			out.Version = int64(in.Int64())
+		case "boardHash":
+			out.BoardHash = string(in.String())
+		case "team":
+			out.Team = game.Team(in.Int())
+		case "player":
+			if in.IsNull() {
+				in.Skip()
+				out.Player = nil
+			} else {
+				if out.Player == nil {
+					out.Player = new(StatePlayer)
+				}
+				(*out.Player).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol55(out *jwriter.Writer, in PlayerJoined) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"since\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.Since))
+	}
+	{
+		const prefix string = ",\"version\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Version))
+	}
+	if in.BoardHash != "" {
+		const prefix string = ",\"boardHash\":"
+		out.RawString(prefix)
+		out.String(string(in.BoardHash))
+	}
+	{
+		const prefix string = ",\"team\":"
+		out.RawString(prefix)
+		out.Int(int(in.Team))
+	}
+	{
+		const prefix string = ",\"player\":"
+		out.RawString(prefix)
+		if in.Player == nil {
+			out.RawString("null")
+		} else {
+			(*in.Player).MarshalEasyJSON(out)
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PlayerJoined) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol55(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PlayerJoined) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol55(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PlayerJoined) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol55(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PlayerJoined) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol55(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol56(in *jlexer.Lexer, out *OptionsChanged) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "fields":
+			if in.IsNull() {
+				in.Skip()
+				out.Fields = nil
+			} else {
+				in.Delim('[')
+				if out.Fields == nil {
+					if !in.IsDelim(']') {
+						out.Fields = make([]string, 0, 4)
+					} else {
+						out.Fields = []string{}
+					}
+				} else {
+					out.Fields = (out.Fields)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v100 string
+					v100 = string(in.String())
+					out.Fields = append(out.Fields, v100)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol56(out *jwriter.Writer, in OptionsChanged) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"fields\":"
+		out.RawString(prefix[1:])
+		if in.Fields == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v101, v102 := range in.Fields {
+				if v101 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v102))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OptionsChanged) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol56(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OptionsChanged) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol56(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OptionsChanged) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol56(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OptionsChanged) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol56(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol57(in *jlexer.Lexer, out *OpenPollParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "question":
+			out.Question = string(in.String())
+		case "options":
+			if in.IsNull() {
+				in.Skip()
+				out.Options = nil
+			} else {
+				in.Delim('[')
+				if out.Options == nil {
+					if !in.IsDelim(']') {
+						out.Options = make([]string, 0, 4)
+					} else {
+						out.Options = []string{}
+					}
+				} else {
+					out.Options = (out.Options)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v103 string
+					v103 = string(in.String())
+					out.Options = append(out.Options, v103)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "hidden":
+			out.Hidden = bool(in.Bool())
+		case "seconds":
+			out.Seconds = int(in.Int())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol57(out *jwriter.Writer, in OpenPollParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"question\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Question))
+	}
+	{
+		const prefix string = ",\"options\":"
+		out.RawString(prefix)
+		if in.Options == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v104, v105 := range in.Options {
+				if v104 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v105))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"hidden\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Hidden))
+	}
+	{
+		const prefix string = ",\"seconds\":"
+		out.RawString(prefix)
+		out.Int(int(in.Seconds))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OpenPollParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol57(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OpenPollParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol57(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OpenPollParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol57(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OpenPollParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol57(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol58(in *jlexer.Lexer, out *Notification) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "event":
+			out.Event = NotificationEvent(in.String())
+		case "team":
+			out.Team = game.Team(in.Int())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol58(out *jwriter.Writer, in Notification) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"event\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Event))
+	}
+	{
+		const prefix string = ",\"team\":"
+		out.RawString(prefix)
+		out.Int(int(in.Team))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Notification) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol58(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Notification) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol58(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Notification) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol58(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Notification) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol58(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol59(in *jlexer.Lexer, out *NewGameParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "force":
+			out.Force = bool(in.Bool())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol59(out *jwriter.Writer, in NewGameParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Force {
+		const prefix string = ",\"force\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.Force))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v NewGameParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol59(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v NewGameParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol59(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *NewGameParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol59(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *NewGameParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol59(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol60(in *jlexer.Lexer, out *MirrorToken) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "token":
+			out.Token = string(in.String())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol60(out *jwriter.Writer, in MirrorToken) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"token\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Token))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MirrorToken) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol60(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MirrorToken) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol60(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MirrorToken) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol60(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MirrorToken) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol60(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol61(in *jlexer.Lexer, out *MintMirrorTokenParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol61(out *jwriter.Writer, in MintMirrorTokenParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MintMirrorTokenParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol61(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MintMirrorTokenParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol61(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MintMirrorTokenParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol61(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MintMirrorTokenParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol61(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol62(in *jlexer.Lexer, out *Merged) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "roomID":
+			out.RoomID = string(in.String())
+		case "token":
+			out.Token = string(in.String())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol62(out *jwriter.Writer, in Merged) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"roomID\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.RoomID))
+	}
+	{
+		const prefix string = ",\"token\":"
+		out.RawString(prefix)
+		out.String(string(in.Token))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Merged) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol62(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Merged) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol62(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Merged) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol62(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Merged) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol62(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol63(in *jlexer.Lexer, out *MergeRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "roomName":
+			out.RoomName = string(in.String())
+		case "players":
+			out.Players = int(in.Int())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol63(out *jwriter.Writer, in MergeRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"roomName\":"
+		out.RawString(prefix)
+		out.String(string(in.RoomName))
+	}
+	{
+		const prefix string = ",\"players\":"
+		out.RawString(prefix)
+		out.Int(int(in.Players))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MergeRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol63(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MergeRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol63(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MergeRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol63(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MergeRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol63(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol64(in *jlexer.Lexer, out *MatchmakeResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "ticket":
+			out.Ticket = string(in.String())
+		case "status":
+			out.Status = string(in.String())
+		case "waiting":
+			out.Waiting = int(in.Int())
+		case "roomID":
+			if in.IsNull() {
+				in.Skip()
+				out.RoomID = nil
+			} else {
+				if out.RoomID == nil {
+					out.RoomID = new(string)
+				}
+				*out.RoomID = string(in.String())
+			}
+		case "token":
+			if in.IsNull() {
+				in.Skip()
+				out.Token = nil
+			} else {
+				if out.Token == nil {
+					out.Token = new(string)
+				}
+				*out.Token = string(in.String())
+			}
+		case "error":
+			if in.IsNull() {
+				in.Skip()
+				out.Error = nil
+			} else {
+				if out.Error == nil {
+					out.Error = new(string)
+				}
+				*out.Error = string(in.String())
+			}
+		case "errors":
+			if in.IsNull() {
+				in.Skip()
+				out.Errors = nil
+			} else {
+				in.Delim('[')
+				if out.Errors == nil {
+					if !in.IsDelim(']') {
+						out.Errors = make([]*FieldError, 0, 8)
+					} else {
+						out.Errors = []*FieldError{}
+					}
+				} else {
+					out.Errors = (out.Errors)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v106 *FieldError
+					if in.IsNull() {
+						in.Skip()
+						v106 = nil
+					} else {
+						if v106 == nil {
+							v106 = new(FieldError)
+						}
+						(*v106).UnmarshalEasyJSON(in)
+					}
+					out.Errors = append(out.Errors, v106)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol64(out *jwriter.Writer, in MatchmakeResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Ticket != "" {
+		const prefix string = ",\"ticket\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Ticket))
+	}
+	if in.Status != "" {
+		const prefix string = ",\"status\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"waiting\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Waiting))
+	}
+	if in.RoomID != nil {
+		const prefix string = ",\"roomID\":"
+		out.RawString(prefix)
+		out.String(string(*in.RoomID))
+	}
+	if in.Token != nil {
+		const prefix string = ",\"token\":"
+		out.RawString(prefix)
+		out.String(string(*in.Token))
+	}
+	if in.Error != nil {
+		const prefix string = ",\"error\":"
+		out.RawString(prefix)
+		out.String(string(*in.Error))
+	}
+	if len(in.Errors) != 0 {
+		const prefix string = ",\"errors\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v107, v108 := range in.Errors {
+				if v107 > 0 {
+					out.RawByte(',')
+				}
+				if v108 == nil {
+					out.RawString("null")
+				} else {
+					(*v108).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MatchmakeResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol64(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MatchmakeResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol64(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MatchmakeResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol64(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MatchmakeResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol64(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol65(in *jlexer.Lexer, out *MatchmakeRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "nickname":
+			out.Nickname = string(in.String())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol65(out *jwriter.Writer, in MatchmakeRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"nickname\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Nickname))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MatchmakeRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol65(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MatchmakeRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol65(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MatchmakeRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol65(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MatchmakeRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol65(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol66(in *jlexer.Lexer, out *ListedRoom) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "name":
+			out.Name = string(in.String())
+		case "players":
+			out.Players = int(in.Int())
+		case "passwordRequired":
+			out.PasswordRequired = bool(in.Bool())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol66(out *jwriter.Writer, in ListedRoom) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"players\":"
+		out.RawString(prefix)
+		out.Int(int(in.Players))
+	}
+	{
+		const prefix string = ",\"passwordRequired\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.PasswordRequired))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ListedRoom) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol66(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ListedRoom) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol66(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ListedRoom) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol66(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ListedRoom) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol66(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol67(in *jlexer.Lexer, out *KickParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "playerID":
+			out.PlayerID = string(in.String())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol67(out *jwriter.Writer, in KickParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"playerID\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.PlayerID))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v KickParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol67(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v KickParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol67(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *KickParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol67(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *KickParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol67(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol68(in *jlexer.Lexer, out *InfoResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "startTime":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.StartTime).UnmarshalJSON(data))
+			}
+		case "uptimeSeconds":
+			out.UptimeSeconds = int64(in.Int64())
+		case "version":
+			out.Version = string(in.String())
+		case "protocolVersion":
+			out.ProtocolVersion = int(in.Int())
+		case "buildTime":
+			out.BuildTime = string(in.String())
+		case "goVersion":
+			out.GoVersion = string(in.String())
+		case "mode":
+			out.Mode = string(in.String())
+		case "limits":
+			(out.Limits).UnmarshalEasyJSON(in)
+		case "features":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				out.Features = make(map[string]bool)
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v109 bool
+					v109 = bool(in.Bool())
+					(out.Features)[key] = v109
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol68(out *jwriter.Writer, in InfoResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"startTime\":"
+		out.RawString(prefix[1:])
+		out.Raw((in.StartTime).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"uptimeSeconds\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.UptimeSeconds))
+	}
+	{
+		const prefix string = ",\"version\":"
+		out.RawString(prefix)
+		out.String(string(in.Version))
+	}
+	{
+		const prefix string = ",\"protocolVersion\":"
+		out.RawString(prefix)
+		out.Int(int(in.ProtocolVersion))
+	}
+	if in.BuildTime != "" {
+		const prefix string = ",\"buildTime\":"
+		out.RawString(prefix)
+		out.String(string(in.BuildTime))
+	}
+	{
+		const prefix string = ",\"goVersion\":"
+		out.RawString(prefix)
+		out.String(string(in.GoVersion))
+	}
+	{
+		const prefix string = ",\"mode\":"
+		out.RawString(prefix)
+		out.String(string(in.Mode))
+	}
+	{
+		const prefix string = ",\"limits\":"
+		out.RawString(prefix)
+		(in.Limits).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"features\":"
+		out.RawString(prefix)
+		if in.Features == nil && (out.Flags&jwriter.NilMapAsEmpty) == 0 {
+			out.RawString(`null`)
+		} else {
+			out.RawByte('{')
+			v110First := true
+			for v110Name, v110Value := range in.Features {
+				if v110First {
+					v110First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v110Name))
+				out.RawByte(':')
+				out.Bool(bool(v110Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v InfoResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol68(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v InfoResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol68(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *InfoResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol68(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *InfoResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol68(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol69(in *jlexer.Lexer, out *GiveClueParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "board":
+			out.Board = int(in.Int())
+		case "word":
+			out.Word = string(in.String())
+		case "count":
+			out.Count = int(in.Int())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol69(out *jwriter.Writer, in GiveClueParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"board\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Board))
+	}
+	{
+		const prefix string = ",\"word\":"
+		out.RawString(prefix)
+		out.String(string(in.Word))
+	}
+	{
+		const prefix string = ",\"count\":"
+		out.RawString(prefix)
+		out.Int(int(in.Count))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GiveClueParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol69(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GiveClueParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol69(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GiveClueParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol69(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GiveClueParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol69(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol70(in *jlexer.Lexer, out *GetWebhookParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol70(out *jwriter.Writer, in GetWebhookParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GetWebhookParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol70(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GetWebhookParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol70(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GetWebhookParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol70(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GetWebhookParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol70(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol71(in *jlexer.Lexer, out *GetBansParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol71(out *jwriter.Writer, in GetBansParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GetBansParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol71(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GetBansParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol71(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GetBansParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol71(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GetBansParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol71(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol72(in *jlexer.Lexer, out *GetAuditLogParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol72(out *jwriter.Writer, in GetAuditLogParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GetAuditLogParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol72(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GetAuditLogParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol72(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GetAuditLogParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol72(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GetAuditLogParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol72(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol73(in *jlexer.Lexer, out *GameLogReveal) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "time":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Time).UnmarshalJSON(data))
+			}
+		case "playerID":
+			out.PlayerID = string(in.String())
+		case "nickname":
+			out.Nickname = string(in.String())
+		case "board":
+			out.Board = int(in.Int())
+		case "row":
+			out.Row = int(in.Int())
+		case "col":
+			out.Col = int(in.Int())
+		case "word":
+			out.Word = string(in.String())
+		case "view":
+			if in.IsNull() {
+				in.Skip()
+				out.View = nil
+			} else {
+				if out.View == nil {
+					out.View = new(StateView)
+				}
+				(*out.View).UnmarshalEasyJSON(in)
+			}
+		case "hint":
+			out.Hint = bool(in.Bool())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol73(out *jwriter.Writer, in GameLogReveal) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"time\":"
+		out.RawString(prefix[1:])
+		out.Raw((in.Time).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"playerID\":"
+		out.RawString(prefix)
+		out.String(string(in.PlayerID))
+	}
+	{
+		const prefix string = ",\"nickname\":"
+		out.RawString(prefix)
+		out.String(string(in.Nickname))
+	}
+	{
+		const prefix string = ",\"board\":"
+		out.RawString(prefix)
+		out.Int(int(in.Board))
+	}
+	{
+		const prefix string = ",\"row\":"
+		out.RawString(prefix)
+		out.Int(int(in.Row))
+	}
+	{
+		const prefix string = ",\"col\":"
+		out.RawString(prefix)
+		out.Int(int(in.Col))
+	}
+	{
+		const prefix string = ",\"word\":"
+		out.RawString(prefix)
+		out.String(string(in.Word))
+	}
+	{
+		const prefix string = ",\"view\":"
+		out.RawString(prefix)
+		if in.View == nil {
+			out.RawString("null")
+		} else {
+			(*in.View).MarshalEasyJSON(out)
+		}
+	}
+	if in.Hint {
+		const prefix string = ",\"hint\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Hint))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GameLogReveal) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol73(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GameLogReveal) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol73(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GameLogReveal) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol73(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GameLogReveal) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol73(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol74(in *jlexer.Lexer, out *GameLogPlayer) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "playerID":
+			out.PlayerID = string(in.String())
+		case "nickname":
+			out.Nickname = string(in.String())
+		case "team":
+			out.Team = game.Team(in.Int())
+		case "spymaster":
+			out.Spymaster = bool(in.Bool())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol74(out *jwriter.Writer, in GameLogPlayer) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"playerID\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.PlayerID))
+	}
+	{
+		const prefix string = ",\"nickname\":"
+		out.RawString(prefix)
+		out.String(string(in.Nickname))
+	}
+	{
+		const prefix string = ",\"team\":"
+		out.RawString(prefix)
+		out.Int(int(in.Team))
+	}
+	{
+		const prefix string = ",\"spymaster\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Spymaster))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GameLogPlayer) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol74(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GameLogPlayer) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol74(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GameLogPlayer) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol74(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GameLogPlayer) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol74(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol75(in *jlexer.Lexer, out *GameLog) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "started":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Started).UnmarshalJSON(data))
+			}
+		case "ended":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Ended).UnmarshalJSON(data))
+			}
+		case "ruleset":
+			out.Ruleset = string(in.String())
+		case "players":
+			if in.IsNull() {
+				in.Skip()
+				out.Players = nil
+			} else {
+				in.Delim('[')
+				if out.Players == nil {
+					if !in.IsDelim(']') {
+						out.Players = make([]*GameLogPlayer, 0, 8)
+					} else {
+						out.Players = []*GameLogPlayer{}
+					}
+				} else {
+					out.Players = (out.Players)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v111 *GameLogPlayer
+					if in.IsNull() {
+						in.Skip()
+						v111 = nil
+					} else {
+						if v111 == nil {
+							v111 = new(GameLogPlayer)
+						}
+						(*v111).UnmarshalEasyJSON(in)
+					}
+					out.Players = append(out.Players, v111)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "words":
+			if in.IsNull() {
+				in.Skip()
+				out.Words = nil
+			} else {
+				in.Delim('[')
+				if out.Words == nil {
+					if !in.IsDelim(']') {
+						out.Words = make([][][]string, 0, 2)
+					} else {
+						out.Words = [][][]string{}
+					}
+				} else {
+					out.Words = (out.Words)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v112 [][]string
+					if in.IsNull() {
+						in.Skip()
+						v112 = nil
+					} else {
+						in.Delim('[')
+						if v112 == nil {
+							if !in.IsDelim(']') {
+								v112 = make([][]string, 0, 2)
+							} else {
+								v112 = [][]string{}
+							}
+						} else {
+							v112 = (v112)[:0]
+						}
+						for !in.IsDelim(']') {
+							var v113 []string
+							if in.IsNull() {
+								in.Skip()
+								v113 = nil
+							} else {
+								in.Delim('[')
+								if v113 == nil {
+									if !in.IsDelim(']') {
+										v113 = make([]string, 0, 4)
+									} else {
+										v113 = []string{}
+									}
+								} else {
+									v113 = (v113)[:0]
+								}
+								for !in.IsDelim(']') {
+									var v114 string
+									v114 = string(in.String())
+									v113 = append(v113, v114)
+									in.WantComma()
+								}
+								in.Delim(']')
+							}
+							v112 = append(v112, v113)
+							in.WantComma()
+						}
+						in.Delim(']')
+					}
+					out.Words = append(out.Words, v112)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "reveals":
+			if in.IsNull() {
+				in.Skip()
+				out.Reveals = nil
+			} else {
+				in.Delim('[')
+				if out.Reveals == nil {
+					if !in.IsDelim(']') {
+						out.Reveals = make([]*GameLogReveal, 0, 8)
+					} else {
+						out.Reveals = []*GameLogReveal{}
+					}
+				} else {
+					out.Reveals = (out.Reveals)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v115 *GameLogReveal
+					if in.IsNull() {
+						in.Skip()
+						v115 = nil
+					} else {
+						if v115 == nil {
+							v115 = new(GameLogReveal)
+						}
+						(*v115).UnmarshalEasyJSON(in)
+					}
+					out.Reveals = append(out.Reveals, v115)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "clues":
+			if in.IsNull() {
+				in.Skip()
+				out.Clues = nil
+			} else {
+				in.Delim('[')
+				if out.Clues == nil {
+					if !in.IsDelim(']') {
+						out.Clues = make([]*StateLoggedClue, 0, 8)
+					} else {
+						out.Clues = []*StateLoggedClue{}
+					}
+				} else {
+					out.Clues = (out.Clues)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v116 *StateLoggedClue
+					if in.IsNull() {
+						in.Skip()
+						v116 = nil
+					} else {
+						if v116 == nil {
+							v116 = new(StateLoggedClue)
+						}
+						(*v116).UnmarshalEasyJSON(in)
+					}
+					out.Clues = append(out.Clues, v116)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "result":
+			if in.IsNull() {
+				in.Skip()
+				out.Result = nil
+			} else {
+				if out.Result == nil {
+					out.Result = new(StateGameOver)
+				}
+				(*out.Result).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol75(out *jwriter.Writer, in GameLog) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"started\":"
+		out.RawString(prefix[1:])
+		out.Raw((in.Started).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"ended\":"
+		out.RawString(prefix)
+		out.Raw((in.Ended).MarshalJSON())
+	}
+	if in.Ruleset != "" {
+		const prefix string = ",\"ruleset\":"
+		out.RawString(prefix)
+		out.String(string(in.Ruleset))
+	}
+	{
+		const prefix string = ",\"players\":"
+		out.RawString(prefix)
+		if in.Players == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v117, v118 := range in.Players {
+				if v117 > 0 {
+					out.RawByte(',')
+				}
+				if v118 == nil {
+					out.RawString("null")
+				} else {
+					(*v118).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Words) != 0 {
+		const prefix string = ",\"words\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v119, v120 := range in.Words {
+				if v119 > 0 {
+					out.RawByte(',')
+				}
+				if v120 == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+					out.RawString("null")
+				} else {
+					out.RawByte('[')
+					for v121, v122 := range v120 {
+						if v121 > 0 {
+							out.RawByte(',')
+						}
+						if v122 == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+							out.RawString("null")
+						} else {
+							out.RawByte('[')
+							for v123, v124 := range v122 {
+								if v123 > 0 {
+									out.RawByte(',')
+								}
+								out.String(string(v124))
+							}
+							out.RawByte(']')
+						}
+					}
+					out.RawByte(']')
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"reveals\":"
+		out.RawString(prefix)
+		if in.Reveals == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v125, v126 := range in.Reveals {
+				if v125 > 0 {
+					out.RawByte(',')
+				}
+				if v126 == nil {
+					out.RawString("null")
+				} else {
+					(*v126).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"clues\":"
+		out.RawString(prefix)
+		if in.Clues == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v127, v128 := range in.Clues {
+				if v127 > 0 {
+					out.RawByte(',')
+				}
+				if v128 == nil {
+					out.RawString("null")
+				} else {
+					(*v128).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"result\":"
+		out.RawString(prefix)
+		if in.Result == nil {
+			out.RawString("null")
+		} else {
+			(*in.Result).MarshalEasyJSON(out)
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GameLog) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol75(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GameLog) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol75(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GameLog) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol75(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GameLog) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol75(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol76(in *jlexer.Lexer, out *FieldError) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "field":
+			out.Field = string(in.String())
+		case "code":
+			out.Code = string(in.String())
+		case "message":
+			out.Message = string(in.String())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol76(out *jwriter.Writer, in FieldError) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"field\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Field))
+	}
+	{
+		const prefix string = ",\"code\":"
+		out.RawString(prefix)
+		out.String(string(in.Code))
+	}
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		out.String(string(in.Message))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v FieldError) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol76(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v FieldError) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol76(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *FieldError) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol76(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *FieldError) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol76(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol77(in *jlexer.Lexer, out *FeedbackPrompt) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "game":
+			out.Game = int(in.Int())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol77(out *jwriter.Writer, in FeedbackPrompt) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"game\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Game))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v FeedbackPrompt) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol77(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v FeedbackPrompt) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol77(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *FeedbackPrompt) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol77(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *FeedbackPrompt) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol77(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol78(in *jlexer.Lexer, out *ErrorShortfall) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "team":
+			out.Team = game.Team(in.Int())
+		case "spymaster":
+			out.Spymaster = bool(in.Bool())
+		case "have":
+			out.Have = int(in.Int())
+		case "need":
+			out.Need = int(in.Int())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol78(out *jwriter.Writer, in ErrorShortfall) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"team\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Team))
+	}
+	{
+		const prefix string = ",\"spymaster\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Spymaster))
+	}
+	{
+		const prefix string = ",\"have\":"
+		out.RawString(prefix)
+		out.Int(int(in.Have))
+	}
+	{
+		const prefix string = ",\"need\":"
+		out.RawString(prefix)
+		out.Int(int(in.Need))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ErrorShortfall) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol78(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ErrorShortfall) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol78(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ErrorShortfall) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol78(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ErrorShortfall) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol78(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol79(in *jlexer.Lexer, out *Error) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "code":
+			out.Code = string(in.String())
+		case "message":
+			out.Message = string(in.String())
+		case "limit":
+			if in.IsNull() {
+				in.Skip()
+				out.Limit = nil
+			} else {
+				if out.Limit == nil {
+					out.Limit = new(int)
+				}
+				*out.Limit = int(in.Int())
+			}
+		case "shortfalls":
+			if in.IsNull() {
+				in.Skip()
+				out.Shortfalls = nil
+			} else {
+				in.Delim('[')
+				if out.Shortfalls == nil {
+					if !in.IsDelim(']') {
+						out.Shortfalls = make([]*ErrorShortfall, 0, 8)
+					} else {
+						out.Shortfalls = []*ErrorShortfall{}
+					}
+				} else {
+					out.Shortfalls = (out.Shortfalls)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v129 *ErrorShortfall
+					if in.IsNull() {
+						in.Skip()
+						v129 = nil
+					} else {
+						if v129 == nil {
+							v129 = new(ErrorShortfall)
+						}
+						(*v129).UnmarshalEasyJSON(in)
+					}
+					out.Shortfalls = append(out.Shortfalls, v129)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "conflicts":
+			if in.IsNull() {
+				in.Skip()
+				out.Conflicts = nil
+			} else {
+				in.Delim('[')
+				if out.Conflicts == nil {
+					if !in.IsDelim(']') {
+						out.Conflicts = make([]*TeamConstraint, 0, 8)
+					} else {
+						out.Conflicts = []*TeamConstraint{}
+					}
+				} else {
+					out.Conflicts = (out.Conflicts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v130 *TeamConstraint
+					if in.IsNull() {
+						in.Skip()
+						v130 = nil
+					} else {
+						if v130 == nil {
+							v130 = new(TeamConstraint)
+						}
+						(*v130).UnmarshalEasyJSON(in)
+					}
+					out.Conflicts = append(out.Conflicts, v130)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "retryAfter":
+			if in.IsNull() {
+				in.Skip()
+				out.RetryAfter = nil
+			} else {
+				if out.RetryAfter == nil {
+					out.RetryAfter = new(int)
+				}
+				*out.RetryAfter = int(in.Int())
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol79(out *jwriter.Writer, in Error) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"code\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Code))
+	}
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		out.String(string(in.Message))
+	}
+	if in.Limit != nil {
+		const prefix string = ",\"limit\":"
+		out.RawString(prefix)
+		out.Int(int(*in.Limit))
+	}
+	if len(in.Shortfalls) != 0 {
+		const prefix string = ",\"shortfalls\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v131, v132 := range in.Shortfalls {
+				if v131 > 0 {
+					out.RawByte(',')
+				}
+				if v132 == nil {
+					out.RawString("null")
+				} else {
+					(*v132).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Conflicts) != 0 {
+		const prefix string = ",\"conflicts\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v133, v134 := range in.Conflicts {
+				if v133 > 0 {
+					out.RawByte(',')
+				}
+				if v134 == nil {
+					out.RawString("null")
+				} else {
+					(*v134).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.RetryAfter != nil {
+		const prefix string = ",\"retryAfter\":"
+		out.RawString(prefix)
+		out.Int(int(*in.RetryAfter))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Error) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol79(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Error) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol79(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Error) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol79(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Error) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol79(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol80(in *jlexer.Lexer, out *EndTurnParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol80(out *jwriter.Writer, in EndTurnParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v EndTurnParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol80(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v EndTurnParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol80(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *EndTurnParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol80(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *EndTurnParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol80(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol81(in *jlexer.Lexer, out *DeclareWinnerParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "team":
+			out.Team = game.Team(in.Int())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol81(out *jwriter.Writer, in DeclareWinnerParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"team\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Team))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v DeclareWinnerParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol81(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v DeclareWinnerParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol81(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *DeclareWinnerParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol81(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *DeclareWinnerParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol81(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol82(in *jlexer.Lexer, out *DebugInfoParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol82(out *jwriter.Writer, in DebugInfoParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v DebugInfoParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol82(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v DebugInfoParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol82(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *DebugInfoParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol82(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *DebugInfoParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol82(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol83(in *jlexer.Lexer, out *DebugInfo) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "playerID":
+			out.PlayerID = string(in.String())
+		case "bytesSent":
+			out.BytesSent = int64(in.Int64())
+		case "bytesReceived":
+			out.BytesReceived = int64(in.Int64())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol83(out *jwriter.Writer, in DebugInfo) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"playerID\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.PlayerID))
+	}
+	{
+		const prefix string = ",\"bytesSent\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.BytesSent))
+	}
+	{
+		const prefix string = ",\"bytesReceived\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.BytesReceived))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v DebugInfo) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol83(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v DebugInfo) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol83(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *DebugInfo) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol83(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *DebugInfo) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol83(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol84(in *jlexer.Lexer, out *ConfigLimits) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "maxRooms":
+			out.MaxRooms = int(in.Int())
+		case "maxPlayers":
+			out.MaxPlayers = int(in.Int())
+		case "maxConnections":
+			out.MaxConnections = int(in.Int())
+		case "maxConnsPerIP":
+			out.MaxConnsPerIP = int(in.Int())
+		case "maxCustomPacks":
+			out.MaxCustomPacks = int(in.Int())
+		case "maxCustomPackBytes":
+			out.MaxCustomPackBytes = int(in.Int())
+		case "minRevealPlayers":
+			out.MinRevealPlayers = int(in.Int())
+		case "matchSize":
+			out.MatchSize = int(in.Int())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol84(out *jwriter.Writer, in ConfigLimits) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"maxRooms\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.MaxRooms))
+	}
+	{
+		const prefix string = ",\"maxPlayers\":"
+		out.RawString(prefix)
+		out.Int(int(in.MaxPlayers))
+	}
+	{
+		const prefix string = ",\"maxConnections\":"
+		out.RawString(prefix)
+		out.Int(int(in.MaxConnections))
+	}
+	{
+		const prefix string = ",\"maxConnsPerIP\":"
+		out.RawString(prefix)
+		out.Int(int(in.MaxConnsPerIP))
+	}
+	{
+		const prefix string = ",\"maxCustomPacks\":"
+		out.RawString(prefix)
+		out.Int(int(in.MaxCustomPacks))
+	}
+	{
+		const prefix string = ",\"maxCustomPackBytes\":"
+		out.RawString(prefix)
+		out.Int(int(in.MaxCustomPackBytes))
+	}
+	{
+		const prefix string = ",\"minRevealPlayers\":"
+		out.RawString(prefix)
+		out.Int(int(in.MinRevealPlayers))
+	}
+	{
+		const prefix string = ",\"matchSize\":"
+		out.RawString(prefix)
+		out.Int(int(in.MatchSize))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ConfigLimits) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol84(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ConfigLimits) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol84(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ConfigLimits) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol84(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ConfigLimits) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol84(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol85(in *jlexer.Lexer, out *Config) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "version":
+			out.Version = string(in.String())
+		case "protocolVersion":
+			out.ProtocolVersion = int(in.Int())
+		case "buildTime":
+			out.BuildTime = string(in.String())
+		case "goVersion":
+			out.GoVersion = string(in.String())
+		case "mode":
+			out.Mode = string(in.String())
+		case "limits":
+			(out.Limits).UnmarshalEasyJSON(in)
+		case "features":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				out.Features = make(map[string]bool)
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v135 bool
+					v135 = bool(in.Bool())
+					(out.Features)[key] = v135
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol85(out *jwriter.Writer, in Config) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"version\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Version))
+	}
+	{
+		const prefix string = ",\"protocolVersion\":"
+		out.RawString(prefix)
+		out.Int(int(in.ProtocolVersion))
+	}
+	if in.BuildTime != "" {
+		const prefix string = ",\"buildTime\":"
+		out.RawString(prefix)
+		out.String(string(in.BuildTime))
+	}
+	{
+		const prefix string = ",\"goVersion\":"
+		out.RawString(prefix)
+		out.String(string(in.GoVersion))
+	}
+	{
+		const prefix string = ",\"mode\":"
+		out.RawString(prefix)
+		out.String(string(in.Mode))
+	}
+	{
+		const prefix string = ",\"limits\":"
+		out.RawString(prefix)
+		(in.Limits).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"features\":"
+		out.RawString(prefix)
+		if in.Features == nil && (out.Flags&jwriter.NilMapAsEmpty) == 0 {
+			out.RawString(`null`)
+		} else {
+			out.RawByte('{')
+			v136First := true
+			for v136Name, v136Value := range in.Features {
+				if v136First {
+					v136First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v136Name))
+				out.RawByte(':')
+				out.Bool(bool(v136Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Config) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol85(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Config) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol85(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Config) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol85(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Config) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol85(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol86(in *jlexer.Lexer, out *ClueSuggestions) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "suggestions":
+			if in.IsNull() {
+				in.Skip()
+				out.Suggestions = nil
+			} else {
+				in.Delim('[')
+				if out.Suggestions == nil {
+					if !in.IsDelim(']') {
+						out.Suggestions = make([]*ClueSuggestion, 0, 8)
+					} else {
+						out.Suggestions = []*ClueSuggestion{}
+					}
+				} else {
+					out.Suggestions = (out.Suggestions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v137 *ClueSuggestion
+					if in.IsNull() {
+						in.Skip()
+						v137 = nil
+					} else {
+						if v137 == nil {
+							v137 = new(ClueSuggestion)
+						}
+						easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol87(in, v137)
+					}
+					out.Suggestions = append(out.Suggestions, v137)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol86(out *jwriter.Writer, in ClueSuggestions) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"suggestions\":"
+		out.RawString(prefix[1:])
+		if in.Suggestions == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v138, v139 := range in.Suggestions {
+				if v138 > 0 {
+					out.RawByte(',')
+				}
+				if v139 == nil {
+					out.RawString("null")
+				} else {
+					easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol87(out, *v139)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ClueSuggestions) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol86(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ClueSuggestions) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol86(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ClueSuggestions) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol86(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ClueSuggestions) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol86(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol87(in *jlexer.Lexer, out *ClueSuggestion) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "board":
+			out.Board = int(in.Int())
+		case "word":
+			out.Word = string(in.String())
+		case "count":
+			out.Count = int(in.Int())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol87(out *jwriter.Writer, in ClueSuggestion) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Board != 0 {
+		const prefix string = ",\"board\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.Int(int(in.Board))
+	}
+	{
+		const prefix string = ",\"word\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Word))
+	}
+	{
+		const prefix string = ",\"count\":"
+		out.RawString(prefix)
+		out.Int(int(in.Count))
+	}
+	out.RawByte('}')
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol88(in *jlexer.Lexer, out *ClosedResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "reason":
+			out.Reason = string(in.String())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol88(out *jwriter.Writer, in ClosedResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"reason\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Reason))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ClosedResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol88(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ClosedResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol88(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ClosedResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol88(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ClosedResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol88(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol89(in *jlexer.Lexer, out *ClosePollParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "poll":
+			out.Poll = int(in.Int())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol89(out *jwriter.Writer, in ClosePollParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"poll\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Poll))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ClosePollParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol89(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ClosePollParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol89(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ClosePollParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol89(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ClosePollParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol89(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol90(in *jlexer.Lexer, out *CloseHint) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "retry":
+			out.Retry = bool(in.Bool())
+		case "afterMs":
+			out.AfterMS = int(in.Int())
+		case "roomId":
+			out.RoomID = string(in.String())
+		case "reason":
+			out.Reason = string(in.String())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol90(out *jwriter.Writer, in CloseHint) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"retry\":"
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.Retry))
+	}
+	if in.AfterMS != 0 {
+		const prefix string = ",\"afterMs\":"
+		out.RawString(prefix)
+		out.Int(int(in.AfterMS))
+	}
+	if in.RoomID != "" {
+		const prefix string = ",\"roomId\":"
+		out.RawString(prefix)
+		out.String(string(in.RoomID))
+	}
+	if in.Reason != "" {
+		const prefix string = ",\"reason\":"
+		out.RawString(prefix)
+		out.String(string(in.Reason))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v CloseHint) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol90(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v CloseHint) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol90(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *CloseHint) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol90(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *CloseHint) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol90(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol91(in *jlexer.Lexer, out *ClientNote) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "method":
+			out.Method = ClientMethod(in.StringIntern())
+		case "version":
+			out.Version = int64(in.Int64())
+		case "params":
+			(out.Params).UnmarshalEasyJSON(in)
+		case "id":
+			out.ID = string(in.String())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol91(out *jwriter.Writer, in ClientNote) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"method\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Method))
+	}
+	{
+		const prefix string = ",\"version\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Version))
+	}
+	{
+		const prefix string = ",\"params\":"
+		out.RawString(prefix)
+		(in.Params).MarshalEasyJSON(out)
+	}
+	if in.ID != "" {
+		const prefix string = ",\"id\":"
+		out.RawString(prefix)
+		out.String(string(in.ID))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ClientNote) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol91(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ClientNote) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol91(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ClientNote) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol91(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ClientNote) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol91(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol92(in *jlexer.Lexer, out *ChatParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "text":
+			out.Text = string(in.String())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol92(out *jwriter.Writer, in ChatParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"text\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Text))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ChatParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol92(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ChatParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol92(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ChatParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol92(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ChatParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol92(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol93(in *jlexer.Lexer, out *Chat) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "messages":
+			if in.IsNull() {
+				in.Skip()
+				out.Messages = nil
+			} else {
+				in.Delim('[')
+				if out.Messages == nil {
+					if !in.IsDelim(']') {
+						out.Messages = make([]*ChatMessage, 0, 8)
+					} else {
+						out.Messages = []*ChatMessage{}
+					}
+				} else {
+					out.Messages = (out.Messages)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v140 *ChatMessage
+					if in.IsNull() {
+						in.Skip()
+						v140 = nil
+					} else {
+						if v140 == nil {
+							v140 = new(ChatMessage)
+						}
+						easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol94(in, v140)
+					}
+					out.Messages = append(out.Messages, v140)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol93(out *jwriter.Writer, in Chat) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"messages\":"
+		out.RawString(prefix[1:])
+		if in.Messages == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v141, v142 := range in.Messages {
+				if v141 > 0 {
+					out.RawByte(',')
+				}
+				if v142 == nil {
+					out.RawString("null")
+				} else {
+					easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol94(out, *v142)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Chat) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol93(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Chat) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol93(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Chat) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol93(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Chat) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol93(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol94(in *jlexer.Lexer, out *ChatMessage) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "playerID":
+			out.PlayerID = string(in.String())
+		case "nickname":
+			out.Nickname = string(in.String())
+		case "text":
+			out.Text = string(in.String())
+		case "time":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Time).UnmarshalJSON(data))
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol94(out *jwriter.Writer, in ChatMessage) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"playerID\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.PlayerID))
+	}
+	{
+		const prefix string = ",\"nickname\":"
+		out.RawString(prefix)
+		out.String(string(in.Nickname))
+	}
+	{
+		const prefix string = ",\"text\":"
+		out.RawString(prefix)
+		out.String(string(in.Text))
+	}
+	{
+		const prefix string = ",\"time\":"
+		out.RawString(prefix)
+		out.Raw((in.Time).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol95(in *jlexer.Lexer, out *ChangeTurnTimeParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "seconds":
+			out.Seconds = int(in.Int())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol95(out *jwriter.Writer, in ChangeTurnTimeParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"seconds\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Seconds))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ChangeTurnTimeParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol95(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ChangeTurnTimeParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol95(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ChangeTurnTimeParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol95(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ChangeTurnTimeParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol95(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol96(in *jlexer.Lexer, out *ChangeTurnModeParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "timed":
+			out.Timed = bool(in.Bool())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol96(out *jwriter.Writer, in ChangeTurnModeParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"timed\":"
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.Timed))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ChangeTurnModeParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol96(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ChangeTurnModeParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol96(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ChangeTurnModeParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol96(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ChangeTurnModeParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol96(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol97(in *jlexer.Lexer, out *ChangeTeamParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "team":
+			out.Team = game.Team(in.Int())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol97(out *jwriter.Writer, in ChangeTeamParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"team\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Team))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ChangeTeamParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol97(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ChangeTeamParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol97(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ChangeTeamParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol97(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ChangeTeamParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol97(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol98(in *jlexer.Lexer, out *ChangeTeamConstraintsParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "constraints":
+			if in.IsNull() {
+				in.Skip()
+				out.Constraints = nil
+			} else {
+				in.Delim('[')
+				if out.Constraints == nil {
+					if !in.IsDelim(']') {
+						out.Constraints = make([]*TeamConstraint, 0, 8)
+					} else {
+						out.Constraints = []*TeamConstraint{}
+					}
+				} else {
+					out.Constraints = (out.Constraints)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v143 *TeamConstraint
+					if in.IsNull() {
+						in.Skip()
+						v143 = nil
+					} else {
+						if v143 == nil {
+							v143 = new(TeamConstraint)
+						}
+						(*v143).UnmarshalEasyJSON(in)
+					}
+					out.Constraints = append(out.Constraints, v143)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol98(out *jwriter.Writer, in ChangeTeamConstraintsParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"constraints\":"
+		out.RawString(prefix[1:])
+		if in.Constraints == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v144, v145 := range in.Constraints {
+				if v144 > 0 {
+					out.RawByte(',')
+				}
+				if v145 == nil {
+					out.RawString("null")
+				} else {
+					(*v145).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ChangeTeamConstraintsParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol98(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ChangeTeamConstraintsParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol98(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ChangeTeamConstraintsParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol98(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ChangeTeamConstraintsParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol98(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol99(in *jlexer.Lexer, out *ChangeSuggestCluesParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "suggestClues":
+			out.SuggestClues = bool(in.Bool())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol99(out *jwriter.Writer, in ChangeSuggestCluesParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"suggestClues\":"
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.SuggestClues))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ChangeSuggestCluesParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol99(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ChangeSuggestCluesParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol99(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ChangeSuggestCluesParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol99(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ChangeSuggestCluesParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol99(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol100(in *jlexer.Lexer, out *ChangeSpymastersParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "limit":
+			out.Limit = int(in.Int())
+		case "confirmClues":
+			out.ConfirmClues = bool(in.Bool())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol100(out *jwriter.Writer, in ChangeSpymastersParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"limit\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"confirmClues\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.ConfirmClues))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ChangeSpymastersParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol100(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ChangeSpymastersParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol100(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ChangeSpymastersParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol100(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ChangeSpymastersParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol100(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol101(in *jlexer.Lexer, out *ChangeRoleParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "spymaster":
+			out.Spymaster = bool(in.Bool())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol101(out *jwriter.Writer, in ChangeRoleParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"spymaster\":"
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.Spymaster))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ChangeRoleParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol101(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ChangeRoleParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol101(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ChangeRoleParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol101(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ChangeRoleParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol101(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol102(in *jlexer.Lexer, out *ChangePenaltiesParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "limit":
+			out.Limit = int(in.Int())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol102(out *jwriter.Writer, in ChangePenaltiesParams) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"version\":"
+		const prefix string = ",\"limit\":"
 		out.RawString(prefix[1:])
-		out.Int(int(in.Version))
-	}
-	{
-		const prefix string = ",\"teams\":"
-		out.RawString(prefix)
-		if in.Teams == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
-			out.RawString("null")
-		} else {
-			out.RawByte('[')
-			for v7, v8 := range in.Teams {
-				if v7 > 0 {
-					out.RawByte(',')
-				}
-				if v8 == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
-					out.RawString("null")
-				} else {
-					out.RawByte('[')
-					for v9, v10 := range v8 {
-						if v9 > 0 {
-							out.RawByte(',')
-						}
-						if v10 == nil {
-							out.RawString("null")
-						} else {
-							(*v10).MarshalEasyJSON(out)
-						}
-					}
-					out.RawByte(']')
-				}
-			}
-			out.RawByte(']')
-		}
-	}
-	{
-		const prefix string = ",\"turn\":"
-		out.RawString(prefix)
-		out.Int(int(in.Turn))
-	}
-	{
-		const prefix string = ",\"winner\":"
-		out.RawString(prefix)
-		if in.Winner == nil {
-			out.RawString("null")
-		} else {
-			out.Int(int(*in.Winner))
-		}
-	}
-	{
-		const prefix string = ",\"board\":"
-		out.RawString(prefix)
-		if in.Board == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
-			out.RawString("null")
-		} else {
-			out.RawByte('[')
-			for v11, v12 := range in.Board {
-				if v11 > 0 {
-					out.RawByte(',')
-				}
-				if v12 == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
-					out.RawString("null")
-				} else {
-					out.RawByte('[')
-					for v13, v14 := range v12 {
-						if v13 > 0 {
-							out.RawByte(',')
-						}
-						if v14 == nil {
-							out.RawString("null")
-						} else {
-							(*v14).MarshalEasyJSON(out)
-						}
-					}
-					out.RawByte(']')
-				}
-			}
-			out.RawByte(']')
-		}
-	}
-	{
-		const prefix string = ",\"wordsLeft\":"
-		out.RawString(prefix)
-		if in.WordsLeft == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
-			out.RawString("null")
-		} else {
-			out.RawByte('[')
-			for v15, v16 := range in.WordsLeft {
-				if v15 > 0 {
-					out.RawByte(',')
-				}
-				out.Int(int(v16))
-			}
-			out.RawByte(']')
-		}
-	}
-	{
-		const prefix string = ",\"lists\":"
-		out.RawString(prefix)
-		if in.Lists == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
-			out.RawString("null")
-		} else {
-			out.RawByte('[')
-			for v17, v18 := range in.Lists {
-				if v17 > 0 {
-					out.RawByte(',')
-				}
-				if v18 == nil {
-					out.RawString("null")
-				} else {
-					(*v18).MarshalEasyJSON(out)
-				}
-			}
-			out.RawByte(']')
-		}
-	}
-	{
-		const prefix string = ",\"timer\":"
-		out.RawString(prefix)
-		if in.Timer == nil {
-			out.RawString("null")
-		} else {
-			(*in.Timer).MarshalEasyJSON(out)
-		}
-	}
-	{
-		const prefix string = ",\"hideBomb\":"
-		out.RawString(prefix)
-		out.Bool(bool(in.HideBomb))
+		out.Int(int(in.Limit))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v RoomState) MarshalJSON() ([]byte, error) {
+func (v ChangePenaltiesParams) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol9(&w, v)
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol102(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v RoomState) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol9(w, v)
+func (v ChangePenaltiesParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol102(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *RoomState) UnmarshalJSON(data []byte) error {
+func (v *ChangePenaltiesParams) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol9(&r, v)
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol102(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *RoomState) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol9(l, v)
+func (v *ChangePenaltiesParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol102(l, v)
 }
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol10(in *jlexer.Lexer, out *RoomResponse) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol103(in *jlexer.Lexer, out *ChangePackParams) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1176,26 +11200,10 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol10(in *jlexer.
 			continue
 		}
 		switch key {
-		case "id":
-			if in.IsNull() {
-				in.Skip()
-				out.ID = nil
-			} else {
-				if out.ID == nil {
-					out.ID = new(string)
-				}
-				*out.ID = string(in.String())
-			}
-		case "error":
-			if in.IsNull() {
-				in.Skip()
-				out.Error = nil
-			} else {
-				if out.Error == nil {
-					out.Error = new(string)
-				}
-				*out.Error = string(in.String())
-			}
+		case "num":
+			out.Num = int(in.Int())
+		case "enable":
+			out.Enable = bool(in.Bool())
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -1210,53 +11218,47 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol10(in *jlexer.
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol10(out *jwriter.Writer, in RoomResponse) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol103(out *jwriter.Writer, in ChangePackParams) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.ID != nil {
-		const prefix string = ",\"id\":"
-		first = false
+	{
+		const prefix string = ",\"num\":"
 		out.RawString(prefix[1:])
-		out.String(string(*in.ID))
+		out.Int(int(in.Num))
 	}
-	if in.Error != nil {
-		const prefix string = ",\"error\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(*in.Error))
+	{
+		const prefix string = ",\"enable\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Enable))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v RoomResponse) MarshalJSON() ([]byte, error) {
+func (v ChangePackParams) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol10(&w, v)
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol103(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v RoomResponse) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol10(w, v)
+func (v ChangePackParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol103(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *RoomResponse) UnmarshalJSON(data []byte) error {
+func (v *ChangePackParams) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol10(&r, v)
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol103(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *RoomResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol10(l, v)
+func (v *ChangePackParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol103(l, v)
 }
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol11(in *jlexer.Lexer, out *RoomRequest) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol104(in *jlexer.Lexer, out *ChangeNotificationsParams) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1275,12 +11277,31 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol11(in *jlexer.
 			continue
 		}
 		switch key {
-		case "roomName":
-			out.RoomName = string(in.String())
-		case "roomPass":
-			out.RoomPass = string(in.String())
-		case "create":
-			out.Create = bool(in.Bool())
+		case "off":
+			out.Off = bool(in.Bool())
+		case "disabled":
+			if in.IsNull() {
+				in.Skip()
+				out.Disabled = nil
+			} else {
+				in.Delim('[')
+				if out.Disabled == nil {
+					if !in.IsDelim(']') {
+						out.Disabled = make([]NotificationEvent, 0, 4)
+					} else {
+						out.Disabled = []NotificationEvent{}
+					}
+				} else {
+					out.Disabled = (out.Disabled)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v146 NotificationEvent
+					v146 = NotificationEvent(in.String())
+					out.Disabled = append(out.Disabled, v146)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -1295,52 +11316,58 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol11(in *jlexer.
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol11(out *jwriter.Writer, in RoomRequest) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol104(out *jwriter.Writer, in ChangeNotificationsParams) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"roomName\":"
+		const prefix string = ",\"off\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.RoomName))
-	}
-	{
-		const prefix string = ",\"roomPass\":"
-		out.RawString(prefix)
-		out.String(string(in.RoomPass))
+		out.Bool(bool(in.Off))
 	}
 	{
-		const prefix string = ",\"create\":"
+		const prefix string = ",\"disabled\":"
 		out.RawString(prefix)
-		out.Bool(bool(in.Create))
+		if in.Disabled == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v147, v148 := range in.Disabled {
+				if v147 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v148))
+			}
+			out.RawByte(']')
+		}
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v RoomRequest) MarshalJSON() ([]byte, error) {
+func (v ChangeNotificationsParams) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol11(&w, v)
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol104(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v RoomRequest) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol11(w, v)
+func (v ChangeNotificationsParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol104(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *RoomRequest) UnmarshalJSON(data []byte) error {
+func (v *ChangeNotificationsParams) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol11(&r, v)
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol104(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *RoomRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol11(l, v)
+func (v *ChangeNotificationsParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol104(l, v)
 }
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol12(in *jlexer.Lexer, out *RevealParams) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol105(in *jlexer.Lexer, out *ChangeNicknameParams) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1359,10 +11386,8 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol12(in *jlexer.
 			continue
 		}
 		switch key {
-		case "row":
-			out.Row = int(in.Int())
-		case "col":
-			out.Col = int(in.Int())
+		case "nickname":
+			out.Nickname = string(in.String())
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -1377,47 +11402,42 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol12(in *jlexer.
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol12(out *jwriter.Writer, in RevealParams) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol105(out *jwriter.Writer, in ChangeNicknameParams) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"row\":"
+		const prefix string = ",\"nickname\":"
 		out.RawString(prefix[1:])
-		out.Int(int(in.Row))
-	}
-	{
-		const prefix string = ",\"col\":"
-		out.RawString(prefix)
-		out.Int(int(in.Col))
+		out.String(string(in.Nickname))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v RevealParams) MarshalJSON() ([]byte, error) {
+func (v ChangeNicknameParams) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol12(&w, v)
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol105(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v RevealParams) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol12(w, v)
+func (v ChangeNicknameParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol105(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *RevealParams) UnmarshalJSON(data []byte) error {
+func (v *ChangeNicknameParams) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol12(&r, v)
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol105(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *RevealParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol12(l, v)
+func (v *ChangeNicknameParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol105(l, v)
 }
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol13(in *jlexer.Lexer, out *RemovePackParams) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol106(in *jlexer.Lexer, out *ChangeMirrorDelayParams) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1436,8 +11456,8 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol13(in *jlexer.
 			continue
 		}
 		switch key {
-		case "num":
-			out.Num = int(in.Int())
+		case "seconds":
+			out.Seconds = int(in.Int())
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -1452,42 +11472,42 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol13(in *jlexer.
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol13(out *jwriter.Writer, in RemovePackParams) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol106(out *jwriter.Writer, in ChangeMirrorDelayParams) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"num\":"
+		const prefix string = ",\"seconds\":"
 		out.RawString(prefix[1:])
-		out.Int(int(in.Num))
+		out.Int(int(in.Seconds))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v RemovePackParams) MarshalJSON() ([]byte, error) {
+func (v ChangeMirrorDelayParams) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol13(&w, v)
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol106(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v RemovePackParams) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol13(w, v)
+func (v ChangeMirrorDelayParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol106(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *RemovePackParams) UnmarshalJSON(data []byte) error {
+func (v *ChangeMirrorDelayParams) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol13(&r, v)
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol106(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *RemovePackParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol13(l, v)
+func (v *ChangeMirrorDelayParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol106(l, v)
 }
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol14(in *jlexer.Lexer, out *RandomizeTeamsParams) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol107(in *jlexer.Lexer, out *ChangeHideBombParams) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1506,6 +11526,8 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol14(in *jlexer.
 			continue
 		}
 		switch key {
+		case "hideBomb":
+			out.HideBomb = bool(in.Bool())
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -1520,37 +11542,42 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol14(in *jlexer.
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol14(out *jwriter.Writer, in RandomizeTeamsParams) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol107(out *jwriter.Writer, in ChangeHideBombParams) {
 	out.RawByte('{')
 	first := true
 	_ = first
+	{
+		const prefix string = ",\"hideBomb\":"
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.HideBomb))
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v RandomizeTeamsParams) MarshalJSON() ([]byte, error) {
+func (v ChangeHideBombParams) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol14(&w, v)
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol107(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v RandomizeTeamsParams) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol14(w, v)
+func (v ChangeHideBombParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol107(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *RandomizeTeamsParams) UnmarshalJSON(data []byte) error {
+func (v *ChangeHideBombParams) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol14(&r, v)
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol107(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *RandomizeTeamsParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol14(l, v)
+func (v *ChangeHideBombParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol107(l, v)
 }
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol15(in *jlexer.Lexer, out *NewGameParams) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol108(in *jlexer.Lexer, out *ChangeBoundCluesParams) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1569,6 +11596,8 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol15(in *jlexer.
 			continue
 		}
 		switch key {
+		case "boundClues":
+			out.BoundClues = bool(in.Bool())
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -1583,37 +11612,42 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol15(in *jlexer.
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol15(out *jwriter.Writer, in NewGameParams) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol108(out *jwriter.Writer, in ChangeBoundCluesParams) {
 	out.RawByte('{')
 	first := true
 	_ = first
+	{
+		const prefix string = ",\"boundClues\":"
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.BoundClues))
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v NewGameParams) MarshalJSON() ([]byte, error) {
+func (v ChangeBoundCluesParams) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol15(&w, v)
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol108(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v NewGameParams) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol15(w, v)
+func (v ChangeBoundCluesParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol108(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *NewGameParams) UnmarshalJSON(data []byte) error {
+func (v *ChangeBoundCluesParams) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol15(&r, v)
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol108(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *NewGameParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol15(l, v)
+func (v *ChangeBoundCluesParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol108(l, v)
 }
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol16(in *jlexer.Lexer, out *EndTurnParams) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol109(in *jlexer.Lexer, out *BuiltinPack) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1632,6 +11666,12 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol16(in *jlexer.
 			continue
 		}
 		switch key {
+		case "name":
+			out.Name = string(in.String())
+		case "language":
+			out.Language = string(in.String())
+		case "count":
+			out.Count = int(in.Int())
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -1646,37 +11686,52 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol16(in *jlexer.
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol16(out *jwriter.Writer, in EndTurnParams) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol109(out *jwriter.Writer, in BuiltinPack) {
 	out.RawByte('{')
 	first := true
 	_ = first
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	if in.Language != "" {
+		const prefix string = ",\"language\":"
+		out.RawString(prefix)
+		out.String(string(in.Language))
+	}
+	{
+		const prefix string = ",\"count\":"
+		out.RawString(prefix)
+		out.Int(int(in.Count))
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v EndTurnParams) MarshalJSON() ([]byte, error) {
+func (v BuiltinPack) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol16(&w, v)
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol109(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v EndTurnParams) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol16(w, v)
+func (v BuiltinPack) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol109(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *EndTurnParams) UnmarshalJSON(data []byte) error {
+func (v *BuiltinPack) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol16(&r, v)
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol109(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *EndTurnParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol16(l, v)
+func (v *BuiltinPack) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol109(l, v)
 }
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol17(in *jlexer.Lexer, out *ClientNote) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol110(in *jlexer.Lexer, out *Bans) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1695,12 +11750,37 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol17(in *jlexer.
 			continue
 		}
 		switch key {
-		case "method":
-			out.Method = ClientMethod(in.StringIntern())
-		case "version":
-			out.Version = int(in.Int())
-		case "params":
-			(out.Params).UnmarshalEasyJSON(in)
+		case "bans":
+			if in.IsNull() {
+				in.Skip()
+				out.Bans = nil
+			} else {
+				in.Delim('[')
+				if out.Bans == nil {
+					if !in.IsDelim(']') {
+						out.Bans = make([]*BannedPlayer, 0, 8)
+					} else {
+						out.Bans = []*BannedPlayer{}
+					}
+				} else {
+					out.Bans = (out.Bans)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v149 *BannedPlayer
+					if in.IsNull() {
+						in.Skip()
+						v149 = nil
+					} else {
+						if v149 == nil {
+							v149 = new(BannedPlayer)
+						}
+						easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol111(in, v149)
+					}
+					out.Bans = append(out.Bans, v149)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -1715,52 +11795,57 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol17(in *jlexer.
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol17(out *jwriter.Writer, in ClientNote) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol110(out *jwriter.Writer, in Bans) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"method\":"
+		const prefix string = ",\"bans\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Method))
-	}
-	{
-		const prefix string = ",\"version\":"
-		out.RawString(prefix)
-		out.Int(int(in.Version))
-	}
-	{
-		const prefix string = ",\"params\":"
-		out.RawString(prefix)
-		(in.Params).MarshalEasyJSON(out)
+		if in.Bans == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v150, v151 := range in.Bans {
+				if v150 > 0 {
+					out.RawByte(',')
+				}
+				if v151 == nil {
+					out.RawString("null")
+				} else {
+					easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol111(out, *v151)
+				}
+			}
+			out.RawByte(']')
+		}
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ClientNote) MarshalJSON() ([]byte, error) {
+func (v Bans) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol17(&w, v)
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol110(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ClientNote) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol17(w, v)
+func (v Bans) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol110(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ClientNote) UnmarshalJSON(data []byte) error {
+func (v *Bans) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol17(&r, v)
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol110(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ClientNote) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol17(l, v)
+func (v *Bans) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol110(l, v)
 }
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol18(in *jlexer.Lexer, out *ChangeTurnTimeParams) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol111(in *jlexer.Lexer, out *BannedPlayer) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1779,8 +11864,10 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol18(in *jlexer.
 			continue
 		}
 		switch key {
-		case "seconds":
-			out.Seconds = int(in.Int())
+		case "playerID":
+			out.PlayerID = string(in.String())
+		case "nickname":
+			out.Nickname = string(in.String())
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -1795,42 +11882,23 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol18(in *jlexer.
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol18(out *jwriter.Writer, in ChangeTurnTimeParams) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol111(out *jwriter.Writer, in BannedPlayer) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"seconds\":"
+		const prefix string = ",\"playerID\":"
 		out.RawString(prefix[1:])
-		out.Int(int(in.Seconds))
+		out.String(string(in.PlayerID))
+	}
+	{
+		const prefix string = ",\"nickname\":"
+		out.RawString(prefix)
+		out.String(string(in.Nickname))
 	}
 	out.RawByte('}')
 }
-
-// MarshalJSON supports json.Marshaler interface
-func (v ChangeTurnTimeParams) MarshalJSON() ([]byte, error) {
-	w := jwriter.Writer{}
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol18(&w, v)
-	return w.Buffer.BuildBytes(), w.Error
-}
-
-// MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ChangeTurnTimeParams) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol18(w, v)
-}
-
-// UnmarshalJSON supports json.Unmarshaler interface
-func (v *ChangeTurnTimeParams) UnmarshalJSON(data []byte) error {
-	r := jlexer.Lexer{Data: data}
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol18(&r, v)
-	return r.Error()
-}
-
-// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ChangeTurnTimeParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol18(l, v)
-}
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol19(in *jlexer.Lexer, out *ChangeTurnModeParams) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol112(in *jlexer.Lexer, out *Bandwidth) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1849,8 +11917,10 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol19(in *jlexer.
 			continue
 		}
 		switch key {
-		case "timed":
-			out.Timed = bool(in.Bool())
+		case "bytesSent":
+			out.BytesSent = int64(in.Int64())
+		case "bytesReceived":
+			out.BytesReceived = int64(in.Int64())
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -1865,42 +11935,47 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol19(in *jlexer.
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol19(out *jwriter.Writer, in ChangeTurnModeParams) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol112(out *jwriter.Writer, in Bandwidth) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"timed\":"
+		const prefix string = ",\"bytesSent\":"
 		out.RawString(prefix[1:])
-		out.Bool(bool(in.Timed))
+		out.Int64(int64(in.BytesSent))
+	}
+	{
+		const prefix string = ",\"bytesReceived\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.BytesReceived))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ChangeTurnModeParams) MarshalJSON() ([]byte, error) {
+func (v Bandwidth) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol19(&w, v)
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol112(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ChangeTurnModeParams) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol19(w, v)
+func (v Bandwidth) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol112(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ChangeTurnModeParams) UnmarshalJSON(data []byte) error {
+func (v *Bandwidth) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol19(&r, v)
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol112(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ChangeTurnModeParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol19(l, v)
+func (v *Bandwidth) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol112(l, v)
 }
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol20(in *jlexer.Lexer, out *ChangeTeamParams) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol113(in *jlexer.Lexer, out *BanParams) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1919,8 +11994,8 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol20(in *jlexer.
 			continue
 		}
 		switch key {
-		case "team":
-			out.Team = game.Team(in.Int())
+		case "playerID":
+			out.PlayerID = string(in.String())
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -1935,42 +12010,42 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol20(in *jlexer.
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol20(out *jwriter.Writer, in ChangeTeamParams) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol113(out *jwriter.Writer, in BanParams) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"team\":"
+		const prefix string = ",\"playerID\":"
 		out.RawString(prefix[1:])
-		out.Int(int(in.Team))
+		out.String(string(in.PlayerID))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ChangeTeamParams) MarshalJSON() ([]byte, error) {
+func (v BanParams) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol20(&w, v)
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol113(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ChangeTeamParams) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol20(w, v)
+func (v BanParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol113(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ChangeTeamParams) UnmarshalJSON(data []byte) error {
+func (v *BanParams) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol20(&r, v)
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol113(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ChangeTeamParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol20(l, v)
+func (v *BanParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol113(l, v)
 }
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol21(in *jlexer.Lexer, out *ChangeRoleParams) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol114(in *jlexer.Lexer, out *AuditLog) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1989,8 +12064,37 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol21(in *jlexer.
 			continue
 		}
 		switch key {
-		case "spymaster":
-			out.Spymaster = bool(in.Bool())
+		case "entries":
+			if in.IsNull() {
+				in.Skip()
+				out.Entries = nil
+			} else {
+				in.Delim('[')
+				if out.Entries == nil {
+					if !in.IsDelim(']') {
+						out.Entries = make([]*AuditEntry, 0, 8)
+					} else {
+						out.Entries = []*AuditEntry{}
+					}
+				} else {
+					out.Entries = (out.Entries)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v152 *AuditEntry
+					if in.IsNull() {
+						in.Skip()
+						v152 = nil
+					} else {
+						if v152 == nil {
+							v152 = new(AuditEntry)
+						}
+						(*v152).UnmarshalEasyJSON(in)
+					}
+					out.Entries = append(out.Entries, v152)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -2005,42 +12109,57 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol21(in *jlexer.
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol21(out *jwriter.Writer, in ChangeRoleParams) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol114(out *jwriter.Writer, in AuditLog) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"spymaster\":"
+		const prefix string = ",\"entries\":"
 		out.RawString(prefix[1:])
-		out.Bool(bool(in.Spymaster))
+		if in.Entries == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v153, v154 := range in.Entries {
+				if v153 > 0 {
+					out.RawByte(',')
+				}
+				if v154 == nil {
+					out.RawString("null")
+				} else {
+					(*v154).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ChangeRoleParams) MarshalJSON() ([]byte, error) {
+func (v AuditLog) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol21(&w, v)
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol114(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ChangeRoleParams) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol21(w, v)
+func (v AuditLog) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol114(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ChangeRoleParams) UnmarshalJSON(data []byte) error {
+func (v *AuditLog) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol21(&r, v)
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol114(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ChangeRoleParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol21(l, v)
+func (v *AuditLog) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol114(l, v)
 }
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol22(in *jlexer.Lexer, out *ChangePackParams) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol115(in *jlexer.Lexer, out *AuditEntry) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2058,11 +12177,50 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol22(in *jlexer.
 			in.WantComma()
 			continue
 		}
-		switch key {
-		case "num":
-			out.Num = int(in.Int())
-		case "enable":
-			out.Enable = bool(in.Bool())
+		switch key {
+		case "time":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Time).UnmarshalJSON(data))
+			}
+		case "action":
+			out.Action = string(in.String())
+		case "actor":
+			out.Actor = string(in.String())
+		case "actorName":
+			out.ActorName = string(in.String())
+		case "target":
+			out.Target = string(in.String())
+		case "changes":
+			if in.IsNull() {
+				in.Skip()
+				out.Changes = nil
+			} else {
+				in.Delim('[')
+				if out.Changes == nil {
+					if !in.IsDelim(']') {
+						out.Changes = make([]*AuditChange, 0, 8)
+					} else {
+						out.Changes = []*AuditChange{}
+					}
+				} else {
+					out.Changes = (out.Changes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v155 *AuditChange
+					if in.IsNull() {
+						in.Skip()
+						v155 = nil
+					} else {
+						if v155 == nil {
+							v155 = new(AuditChange)
+						}
+						easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol116(in, v155)
+					}
+					out.Changes = append(out.Changes, v155)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -2077,47 +12235,80 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol22(in *jlexer.
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol22(out *jwriter.Writer, in ChangePackParams) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol115(out *jwriter.Writer, in AuditEntry) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"num\":"
+		const prefix string = ",\"time\":"
 		out.RawString(prefix[1:])
-		out.Int(int(in.Num))
+		out.Raw((in.Time).MarshalJSON())
 	}
 	{
-		const prefix string = ",\"enable\":"
+		const prefix string = ",\"action\":"
 		out.RawString(prefix)
-		out.Bool(bool(in.Enable))
+		out.String(string(in.Action))
+	}
+	if in.Actor != "" {
+		const prefix string = ",\"actor\":"
+		out.RawString(prefix)
+		out.String(string(in.Actor))
+	}
+	if in.ActorName != "" {
+		const prefix string = ",\"actorName\":"
+		out.RawString(prefix)
+		out.String(string(in.ActorName))
+	}
+	if in.Target != "" {
+		const prefix string = ",\"target\":"
+		out.RawString(prefix)
+		out.String(string(in.Target))
+	}
+	if len(in.Changes) != 0 {
+		const prefix string = ",\"changes\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v156, v157 := range in.Changes {
+				if v156 > 0 {
+					out.RawByte(',')
+				}
+				if v157 == nil {
+					out.RawString("null")
+				} else {
+					easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol116(out, *v157)
+				}
+			}
+			out.RawByte(']')
+		}
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ChangePackParams) MarshalJSON() ([]byte, error) {
+func (v AuditEntry) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol22(&w, v)
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol115(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ChangePackParams) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol22(w, v)
+func (v AuditEntry) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol115(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ChangePackParams) UnmarshalJSON(data []byte) error {
+func (v *AuditEntry) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol22(&r, v)
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol115(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ChangePackParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol22(l, v)
+func (v *AuditEntry) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol115(l, v)
 }
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol23(in *jlexer.Lexer, out *ChangeNicknameParams) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol116(in *jlexer.Lexer, out *AuditChange) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2136,8 +12327,12 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol23(in *jlexer.
 			continue
 		}
 		switch key {
-		case "nickname":
-			out.Nickname = string(in.String())
+		case "field":
+			out.Field = string(in.String())
+		case "before":
+			out.Before = string(in.String())
+		case "after":
+			out.After = string(in.String())
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -2152,42 +12347,28 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol23(in *jlexer.
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol23(out *jwriter.Writer, in ChangeNicknameParams) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol116(out *jwriter.Writer, in AuditChange) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"nickname\":"
+		const prefix string = ",\"field\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Nickname))
+		out.String(string(in.Field))
+	}
+	{
+		const prefix string = ",\"before\":"
+		out.RawString(prefix)
+		out.String(string(in.Before))
+	}
+	{
+		const prefix string = ",\"after\":"
+		out.RawString(prefix)
+		out.String(string(in.After))
 	}
 	out.RawByte('}')
 }
-
-// MarshalJSON supports json.Marshaler interface
-func (v ChangeNicknameParams) MarshalJSON() ([]byte, error) {
-	w := jwriter.Writer{}
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol23(&w, v)
-	return w.Buffer.BuildBytes(), w.Error
-}
-
-// MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ChangeNicknameParams) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol23(w, v)
-}
-
-// UnmarshalJSON supports json.Unmarshaler interface
-func (v *ChangeNicknameParams) UnmarshalJSON(data []byte) error {
-	r := jlexer.Lexer{Data: data}
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol23(&r, v)
-	return r.Error()
-}
-
-// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ChangeNicknameParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol23(l, v)
-}
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol24(in *jlexer.Lexer, out *ChangeHideBombParams) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol117(in *jlexer.Lexer, out *AnswerMergeParams) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2206,8 +12387,10 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol24(in *jlexer.
 			continue
 		}
 		switch key {
-		case "hideBomb":
-			out.HideBomb = bool(in.Bool())
+		case "id":
+			out.ID = string(in.String())
+		case "accept":
+			out.Accept = bool(in.Bool())
 		default:
 			in.AddError(&jlexer.LexerError{
 				Offset: in.GetPos(),
@@ -2222,42 +12405,47 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol24(in *jlexer.
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol24(out *jwriter.Writer, in ChangeHideBombParams) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol117(out *jwriter.Writer, in AnswerMergeParams) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"hideBomb\":"
+		const prefix string = ",\"id\":"
 		out.RawString(prefix[1:])
-		out.Bool(bool(in.HideBomb))
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"accept\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Accept))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ChangeHideBombParams) MarshalJSON() ([]byte, error) {
+func (v AnswerMergeParams) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol24(&w, v)
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol117(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ChangeHideBombParams) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol24(w, v)
+func (v AnswerMergeParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol117(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ChangeHideBombParams) UnmarshalJSON(data []byte) error {
+func (v *AnswerMergeParams) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol24(&r, v)
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol117(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ChangeHideBombParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol24(l, v)
+func (v *AnswerMergeParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol117(l, v)
 }
-func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol25(in *jlexer.Lexer, out *AddPacksParams) {
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol118(in *jlexer.Lexer, out *AddPacksParams) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -2298,12 +12486,12 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol25(in *jlexer.
 					out.Packs = (out.Packs)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v19 struct {
+					var v158 struct {
 						Name  string   `json:"name"`
 						Words []string `json:"words"`
 					}
-					easyjsonE4425964Decode(in, &v19)
-					out.Packs = append(out.Packs, v19)
+					easyjsonE4425964Decode(in, &v158)
+					out.Packs = append(out.Packs, v158)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -2322,7 +12510,7 @@ func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol25(in *jlexer.
 		in.Consumed()
 	}
 }
-func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol25(out *jwriter.Writer, in AddPacksParams) {
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol118(out *jwriter.Writer, in AddPacksParams) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -2333,11 +12521,11 @@ func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol25(out *jwrite
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v20, v21 := range in.Packs {
-				if v20 > 0 {
+			for v159, v160 := range in.Packs {
+				if v159 > 0 {
 					out.RawByte(',')
 				}
-				easyjsonE4425964Encode(out, v21)
+				easyjsonE4425964Encode(out, v160)
 			}
 			out.RawByte(']')
 		}
@@ -2348,25 +12536,25 @@ func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol25(out *jwrite
 // MarshalJSON supports json.Marshaler interface
 func (v AddPacksParams) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol25(&w, v)
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol118(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v AddPacksParams) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol25(w, v)
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol118(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *AddPacksParams) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol25(&r, v)
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol118(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *AddPacksParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol25(l, v)
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol118(l, v)
 }
 func easyjsonE4425964Decode(in *jlexer.Lexer, out *struct {
 	Name  string   `json:"name"`
@@ -2408,9 +12596,9 @@ func easyjsonE4425964Decode(in *jlexer.Lexer, out *struct {
 					out.Words = (out.Words)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v22 string
-					v22 = string(in.String())
-					out.Words = append(out.Words, v22)
+					var v161 string
+					v161 = string(in.String())
+					out.Words = append(out.Words, v161)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -2448,14 +12636,196 @@ func easyjsonE4425964Encode(out *jwriter.Writer, in struct {
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v23, v24 := range in.Words {
-				if v23 > 0 {
+			for v162, v163 := range in.Words {
+				if v162 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v24))
+				out.String(string(v163))
 			}
 			out.RawByte(']')
 		}
 	}
 	out.RawByte('}')
 }
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol119(in *jlexer.Lexer, out *AddPackURLParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "name":
+			out.Name = string(in.String())
+		case "url":
+			out.URL = string(in.String())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol119(out *jwriter.Writer, in AddPackURLParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"url\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.URL))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v AddPackURLParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol119(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v AddPackURLParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol119(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *AddPackURLParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol119(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *AddPackURLParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol119(l, v)
+}
+func easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol120(in *jlexer.Lexer, out *Ack) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "ok":
+			out.OK = bool(in.Bool())
+		case "error":
+			if in.IsNull() {
+				in.Skip()
+				out.Error = nil
+			} else {
+				if out.Error == nil {
+					out.Error = new(Error)
+				}
+				(*out.Error).UnmarshalEasyJSON(in)
+			}
+		case "version":
+			out.Version = int64(in.Int64())
+		default:
+			in.AddError(&jlexer.LexerError{
+				Offset: in.GetPos(),
+				Reason: "unknown field",
+				Data:   key,
+			})
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol120(out *jwriter.Writer, in Ack) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"ok\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.OK))
+	}
+	if in.Error != nil {
+		const prefix string = ",\"error\":"
+		out.RawString(prefix)
+		(*in.Error).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"version\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Version))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Ack) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol120(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Ack) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE4425964EncodeGithubComZikaerohCodiesInternalProtocol120(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Ack) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol120(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Ack) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE4425964DecodeGithubComZikaerohCodiesInternalProtocol120(l, v)
+}
@@ -0,0 +1,56 @@
+package protocol
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCloseHintEncode(t *testing.T) {
+	tests := []struct {
+		name string
+		hint CloseHint
+		want string
+	}{
+		{"retry", CloseHint{Retry: true, AfterMS: 2000, Reason: "going away"}, `{"retry":true,"afterMs":2000,"reason":"going away"}`},
+		{"noRetry", CloseHint{}, `{"retry":false}`},
+		{"room", CloseHint{Retry: true, RoomID: "abcdefghijklmnop"}, `{"retry":true,"roomId":"abcdefghijklmnop"}`},
+		{"longReason", CloseHint{Reason: strings.Repeat("x", 200)}, `{"retry":false,"reason":"` + strings.Repeat("x", MaxCloseReason-27) + `"}`},
+		{"longRoom", CloseHint{Retry: true, RoomID: strings.Repeat("r", 200), Reason: "merged"}, `{"retry":false}`},
+	}
+
+	for _, test := range tests {
+		got := test.hint.Encode()
+		assert.Equal(t, got, test.want, test.name)
+		assert.Assert(t, len(got) <= MaxCloseReason, test.name)
+	}
+}
+
+func TestCloseHintEncodeRunes(t *testing.T) {
+	// Escaped and multi-byte runes are never cut in half.
+	for _, r := range []string{"é", " ", `"`, "日本"} {
+		got := CloseHint{Retry: true, Reason: strings.Repeat(r, 100)}.Encode()
+		assert.Assert(t, len(got) <= MaxCloseReason, r)
+
+		var hint CloseHint
+		assert.NilError(t, json.Unmarshal([]byte(got), &hint), r)
+		assert.Assert(t, hint.Retry, r)
+		assert.Assert(t, strings.HasPrefix(strings.Repeat(r, 100), hint.Reason), r)
+		assert.Assert(t, len(hint.Reason) > 0, r)
+	}
+}
+
+func TestRoomRequestPassLength(t *testing.T) {
+	req := &RoomRequest{RoomName: "room", RoomPass: strings.Repeat("x", MaxRoomPass)}
+	_, valid := req.Valid()
+	assert.Assert(t, valid)
+
+	req.RoomPass += "x"
+	errs, valid := req.Valid()
+	assert.Assert(t, !valid)
+	assert.Equal(t, len(errs), 1)
+	assert.Equal(t, errs[0].Field, "roomPass")
+	assert.Equal(t, errs[0].Code, ErrorTooLong)
+}
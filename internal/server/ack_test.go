@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func (c *testClient) acks() []*protocol.Ack {
+	var acks []*protocol.Ack
+	for _, n := range c.notes {
+		if n.Method == "ack" {
+			acks = append(acks, n.Params.(*protocol.Ack))
+		}
+	}
+	return acks
+}
+
+func (r *Room) testCommand(t *testing.T, id game.PlayerID, commandID string, version int64, method protocol.ClientMethod, params interface{}) {
+	t.Helper()
+
+	raw, err := json.Marshal(params)
+	assert.NilError(t, err)
+
+	r.hold(func() {
+		err = r.handleNote(context.Background(), id, &protocol.ClientNote{
+			Method:  method,
+			Version: version,
+			Params:  raw,
+			ID:      commandID,
+		})
+	})
+	assert.NilError(t, err)
+}
+
+func TestAckChange(t *testing.T) {
+	r := newTestRoom(t)
+	c := r.addTestClient(t, "g0", 0, false)
+	version := r.version()
+
+	r.testCommand(t, "g0", "1", version, protocol.ChangeTeamMethod, &protocol.ChangeTeamParams{Team: 1})
+
+	assert.DeepEqual(t, c.acks(), []*protocol.Ack{{ID: "1", OK: true, Version: version + 1}})
+
+	// The ack is queued ahead of the broadcast.
+	last := c.notes[len(c.notes)-2:]
+	assert.Equal(t, string(last[0].Method), "ack")
+	assert.Equal(t, string(last[1].Method), "state")
+}
+
+func TestAckNoOp(t *testing.T) {
+	r := newTestRoom(t)
+	c := r.addTestClient(t, "g0", 0, false)
+	version := r.version()
+	sent := len(c.notes)
+
+	r.testCommand(t, "g0", "1", version, protocol.ChangeTeamMethod, &protocol.ChangeTeamParams{Team: 0})
+
+	assert.DeepEqual(t, c.acks(), []*protocol.Ack{{ID: "1", OK: true, Version: version}})
+	assert.Equal(t, len(c.notes), sent+1)
+}
+
+func TestAckRuleViolation(t *testing.T) {
+	r := newTestRoom(t)
+	c := r.addTestClient(t, "spy", 0, true)
+	var version int64
+	var max int
+	r.hold(func() {
+		r.room.Turn = 0
+		r.room.ChangeBoundClues(true)
+		version = r.room.Version
+		max = r.room.Board.WordCounts[0]
+	})
+
+	r.testCommand(t, "spy", "clue", version, protocol.GiveClueMethod, &protocol.GiveClueParams{Word: "ANIMAL", Count: max + 1})
+
+	acks := c.acks()
+	assert.Equal(t, len(acks), 1)
+	assert.Assert(t, !acks[0].OK)
+	assert.Equal(t, acks[0].Error.Code, "clueTooHigh")
+	assert.Equal(t, acks[0].Version, version)
+	assert.Equal(t, len(c.errors()), 1)
+}
+
+func TestAckStaleVersion(t *testing.T) {
+	r := newTestRoom(t)
+	c := r.addTestClient(t, "g0", 0, false)
+	version := r.version()
+
+	r.testCommand(t, "g0", "1", version-1, protocol.ChangeTeamMethod, &protocol.ChangeTeamParams{Team: 1})
+
+	acks := c.acks()
+	assert.Equal(t, len(acks), 1)
+	assert.Assert(t, !acks[0].OK)
+	assert.Equal(t, acks[0].Error.Code, "staleVersion")
+	assert.Equal(t, acks[0].Version, version)
+	r.hold(func() {
+		assert.Equal(t, r.room.Players["g0"].Team, game.Team(0))
+	})
+}
+
+func TestAckWithoutID(t *testing.T) {
+	r := newTestRoom(t)
+	c := r.addTestClient(t, "g0", 0, false)
+
+	r.testNote(t, "g0", protocol.ChangeTeamMethod, &protocol.ChangeTeamParams{Team: 1})
+	assert.Equal(t, len(c.acks()), 0)
+}
+
+func TestAckBeforeBroadcastOnWire(t *testing.T) {
+	r := newTestRoom(t)
+	c := dialTestRoom(t, r, ConnOptions{Nickname: "player"})
+
+	var state protocol.State
+	readNote(t, c, "state", &state)
+	version := state.RoomState.Version
+
+	// Several commands in a row; each ack precedes the state it caused.
+	for i, team := range []game.Team{1, 0, 1} {
+		raw, err := json.Marshal(&protocol.ChangeTeamParams{Team: team})
+		assert.NilError(t, err)
+		writeRawNote(t, c, &protocol.ClientNote{
+			Method:  protocol.ChangeTeamMethod,
+			Version: version + int64(i),
+			Params:  raw,
+			ID:      string(rune('a' + i)),
+		})
+
+		var ack protocol.Ack
+		assert.Equal(t, nextNote(t, c, &ack), protocol.ServerMethod("ack"))
+		assert.Equal(t, ack.ID, string(rune('a'+i)))
+		assert.Assert(t, ack.OK)
+		assert.Equal(t, ack.Version, version+int64(i)+1)
+
+		assert.Equal(t, nextNote(t, c, &state), protocol.ServerMethod("state"))
+		assert.Equal(t, state.RoomState.Version, ack.Version)
+	}
+}
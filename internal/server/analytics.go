@@ -0,0 +1,108 @@
+package server
+
+import (
+	"time"
+
+	"github.com/zikaeroh/codies/internal/analytics"
+	"github.com/zikaeroh/codies/internal/gamefeed"
+)
+
+// analyticsGame describes the room's current game for analytics. The settings
+// fingerprint covers the same options as updateOptions, and nothing which
+// could identify the room or its players.
+//
+// Must be called on the room's goroutine.
+func (r *Room) analyticsGame() analytics.Game {
+	values := r.options().values()
+	settings := make([]string, len(values))
+	for i, v := range values {
+		settings[i] = v.Field + "=" + v.After
+	}
+
+	return analytics.Game{
+		Mode:    r.mode(),
+		Options: analytics.Fingerprint(settings...),
+		Ruleset: r.rulesetHash,
+	}
+}
+
+// recordGame records games started or finished since the snapshot was taken.
+//
+// Must be called on the room's goroutine.
+func (r *Room) recordGame(before turnSnapshot) {
+	now := r.clock.Now()
+	a := r.counters.analytics
+
+	if before.games != r.room.Games {
+		r.gameStart = now
+		r.reveals = nil
+		if a != nil {
+			a.GameStarted(r.ID, r.analyticsGame())
+		}
+		return
+	}
+
+	if before.won || r.room.Winner == nil {
+		return
+	}
+
+	r.gameEnd = now
+	r.finishGameLog()
+	metricGamesCompleted.Inc()
+	length := now.Sub(r.gameStart)
+	if a != nil {
+		a.GameFinished(r.ID, r.analyticsGame(), length, r.room.Forfeit)
+	}
+	if r.counters.feed != nil {
+		r.counters.feed.Record(r.feedGame(length))
+	}
+	r.deliverWebhook()
+}
+
+// feedGame describes the room's finished game for the game feed.
+//
+// Must be called on the room's goroutine.
+func (r *Room) feedGame(length time.Duration) gamefeed.Game {
+	room := r.room
+
+	teams := make([][]string, len(room.Teams))
+	for i, team := range room.Teams {
+		teams[i] = make([]string, len(team))
+		for j, id := range team {
+			teams[i][j] = string(id)
+		}
+	}
+
+	return gamefeed.Game{
+		Ruleset: r.rulesetHash,
+		Seed:    room.Seed,
+		Teams:   teams,
+		Winner:  int(*room.Winner),
+		Outcome: r.outcome(),
+		Length:  length,
+		Turns:   room.Turns(),
+	}
+}
+
+// outcome returns how the room's finished game was won.
+//
+// Must be called on the room's goroutine.
+func (r *Room) outcome() gamefeed.Outcome {
+	switch {
+	case r.room.Forfeit:
+		return gamefeed.Forfeit
+	case r.room.Tracker:
+		return gamefeed.Declared
+	}
+
+	for _, b := range r.room.Boards {
+		for row := 0; row < b.Rows; row++ {
+			for col := 0; col < b.Cols; col++ {
+				if tile := b.Get(row, col); tile.Bomb && tile.Revealed {
+					return gamefeed.Bomb
+				}
+			}
+		}
+	}
+	return gamefeed.Words
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/analytics"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func TestAnalytics(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	events, err := analytics.Open(path)
+	assert.NilError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	s := NewServer(Options{Analytics: events})
+	go s.Run(ctx) //nolint:errcheck
+
+	r, err := s.CreateRoomWait(ctx, "secret room", "pass", RoomOptions{Tracker: true}, 0)
+	assert.NilError(t, err)
+	r.addTestClient(t, "host", 0, false)
+
+	r.testNote(t, "host", protocol.DeclareWinnerMethod, &protocol.DeclareWinnerParams{Team: 0})
+	r.testNote(t, "host", protocol.ChangeNicknameMethod, &protocol.ChangeNicknameParams{Nickname: "Alice"})
+	r.testNote(t, "host", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	assert.Assert(t, s.DeleteRoom(ctx, r.ID))
+	assert.NilError(t, events.Close())
+
+	b, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+
+	for _, secret := range []string{r.ID, "secret room", "host", "Alice", "pass"} {
+		assert.Assert(t, !strings.Contains(string(b), secret), "analytics contain %q", secret)
+	}
+
+	var types []analytics.EventType
+	for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+		var e analytics.Event
+		assert.NilError(t, json.Unmarshal([]byte(line), &e))
+		types = append(types, e.Type)
+		if e.Type != analytics.RoomClosed {
+			assert.Equal(t, e.Mode, "tracker")
+		}
+	}
+
+	assert.DeepEqual(t, types, []analytics.EventType{
+		analytics.RoomCreated,
+		analytics.GameStarted,
+		analytics.GameFinished,
+		analytics.GameStarted,
+		analytics.RoomClosed,
+	})
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+)
+
+// maxAuditEntries bounds a room's audit log; the oldest entries are dropped
+// first.
+const maxAuditEntries = 100
+
+// Audited actions.
+const (
+	auditOptions           = "options"
+	auditMintMirrorToken   = "mintMirrorToken"
+	auditRevokeMirrorToken = "revokeMirrorToken"
+	auditHostTransfer      = "hostTransfer"
+	auditLogLevel          = "logLevel"
+	auditForceNewGame      = "forceNewGame"
+	auditForceReveal       = "forceReveal"
+	auditRequestMerge      = "requestMerge"
+	auditDeclineMerge      = "declineMerge"
+	auditMergeOut          = "mergeOut"
+	auditMergeIn           = "mergeIn"
+	auditKick              = "kick"
+	auditBan               = "ban"
+	auditUnban             = "unban"
+	auditMatchmade         = "matchmade"
+	auditAddPack           = "addPack"
+	auditRemovePack        = "removePack"
+	auditTeamConstraints   = "teamConstraints"
+	auditUndo              = "undo"
+	auditWebhook           = "webhook"
+	auditPoll              = "poll"
+)
+
+// audit records a privileged action. The actor may be empty for actions taken
+// by an operator or the server.
+//
+// Must be called on the room's goroutine.
+func (r *Room) audit(action string, actor game.PlayerID, target string, changes ...*protocol.AuditChange) {
+	entry := &protocol.AuditEntry{
+		Time:    r.clock.Now(),
+		Action:  action,
+		Actor:   actor,
+		Target:  target,
+		Changes: changes,
+	}
+
+	if p := r.room.Players[actor]; p != nil {
+		entry.ActorName = p.Nickname
+	}
+
+	if len(r.auditLog) >= maxAuditEntries {
+		r.auditLog = append(r.auditLog[:0:0], r.auditLog[len(r.auditLog)-maxAuditEntries+1:]...)
+	}
+	r.auditLog = append(r.auditLog, entry)
+}
+
+// sendAuditLog sends the host the audit log; nobody else may read it.
+//
+// Must be called on the room's goroutine.
+func (r *Room) sendAuditLog(playerID game.PlayerID) {
+	if playerID != r.room.Host {
+		return
+	}
+
+	if sender := r.players[playerID]; sender != nil {
+		sender(priorityTargeted, protocol.NewAuditLogNote(r.copyAuditLog()))
+	}
+}
+
+// copyAuditLog copies the audit log, and its entries, so that it may be used
+// off the room's goroutine.
+//
+// Must be called on the room's goroutine.
+func (r *Room) copyAuditLog() []*protocol.AuditEntry {
+	entries := make([]*protocol.AuditEntry, len(r.auditLog))
+	for i, e := range r.auditLog {
+		e := *e
+		if e.Changes != nil {
+			changes := make([]*protocol.AuditChange, len(e.Changes))
+			for j, c := range e.Changes {
+				c := *c
+				changes[j] = &c
+			}
+			e.Changes = changes
+		}
+		entries[i] = &e
+	}
+	return entries
+}
+
+// AuditLog returns the room's audit log, oldest first.
+func (r *Room) AuditLog() (log []*protocol.AuditEntry) {
+	r.do(func() { log = r.copyAuditLog() })
+	return log
+}
+
+// auditToken identifies a mirror token in the audit log without revealing it.
+func auditToken(token string) string {
+	if len(token) > 8 {
+		return token[:8]
+	}
+	return token
+}
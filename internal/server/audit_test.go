@@ -0,0 +1,185 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"go.uber.org/zap/zapcore"
+	"gotest.tools/v3/assert"
+)
+
+func (c *testClient) auditLogs() []*protocol.AuditLog {
+	var logs []*protocol.AuditLog
+	for _, n := range c.notes {
+		if n.Method == "auditLog" {
+			logs = append(logs, n.Params.(*protocol.AuditLog))
+		}
+	}
+	return logs
+}
+
+func TestAuditOptionCommands(t *testing.T) {
+	tests := []struct {
+		method protocol.ClientMethod
+		params interface{}
+		field  string
+	}{
+		{protocol.ChangeTurnModeMethod, &protocol.ChangeTurnModeParams{Timed: true}, "timed"},
+		{protocol.ChangeTurnTimeMethod, &protocol.ChangeTurnTimeParams{Seconds: 90}, "turnTime"},
+		{protocol.ChangeHideBombMethod, &protocol.ChangeHideBombParams{HideBomb: true}, "hideBomb"},
+		{protocol.ChangeBoundCluesMethod, &protocol.ChangeBoundCluesParams{BoundClues: true}, "boundClues"},
+		{protocol.ChangeSuggestCluesMethod, &protocol.ChangeSuggestCluesParams{SuggestClues: true}, "suggestClues"},
+		{protocol.ChangePenaltiesMethod, &protocol.ChangePenaltiesParams{Limit: 3}, "penaltyLimit"},
+		{protocol.ChangeSpymastersMethod, &protocol.ChangeSpymastersParams{Limit: 2}, "spymasterLimit"},
+		{protocol.ChangeMirrorDelayMethod, &protocol.ChangeMirrorDelayParams{Seconds: 60}, "mirrorDelay"},
+		{protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{PenaltyLimit: intPtr(2)}, "penaltyLimit"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(string(test.method), func(t *testing.T) {
+			r := newTestRoom(t)
+			c := newFakeClock()
+			r.setClock(c)
+			r.addTestClient(t, "host", 0, false)
+
+			r.testNote(t, "host", test.method, test.params)
+
+			log := r.AuditLog()
+			assert.Equal(t, len(log), 1)
+			assert.Equal(t, log[0].Action, auditOptions)
+			assert.Equal(t, log[0].Actor, game.PlayerID("host"))
+			assert.Equal(t, log[0].ActorName, "host")
+			assert.Equal(t, log[0].Time, c.Now())
+			assert.Equal(t, len(log[0].Changes), 1)
+			assert.Equal(t, log[0].Changes[0].Field, test.field)
+		})
+	}
+}
+
+func TestAuditOptionValues(t *testing.T) {
+	r := newTestRoom(t)
+	r.addTestClient(t, "host", 0, false)
+
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{
+		Timed:    boolPtr(true),
+		TurnTime: intPtr(45),
+	})
+
+	log := r.AuditLog()
+	assert.Equal(t, len(log), 1)
+	assert.DeepEqual(t, log[0].Changes, []*protocol.AuditChange{
+		{Field: "timed", Before: "false", After: "true"},
+		{Field: "turnTime", Before: "60", After: "45"},
+	})
+}
+
+func TestAuditOnlyChanges(t *testing.T) {
+	r := newTestRoom(t)
+	r.addTestClient(t, "host", 0, false)
+	r.addTestClient(t, "other", 1, false)
+
+	// No-ops, rejected updates, and commands the player may not send.
+	r.testNote(t, "host", protocol.ChangeHideBombMethod, &protocol.ChangeHideBombParams{HideBomb: false})
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{PenaltyLimit: intPtr(-1)})
+	r.testNote(t, "other", protocol.ChangeMirrorDelayMethod, &protocol.ChangeMirrorDelayParams{Seconds: 60})
+	r.testNote(t, "other", protocol.MintMirrorTokenMethod, &protocol.MintMirrorTokenParams{})
+
+	assert.Equal(t, len(r.AuditLog()), 0)
+}
+
+func TestAuditMirrorTokens(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+
+	token := r.testMirrorToken(t, host)
+	assert.Equal(t, len(r.AuditLog()), 1)
+
+	r.testNote(t, "host", protocol.RevokeMirrorTokenMethod, &protocol.RevokeMirrorTokenParams{Token: token})
+
+	log := r.AuditLog()
+	assert.Equal(t, len(log), 2)
+	assert.Equal(t, log[0].Action, auditMintMirrorToken)
+	assert.Equal(t, log[1].Action, auditRevokeMirrorToken)
+
+	// The token itself isn't recorded, only enough to tell tokens apart.
+	assert.Equal(t, log[0].Target, log[1].Target)
+	assert.Assert(t, strings.HasPrefix(token, log[0].Target))
+	assert.Assert(t, log[0].Target != token)
+}
+
+func TestAuditHostTransfer(t *testing.T) {
+	r := newTestRoom(t)
+	r.joinTestClient(t, "host", false)
+	next := r.joinTestClient(t, "next", false)
+	r.joinTestClient(t, "other", false)
+
+	r.testNote(t, "host", protocol.ChangeHideBombMethod, &protocol.ChangeHideBombParams{HideBomb: true})
+	r.disconnect("host", nil)
+
+	log := r.AuditLog()
+	assert.Equal(t, len(log), 2)
+	assert.Equal(t, log[1].Action, auditHostTransfer)
+	assert.Equal(t, log[1].Target, "next")
+	assert.DeepEqual(t, log[1].Changes, []*protocol.AuditChange{{Field: "host", Before: "host", After: "next"}})
+
+	// Other players leaving isn't audited.
+	r.disconnect("other", nil)
+	assert.Equal(t, len(r.AuditLog()), 2)
+
+	// The new host sees everything, including the old host's actions.
+	r.testNote(t, "next", protocol.GetAuditLogMethod, &protocol.GetAuditLogParams{})
+	logs := next.auditLogs()
+	assert.Equal(t, len(logs), 1)
+	assert.DeepEqual(t, logs[0].Entries, log)
+}
+
+func TestAuditLogHostOnly(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+	other := r.addTestClient(t, "other", 1, false)
+
+	r.testNote(t, "other", protocol.GetAuditLogMethod, &protocol.GetAuditLogParams{})
+	assert.Equal(t, len(other.auditLogs()), 0)
+
+	r.testNote(t, "host", protocol.GetAuditLogMethod, &protocol.GetAuditLogParams{})
+	logs := host.auditLogs()
+	assert.Equal(t, len(logs), 1)
+	assert.Assert(t, logs[0].Entries != nil)
+}
+
+func TestAuditLogBounded(t *testing.T) {
+	r := newTestRoom(t)
+	r.addTestClient(t, "host", 0, false)
+
+	for i := 0; i < maxAuditEntries+10; i++ {
+		r.testNote(t, "host", protocol.ChangeHideBombMethod, &protocol.ChangeHideBombParams{HideBomb: i%2 == 0})
+	}
+
+	log := r.AuditLog()
+	assert.Equal(t, len(log), maxAuditEntries)
+
+	// The oldest entries were dropped; the last change turned the setting off.
+	assert.DeepEqual(t, log[len(log)-1].Changes, []*protocol.AuditChange{{Field: "hideBomb", Before: "true", After: "false"}})
+}
+
+func TestAuditLogCopied(t *testing.T) {
+	r := newRoom(context.Background(), "room", nil, "room", &counters{})
+	r.SetLogLevel(zapcore.DebugLevel, time.Minute)
+
+	log := r.AuditLog()
+	assert.Equal(t, len(log), 1)
+	log[0].Action = "changed"
+	log[0].Changes[0].After = "changed"
+
+	log = r.AuditLog()
+	assert.Equal(t, log[0].Action, auditLogLevel)
+	assert.Equal(t, log[0].Changes[0].After, zapcore.DebugLevel.String())
+
+	r.LogLevel().Level = zapcore.ErrorLevel
+	assert.Equal(t, r.LogLevel().Level, zapcore.DebugLevel)
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"nhooyr.io/websocket"
+)
+
+// Bans are kept per room, until it's pruned. A player's ID only lasts as long
+// as their connection, so a ban is listed by the ID the player had, but is
+// enforced against the address they connected from. Everyone joining from
+// that address is kept out, and a player who connected without one (as in
+// tests) can only be kicked.
+const maxBans = 50
+
+// closeBanned closes connections from banned players, like closeMirrorRejected
+// does refused mirrors; clients shouldn't retry either.
+const closeBanned websocket.StatusCode = 4403
+
+var errBanned = &game.Error{
+	Code:    "banned",
+	Message: "You have been banned from this room.",
+}
+
+type ban struct {
+	playerID game.PlayerID
+	nickname string
+	ip       net.IP
+}
+
+// ban kicks a player and keeps them out of the room. Only the host may ban,
+// and not themselves.
+//
+// Must be called on the room's goroutine.
+func (r *Room) ban(playerID, target game.PlayerID) error {
+	if playerID != r.room.Host || target == playerID {
+		return nil
+	}
+
+	p := r.room.Players[target]
+	if p == nil {
+		return nil
+	}
+
+	if len(r.bans) >= maxBans {
+		max := maxBans
+		return &game.Error{
+			Code:    "banLimit",
+			Message: fmt.Sprintf("Rooms may have at most %d bans.", max),
+			Limit:   &max,
+		}
+	}
+
+	r.bans = append(r.bans, &ban{playerID: target, nickname: p.Nickname, ip: r.addrs[target]})
+	r.banCount.Store(int64(len(r.bans)))
+
+	// The connection's own leave finds the player already gone.
+	if w := r.conns[target]; w != nil {
+		w.close(closeBanned, "banned by the host")
+	}
+
+	r.audit(auditBan, playerID, p.Nickname)
+	r.removePlayer(target)
+	r.sendBans(playerID)
+	return nil
+}
+
+// unban lifts a ban, by the ID the player had when banned. Only the host may
+// unban.
+//
+// Must be called on the room's goroutine.
+func (r *Room) unban(playerID, target game.PlayerID) {
+	if playerID != r.room.Host {
+		return
+	}
+
+	for i, b := range r.bans {
+		if b.playerID != target {
+			continue
+		}
+
+		r.bans = append(r.bans[:i], r.bans[i+1:]...)
+		r.banCount.Store(int64(len(r.bans)))
+		r.audit(auditUnban, playerID, b.nickname)
+		r.sendBans(playerID)
+		return
+	}
+}
+
+// banned reports whether connections from an address are kept out.
+//
+// Must be called on the room's goroutine.
+func (r *Room) banned(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+
+	for _, b := range r.bans {
+		if b.ip.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// sendBans sends the host the ban list. Addresses are never sent.
+//
+// Must be called on the room's goroutine.
+func (r *Room) sendBans(playerID game.PlayerID) {
+	if playerID != r.room.Host {
+		return
+	}
+
+	sender := r.players[playerID]
+	if sender == nil {
+		return
+	}
+
+	bans := make([]*protocol.BannedPlayer, len(r.bans))
+	for i, b := range r.bans {
+		bans[i] = &protocol.BannedPlayer{PlayerID: b.playerID, Nickname: b.nickname}
+	}
+	sender(priorityTargeted, protocol.NewBansNote(bans))
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) clearBans() {
+	r.bans = nil
+	r.banCount.Store(0)
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func (c *testClient) bans() []*protocol.Bans {
+	var bans []*protocol.Bans
+	for _, n := range c.notes {
+		if n.Method == "bans" {
+			bans = append(bans, n.Params.(*protocol.Bans))
+		}
+	}
+	return bans
+}
+
+func TestBan(t *testing.T) {
+	r := newTestRoom(t)
+	bobIP := net.ParseIP("192.0.2.1")
+
+	alice := dialTestRoom(t, r, ConnOptions{Nickname: "alice", IP: net.ParseIP("192.0.2.2")})
+	readState(t, alice)
+	bob := dialTestRoom(t, r, ConnOptions{Nickname: "bob", IP: bobIP})
+	readState(t, bob)
+	state := readState(t, alice)
+
+	target, _ := findStatePlayer(state, "bob")
+	writeNote(t, alice, protocol.BanMethod, state.RoomState.Version, &protocol.BanParams{PlayerID: target.PlayerID})
+
+	code, hint := readCloseHint(t, bob)
+	assert.Equal(t, code, closeBanned)
+	assert.DeepEqual(t, hint, protocol.CloseHint{Reason: "banned by the host"})
+
+	var bans protocol.Bans
+	readNote(t, alice, "bans", &bans)
+	assert.DeepEqual(t, bans.Bans, []*protocol.BannedPlayer{{PlayerID: target.PlayerID, Nickname: "bob"}})
+
+	state = readState(t, alice)
+	p, _ := findStatePlayer(state, "bob")
+	assert.Assert(t, p == nil)
+
+	entry := findAudit(r.AuditLog(), auditBan)
+	assert.Assert(t, entry != nil)
+	assert.Equal(t, entry.Target, "bob")
+
+	assert.Equal(t, r.stats().Bans, 1)
+
+	// Reconnecting, under any name, doesn't get around it.
+	bob = dialTestRoom(t, r, ConnOptions{Nickname: "robert", IP: bobIP})
+	var e protocol.Error
+	readNote(t, bob, "error", &e)
+	assert.Equal(t, e.Code, "banned")
+	assert.Equal(t, readCloseError(t, bob).Code, closeBanned)
+
+	// Until the host lifts it.
+	writeNote(t, alice, protocol.UnbanMethod, state.RoomState.Version, &protocol.UnbanParams{PlayerID: target.PlayerID})
+	readNote(t, alice, "bans", &bans)
+	assert.Equal(t, len(bans.Bans), 0)
+	assert.Assert(t, findAudit(r.AuditLog(), auditUnban) != nil)
+
+	bob = dialTestRoom(t, r, ConnOptions{Nickname: "bob", IP: bobIP})
+	p, _ = findStatePlayer(readState(t, bob), "bob")
+	assert.Assert(t, p != nil)
+}
+
+func TestBanHostOnly(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+	other := r.addTestClient(t, "other", 1, false)
+
+	r.testNote(t, "other", protocol.BanMethod, &protocol.BanParams{PlayerID: "host"})
+	r.testNote(t, "host", protocol.BanMethod, &protocol.BanParams{PlayerID: "host"})
+	r.testNote(t, "host", protocol.BanMethod, &protocol.BanParams{PlayerID: "missing"})
+	r.testNote(t, "other", protocol.GetBansMethod, &protocol.GetBansParams{})
+	assert.Equal(t, len(other.bans()), 0)
+
+	r.testNote(t, "host", protocol.BanMethod, &protocol.BanParams{PlayerID: "other"})
+	r.addTestClient(t, "third", 1, false)
+	r.testNote(t, "third", protocol.UnbanMethod, &protocol.UnbanParams{PlayerID: "other"})
+
+	r.testNote(t, "host", protocol.GetBansMethod, &protocol.GetBansParams{})
+	bans := host.bans()
+	assert.Equal(t, len(bans), 2)
+	assert.Equal(t, len(bans[1].Bans), 1)
+
+	r.hold(func() {
+		assert.Equal(t, len(r.room.Players), 2)
+		assert.Equal(t, len(r.bans), 1)
+	})
+}
+
+func TestBanLimit(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+	r.addTestClient(t, "other", 1, false)
+
+	r.hold(func() {
+		for i := 0; i < maxBans; i++ {
+			r.bans = append(r.bans, &ban{playerID: strconv.Itoa(i)})
+		}
+	})
+
+	r.testNote(t, "host", protocol.BanMethod, &protocol.BanParams{PlayerID: "other"})
+	errs := host.errors()
+	assert.Equal(t, len(errs), 1)
+	assert.Equal(t, errs[0].Code, "banLimit")
+	assert.Equal(t, *errs[0].Limit, maxBans)
+
+	r.hold(func() {
+		assert.Equal(t, len(r.room.Players), 2)
+	})
+}
+
+func TestBansClearedOnPrune(t *testing.T) {
+	s := newTestServer(t, nil)
+	r, err := s.CreateRoom(context.Background(), "room", "pass")
+	assert.NilError(t, err)
+	r.addTestClient(t, "host", 0, false)
+	r.addTestClient(t, "other", 1, false)
+	r.testNote(t, "host", protocol.BanMethod, &protocol.BanParams{PlayerID: "other"})
+	assert.Equal(t, r.stats().Bans, 1)
+
+	s.mu.Lock()
+	s.removeRoom(r, CloseAdminDeleted)
+	s.mu.Unlock()
+
+	assert.Equal(t, r.stats().Bans, 0)
+	r.hold(func() {
+		assert.Equal(t, len(r.bans), 0)
+	})
+}
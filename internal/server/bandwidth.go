@@ -0,0 +1,106 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+
+	"go.uber.org/atomic"
+	"nhooyr.io/websocket"
+)
+
+// ByteCounter counts the bytes sent and received on the wire for a
+// connection, after compression and framing.
+type ByteCounter struct {
+	sent     atomic.Int64
+	received atomic.Int64
+}
+
+// Sent returns the number of bytes written to the connection.
+func (b *ByteCounter) Sent() int64 {
+	if b == nil {
+		return 0
+	}
+	return b.sent.Load()
+}
+
+// Received returns the number of bytes read from the connection.
+func (b *ByteCounter) Received() int64 {
+	if b == nil {
+		return 0
+	}
+	return b.received.Load()
+}
+
+// Accept accepts a WebSocket connection like websocket.Accept, but counts the
+// bytes which pass through the underlying network connection.
+func Accept(w http.ResponseWriter, r *http.Request, opts *websocket.AcceptOptions) (*websocket.Conn, *ByteCounter, error) {
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		c, err := websocket.Accept(w, r, opts)
+		return c, nil, err
+	}
+
+	counter := &ByteCounter{}
+	c, err := websocket.Accept(&countingResponseWriter{ResponseWriter: w, hj: hj, counter: counter}, r, opts)
+	return c, counter, err
+}
+
+type countingResponseWriter struct {
+	http.ResponseWriter
+	hj      http.Hijacker
+	counter *ByteCounter
+}
+
+var _ http.Hijacker = (*countingResponseWriter)(nil)
+
+func (w *countingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, brw, err := w.hj.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Anything still buffered is the handshake; send it before counting starts.
+	if err := brw.Writer.Flush(); err != nil {
+		_ = conn.Close()
+		return nil, nil, err
+	}
+
+	cc := &countingConn{Conn: conn, counter: w.counter}
+
+	// Carry over any already buffered input. Peeking pulls it into the new
+	// reader's buffer without reading from the connection itself.
+	buffered, _ := brw.Reader.Peek(brw.Reader.Buffered())
+	buffered = append([]byte(nil), buffered...)
+	br := bufio.NewReader(io.MultiReader(bytes.NewReader(buffered), cc))
+	if len(buffered) > 0 {
+		if _, err := br.Peek(len(buffered)); err != nil && !errors.Is(err, io.EOF) {
+			_ = conn.Close()
+			return nil, nil, err
+		}
+	}
+
+	return cc, bufio.NewReadWriter(br, bufio.NewWriter(cc)), nil
+}
+
+type countingConn struct {
+	net.Conn
+	counter *ByteCounter
+}
+
+func (c *countingConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.counter.received.Add(int64(n))
+	metricReceivedBytes.Add(float64(n))
+	return n, err
+}
+
+func (c *countingConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	c.counter.sent.Add(int64(n))
+	metricSentBytes.Add(float64(n))
+	return n, err
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"time"
+)
+
+// MaxCreateWait is the longest CreateRoomWait waits for capacity.
+const MaxCreateWait = 5 * time.Second
+
+// CreateRoomWait is like CreateRoom, but if the server is full, it waits up to
+// wait (capped to MaxCreateWait) for a room to be removed. Waiters are served
+// in the order they arrived; a freed slot is reserved for the oldest waiter
+// so that neither later waiters nor new creations can take it.
+func (s *Server) CreateRoomWait(ctx context.Context, name, password string, opts RoomOptions, wait time.Duration) (*Room, error) {
+	<-s.ready
+
+	if wait > MaxCreateWait {
+		wait = MaxCreateWait
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	room, err := s.createRoom(ctx, name, hash, opts)
+	if err != ErrTooManyRooms || wait <= 0 {
+		s.mu.Unlock()
+		return room, err
+	}
+
+	ready := make(chan struct{})
+	s.waiters = append(s.waiters, ready)
+	s.mu.Unlock()
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ready:
+	case <-timer.C:
+		s.giveUp(ready)
+		return nil, ErrTooManyRooms
+	case <-ctx.Done():
+		s.giveUp(ready)
+		return nil, ctx.Err()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reserved--
+	room, err = s.createRoom(ctx, name, hash, opts)
+	if err != nil {
+		// The slot wasn't used; pass it on.
+		s.capacityFreed()
+	}
+	return room, err
+}
+
+// giveUp removes a waiter. If it was handed a slot in the meantime, the slot
+// is passed on.
+func (s *Server) giveUp(ready chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, w := range s.waiters {
+		if w == ready {
+			s.waiters = append(s.waiters[:i], s.waiters[i+1:]...)
+			return
+		}
+	}
+
+	s.reserved--
+	s.capacityFreed()
+}
+
+// Must be called with s.mu locked.
+func (s *Server) full() bool {
+	return len(s.rooms)+s.reserved >= s.maxRooms
+}
+
+// capacityFreed hands any free slots to the oldest waiters.
+//
+// Must be called with s.mu locked.
+func (s *Server) capacityFreed() {
+	for len(s.waiters) > 0 && !s.full() {
+		ready := s.waiters[0]
+		s.waiters[0] = nil
+		s.waiters = s.waiters[1:]
+		s.reserved++
+		close(ready)
+	}
+}
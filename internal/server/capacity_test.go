@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func newFullTestServer(t *testing.T, max int) (*Server, []*Room) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	s := NewServer(Options{MaxRooms: max})
+	go s.Run(ctx) //nolint:errcheck
+
+	rooms := make([]*Room, max)
+	for i := range rooms {
+		room, err := s.CreateRoom(ctx, fmt.Sprintf("full%d", i), "pass")
+		assert.NilError(t, err)
+		rooms[i] = room
+	}
+
+	_, err := s.CreateRoom(ctx, "extra", "pass")
+	assert.Equal(t, err, ErrTooManyRooms)
+
+	return s, rooms
+}
+
+type createResult struct {
+	room *Room
+	err  error
+}
+
+// goCreateWait starts a waiting creation and returns once it's queued.
+func goCreateWait(t *testing.T, s *Server, name string, wait time.Duration) <-chan createResult {
+	t.Helper()
+
+	s.mu.Lock()
+	before := len(s.waiters)
+	s.mu.Unlock()
+
+	results := make(chan createResult, 1)
+	go func() {
+		room, err := s.CreateRoomWait(context.Background(), name, "pass", RoomOptions{}, wait)
+		results <- createResult{room, err}
+	}()
+
+	for {
+		s.mu.Lock()
+		queued := len(s.waiters) > before
+		s.mu.Unlock()
+		if queued {
+			return results
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCreateRoomWaitFreedDuringWindow(t *testing.T) {
+	s, rooms := newFullTestServer(t, 1)
+
+	results := goCreateWait(t, s, "waiter", time.Minute)
+	assert.Assert(t, s.DeleteRoom(context.Background(), rooms[0].ID))
+
+	res := <-results
+	assert.NilError(t, res.err)
+	assert.Equal(t, res.room.Name, "waiter")
+
+	s.mu.Lock()
+	assert.Equal(t, s.reserved, 0)
+	s.mu.Unlock()
+}
+
+func TestCreateRoomWaitFreedAfterWindow(t *testing.T) {
+	s, rooms := newFullTestServer(t, 1)
+
+	results := goCreateWait(t, s, "waiter", 10*time.Millisecond)
+	res := <-results
+	assert.Equal(t, res.err, ErrTooManyRooms)
+
+	// The waiter left; nothing is held back for it.
+	assert.Assert(t, s.DeleteRoom(context.Background(), rooms[0].ID))
+	_, err := s.CreateRoom(context.Background(), "later", "pass")
+	assert.NilError(t, err)
+}
+
+func TestCreateRoomWaitNoWait(t *testing.T) {
+	s, _ := newFullTestServer(t, 1)
+
+	_, err := s.CreateRoomWait(context.Background(), "waiter", "pass", RoomOptions{}, 0)
+	assert.Equal(t, err, ErrTooManyRooms)
+}
+
+func TestCreateRoomWaitFIFO(t *testing.T) {
+	s, rooms := newFullTestServer(t, 2)
+
+	first := goCreateWait(t, s, "first", time.Minute)
+	second := goCreateWait(t, s, "second", time.Minute)
+
+	assert.Assert(t, s.DeleteRoom(context.Background(), rooms[0].ID))
+
+	// The freed slot belongs to the first waiter, even before it's used.
+	_, err := s.CreateRoom(context.Background(), "thief", "pass")
+	assert.Equal(t, err, ErrTooManyRooms)
+
+	res := <-first
+	assert.NilError(t, res.err)
+
+	select {
+	case <-second:
+		t.Fatal("second waiter should still be waiting")
+	default:
+	}
+
+	assert.Assert(t, s.DeleteRoom(context.Background(), rooms[1].ID))
+	res = <-second
+	assert.NilError(t, res.err)
+}
+
+func TestCreateRoomWaitExistsPassesSlot(t *testing.T) {
+	s, rooms := newFullTestServer(t, 2)
+
+	first := goCreateWait(t, s, "same", time.Minute)
+	second := goCreateWait(t, s, "same", time.Minute)
+	third := goCreateWait(t, s, "third", time.Minute)
+
+	assert.Assert(t, s.DeleteRoom(context.Background(), rooms[0].ID))
+	res := <-first
+	assert.NilError(t, res.err)
+
+	// The second waiter can't use its slot, so the third gets it.
+	assert.Assert(t, s.DeleteRoom(context.Background(), rooms[1].ID))
+	res = <-second
+	assert.Equal(t, res.err, ErrRoomExists)
+
+	res = <-third
+	assert.NilError(t, res.err)
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+)
+
+// ErrUnknownLanguage is returned when creating a room in a language no
+// built-in pack is in.
+var ErrUnknownLanguage = &game.Error{
+	Code:    "unknownLanguage",
+	Message: "No built-in packs are in that language.",
+}
+
+// Packs lists the built-in packs, in the order new rooms list them.
+func (s *Server) Packs() []*protocol.BuiltinPack {
+	builtin := s.packs.Packs()
+
+	packs := make([]*protocol.BuiltinPack, len(builtin))
+	for i, p := range builtin {
+		packs[i] = &protocol.BuiltinPack{
+			Name:     p.Name,
+			Language: p.Language,
+			Count:    p.List.Len(),
+		}
+	}
+	return packs
+}
+
+// useLanguage plays with the built-in packs in the language alone. The others
+// are kept, disabled, so they may be selected later.
+//
+// Must be called before the room is shared.
+func (r *Room) useLanguage(language string) error {
+	found := false
+	for _, list := range r.room.WordLists {
+		if list.Language == language {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return ErrUnknownLanguage
+	}
+
+	for _, list := range r.room.WordLists {
+		list.Enabled = list.Language == language
+	}
+	r.room.Language = language
+	return nil
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+)
+
+// maxChatHistory is the number of chat messages a room keeps to send players
+// as they join. Nothing older is kept anywhere.
+const maxChatHistory = 50
+
+// Each connection may send chatBurst messages at once, then one every
+// chatInterval. Messages past that are dropped, and the sender told so.
+const (
+	chatBurst    = 5
+	chatInterval = time.Second / 3
+)
+
+var errChatEmpty = &game.Error{
+	Code:    "invalidChat",
+	Message: "Chat messages can't be empty.",
+}
+
+// handleChat handles a chat command. It's acked like any other command.
+//
+// Must be called on the room's goroutine.
+func (r *Room) handleChat(playerID game.PlayerID, note *protocol.ClientNote) error {
+	var params protocol.ChatParams
+	if err := json.Unmarshal(note.Params, &params); err != nil {
+		return err
+	}
+
+	violation := r.chat(playerID, params.Text)
+	if violation != nil {
+		if err := r.sendError(playerID, violation); err != nil {
+			return err
+		}
+	}
+	r.sendAck(playerID, note.ID, violation)
+	return nil
+}
+
+// chat sends a message to everyone in the room, and keeps it for those who
+// join later. Messages are broadcast, so a connection which has fallen behind
+// may miss some.
+//
+// Must be called on the room's goroutine.
+func (r *Room) chat(playerID game.PlayerID, text string) *game.Error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return errChatEmpty
+	}
+
+	if max := protocol.MaxChatLength; utf8.RuneCountInString(text) > max {
+		return &game.Error{
+			Code:    "chatTooLong",
+			Message: fmt.Sprintf("Chat messages may be at most %d characters.", max),
+			Limit:   &max,
+		}
+	}
+
+	now := r.clock.Now()
+
+	bucket := r.chatLimits[playerID]
+	if bucket == nil {
+		bucket = &tokenBucket{}
+		r.chatLimits[playerID] = bucket
+	}
+	if retryAfter, ok := bucket.take(now, chatBurst, chatInterval); !ok {
+		metricChatsThrottled.Inc()
+		seconds := int(math.Ceil(retryAfter.Seconds()))
+		return &game.Error{
+			Code:       "tooManyChats",
+			Message:    "You're sending messages too quickly.",
+			RetryAfter: &seconds,
+		}
+	}
+
+	msg := &protocol.ChatMessage{
+		PlayerID: playerID,
+		Text:     text,
+		Time:     now,
+	}
+	if p := r.room.Players[playerID]; p != nil {
+		msg.Nickname = p.Nickname
+	}
+
+	if len(r.chatLog) >= maxChatHistory {
+		r.chatLog = append(r.chatLog[:0:0], r.chatLog[len(r.chatLog)-maxChatHistory+1:]...)
+	}
+	r.chatLog = append(r.chatLog, msg)
+
+	note := protocol.NewChatNote([]*protocol.ChatMessage{msg})
+	for _, sender := range r.players {
+		sender(priorityBroadcast, note)
+	}
+	return nil
+}
+
+// sendChatHistory sends a player who's just joined the room's recent chat.
+//
+// Must be called on the room's goroutine.
+func (r *Room) sendChatHistory(playerID game.PlayerID) {
+	sender := r.players[playerID]
+	if sender == nil || len(r.chatLog) == 0 {
+		return
+	}
+
+	messages := append([]*protocol.ChatMessage(nil), r.chatLog...)
+	sender(priorityTargeted, protocol.NewChatNote(messages))
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func (c *testClient) chats() []*protocol.ChatMessage {
+	var messages []*protocol.ChatMessage
+	for _, n := range c.notes {
+		if n.Method == "chat" {
+			messages = append(messages, n.Params.(*protocol.Chat).Messages...)
+		}
+	}
+	return messages
+}
+
+func TestChat(t *testing.T) {
+	r := newTestRoom(t)
+	clock := newFakeClock()
+	r.setClock(clock)
+	spy := r.addTestClient(t, "spy", 0, true)
+	guesser := r.addTestClient(t, "guesser", 1, false)
+	version := r.version()
+
+	// Spymasters' chat is seen by everyone, and needn't be at the room's version.
+	r.testCommand(t, "spy", "1", version-1, protocol.ChatMethod, &protocol.ChatParams{Text: "  good luck!  "})
+
+	want := []*protocol.ChatMessage{{PlayerID: "spy", Nickname: "spy", Text: "good luck!", Time: clock.Now()}}
+	assert.DeepEqual(t, spy.chats(), want)
+	assert.DeepEqual(t, guesser.chats(), want)
+	assert.DeepEqual(t, spy.acks(), []*protocol.Ack{{ID: "1", OK: true, Version: version}})
+
+	// It's not a change to the room.
+	assert.Equal(t, r.version(), version)
+	assert.Assert(t, spy.lastState() == nil)
+}
+
+func TestChatInvalid(t *testing.T) {
+	r := newTestRoom(t)
+	c := r.addTestClient(t, "p1", 0, false)
+
+	r.testNote(t, "p1", protocol.ChatMethod, &protocol.ChatParams{Text: " \n "})
+	r.testNote(t, "p1", protocol.ChatMethod, &protocol.ChatParams{Text: strings.Repeat("é", protocol.MaxChatLength+1)})
+	r.testNote(t, "p1", protocol.ChatMethod, &protocol.ChatParams{Text: strings.Repeat("é", protocol.MaxChatLength)})
+
+	errs := c.errors()
+	assert.Equal(t, len(errs), 2)
+	assert.Equal(t, errs[0].Code, "invalidChat")
+	assert.Equal(t, errs[1].Code, "chatTooLong")
+	assert.Equal(t, *errs[1].Limit, protocol.MaxChatLength)
+	assert.Equal(t, len(c.chats()), 1)
+}
+
+func TestChatFlood(t *testing.T) {
+	r := newTestRoom(t)
+	clock := newFakeClock()
+	r.setClock(clock)
+	flooder := r.addTestClient(t, "flooder", 0, false)
+	other := r.addTestClient(t, "other", 1, false)
+
+	throttled := testutil.ToFloat64(metricChatsThrottled)
+
+	for i := 0; i < chatBurst+2; i++ {
+		r.testNote(t, "flooder", protocol.ChatMethod, &protocol.ChatParams{Text: fmt.Sprint("spam ", i)})
+	}
+
+	// The excess is dropped, and the sender told, without being disconnected.
+	assert.Equal(t, len(other.chats()), chatBurst)
+	errs := flooder.errors()
+	assert.Equal(t, len(errs), 2)
+	assert.Equal(t, errs[0].Code, "tooManyChats")
+	assert.Equal(t, *errs[0].RetryAfter, 1)
+	assert.Equal(t, testutil.ToFloat64(metricChatsThrottled), throttled+2)
+
+	// Others aren't held back by it.
+	r.testNote(t, "other", protocol.ChatMethod, &protocol.ChatParams{Text: "stop that"})
+	assert.Equal(t, len(other.chats()), chatBurst+1)
+
+	clock.Advance(chatInterval)
+	r.testNote(t, "flooder", protocol.ChatMethod, &protocol.ChatParams{Text: "sorry"})
+	assert.Equal(t, len(other.chats()), chatBurst+2)
+}
+
+func TestChatHistory(t *testing.T) {
+	r := newTestRoom(t)
+	clock := newFakeClock()
+	r.setClock(clock)
+	r.addTestClient(t, "p1", 0, false)
+
+	for i := 0; i < maxChatHistory+10; i++ {
+		clock.Advance(time.Second)
+		r.testNote(t, "p1", protocol.ChatMethod, &protocol.ChatParams{Text: fmt.Sprint("message ", i)})
+	}
+
+	// Those who join are sent the most recent messages, oldest first.
+	late := r.joinTestClient(t, "late", false)
+	chats := late.chats()
+	assert.Equal(t, len(chats), maxChatHistory)
+	assert.Equal(t, chats[0].Text, "message 10")
+	assert.Equal(t, chats[maxChatHistory-1].Text, fmt.Sprint("message ", maxChatHistory+9))
+	assert.Equal(t, late.methods(0)[len(late.notes)-1], "chat")
+
+	// An empty room sends nothing.
+	empty := newTestRoom(t)
+	c := empty.joinTestClient(t, "first", false)
+	assert.Assert(t, c.chats() == nil)
+}
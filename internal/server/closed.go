@@ -0,0 +1,195 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"nhooyr.io/websocket"
+)
+
+// CloseReason is the reason a room was closed.
+type CloseReason string
+
+const (
+	// CloseExpired means the room went unused for too long.
+	CloseExpired = CloseReason("expired")
+	// CloseUnclaimed means the room was created, but nobody ever joined.
+	CloseUnclaimed = CloseReason("unclaimed")
+	// CloseAdminDeleted means an operator deleted the room.
+	CloseAdminDeleted = CloseReason("adminDeleted")
+	// CloseMerged means the room's players were moved into another room.
+	CloseMerged = CloseReason("merged")
+)
+
+// closeRoomClosed closes connections to a room as the server closes it, with
+// the CloseReason as the reason. Clients shouldn't reconnect; the room is gone.
+const closeRoomClosed websocket.StatusCode = 4410
+
+// roomCloseWait is how long a closing room waits on slow clients to take their
+// closes before cancelling its context, which drops them without one.
+const roomCloseWait = 5 * time.Second
+
+// shut closes a room's connections, refusing any which arrive later. The
+// server shuts a room as it removes it, while the room can still be found, so
+// a connection either joins first and is closed with the room, or finds it
+// shut and never joins. Each connection still leaves on its own as it winds
+// down.
+//
+// The room's context is only cancelled once the closes have been written, as
+// cancelling it stops the connections' reads, which drops them outright.
+//
+// Must be called on the room's goroutine.
+func (r *Room) shut(reason CloseReason) {
+	r.closeReason = reason
+
+	var writers []*connWriter
+	for _, w := range r.conns {
+		writers = append(writers, w)
+	}
+	for _, m := range r.mirrors {
+		writers = append(writers, m.w)
+	}
+	for _, m := range r.spectators {
+		writers = append(writers, m.w)
+	}
+
+	for _, w := range writers {
+		w.close(closeRoomClosed, string(reason))
+	}
+
+	go func() {
+		defer r.cancel()
+
+		timeout := time.NewTimer(roomCloseWait)
+		defer timeout.Stop()
+
+		for _, w := range writers {
+			select {
+			case <-w.done:
+			case <-timeout.C:
+				return
+			}
+		}
+	}()
+}
+
+// closing reports whether the room has been shut, or its context is done, as
+// it is once the server stops. Nothing new joins a closing room.
+//
+// Must be called on the room's goroutine.
+func (r *Room) closing() bool {
+	return r.closeReason != "" || r.ctx.Err() != nil
+}
+
+// refuseClosed turns away a connection which arrived after the room closed.
+// A room shut by the server says why; one whose context ended with the
+// server's own only says goodbye.
+func (r *Room) refuseClosed(ctx context.Context, w *connWriter, reason CloseReason) {
+	if reason != "" {
+		w.close(closeRoomClosed, string(reason))
+	}
+	_ = w.run(ctx)
+}
+
+const (
+	maxClosedRooms            = 500
+	defaultClosedRoomsWindow  = time.Hour
+	closedRoomHashPrefixBytes = 8
+)
+
+// ClosedRoom is a record of a recently closed room. Nothing identifying is
+// kept; the room ID is hashed.
+type ClosedRoom struct {
+	IDHash string      `json:"idHash"`
+	Reason CloseReason `json:"reason"`
+	Closed time.Time   `json:"closed"`
+}
+
+// closedRooms is a fixed size ring of recently closed rooms.
+type closedRooms struct {
+	window time.Duration
+	salt   []byte // If set, IDs are hashed with it; see Options.PrivacyStrict.
+
+	mu      sync.Mutex
+	entries []ClosedRoom
+	next    int
+}
+
+func newClosedRooms(window time.Duration) *closedRooms {
+	if window <= 0 {
+		window = defaultClosedRoomsWindow
+	}
+
+	return &closedRooms{
+		window:  window,
+		entries: make([]ClosedRoom, 0, maxClosedRooms),
+	}
+}
+
+func hashRoomID(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:closedRoomHashPrefixBytes])
+}
+
+func (c *closedRooms) hash(id string) string {
+	if c.salt != nil {
+		return saltedHash(c.salt, id)
+	}
+	return hashRoomID(id)
+}
+
+func (c *closedRooms) add(id string, reason CloseReason, now time.Time) {
+	entry := ClosedRoom{
+		IDHash: c.hash(id),
+		Reason: reason,
+		Closed: now,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) < maxClosedRooms {
+		c.entries = append(c.entries, entry)
+		return
+	}
+
+	c.entries[c.next] = entry
+	c.next = (c.next + 1) % maxClosedRooms
+}
+
+func (c *closedRooms) find(id string, now time.Time) (ClosedRoom, bool) {
+	hash := c.hash(id)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, e := range c.entries {
+		if e.IDHash == hash && now.Sub(e.Closed) <= c.window {
+			return e, true
+		}
+	}
+
+	return ClosedRoom{}, false
+}
+
+// list returns the unexpired entries, newest first.
+func (c *closedRooms) list(now time.Time) []ClosedRoom {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	list := make([]ClosedRoom, 0, len(c.entries))
+	n := len(c.entries)
+
+	for i := 0; i < n; i++ {
+		// Walk backwards from the most recently written entry.
+		e := c.entries[(c.next-1-i+2*n)%n]
+		if now.Sub(e.Closed) <= c.window {
+			list = append(list, e)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestClosedReasons(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t, nil)
+
+	unclaimed, err := s.CreateRoom(ctx, "unclaimed", "pass")
+	assert.NilError(t, err)
+	expired, err := s.CreateRoom(ctx, "expired", "pass")
+	assert.NilError(t, err)
+	deleted, err := s.CreateRoom(ctx, "deleted", "pass")
+	assert.NilError(t, err)
+	live, err := s.CreateRoom(ctx, "live", "pass")
+	assert.NilError(t, err)
+
+	expired.claimed.Store(true)
+	old := time.Now().Add(-time.Hour)
+	unclaimed.lastSeen.Store(old)
+	expired.lastSeen.Store(old)
+
+	s.prune(ctx)
+	assert.Assert(t, s.DeleteRoom(ctx, deleted.ID))
+	assert.Assert(t, !s.DeleteRoom(ctx, deleted.ID))
+
+	tests := map[string]CloseReason{
+		unclaimed.ID: CloseUnclaimed,
+		expired.ID:   CloseExpired,
+		deleted.ID:   CloseAdminDeleted,
+	}
+
+	for id, want := range tests {
+		assert.Assert(t, s.FindRoomByID(id) == nil)
+		closed, ok := s.RecentlyClosed(id)
+		assert.Assert(t, ok)
+		assert.Equal(t, closed.Reason, want)
+		assert.Equal(t, closed.IDHash, hashRoomID(id))
+	}
+
+	_, ok := s.RecentlyClosed(live.ID)
+	assert.Assert(t, !ok)
+	_, ok = s.RecentlyClosed("never existed")
+	assert.Assert(t, !ok)
+
+	assert.Equal(t, len(s.ClosedRooms()), 3)
+	assert.Equal(t, s.ClosedRooms()[0].Reason, CloseAdminDeleted)
+}
+
+func TestClosedRoomsRing(t *testing.T) {
+	c := newClosedRooms(time.Hour)
+	now := time.Now()
+
+	for i := 0; i < maxClosedRooms+10; i++ {
+		c.add(strconv.Itoa(i), CloseExpired, now)
+	}
+
+	_, ok := c.find("0", now)
+	assert.Assert(t, !ok)
+	_, ok = c.find("10", now)
+	assert.Assert(t, ok)
+
+	list := c.list(now)
+	assert.Equal(t, len(list), maxClosedRooms)
+	assert.Equal(t, list[0].IDHash, hashRoomID(strconv.Itoa(maxClosedRooms+9)))
+	assert.Equal(t, list[len(list)-1].IDHash, hashRoomID("10"))
+}
+
+func TestClosedRoomsWindow(t *testing.T) {
+	c := newClosedRooms(time.Minute)
+	now := time.Now()
+
+	c.add("old", CloseExpired, now.Add(-2*time.Minute))
+	c.add("new", CloseExpired, now)
+
+	_, ok := c.find("old", now)
+	assert.Assert(t, !ok)
+	_, ok = c.find("new", now)
+	assert.Assert(t, ok)
+	assert.Equal(t, len(c.list(now)), 1)
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"time"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"nhooyr.io/websocket"
+)
+
+// Every close the server sends has a protocol.CloseHint as its reason, so
+// clients needn't guess from the code whether to reconnect. closeRetries lists
+// the closes a client may recover from, and how long it should wait first;
+// any other close, like a kick, a ban, or a room closing, says not to retry.
+var closeRetries = map[websocket.StatusCode]time.Duration{
+	websocket.StatusGoingAway:     2 * time.Second,     // The server is shutting down.
+	websocket.StatusInternalError: time.Second,         // A write failed.
+	closeTooSlow:                  time.Second,         // The client fell too far behind.
+	closeRoomBusy:                 defaultJoinInterval, // Until the room admits another join; see rejectConn.
+	closeRoomMerged:               0,                   // Straight to the room the hint names.
+	closeCycled:                   time.Second,         // Under memory pressure; see pressure.go.
+	closeUnresponsive:             time.Second,         // Pings went unanswered; see keepalive.
+}
+
+func newCloseHint(code websocket.StatusCode, reason string) protocol.CloseHint {
+	after, retry := closeRetries[code]
+	return protocol.CloseHint{
+		Retry:   retry,
+		AfterMS: int(after / time.Millisecond),
+		Reason:  reason,
+	}
+}
+
+// mergedHint sends a client on to the room its room was merged into.
+func mergedHint(target string) protocol.CloseHint {
+	hint := newCloseHint(closeRoomMerged, "room merged")
+	hint.RoomID = target
+	return hint
+}
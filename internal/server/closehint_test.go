@@ -0,0 +1,192 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+	"nhooyr.io/websocket"
+)
+
+// readCloseHint reads until the connection is closed, returning the close's
+// code and hint.
+func readCloseHint(t *testing.T, c *websocket.Conn) (websocket.StatusCode, protocol.CloseHint) {
+	t.Helper()
+
+	closeErr := readCloseError(t, c)
+
+	var hint protocol.CloseHint
+	assert.NilError(t, json.Unmarshal([]byte(closeErr.Reason), &hint), closeErr.Reason)
+	return closeErr.Code, hint
+}
+
+func TestCloseHintRetries(t *testing.T) {
+	tests := []struct {
+		code websocket.StatusCode
+		want string
+	}{
+		{websocket.StatusGoingAway, `{"retry":true,"afterMs":2000,"reason":"why"}`},
+		{closeTooSlow, `{"retry":true,"afterMs":1000,"reason":"why"}`},
+		{websocket.StatusInternalError, `{"retry":true,"afterMs":1000,"reason":"why"}`},
+		{closeRoomBusy, `{"retry":true,"afterMs":200,"reason":"why"}`},
+		{closeRoomMerged, `{"retry":true,"reason":"why"}`},
+		{closeCycled, `{"retry":true,"afterMs":1000,"reason":"why"}`},
+		{closeRoomClosed, `{"retry":false,"reason":"why"}`},
+		{closeKicked, `{"retry":false,"reason":"why"}`},
+		{closeReplaced, `{"retry":false,"reason":"why"}`},
+		{closeBanned, `{"retry":false,"reason":"why"}`},
+		{closeMirrorRejected, `{"retry":false,"reason":"why"}`},
+		{closeNicknameTaken, `{"retry":false,"reason":"why"}`},
+		{websocket.StatusInvalidFramePayloadData, `{"retry":false,"reason":"why"}`},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, newCloseHint(test.code, "why").Encode(), test.want, test.code)
+	}
+
+	assert.Equal(t, mergedHint("abcdefghijklmnop").Encode(), `{"retry":true,"roomId":"abcdefghijklmnop","reason":"room merged"}`)
+}
+
+func TestCloseHints(t *testing.T) {
+	tests := []struct {
+		name string
+		dial func(t *testing.T, r *Room) *websocket.Conn
+		code websocket.StatusCode
+		want protocol.CloseHint
+	}{
+		{
+			name: "room closed",
+			dial: func(t *testing.T, r *Room) *websocket.Conn {
+				c := dialTestRoom(t, r, ConnOptions{Nickname: "alice"})
+				readState(t, c)
+				r.hold(func() {
+					r.shut(CloseExpired)
+				})
+				return c
+			},
+			code: closeRoomClosed,
+			want: protocol.CloseHint{Reason: string(CloseExpired)},
+		},
+		{
+			name: "joined closed room",
+			dial: func(t *testing.T, r *Room) *websocket.Conn {
+				r.hold(func() {
+					r.shut(CloseUnclaimed)
+				})
+				return dialTestRoom(t, r, ConnOptions{Nickname: "alice"})
+			},
+			code: closeRoomClosed,
+			want: protocol.CloseHint{Reason: string(CloseUnclaimed)},
+		},
+		{
+			name: "merged",
+			dial: func(t *testing.T, r *Room) *websocket.Conn {
+				r.hold(func() {
+					r.mergedInto = "elsewhere"
+				})
+				return dialTestRoom(t, r, ConnOptions{Nickname: "alice"})
+			},
+			code: closeRoomMerged,
+			want: protocol.CloseHint{Retry: true, RoomID: "elsewhere", Reason: "room merged"},
+		},
+		{
+			name: "room busy",
+			dial: func(t *testing.T, r *Room) *websocket.Conn {
+				r.hold(func() {
+					for i := 0; i < defaultJoinBurst; i++ {
+						r.joins.admit(r.clock.Now())
+					}
+				})
+				return dialTestRoom(t, r, ConnOptions{Nickname: "alice"})
+			},
+			code: closeRoomBusy,
+			want: protocol.CloseHint{Retry: true, AfterMS: 1000, Reason: "Too many people are joining this room; try again shortly."},
+		},
+		{
+			name: "nickname refused",
+			dial: func(t *testing.T, r *Room) *websocket.Conn {
+				r.addTestClient(t, "alice", 0, false)
+				return dialTestRoom(t, r, ConnOptions{Nickname: "alice"})
+			},
+			code: closeNicknameTaken,
+			want: protocol.CloseHint{Reason: "Nickname is too similar to the host's."},
+		},
+		{
+			name: "kicked",
+			dial: func(t *testing.T, r *Room) *websocket.Conn {
+				r.addTestClient(t, "host", 0, false)
+				c := dialTestRoom(t, r, ConnOptions{Nickname: "alice"})
+				state := readState(t, c)
+				r.testNote(t, "host", protocol.KickMethod, &protocol.KickParams{PlayerID: state.PlayerID})
+				return c
+			},
+			code: closeKicked,
+			want: protocol.CloseHint{Reason: "kicked by the host"},
+		},
+		{
+			name: "invalid note",
+			dial: func(t *testing.T, r *Room) *websocket.Conn {
+				c := dialTestRoom(t, r, ConnOptions{Nickname: "alice"})
+				readState(t, c)
+
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				assert.NilError(t, c.Write(ctx, websocket.MessageText, []byte("nope")))
+				return c
+			},
+			code: websocket.StatusInvalidFramePayloadData,
+			want: protocol.CloseHint{Reason: errInvalidNote.Error()},
+		},
+		{
+			name: "mirror rejected",
+			dial: func(t *testing.T, r *Room) *websocket.Conn {
+				r.addTestClient(t, "host", 0, false)
+				return dialTestMirror(t, r, "bogus")
+			},
+			code: closeMirrorRejected,
+			want: protocol.CloseHint{Reason: "Invalid mirror token."},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			r := newTestRoom(t)
+			code, hint := readCloseHint(t, test.dial(t, r))
+			assert.Equal(t, code, test.code)
+			assert.DeepEqual(t, hint, test.want)
+		})
+	}
+}
+
+// The writer's own closes are best-effort: a server shutting down cancels the
+// connection's reads as well, which may drop it before the close is written.
+func TestCloseHintsWriter(t *testing.T) {
+	t.Run("shutdown", func(t *testing.T) {
+		w, _, c := dialTestWriter(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go w.run(ctx) //nolint:errcheck
+		cancel()
+
+		code, hint := readCloseHint(t, c)
+		assert.Equal(t, code, websocket.StatusGoingAway)
+		assert.DeepEqual(t, hint, protocol.CloseHint{Retry: true, AfterMS: 2000, Reason: "going away"})
+	})
+
+	t.Run("too slow", func(t *testing.T) {
+		w, start, c := dialTestWriter(t)
+
+		for i := 0; i <= writerQueueSize; i++ {
+			w.send(priorityTargeted, testWriterNote("targeted"))
+		}
+		start()
+
+		code, hint := readCloseHint(t, c)
+		assert.Equal(t, code, closeTooSlow)
+		assert.DeepEqual(t, hint, protocol.CloseHint{Retry: true, AfterMS: 1000, Reason: "too slow"})
+	})
+}
@@ -0,0 +1,312 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"go.uber.org/zap/zapcore"
+	"gotest.tools/v3/assert"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+)
+
+// stressPlay plays a room over a connection, sending a random command after
+// each state, until the connection ends. It can't fail the test, as it runs
+// on its own goroutine; a room closed under it is the point.
+func stressPlay(c *websocket.Conn, seed int64, commands int) {
+	rng := rand.New(rand.NewSource(seed)) //nolint:gosec
+
+	send := func(method protocol.ClientMethod, version int64, params interface{}) error {
+		raw, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return wsjson.Write(ctx, c, &protocol.ClientNote{Method: method, Version: version, Params: raw})
+	}
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		var note struct {
+			Method protocol.ServerMethod `json:"method"`
+			Params json.RawMessage       `json:"params"`
+		}
+		err := wsjson.Read(ctx, c, &note)
+		cancel()
+		if err != nil {
+			return
+		}
+
+		var state protocol.State
+		if note.Method != "state" || commands == 0 || json.Unmarshal(note.Params, &state) != nil {
+			continue
+		}
+		commands--
+
+		version := state.RoomState.Version
+		switch rng.Intn(6) {
+		case 0:
+			err = send(protocol.ChangeTeamMethod, version, &protocol.ChangeTeamParams{Team: game.Team(rng.Intn(2))})
+		case 1:
+			err = send(protocol.ChangeRoleMethod, version, &protocol.ChangeRoleParams{Spymaster: rng.Intn(2) == 0})
+		case 2:
+			err = send(protocol.RevealMethod, version, &protocol.RevealParams{Row: rng.Intn(5), Col: rng.Intn(5)})
+		case 3:
+			err = send(protocol.EndTurnMethod, version, &protocol.EndTurnParams{})
+		case 4:
+			err = send(protocol.ChangeNicknameMethod, version, &protocol.ChangeNicknameParams{Nickname: fmt.Sprintf("n%d", rng.Intn(1000))})
+		case 5:
+			err = send(protocol.NewGameMethod, version, &protocol.NewGameParams{Force: true})
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// TestRoomConcurrency mixes everything which reaches into rooms from outside
+// their connections, like admin deletes, audit logs, log levels, and stats,
+// with players joining and playing, and rooms closing under all of it. It
+// checks little itself; it's there for -race.
+func TestRoomConcurrency(t *testing.T) {
+	rounds := 5
+	if testing.Short() {
+		rounds = 2
+	}
+
+	ctx := context.Background()
+	s := newTestServer(t, nil)
+
+	for i := 0; i < rounds; i++ {
+		room, err := s.CreateRoom(ctx, fmt.Sprintf("room%d", i), "pass")
+		assert.NilError(t, err)
+
+		var conns []*websocket.Conn
+		for j := 0; j < 4; j++ {
+			conns = append(conns, dialTestRoom(t, room, ConnOptions{Nickname: fmt.Sprintf("p%d", j), Deltas: j%2 == 0}))
+		}
+		conns = append(conns, dialTestRoom(t, room, ConnOptions{Spectate: true}))
+
+		var wg sync.WaitGroup
+		for j, c := range conns {
+			c := c
+			seed := int64(i*len(conns) + j)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				stressPlay(c, seed, 30)
+			}()
+		}
+
+		// Late joins race the delete below.
+		late := dialTestRoom(t, room, ConnOptions{Nickname: "late"})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stressPlay(late, int64(i), 5)
+		}()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for k := 0; k < 20; k++ {
+				room.AuditLog()
+				room.CheckPassword("pass")
+				room.SetLogLevel(zapcore.DebugLevel, time.Minute)
+				s.RoomStats(room.ID)
+				s.ListRooms()
+				s.refreshStats()
+				room.ClearLogLevel()
+				time.Sleep(time.Millisecond)
+			}
+			s.DeleteRoom(ctx, room.ID)
+		}()
+
+		wg.Wait()
+		assert.Assert(t, s.FindRoomByID(room.ID) == nil)
+
+		// Joining a room which has closed goes nowhere.
+		conn := dialTestRoom(t, room, ConnOptions{Nickname: "after"})
+		code, hint := readCloseHint(t, conn)
+		assert.Equal(t, code, closeRoomClosed)
+		assert.DeepEqual(t, hint, protocol.CloseHint{Reason: string(CloseAdminDeleted)})
+	}
+
+	// Every connection is let go of.
+	until := time.Now().Add(5 * time.Second)
+	for s.counters.clients.Load() != 0 || s.counters.spectators.Load() != 0 {
+		assert.Assert(t, time.Now().Before(until), "connections never ended")
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	s.refreshStats()
+	stats := s.Stats()
+	assert.Equal(t, stats.Rooms, 0)
+	assert.Equal(t, stats.Clients, 0)
+}
+
+// TestRoomChurn creates rooms through the server, joins them, and closes them
+// as the joins arrive, or just after, like pruning does rooms nobody stayed
+// in. Every join
+// either lands in the room before it closes, and is closed with it, or is
+// refused as closed; once it settles, no connection is left counted against
+// a room which is gone.
+func TestRoomChurn(t *testing.T) {
+	rooms := 2000
+	if testing.Short() {
+		rooms = 200
+	}
+
+	ctx := context.Background()
+	s := newTestServer(t, nil)
+
+	// Connections are handed the room they were dialed for even once it's
+	// closed, like a join which found the room just before it went.
+	var all sync.Map
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		room, ok := all.Load(strings.TrimPrefix(r.URL.Path, "/"))
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		c, counter, err := Accept(w, r, testAcceptOptions)
+		if err != nil {
+			return
+		}
+		room.(*Room).HandleConn(r.Context(), ConnOptions{Nickname: "churn", Counter: counter}, c)
+	}))
+	defer hs.Close()
+	url := "ws" + strings.TrimPrefix(hs.URL, "http") + "/"
+
+	const parallel = 50
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+
+	for i := 0; i < rooms; i++ {
+		i := i
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			room, err := s.CreateRoom(ctx, fmt.Sprintf("churn%d", i), "pass")
+			if err != nil {
+				t.Errorf("creating room: %v", err)
+				return
+			}
+			all.Store(room.ID, room)
+
+			reason := CloseExpired
+			if i%2 == 0 {
+				reason = CloseUnclaimed
+			}
+
+			joined := make(chan error, 1)
+			go func() {
+				joined <- churnJoin(url+room.ID, reason)
+			}()
+
+			// Some rooms are closed with their player in, rather than as
+			// they arrive.
+			if i%3 == 0 {
+				until := time.Now().Add(10 * time.Second)
+				for room.clients.Load() == 0 && time.Now().Before(until) {
+					time.Sleep(time.Millisecond)
+				}
+			}
+
+			s.closeRoom(room, reason)
+			if s.FindRoomByID(room.ID) != nil {
+				t.Errorf("room %s found after closing", room.ID)
+			}
+
+			if err := <-joined; err != nil {
+				t.Errorf("room %s: %v", room.ID, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	until := time.Now().Add(10 * time.Second)
+	for s.counters.clients.Load() != 0 || ghostClients(s) != 0 {
+		assert.Assert(t, time.Now().Before(until), "connections never ended: %d ghosts", ghostClients(s))
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	all.Range(func(_, v interface{}) bool {
+		room := v.(*Room)
+		assert.Equal(t, room.clients.Load(), int64(0), "room %s", room.ID)
+
+		room.hold(func() {
+			assert.Equal(t, len(room.players), 0, "room %s", room.ID)
+			assert.Equal(t, len(room.conns), 0, "room %s", room.ID)
+		})
+		return true
+	})
+
+	s.refreshStats()
+	stats := s.Stats()
+	assert.Equal(t, stats.Rooms, 0)
+	assert.Equal(t, stats.Clients, 0)
+}
+
+// churnJoin joins a room which is closing, reading until the connection ends.
+// Whether or not it got in first, it should be closed as the room was.
+func churnJoin(url string, reason CloseReason) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c, _, err := websocket.Dial(ctx, url, nil)
+	if err != nil {
+		return err
+	}
+	defer c.Close(websocket.StatusNormalClosure, "") //nolint:errcheck
+
+	for {
+		var note protocol.ServerNote
+		err := wsjson.Read(ctx, c, &note)
+		if err == nil {
+			continue
+		}
+
+		var closeErr websocket.CloseError
+		if !errors.As(err, &closeErr) {
+			return err
+		}
+		if closeErr.Code != closeRoomClosed || closeErr.Reason != newCloseHint(closeRoomClosed, string(reason)).Encode() {
+			return fmt.Errorf("closed with %d %q", closeErr.Code, closeErr.Reason)
+		}
+		return nil
+	}
+}
+
+// ghostClients reconciles the server's count of clients with the rooms it
+// still has: any client left over is connected to a room which is gone.
+func ghostClients(s *Server) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := s.counters.clients.Load()
+	for _, room := range s.roomIDs {
+		n -= room.clients.Load()
+	}
+	return n
+}
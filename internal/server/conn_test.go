@@ -0,0 +1,159 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+)
+
+var testAcceptOptions = &websocket.AcceptOptions{
+	InsecureSkipVerify: true,
+	CompressionMode:    websocket.CompressionContextTakeover,
+}
+
+// dialTestRoom serves the room over a real WebSocket and connects to it.
+func dialTestRoom(t testing.TB, room *Room, opts ConnOptions) *websocket.Conn {
+	t.Helper()
+
+	return dialTest(t, func(ctx context.Context, c *websocket.Conn, counter *ByteCounter) {
+		opts := opts
+		opts.Counter = counter
+		room.HandleConn(ctx, opts, c)
+	})
+}
+
+// dialTestMirror connects to the room as a mirror.
+func dialTestMirror(t *testing.T, room *Room, token string) *websocket.Conn {
+	t.Helper()
+
+	return dialTest(t, func(ctx context.Context, c *websocket.Conn, _ *ByteCounter) {
+		room.HandleMirrorConn(ctx, token, c)
+	})
+}
+
+func dialTest(t testing.TB, handle func(context.Context, *websocket.Conn, *ByteCounter)) *websocket.Conn {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	done := make(chan struct{})
+
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(done)
+
+		c, counter, err := Accept(w, r, testAcceptOptions)
+		if err != nil {
+			return
+		}
+
+		handle(ctx, c, counter)
+	}))
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		hs.Close()
+	})
+
+	url := "ws" + strings.TrimPrefix(hs.URL, "http")
+	c, _, err := websocket.Dial(ctx, url, &websocket.DialOptions{
+		CompressionMode: websocket.CompressionContextTakeover,
+	})
+	assert.NilError(t, err)
+	t.Cleanup(func() { c.Close(websocket.StatusNormalClosure, "") })
+
+	return c
+}
+
+// readNote reads notes until one with the given method arrives.
+func readNote(t testing.TB, c *websocket.Conn, method protocol.ServerMethod, params interface{}) {
+	t.Helper()
+
+	for {
+		m, raw := readRawNote(t, c)
+		if m == method {
+			if params != nil {
+				assert.NilError(t, json.Unmarshal(raw, params))
+			}
+			return
+		}
+	}
+}
+
+// nextNote reads the next note, decoding its params if non-nil.
+func nextNote(t *testing.T, c *websocket.Conn, params interface{}) protocol.ServerMethod {
+	t.Helper()
+
+	method, raw := readRawNote(t, c)
+	if params != nil {
+		assert.NilError(t, json.Unmarshal(raw, params))
+	}
+	return method
+}
+
+func readRawNote(t testing.TB, c *websocket.Conn) (protocol.ServerMethod, json.RawMessage) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var note struct {
+		Method protocol.ServerMethod `json:"method"`
+		Params json.RawMessage       `json:"params"`
+	}
+	assert.NilError(t, wsjson.Read(ctx, c, &note))
+	return note.Method, note.Params
+}
+
+func writeNote(t *testing.T, c *websocket.Conn, method protocol.ClientMethod, version int64, params interface{}) {
+	t.Helper()
+
+	raw, err := json.Marshal(params)
+	assert.NilError(t, err)
+
+	writeRawNote(t, c, &protocol.ClientNote{
+		Method:  method,
+		Version: version,
+		Params:  raw,
+	})
+}
+
+func writeRawNote(t *testing.T, c *websocket.Conn, note *protocol.ClientNote) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	assert.NilError(t, wsjson.Write(ctx, c, note))
+}
+
+func TestBandwidthCounted(t *testing.T) {
+	room := newTestRoom(t)
+	c := dialTestRoom(t, room, ConnOptions{Nickname: "player"})
+
+	var state protocol.State
+	readNote(t, c, "state", &state)
+
+	writeNote(t, c, protocol.DebugInfoMethod, state.RoomState.Version, &protocol.DebugInfoParams{})
+
+	var info protocol.DebugInfo
+	readNote(t, c, "debugInfo", &info)
+	assert.Equal(t, info.PlayerID, state.PlayerID)
+	assert.Assert(t, info.BytesSent > 0)
+	assert.Assert(t, info.BytesReceived > 0)
+
+	var counter *ByteCounter
+	room.hold(func() {
+		counter = room.bytes[state.PlayerID]
+	})
+	assert.Assert(t, counter.Sent() >= info.BytesSent)
+}
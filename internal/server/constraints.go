@@ -0,0 +1,53 @@
+package server
+
+import (
+	"strconv"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+)
+
+// changeTeamConstraints replaces the room's team constraints. Only the host
+// may change them.
+//
+// Must be called on the room's goroutine.
+func (r *Room) changeTeamConstraints(playerID game.PlayerID, constraints []*protocol.TeamConstraint) error {
+	if playerID != r.room.Host {
+		return nil
+	}
+
+	cs := make([]game.TeamConstraint, 0, len(constraints))
+	for _, c := range constraints {
+		if c == nil {
+			continue
+		}
+		cs = append(cs, game.TeamConstraint{A: c.A, B: c.B, Apart: c.Apart})
+	}
+
+	before := len(r.room.TeamConstraints)
+	version := r.room.Version
+	if err := r.room.ChangeTeamConstraints(cs); err != nil {
+		return err
+	}
+
+	if r.room.Version != version {
+		r.audit(auditTeamConstraints, playerID, "", &protocol.AuditChange{
+			Field:  "teamConstraints",
+			Before: strconv.Itoa(before),
+			After:  strconv.Itoa(len(cs)),
+		})
+	}
+	return nil
+}
+
+func protocolConstraints(cs []game.TeamConstraint) []*protocol.TeamConstraint {
+	if len(cs) == 0 {
+		return nil
+	}
+
+	out := make([]*protocol.TeamConstraint, len(cs))
+	for i, c := range cs {
+		out[i] = &protocol.TeamConstraint{A: c.A, B: c.B, Apart: c.Apart}
+	}
+	return out
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func TestChangeTeamConstraints(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+	r.addTestClient(t, "a", 0, false)
+	other := r.addTestClient(t, "b", 1, false)
+	r.addTestClient(t, "c", 1, false)
+
+	constraints := []*protocol.TeamConstraint{{A: "a", B: "b"}, {A: "host", B: "c", Apart: true}}
+
+	// Only the host may change them.
+	version := r.version()
+	r.testNote(t, "b", protocol.ChangeTeamConstraintsMethod, &protocol.ChangeTeamConstraintsParams{Constraints: constraints})
+	assert.Equal(t, r.version(), version)
+
+	r.testNote(t, "host", protocol.ChangeTeamConstraintsMethod, &protocol.ChangeTeamConstraintsParams{Constraints: constraints})
+	assert.DeepEqual(t, other.lastState().RoomState.TeamConstraints, constraints)
+
+	entry := findAudit(r.AuditLog(), auditTeamConstraints)
+	assert.Assert(t, entry != nil)
+	assert.DeepEqual(t, entry.Changes, []*protocol.AuditChange{{Field: "teamConstraints", Before: "0", After: "2"}})
+
+	for i := 0; i < 20; i++ {
+		r.testNote(t, "b", protocol.RandomizeTeamsMethod, &protocol.RandomizeTeamsParams{})
+		r.hold(func() {
+			players := r.room.Players
+			assert.Equal(t, players["a"].Team, players["b"].Team)
+			assert.Assert(t, players["host"].Team != players["c"].Team)
+		})
+	}
+
+	// They're kept across games.
+	r.testNote(t, "host", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	assert.DeepEqual(t, other.lastState().RoomState.TeamConstraints, constraints)
+
+	r.testNote(t, "host", protocol.ChangeTeamConstraintsMethod, &protocol.ChangeTeamConstraintsParams{})
+	assert.Assert(t, other.lastState().RoomState.TeamConstraints == nil)
+	assert.Equal(t, len(host.errors()), 0)
+}
+
+func TestChangeTeamConstraintsUnsatisfiable(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+	r.addTestClient(t, "a", 0, false)
+	r.addTestClient(t, "b", 1, false)
+
+	constraints := []*protocol.TeamConstraint{
+		{A: "host", B: "a"},
+		{A: "a", B: "b", Apart: true},
+		{A: "b", B: "host"},
+	}
+
+	version := r.version()
+	r.testNote(t, "host", protocol.ChangeTeamConstraintsMethod, &protocol.ChangeTeamConstraintsParams{Constraints: constraints})
+	assert.Equal(t, r.version(), version)
+
+	errs := host.errors()
+	assert.Equal(t, len(errs), 1)
+	assert.Equal(t, errs[0].Code, "unsatisfiableConstraints")
+	assert.DeepEqual(t, errs[0].Conflicts, constraints)
+}
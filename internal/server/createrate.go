@@ -0,0 +1,87 @@
+package server
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// An IP may create createBurst rooms at once, then one every createInterval.
+// That's plenty for anyone hosting games, even a few people behind one NAT,
+// but keeps a single address from filling the server with rooms.
+const (
+	createBurst    = 10
+	createInterval = 30 * time.Second
+
+	// createRefill is how long an emptied bucket takes to fill back up. A
+	// bucket untouched for that long is no different from no bucket at all.
+	createRefill = createBurst * createInterval
+)
+
+// createLimiter limits room creations by client IP.
+type createLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	swept   time.Time
+}
+
+func newCreateLimiter() *createLimiter {
+	return &createLimiter{
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// admit takes a creation for ip, or returns how long until one is available.
+func (l *createLimiter) admit(ip net.IP, now time.Time) (retryAfter time.Duration, ok bool) {
+	key := ip.String()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Full buckets are dropped every so often, so the map only holds IPs
+	// which have created rooms recently.
+	if now.Sub(l.swept) >= createRefill {
+		for k, b := range l.buckets {
+			if now.Sub(b.last) >= createRefill {
+				delete(l.buckets, k)
+			}
+		}
+		l.swept = now
+	}
+
+	b := l.buckets[key]
+	if b == nil {
+		b = &tokenBucket{}
+		l.buckets[key] = b
+	}
+
+	retryAfter, ok = b.take(now, createBurst, createInterval)
+	if !ok {
+		metricCreatesThrottled.Inc()
+	}
+	return retryAfter, ok
+}
+
+// refund gives back a creation admit took for ip which didn't create a room.
+// If ip's bucket has been dropped since, it was full anyway.
+func (l *createLimiter) refund(ip net.IP) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b := l.buckets[ip.String()]; b != nil {
+		b.refund(createBurst)
+	}
+}
+
+// AdmitCreate checks that ip may create another room right now, returning how
+// long until it may if not. If it may, but no room is created after all,
+// RefundCreate gives the creation back.
+func (s *Server) AdmitCreate(ip net.IP) (retryAfter time.Duration, ok bool) {
+	return s.creates.admit(ip, time.Now())
+}
+
+// RefundCreate gives back a creation AdmitCreate allowed ip which didn't
+// create a room, so that failed attempts don't count against it.
+func (s *Server) RefundCreate(ip net.IP) {
+	s.creates.refund(ip)
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCreateLimiter(t *testing.T) {
+	l := newCreateLimiter()
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := net.ParseIP("192.0.2.1")
+	b := net.ParseIP("2001:db8::1")
+
+	for i := 0; i < createBurst; i++ {
+		_, ok := l.admit(a, now)
+		assert.Assert(t, ok, "create %d", i)
+	}
+
+	retryAfter, ok := l.admit(a, now)
+	assert.Assert(t, !ok)
+	assert.Equal(t, retryAfter, createInterval)
+
+	// Other addresses have their own limit.
+	_, ok = l.admit(b, now)
+	assert.Assert(t, ok)
+
+	now = now.Add(createInterval)
+	_, ok = l.admit(a, now)
+	assert.Assert(t, ok)
+	_, ok = l.admit(a, now)
+	assert.Assert(t, !ok)
+}
+
+func TestCreateLimiterEviction(t *testing.T) {
+	l := newCreateLimiter()
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 1000; i++ {
+		_, ok := l.admit(net.IPv4(10, 0, byte(i>>8), byte(i)), now)
+		assert.Assert(t, ok)
+	}
+	assert.Equal(t, len(l.buckets), 1000)
+
+	// An address which created rooms since keeps its bucket; the rest have
+	// refilled and are dropped.
+	busy := net.ParseIP("192.0.2.1")
+	for i := 0; i < createBurst; i++ {
+		l.admit(busy, now.Add(createRefill/2))
+	}
+
+	now = now.Add(createRefill)
+	_, ok := l.admit(busy, now)
+	assert.Assert(t, ok)
+	assert.Equal(t, len(l.buckets), 1)
+
+	// Having kept it, it's only half refilled.
+	for i := 1; i < createBurst/2; i++ {
+		_, ok = l.admit(busy, now)
+		assert.Assert(t, ok)
+	}
+	_, ok = l.admit(busy, now)
+	assert.Assert(t, !ok)
+}
+
+func TestCreateLimiterRefund(t *testing.T) {
+	l := newCreateLimiter()
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := net.ParseIP("192.0.2.1")
+
+	// Creations which were refunded don't count.
+	for i := 0; i < 2*createBurst; i++ {
+		_, ok := l.admit(a, now)
+		assert.Assert(t, ok, "create %d", i)
+		l.refund(a)
+	}
+
+	for i := 0; i < createBurst; i++ {
+		_, ok := l.admit(a, now)
+		assert.Assert(t, ok, "create %d", i)
+	}
+	_, ok := l.admit(a, now)
+	assert.Assert(t, !ok)
+
+	// Nor do refunds go past the burst.
+	for i := 0; i < 2*createBurst; i++ {
+		l.refund(a)
+	}
+	for i := 0; i < createBurst; i++ {
+		_, ok = l.admit(a, now)
+		assert.Assert(t, ok, "create %d", i)
+	}
+	_, ok = l.admit(a, now)
+	assert.Assert(t, !ok)
+
+	// An address without a bucket has nothing to refund.
+	l.refund(net.ParseIP("192.0.2.2"))
+	assert.Equal(t, len(l.buckets), 1)
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"time"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+)
+
+// Mirror delays, in seconds. Zero disables the delay.
+const (
+	minMirrorDelay = 30
+	maxMirrorDelay = 180
+)
+
+// mirrorCoalesce is the granularity of the mirror delay queue. States which
+// come due within the same interval are coalesced into the latest one, which
+// bounds the queue at maxMirrorDelay/mirrorCoalesce entries.
+const mirrorCoalesce = time.Second
+
+// delayedNote is a state note waiting to be sent to the mirrors.
+type delayedNote struct {
+	due  time.Time
+	note protocol.ServerNote
+}
+
+// changeMirrorDelay changes how long mirrors lag behind the room. Only the
+// host may change it.
+//
+// Must be called on the room's goroutine.
+func (r *Room) changeMirrorDelay(playerID game.PlayerID, seconds int) {
+	if playerID != r.room.Host || seconds == r.mirrorDelay {
+		return
+	}
+
+	if seconds != 0 && (seconds < minMirrorDelay || seconds > maxMirrorDelay) {
+		return
+	}
+
+	if r.mirrorDelay == 0 {
+		// Mirrors are up to date; start delaying from what they last saw.
+		r.mirrorLatest = r.mirrorNote()
+	}
+
+	r.mirrorDelay = seconds
+	r.room.Version++
+
+	if seconds == 0 {
+		r.flushMirrors()
+	}
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) mirrorNote() *protocol.ServerNote {
+	if r.state == nil || r.state.version != r.room.Version {
+		r.state = r.createStateCache()
+	}
+
+	note := protocol.NewStateNote("", r.state.guesser)
+	return &note
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) sendMirrors() {
+	if r.mirrorDelay == 0 {
+		if len(r.mirrors) != 0 || len(r.spectators) != 0 {
+			r.deliverMirrors(r.mirrorNote())
+		}
+		return
+	}
+
+	// The end of the game isn't worth hiding.
+	if r.room.Winner != nil {
+		r.flushMirrors()
+		return
+	}
+
+	note := r.mirrorNote()
+	// Round up, so that notes are never sent early.
+	at := r.clock.Now().Add(time.Duration(r.mirrorDelay) * time.Second)
+	due := at.Truncate(mirrorCoalesce)
+	if due.Before(at) {
+		due = due.Add(mirrorCoalesce)
+	}
+
+	if n := len(r.mirrorQueue); n != 0 && !r.mirrorQueue[n-1].due.Before(due) {
+		r.mirrorQueue[n-1].note = *note
+		return
+	}
+
+	r.mirrorQueue = append(r.mirrorQueue, delayedNote{due: due, note: *note})
+
+	if r.mirrorTimer == nil {
+		r.mirrorTimer = r.after(due.Sub(r.clock.Now()), r.timerSendMirrors)
+	}
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) deliverMirrors(note *protocol.ServerNote) {
+	r.mirrorLatest = note
+	for _, m := range r.mirrors {
+		m.send(priorityBroadcast, *note)
+	}
+
+	now := r.clock.Now()
+	for _, m := range r.spectators {
+		if m.overflow {
+			continue // Sent on their own timer; see timerSendOverflow.
+		}
+		m.sent = now
+		m.send(priorityBroadcast, *note)
+	}
+}
+
+// flushMirrors drops the delay queue and sends the current state.
+//
+// Must be called on the room's goroutine.
+func (r *Room) flushMirrors() {
+	r.stopMirrorTimer()
+	r.mirrorQueue = nil
+	r.deliverMirrors(r.mirrorNote())
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) stopMirrorTimer() (stopped bool) {
+	if r.mirrorTimer != nil {
+		r.mirrorTimer.Stop()
+		stopped = true
+	}
+	r.mirrorTimer = nil
+	return stopped
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) timerSendMirrors() {
+	if !r.stopMirrorTimer() {
+		// Room was pruned, or the queue was flushed.
+		return
+	}
+
+	now := r.clock.Now()
+
+	var due *protocol.ServerNote
+	for len(r.mirrorQueue) != 0 && !r.mirrorQueue[0].due.After(now) {
+		note := r.mirrorQueue[0].note
+		due = &note
+		r.mirrorQueue = r.mirrorQueue[1:]
+	}
+
+	// Only the newest state matters.
+	if due != nil {
+		r.deliverMirrors(due)
+	}
+
+	if len(r.mirrorQueue) != 0 {
+		r.mirrorTimer = r.after(r.mirrorQueue[0].due.Sub(now), r.timerSendMirrors)
+	}
+}
+
+// sendMirror sends a newly connected mirror what the other mirrors currently
+// see, which is delayed like everything else.
+//
+// Must be called on the room's goroutine.
+func (r *Room) sendMirror(m *mirror) {
+	note := r.currentMirrorNote()
+	m.sent = r.clock.Now()
+	m.send(priorityBroadcast, *note)
+}
+
+// currentMirrorNote returns what mirrors currently see.
+//
+// Must be called on the room's goroutine.
+func (r *Room) currentMirrorNote() *protocol.ServerNote {
+	note := r.mirrorLatest
+	if r.mirrorDelay == 0 || note == nil {
+		note = r.mirrorNote()
+		r.mirrorLatest = note
+	}
+	return note
+}
@@ -0,0 +1,228 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+type delayedState struct {
+	at      time.Time
+	version int64
+}
+
+type testMirror struct {
+	clock  *fakeClock
+	states []delayedState
+}
+
+func (r *Room) addTestMirror(t *testing.T, id string, c *fakeClock) *testMirror {
+	t.Helper()
+
+	m := &testMirror{clock: c}
+	tm := &mirror{send: func(_ priority, note protocol.ServerNote) {
+		state := note.Params.(*protocol.State)
+		m.states = append(m.states, delayedState{at: c.Now(), version: state.RoomState.Version})
+	}}
+
+	r.hold(func() {
+		r.mirrors[id] = tm
+		r.sendMirror(tm)
+	})
+	return m
+}
+
+func (m *testMirror) last() delayedState {
+	return m.states[len(m.states)-1]
+}
+
+// newDelayedTestRoom returns a room with a host and a mirror delay driven by a
+// fake clock.
+func newDelayedTestRoom(t *testing.T, seconds int) (*Room, *fakeClock) {
+	t.Helper()
+
+	r := newTestRoom(t)
+	c := newFakeClock()
+	r.setClock(c)
+	r.addTestClient(t, "host", 0, false)
+	r.addTestClient(t, "other", 1, false)
+	r.testNote(t, "host", protocol.ChangeMirrorDelayMethod, &protocol.ChangeMirrorDelayParams{Seconds: seconds})
+	return r, c
+}
+
+func (r *Room) revealBomb(t *testing.T, id game.PlayerID) {
+	t.Helper()
+
+	row, col, found := 0, 0, false
+	r.hold(func() {
+		for row = 0; row < r.room.Board.Rows; row++ {
+			for col = 0; col < r.room.Board.Cols; col++ {
+				if r.room.Board.Get(row, col).Bomb {
+					found = true
+					return
+				}
+			}
+		}
+	})
+	if !found {
+		t.Fatal("no bomb")
+	}
+	r.testNote(t, id, protocol.RevealMethod, &protocol.RevealParams{Row: row, Col: col})
+}
+
+func (r *Room) version() int64 {
+	var version int64
+	r.hold(func() {
+		version = r.room.Version
+	})
+	return version
+}
+
+func TestMirrorDelayOffset(t *testing.T) {
+	r, c := newDelayedTestRoom(t, 30)
+	before := r.version() - 1 // Mirrors haven't seen the delay being enabled.
+
+	m := r.addTestMirror(t, "m", c)
+	assert.Equal(t, len(m.states), 1)
+	assert.Equal(t, m.last().version, before)
+
+	start := c.Now()
+	r.testNote(t, "host", protocol.ChangeTeamMethod, &protocol.ChangeTeamParams{Team: 1})
+	changed := r.version()
+
+	c.Advance(29 * time.Second)
+	assert.Equal(t, len(m.states), 1)
+
+	c.Advance(time.Second)
+	assert.Equal(t, len(m.states), 2)
+	assert.Equal(t, m.last().version, changed)
+
+	offset := m.last().at.Sub(start)
+	assert.Assert(t, offset >= 30*time.Second && offset <= 30*time.Second+mirrorCoalesce, "offset %v", offset)
+}
+
+func TestMirrorDelayPlayersRealTime(t *testing.T) {
+	r, _ := newDelayedTestRoom(t, 30)
+	c := r.addTestClient(t, "player", 0, false)
+
+	r.testNote(t, "host", protocol.ChangeTeamMethod, &protocol.ChangeTeamParams{Team: 1})
+	assert.Equal(t, c.lastState().RoomState.Version, r.version())
+}
+
+func TestMirrorDelayCoalesces(t *testing.T) {
+	r, c := newDelayedTestRoom(t, 30)
+	m := r.addTestMirror(t, "m", c)
+
+	// Many changes within one coalescing interval.
+	for i := 0; i < 10; i++ {
+		r.testNote(t, "host", protocol.ChangeTeamMethod, &protocol.ChangeTeamParams{Team: game.Team((i + 1) % 2)})
+	}
+
+	r.hold(func() {
+		assert.Equal(t, len(r.mirrorQueue), 1)
+	})
+
+	c.Advance(31 * time.Second)
+	assert.Equal(t, len(m.states), 2)
+	assert.Equal(t, m.last().version, r.version())
+}
+
+func TestMirrorDelayQueueBounded(t *testing.T) {
+	r, c := newDelayedTestRoom(t, maxMirrorDelay)
+	r.addTestMirror(t, "m", c)
+
+	for i := 0; i < 10*maxMirrorDelay; i++ {
+		r.testNote(t, "host", protocol.ChangeTeamMethod, &protocol.ChangeTeamParams{Team: game.Team((i + 1) % 2)})
+		c.Advance(100 * time.Millisecond)
+	}
+
+	r.hold(func() {
+		assert.Assert(t, len(r.mirrorQueue) <= int(maxMirrorDelay*time.Second/mirrorCoalesce)+1, "queue length %d", len(r.mirrorQueue))
+	})
+}
+
+func TestMirrorDelaySequence(t *testing.T) {
+	r, c := newDelayedTestRoom(t, 30)
+	m := r.addTestMirror(t, "m", c)
+
+	var versions []int64
+	for i := 0; i < 3; i++ {
+		r.testNote(t, "host", protocol.ChangeTeamMethod, &protocol.ChangeTeamParams{Team: game.Team((i + 1) % 2)})
+		versions = append(versions, r.version())
+		c.Advance(10 * time.Second)
+	}
+
+	for i := 0; i < 30; i++ {
+		c.Advance(time.Second)
+	}
+	assert.Equal(t, len(m.states), 4)
+	for i, v := range versions {
+		assert.Equal(t, m.states[i+1].version, v)
+		if i > 0 {
+			assert.Equal(t, m.states[i+1].at.Sub(m.states[i].at), 10*time.Second)
+		}
+	}
+}
+
+func TestMirrorDelayGameEndFlushes(t *testing.T) {
+	r, c := newDelayedTestRoom(t, 30)
+	m := r.addTestMirror(t, "m", c)
+
+	r.testNote(t, "host", protocol.ChangeTeamMethod, &protocol.ChangeTeamParams{Team: 1})
+	assert.Equal(t, len(m.states), 1)
+
+	r.hold(func() {
+		r.room.Turn = 1
+	})
+	r.revealBomb(t, "other")
+
+	assert.Equal(t, len(m.states), 2)
+	assert.Equal(t, m.last().version, r.version())
+	assert.Equal(t, m.last().at, c.Now())
+
+	r.hold(func() {
+		assert.Equal(t, len(r.mirrorQueue), 0)
+		assert.Assert(t, r.mirrorTimer == nil)
+	})
+}
+
+func TestMirrorDelayReconnect(t *testing.T) {
+	r, c := newDelayedTestRoom(t, 30)
+	first := r.addTestMirror(t, "first", c)
+
+	r.testNote(t, "host", protocol.ChangeTeamMethod, &protocol.ChangeTeamParams{Team: 1})
+
+	// A reconnecting mirror sees the same delayed state, not the live one.
+	c.Advance(10 * time.Second)
+	second := r.addTestMirror(t, "second", c)
+	assert.Equal(t, second.last().version, first.last().version)
+
+	c.Advance(20 * time.Second)
+	assert.Equal(t, first.last().version, r.version())
+	assert.Equal(t, second.last().version, r.version())
+}
+
+func TestMirrorDelayDisabledFlushes(t *testing.T) {
+	r, c := newDelayedTestRoom(t, 30)
+	m := r.addTestMirror(t, "m", c)
+
+	r.testNote(t, "host", protocol.ChangeTeamMethod, &protocol.ChangeTeamParams{Team: 1})
+	r.testNote(t, "host", protocol.ChangeMirrorDelayMethod, &protocol.ChangeMirrorDelayParams{Seconds: 0})
+	assert.Equal(t, m.last().version, r.version())
+	assert.Equal(t, m.last().at, c.Now())
+}
+
+func TestMirrorDelayHostOnly(t *testing.T) {
+	r, _ := newDelayedTestRoom(t, 30)
+
+	r.testNote(t, "other", protocol.ChangeMirrorDelayMethod, &protocol.ChangeMirrorDelayParams{Seconds: 0})
+	r.testNote(t, "host", protocol.ChangeMirrorDelayMethod, &protocol.ChangeMirrorDelayParams{Seconds: 5})
+	r.testNote(t, "host", protocol.ChangeMirrorDelayMethod, &protocol.ChangeMirrorDelayParams{Seconds: maxMirrorDelay + 1})
+
+	r.hold(func() {
+		assert.Equal(t, r.mirrorDelay, 30)
+	})
+}
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"reflect"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+)
+
+// Must be called on the room's goroutine.
+func (r *Room) currentState() *stateCache {
+	if r.state == nil || r.state.version != r.room.Version {
+		r.state = r.createStateCache()
+	}
+	return r.state
+}
+
+// sendRoster broadcasts a change which is expected to only affect the player
+// list, given the state from before the change. Clients with the deltas
+// capability are sent roster deltas if the change really was roster-only;
+// everyone else, and the player who just joined (if any), get the full state.
+//
+// Must be called on the room's goroutine.
+func (r *Room) sendRoster(before *stateCache, joined game.PlayerID) {
+	if r.room.Version == before.version {
+		return
+	}
+
+	if r.rolloverVersion() {
+		r.sendAll()
+		return
+	}
+
+	after := r.currentState()
+	deltas, ok := rosterDeltas(before, after)
+	if !ok {
+		r.sendAll()
+		return
+	}
+
+	metricRosterDeltas.Inc()
+
+	for playerID, sender := range r.players {
+		if !r.deltas[playerID] || playerID == joined {
+			r.sendOne(playerID, sender, priorityBroadcast)
+			continue
+		}
+
+		for _, note := range deltas {
+			sender(priorityBroadcast, note)
+		}
+	}
+	r.sendMirrors()
+}
+
+type rosterEntry struct {
+	team   game.Team
+	player *protocol.StatePlayer
+}
+
+func rosterIndex(teams [][]*protocol.StatePlayer) map[game.PlayerID]rosterEntry {
+	index := make(map[game.PlayerID]rosterEntry)
+	for team, players := range teams {
+		for _, p := range players {
+			index[p.PlayerID] = rosterEntry{team: game.Team(team), player: p}
+		}
+	}
+	return index
+}
+
+// rosterDeltas describes the change between two states as roster deltas. It
+// returns false if anything other than the player list changed, if a player's
+// view of the board changed, or if the deltas wouldn't reproduce the new
+// player list exactly.
+func rosterDeltas(before, after *stateCache) ([]protocol.ServerNote, bool) {
+	if !reflect.DeepEqual(withoutRoster(before.guesser), withoutRoster(after.guesser)) ||
+		!reflect.DeepEqual(withoutRoster(before.spymaster), withoutRoster(after.spymaster)) ||
+		!reflect.DeepEqual(before.suggestions, after.suggestions) {
+		return nil, false
+	}
+
+	since, version, hash := before.version, after.version, after.guesser.BoardHash
+	prev := rosterIndex(before.guesser.Teams)
+	next := rosterIndex(after.guesser.Teams)
+
+	var deltas []protocol.ServerNote
+
+	for _, players := range before.guesser.Teams {
+		for _, p := range players {
+			if _, ok := next[p.PlayerID]; !ok {
+				deltas = append(deltas, protocol.NewPlayerLeftNote(since, version, hash, p.PlayerID))
+			}
+		}
+	}
+
+	for team, players := range after.guesser.Teams {
+		for _, p := range players {
+			old, ok := prev[p.PlayerID]
+			switch {
+			case !ok:
+				deltas = append(deltas, protocol.NewPlayerJoinedNote(since, version, hash, game.Team(team), p))
+			case old.team != game.Team(team) || old.player.Spymaster != p.Spymaster:
+				return nil, false
+			case *old.player != *p:
+				deltas = append(deltas, protocol.NewPlayerUpdatedNote(since, version, hash, game.Team(team), p))
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(applyRoster(before.guesser.Teams, deltas), after.guesser.Teams) {
+		return nil, false
+	}
+
+	return deltas, true
+}
+
+// withoutRoster returns a copy of the state without the parts which roster
+// deltas change. The board hash covers the version, so it changes too.
+func withoutRoster(s *protocol.RoomState) protocol.RoomState {
+	c := s.Unencoded()
+	c.Version = 0
+	c.BoardHash = ""
+	c.Teams = nil
+	return c
+}
+
+// applyRoster applies roster deltas to a player list the way a client would.
+func applyRoster(teams [][]*protocol.StatePlayer, deltas []protocol.ServerNote) [][]*protocol.StatePlayer {
+	out := make([][]*protocol.StatePlayer, len(teams))
+	for team, players := range teams {
+		out[team] = append([]*protocol.StatePlayer{}, players...)
+	}
+
+	remove := func(team int, id game.PlayerID) {
+		for i, p := range out[team] {
+			if p.PlayerID == id {
+				out[team] = append(out[team][:i:i], out[team][i+1:]...)
+				return
+			}
+		}
+	}
+
+	for _, note := range deltas {
+		switch d := note.Params.(type) {
+		case *protocol.PlayerLeft:
+			for team := range out {
+				remove(team, d.PlayerID)
+			}
+		case *protocol.PlayerJoined:
+			out[d.Team] = append(out[d.Team], d.Player)
+		case *protocol.PlayerUpdated:
+			for i, p := range out[d.Team] {
+				if p.PlayerID == d.Player.PlayerID {
+					out[d.Team][i] = d.Player
+				}
+			}
+		}
+	}
+
+	return out
+}
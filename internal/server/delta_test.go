@@ -0,0 +1,220 @@
+package server
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func (r *Room) joinTestClient(t testing.TB, id game.PlayerID, deltas bool) *testClient {
+	t.Helper()
+
+	c := &testClient{}
+
+	r.hold(func() {
+		r.join(id, func(_ priority, note protocol.ServerNote) {
+			c.notes = append(c.notes, note)
+		}, ConnOptions{Nickname: string(id), Deltas: deltas})
+	})
+	return c
+}
+
+func (c *testClient) methods(from int) []string {
+	var methods []string
+	for _, n := range c.notes[from:] {
+		methods = append(methods, string(n.Method))
+	}
+	return methods
+}
+
+// roster tracks a client's view of the player list, applying deltas the way
+// a client should.
+type roster struct {
+	version int64
+	teams   [][]*protocol.StatePlayer
+}
+
+func (rs *roster) apply(t *testing.T, notes []protocol.ServerNote) {
+	t.Helper()
+
+	for _, note := range notes {
+		var since, version int64
+		switch d := note.Params.(type) {
+		case *protocol.State:
+			rs.version = d.RoomState.Version
+			rs.teams = d.RoomState.Teams
+			continue
+		case *protocol.PlayerJoined:
+			since, version = d.Since, d.Version
+		case *protocol.PlayerLeft:
+			since, version = d.Since, d.Version
+		case *protocol.PlayerUpdated:
+			since, version = d.Since, d.Version
+		default:
+			continue
+		}
+
+		assert.Assert(t, rs.version == since || rs.version == version, "gap: at %d, delta %d -> %d", rs.version, since, version)
+		rs.teams = applyRoster(rs.teams, []protocol.ServerNote{note})
+		rs.version = version
+	}
+}
+
+func TestRosterDeltasJoinLeave(t *testing.T) {
+	r := newTestRoom(t)
+	old := r.joinTestClient(t, "old", false)
+	capable := r.joinTestClient(t, "capable", true)
+
+	oldSent, capableSent := len(old.notes), len(capable.notes)
+	joined := r.joinTestClient(t, "joined", true)
+
+	assert.DeepEqual(t, old.methods(oldSent), []string{"state"})
+	assert.DeepEqual(t, capable.methods(capableSent), []string{"playerJoined"})
+	assert.DeepEqual(t, joined.methods(0), []string{"state"})
+
+	oldSent, capableSent = len(old.notes), len(capable.notes)
+	r.disconnect("joined", nil)
+
+	assert.DeepEqual(t, old.methods(oldSent), []string{"state"})
+	assert.DeepEqual(t, capable.methods(capableSent), []string{"playerLeft"})
+
+	var view roster
+	view.apply(t, capable.notes)
+	assert.Equal(t, view.version, r.version())
+	r.hold(func() {
+		assert.DeepEqual(t, view.teams, r.currentState().guesser.Teams)
+	})
+}
+
+func TestRosterDeltasHostLeaves(t *testing.T) {
+	r := newTestRoom(t)
+	r.joinTestClient(t, "host", false)
+	capable := r.joinTestClient(t, "capable", true)
+	sent := len(capable.notes)
+
+	r.disconnect("host", nil)
+
+	assert.DeepEqual(t, capable.methods(sent), []string{"playerLeft", "playerUpdated"})
+	updated := capable.notes[len(capable.notes)-1].Params.(*protocol.PlayerUpdated)
+	assert.Equal(t, updated.Player.PlayerID, game.PlayerID("capable"))
+	assert.Assert(t, updated.Player.Host)
+
+	var view roster
+	view.apply(t, capable.notes)
+	r.hold(func() {
+		assert.DeepEqual(t, view.teams, r.currentState().guesser.Teams)
+	})
+}
+
+func TestRosterDeltasNickname(t *testing.T) {
+	r := newTestRoom(t)
+	old := r.joinTestClient(t, "old", false)
+	capable := r.joinTestClient(t, "capable", true)
+	oldSent, capableSent := len(old.notes), len(capable.notes)
+
+	r.testNote(t, "old", protocol.ChangeNicknameMethod, &protocol.ChangeNicknameParams{Nickname: "renamed"})
+
+	assert.DeepEqual(t, old.methods(oldSent), []string{"state"})
+	assert.DeepEqual(t, capable.methods(capableSent), []string{"playerUpdated"})
+
+	updated := capable.notes[len(capable.notes)-1].Params.(*protocol.PlayerUpdated)
+	assert.Equal(t, updated.Player.Nickname, "renamed")
+}
+
+func TestRosterDeltasGameAffecting(t *testing.T) {
+	r := newTestRoom(t)
+	r.joinTestClient(t, "host", false)
+	r.hold(func() {
+		r.room.ChangeSpymasters(2, true)
+	})
+
+	spy0 := r.joinTestClient(t, "spy0", true)
+	r.joinTestClient(t, "spy1", true)
+	r.hold(func() {
+		r.room.ChangeTeam("spy0", 0)
+		r.room.ChangeTeam("spy1", 0)
+		assert.NilError(t, r.room.ChangeRole("spy0", true))
+		assert.NilError(t, r.room.ChangeRole("spy1", true))
+		r.room.Turn = 0
+	})
+
+	r.testNote(t, "spy0", protocol.GiveClueMethod, &protocol.GiveClueParams{Word: "ANIMAL", Count: 1})
+	r.hold(func() {
+		assert.Assert(t, r.room.PendingClue != nil)
+	})
+
+	// spy0's pending clue goes out once spy1 leaves, which isn't a roster-only
+	// change, so even capable clients get the full state.
+	sent := len(spy0.notes)
+	r.disconnect("spy1", nil)
+
+	r.hold(func() {
+		assert.Assert(t, r.room.Clue != nil)
+	})
+	assert.DeepEqual(t, spy0.methods(sent), []string{"state"})
+}
+
+func TestRosterDeltasMirrors(t *testing.T) {
+	r := newTestRoom(t)
+	r.joinTestClient(t, "host", true)
+	m := r.addTestMirror(t, "m", newFakeClock())
+
+	r.joinTestClient(t, "joined", true)
+	assert.Equal(t, m.last().version, r.version())
+}
+
+func TestRosterDeltasNoChange(t *testing.T) {
+	r := newTestRoom(t)
+	capable := r.joinTestClient(t, "capable", true)
+	sent := len(capable.notes)
+
+	r.disconnect("missing", nil)
+	assert.Equal(t, len(capable.notes), sent)
+}
+
+const lobbySize = 30
+
+// BenchmarkLobbyChurn measures the bytes broadcast when players leave and
+// rejoin a lobby with no game running.
+func BenchmarkLobbyChurn(b *testing.B) {
+	for _, deltas := range []bool{false, true} {
+		deltas := deltas
+		b.Run("deltas="+strconv.FormatBool(deltas), func(b *testing.B) {
+			r := newTestRoom(b)
+
+			var bytes int64
+			count := func(_ priority, note protocol.ServerNote) {
+				buf, err := json.Marshal(&note)
+				if err != nil {
+					b.Fatal(err)
+				}
+				bytes += int64(len(buf))
+			}
+
+			r.hold(func() {
+				for i := 0; i < lobbySize; i++ {
+					id := game.PlayerID("p" + strconv.Itoa(i))
+					r.join(id, count, ConnOptions{Nickname: string(id), Deltas: deltas})
+				}
+			})
+
+			bytes = 0
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				id := game.PlayerID("p" + strconv.Itoa(i%lobbySize))
+				r.disconnect(id, nil)
+
+				r.hold(func() {
+					r.join(id, count, ConnOptions{Nickname: string(id), Deltas: deltas})
+				})
+			}
+
+			b.ReportMetric(float64(bytes)/float64(b.N), "bytes/op")
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"github.com/zikaeroh/ctxlog"
+	"go.uber.org/zap"
+)
+
+const (
+	// desyncWindow is how long desync reports are remembered.
+	desyncWindow = 10 * time.Second
+
+	// desyncRoomReports is the number of players who must report a desync
+	// within desyncWindow before everyone is resent the state.
+	desyncRoomReports = 2
+)
+
+// reportDesync checks a player's hash of their board against the room's. If
+// they differ, the player is resent the full state; if enough players have
+// reported a desync recently, the whole room is.
+//
+// Must be called on the room's goroutine.
+func (r *Room) reportDesync(ctx context.Context, playerID game.PlayerID, hash string) {
+	sender := r.players[playerID]
+	if sender == nil {
+		return
+	}
+
+	state := r.currentState()
+
+	want := state.boardHash
+	if hash == want {
+		return
+	}
+
+	metricDesyncs.Inc()
+	ctxlog.Debug(ctx, "client desynced",
+		zap.String("got", hash),
+		zap.String("want", want),
+		zap.String("board", protocol.CanonicalBoard(state.guesser)),
+	)
+
+	now := r.clock.Now()
+	for id, at := range r.desyncs {
+		if now.Sub(at) >= desyncWindow {
+			delete(r.desyncs, id)
+		}
+	}
+	r.desyncs[playerID] = now
+
+	if len(r.desyncs) < desyncRoomReports {
+		r.sendOne(playerID, sender, priorityTargeted)
+		return
+	}
+
+	ctxlog.Info(ctx, "resending state to room after desync reports", zap.Int("reports", len(r.desyncs)))
+	r.desyncs = make(map[game.PlayerID]time.Time)
+	metricDesyncResyncs.Inc()
+
+	for id, sender := range r.players {
+		r.sendOne(id, sender, priorityTargeted)
+	}
+}
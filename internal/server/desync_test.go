@@ -0,0 +1,112 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func (r *Room) boardHash(t *testing.T, spymaster bool) string {
+	t.Helper()
+
+	var hash string
+	r.hold(func() {
+		hash = protocol.BoardHash(r.createRoomState(spymaster))
+	})
+	return hash
+}
+
+func TestReportDesyncMatching(t *testing.T) {
+	r := newTestRoom(t)
+	spy := r.addTestClient(t, "spy", 0, true)
+	states := spy.states()
+
+	// Spymasters hash the same as everyone else.
+	r.testNote(t, "spy", protocol.ReportDesyncMethod, &protocol.ReportDesyncParams{Hash: r.boardHash(t, true)})
+	assert.Equal(t, spy.states(), states)
+}
+
+func TestReportDesyncResendsToReporter(t *testing.T) {
+	r := newTestRoom(t)
+	a := r.addTestClient(t, "a", 0, false)
+	b := r.addTestClient(t, "b", 1, false)
+	statesA, statesB := a.states(), b.states()
+
+	r.testNote(t, "a", protocol.ReportDesyncMethod, &protocol.ReportDesyncParams{Hash: "wrong"})
+	assert.Equal(t, a.states(), statesA+1)
+	assert.Equal(t, b.states(), statesB)
+}
+
+func TestReportDesyncRoomWide(t *testing.T) {
+	r := newTestRoom(t)
+	c := newFakeClock()
+	r.setClock(c)
+	a := r.addTestClient(t, "a", 0, false)
+	b := r.addTestClient(t, "b", 1, false)
+	other := r.addTestClient(t, "other", 1, false)
+
+	r.testNote(t, "a", protocol.ReportDesyncMethod, &protocol.ReportDesyncParams{Hash: "wrong"})
+
+	// Reports outside of the window don't count together.
+	c.Advance(desyncWindow)
+	statesOther := other.states()
+	r.testNote(t, "b", protocol.ReportDesyncMethod, &protocol.ReportDesyncParams{Hash: "wrong"})
+	assert.Equal(t, other.states(), statesOther)
+
+	c.Advance(time.Second)
+	statesA, statesB := a.states(), b.states()
+	r.testNote(t, "a", protocol.ReportDesyncMethod, &protocol.ReportDesyncParams{Hash: "wrong"})
+	assert.Equal(t, a.states(), statesA+1)
+	assert.Equal(t, b.states(), statesB+1)
+	assert.Equal(t, other.states(), statesOther+1)
+
+	r.hold(func() {
+		assert.Equal(t, len(r.desyncs), 0)
+	})
+}
+
+func TestBroadcastBoardHash(t *testing.T) {
+	r := newTestRoom(t)
+	spy := r.joinTestClient(t, "spy", false)
+	guesser := r.joinTestClient(t, "guesser", true)
+
+	r.hold(func() {
+		assert.NilError(t, r.room.ChangeRole("spy", true))
+	})
+
+	r.testNote(t, "spy", protocol.ChangeTeamMethod, &protocol.ChangeTeamParams{Team: 1})
+	r.testNote(t, "guesser", protocol.RevealMethod, &protocol.RevealParams{Row: 0, Col: 0, Force: true})
+	r.joinTestClient(t, "late", false)
+
+	check := func(c *testClient) (states, deltas int) {
+		var last protocol.RoomState
+		for _, note := range c.notes {
+			switch params := note.Params.(type) {
+			case *protocol.State:
+				s := params.RoomState
+				assert.Equal(t, s.BoardHash, protocol.ShortBoardHash(s))
+				last = *s
+				states++
+			case *protocol.PlayerJoined:
+				last.Version = params.Version
+				assert.Equal(t, params.BoardHash, protocol.ShortBoardHash(&last))
+				deltas++
+			}
+		}
+		return states, deltas
+	}
+
+	states, _ := check(spy)
+	assert.Assert(t, states > 1)
+	_, deltas := check(guesser)
+	assert.Assert(t, deltas > 0)
+
+	// Everyone can check the same hash, whatever they were sent.
+	r.hold(func() {
+		state := r.currentState()
+		assert.Equal(t, state.spymaster.BoardHash, state.guesser.BoardHash)
+		assert.Equal(t, protocol.BoardHash(state.spymaster), state.boardHash)
+	})
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+
+	"nhooyr.io/websocket"
+)
+
+// disconnectReason is why a connection ended, for logs and metrics.
+type disconnectReason string
+
+const (
+	// disconnectClosed means the client sent a close frame.
+	disconnectClosed = disconnectReason("closed")
+	// disconnectNetwork means the connection dropped without a close frame:
+	// reset, truncated, or otherwise gone.
+	disconnectNetwork = disconnectReason("network")
+	// disconnectTimeout means pings or a write went unanswered.
+	disconnectTimeout = disconnectReason("timeout")
+	// disconnectProtocol means the client broke the WebSocket protocol, or
+	// sent something which wasn't a note.
+	disconnectProtocol = disconnectReason("protocol")
+	// disconnectTooSlow means the client fell too far behind and was closed.
+	disconnectTooSlow = disconnectReason("tooSlow")
+	// disconnectRejected means the client was never let into the room.
+	disconnectRejected = disconnectReason("rejected")
+	// disconnectServer means the room closed or the server shut down.
+	disconnectServer = disconnectReason("server")
+	// disconnectError means the server failed to handle a note.
+	disconnectError = disconnectReason("error")
+)
+
+// handleError marks the error which ended a connection as the server's own
+// failure, rather than the connection's.
+type handleError struct {
+	err error
+}
+
+func (e *handleError) Error() string { return e.err.Error() }
+func (e *handleError) Unwrap() error { return e.err }
+
+// classifyDisconnect works out why a connection ended, given the context it
+// was served with (before any of its own goroutines could cancel it), its
+// writer, and the first error its goroutines returned.
+//
+// Flaky networks end connections in every way imaginable, and by the time the
+// error arrives, it's been wrapped by the websocket library, TLS, and the
+// network stack. Anything which isn't recognizably the network or a timeout is
+// taken to be the client breaking the protocol.
+func classifyDisconnect(ctx context.Context, w *connWriter, err error) disconnectReason {
+	var hErr *handleError
+
+	switch {
+	case ctx.Err() != nil:
+		return disconnectServer
+	case w.closedWith() == closeTooSlow:
+		return disconnectTooSlow
+	case errors.As(err, &hErr):
+		return disconnectError
+	case errors.Is(err, errUnresponsive):
+		return disconnectTimeout
+	case err == nil, websocket.CloseStatus(err) != -1:
+		return disconnectClosed
+	case errors.Is(err, context.DeadlineExceeded):
+		return disconnectTimeout
+	case isNetworkError(err):
+		return disconnectNetwork
+	default:
+		return disconnectProtocol
+	}
+}
+
+func isNetworkError(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, errWriterClosed) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
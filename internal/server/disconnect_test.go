@@ -0,0 +1,404 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"github.com/zikaeroh/ctxlog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	"nhooyr.io/websocket"
+)
+
+// closeTest is a single client connected to a room over a real socket, with
+// the client's TCP connection exposed so tests can misbehave underneath the
+// WebSocket.
+type closeTest struct {
+	room    *Room
+	logs    *observer.ObservedLogs
+	ws      *websocket.Conn
+	raw     net.Conn // The client's TCP connection, under any TLS.
+	handled chan struct{}
+}
+
+// newCloseTest connects a client. If ping is set, the room pings its clients
+// that often.
+func newCloseTest(t *testing.T, useTLS bool, ping time.Duration) *closeTest {
+	t.Helper()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	ctx, cancel := context.WithCancel(ctxlog.WithLogger(context.Background(), zap.New(core)))
+
+	room := newRoom(ctx, "test", nil, "test", &counters{})
+	room.room = game.NewRoom(game.NewRand(1))
+	room.room.NewGame()
+	if ping != 0 {
+		room.pingInterval = ping
+	}
+
+	ct := &closeTest{
+		room:    room,
+		logs:    logs,
+		handled: make(chan struct{}),
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(ct.handled)
+
+		c, counter, err := Accept(w, r, testAcceptOptions)
+		if err != nil {
+			return
+		}
+
+		room.HandleConn(ctx, ConnOptions{Nickname: "player", Deltas: true, Counter: counter}, c)
+	})
+
+	var hs *httptest.Server
+	if useTLS {
+		hs = httptest.NewTLSServer(handler)
+	} else {
+		hs = httptest.NewServer(handler)
+	}
+
+	raws := make(chan net.Conn, 1)
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			c, err := (&net.Dialer{}).DialContext(ctx, network, addr)
+			if err == nil {
+				raws <- c
+			}
+			return c, err
+		},
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
+	}
+
+	url := "ws" + strings.TrimPrefix(hs.URL, "http")
+	c, _, err := websocket.Dial(ctx, url, &websocket.DialOptions{HTTPClient: &http.Client{Transport: transport}})
+	assert.NilError(t, err)
+
+	ct.ws = c
+	ct.raw = <-raws
+
+	t.Cleanup(func() {
+		cancel()
+		<-ct.handled
+		// The client's connection is usually dead by now. Reading until that's
+		// noticed closes it without waiting out a close handshake which will
+		// never be answered.
+		readCtx, readCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer readCancel()
+		for {
+			if _, _, err := c.Read(readCtx); err != nil {
+				break
+			}
+		}
+
+		c.Close(websocket.StatusNormalClosure, "")
+		ct.raw.Close()
+		transport.CloseIdleConnections()
+		hs.Close()
+	})
+
+	// Wait for the player to join.
+	readNote(t, c, "state", nil)
+	return ct
+}
+
+// writeFrame writes a raw, masked client frame, claiming a payload of length
+// bytes but only sending payload.
+func (ct *closeTest) writeFrame(t *testing.T, opcode byte, length int, payload []byte) {
+	t.Helper()
+
+	assert.Assert(t, length < 126)
+
+	frame := []byte{0x80 | opcode, 0x80 | byte(length), 0, 0, 0, 0}
+	frame = append(frame, payload...) // The mask is all zeros.
+
+	_, err := ct.raw.Write(frame)
+	assert.NilError(t, err)
+}
+
+// reset drops the client's TCP connection with an RST rather than a FIN.
+func (ct *closeTest) reset(t *testing.T) {
+	t.Helper()
+
+	assert.NilError(t, ct.raw.(*net.TCPConn).SetLinger(0))
+	assert.NilError(t, ct.raw.Close())
+}
+
+func (ct *closeTest) player() noteSender {
+	var player noteSender
+	ct.room.hold(func() {
+		for _, sender := range ct.room.players {
+			player = sender
+			break
+		}
+	})
+	return player
+}
+
+func (r *Room) clientCount() int {
+	var count int
+	r.hold(func() {
+		count = len(r.players)
+	})
+	return count
+}
+
+func closePayload(code uint16) []byte {
+	var b [2]byte
+	binary.BigEndian.PutUint16(b[:], code)
+	return b[:]
+}
+
+func TestDisconnectReasons(t *testing.T) {
+	tests := []struct {
+		name      string
+		tls       bool
+		ping      time.Duration
+		misbehave func(t *testing.T, ct *closeTest)
+		want      disconnectReason
+	}{
+		{
+			name: "normal close",
+			misbehave: func(t *testing.T, ct *closeTest) {
+				ct.ws.Close(websocket.StatusNormalClosure, "") //nolint:errcheck
+			},
+			want: disconnectClosed,
+		},
+		{
+			name: "going away",
+			misbehave: func(t *testing.T, ct *closeTest) {
+				ct.ws.Close(websocket.StatusGoingAway, "tab closed") //nolint:errcheck
+			},
+			want: disconnectClosed,
+		},
+		{
+			name: "reset mid-frame",
+			misbehave: func(t *testing.T, ct *closeTest) {
+				ct.writeFrame(t, 0x1, 100, []byte(`{"method":`))
+				ct.reset(t)
+			},
+			want: disconnectNetwork,
+		},
+		{
+			name: "truncated mid-frame",
+			misbehave: func(t *testing.T, ct *closeTest) {
+				ct.writeFrame(t, 0x1, 100, []byte(`{"method":`))
+				assert.NilError(t, ct.raw.Close())
+			},
+			want: disconnectNetwork,
+		},
+		{
+			name: "truncated frame header",
+			misbehave: func(t *testing.T, ct *closeTest) {
+				_, err := ct.raw.Write([]byte{0x81})
+				assert.NilError(t, err)
+				assert.NilError(t, ct.raw.Close())
+			},
+			want: disconnectNetwork,
+		},
+		{
+			name: "idle",
+			ping: 50 * time.Millisecond,
+			misbehave: func(t *testing.T, ct *closeTest) {
+				// Nothing reads the client's side, so pings go unanswered.
+			},
+			want: disconnectTimeout,
+		},
+		{
+			name: "reserved close code",
+			misbehave: func(t *testing.T, ct *closeTest) {
+				ct.writeFrame(t, 0x8, 2, closePayload(uint16(websocket.StatusNoStatusRcvd)))
+			},
+			want: disconnectProtocol,
+		},
+		{
+			name: "close code out of range",
+			misbehave: func(t *testing.T, ct *closeTest) {
+				ct.writeFrame(t, 0x8, 2, closePayload(5000))
+			},
+			want: disconnectProtocol,
+		},
+		{
+			name: "reserved opcode",
+			misbehave: func(t *testing.T, ct *closeTest) {
+				ct.writeFrame(t, 0x3, 0, nil)
+			},
+			want: disconnectProtocol,
+		},
+		{
+			name: "not JSON",
+			misbehave: func(t *testing.T, ct *closeTest) {
+				ct.writeFrame(t, 0x1, 5, []byte(`{"met`))
+				ct.ws.CloseRead(context.Background()) // Answers the server's close.
+			},
+			want: disconnectProtocol,
+		},
+		{
+			name: "reset during server write",
+			misbehave: func(t *testing.T, ct *closeTest) {
+				// Far more than the socket buffers hold, so the write is still
+				// going when the client goes away.
+				big := make([]byte, 8<<20)
+				_, err := rand.Read(big)
+				assert.NilError(t, err)
+				ct.player()(priorityTargeted, protocol.NewErrorNote(&protocol.Error{Code: "big", Message: hex.EncodeToString(big)}))
+
+				time.Sleep(100 * time.Millisecond)
+				ct.reset(t)
+			},
+			want: disconnectNetwork,
+		},
+		{
+			name: "TLS truncated mid-record",
+			tls:  true,
+			misbehave: func(t *testing.T, ct *closeTest) {
+				_, err := ct.raw.Write([]byte{0x17, 0x03, 0x03})
+				assert.NilError(t, err)
+				assert.NilError(t, ct.raw.Close())
+			},
+			want: disconnectNetwork,
+		},
+		{
+			name: "TLS reset",
+			tls:  true,
+			misbehave: func(t *testing.T, ct *closeTest) {
+				ct.reset(t)
+			},
+			want: disconnectNetwork,
+		},
+		{
+			name: "room closed",
+			misbehave: func(t *testing.T, ct *closeTest) {
+				go ct.ws.CloseRead(context.Background())
+				ct.room.cancel()
+			},
+			want: disconnectServer,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			before := runtime.NumGoroutine()
+
+			t.Run("conn", func(t *testing.T) {
+				ct := newCloseTest(t, test.tls, test.ping)
+				test.misbehave(t, ct)
+
+				// The player leaves right away, even if closing the connection
+				// itself takes longer.
+				deadline := time.Now().Add(time.Second)
+				for ct.room.clientCount() != 0 {
+					if time.Now().After(deadline) {
+						t.Fatal("player never left")
+					}
+					time.Sleep(10 * time.Millisecond)
+				}
+
+				select {
+				case <-ct.handled:
+				case <-time.After(10 * time.Second):
+					t.Fatal("connection never torn down")
+				}
+
+				var reasons []string
+				for _, e := range ct.logs.All() {
+					assert.Assert(t, e.Level <= zapcore.WarnLevel, "%s: %s %v", e.Level, e.Message, e.ContextMap())
+					if e.Message == "client disconnected" {
+						reasons = append(reasons, e.ContextMap()["reason"].(string))
+					}
+				}
+				assert.DeepEqual(t, reasons, []string{string(test.want)})
+
+				r := ct.room
+				r.hold(func() {
+					assert.Equal(t, len(r.players), 0)
+					assert.Equal(t, len(r.deltas), 0)
+					assert.Equal(t, len(r.bytes), 0)
+					assert.Equal(t, len(r.room.Players), 0)
+					assert.Equal(t, r.clients.Load(), int64(0))
+					assert.Equal(t, r.counters.clients.Load(), int64(0))
+				})
+			})
+
+			assertNoLeakedGoroutines(t, before)
+		})
+	}
+}
+
+// assertNoLeakedGoroutines waits for the number of goroutines to fall back to
+// what it was before a test.
+func assertNoLeakedGoroutines(t *testing.T, before int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<20)
+			t.Fatalf("leaked %d goroutines:\n%s", runtime.NumGoroutine()-before, buf[:runtime.Stack(buf, true)])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestUnansweredPingsReaped(t *testing.T) {
+	reaped := testutil.ToFloat64(metricConnsReaped)
+
+	ct := newCloseTest(t, false, 20*time.Millisecond)
+	other := ct.room.addTestClient(t, "other", 0, false)
+
+	// Nothing reads the client's side, so its pings go unanswered.
+	deadline := time.Now().Add(time.Second)
+	for ct.room.clientCount() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("player never reaped")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, testutil.ToFloat64(metricConnsReaped), reaped+1)
+
+	var teams [][]*protocol.StatePlayer
+	ct.room.hold(func() {
+		teams = other.lastState().RoomState.Teams
+	})
+	assert.Equal(t, len(teams[0])+len(teams[1]), 1)
+
+	select {
+	case <-ct.handled:
+	case <-time.After(10 * time.Second):
+		t.Fatal("connection never torn down")
+	}
+
+	reaping := ct.logs.FilterMessage("reaping unresponsive connection").All()
+	assert.Equal(t, len(reaping), 1)
+	disconnected := ct.logs.FilterMessage("client disconnected").All()
+	assert.Equal(t, disconnected[0].ContextMap()["reason"], string(disconnectTimeout))
+}
+
+func TestAnsweredPingsKept(t *testing.T) {
+	reaped := testutil.ToFloat64(metricConnsReaped)
+
+	ct := newCloseTest(t, false, 50*time.Millisecond)
+	ct.ws.CloseRead(context.Background()) // Reads, which answers the pings.
+
+	time.Sleep(8 * 50 * time.Millisecond)
+	assert.Equal(t, ct.room.clientCount(), 1)
+	assert.Equal(t, testutil.ToFloat64(metricConnsReaped), reaped)
+}
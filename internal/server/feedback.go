@@ -0,0 +1,175 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/zikaeroh/codies/internal/analytics"
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+)
+
+// Rooms may ask their players to rate each game once it's won. Those playing
+// when it ends may each answer once, until the next game ends. Answers are
+// only kept as counts per ruleset hash, shared by every room the server runs;
+// nothing ties a rating to the room or player who gave it.
+//
+// maxFeedbackRulesets bounds the rulesets tallied. Once full, the ruleset
+// with the fewest ratings makes way for a new one.
+const maxFeedbackRulesets = 1000
+
+var errFeedbackClosed = &game.Error{
+	Code:    "feedbackClosed",
+	Message: "That game can't be rated.",
+}
+
+// feedbackPrompt is a room's feedback setting, and the game last put to its
+// players.
+type feedbackPrompt struct {
+	enabled bool
+	game    int
+	ruleset string
+	mode    string
+	pending map[game.PlayerID]bool // Players who may still answer.
+}
+
+// FeedbackTally counts the ratings given to games with a ruleset, since the
+// server started.
+type FeedbackTally struct {
+	Ruleset string                       `json:"ruleset"`
+	Mode    string                       `json:"mode"`
+	Ratings []int                        `json:"ratings"` // Ratings[i] counts ratings of i+1.
+	Tags    map[protocol.FeedbackTag]int `json:"tags"`
+	Total   int                          `json:"total"`
+}
+
+// feedbackTallies are the tallies by ruleset hash. The zero value is ready to
+// use.
+type feedbackTallies struct {
+	mu        sync.Mutex
+	byRuleset map[string]*FeedbackTally
+}
+
+func (f *feedbackTallies) add(ruleset, mode string, rating int, tag protocol.FeedbackTag) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.byRuleset == nil {
+		f.byRuleset = make(map[string]*FeedbackTally)
+	}
+
+	t := f.byRuleset[ruleset]
+	if t == nil {
+		if len(f.byRuleset) >= maxFeedbackRulesets {
+			f.evict()
+		}
+
+		t = &FeedbackTally{
+			Ruleset: ruleset,
+			Mode:    mode,
+			Ratings: make([]int, protocol.MaxRating-protocol.MinRating+1),
+			Tags:    make(map[protocol.FeedbackTag]int),
+		}
+		f.byRuleset[ruleset] = t
+	}
+
+	t.Ratings[rating-protocol.MinRating]++
+	t.Total++
+	if tag != "" {
+		t.Tags[tag]++
+	}
+}
+
+// Must be called with f.mu locked.
+func (f *feedbackTallies) evict() {
+	var fewest *FeedbackTally
+	for _, t := range f.byRuleset {
+		if fewest == nil || t.Total < fewest.Total || (t.Total == fewest.Total && t.Ruleset < fewest.Ruleset) {
+			fewest = t
+		}
+	}
+	delete(f.byRuleset, fewest.Ruleset)
+}
+
+// list copies the tallies, most rated first.
+func (f *feedbackTallies) list() []*FeedbackTally {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tallies := make([]*FeedbackTally, 0, len(f.byRuleset))
+	for _, t := range f.byRuleset {
+		c := *t
+		c.Ratings = append([]int(nil), t.Ratings...)
+		c.Tags = make(map[protocol.FeedbackTag]int, len(t.Tags))
+		for tag, n := range t.Tags {
+			c.Tags[tag] = n
+		}
+		tallies = append(tallies, &c)
+	}
+
+	sort.Slice(tallies, func(i, j int) bool {
+		if tallies[i].Total != tallies[j].Total {
+			return tallies[i].Total > tallies[j].Total
+		}
+		return tallies[i].Ruleset < tallies[j].Ruleset
+	})
+	return tallies
+}
+
+// Feedback returns the ratings tallied for each ruleset, most rated first.
+func (s *Server) Feedback() []*FeedbackTally {
+	return s.counters.feedback.list()
+}
+
+// promptFeedback asks the players to rate a game which was won since the
+// snapshot was taken, if the room asks for feedback. Players who didn't
+// answer for the game before can no longer.
+//
+// Must be called on the room's goroutine.
+func (r *Room) promptFeedback(before turnSnapshot) {
+	if !r.feedback.enabled || before.won || r.room.Winner == nil {
+		return
+	}
+
+	r.feedback.game = r.room.Games
+	r.feedback.ruleset = r.rulesetHash
+	r.feedback.mode = r.mode()
+	r.feedback.pending = make(map[game.PlayerID]bool, len(r.players))
+
+	note := protocol.NewFeedbackPromptNote(r.room.Games)
+	for playerID, sender := range r.players {
+		r.feedback.pending[playerID] = true
+		sender(priorityBroadcast, note)
+	}
+}
+
+// rateGame records a player's answer to the last feedback prompt.
+//
+// Must be called on the room's goroutine.
+func (r *Room) rateGame(playerID game.PlayerID, params *protocol.RateGameParams) error {
+	if params.Rating < protocol.MinRating || params.Rating > protocol.MaxRating {
+		max := protocol.MaxRating
+		return &game.Error{
+			Code:    "invalidRating",
+			Message: fmt.Sprintf("Ratings must be between %d and %d.", protocol.MinRating, max),
+			Limit:   &max,
+		}
+	}
+
+	if params.Tag != "" && !params.Tag.Valid() {
+		return &game.Error{
+			Code:    "invalidTag",
+			Message: "Unknown feedback tag.",
+		}
+	}
+
+	if params.Game != r.feedback.game || !r.feedback.pending[playerID] {
+		return errFeedbackClosed
+	}
+	delete(r.feedback.pending, playerID)
+
+	r.counters.feedback.add(r.feedback.ruleset, r.feedback.mode, params.Rating, params.Tag)
+	r.counters.analytics.GameRated(analytics.Game{Mode: r.feedback.mode, Ruleset: r.feedback.ruleset}, params.Rating, string(params.Tag))
+	return nil
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func (c *testClient) feedbackPrompts() []*protocol.FeedbackPrompt {
+	var prompts []*protocol.FeedbackPrompt
+	for _, n := range c.notes {
+		if n.Method == "feedbackPrompt" {
+			prompts = append(prompts, n.Params.(*protocol.FeedbackPrompt))
+		}
+	}
+	return prompts
+}
+
+func enableFeedback(t *testing.T, r *Room) {
+	t.Helper()
+
+	enabled := true
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{Feedback: &enabled})
+}
+
+// endTestGame ends the game, with the host's team revealing the bomb.
+func (r *Room) endTestGame(t *testing.T) {
+	t.Helper()
+
+	r.hold(func() {
+		r.room.Turn = 0
+	})
+	r.revealBomb(t, "host")
+}
+
+func TestFeedbackPrompt(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+	other := r.addTestClient(t, "other", 1, false)
+
+	// Off by default.
+	r.endTestGame(t)
+	assert.Equal(t, len(host.feedbackPrompts()), 0)
+
+	// Only the host may turn it on.
+	enabled := true
+	r.testNote(t, "other", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{Feedback: &enabled})
+	assert.Equal(t, len(other.errors()), 1)
+	assert.Equal(t, other.errors()[0].Code, "invalidOptions")
+
+	enableFeedback(t, r)
+	r.hold(func() {
+		assert.Assert(t, r.createRoomState(false).Feedback)
+	})
+
+	r.testNote(t, "host", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	assert.Equal(t, len(host.feedbackPrompts()), 0)
+	r.endTestGame(t)
+
+	for _, c := range []*testClient{host, other} {
+		prompts := c.feedbackPrompts()
+		assert.Equal(t, len(prompts), 1)
+		assert.Equal(t, prompts[0].Game, r.games())
+	}
+}
+
+func TestRateGame(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+	enableFeedback(t, r)
+
+	// Nothing to rate yet.
+	r.testNote(t, "host", protocol.RateGameMethod, &protocol.RateGameParams{Game: r.games(), Rating: 3})
+
+	r.endTestGame(t)
+	late := r.addTestClient(t, "late", 1, false)
+	game := r.games()
+
+	r.testNote(t, "host", protocol.RateGameMethod, &protocol.RateGameParams{Game: game, Rating: 0})
+	r.testNote(t, "host", protocol.RateGameMethod, &protocol.RateGameParams{Game: game, Rating: 6})
+	r.testNote(t, "host", protocol.RateGameMethod, &protocol.RateGameParams{Game: game, Rating: 3, Tag: "boring"})
+	r.testNote(t, "host", protocol.RateGameMethod, &protocol.RateGameParams{Game: game - 1, Rating: 3})
+	r.testNote(t, "host", protocol.RateGameMethod, &protocol.RateGameParams{Game: game, Rating: 4, Tag: protocol.FeedbackFun})
+	r.testNote(t, "host", protocol.RateGameMethod, &protocol.RateGameParams{Game: game, Rating: 5})
+
+	// Players who joined after the game ended weren't asked.
+	r.testNote(t, "late", protocol.RateGameMethod, &protocol.RateGameParams{Game: game, Rating: 1})
+
+	var codes []string
+	for _, e := range host.errors() {
+		codes = append(codes, e.Code)
+	}
+	assert.DeepEqual(t, codes, []string{
+		"feedbackClosed", "invalidRating", "invalidRating", "invalidTag", "feedbackClosed", "feedbackClosed",
+	})
+	assert.Equal(t, len(late.errors()), 1)
+	assert.Equal(t, late.errors()[0].Code, "feedbackClosed")
+
+	assert.DeepEqual(t, r.counters.feedback.list(), []*FeedbackTally{{
+		Ruleset: r.gameRulesetHash(),
+		Mode:    "classic",
+		Ratings: []int{0, 0, 0, 1, 0},
+		Tags:    map[protocol.FeedbackTag]int{protocol.FeedbackFun: 1},
+		Total:   1,
+	}})
+}
+
+func TestFeedbackByRuleset(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t, nil)
+
+	rate := func(name string, timed bool, ratings ...int) *Room {
+		t.Helper()
+
+		r, err := s.CreateRoom(ctx, name, "pass")
+		assert.NilError(t, err)
+		r.addTestClient(t, "host", 0, false)
+		r.addTestClient(t, "other", 1, false)
+		enableFeedback(t, r)
+
+		if timed {
+			r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{Timed: &timed})
+			r.testNote(t, "host", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+		}
+
+		r.endTestGame(t)
+		for i, rating := range ratings {
+			playerID := []string{"host", "other"}[i]
+			r.testNote(t, playerID, protocol.RateGameMethod, &protocol.RateGameParams{Game: r.games(), Rating: rating})
+		}
+		return r
+	}
+
+	first := rate("first", false, 5, 4)
+	second := rate("second", false, 2)
+	timed := rate("timed", true, 1)
+
+	// Rooms playing by the same rules share a tally, whatever else differs
+	// between them.
+	assert.Equal(t, first.gameRulesetHash(), second.gameRulesetHash())
+	assert.Assert(t, first.gameRulesetHash() != timed.gameRulesetHash())
+
+	assert.DeepEqual(t, s.Feedback(), []*FeedbackTally{
+		{
+			Ruleset: first.gameRulesetHash(),
+			Mode:    "classic",
+			Ratings: []int{0, 1, 0, 1, 1},
+			Tags:    map[protocol.FeedbackTag]int{},
+			Total:   3,
+		},
+		{
+			Ruleset: timed.gameRulesetHash(),
+			Mode:    "classic",
+			Ratings: []int{1, 0, 0, 0, 0},
+			Tags:    map[protocol.FeedbackTag]int{},
+			Total:   1,
+		},
+	})
+}
+
+func TestFeedbackTalliesEvict(t *testing.T) {
+	var f feedbackTallies
+	for i := 0; i < maxFeedbackRulesets; i++ {
+		f.add(strconv.Itoa(i), "classic", 3, "")
+		if i != 0 {
+			f.add(strconv.Itoa(i), "classic", 3, "")
+		}
+	}
+	f.add("new", "classic", 3, "")
+
+	tallies := f.list()
+	assert.Equal(t, len(tallies), maxFeedbackRulesets)
+	assert.Equal(t, tallies[len(tallies)-1].Ruleset, "new")
+	for _, tally := range tallies {
+		assert.Assert(t, tally.Ruleset != "0", "least rated ruleset kept")
+	}
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/gamefeed"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func TestGameFeed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.jsonl")
+	feed, err := gamefeed.Open(path, gamefeed.Options{Buffer: 4, Sync: gamefeed.SyncNever})
+	assert.NilError(t, err)
+
+	r := newTestRoom(t)
+	r.counters.feed = feed
+	r.addTestClient(t, "g0", 0, false)
+	r.addTestClient(t, "g1", 1, false)
+	r.setTurn(0)
+	r.hold(func() {
+		r.startRuleset()
+	})
+
+	r.testNote(t, "g0", protocol.EndTurnMethod, &protocol.EndTurnParams{})
+	r.testNote(t, "g1", protocol.EndTurnMethod, &protocol.EndTurnParams{})
+	r.revealBomb(t, "g0")
+	var word string
+	r.hold(func() {
+		word = r.room.Board.Get(0, 0).Word
+	})
+
+	// A new game isn't a finished one.
+	r.testNote(t, "g0", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	assert.NilError(t, feed.Close())
+
+	b, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	for _, word := range []string{"g0", "g1", word} {
+		assert.Assert(t, !strings.Contains(string(b), `"`+word+`"`), word)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	assert.Equal(t, len(lines), 1)
+
+	var e gamefeed.Entry
+	assert.NilError(t, json.Unmarshal([]byte(lines[0]), &e))
+	assert.NilError(t, e.Validate())
+	assert.Equal(t, e.Ruleset, r.gameRulesetHash())
+	assert.Equal(t, e.Winner, 1)
+	assert.Equal(t, e.Outcome, gamefeed.Bomb)
+	assert.Equal(t, e.Turns, 3)
+	assert.Equal(t, len(e.Teams), 2)
+}
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+)
+
+// maxGameLogReveals bounds the reveals logged for a game; the oldest are
+// dropped first. Games end long before their boards run out of cards, so it's
+// only reached by unusually large boards.
+const maxGameLogReveals = 500
+
+// logReveal logs a card revealed in the current game.
+//
+// Must be called on the room's goroutine.
+func (r *Room) logReveal(playerID game.PlayerID, board, row, col int, hint bool) {
+	tile := r.room.Boards[board].Get(row, col)
+	reveal := &protocol.GameLogReveal{
+		Time:     r.clock.Now(),
+		PlayerID: playerID,
+		Board:    board,
+		Row:      row,
+		Col:      col,
+		Word:     tile.Word,
+		View:     &protocol.StateView{Team: tile.Team, Neutral: tile.Neutral, Bomb: tile.Bomb},
+		Hint:     hint,
+	}
+
+	if p := r.room.Players[playerID]; p != nil {
+		reveal.Nickname = p.Nickname
+	}
+
+	if len(r.reveals) >= maxGameLogReveals {
+		r.reveals = append(r.reveals[:0:0], r.reveals[len(r.reveals)-maxGameLogReveals+1:]...)
+	}
+	r.reveals = append(r.reveals, reveal)
+}
+
+// unlogReveal drops the most recent reveal, which was undone. If that reopened
+// the game, the log of the game finished before it is restored.
+//
+// Must be called on the room's goroutine.
+func (r *Room) unlogReveal(reopened bool) {
+	if len(r.reveals) != 0 {
+		r.reveals = r.reveals[:len(r.reveals)-1]
+	}
+	if reopened {
+		r.gameLog = r.prevGameLog
+		r.prevGameLog = nil
+	}
+}
+
+// hiddenTiles returns the cards on the board which haven't been revealed, so
+// that the one a hint reveals can be found.
+//
+// Must be called on the room's goroutine.
+func (r *Room) hiddenTiles(board int) [][2]int {
+	if board < 0 || board >= len(r.room.Boards) {
+		return nil
+	}
+
+	b := r.room.Boards[board]
+	var hidden [][2]int
+	for row := 0; row < b.Rows; row++ {
+		for col := 0; col < b.Cols; col++ {
+			if !b.Get(row, col).Revealed {
+				hidden = append(hidden, [2]int{row, col})
+			}
+		}
+	}
+	return hidden
+}
+
+// logHint logs the card a hint revealed, which is the one of the previously
+// hidden cards which isn't any longer.
+//
+// Must be called on the room's goroutine.
+func (r *Room) logHint(playerID game.PlayerID, board int, hidden [][2]int) {
+	b := r.room.Boards[board]
+	for _, pos := range hidden {
+		if b.Get(pos[0], pos[1]).Revealed {
+			r.logReveal(playerID, board, pos[0], pos[1], true)
+			return
+		}
+	}
+}
+
+// finishGameLog builds the log of the game which was just won, replacing that
+// of the previous game.
+//
+// Must be called on the room's goroutine.
+func (r *Room) finishGameLog() {
+	room := r.room
+	log := &protocol.GameLog{
+		Started: r.gameStart,
+		Ended:   r.gameEnd,
+		Ruleset: r.rulesetHash,
+		Players: []*protocol.GameLogPlayer{},
+		Reveals: append([]*protocol.GameLogReveal{}, r.reveals...),
+		Clues:   make([]*protocol.StateLoggedClue, len(room.ClueLog)),
+		Result:  r.createGameOver(),
+	}
+
+	for team, ids := range room.Teams {
+		for _, id := range ids {
+			p := room.Players[id]
+			if p == nil {
+				continue
+			}
+			log.Players = append(log.Players, &protocol.GameLogPlayer{
+				PlayerID:  id,
+				Nickname:  p.Nickname,
+				Team:      game.Team(team),
+				Spymaster: p.Spymaster,
+			})
+		}
+	}
+
+	for i, c := range room.ClueLog {
+		log.Clues[i] = protocolLoggedClue(c)
+	}
+
+	if !room.Tracker {
+		log.Words = make([][][]string, len(room.Boards))
+		for i, b := range room.Boards {
+			words := make([][]string, b.Rows)
+			for row := range words {
+				words[row] = make([]string, b.Cols)
+				for col := range words[row] {
+					words[row][col] = b.Get(row, col).Word
+				}
+			}
+			log.Words[i] = words
+		}
+	}
+
+	r.prevGameLog = r.gameLog
+	r.gameLog = log
+}
+
+// GameLog returns the log of the room's last finished game, which is nil until
+// a game has finished. Only players in the room may read it; inRoom is false
+// for anyone else, and the log is withheld.
+func (r *Room) GameLog(playerID game.PlayerID) (log *protocol.GameLog, inRoom bool) {
+	r.do(func() {
+		if r.room.Players[playerID] != nil {
+			log, inRoom = r.gameLog, true
+		}
+	})
+	return log, inRoom
+}
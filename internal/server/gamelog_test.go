@@ -0,0 +1,126 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func TestGameLog(t *testing.T) {
+	r := newTestRoom(t)
+	c := newFakeClock()
+	r.setClock(c)
+	r.addTestClient(t, "g0", 0, false)
+	r.addTestClient(t, "g1", 1, false)
+
+	r.testNote(t, "g0", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	start := c.Now()
+	r.addTestClient(t, "spy0", 0, true)
+	r.setTurn(0)
+
+	log, inRoom := r.GameLog("g0")
+	assert.Assert(t, inRoom)
+	assert.Assert(t, log == nil)
+
+	_, inRoom = r.GameLog("stranger")
+	assert.Assert(t, !inRoom)
+
+	r.testNote(t, "spy0", protocol.GiveClueMethod, &protocol.GiveClueParams{Word: "ANIMAL", Count: 2})
+	c.Advance(10 * time.Second)
+	r.revealOwn(t, "g0", 0)
+	c.Advance(20 * time.Second)
+	r.revealBomb(t, "g0")
+
+	log, _ = r.GameLog("g1")
+	assert.Assert(t, log != nil)
+	assert.Assert(t, log.Started.Equal(start))
+	assert.Assert(t, log.Ended.Equal(start.Add(30*time.Second)))
+	assert.Equal(t, len(log.Players), 3)
+	assert.DeepEqual(t, log.Clues, []*protocol.StateLoggedClue{{Team: 0, Word: "ANIMAL", Count: 2}})
+	assert.Equal(t, log.Result.Reason, "bomb")
+	assert.Equal(t, log.Result.Winner, game.Team(1))
+	assert.Equal(t, len(log.Result.Key), 1)
+
+	r.hold(func() {
+		b := r.room.Board
+		assert.Equal(t, len(log.Words), 1)
+		for row := 0; row < b.Rows; row++ {
+			for col := 0; col < b.Cols; col++ {
+				assert.Equal(t, log.Words[0][row][col], b.Get(row, col).Word)
+			}
+		}
+
+		assert.Equal(t, len(log.Reveals), 2)
+		for i, reveal := range log.Reveals {
+			assert.Equal(t, reveal.PlayerID, game.PlayerID("g0"))
+			assert.Equal(t, reveal.Nickname, "g0")
+			assert.Equal(t, reveal.Word, b.Get(reveal.Row, reveal.Col).Word)
+			assert.Assert(t, !reveal.Hint)
+			assert.Assert(t, reveal.Time.Equal(start.Add(time.Duration(10+i*20)*time.Second)))
+		}
+	})
+	assert.Equal(t, log.Reveals[0].View.Team, game.Team(0))
+	assert.Assert(t, log.Reveals[1].View.Bomb)
+
+	// The finished game's log is kept while the next is played.
+	r.testNote(t, "g0", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	r.setTurn(0)
+	r.revealOwn(t, "g0", 0)
+	next, _ := r.GameLog("g0")
+	assert.Assert(t, next == log)
+
+	// Until that one's finished, and replaces it.
+	r.revealBomb(t, "g0")
+	next, _ = r.GameLog("g0")
+	assert.Assert(t, next != log)
+	assert.Equal(t, len(next.Reveals), 2)
+	assert.Equal(t, len(next.Clues), 0)
+
+	// Undoing the reveal which finished it reopens the game, which hasn't
+	// finished any longer.
+	r.testNote(t, "g0", protocol.UndoMethod, &protocol.UndoParams{})
+	next, _ = r.GameLog("g0")
+	assert.Assert(t, next == log)
+
+	r.revealBomb(t, "g0")
+	next, _ = r.GameLog("g0")
+	assert.Equal(t, len(next.Reveals), 2)
+}
+
+func TestGameLogHint(t *testing.T) {
+	r := newTestRoom(t)
+	r.addTestClient(t, "g0", 0, false)
+	r.addTestClient(t, "g1", 1, false)
+	r.testNote(t, "g0", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{HintBudget: []int{1, 0}})
+	r.testNote(t, "g0", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	r.setTurn(0)
+
+	r.testNote(t, "g0", protocol.UseHintMethod, &protocol.UseHintParams{})
+	r.revealBomb(t, "g0")
+
+	log, _ := r.GameLog("g0")
+	assert.Equal(t, len(log.Reveals), 2)
+	hint := log.Reveals[0]
+	assert.Assert(t, hint.Hint)
+	assert.Assert(t, hint.View.Neutral)
+	r.hold(func() {
+		assert.Assert(t, r.room.Board.Get(hint.Row, hint.Col).Revealed)
+	})
+}
+
+func TestGameLogBounded(t *testing.T) {
+	r := newTestRoom(t)
+	r.addTestClient(t, "g0", 0, false)
+
+	r.hold(func() {
+		for i := 0; i <= maxGameLogReveals; i++ {
+			r.logReveal("g0", 0, 0, i%r.room.Board.Cols, false)
+		}
+
+		assert.Equal(t, len(r.reveals), maxGameLogReveals)
+		assert.Equal(t, r.reveals[0].Col, 1%r.room.Board.Cols)
+	})
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func TestGameOver(t *testing.T) {
+	r := newTestRoom(t)
+	c := newFakeClock()
+	r.setClock(c)
+	r.addTestClient(t, "g0", 0, false)
+	obs := r.addTestClient(t, "obs", 1, false)
+
+	r.testNote(t, "g0", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	assert.Assert(t, obs.lastState().RoomState.GameOver == nil)
+
+	r.hold(func() {
+		r.room.Turn = 0
+	})
+
+	c.Advance(5*time.Minute + 30*time.Second)
+	r.revealBomb(t, "g0")
+
+	over := obs.lastState().RoomState.GameOver
+	assert.Assert(t, over != nil)
+	assert.Equal(t, over.Winner, game.Team(1))
+	assert.Equal(t, over.Reason, "bomb")
+	assert.Equal(t, over.Seconds, int64(330))
+	r.hold(func() {
+		assert.DeepEqual(t, over.WordsLeft, [][]int{r.room.Board.WordCounts})
+
+		// The key has every card, whether it was revealed or not.
+		b := r.room.Board
+		assert.Equal(t, len(over.Key), 1)
+		assert.Equal(t, len(over.Key[0]), b.Rows)
+		for row := 0; row < b.Rows; row++ {
+			assert.Equal(t, len(over.Key[0][row]), b.Cols)
+			for col := 0; col < b.Cols; col++ {
+				tile := b.Get(row, col)
+				assert.DeepEqual(t, over.Key[0][row][col], &protocol.StateView{Team: tile.Team, Neutral: tile.Neutral, Bomb: tile.Bomb})
+			}
+		}
+	})
+
+	// A new game clears it.
+	r.testNote(t, "g0", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	assert.Assert(t, obs.lastState().RoomState.GameOver == nil)
+}
+
+func TestGameOverWords(t *testing.T) {
+	r := newTestRoom(t)
+	r.addTestClient(t, "g0", 0, false)
+	obs := r.addTestClient(t, "obs", 1, false)
+	r.hold(func() {
+		r.room.Turn = 0
+		r.room.Board.WordCounts[0] = 1
+	})
+
+	r.revealOwn(t, "g0", 0)
+	over := obs.lastState().RoomState.GameOver
+	assert.Equal(t, over.Winner, game.Team(0))
+	assert.Equal(t, over.Reason, "words")
+	assert.Equal(t, over.WordsLeft[0][0], 0)
+}
+
+func TestGameOverTracker(t *testing.T) {
+	r := newTestTrackerRoom(t)
+	obs := r.addTestClient(t, "obs", 1, false)
+
+	r.testNote(t, r.host(), protocol.DeclareWinnerMethod, &protocol.DeclareWinnerParams{Team: 1})
+	over := obs.lastState().RoomState.GameOver
+	assert.Equal(t, over.Winner, game.Team(1))
+	assert.Equal(t, over.Reason, "declared")
+	assert.Assert(t, over.Key == nil)
+	assert.Assert(t, over.WordsLeft == nil)
+}
@@ -0,0 +1,221 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+// newGauntletTestRoom returns a room playing three 4x4 boards, with a
+// spymaster and guesser on team 0.
+func newGauntletTestRoom(t *testing.T) (r *Room, spy, guesser *testClient) {
+	t.Helper()
+
+	r = newTestRoom(t)
+	r.addTestClient(t, "host", 1, false)
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{
+		Boards: intPtr(3),
+		Rows:   intPtr(4),
+		Cols:   intPtr(4),
+	})
+	r.testNote(t, "host", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+
+	spy = r.addTestClient(t, "spy", 0, true)
+	guesser = r.addTestClient(t, "guesser", 0, false)
+	r.setTurn(0)
+	return r, spy, guesser
+}
+
+func TestSingleBoardWireCompatible(t *testing.T) {
+	r := newTestRoom(t)
+	c := r.addTestClient(t, "host", 0, false)
+	r.testNote(t, "host", protocol.ChangeTeamMethod, &protocol.ChangeTeamParams{Team: 1})
+
+	raw, err := json.Marshal(c.lastState().RoomState)
+	assert.NilError(t, err)
+
+	var fields map[string]json.RawMessage
+	assert.NilError(t, json.Unmarshal(raw, &fields))
+	_, hasBoards := fields["boards"]
+	_, hasTurnBoard := fields["turnBoard"]
+	assert.Assert(t, !hasBoards)
+	assert.Assert(t, !hasTurnBoard)
+
+	// Commands without a board index play the only board.
+	var params protocol.RevealParams
+	assert.NilError(t, json.Unmarshal([]byte(`{"row":1,"col":2}`), &params))
+	assert.Equal(t, params.Board, 0)
+}
+
+func TestGauntletState(t *testing.T) {
+	r, spy, guesser := newGauntletTestRoom(t)
+	r.testNote(t, "spy", protocol.GiveClueMethod, &protocol.GiveClueParams{Board: 2, Word: "ANIMAL", Count: 1})
+
+	for _, c := range []*testClient{spy, guesser} {
+		state := c.lastState().RoomState
+		assert.Equal(t, len(state.Boards), 3)
+		assert.DeepEqual(t, state.Board, state.Boards[0].Board)
+		assert.DeepEqual(t, state.WordsLeft, state.Boards[0].WordsLeft)
+		assert.DeepEqual(t, state.BoardOptions, &protocol.StateBoardOptions{Count: 3, Rows: 4, Cols: 4, Bombs: 1})
+		assert.Equal(t, *state.TurnBoard, 2)
+		assert.Equal(t, state.Clue.Board, 2)
+
+		// Every board is redacted the same way.
+		hidden := 0
+		for _, b := range state.Boards {
+			assert.Equal(t, len(b.Board), 4)
+			for _, row := range b.Board {
+				for _, tile := range row {
+					if tile.View == nil {
+						hidden++
+					}
+				}
+			}
+		}
+
+		if c == spy {
+			assert.Equal(t, hidden, 0)
+		} else {
+			assert.Equal(t, hidden, 3*16)
+		}
+	}
+}
+
+func TestGauntletReveal(t *testing.T) {
+	r, _, guesser := newGauntletTestRoom(t)
+
+	row, col, found := 0, 0, false
+	r.hold(func() {
+		b := r.room.Boards[1]
+		for row = 0; row < b.Rows; row++ {
+			for col = 0; col < b.Cols; col++ {
+				if tile := b.Get(row, col); !tile.Neutral && !tile.Bomb && tile.Team == 0 {
+					found = true
+					return
+				}
+			}
+		}
+	})
+	if !found {
+		t.Fatal("no tile")
+	}
+
+	r.testNote(t, "guesser", protocol.RevealMethod, &protocol.RevealParams{Board: 1, Row: row, Col: col})
+
+	state := guesser.lastState().RoomState
+	assert.Assert(t, state.Boards[1].Board[row][col].Revealed)
+	assert.Assert(t, !state.Board[row][col].Revealed)
+	r.hold(func() {
+		assert.Equal(t, state.Boards[1].WordsLeft[0], r.room.Boards[1].WordCounts[0])
+	})
+}
+
+func TestGauntletOptionsInvalid(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+
+	for _, params := range []*protocol.UpdateOptionsParams{
+		{Boards: intPtr(game.MaxBoards + 1)},
+		{Rows: intPtr(2)},
+		{Rows: intPtr(4), Cols: intPtr(5), Boards: intPtr(game.MaxBoards)},
+		{Cols: intPtr(game.MaxBoardSide + 1)},
+	} {
+		r.testNote(t, "host", protocol.UpdateOptionsMethod, params)
+	}
+
+	errs := host.errors()
+	assert.Equal(t, len(errs), 3)
+	for _, e := range errs {
+		assert.Equal(t, e.Code, "invalidOptions")
+	}
+	r.hold(func() {
+		assert.Equal(t, r.room.NumBoards, game.MaxBoards)
+		assert.Equal(t, r.room.Rows, 4)
+		assert.Equal(t, r.room.Cols, 5)
+	})
+}
+
+func TestNewGameTooFewWords(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{Rows: intPtr(6), Cols: intPtr(6)})
+	r.testNote(t, "host", protocol.AddPacksMethod, addPacksParams(testPackParam{Name: "small", Words: packWords("w", 30)}))
+
+	// Leave only the small pack enabled.
+	custom := r.packCount() - 1
+	r.testNote(t, "host", protocol.ChangePackMethod, &protocol.ChangePackParams{Num: custom, Enable: true})
+	for i := 0; i < custom; i++ {
+		r.testNote(t, "host", protocol.ChangePackMethod, &protocol.ChangePackParams{Num: i, Enable: false})
+	}
+
+	// Not even forcing it deals a board.
+	games := r.games()
+	r.testNote(t, "host", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	assert.Equal(t, r.games(), games)
+
+	errs := host.errors()
+	assert.Equal(t, len(errs), 1)
+	assert.Equal(t, errs[0].Code, "tooFewWords")
+	assert.Equal(t, *errs[0].Limit, 36)
+	assert.Equal(t, errs[0].Message, "The enabled packs have 30 words, but 6x6 boards need 36.")
+
+	r.testNote(t, "host", protocol.ChangePackMethod, &protocol.ChangePackParams{Num: 0, Enable: true})
+	r.testNote(t, "host", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	assert.Equal(t, r.games(), games+1)
+	r.hold(func() {
+		assert.Equal(t, r.room.Board.Rows, 6)
+	})
+	assert.Equal(t, len(host.errors()), 1)
+}
+
+func countBombs(board [][]*protocol.StateTile) int {
+	count := 0
+	for _, row := range board {
+		for _, tile := range row {
+			if tile.View != nil && tile.View.Bomb {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func TestBombsOption(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+	spy := r.addTestClient(t, "spy", 1, true)
+
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{Bombs: intPtr(3)})
+	assert.DeepEqual(t, host.optionsChanged(), [][]string{{"bombs"}})
+	assert.Equal(t, host.lastState().RoomState.BoardOptions.Bombs, 3)
+
+	// It applies from the next game, which spymasters see every bomb of.
+	assert.Equal(t, countBombs(spy.lastState().RoomState.Board), 1)
+	r.testNote(t, "host", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	r.testNote(t, "spy", protocol.ChangeRoleMethod, &protocol.ChangeRoleParams{Spymaster: true})
+	assert.Equal(t, countBombs(spy.lastState().RoomState.Board), 3)
+	assert.Equal(t, countBombs(host.lastState().RoomState.Board), 0)
+
+	// Shrinking the board leaves no room for them, unless there are fewer.
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{Rows: intPtr(3), Cols: intPtr(3)})
+	errs := host.errors()
+	assert.Equal(t, len(errs), 1)
+	assert.Equal(t, errs[0].Message, "3x3 boards don't have room for 3 bombs.")
+
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{Rows: intPtr(3), Cols: intPtr(3), Bombs: intPtr(2)})
+	assert.Equal(t, len(host.errors()), 1)
+	assert.DeepEqual(t, host.lastState().RoomState.BoardOptions, &protocol.StateBoardOptions{Count: 1, Rows: 3, Cols: 3, Bombs: 2})
+
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{Bombs: intPtr(game.MaxBombs + 1)})
+	assert.Equal(t, len(host.errors()), 2)
+
+	// No bombs at all is allowed.
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{Bombs: intPtr(0)})
+	r.testNote(t, "host", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	r.testNote(t, "spy", protocol.ChangeRoleMethod, &protocol.ChangeRoleParams{Spymaster: true})
+	assert.Equal(t, len(host.errors()), 2)
+	assert.Equal(t, countBombs(spy.lastState().RoomState.Board), 0)
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"github.com/zikaeroh/codies/internal/game"
+	"nhooyr.io/websocket"
+)
+
+// When a player's connection ends, their seat is held for the room's
+// seatGrace, so that a laptop going to sleep doesn't cost them their team or
+// their place as spymaster. They stay in the room, marked disconnected, and
+// nothing waits on them. Reconnecting within the grace with their latest
+// resume token reclaims the seat; the player ID alone isn't enough, being in
+// every state. Once the grace has passed, they're removed as if they'd left
+// straight away.
+//
+// A client which slept may reconnect before the server notices its old
+// connection is gone. Reclaiming the seat closes the old connection, whose
+// end then leaves the seat alone.
+
+// closeReplaced closes a connection whose seat was reclaimed by a newer one.
+// The client has usually moved on to the newer one already.
+const closeReplaced websocket.StatusCode = 4411
+
+var errSeatReclaimed = &game.Error{
+	Code:    "seatReclaimed",
+	Message: "Your seat was reclaimed by another connection.",
+}
+
+// seatHolder returns the player a resume token was last sent to, and their
+// nickname, if the room holds seats and they're still in it.
+func (r *Room) seatHolder(token string) (game.PlayerID, string) {
+	if token == "" || r.seatGrace <= 0 {
+		return "", ""
+	}
+
+	hash := hashResumeToken(token)
+
+	var holder game.PlayerID
+	nickname := ""
+	r.do(func() {
+		for playerID, h := range r.resumeTokens {
+			if h != hash {
+				continue
+			}
+			if p := r.room.Players[playerID]; p != nil {
+				holder, nickname = playerID, p.Nickname
+				return
+			}
+		}
+	})
+	return holder, nickname
+}
+
+// disconnect is called once a player's connection has ended. Their seat is
+// held if the room holds seats; otherwise, they leave. Connections which were
+// replaced, or whose players were kicked or banned, leave nothing behind.
+func (r *Room) disconnect(playerID game.PlayerID, w *connWriter) {
+	r.do(func() {
+		if r.conns[playerID] != w {
+			return
+		}
+
+		roster := r.currentState()
+		if !r.holdSeat(playerID) {
+			r.keepStoppedSeat(playerID)
+			r.removePlayer(playerID)
+		}
+		r.sendRoster(roster, "")
+	})
+}
+
+// holdSeat drops a player's connection, but keeps them in the room until the
+// grace has passed. Seats aren't held in rooms which are closing, or as the
+// server stops.
+//
+// Must be called on the room's goroutine.
+func (r *Room) holdSeat(playerID game.PlayerID) bool {
+	if r.seatGrace <= 0 || r.closing() || r.mergedInto != "" || r.ctx.Err() != nil || r.room.Players[playerID] == nil {
+		return false
+	}
+
+	r.dropConn(playerID)
+	r.room.SetDisconnected(playerID, true)
+
+	// The timer is compared on the room's goroutine, which set it, so that
+	// one which was stopped too late can't remove a player who reclaimed
+	// their seat.
+	var t timer
+	t = r.after(r.seatGrace, func() {
+		if r.held[playerID] == t {
+			r.expireSeat(playerID)
+		}
+	})
+	r.held[playerID] = t
+	return true
+}
+
+// expireSeat removes a player whose seat was held for too long.
+//
+// Must be called on the room's goroutine.
+func (r *Room) expireSeat(playerID game.PlayerID) {
+	roster := r.currentState()
+	r.removePlayer(playerID)
+	r.sendRoster(roster, "")
+}
+
+// replaceConn readies a held seat for the connection reclaiming it, closing
+// the old connection if it's still open.
+//
+// Must be called on the room's goroutine.
+func (r *Room) replaceConn(playerID game.PlayerID) {
+	if w := r.conns[playerID]; w != nil {
+		w.close(closeReplaced, "replaced by a newer connection")
+	}
+	r.dropConn(playerID)
+	r.releaseSeat(playerID)
+}
+
+// dropConn forgets a player's connection, but not the player. Their address
+// is kept, so that they can still be banned.
+//
+// Must be called on the room's goroutine.
+func (r *Room) dropConn(playerID game.PlayerID) {
+	delete(r.players, playerID)
+	delete(r.conns, playerID)
+	delete(r.bytes, playerID)
+	delete(r.deltas, playerID)
+	delete(r.info, playerID)
+}
+
+// releaseSeat stops holding a player's seat, if it's held.
+//
+// Must be called on the room's goroutine.
+func (r *Room) releaseSeat(playerID game.PlayerID) {
+	if t := r.held[playerID]; t != nil {
+		t.Stop()
+		delete(r.held, playerID)
+	}
+}
+
+// releaseSeats stops holding every seat, as the room closes.
+//
+// Must be called on the room's goroutine.
+func (r *Room) releaseSeats() {
+	for playerID := range r.held {
+		r.releaseSeat(playerID)
+	}
+}
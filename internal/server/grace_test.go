@@ -0,0 +1,187 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+	"nhooyr.io/websocket"
+)
+
+// newGraceRoom creates a room which holds seats for a minute, on a fake
+// clock, with alice and bob in it. It returns alice's connection, and bob's
+// with his ID and resume token. Bob is a spymaster.
+func newGraceRoom(t *testing.T) (*Room, *fakeClock, *websocket.Conn, *websocket.Conn, game.PlayerID, string) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	s := NewServer(Options{SeatGrace: time.Minute})
+	go s.Run(ctx) //nolint:errcheck
+
+	r, err := s.CreateRoom(ctx, "grace", "pass")
+	assert.NilError(t, err)
+	c := newFakeClock()
+	r.hold(func() {
+		r.clock = c
+	})
+
+	alice, _ := dialResume(t, r, "alice", "")
+	bob := dialTestRoom(t, r, ConnOptions{Nickname: "bob"})
+	state, token := readJoined(t, bob)
+
+	r.hold(func() {
+		assert.NilError(t, r.room.ChangeRole(state.PlayerID, true))
+	})
+
+	return r, c, alice, bob, state.PlayerID, token
+}
+
+// readJoined reads the state and resume token a player is sent as they join,
+// in whichever order they come.
+func readJoined(t *testing.T, c *websocket.Conn) (*protocol.State, string) {
+	t.Helper()
+
+	var state *protocol.State
+	token := ""
+	for state == nil || token == "" {
+		method, raw := readRawNote(t, c)
+		switch method {
+		case "state":
+			state = &protocol.State{}
+			assert.NilError(t, json.Unmarshal(raw, state))
+		case "resumeToken":
+			var rt protocol.ResumeToken
+			assert.NilError(t, json.Unmarshal(raw, &rt))
+			token = rt.Token
+		}
+	}
+	return state, token
+}
+
+// readUntilBob reads states until one shows bob as wanted, returning it.
+func readUntilBob(t *testing.T, c *websocket.Conn, want func(p *protocol.StatePlayer) bool) *protocol.State {
+	t.Helper()
+
+	for {
+		state := readState(t, c)
+		if p, _ := findStatePlayer(state, "bob"); want(p) {
+			return state
+		}
+	}
+}
+
+func bobDisconnected(p *protocol.StatePlayer) bool { return p != nil && p.Disconnected }
+func bobConnected(p *protocol.StatePlayer) bool    { return p != nil && !p.Disconnected }
+func bobGone(p *protocol.StatePlayer) bool         { return p == nil }
+
+func TestSeatHeld(t *testing.T) {
+	r, _, alice, bob, bobID, token := newGraceRoom(t)
+
+	var team game.Team
+	r.hold(func() {
+		team = r.room.Players[bobID].Team
+	})
+
+	// Bob's seat is held, and shown as disconnected.
+	bob.Close(websocket.StatusNormalClosure, "")
+	readUntilBob(t, alice, bobDisconnected)
+
+	r.hold(func() {
+		p := r.room.Players[bobID]
+		assert.Assert(t, p != nil)
+		assert.Assert(t, p.Spymaster)
+		assert.Equal(t, p.Team, team)
+		assert.Equal(t, len(r.players), 1)
+		assert.Equal(t, len(r.resumeRoom().Seats), 2) // Kept should the server stop.
+	})
+
+	// The player ID alone doesn't reclaim it.
+	mallory := dialTestRoom(t, r, ConnOptions{Nickname: "mallory", ResumeToken: string(bobID)})
+	assert.Assert(t, readState(t, mallory).PlayerID != bobID)
+
+	// Reconnecting with the token does, with a fresh state.
+	bob = dialTestRoom(t, r, ConnOptions{Nickname: "robert", ResumeToken: token})
+	state, newToken := readJoined(t, bob)
+	assert.Equal(t, state.PlayerID, bobID)
+	assert.Assert(t, newToken != token)
+	p2, got := findStatePlayer(state, "bob")
+	assert.Assert(t, p2 != nil)
+	assert.Assert(t, p2.Spymaster)
+	assert.Assert(t, !p2.Disconnected)
+	assert.Equal(t, got, team)
+
+	readUntilBob(t, alice, bobConnected)
+}
+
+func TestSeatGraceExpires(t *testing.T) {
+	r, c, alice, bob, bobID, token := newGraceRoom(t)
+
+	bob.Close(websocket.StatusNormalClosure, "")
+	readUntilBob(t, alice, bobDisconnected)
+
+	c.Advance(time.Minute - time.Second)
+	r.hold(func() {
+		assert.Assert(t, r.room.Players[bobID] != nil)
+	})
+
+	// Once the grace passes, bob's removed, and his token claims nothing.
+	c.Advance(time.Second)
+	readUntilBob(t, alice, bobGone)
+
+	bob = dialTestRoom(t, r, ConnOptions{Nickname: "bob", ResumeToken: token})
+	state := readState(t, bob)
+	assert.Assert(t, state.PlayerID != bobID)
+	p, _ := findStatePlayer(state, "bob")
+	assert.Assert(t, !p.Spymaster)
+}
+
+func TestSeatReclaimReplacesConn(t *testing.T) {
+	r, _, alice, bob, bobID, token := newGraceRoom(t)
+
+	// The server hasn't noticed bob's old connection is gone.
+	again := dialTestRoom(t, r, ConnOptions{Nickname: "bob", ResumeToken: token})
+	assert.Equal(t, readState(t, again).PlayerID, bobID)
+
+	code, hint := readCloseHint(t, bob)
+	assert.Equal(t, code, closeReplaced)
+	assert.Assert(t, !hint.Retry)
+
+	// The old connection ending leaves bob alone.
+	writeNote(t, again, protocol.ChatMethod, 0, &protocol.ChatParams{Text: "still here"})
+	readNote(t, alice, "chat", nil)
+	r.hold(func() {
+		assert.Equal(t, len(r.players), 2)
+		assert.Assert(t, !r.room.Players[bobID].Disconnected)
+		assert.Equal(t, len(r.held), 0)
+	})
+}
+
+func TestSeatHeldKicked(t *testing.T) {
+	r, _, alice, bob, bobID, _ := newGraceRoom(t)
+
+	bob.Close(websocket.StatusNormalClosure, "")
+	readUntilBob(t, alice, bobDisconnected)
+
+	r.hold(func() {
+		r.kick(r.room.Host, bobID)
+		assert.Assert(t, r.room.Players[bobID] == nil)
+		assert.Equal(t, len(r.held), 0)
+	})
+}
+
+func TestSeatGraceDisabled(t *testing.T) {
+	r := newTestRoom(t)
+	alice := dialTestRoom(t, r, ConnOptions{Nickname: "alice"})
+	readState(t, alice)
+	bob := dialTestRoom(t, r, ConnOptions{Nickname: "bob"})
+	readState(t, bob)
+
+	bob.Close(websocket.StatusNormalClosure, "")
+	readUntilBob(t, alice, bobGone)
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func TestGuessLimit(t *testing.T) {
+	r := newTestRoom(t)
+	r.addTestClient(t, "spy0", 0, true)
+	g0 := r.addTestClient(t, "g0", 0, false)
+	obs := r.addTestClient(t, "obs", 1, false)
+	r.setTurn(0)
+
+	r.testNote(t, "spy0", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{EnforceGuessLimit: boolPtr(true)})
+	assert.DeepEqual(t, g0.optionsChanged(), [][]string{{"enforceGuessLimit"}})
+
+	state := obs.lastState().RoomState
+	assert.Assert(t, state.EnforceGuessLimit)
+	assert.Assert(t, state.GuessesLeft == nil)
+
+	r.testNote(t, "spy0", protocol.GiveClueMethod, &protocol.GiveClueParams{Word: "ANIMAL", Count: 1})
+	assert.Equal(t, *obs.lastState().RoomState.GuessesLeft, 2)
+
+	r.revealOwn(t, "g0", 0)
+	assert.Equal(t, *obs.lastState().RoomState.GuessesLeft, 1)
+
+	// The last guess ends the turn, and everyone is sent the next one.
+	r.revealOwn(t, "g0", 0)
+	state = obs.lastState().RoomState
+	assert.Equal(t, state.Turn, game.Team(1))
+	assert.Assert(t, state.Clue == nil)
+	assert.Assert(t, state.GuessesLeft == nil)
+}
+
+func TestGuessLimitTracker(t *testing.T) {
+	r := newTestTrackerRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{EnforceGuessLimit: boolPtr(true)})
+	errs := host.errors()
+	assert.Equal(t, len(errs), 1)
+	assert.Equal(t, errs[0].Code, "invalidOptions")
+	r.hold(func() {
+		assert.Assert(t, !r.room.EnforceGuessLimit)
+	})
+}
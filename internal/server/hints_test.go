@@ -0,0 +1,53 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func TestHints(t *testing.T) {
+	r := newTestRoom(t)
+	c := r.addTestClient(t, "host", 0, false)
+	r.addTestClient(t, "other", 1, false)
+
+	r.testNote(t, "host", protocol.ChangeTeamMethod, &protocol.ChangeTeamParams{Team: 1})
+	assert.Assert(t, c.lastState().RoomState.Hints == nil)
+
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{HintBudget: []int{1, 0}})
+	assert.DeepEqual(t, c.lastState().RoomState.Hints, &protocol.StateHints{
+		Budget: []int{1, 0},
+		Left:   []int{0, 0},
+		Used:   []int{0, 0},
+	})
+
+	r.testNote(t, "host", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	r.testNote(t, "host", protocol.ChangeTeamMethod, &protocol.ChangeTeamParams{Team: 0})
+	r.setTurn(0)
+
+	r.testNote(t, "host", protocol.UseHintMethod, &protocol.UseHintParams{})
+	assert.DeepEqual(t, c.lastState().RoomState.Hints, &protocol.StateHints{
+		Budget: []int{1, 0},
+		Left:   []int{0, 0},
+		Used:   []int{1, 0},
+	})
+
+	r.testNote(t, "host", protocol.UseHintMethod, &protocol.UseHintParams{})
+	errs := c.errors()
+	assert.Equal(t, len(errs), 1)
+	assert.Equal(t, errs[0].Code, "noHints")
+}
+
+func TestHintBudgetInvalid(t *testing.T) {
+	r := newTestRoom(t)
+	c := r.addTestClient(t, "host", 0, false)
+
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{HintBudget: []int{1}})
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{HintBudget: []int{4, 0}})
+
+	errs := c.errors()
+	assert.Equal(t, len(errs), 2)
+	assert.Equal(t, errs[0].Code, "invalidOptions")
+	assert.Equal(t, len(r.AuditLog()), 0)
+}
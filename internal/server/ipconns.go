@@ -0,0 +1,120 @@
+package server
+
+import (
+	"errors"
+	"net"
+	"sort"
+	"sync"
+)
+
+// MaxConnsPerIP is the default number of WebSocket connections which may be
+// open at once from a single IP.
+const MaxConnsPerIP = 50
+
+// ErrTooManyConns is returned when an IP already has too many connections.
+var ErrTooManyConns = errors.New("server: too many connections from this address")
+
+// IPConns is a count of the connections from a single IP.
+type IPConns struct {
+	IP     string `json:"ip"`
+	Conns  int    `json:"conns"`
+	Exempt bool   `json:"exempt"`
+}
+
+// ipConns counts live connections by client IP.
+type ipConns struct {
+	max   int // Zero is unlimited.
+	allow []*net.IPNet
+
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func newIPConns(max int, allow []*net.IPNet) *ipConns {
+	return &ipConns{
+		max:    max,
+		allow:  allow,
+		counts: make(map[string]int),
+	}
+}
+
+func (c *ipConns) exempt(ip net.IP) bool {
+	for _, n := range c.allow {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// acquire counts a new connection from ip. The returned release must be
+// called once the connection has been torn down; calling it more than once
+// is harmless.
+func (c *ipConns) acquire(ip net.IP) (release func(), err error) {
+	key := ip.String()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.max > 0 && c.counts[key] >= c.max && !c.exempt(ip) {
+		metricConnsRejected.Inc()
+		return nil, ErrTooManyConns
+	}
+
+	c.counts[key]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+
+			if c.counts[key]--; c.counts[key] <= 0 {
+				delete(c.counts, key)
+			}
+		})
+	}, nil
+}
+
+// top returns up to n IPs with the most connections, most first.
+func (c *ipConns) top(n int) []IPConns {
+	c.mu.Lock()
+	list := make([]IPConns, 0, len(c.counts))
+	for ip, count := range c.counts {
+		list = append(list, IPConns{IP: ip, Conns: count})
+	}
+	c.mu.Unlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Conns != list[j].Conns {
+			return list[i].Conns > list[j].Conns
+		}
+		return list[i].IP < list[j].IP
+	})
+
+	if len(list) > n {
+		list = list[:n]
+	}
+
+	for i := range list {
+		list[i].Exempt = c.exempt(net.ParseIP(list[i].IP))
+	}
+	return list
+}
+
+// AcquireConn counts a new connection from ip, failing with ErrTooManyConns if
+// the IP is at its limit and not allowlisted. The returned release must be
+// called when the connection ends.
+func (s *Server) AcquireConn(ip net.IP) (release func(), err error) {
+	return s.ipConns.acquire(ip)
+}
+
+// TopIPs lists up to n IPs with the most open connections.
+func (s *Server) TopIPs(n int) []IPConns {
+	return s.ipConns.top(n)
+}
+
+// MaxConnsPerIP returns the per-IP connection limit, or zero if unlimited.
+func (s *Server) MaxConnsPerIP() int {
+	return s.ipConns.max
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"net"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestIPConnsLimit(t *testing.T) {
+	c := newIPConns(2, nil)
+	ip := net.ParseIP("192.0.2.1")
+
+	r1, err := c.acquire(ip)
+	assert.NilError(t, err)
+	_, err = c.acquire(ip)
+	assert.NilError(t, err)
+
+	_, err = c.acquire(ip)
+	assert.Equal(t, err, ErrTooManyConns)
+
+	// Other IPs are counted separately.
+	_, err = c.acquire(net.ParseIP("192.0.2.2"))
+	assert.NilError(t, err)
+
+	r1()
+	_, err = c.acquire(ip)
+	assert.NilError(t, err)
+}
+
+func TestIPConnsAllowlist(t *testing.T) {
+	_, venue, err := net.ParseCIDR("198.51.100.0/24")
+	assert.NilError(t, err)
+
+	c := newIPConns(1, []*net.IPNet{venue})
+
+	for i := 0; i < 10; i++ {
+		_, err := c.acquire(net.ParseIP("198.51.100.7"))
+		assert.NilError(t, err)
+	}
+
+	_, err = c.acquire(net.ParseIP("198.51.101.7"))
+	assert.NilError(t, err)
+	_, err = c.acquire(net.ParseIP("198.51.101.7"))
+	assert.Equal(t, err, ErrTooManyConns)
+
+	top := c.top(1)
+	assert.DeepEqual(t, top, []IPConns{{IP: "198.51.100.7", Conns: 10, Exempt: true}})
+}
+
+func TestIPConnsRelease(t *testing.T) {
+	c := newIPConns(0, nil)
+	ip := net.ParseIP("2001:db8::1")
+
+	releases := make([]func(), 5)
+	for i := range releases {
+		var err error
+		releases[i], err = c.acquire(ip)
+		assert.NilError(t, err)
+	}
+	assert.DeepEqual(t, c.top(10), []IPConns{{IP: "2001:db8::1", Conns: 5}})
+
+	// Releasing twice doesn't drift the count.
+	releases[0]()
+	releases[0]()
+	assert.DeepEqual(t, c.top(10), []IPConns{{IP: "2001:db8::1", Conns: 4}})
+
+	for _, release := range releases[1:] {
+		release()
+	}
+	assert.Equal(t, len(c.top(10)), 0)
+}
+
+func TestIPConnsTop(t *testing.T) {
+	c := newIPConns(0, nil)
+
+	for ip, n := range map[string]int{"192.0.2.1": 1, "192.0.2.2": 3, "192.0.2.3": 2} {
+		for i := 0; i < n; i++ {
+			_, err := c.acquire(net.ParseIP(ip))
+			assert.NilError(t, err)
+		}
+	}
+
+	assert.DeepEqual(t, c.top(2), []IPConns{
+		{IP: "192.0.2.2", Conns: 3},
+		{IP: "192.0.2.3", Conns: 2},
+	})
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"math"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"nhooyr.io/websocket"
+)
+
+// By default, a room admits defaultJoinBurst connections at once, then one
+// every defaultJoinInterval; see Options.JoinBurst and JoinInterval. Friends
+// joining a room never come close; a room shared with a crowd is kept from
+// being swamped, since every join is broadcast to everyone already there.
+const (
+	defaultJoinBurst    = 20
+	defaultJoinInterval = 200 * time.Millisecond
+)
+
+const closeRoomBusy websocket.StatusCode = 4429
+
+// tokenBucket holds up to burst tokens, gaining one every interval. The zero
+// value starts full.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take takes a token, or returns how long until one is available.
+func (b *tokenBucket) take(now time.Time, burst int, interval time.Duration) (retryAfter time.Duration, ok bool) {
+	if b.last.IsZero() {
+		b.tokens = float64(burst)
+	} else {
+		b.tokens = math.Min(float64(burst), b.tokens+float64(now.Sub(b.last))/float64(interval))
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+
+	return time.Duration((1 - b.tokens) * float64(interval)), false
+}
+
+// refund gives back a token which was taken but not used.
+func (b *tokenBucket) refund(burst int) {
+	b.tokens = math.Min(float64(burst), b.tokens+1)
+}
+
+// joinLimiter spaces out admissions to a room, burst at once, then one every
+// interval. The zero value uses the defaults, and starts full.
+type joinLimiter struct {
+	burst    int           // If zero, defaultJoinBurst.
+	interval time.Duration // If zero, defaultJoinInterval.
+	bucket   tokenBucket
+}
+
+func (l *joinLimiter) limits() (int, time.Duration) {
+	burst, interval := l.burst, l.interval
+	if burst == 0 {
+		burst = defaultJoinBurst
+	}
+	if interval == 0 {
+		interval = defaultJoinInterval
+	}
+	return burst, interval
+}
+
+// admit takes a token, or returns how long until one is available.
+func (l *joinLimiter) admit(now time.Time) (retryAfter time.Duration, ok bool) {
+	burst, interval := l.limits()
+	return l.bucket.take(now, burst, interval)
+}
+
+// admitJoin checks that the room can take another connection right now.
+//
+// Must be called on the room's goroutine.
+func (r *Room) admitJoin() error {
+	retryAfter, ok := r.joins.admit(r.clock.Now())
+	if ok {
+		return nil
+	}
+
+	metricJoinsThrottled.Inc()
+
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	return &game.Error{
+		Code:       "roomBusy",
+		Message:    "Too many people are joining this room; try again shortly.",
+		RetryAfter: &seconds,
+	}
+}
@@ -0,0 +1,257 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"go.uber.org/atomic"
+	"gotest.tools/v3/assert"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+)
+
+func TestJoinLimiter(t *testing.T) {
+	var l joinLimiter
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < defaultJoinBurst; i++ {
+		_, ok := l.admit(now)
+		assert.Assert(t, ok, "join %d", i)
+	}
+
+	retryAfter, ok := l.admit(now)
+	assert.Assert(t, !ok)
+	assert.Equal(t, retryAfter, defaultJoinInterval)
+
+	// Once the burst is spent, joins are spaced out.
+	now = now.Add(defaultJoinInterval)
+	_, ok = l.admit(now)
+	assert.Assert(t, ok)
+	_, ok = l.admit(now)
+	assert.Assert(t, !ok)
+
+	// And the burst comes back, but no more than that.
+	now = now.Add(time.Hour)
+	for i := 0; i < defaultJoinBurst; i++ {
+		_, ok := l.admit(now)
+		assert.Assert(t, ok, "join %d", i)
+	}
+	_, ok = l.admit(now)
+	assert.Assert(t, !ok)
+}
+
+func TestJoinFlood(t *testing.T) {
+	r := newTestRoom(t)
+	c := newFakeClock()
+	r.setClock(c)
+
+	host := r.addTestClient(t, "host", 0, false)
+
+	for i := 0; i < defaultJoinBurst; i++ {
+		conn := dialTestRoom(t, r, ConnOptions{Nickname: fmt.Sprint("player", i)})
+		readNote(t, conn, "state", nil)
+	}
+
+	// The rest of the flood is turned away, with a time to retry.
+	for i := 0; i < 5; i++ {
+		conn := dialTestRoom(t, r, ConnOptions{Nickname: fmt.Sprint("late", i)})
+
+		var e protocol.Error
+		readNote(t, conn, "error", &e)
+		assert.Equal(t, e.Code, "roomBusy")
+		assert.Assert(t, e.RetryAfter != nil)
+		assert.Equal(t, *e.RetryAfter, 1)
+		assert.Equal(t, readClose(t, conn), closeRoomBusy)
+	}
+
+	// Players already in the room only saw the admitted joins.
+	r.hold(func() {
+		assert.Equal(t, len(r.players), defaultJoinBurst+1)
+		assert.Assert(t, len(host.notes) <= defaultJoinBurst)
+	})
+
+	c.Advance(defaultJoinInterval)
+	conn := dialTestRoom(t, r, ConnOptions{Nickname: "patient"})
+	readNote(t, conn, "state", nil)
+}
+
+func TestJoinFloodMirrors(t *testing.T) {
+	r := newTestRoom(t)
+	c := newFakeClock()
+	r.setClock(c)
+
+	host := r.addTestClient(t, "host", 0, false)
+	token := r.testMirrorToken(t, host)
+
+	r.hold(func() {
+		for i := 0; i < defaultJoinBurst; i++ {
+			_, ok := r.joins.admit(c.Now())
+			assert.Assert(t, ok)
+		}
+	})
+
+	conn := dialTestMirror(t, r, token)
+	assert.Equal(t, readClose(t, conn), closeRoomBusy)
+}
+
+func TestJoinFloodLatency(t *testing.T) {
+	if testing.Short() {
+		t.Skip("floods a room for a few seconds")
+	}
+
+	for _, overflow := range []bool{false, true} {
+		overflow := overflow
+		t.Run("overflow="+strconv.FormatBool(overflow), func(t *testing.T) {
+			latencies, flood := joinFloodLatency(t, overflow, 20)
+
+			// Players are kept up to date however hard the room is being joined.
+			assert.Assert(t, latencies[len(latencies)-1] < 2*time.Second, "slowest broadcast took %v", latencies[len(latencies)-1])
+			assert.Equal(t, flood.failed.Load(), int64(0))
+			if overflow {
+				assert.Equal(t, flood.rejected.Load(), int64(0))
+				assert.Assert(t, flood.waiting.Load() != 0)
+			} else {
+				assert.Assert(t, flood.rejected.Load() != 0)
+			}
+		})
+	}
+}
+
+// BenchmarkJoinFlood measures how long a broadcast takes to reach every
+// player in a room while spectators flood it with joins.
+func BenchmarkJoinFlood(b *testing.B) {
+	for _, overflow := range []bool{false, true} {
+		overflow := overflow
+		b.Run("overflow="+strconv.FormatBool(overflow), func(b *testing.B) {
+			latencies, flood := joinFloodLatency(b, overflow, b.N)
+
+			b.ReportMetric(float64(latencies[len(latencies)/2])/float64(time.Millisecond), "p50-ms")
+			b.ReportMetric(float64(latencies[len(latencies)-1])/float64(time.Millisecond), "max-ms")
+			b.ReportMetric(float64(flood.joins.Load())/float64(b.N), "joins/op")
+		})
+	}
+}
+
+const (
+	floodPlayers = 6
+	floodWorkers = 16
+)
+
+// floodStats counts what a join flood's spectators were met with.
+type floodStats struct {
+	joins    atomic.Int64 // Sent a state.
+	rejected atomic.Int64 // Turned away.
+	waiting  atomic.Int64 // Sent nothing yet; in the overflow tier.
+	failed   atomic.Int64 // Couldn't connect at all.
+}
+
+// joinFloodLatency broadcasts a state rounds times to players in a room which
+// spectators are flooding with joins, returning how long each took to reach
+// every player, sorted.
+func joinFloodLatency(t testing.TB, overflow bool, rounds int) ([]time.Duration, *floodStats) {
+	t.Helper()
+
+	r := newTestRoom(t)
+	r.hold(func() {
+		r.spectate.overflow = overflow
+	})
+
+	players := make([]*websocket.Conn, floodPlayers)
+	for i := range players {
+		players[i] = dialTestRoom(t, r, ConnOptions{Nickname: fmt.Sprint("player", i)})
+		readState(t, players[i])
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		c, counter, err := Accept(w, req, testAcceptOptions)
+		if err != nil {
+			return
+		}
+		r.HandleConn(ctx, ConnOptions{Spectate: true, Counter: counter}, c)
+	}))
+	url := "ws" + strings.TrimPrefix(hs.URL, "http")
+
+	var flood floodStats
+	var wg sync.WaitGroup
+	for i := 0; i < floodWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				floodJoin(ctx, url, &flood)
+			}
+		}()
+	}
+	defer func() {
+		cancel()
+		wg.Wait()
+		hs.Close()
+	}()
+
+	if b, ok := t.(*testing.B); ok {
+		b.ResetTimer()
+	}
+
+	latencies := make([]time.Duration, 0, rounds)
+	for i := 0; i < rounds; i++ {
+		var version int64
+		r.hold(func() {
+			r.room.Version++
+			version = r.room.Version
+			r.sendAll()
+		})
+
+		start := time.Now()
+		for _, conn := range players {
+			for readState(t, conn).RoomState.Version < version {
+				// Skip the spectator counts, and any states from before.
+			}
+		}
+		latencies = append(latencies, time.Since(start))
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	return latencies, &flood
+}
+
+// floodJoin connects a spectator, waits briefly for its first note, then
+// leaves.
+func floodJoin(ctx context.Context, url string, flood *floodStats) {
+	c, _, err := websocket.Dial(ctx, url, &websocket.DialOptions{
+		CompressionMode: websocket.CompressionContextTakeover,
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			flood.failed.Inc()
+		}
+		return
+	}
+	defer c.Close(websocket.StatusNormalClosure, "")
+
+	readCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+
+	var note protocol.ServerNote
+	switch err := wsjson.Read(readCtx, c, &note); {
+	case err != nil:
+		if ctx.Err() == nil {
+			flood.waiting.Inc()
+		}
+	case note.Method == "error":
+		flood.rejected.Inc()
+	default:
+		flood.joins.Inc()
+	}
+}
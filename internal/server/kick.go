@@ -0,0 +1,32 @@
+package server
+
+import (
+	"github.com/zikaeroh/codies/internal/game"
+	"nhooyr.io/websocket"
+)
+
+const closeKicked websocket.StatusCode = 4401
+
+// kick removes a player from the room and closes their connection. Only the
+// host may kick, and not themselves. Nothing stops the player from joining
+// again; kicking isn't banning.
+//
+// Must be called on the room's goroutine.
+func (r *Room) kick(playerID, target game.PlayerID) {
+	if playerID != r.room.Host || target == playerID {
+		return
+	}
+
+	p := r.room.Players[target]
+	if p == nil {
+		return
+	}
+
+	// The connection's own leave finds the player already gone.
+	if w := r.conns[target]; w != nil {
+		w.close(closeKicked, "kicked by the host")
+	}
+
+	r.audit(auditKick, playerID, p.Nickname)
+	r.removePlayer(target)
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func TestKick(t *testing.T) {
+	r := newTestRoom(t)
+
+	alice := dialTestRoom(t, r, ConnOptions{Nickname: "alice"})
+	readState(t, alice)
+	bob := dialTestRoom(t, r, ConnOptions{Nickname: "bob"})
+	readState(t, bob)
+	state := readState(t, alice)
+
+	target, _ := findStatePlayer(state, "bob")
+	writeNote(t, alice, protocol.KickMethod, state.RoomState.Version, &protocol.KickParams{PlayerID: target.PlayerID})
+
+	code, hint := readCloseHint(t, bob)
+	assert.Equal(t, code, closeKicked)
+	assert.DeepEqual(t, hint, protocol.CloseHint{Reason: "kicked by the host"})
+
+	state = readState(t, alice)
+	p, _ := findStatePlayer(state, "bob")
+	assert.Assert(t, p == nil)
+
+	entry := findAudit(r.AuditLog(), auditKick)
+	assert.Assert(t, entry != nil)
+	assert.Equal(t, entry.Target, "bob")
+	assert.Equal(t, entry.ActorName, "alice")
+
+	// Kicked players may come back.
+	bob = dialTestRoom(t, r, ConnOptions{Nickname: "bob"})
+	p, _ = findStatePlayer(readState(t, bob), "bob")
+	assert.Assert(t, p != nil)
+}
+
+func TestKickHostOnly(t *testing.T) {
+	r := newTestRoom(t)
+	r.addTestClient(t, "host", 0, false)
+	r.addTestClient(t, "other", 1, false)
+
+	r.testNote(t, "other", protocol.KickMethod, &protocol.KickParams{PlayerID: "host"})
+	r.testNote(t, "host", protocol.KickMethod, &protocol.KickParams{PlayerID: "host"})
+	r.testNote(t, "host", protocol.KickMethod, &protocol.KickParams{PlayerID: "missing"})
+
+	r.hold(func() {
+		assert.Equal(t, len(r.room.Players), 2)
+		assert.Equal(t, r.room.Host, "host")
+		assert.Equal(t, len(r.auditLog), 0)
+	})
+}
+
+func TestKickDuringTurn(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+	r.addTestClient(t, "spy", 1, true)
+	r.addTestClient(t, "guesser", 1, false)
+
+	r.hold(func() {
+		r.room.Turn = 1
+	})
+
+	r.testNote(t, "host", protocol.KickMethod, &protocol.KickParams{PlayerID: "guesser"})
+
+	r.hold(func() {
+		assert.Equal(t, len(r.room.Players), 2)
+		assert.Equal(t, len(r.players), 2)
+		assert.Equal(t, r.room.Turn, game.Team(1))
+
+		state := host.lastState().RoomState
+		assert.Equal(t, state.Version, r.room.Version)
+		assert.Equal(t, len(state.Teams[1]), 1)
+	})
+}
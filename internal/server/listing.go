@@ -0,0 +1,41 @@
+package server
+
+import (
+	"sort"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+)
+
+// MaxListedRooms is the most rooms ListRooms returns.
+const MaxListedRooms = 100
+
+// ListRooms returns the rooms which weren't created unlisted, busiest first,
+// up to MaxListedRooms. Like Stats, it's built from the latest snapshot, so
+// it never contends with the rooms.
+func (s *Server) ListRooms() []*protocol.ListedRoom {
+	details := s.Stats().Details
+
+	rooms := make([]*protocol.ListedRoom, 0, len(details))
+	for _, d := range details {
+		if d.Unlisted {
+			continue
+		}
+		rooms = append(rooms, &protocol.ListedRoom{
+			Name:             d.Name,
+			Players:          d.Clients,
+			PasswordRequired: true, // Every room has a password.
+		})
+	}
+
+	sort.Slice(rooms, func(i, j int) bool {
+		if rooms[i].Players != rooms[j].Players {
+			return rooms[i].Players > rooms[j].Players
+		}
+		return rooms[i].Name < rooms[j].Name
+	})
+
+	if len(rooms) > MaxListedRooms {
+		rooms = rooms[:MaxListedRooms]
+	}
+	return rooms
+}
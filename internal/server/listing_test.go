@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func TestListRooms(t *testing.T) {
+	s := newTestServer(t, nil)
+	ctx := context.Background()
+
+	quiet, err := s.CreateRoomWait(ctx, "quiet", "pass", RoomOptions{}, 0)
+	assert.NilError(t, err)
+	busy, err := s.CreateRoomWait(ctx, "busy", "pass", RoomOptions{}, 0)
+	assert.NilError(t, err)
+	_, err = s.CreateRoomWait(ctx, "also quiet", "pass", RoomOptions{Tracker: true}, 0)
+	assert.NilError(t, err)
+	hidden, err := s.CreateRoomWait(ctx, "hidden", "pass", RoomOptions{Unlisted: true}, 0)
+	assert.NilError(t, err)
+
+	quiet.clients.Add(1)
+	busy.clients.Add(3)
+	hidden.clients.Add(5)
+	s.refreshStats()
+
+	assert.DeepEqual(t, s.ListRooms(), []*protocol.ListedRoom{
+		{Name: "busy", Players: 3, PasswordRequired: true},
+		{Name: "quiet", Players: 1, PasswordRequired: true},
+		{Name: "also quiet", Players: 0, PasswordRequired: true},
+	})
+
+	// Unlisted rooms are still there for anyone who knows them.
+	assert.Assert(t, s.FindRoom("hidden") == hidden)
+	stats, ok := s.RoomStats(hidden.ID)
+	assert.Assert(t, ok)
+	assert.Assert(t, stats.Unlisted)
+}
+
+func TestListRoomsCapped(t *testing.T) {
+	s := newTestServer(t, nil)
+
+	for i := 0; i < MaxListedRooms+10; i++ {
+		_, err := s.CreateRoom(context.Background(), fmt.Sprint("room", i), "pass")
+		assert.NilError(t, err)
+	}
+	s.refreshStats()
+
+	assert.Equal(t, len(s.ListRooms()), MaxListedRooms)
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"github.com/zikaeroh/ctxlog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogLevelDuration is how long a room's log level override lasts by default.
+const LogLevelDuration = 15 * time.Minute
+
+// MaxLogLevelDuration is the longest a room's log level may be overridden.
+const MaxLogLevelDuration = 24 * time.Hour
+
+// LogLevel is a room's log level override.
+type LogLevel struct {
+	Level zapcore.Level `json:"level"`
+	Until time.Time     `json:"until"`
+}
+
+// withRoomLogger returns a context whose logger includes the room's fields and
+// respects its log level override.
+func (r *Room) withRoomLogger(ctx context.Context) context.Context {
+	logger := ctxlog.FromContext(ctx).WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		return &roomCore{Core: c, room: r}
+	}))
+	return ctxlog.WithLogger(ctx, logger.With(zap.String("roomName", r.Name), zap.String("roomID", r.ID)))
+}
+
+// withLogger returns ctx with the room's logger, for contexts which didn't
+// come from the room itself.
+func (r *Room) withLogger(ctx context.Context) context.Context {
+	return ctxlog.WithLogger(ctx, ctxlog.FromContext(r.ctx))
+}
+
+// SetLogLevel overrides the room's log level for d, which is capped at
+// MaxLogLevelDuration.
+func (r *Room) SetLogLevel(level zapcore.Level, d time.Duration) LogLevel {
+	if d > MaxLogLevelDuration {
+		d = MaxLogLevelDuration
+	}
+
+	before := r.auditLogLevel()
+	override := &LogLevel{Level: level, Until: r.clock.Now().Add(d)}
+	r.logLevel.Store(override)
+	r.counters.statsDirty.Store(true)
+
+	r.do(func() {
+		r.audit(auditLogLevel, "", "", &protocol.AuditChange{Field: "level", Before: before, After: level.String()})
+	})
+
+	return *override
+}
+
+// ClearLogLevel removes the room's log level override.
+func (r *Room) ClearLogLevel() {
+	before := r.auditLogLevel()
+	r.logLevel.Store((*LogLevel)(nil))
+	r.counters.statsDirty.Store(true)
+
+	r.do(func() {
+		r.audit(auditLogLevel, "", "", &protocol.AuditChange{Field: "level", Before: before, After: ""})
+	})
+}
+
+// auditLogLevel describes the current override for the audit log; it's empty
+// if there isn't one.
+func (r *Room) auditLogLevel() string {
+	if override := r.currentLogLevel(); override != nil {
+		return override.Level.String()
+	}
+	return ""
+}
+
+// LogLevel returns the room's log level override, or nil if it has none or
+// it's expired.
+func (r *Room) LogLevel() *LogLevel {
+	override := r.currentLogLevel()
+	if override == nil {
+		return nil
+	}
+	copied := *override
+	return &copied
+}
+
+// currentLogLevel is LogLevel without the copy, for the logger, which checks
+// it on every entry. Overrides are replaced rather than changed, so it's
+// safe to read.
+func (r *Room) currentLogLevel() *LogLevel {
+	override, _ := r.logLevel.Load().(*LogLevel)
+	if override == nil || !r.clock.Now().Before(override.Until) {
+		return nil
+	}
+	return override
+}
+
+// roomCore applies a room's log level override on top of the logger it was
+// derived from. An override may be more verbose than the parent's level, so
+// enabled entries are written to the parent directly rather than through its
+// level check.
+type roomCore struct {
+	zapcore.Core
+	room *Room
+}
+
+func (c *roomCore) Enabled(level zapcore.Level) bool {
+	if override := c.room.currentLogLevel(); override != nil {
+		return override.Level.Enabled(level)
+	}
+	return c.Core.Enabled(level)
+}
+
+func (c *roomCore) With(fields []zapcore.Field) zapcore.Core {
+	return &roomCore{Core: c.Core.With(fields), room: c.room}
+}
+
+func (c *roomCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if override := c.room.currentLogLevel(); override != nil {
+		if override.Level.Enabled(ent.Level) {
+			return ce.AddCore(ent, c)
+		}
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/ctxlog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+)
+
+func TestRoomLogLevel(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+
+	ctx, cancel := context.WithCancel(ctxlog.WithLogger(context.Background(), zap.New(core)))
+	defer cancel()
+
+	clock := newFakeClock()
+	counters := &counters{}
+
+	loud := newRoom(ctx, "loud", nil, "loud", counters)
+	loud.setClock(clock)
+	quiet := newRoom(ctx, "quiet", nil, "quiet", counters)
+	quiet.setClock(clock)
+
+	debug := func() {
+		ctxlog.Debug(loud.ctx, "debug")
+		ctxlog.Debug(quiet.ctx, "debug")
+	}
+
+	debug()
+	assert.Equal(t, logs.Len(), 0)
+	assert.Assert(t, loud.LogLevel() == nil)
+
+	override := loud.SetLogLevel(zapcore.DebugLevel, time.Minute)
+	assert.Equal(t, override.Until, clock.Now().Add(time.Minute))
+	assert.Assert(t, loud.LogLevel() != nil)
+	assert.Assert(t, counters.statsDirty.Load())
+
+	debug()
+	entries := logs.TakeAll()
+	assert.Equal(t, len(entries), 1)
+	assert.Equal(t, entries[0].ContextMap()["roomID"], "loud")
+
+	// Derived loggers see the override too.
+	ctxlog.Debug(ctxlog.With(loud.withLogger(context.Background()), zap.String("playerID", "p")), "derived")
+	assert.Equal(t, logs.Len(), 1)
+
+	clock.Advance(time.Minute)
+	assert.Assert(t, loud.LogLevel() == nil)
+
+	logs.TakeAll()
+	debug()
+	assert.Equal(t, logs.Len(), 0)
+
+	// Info is still written without an override.
+	ctxlog.Info(loud.ctx, "info")
+	assert.Equal(t, logs.Len(), 1)
+}
+
+func TestRoomLogLevelQuieter(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	ctx, cancel := context.WithCancel(ctxlog.WithLogger(context.Background(), zap.New(core)))
+	defer cancel()
+
+	r := newRoom(ctx, "room", nil, "room", &counters{})
+	r.SetLogLevel(zapcore.ErrorLevel, time.Hour)
+
+	ctxlog.Info(r.ctx, "info")
+	assert.Equal(t, logs.Len(), 0)
+
+	r.ClearLogLevel()
+	assert.Assert(t, r.LogLevel() == nil)
+
+	ctxlog.Info(r.ctx, "info")
+	assert.Equal(t, logs.Len(), 1)
+}
+
+func TestRoomLogLevelCapped(t *testing.T) {
+	r := newRoom(context.Background(), "room", nil, "room", &counters{})
+	clock := newFakeClock()
+	r.setClock(clock)
+
+	override := r.SetLogLevel(zapcore.DebugLevel, 7*24*time.Hour)
+	assert.Equal(t, override.Until, clock.Now().Add(MaxLogLevelDuration))
+}
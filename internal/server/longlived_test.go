@@ -0,0 +1,149 @@
+package server
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+// joinDiscardClient joins a player whose notes are thrown away, so that long
+// simulations don't measure the test's own memory.
+func (r *Room) joinDiscardClient(id game.PlayerID, team game.Team, spymaster bool) {
+	r.hold(func() {
+		r.join(id, func(priority, protocol.ServerNote) {}, ConnOptions{Nickname: string(id), Deltas: true})
+		r.room.ChangeTeam(id, team)
+		if spymaster {
+			r.room.ChangeRole(id, true)
+		}
+	})
+}
+
+func heapAlloc() uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+func TestLongLivedRoom(t *testing.T) {
+	events := 100000
+	if testing.Short() {
+		events = 10000
+	}
+
+	r := newTestRoom(t)
+	clock := newFakeClock()
+	r.setClock(clock)
+
+	r.joinDiscardClient("host", 0, true)
+	r.joinDiscardClient("spy1", 1, true)
+	r.joinDiscardClient("guess0", 0, false)
+	r.joinDiscardClient("guess1", 1, false)
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{Timed: boolPtr(true), TurnTime: intPtr(30)})
+
+	rng := rand.New(rand.NewSource(1)) //nolint:gosec
+	churn := 0
+
+	step := func() {
+		guesser := game.PlayerID("guess0")
+		if r.turn() == 1 {
+			guesser = "guess1"
+		}
+
+		switch rng.Intn(8) {
+		case 0:
+			id := game.PlayerID(fmt.Sprintf("churn%d", churn))
+			churn++
+			r.joinDiscardClient(id, game.Team(rng.Intn(2)), rng.Intn(2) == 0)
+			r.disconnect(id, nil)
+		case 1, 2:
+			var rows, cols int
+			r.hold(func() {
+				rows, cols = r.room.Rows, r.room.Cols
+			})
+			r.testNote(t, guesser, protocol.RevealMethod, &protocol.RevealParams{Row: rng.Intn(rows), Col: rng.Intn(cols)})
+		case 3:
+			r.testNote(t, guesser, protocol.EndTurnMethod, &protocol.EndTurnParams{})
+		case 4:
+			r.testNote(t, "host", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+		case 5:
+			r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{HideBomb: boolPtr(rng.Intn(2) == 0)})
+		case 6:
+			r.testNote(t, "host", protocol.ChangeMirrorDelayMethod, &protocol.ChangeMirrorDelayParams{Seconds: rng.Intn(2) * minMirrorDelay})
+		case 7:
+			clock.Advance(31 * time.Second)
+		}
+	}
+
+	// Warm up, so that everything the room keeps for good has been allocated.
+	for i := 0; i < events/10; i++ {
+		step()
+	}
+	before := heapAlloc()
+
+	for i := 0; i < events; i++ {
+		step()
+	}
+	after := heapAlloc()
+
+	assert.Assert(t, after < before+1<<20, "heap grew from %d to %d bytes", before, after)
+
+	r.hold(func() {
+		assert.Equal(t, len(r.players), 4)
+		assert.Equal(t, len(r.deltas), 4)
+		assert.Equal(t, len(r.bytes), 4)
+		assert.Equal(t, len(r.room.Players), 4)
+		assert.Assert(t, len(r.auditLog) <= maxAuditEntries)
+
+		// The turn timer and mirror timer, at most.
+		pending := 0
+		for _, timer := range clock.timers {
+			if !timer.done {
+				pending++
+			}
+		}
+		assert.Assert(t, pending <= 2)
+		assert.Assert(t, r.room.Version > 0 && r.room.Version <= game.MaxVersion)
+	})
+}
+
+func TestVersionRollover(t *testing.T) {
+	r := newTestRoom(t)
+	capable := r.joinTestClient(t, "capable", true)
+	old := r.joinTestClient(t, "old", false)
+
+	r.hold(func() {
+		r.room.Version = game.MaxVersion
+	})
+
+	var view roster
+	view.apply(t, capable.notes)
+
+	capableSent, oldSent := len(capable.notes), len(old.notes)
+	r.testCommand(t, "old", "1", game.MaxVersion, protocol.ChangeNicknameMethod, &protocol.ChangeNicknameParams{Nickname: "renamed"})
+
+	// A roster-only change would normally be a delta, but nothing can follow
+	// a rollover except a full state.
+	assert.DeepEqual(t, capable.methods(capableSent), []string{"state"})
+	assert.DeepEqual(t, old.methods(oldSent), []string{"ack", "state"})
+	assert.Equal(t, r.version(), int64(1))
+	assert.Equal(t, capable.lastState().RoomState.Version, int64(1))
+	assert.DeepEqual(t, old.acks(), []*protocol.Ack{{ID: "1", OK: true, Version: 1}})
+
+	// Deltas resume from the restarted version.
+	capableSent = len(capable.notes)
+	r.joinTestClient(t, "joined", false)
+	assert.DeepEqual(t, capable.methods(capableSent), []string{"playerJoined"})
+
+	view.apply(t, capable.notes)
+	assert.Equal(t, view.version, r.version())
+	r.hold(func() {
+		assert.DeepEqual(t, view.teams, r.currentState().guesser.Teams)
+	})
+}
@@ -0,0 +1,310 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/idgen"
+	"github.com/zikaeroh/codies/internal/names"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"github.com/zikaeroh/ctxlog"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+// Solo players may ask to be matched with others. Their tickets wait in a
+// pool until there are enough of them, at which point a room is created with
+// a seat kept for each player, on balanced teams. Seats are claimed with a
+// token, just like those kept by a merge.
+//
+// Clients poll their tickets. A ticket which isn't polled for
+// matchPollTimeout is dropped, as is one which has waited matchWindow without
+// a match, so that the pool only holds players who are still there. Matched
+// tickets are kept until their seats expire, in case the poll which would
+// have found the match was lost.
+//
+// The pool's lock is taken before the server's, which room creation needs,
+// and never while holding it.
+const (
+	matchPollTimeout = 30 * time.Second
+	matchWindow      = 5 * time.Minute
+	maxMatchPool     = 1000
+)
+
+var (
+	ErrMatchmakingDisabled = errors.New("server: matchmaking disabled")
+	ErrMatchPoolFull       = errors.New("server: matchmaking pool full")
+)
+
+type matchTicket struct {
+	id       string
+	nickname string
+	queued   time.Time
+	polled   time.Time
+
+	// Set once matched.
+	roomID  string
+	token   string
+	expires time.Time
+}
+
+type matchPool struct {
+	size    int // Zero if matchmaking is disabled.
+	waiting atomic.Int64
+
+	mu      sync.Mutex
+	queue   []*matchTicket          // Unmatched tickets, oldest first.
+	tickets map[string]*matchTicket // Every ticket, matched or not, by ID.
+}
+
+func newMatchPool(size int) *matchPool {
+	return &matchPool{
+		size:    size,
+		tickets: make(map[string]*matchTicket),
+	}
+}
+
+// sweep drops stale tickets.
+//
+// Must be called with p.mu locked.
+func (p *matchPool) sweep(now time.Time) {
+	queue := p.queue[:0]
+	for _, t := range p.queue {
+		if now.Sub(t.polled) >= matchPollTimeout || now.Sub(t.queued) >= matchWindow {
+			delete(p.tickets, t.id)
+			continue
+		}
+		queue = append(queue, t)
+	}
+	for i := len(queue); i < len(p.queue); i++ {
+		p.queue[i] = nil
+	}
+	p.queue = queue
+
+	for id, t := range p.tickets {
+		if t.roomID != "" && (now.Sub(t.polled) >= matchPollTimeout || !now.Before(t.expires)) {
+			delete(p.tickets, id)
+		}
+	}
+}
+
+// Must be called with p.mu locked.
+func (p *matchPool) response(t *matchTicket) *protocol.MatchmakeResponse {
+	resp := &protocol.MatchmakeResponse{
+		Ticket:  t.id,
+		Status:  protocol.MatchWaiting,
+		Waiting: len(p.queue),
+	}
+
+	if t.roomID != "" {
+		roomID, token := t.roomID, t.token
+		resp.Status = protocol.MatchFound
+		resp.RoomID = &roomID
+		resp.Token = &token
+	}
+	return resp
+}
+
+// Must be called with p.mu locked.
+func (p *matchPool) remove(t *matchTicket) {
+	for i, queued := range p.queue {
+		if queued == t {
+			p.queue = append(p.queue[:i], p.queue[i+1:]...)
+			break
+		}
+	}
+}
+
+// Must be called with p.mu locked.
+func (p *matchPool) counted(c *counters) {
+	if n := int64(len(p.queue)); p.waiting.Load() != n {
+		p.waiting.Store(n)
+		c.statsDirty.Store(true)
+	}
+}
+
+// Matchmake enters a player into the matchmaking pool, and matches them if
+// enough players are waiting.
+func (s *Server) Matchmake(ctx context.Context, nickname string) (*protocol.MatchmakeResponse, error) {
+	<-s.ready
+
+	p := s.matches
+	if p.size == 0 {
+		return nil, ErrMatchmakingDisabled
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	p.sweep(now)
+
+	if len(p.queue) >= maxMatchPool {
+		p.counted(&s.counters)
+		return nil, ErrMatchPoolFull
+	}
+
+	t := &matchTicket{
+		id:       idgen.Token(),
+		nickname: nickname,
+		queued:   now,
+		polled:   now,
+	}
+	p.queue = append(p.queue, t)
+	p.tickets[t.id] = t
+
+	s.match(ctx, now)
+	p.counted(&s.counters)
+	return p.response(t), nil
+}
+
+// MatchStatus polls a matchmaking ticket, returning false if it isn't known,
+// because it was cancelled or dropped.
+func (s *Server) MatchStatus(ctx context.Context, ticket string) (*protocol.MatchmakeResponse, bool) {
+	<-s.ready
+
+	p := s.matches
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	p.sweep(now)
+	defer p.counted(&s.counters)
+
+	t := p.tickets[ticket]
+	if t == nil {
+		return nil, false
+	}
+	t.polled = now
+
+	// A match which couldn't be made before, like when the server was
+	// full, is tried again.
+	s.match(ctx, now)
+	return p.response(t), true
+}
+
+// CancelMatch takes a ticket out of the pool, returning false if it isn't
+// known. A matched ticket may be cancelled too; its seat is left to expire.
+func (s *Server) CancelMatch(ticket string) bool {
+	<-s.ready
+
+	p := s.matches
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	t := p.tickets[ticket]
+	if t == nil {
+		return false
+	}
+
+	delete(p.tickets, ticket)
+	p.remove(t)
+	p.counted(&s.counters)
+	return true
+}
+
+// match makes rooms for the oldest waiting players, for as long as there are
+// enough of them. If a room can't be created, the players stay in the pool.
+//
+// Must be called with s.matches.mu locked.
+func (s *Server) match(ctx context.Context, now time.Time) {
+	p := s.matches
+
+	for len(p.queue) >= p.size {
+		group := p.queue[:p.size]
+
+		room, seats, err := s.createMatchRoom(ctx, group, now)
+		if err != nil {
+			ctxlog.Warn(ctx, "error creating matchmade room", zap.Error(err))
+			return
+		}
+
+		for i, t := range group {
+			t.roomID = room.ID
+			t.token = seats[i]
+			t.expires = now.Add(mergeSeatTTL)
+		}
+
+		p.queue = append([]*matchTicket(nil), p.queue[p.size:]...)
+		metricMatches.Inc()
+		ctxlog.Info(ctx, "matched players", zap.String("roomID", room.ID), zap.Int("players", len(group)))
+	}
+}
+
+// createMatchRoom creates a room for a group of matched players, returning
+// the tokens for their seats. The room has no password, as matched players
+// don't know each other's rooms; it's unlisted, as listed rooms are joined by
+// name and password.
+//
+// Must be called with s.matches.mu locked.
+func (s *Server) createMatchRoom(ctx context.Context, group []*matchTicket, now time.Time) (*Room, []string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, err := s.createRoom(ctx, "Match "+idgen.RoomID()[:8], nil, RoomOptions{Unlisted: true})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var tokens []string
+	room.do(func() { tokens = room.seatMatch(group, now) })
+	return room, tokens, nil
+}
+
+// seatMatch keeps seats for matched players, alternating between the teams
+// in the order they joined the pool, so teams differ in size by at most one.
+// Nicknames too similar to one taken earlier in the group are numbered, as
+// they'd otherwise be turned away once one of them is the host.
+//
+// Must be called on the room's goroutine.
+func (r *Room) seatMatch(group []*matchTicket, now time.Time) []string {
+	expires := now.Add(mergeSeatTTL)
+	teams := len(r.room.Teams)
+
+	tokens := make([]string, len(group))
+	taken := make([]string, 0, len(group))
+	for i, t := range group {
+		nickname := matchNickname(t.nickname, taken)
+		taken = append(taken, nickname)
+
+		token := idgen.Token()
+		r.mergeSeats[token] = &mergeSeat{
+			nickname: nickname,
+			team:     game.Team(i % teams),
+			expires:  expires,
+		}
+		tokens[i] = token
+	}
+
+	players := &protocol.AuditChange{Field: "players", After: strconv.Itoa(len(group))}
+	r.audit(auditMatchmade, "", "", players)
+	return tokens
+}
+
+// matchNickname returns nickname, or it with a number appended if it's too
+// similar to one already taken, shortened to fit.
+func matchNickname(nickname string, taken []string) string {
+	confusable := func(name string) bool {
+		for _, other := range taken {
+			if names.Confusable(name, other) {
+				return true
+			}
+		}
+		return false
+	}
+
+	candidate := nickname
+	for n := 2; confusable(candidate); n++ {
+		suffix := " " + strconv.Itoa(n)
+		base := []rune(nickname)
+		for len(string(base))+len(suffix) > 16 {
+			base = base[:len(base)-1]
+		}
+		candidate = string(base) + suffix
+	}
+	return candidate
+}
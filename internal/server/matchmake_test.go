@@ -0,0 +1,197 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func newTestMatchServer(t *testing.T, size int) *Server {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	s := NewServer(Options{MatchSize: size})
+	go s.Run(ctx) //nolint:errcheck
+	return s
+}
+
+func TestMatchmake(t *testing.T) {
+	ctx := context.Background()
+	s := newTestMatchServer(t, 4)
+
+	var tickets []string
+	for i := 0; i < 3; i++ {
+		resp, err := s.Matchmake(ctx, "p"+strconv.Itoa(i))
+		assert.NilError(t, err)
+		assert.Equal(t, resp.Status, protocol.MatchWaiting)
+		assert.Equal(t, resp.Waiting, i+1)
+		assert.Assert(t, resp.RoomID == nil)
+		tickets = append(tickets, resp.Ticket)
+	}
+
+	s.refreshStats()
+	assert.Equal(t, s.Stats().Matchmaking, 3)
+	assert.Equal(t, s.Stats().Rooms, 0)
+
+	last, err := s.Matchmake(ctx, "p3")
+	assert.NilError(t, err)
+	assert.Equal(t, last.Status, protocol.MatchFound)
+	assert.Equal(t, last.Waiting, 0)
+	tickets = append(tickets, last.Ticket)
+
+	room := s.FindRoomByID(*last.RoomID)
+	assert.Assert(t, room != nil)
+
+	tokens := make(map[string]bool)
+	for _, ticket := range tickets {
+		resp, ok := s.MatchStatus(ctx, ticket)
+		assert.Assert(t, ok)
+		assert.Equal(t, resp.Status, protocol.MatchFound)
+		assert.Equal(t, *resp.RoomID, room.ID)
+		tokens[*resp.Token] = true
+
+		conn := dialTestRoom(t, room, ConnOptions{Nickname: "anything", MergeToken: *resp.Token})
+		readState(t, conn)
+	}
+	assert.Equal(t, len(tokens), 4)
+
+	// Seats alternate between the teams, in the order players queued.
+	room.hold(func() {
+		for i := 0; i < 4; i++ {
+			var found bool
+			for _, p := range room.room.Players {
+				if p.Nickname == "p"+strconv.Itoa(i) {
+					assert.Equal(t, int(p.Team), i%2)
+					found = true
+				}
+			}
+			assert.Assert(t, found, "p%d wasn't seated", i)
+		}
+		assert.Equal(t, len(room.room.Teams[0]), 2)
+		assert.Equal(t, len(room.room.Teams[1]), 2)
+	})
+
+	assert.Assert(t, findAudit(room.AuditLog(), auditMatchmade) != nil)
+
+	s.refreshStats()
+	assert.Equal(t, s.Stats().Matchmaking, 0)
+}
+
+func TestMatchmakeDisabled(t *testing.T) {
+	s := newTestMatchServer(t, 0)
+
+	_, err := s.Matchmake(context.Background(), "solo")
+	assert.Equal(t, err, ErrMatchmakingDisabled)
+}
+
+func TestMatchmakeCancel(t *testing.T) {
+	ctx := context.Background()
+	s := newTestMatchServer(t, 2)
+
+	first, err := s.Matchmake(ctx, "first")
+	assert.NilError(t, err)
+	assert.Assert(t, s.CancelMatch(first.Ticket))
+	assert.Assert(t, !s.CancelMatch(first.Ticket))
+
+	_, ok := s.MatchStatus(ctx, first.Ticket)
+	assert.Assert(t, !ok)
+
+	// The cancelled ticket isn't matched with the next player.
+	second, err := s.Matchmake(ctx, "second")
+	assert.NilError(t, err)
+	assert.Equal(t, second.Status, protocol.MatchWaiting)
+	assert.Equal(t, second.Waiting, 1)
+}
+
+func TestMatchmakeExpiry(t *testing.T) {
+	ctx := context.Background()
+	s := newTestMatchServer(t, 2)
+
+	unpolled, err := s.Matchmake(ctx, "unpolled")
+	assert.NilError(t, err)
+	stale, err := s.Matchmake(ctx, "stale")
+	assert.NilError(t, err)
+	assert.Equal(t, stale.Status, protocol.MatchFound)
+
+	s.matches.mu.Lock()
+	s.matches.tickets[unpolled.Ticket].polled = time.Now().Add(-matchPollTimeout)
+	s.matches.tickets[stale.Ticket].expires = time.Now()
+	s.matches.mu.Unlock()
+
+	_, ok := s.MatchStatus(ctx, unpolled.Ticket)
+	assert.Assert(t, !ok, "unpolled match kept")
+	_, ok = s.MatchStatus(ctx, stale.Ticket)
+	assert.Assert(t, !ok, "expired match kept")
+
+	waitingUnpolled, err := s.Matchmake(ctx, "unpolled")
+	assert.NilError(t, err)
+
+	s.matches.mu.Lock()
+	s.matches.tickets[waitingUnpolled.Ticket].polled = time.Now().Add(-matchPollTimeout)
+	s.matches.mu.Unlock()
+
+	// A player who's stopped polling isn't matched with someone new.
+	next, err := s.Matchmake(ctx, "next")
+	assert.NilError(t, err)
+	assert.Equal(t, next.Status, protocol.MatchWaiting)
+	assert.Equal(t, next.Waiting, 1)
+
+	s.matches.mu.Lock()
+	s.matches.tickets[next.Ticket].queued = time.Now().Add(-matchWindow)
+	s.matches.mu.Unlock()
+
+	// Nor is one who's waited too long.
+	_, ok = s.MatchStatus(ctx, next.Ticket)
+	assert.Assert(t, !ok, "old ticket kept")
+
+	s.refreshStats()
+	assert.Equal(t, s.Stats().Matchmaking, 0)
+}
+
+func TestMatchmakeServerFull(t *testing.T) {
+	ctx := context.Background()
+	s := NewServer(Options{MatchSize: 2, MaxRooms: 1})
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go s.Run(runCtx) //nolint:errcheck
+
+	_, err := s.CreateRoom(ctx, "full", "pass")
+	assert.NilError(t, err)
+
+	first, err := s.Matchmake(ctx, "first")
+	assert.NilError(t, err)
+	second, err := s.Matchmake(ctx, "second")
+	assert.NilError(t, err)
+	assert.Equal(t, second.Status, protocol.MatchWaiting)
+	assert.Equal(t, second.Waiting, 2)
+
+	// Once there's room, the next poll makes the match.
+	assert.Assert(t, s.DeleteRoom(ctx, s.FindRoom("full").ID))
+	resp, ok := s.MatchStatus(ctx, first.Ticket)
+	assert.Assert(t, ok)
+	assert.Equal(t, resp.Status, protocol.MatchFound)
+}
+
+func TestMatchNickname(t *testing.T) {
+	tests := []struct {
+		nickname string
+		taken    []string
+		want     string
+	}{
+		{"alice", nil, "alice"},
+		{"alice", []string{"bob"}, "alice"},
+		{"alice", []string{"alice"}, "alice 2"},
+		{"ALICE", []string{"alice", "alice 2"}, "ALICE 3"},
+		{"sixteencharsname", []string{"sixteencharsname"}, "sixteencharsna 2"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, matchNickname(test.nickname, test.taken), test.want, "%s %v", test.nickname, test.taken)
+	}
+}
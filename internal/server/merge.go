@@ -0,0 +1,385 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/idgen"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"github.com/zikaeroh/ctxlog"
+	"go.uber.org/zap"
+	"nhooyr.io/websocket"
+)
+
+// A merge moves the players of one room (the source) into another (the
+// target), for groups which end up split between two rooms. The source's host
+// asks with requestMerge, naming the target's ID, and the target's host is
+// sent a mergeRequest to accept or decline. Once accepted, the target keeps a
+// seat for each of the source's players, with their nickname and team, and
+// every player in the source is sent a merged note with their seat's token,
+// then closed with closeRoomMerged and the target's ID as the reason. The
+// source is then closed for good.
+//
+// Only rooms between games may merge. Each step runs on one room's goroutine
+// at a time, and steps which need the other room run on a goroutine of their
+// own, so rooms merging toward each other can't deadlock.
+const (
+	mergeRequestTTL = 2 * time.Minute // How long a host has to answer.
+	mergeSeatTTL    = 5 * time.Minute // How long seats are kept.
+	mergeCloseWait  = 5 * time.Second // How long closing the source waits on slow clients.
+
+	// maxMergePrompts is the most unanswered requests a room may have.
+	maxMergePrompts = 4
+)
+
+const closeRoomMerged websocket.StatusCode = 4308
+
+var (
+	errMergeNotFound = &game.Error{
+		Code:    "mergeNotFound",
+		Message: "The room to merge into doesn't exist.",
+	}
+	errMergeStarted = &game.Error{
+		Code:    "mergeStarted",
+		Message: "Rooms can only merge between games.",
+	}
+	errMergeMismatch = &game.Error{
+		Code:    "mergeMismatch",
+		Message: "Rooms with and without boards can't merge.",
+	}
+	errMergePending = &game.Error{
+		Code:    "mergePending",
+		Message: "A merge is already waiting for an answer.",
+	}
+	errMergeDeclined = &game.Error{
+		Code:    "mergeDeclined",
+		Message: "The other room's host declined to merge.",
+	}
+	errMergeExpired = &game.Error{
+		Code:    "mergeExpired",
+		Message: "The merge request is no longer valid.",
+	}
+)
+
+// mergeOffer is a merge the source's host asked for, until it's answered.
+type mergeOffer struct {
+	id      string
+	target  string // Room ID.
+	expires time.Time
+}
+
+// mergePrompt is a merge waiting on the target's host.
+type mergePrompt struct {
+	source  *Room
+	expires time.Time
+}
+
+// mergeSeat is a place in the target kept for one of the source's players.
+type mergeSeat struct {
+	nickname string
+	team     game.Team
+	expires  time.Time
+}
+
+// requestMerge starts merging the room into the target.
+//
+// Must be called on the room's goroutine.
+func (r *Room) requestMerge(playerID game.PlayerID, target string) error {
+	if playerID != r.room.Host {
+		return nil
+	}
+
+	if r.server == nil || target == r.ID {
+		return errMergeNotFound
+	}
+
+	if r.room.Started() {
+		return errMergeStarted
+	}
+
+	now := r.clock.Now()
+	if r.mergeOffer != nil && now.Before(r.mergeOffer.expires) {
+		return errMergePending
+	}
+
+	offer := &mergeOffer{
+		id:      idgen.Token(),
+		target:  target,
+		expires: now.Add(mergeRequestTTL),
+	}
+	r.mergeOffer = offer
+	r.audit(auditRequestMerge, playerID, auditToken(target))
+
+	go r.offerMerge(offer, len(r.room.Players))
+	return nil
+}
+
+// offerMerge passes a merge request along to the target's host.
+func (r *Room) offerMerge(offer *mergeOffer, players int) {
+	target := r.server.FindRoomByID(offer.target)
+	if target == nil {
+		r.mergeFailed(offer.id, errMergeNotFound)
+		return
+	}
+
+	var err error
+	target.do(func() { err = target.promptMerge(r, offer, players) })
+
+	if err != nil {
+		r.mergeFailed(offer.id, err)
+	}
+}
+
+// promptMerge asks the host to accept a merge from the source.
+//
+// Must be called on the room's goroutine.
+func (r *Room) promptMerge(source *Room, offer *mergeOffer, players int) error {
+	if r.role != source.role {
+		return errMergeMismatch
+	}
+
+	if r.room.Started() {
+		return errMergeStarted
+	}
+
+	// A room which nobody's in can't accept anything.
+	sender := r.players[r.room.Host]
+	if sender == nil {
+		return errMergeNotFound
+	}
+
+	now := r.clock.Now()
+	for id, p := range r.mergePrompts {
+		if !now.Before(p.expires) {
+			delete(r.mergePrompts, id)
+		}
+	}
+
+	if len(r.mergePrompts) >= maxMergePrompts {
+		return errMergePending
+	}
+
+	r.mergePrompts[offer.id] = &mergePrompt{source: source, expires: offer.expires}
+	sender(priorityTargeted, protocol.NewMergeRequestNote(offer.id, source.Name, players))
+	return nil
+}
+
+// answerMerge accepts or declines a merge into the room.
+//
+// Must be called on the room's goroutine.
+func (r *Room) answerMerge(playerID game.PlayerID, id string, accept bool) error {
+	if playerID != r.room.Host {
+		return nil
+	}
+
+	prompt := r.mergePrompts[id]
+	if prompt == nil {
+		return errMergeExpired
+	}
+	delete(r.mergePrompts, id)
+
+	source := prompt.source
+
+	if !r.clock.Now().Before(prompt.expires) {
+		go source.mergeFailed(id, errMergeExpired)
+		return errMergeExpired
+	}
+
+	if !accept {
+		r.audit(auditDeclineMerge, playerID, source.Name)
+		go source.mergeFailed(id, errMergeDeclined)
+		return nil
+	}
+
+	if r.room.Started() {
+		go source.mergeFailed(id, errMergeStarted)
+		return errMergeStarted
+	}
+
+	go source.completeMerge(id, r, playerID)
+	return nil
+}
+
+// mergeFailed tells the host their merge request didn't go through.
+func (r *Room) mergeFailed(id string, err error) {
+	r.do(func() {
+		if r.mergeOffer == nil || r.mergeOffer.id != id {
+			return
+		}
+		r.mergeOffer = nil
+
+		_ = r.sendError(r.room.Host, err)
+	})
+}
+
+// completeMerge moves the room's players into the target, which has accepted,
+// then closes the room.
+func (r *Room) completeMerge(id string, target *Room, acceptedBy game.PlayerID) {
+	expired := false
+	var seats map[string]*mergeSeat
+	var tokens map[game.PlayerID]string
+	r.do(func() {
+		offer := r.mergeOffer
+		r.mergeOffer = nil
+		if offer == nil || offer.id != id || !r.clock.Now().Before(offer.expires) || r.room.Started() {
+			expired = true
+			return
+		}
+
+		// New players are turned away from here on; the snapshot is final.
+		r.mergedInto = target.ID
+
+		seats = make(map[string]*mergeSeat, len(r.room.Players))
+		tokens = make(map[game.PlayerID]string, len(r.room.Players))
+		for playerID, p := range r.room.Players {
+			token := idgen.Token()
+			seats[token] = &mergeSeat{nickname: p.Nickname, team: p.Team}
+			tokens[playerID] = token
+		}
+	})
+
+	if expired {
+		target.do(func() { _ = target.sendError(acceptedBy, errMergeExpired) })
+		return
+	}
+
+	var err error
+	target.do(func() { err = target.seatMerge(r, seats, acceptedBy) })
+
+	var closed *sync.WaitGroup
+	r.do(func() {
+		if err != nil {
+			r.mergedInto = ""
+			_ = r.sendError(r.room.Host, err)
+			return
+		}
+
+		players := &protocol.AuditChange{Field: "players", After: strconv.Itoa(len(seats))}
+		r.audit(auditMergeOut, "", target.Name, players)
+		closed = r.redirectAll(target.ID, tokens)
+	})
+	if closed == nil {
+		return
+	}
+
+	closed.Wait()
+
+	metricMerges.Inc()
+	ctxlog.Info(r.ctx, "merged room", zap.String("targetRoomID", target.ID), zap.Int("players", len(seats)))
+	r.server.closeRoom(r, CloseMerged)
+}
+
+// seatMerge keeps seats for the source's players.
+//
+// Must be called on the room's goroutine.
+func (r *Room) seatMerge(source *Room, seats map[string]*mergeSeat, acceptedBy game.PlayerID) error {
+	if r.room.Started() {
+		return errMergeStarted
+	}
+
+	now := r.clock.Now()
+	for token, seat := range r.mergeSeats {
+		if !now.Before(seat.expires) {
+			delete(r.mergeSeats, token)
+		}
+	}
+
+	expires := now.Add(mergeSeatTTL)
+	for token, seat := range seats {
+		seat.expires = expires
+		r.mergeSeats[token] = seat
+	}
+
+	players := &protocol.AuditChange{Field: "players", After: strconv.Itoa(len(seats))}
+	r.audit(auditMergeIn, acceptedBy, source.Name, players)
+	return nil
+}
+
+// takeMergeSeat claims the seat kept for a merge token, or returns nil if
+// there isn't one.
+//
+// Must be called on the room's goroutine.
+func (r *Room) takeMergeSeat(token string) *mergeSeat {
+	seat := r.mergeSeats[token]
+	if seat == nil {
+		return nil
+	}
+	delete(r.mergeSeats, token)
+
+	if !r.clock.Now().Before(seat.expires) {
+		return nil
+	}
+	return seat
+}
+
+// redirectAll sends every player their seat in the target, then closes every
+// connection to the room. The returned WaitGroup is done once the closes have
+// been written, or given up on.
+//
+// Must be called on the room's goroutine.
+func (r *Room) redirectAll(target string, tokens map[game.PlayerID]string) *sync.WaitGroup {
+	var wg sync.WaitGroup
+
+	for playerID, w := range r.conns {
+		token := tokens[playerID]
+		w := w
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(r.ctx, mergeCloseWait)
+			defer cancel()
+
+			if token != "" {
+				_ = w.sendWait(ctx, priorityTargeted, protocol.NewMergedNote(target, token))
+			}
+			w.closeHinted(closeRoomMerged, mergedHint(target))
+
+			select {
+			case <-w.done:
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	// Mirror tokens don't carry over, so mirrors have nowhere to go.
+	for _, m := range r.mirrors {
+		m.w.close(closeMirrorRejected, "room merged")
+	}
+
+	// Spectators need nothing to follow.
+	for _, m := range r.spectators {
+		m.w.closeHinted(closeRoomMerged, mergedHint(target))
+	}
+
+	return &wg
+}
+
+// redirectConn sends a connection which arrived after the room was merged on
+// to the target, then closes it.
+func (r *Room) redirectConn(ctx context.Context, w *connWriter, target string) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = w.run(ctx)
+	}()
+
+	ctxlog.Info(ctx, "redirected client to merged room")
+	w.closeHinted(closeRoomMerged, mergedHint(target))
+	<-done
+}
+
+// closeRoom closes the room, if it's still open.
+func (s *Server) closeRoom(room *Room, reason CloseReason) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.roomIDs[room.ID] != room {
+		return
+	}
+	s.removeRoom(room, reason)
+}
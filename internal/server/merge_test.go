@@ -0,0 +1,264 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+)
+
+// readCloseError reads until the connection is closed, returning the close.
+func readCloseError(t *testing.T, c *websocket.Conn) websocket.CloseError {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for {
+		var note protocol.ServerNote
+		if err := wsjson.Read(ctx, c, &note); err != nil {
+			var closeErr websocket.CloseError
+			assert.Assert(t, errors.As(err, &closeErr), "%v", err)
+			return closeErr
+		}
+	}
+}
+
+func readState(t testing.TB, c *websocket.Conn) *protocol.State {
+	t.Helper()
+
+	var state protocol.State
+	readNote(t, c, "state", &state)
+	return &state
+}
+
+func findStatePlayer(state *protocol.State, nickname string) (*protocol.StatePlayer, game.Team) {
+	for team, players := range state.RoomState.Teams {
+		for _, p := range players {
+			if p.Nickname == nickname {
+				return p, game.Team(team)
+			}
+		}
+	}
+	return nil, -1
+}
+
+// mergeTestRooms creates a source room with two players, both on the first
+// team, and a target room with its host, also on the first team. It returns the connections of the
+// source's host, the other source player, and the target's host, with the
+// latest state each has seen.
+func mergeTestRooms(t *testing.T) (s *Server, source, target *Room, alice, bob, carol *websocket.Conn, aliceState, carolState *protocol.State) {
+	t.Helper()
+
+	ctx := context.Background()
+	s = newTestServer(t, nil)
+
+	source, err := s.CreateRoom(ctx, "fridaygame", "pass")
+	assert.NilError(t, err)
+	target, err = s.CreateRoom(ctx, "friday game", "pass")
+	assert.NilError(t, err)
+
+	alice = dialTestRoom(t, source, ConnOptions{Nickname: "alice"})
+	readState(t, alice)
+
+	bob = dialTestRoom(t, source, ConnOptions{Nickname: "bob"})
+	bobState := readState(t, bob)
+	readState(t, alice)
+
+	writeNote(t, bob, protocol.ChangeTeamMethod, bobState.RoomState.Version, &protocol.ChangeTeamParams{Team: 0})
+	aliceState = readState(t, alice)
+
+	carol = dialTestRoom(t, target, ConnOptions{Nickname: "carol"})
+	carolState = readState(t, carol)
+
+	return s, source, target, alice, bob, carol, aliceState, carolState
+}
+
+func TestMerge(t *testing.T) {
+	s, source, target, alice, bob, carol, aliceState, carolState := mergeTestRooms(t)
+
+	_, bobTeam := findStatePlayer(aliceState, "bob")
+	assert.Equal(t, bobTeam, game.Team(0))
+
+	writeNote(t, alice, protocol.RequestMergeMethod, aliceState.RoomState.Version, &protocol.RequestMergeParams{RoomID: target.ID})
+
+	var request protocol.MergeRequest
+	readNote(t, carol, "mergeRequest", &request)
+	assert.Equal(t, request.RoomName, "fridaygame")
+	assert.Equal(t, request.Players, 2)
+
+	writeNote(t, carol, protocol.AnswerMergeMethod, carolState.RoomState.Version, &protocol.AnswerMergeParams{ID: request.ID, Accept: true})
+
+	// Every one of the source's players is sent a seat, then sent along.
+	var aliceSeat, bobSeat protocol.Merged
+	readNote(t, alice, "merged", &aliceSeat)
+	readNote(t, bob, "merged", &bobSeat)
+	assert.Equal(t, aliceSeat.RoomID, target.ID)
+	assert.Assert(t, aliceSeat.Token != bobSeat.Token)
+
+	code, hint := readCloseHint(t, alice)
+	assert.Equal(t, code, closeRoomMerged)
+	assert.DeepEqual(t, hint, protocol.CloseHint{Retry: true, RoomID: target.ID, Reason: "room merged"})
+	assert.Equal(t, readCloseError(t, bob).Code, closeRoomMerged)
+
+	// Seats keep nicknames and teams, as guessers, even where another team
+	// is smaller.
+	conn := dialTestRoom(t, target, ConnOptions{Nickname: "someone", MergeToken: bobSeat.Token})
+	state := readState(t, conn)
+	p, team := findStatePlayer(state, "bob")
+	assert.Assert(t, p != nil)
+	assert.Equal(t, p.PlayerID, state.PlayerID)
+	assert.Equal(t, team, bobTeam)
+	assert.Assert(t, !p.Spymaster)
+	p, _ = findStatePlayer(state, "carol")
+	assert.Assert(t, p.Host)
+
+	// A seat is only good once.
+	conn = dialTestRoom(t, target, ConnOptions{Nickname: "again", MergeToken: bobSeat.Token})
+	state = readState(t, conn)
+	p, _ = findStatePlayer(state, "again")
+	assert.Assert(t, p != nil)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for s.FindRoomByID(source.ID) != nil {
+		assert.Assert(t, time.Now().Before(deadline), "source room never closed")
+		time.Sleep(10 * time.Millisecond)
+	}
+	closed, ok := s.RecentlyClosed(source.ID)
+	assert.Assert(t, ok)
+	assert.Equal(t, closed.Reason, CloseMerged)
+
+	// The merge is in both audit logs.
+	in := findAudit(target.AuditLog(), auditMergeIn)
+	assert.Assert(t, in != nil)
+	assert.Equal(t, in.Target, "fridaygame")
+	assert.Equal(t, in.ActorName, "carol")
+	out := findAudit(source.AuditLog(), auditMergeOut)
+	assert.Assert(t, out != nil)
+	assert.Equal(t, out.Target, "friday game")
+}
+
+func findAudit(log []*protocol.AuditEntry, action string) *protocol.AuditEntry {
+	for _, e := range log {
+		if e.Action == action {
+			return e
+		}
+	}
+	return nil
+}
+
+func TestMergeDeclined(t *testing.T) {
+	s, source, target, alice, _, carol, aliceState, carolState := mergeTestRooms(t)
+
+	writeNote(t, alice, protocol.RequestMergeMethod, aliceState.RoomState.Version, &protocol.RequestMergeParams{RoomID: target.ID})
+
+	var request protocol.MergeRequest
+	readNote(t, carol, "mergeRequest", &request)
+	writeNote(t, carol, protocol.AnswerMergeMethod, carolState.RoomState.Version, &protocol.AnswerMergeParams{ID: request.ID})
+
+	var e protocol.Error
+	readNote(t, alice, "error", &e)
+	assert.Equal(t, e.Code, "mergeDeclined")
+	assert.Assert(t, s.FindRoomByID(source.ID) != nil)
+
+	// Answering again does nothing.
+	writeNote(t, carol, protocol.AnswerMergeMethod, carolState.RoomState.Version, &protocol.AnswerMergeParams{ID: request.ID, Accept: true})
+	readNote(t, carol, "error", &e)
+	assert.Equal(t, e.Code, "mergeExpired")
+
+	// And the source may ask again.
+	writeNote(t, alice, protocol.RequestMergeMethod, aliceState.RoomState.Version, &protocol.RequestMergeParams{RoomID: target.ID})
+	readNote(t, carol, "mergeRequest", &request)
+}
+
+func TestMergeNotFound(t *testing.T) {
+	_, _, _, alice, _, _, aliceState, _ := mergeTestRooms(t)
+
+	writeNote(t, alice, protocol.RequestMergeMethod, aliceState.RoomState.Version, &protocol.RequestMergeParams{RoomID: "missing"})
+
+	var e protocol.Error
+	readNote(t, alice, "error", &e)
+	assert.Equal(t, e.Code, "mergeNotFound")
+}
+
+func TestMergeTargetStarted(t *testing.T) {
+	_, _, target, alice, _, _, aliceState, _ := mergeTestRooms(t)
+
+	target.hold(func() {
+		target.room.Clue = &game.Clue{Word: "ANIMAL", Count: 1}
+	})
+
+	writeNote(t, alice, protocol.RequestMergeMethod, aliceState.RoomState.Version, &protocol.RequestMergeParams{RoomID: target.ID})
+
+	var e protocol.Error
+	readNote(t, alice, "error", &e)
+	assert.Equal(t, e.Code, "mergeStarted")
+}
+
+func TestRequestMergeStarted(t *testing.T) {
+	r := newTestRoom(t)
+	r.server = newTestServer(t, nil)
+	host := r.addTestClient(t, "host", 0, false)
+
+	r.hold(func() {
+		r.room.Clue = &game.Clue{Word: "ANIMAL", Count: 1}
+	})
+
+	r.testNote(t, "host", protocol.RequestMergeMethod, &protocol.RequestMergeParams{RoomID: "other"})
+	errs := host.errors()
+	assert.Equal(t, len(errs), 1)
+	assert.Equal(t, errs[0].Code, "mergeStarted")
+}
+
+func TestRequestMergeHostOnly(t *testing.T) {
+	r := newTestRoom(t)
+	r.server = newTestServer(t, nil)
+	r.addTestClient(t, "host", 0, false)
+	r.addTestClient(t, "other", 1, false)
+
+	r.testNote(t, "other", protocol.RequestMergeMethod, &protocol.RequestMergeParams{RoomID: "other"})
+
+	r.hold(func() {
+		assert.Assert(t, r.mergeOffer == nil)
+	})
+}
+
+func TestMergedRoomRedirects(t *testing.T) {
+	r := newTestRoom(t)
+
+	r.hold(func() {
+		r.mergedInto = "elsewhere"
+	})
+
+	conn := dialTestRoom(t, r, ConnOptions{Nickname: "late"})
+	code, hint := readCloseHint(t, conn)
+	assert.Equal(t, code, closeRoomMerged)
+	assert.Equal(t, hint.RoomID, "elsewhere")
+
+	r.hold(func() {
+		assert.Equal(t, len(r.room.Players), 0)
+	})
+}
+
+func TestMergeSeatExpires(t *testing.T) {
+	r := newTestRoom(t)
+	c := newFakeClock()
+	r.setClock(c)
+
+	r.hold(func() {
+		r.mergeSeats["token"] = &mergeSeat{nickname: "bob", team: 1, expires: c.Now().Add(mergeSeatTTL)}
+	})
+
+	c.Advance(mergeSeatTTL)
+
+	conn := dialTestRoom(t, r, ConnOptions{Nickname: "robert", MergeToken: "token"})
+	state := readState(t, conn)
+	p, _ := findStatePlayer(state, "robert")
+	assert.Assert(t, p != nil)
+}
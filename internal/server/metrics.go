@@ -20,6 +20,147 @@ var (
 		Help:      "Total number of clients.",
 	})
 
+	metricConnsRejected = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "conns_rejected_total",
+		Help:      "Total number of connections rejected for too many from one IP.",
+	})
+
+	metricJoinsThrottled = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "joins_throttled_total",
+		Help:      "Total number of connections turned away from a room with too many joining at once.",
+	})
+
+	metricSpectatorsOverflowed = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "spectators_overflowed_total",
+		Help:      "Total number of spectators admitted to a room's overflow tier.",
+	})
+
+	metricChatsThrottled = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "chats_throttled_total",
+		Help:      "Total number of chat messages dropped for being sent too quickly.",
+	})
+
+	metricCreatesThrottled = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "creates_throttled_total",
+		Help:      "Total number of room creations rejected for too many from one IP.",
+	})
+
+	metricWebhooks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "webhook_deliveries_total",
+		Help:      "Total number of finished games posted to room webhooks, by result.",
+	}, []string{"result"})
+
+	metricGamesCompleted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "games_completed_total",
+		Help:      "Total number of games played until a team won.",
+	})
+
+	// pacingBuckets, in seconds, span quick clues to turns a group talks
+	// over at length.
+	pacingBuckets = []float64{5, 10, 20, 30, 45, 60, 90, 120, 180, 300, 600}
+
+	metricTurnClueSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "turn_clue_seconds",
+		Help:      "Time from the start of a turn until its clue, or its end if no clue was given, by board size and whether turns were timed.",
+		Buckets:   pacingBuckets,
+	}, []string{"board", "timed"})
+
+	metricTurnGuessSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "turn_guess_seconds",
+		Help:      "Time from a turn's clue until the turn ended, by board size and whether turns were timed.",
+		Buckets:   pacingBuckets,
+	}, []string{"board", "timed"})
+
+	metricMerges = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "merges_total",
+		Help:      "Total number of rooms merged into another room.",
+	})
+
+	metricMatches = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "matches_total",
+		Help:      "Total number of rooms created for matchmade players.",
+	})
+
+	metricDesyncs = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "desyncs_total",
+		Help:      "Total number of confirmed client desync reports.",
+	})
+
+	metricDesyncResyncs = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "desync_resyncs_total",
+		Help:      "Total number of room-wide resyncs after desync reports.",
+	})
+
+	metricRosterDeltas = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "roster_deltas_total",
+		Help:      "Total number of player list changes broadcast as deltas instead of full states.",
+	})
+
+	metricQualityDegraded = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "quality_degraded_total",
+		Help:      "Total number of times a connection fell behind and was degraded to coalesced states.",
+	})
+
+	metricSlowEvicted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "slow_clients_evicted_total",
+		Help:      "Total number of connections closed for falling so far behind that a queue overflowed.",
+	})
+
+	// A counter, not a gauge: reaps are events, which rate() turns into how
+	// often connections are going quiet.
+	metricConnsReaped = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "connections_reaped_total",
+		Help:      "Total number of connections closed after their pings went unanswered.",
+	})
+
+	metricVersionRollovers = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "version_rollovers_total",
+		Help:      "Total number of room versions restarted after passing the highest version clients can represent.",
+	})
+
+	metricDisconnects = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "disconnects_total",
+		Help:      "Total number of ended connections, by reason.",
+	}, []string{"reason"})
+
 	metricReceived = promauto.NewCounter(prometheus.CounterOpts{
 		Namespace: "codies",
 		Subsystem: "codies",
@@ -34,6 +175,42 @@ var (
 		Help:      "Total number of sent messages.",
 	})
 
+	metricStateBytes = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "state_bytes",
+		Help:      "Size of each state encoded for broadcast, by the view encoded, before compression.",
+		Buckets:   prometheus.ExponentialBuckets(1024, 2, 8),
+	}, []string{"view"})
+
+	metricReceivedBytes = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "received_bytes_total",
+		Help:      "Total number of bytes received on WebSocket connections.",
+	})
+
+	metricSentBytes = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "sent_bytes_total",
+		Help:      "Total number of bytes sent on WebSocket connections.",
+	})
+
+	metricPressureLevel = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "memory_pressure_level",
+		Help:      "How many steps the server has taken to save memory under pressure, from 0 to 4.",
+	})
+
+	metricPressureActions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "memory_pressure_actions_total",
+		Help:      "Total number of things given up to save memory, by action.",
+	}, []string{"action"})
+
 	metricHandleErrors = promauto.NewCounter(prometheus.CounterOpts{
 		Namespace: "codies",
 		Subsystem: "codies",
@@ -0,0 +1,253 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/idgen"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"github.com/zikaeroh/ctxjoin"
+	"github.com/zikaeroh/ctxlog"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"nhooyr.io/websocket"
+)
+
+// maxMirrors is the most mirror connections (and tokens) a room may have.
+const maxMirrors = 4
+
+const closeMirrorRejected websocket.StatusCode = 4403
+
+type connRole int
+
+const (
+	rolePlayer connRole = iota
+	roleMirror
+	roleTracker // A player in a tracker room.
+)
+
+// allowedMethods lists the client methods each role may send. Roles which
+// aren't listed may send anything. Mirrors may send nothing; WebSocket pings
+// are handled by the connection itself. Tracker rooms have no board, so their
+// players may send anything which doesn't need one.
+var allowedMethods = map[connRole]map[protocol.ClientMethod]bool{
+	roleMirror: {},
+	roleTracker: {
+		protocol.NewGameMethod:               true,
+		protocol.EndTurnMethod:               true,
+		protocol.RandomizeTeamsMethod:        true,
+		protocol.ChangeTeamConstraintsMethod: true,
+		protocol.ChangeTeamMethod:            true,
+		protocol.ChangeNicknameMethod:        true,
+		protocol.ChangeRoleMethod:            true,
+		protocol.ChangeTurnModeMethod:        true,
+		protocol.ChangeTurnTimeMethod:        true,
+		protocol.ChangePenaltiesMethod:       true,
+		protocol.ScoreMethod:                 true,
+		protocol.DeclareWinnerMethod:         true,
+		protocol.GiveClueMethod:              true,
+		protocol.ChangeNotificationsMethod:   true,
+		protocol.ChangeSpymastersMethod:      true,
+		protocol.ChangeMirrorDelayMethod:     true,
+		protocol.UpdateOptionsMethod:         true,
+		protocol.ReportDesyncMethod:          true,
+		protocol.MintMirrorTokenMethod:       true,
+		protocol.RevokeMirrorTokenMethod:     true,
+		protocol.GetAuditLogMethod:           true,
+		protocol.SetWebhookMethod:            true,
+		protocol.GetWebhookMethod:            true,
+		protocol.KickMethod:                  true,
+		protocol.BanMethod:                   true,
+		protocol.UnbanMethod:                 true,
+		protocol.GetBansMethod:               true,
+		protocol.RequestMergeMethod:          true,
+		protocol.AnswerMergeMethod:           true,
+		protocol.DebugInfoMethod:             true,
+		protocol.RateGameMethod:              true,
+		protocol.ChatMethod:                  true,
+		protocol.OpenPollMethod:              true,
+		protocol.VoteMethod:                  true,
+		protocol.ClosePollMethod:             true,
+	},
+}
+
+func allowed(role connRole, method protocol.ClientMethod) bool {
+	methods, ok := allowedMethods[role]
+	if !ok {
+		return true
+	}
+	return methods[method]
+}
+
+type mirror struct {
+	token string
+	w     *connWriter
+	send  noteSender
+
+	// For spectators; see cycleSpectators and connStats.
+	takeover bool
+	sent     time.Time // When it was last sent a state.
+	overflow bool      // In the overflow tier; see spectate.go.
+	info     connInfo
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) mintMirrorToken(playerID game.PlayerID) error {
+	if playerID != r.room.Host {
+		return nil
+	}
+
+	if len(r.mirrorTokens) >= maxMirrors {
+		max := maxMirrors
+		return &game.Error{
+			Code:    "mirrorTokenLimit",
+			Message: fmt.Sprintf("Rooms may have at most %d mirror tokens.", max),
+			Limit:   &max,
+		}
+	}
+
+	token := idgen.Token()
+	r.mirrorTokens[token] = true
+	r.audit(auditMintMirrorToken, playerID, auditToken(token))
+
+	if sender := r.players[playerID]; sender != nil {
+		sender(priorityTargeted, protocol.NewMirrorTokenNote(token))
+	}
+	return nil
+}
+
+// revokeMirrorToken revokes a token and disconnects the mirrors using it.
+//
+// Must be called on the room's goroutine.
+func (r *Room) revokeMirrorToken(playerID game.PlayerID, token string) {
+	if playerID != r.room.Host || !r.mirrorTokens[token] {
+		return
+	}
+
+	delete(r.mirrorTokens, token)
+	r.audit(auditRevokeMirrorToken, playerID, auditToken(token))
+
+	for _, m := range r.mirrors {
+		if m.token == token {
+			m.w.close(closeMirrorRejected, "mirror token revoked")
+		}
+	}
+}
+
+// HandleMirrorConn serves a read-only view of the room, as seen by guessers.
+// Mirrors aren't players and can't affect the room; anything they send is
+// discarded.
+func (r *Room) HandleMirrorConn(ctx context.Context, token string, c *websocket.Conn) {
+	mirrorID, _ := r.genPlayerID.Next()
+
+	ctx, cancel := ctxjoin.AddCancel(ctx, r.ctx)
+	defer cancel()
+
+	ctx = ctxlog.With(r.withLogger(ctx), zap.String("mirrorID", mirrorID))
+
+	w := newConnWriter(c)
+	w.now = r.clock.Now
+
+	var refuse func()
+	r.do(func() {
+		if r.closing() {
+			closed := r.closeReason
+			refuse = func() { r.refuseClosed(ctx, w, closed) }
+			return
+		}
+
+		if err := r.mirrorAllowed(token); err != nil {
+			refuse = func() { r.rejectConn(ctx, w, closeMirrorRejected, err) }
+			return
+		}
+
+		if err := r.admitJoin(); err != nil {
+			refuse = func() { r.rejectConn(ctx, w, closeRoomBusy, err) }
+			return
+		}
+
+		m := &mirror{token: token, w: w, send: w.send}
+		r.mirrors[mirrorID] = m
+		r.mirrorCount.Inc()
+		r.counters.mirrors.Inc()
+		r.counters.statsDirty.Store(true)
+		r.sendMirror(m)
+	})
+	if refuse != nil {
+		refuse()
+		return
+	}
+
+	ctxlog.Info(ctx, "mirror connected")
+
+	var reason disconnectReason
+
+	defer func() {
+		r.do(func() {
+			delete(r.mirrors, mirrorID)
+		})
+
+		r.mirrorCount.Dec()
+		r.counters.mirrors.Dec()
+		r.counters.statsDirty.Store(true)
+		metricDisconnects.WithLabelValues(string(reason)).Inc()
+		ctxlog.Info(ctx, "mirror disconnected", zap.String("reason", string(reason)))
+	}()
+
+	connCtx := ctx
+	g, ctx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		return w.run(ctx)
+	})
+
+	g.Go(func() error {
+		return r.keepalive(ctx, w)
+	})
+
+	g.Go(func() error {
+		for {
+			var note protocol.ClientNote
+
+			if err := readClientNote(ctx, c, w, &note); err != nil {
+				return err
+			}
+
+			if r.answerTimeSync(w, &note) {
+				continue
+			}
+
+			// Mirrors aren't players, so there's nothing to handle.
+			if !allowed(roleMirror, note.Method) {
+				ctxlog.Debug(ctx, "dropped note from mirror", zap.String("method", string(note.Method)))
+			}
+		}
+	})
+
+	err := g.Wait()
+	reason = classifyDisconnect(connCtx, w, err)
+	ctxlog.Debug(ctx, "connection ended", zap.String("reason", string(reason)), zap.Error(err))
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) mirrorAllowed(token string) error {
+	if !r.mirrorTokens[token] {
+		return &game.Error{
+			Code:    "mirrorTokenInvalid",
+			Message: "Invalid mirror token.",
+		}
+	}
+
+	if len(r.mirrors) >= maxMirrors {
+		max := maxMirrors
+		return &game.Error{
+			Code:    "mirrorLimit",
+			Message: fmt.Sprintf("Rooms may have at most %d mirrors.", max),
+			Limit:   &max,
+		}
+	}
+
+	return nil
+}
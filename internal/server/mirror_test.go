@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+)
+
+func (c *testClient) mirrorTokens() []string {
+	var tokens []string
+	for _, n := range c.notes {
+		if n.Method == "mirrorToken" {
+			tokens = append(tokens, n.Params.(*protocol.MirrorToken).Token)
+		}
+	}
+	return tokens
+}
+
+func (r *Room) testMirrorToken(t *testing.T, host *testClient) string {
+	t.Helper()
+
+	r.testNote(t, "host", protocol.MintMirrorTokenMethod, &protocol.MintMirrorTokenParams{})
+	tokens := host.mirrorTokens()
+	assert.Assert(t, len(tokens) > 0)
+	return tokens[len(tokens)-1]
+}
+
+// readClose reads until the connection is closed, returning the status.
+func readClose(t *testing.T, c *websocket.Conn) websocket.StatusCode {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for {
+		var note protocol.ServerNote
+		if err := wsjson.Read(ctx, c, &note); err != nil {
+			return websocket.CloseStatus(err)
+		}
+	}
+}
+
+func TestMirrorState(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+	token := r.testMirrorToken(t, host)
+
+	c := dialTestMirror(t, r, token)
+
+	var state protocol.State
+	readNote(t, c, "state", &state)
+	assert.Equal(t, state.PlayerID, "")
+	assert.Equal(t, len(state.RoomState.Teams[0]), 1)
+	assert.Equal(t, len(state.RoomState.Teams[1]), 0)
+
+	for _, row := range state.RoomState.Board {
+		for _, tile := range row {
+			assert.Assert(t, tile.View == nil)
+		}
+	}
+
+	r.hold(func() {
+		assert.Equal(t, len(r.players), 1)
+		assert.Equal(t, r.mirrorCount.Load(), int64(1))
+	})
+}
+
+func TestMirrorCannotAffectRoom(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+	r.setTurn(0)
+	token := r.testMirrorToken(t, host)
+
+	c := dialTestMirror(t, r, token)
+
+	var state protocol.State
+	readNote(t, c, "state", &state)
+	version := state.RoomState.Version
+
+	writeNote(t, c, protocol.RevealMethod, version, &protocol.RevealParams{Row: 0, Col: 0})
+	writeNote(t, c, protocol.ChangeNicknameMethod, version, &protocol.ChangeNicknameParams{Nickname: "mirror"})
+	writeNote(t, c, protocol.MintMirrorTokenMethod, version, &protocol.MintMirrorTokenParams{})
+
+	// Wait for a broadcast caused by someone else.
+	r.testNote(t, "host", protocol.EndTurnMethod, &protocol.EndTurnParams{})
+	readNote(t, c, "state", &state)
+
+	assert.Equal(t, state.RoomState.Version, version+1)
+	assert.Assert(t, !state.RoomState.Board[0][0].Revealed)
+	assert.Equal(t, len(host.mirrorTokens()), 1)
+}
+
+func TestMirrorInvalidToken(t *testing.T) {
+	r := newTestRoom(t)
+	r.addTestClient(t, "host", 0, false)
+
+	c := dialTestMirror(t, r, "bogus")
+
+	var e protocol.Error
+	readNote(t, c, "error", &e)
+	assert.Equal(t, e.Code, "mirrorTokenInvalid")
+	assert.Equal(t, readClose(t, c), closeMirrorRejected)
+}
+
+func TestMirrorLimit(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+	token := r.testMirrorToken(t, host)
+
+	for i := 0; i < maxMirrors; i++ {
+		readNote(t, dialTestMirror(t, r, token), "state", nil)
+	}
+
+	c := dialTestMirror(t, r, token)
+
+	var e protocol.Error
+	readNote(t, c, "error", &e)
+	assert.Equal(t, e.Code, "mirrorLimit")
+	assert.Equal(t, *e.Limit, maxMirrors)
+}
+
+func TestMirrorRevoke(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+	r.addTestClient(t, "other", 1, false)
+	token := r.testMirrorToken(t, host)
+
+	c := dialTestMirror(t, r, token)
+	readNote(t, c, "state", nil)
+
+	// Only the host may revoke.
+	r.testNote(t, "other", protocol.RevokeMirrorTokenMethod, &protocol.RevokeMirrorTokenParams{Token: token})
+	r.hold(func() {
+		assert.Assert(t, r.mirrorTokens[token])
+	})
+
+	r.testNote(t, "host", protocol.RevokeMirrorTokenMethod, &protocol.RevokeMirrorTokenParams{Token: token})
+	assert.Equal(t, readClose(t, c), closeMirrorRejected)
+}
+
+func TestMirrorTokenHostOnly(t *testing.T) {
+	r := newTestRoom(t)
+	r.addTestClient(t, "host", 0, false)
+	other := r.addTestClient(t, "other", 1, false)
+
+	r.testNote(t, "other", protocol.MintMirrorTokenMethod, &protocol.MintMirrorTokenParams{})
+	assert.Equal(t, len(other.mirrorTokens()), 0)
+
+	r.hold(func() {
+		assert.Equal(t, len(r.mirrorTokens), 0)
+	})
+}
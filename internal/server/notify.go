@@ -0,0 +1,131 @@
+package server
+
+import (
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+)
+
+// notifyMask controls which notifications a room emits.
+type notifyMask struct {
+	off      bool
+	disabled map[protocol.NotificationEvent]bool
+}
+
+// enabled is the only place where the mask is consulted; anything which
+// creates notifications must go through it.
+func (m *notifyMask) enabled(event protocol.NotificationEvent) bool {
+	return !m.off && !m.disabled[event]
+}
+
+type notification struct {
+	event protocol.NotificationEvent
+	team  game.Team
+	to    []game.PlayerID // nil means everyone
+}
+
+// turnSnapshot is the state notifications are computed against.
+type turnSnapshot struct {
+	games     int
+	won       bool
+	turn      game.Team
+	clued     bool
+	wordsLeft [][]int // By board.
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) snapshotTurn() turnSnapshot {
+	wordsLeft := make([][]int, len(r.room.Boards))
+	for i, b := range r.room.Boards {
+		wordsLeft[i] = append([]int(nil), b.WordCounts...)
+	}
+
+	return turnSnapshot{
+		games:     r.room.Games,
+		won:       r.room.Winner != nil,
+		turn:      r.room.Turn,
+		clued:     r.room.Clue != nil,
+		wordsLeft: wordsLeft,
+	}
+}
+
+// notifications generates the notifications for the changes made since the
+// snapshot was taken, excluding those masked by the room's options.
+//
+// Must be called on the room's goroutine.
+func (r *Room) notifications(before turnSnapshot) []notification {
+	room := r.room
+	if room.Winner != nil {
+		return nil
+	}
+
+	var notes []notification
+	add := func(n notification) {
+		if r.notify.enabled(n.event) {
+			notes = append(notes, n)
+		}
+	}
+
+	newGame := before.games != room.Games
+
+	if newGame || before.turn != room.Turn {
+		add(notification{
+			event: protocol.NotifyYourTurn,
+			team:  room.Turn,
+			to:    room.Teams[room.Turn],
+		})
+	}
+
+	// With more than one board, a team is notified once per change, even if
+	// several boards reach one card left at the same time.
+	for team := range room.Teams {
+		for i, b := range room.Boards {
+			if b.WordCounts[team] == 1 && (newGame || before.wordsLeft[i][team] != 1) {
+				add(notification{
+					event: protocol.NotifyOneCardLeft,
+					team:  game.Team(team),
+				})
+				break
+			}
+		}
+	}
+
+	return notes
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) sendNotifications(notes []notification) {
+	for _, n := range notes {
+		note := protocol.NewNotificationNote(n.event, n.team)
+
+		if n.to == nil {
+			for _, sender := range r.players {
+				sender(priorityTargeted, note)
+			}
+			continue
+		}
+
+		for _, id := range n.to {
+			if sender := r.players[id]; sender != nil {
+				sender(priorityTargeted, note)
+			}
+		}
+	}
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) changeNotifications(off bool, disabled []protocol.NotificationEvent) {
+	mask := notifyMask{off: off}
+
+	for _, event := range disabled {
+		if !event.Valid() {
+			continue
+		}
+		if mask.disabled == nil {
+			mask.disabled = make(map[protocol.NotificationEvent]bool)
+		}
+		mask.disabled[event] = true
+	}
+
+	r.notify = mask
+	r.room.Version++
+}
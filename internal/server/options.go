@@ -0,0 +1,328 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+)
+
+// options are the room settings which may be changed by updateOptions. Field
+// names match protocol.UpdateOptionsParams.
+type options struct {
+	timed             bool
+	turnTime          int
+	hideBomb          bool
+	boundClues        bool
+	enforceGuessLimit bool
+	suggestClues      bool
+	backfillWords     bool
+	penaltyLimit      int
+	spymasterLimit    int
+	confirmClues      bool
+	mirrorDelay       int
+	boards            int
+	rows              int
+	cols              int
+	bombs             int
+	minSpymasters     int
+	minGuessers       int
+	hintBudget        []int
+	feedback          bool
+	openPolls         bool
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) options() options {
+	return options{
+		timed:             r.timed,
+		turnTime:          r.turnSeconds,
+		hideBomb:          r.hideBomb,
+		boundClues:        r.room.BoundClues,
+		enforceGuessLimit: r.room.EnforceGuessLimit,
+		suggestClues:      r.room.SuggestClues,
+		backfillWords:     r.room.BackfillWords,
+		penaltyLimit:      r.room.PenaltyLimit,
+		spymasterLimit:    r.room.SpymasterLimit,
+		confirmClues:      r.room.ConfirmClues,
+		mirrorDelay:       r.mirrorDelay,
+		boards:            r.room.NumBoards,
+		rows:              r.room.Rows,
+		cols:              r.room.Cols,
+		bombs:             r.room.Bombs,
+		minSpymasters:     r.room.MinSpymasters,
+		minGuessers:       r.room.MinGuessers,
+		hintBudget:        append([]int(nil), r.room.HintBudget...),
+		feedback:          r.feedback.enabled,
+		openPolls:         r.openPolls,
+	}
+}
+
+// values lists the options by their protocol names, in a fixed order.
+func (o options) values() []*protocol.AuditChange {
+	value := func(name string, v interface{}) *protocol.AuditChange {
+		return &protocol.AuditChange{Field: name, After: fmt.Sprint(v)}
+	}
+
+	return []*protocol.AuditChange{
+		value("timed", o.timed),
+		value("turnTime", o.turnTime),
+		value("hideBomb", o.hideBomb),
+		value("boundClues", o.boundClues),
+		value("enforceGuessLimit", o.enforceGuessLimit),
+		value("suggestClues", o.suggestClues),
+		value("backfillWords", o.backfillWords),
+		value("penaltyLimit", o.penaltyLimit),
+		value("spymasterLimit", o.spymasterLimit),
+		value("confirmClues", o.confirmClues),
+		value("mirrorDelay", o.mirrorDelay),
+		value("boards", o.boards),
+		value("rows", o.rows),
+		value("cols", o.cols),
+		value("bombs", o.bombs),
+		value("minSpymasters", o.minSpymasters),
+		value("minGuessers", o.minGuessers),
+		value("hintBudget", o.hintBudget),
+		value("feedback", o.feedback),
+		value("openPolls", o.openPolls),
+	}
+}
+
+// changes lists the options which differ between two sets of options, with
+// their old and new values.
+func (o options) changes(other options) []*protocol.AuditChange {
+	var changes []*protocol.AuditChange
+	after := other.values()
+	for i, v := range o.values() {
+		if v.After != after[i].After {
+			changes = append(changes, &protocol.AuditChange{
+				Field:  v.Field,
+				Before: v.After,
+				After:  after[i].After,
+			})
+		}
+	}
+	return changes
+}
+
+func invalidOption(format string, args ...interface{}) *game.Error {
+	return &game.Error{
+		Code:    "invalidOptions",
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) validateOptions(playerID game.PlayerID, params *protocol.UpdateOptionsParams) error {
+	host := playerID == r.room.Host
+
+	if r.room.Tracker && (params.HideBomb != nil || params.BoundClues != nil || params.EnforceGuessLimit != nil || params.SuggestClues != nil ||
+		params.BackfillWords != nil || params.HintBudget != nil || params.Boards != nil || params.Rows != nil || params.Cols != nil || params.Bombs != nil) {
+		return invalidOption("Tracker rooms have no board options.")
+	}
+
+	if params.TurnTime != nil && *params.TurnTime <= 0 {
+		return invalidOption("Turn time must be positive.")
+	}
+
+	if params.SuggestClues != nil && !host {
+		return invalidOption("Only the host may change clue suggestions.")
+	}
+
+	if params.Feedback != nil && !host {
+		return invalidOption("Only the host may change feedback prompts.")
+	}
+
+	if params.OpenPolls != nil && !host {
+		return invalidOption("Only the host may change who may open polls.")
+	}
+
+	if p := params.PenaltyLimit; p != nil && (*p < 0 || *p > maxPenaltyLimit) {
+		return invalidOption("Penalty limit must be between 0 and %d.", maxPenaltyLimit)
+	}
+
+	if p := params.SpymasterLimit; p != nil && (*p < 0 || *p > game.MaxSpymasters) {
+		return invalidOption("Spymaster limit must be between 0 and %d.", game.MaxSpymasters)
+	}
+
+	if (params.SpymasterLimit != nil || params.ConfirmClues != nil) && r.room.Winner != nil {
+		return invalidOption("Spymaster options can't be changed after the game has ended.")
+	}
+
+	if p := params.MirrorDelay; p != nil {
+		if !host {
+			return invalidOption("Only the host may change the mirror delay.")
+		}
+		if *p != 0 && (*p < minMirrorDelay || *p > maxMirrorDelay) {
+			return invalidOption("Mirror delay must be between %d and %d seconds.", minMirrorDelay, maxMirrorDelay)
+		}
+	}
+
+	if p := params.MinSpymasters; p != nil && (*p < 0 || *p > game.MaxStartMinimum) {
+		return invalidOption("Minimum spymasters must be between 0 and %d.", game.MaxStartMinimum)
+	}
+
+	if p := params.MinGuessers; p != nil && (*p < 0 || *p > game.MaxStartMinimum) {
+		return invalidOption("Minimum guessers must be between 0 and %d.", game.MaxStartMinimum)
+	}
+
+	if params.HintBudget != nil {
+		var gErr *game.Error
+		if err := r.room.CheckHintBudget(params.HintBudget); errors.As(err, &gErr) {
+			return invalidOption("%s", gErr.Message)
+		}
+	}
+
+	// Options which are only invalid in combination.
+	limit := r.room.SpymasterLimit
+	if params.SpymasterLimit != nil {
+		limit = *params.SpymasterLimit
+	}
+	confirm := r.room.ConfirmClues
+	if params.ConfirmClues != nil {
+		confirm = *params.ConfirmClues
+	}
+	if confirm && limit == 1 {
+		return invalidOption("Clue confirmation requires more than one spymaster per team.")
+	}
+
+	minSpymasters := r.room.MinSpymasters
+	if params.MinSpymasters != nil {
+		minSpymasters = *params.MinSpymasters
+	}
+	if limit != 0 && minSpymasters > limit {
+		return invalidOption("Teams can't need more spymasters than they may have.")
+	}
+
+	if params.Boards != nil || params.Rows != nil || params.Cols != nil || params.Bombs != nil {
+		count, rows, cols, bombs := r.boardOptions(params)
+		var gErr *game.Error
+		if err := r.room.CheckBoards(count, rows, cols, bombs); errors.As(err, &gErr) {
+			return invalidOption("%s", gErr.Message)
+		}
+	}
+
+	return nil
+}
+
+// boardOptions returns the board options after params are applied.
+//
+// Must be called on the room's goroutine.
+func (r *Room) boardOptions(params *protocol.UpdateOptionsParams) (count, rows, cols, bombs int) {
+	count, rows, cols, bombs = r.room.NumBoards, r.room.Rows, r.room.Cols, r.room.Bombs
+	if params.Boards != nil {
+		count = *params.Boards
+	}
+	if params.Rows != nil {
+		rows = *params.Rows
+	}
+	if params.Cols != nil {
+		cols = *params.Cols
+	}
+	if params.Bombs != nil {
+		bombs = *params.Bombs
+	}
+	return count, rows, cols, bombs
+}
+
+// updateOptions validates a partial set of options as a whole, then applies
+// all of it or none of it. It returns the names of the options which changed,
+// and records the change in the audit log.
+//
+// Must be called on the room's goroutine.
+func (r *Room) updateOptions(playerID game.PlayerID, params *protocol.UpdateOptionsParams) ([]string, error) {
+	if err := r.validateOptions(playerID, params); err != nil {
+		return nil, err
+	}
+
+	before := r.options()
+
+	// The turn time goes first, so that enabling timed turns at the same time
+	// starts the timer with the new time.
+	if params.TurnTime != nil {
+		r.changeTurnTime(*params.TurnTime)
+	}
+	if params.Timed != nil {
+		r.changeTurnMode(*params.Timed)
+	}
+	if params.HideBomb != nil {
+		r.changeHideBomb(*params.HideBomb)
+	}
+	if params.Feedback != nil {
+		r.changeFeedback(*params.Feedback)
+	}
+	if params.OpenPolls != nil {
+		r.changeOpenPolls(*params.OpenPolls)
+	}
+	if params.BoundClues != nil {
+		r.room.ChangeBoundClues(*params.BoundClues)
+	}
+	if params.EnforceGuessLimit != nil {
+		r.room.ChangeEnforceGuessLimit(*params.EnforceGuessLimit)
+	}
+	if params.SuggestClues != nil {
+		r.room.ChangeSuggestClues(playerID, *params.SuggestClues)
+	}
+	if params.BackfillWords != nil {
+		r.room.ChangeBackfillWords(*params.BackfillWords)
+	}
+	if params.PenaltyLimit != nil {
+		r.room.ChangePenaltyLimit(*params.PenaltyLimit)
+	}
+	if params.SpymasterLimit != nil || params.ConfirmClues != nil {
+		limit := r.room.SpymasterLimit
+		if params.SpymasterLimit != nil {
+			limit = *params.SpymasterLimit
+		}
+		confirm := r.room.ConfirmClues
+		if params.ConfirmClues != nil {
+			confirm = *params.ConfirmClues
+		}
+		r.room.ChangeSpymasters(limit, confirm)
+	}
+	if params.MirrorDelay != nil {
+		r.changeMirrorDelay(playerID, *params.MirrorDelay)
+	}
+	if params.MinSpymasters != nil || params.MinGuessers != nil {
+		spymasters, guessers := r.room.MinSpymasters, r.room.MinGuessers
+		if params.MinSpymasters != nil {
+			spymasters = *params.MinSpymasters
+		}
+		if params.MinGuessers != nil {
+			guessers = *params.MinGuessers
+		}
+		r.room.ChangeStartMinimums(spymasters, guessers)
+	}
+	if params.HintBudget != nil {
+		if err := r.room.ChangeHintBudget(params.HintBudget); err != nil {
+			return nil, err
+		}
+	}
+	if params.Boards != nil || params.Rows != nil || params.Cols != nil || params.Bombs != nil {
+		if err := r.room.ChangeBoards(r.boardOptions(params)); err != nil {
+			return nil, err
+		}
+	}
+
+	changes := before.changes(r.options())
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	r.audit(auditOptions, playerID, "", changes...)
+
+	fields := make([]string, len(changes))
+	for i, c := range changes {
+		fields[i] = c.Field
+	}
+	return fields, nil
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) sendOptionsChanged(fields []string) {
+	note := protocol.NewOptionsChangedNote(fields)
+	for _, sender := range r.players {
+		sender(priorityBroadcast, note)
+	}
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func boolPtr(b bool) *bool { return &b }
+func intPtr(i int) *int    { return &i }
+
+func (c *testClient) optionsChanged() [][]string {
+	var changes [][]string
+	for _, n := range c.notes {
+		if n.Method == "optionsChanged" {
+			changes = append(changes, n.Params.(*protocol.OptionsChanged).Fields)
+		}
+	}
+	return changes
+}
+
+func (c *testClient) states() int {
+	count := 0
+	for _, n := range c.notes {
+		if n.Method == "state" {
+			count++
+		}
+	}
+	return count
+}
+
+func TestUpdateOptionsAtomic(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+	other := r.addTestClient(t, "other", 1, false)
+	states := other.states()
+
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{
+		Timed:          boolPtr(true),
+		TurnTime:       intPtr(90),
+		BoundClues:     boolPtr(true),
+		SpymasterLimit: intPtr(2),
+		ConfirmClues:   boolPtr(true),
+	})
+
+	assert.Equal(t, len(host.errors()), 0)
+
+	// Everything was broadcast at once.
+	assert.Equal(t, other.states(), states+1)
+	assert.DeepEqual(t, other.optionsChanged(), [][]string{{"timed", "turnTime", "boundClues", "spymasterLimit", "confirmClues"}})
+
+	state := other.lastState().RoomState
+	assert.Equal(t, state.Timer.TurnTime, 90)
+	assert.Assert(t, state.BoundClues)
+	assert.Equal(t, state.Spymasters.Limit, 2)
+	assert.Assert(t, state.Spymasters.ConfirmClues)
+}
+
+func TestUpdateOptionsJointlyInvalid(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+	other := r.addTestClient(t, "other", 1, false)
+
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{SpymasterLimit: intPtr(1)})
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{SpymasterLimit: intPtr(2), ConfirmClues: boolPtr(true)})
+	assert.Equal(t, len(host.errors()), 0)
+
+	version := r.version()
+	states := other.states()
+
+	// Each would be valid alone, but not together: confirmation needs more
+	// than one spymaster.
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{
+		Timed:          boolPtr(true),
+		HideBomb:       boolPtr(true),
+		SpymasterLimit: intPtr(1),
+	})
+
+	errs := host.errors()
+	assert.Equal(t, len(errs), 1)
+	assert.Equal(t, errs[0].Code, "invalidOptions")
+
+	// Nothing was applied, including the valid options.
+	assert.Equal(t, r.version(), version)
+	assert.Equal(t, other.states(), states)
+	assert.Equal(t, len(other.optionsChanged()), 2)
+
+	r.hold(func() {
+		assert.Assert(t, !r.timed)
+		assert.Assert(t, !r.hideBomb)
+		assert.Equal(t, r.room.SpymasterLimit, 2)
+	})
+}
+
+func TestUpdateOptionsHostOnly(t *testing.T) {
+	r := newTestRoom(t)
+	r.addTestClient(t, "host", 0, false)
+	other := r.addTestClient(t, "other", 1, false)
+
+	r.testNote(t, "other", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{
+		BoundClues:  boolPtr(true),
+		MirrorDelay: intPtr(30),
+	})
+
+	assert.Equal(t, len(other.errors()), 1)
+
+	r.hold(func() {
+		assert.Assert(t, !r.room.BoundClues)
+		assert.Equal(t, r.mirrorDelay, 0)
+	})
+}
+
+func TestFieldCommandsUseOptions(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+
+	r.testNote(t, "host", protocol.ChangeHideBombMethod, &protocol.ChangeHideBombParams{HideBomb: true})
+	r.testNote(t, "host", protocol.ChangeHideBombMethod, &protocol.ChangeHideBombParams{HideBomb: true})
+	assert.DeepEqual(t, host.optionsChanged(), [][]string{{"hideBomb"}})
+
+	r.testNote(t, "host", protocol.ChangePenaltiesMethod, &protocol.ChangePenaltiesParams{Limit: maxPenaltyLimit + 1})
+	errs := host.errors()
+	assert.Equal(t, len(errs), 1)
+	assert.Equal(t, errs[0].Code, "invalidOptions")
+}
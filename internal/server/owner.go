@@ -0,0 +1,80 @@
+package server
+
+import "time"
+
+// Each room's state belongs to one goroutine, the room's owner, and nothing
+// else touches it. Everything else which needs the room, whether a
+// connection's reader handling a note, an HTTP or admin handler, one of the
+// server's sweeps, or a timer, sends the owner a command with do and waits for
+// it to run. Commands run one at a time, in the order they're sent, so a
+// command sees the room as the last one left it, and no other.
+//
+// The first command starts the owner. Once the room's context is done, the
+// owner stops as soon as no commands are waiting. Stragglers, like the
+// cleanup of connections the room closed, start it again.
+
+// A command is a function to run on the room's goroutine.
+type command struct {
+	f    func()
+	done chan interface{} // Receives what f panicked with, or nil.
+}
+
+// do runs f on the room's goroutine, waiting until it returns. Should f panic,
+// so does do, with the same value, rather than the owner.
+//
+// do must not be called on the room's goroutine, which would wait on itself;
+// functions which expect to be on it say so.
+func (r *Room) do(f func()) {
+	c := command{f: f, done: make(chan interface{}, 1)}
+
+	r.ownerMu.Lock()
+	r.pending++
+	if !r.owning {
+		r.owning = true
+		go r.own()
+	}
+	r.ownerMu.Unlock()
+
+	r.cmds <- c
+	if p := <-c.done; p != nil {
+		panic(p)
+	}
+}
+
+// own is the room's goroutine.
+func (r *Room) own() {
+	for {
+		select {
+		case c := <-r.cmds:
+			r.run(c)
+		case <-r.ctx.Done():
+			r.ownerMu.Lock()
+			if r.pending == 0 {
+				r.owning = false
+				r.ownerMu.Unlock()
+				return
+			}
+			r.ownerMu.Unlock()
+			// A command is on its way.
+			r.run(<-r.cmds)
+		}
+	}
+}
+
+func (r *Room) run(c command) {
+	r.ownerMu.Lock()
+	r.pending--
+	r.ownerMu.Unlock()
+
+	defer func() {
+		c.done <- recover()
+	}()
+	c.f()
+}
+
+// after runs f on the room's goroutine once d has passed, unless the timer is
+// stopped first. f may still run after the timer is stopped, should it have
+// fired just before, so it must check that it's still wanted.
+func (r *Room) after(d time.Duration, f func()) timer {
+	return r.clock.AfterFunc(d, func() { r.do(f) })
+}
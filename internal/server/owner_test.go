@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+// hold runs f on the caller's goroutine as though it were on the room's,
+// which waits for f to return. Tests use it rather than do so that f may fail
+// the test, which only the test's own goroutine may do.
+func (r *Room) hold(f func()) {
+	held := make(chan struct{})
+	release := make(chan struct{})
+	go r.do(func() {
+		close(held)
+		<-release
+	})
+
+	<-held
+	defer close(release)
+	f()
+}
+
+func TestRoomCommandsSerialized(t *testing.T) {
+	r := newTestRoom(t)
+
+	const n = 100
+	count := 0 // Unguarded; the race detector checks the owner's enough.
+
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			r.do(func() { count++ })
+		}()
+	}
+	wg.Wait()
+
+	r.hold(func() {
+		assert.Equal(t, count, n)
+	})
+}
+
+func TestRoomCommandPanics(t *testing.T) {
+	r := newTestRoom(t)
+
+	func() {
+		defer func() {
+			assert.Equal(t, recover(), "boom")
+		}()
+		r.do(func() { panic("boom") })
+		t.Fatal("do returned")
+	}()
+
+	// The owner carries on.
+	ran := false
+	r.do(func() { ran = true })
+	assert.Assert(t, ran)
+}
+
+func TestRoomOwnerStops(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := newRoom(ctx, "test", nil, "test", &counters{})
+	t.Cleanup(cancel)
+
+	r.do(func() {})
+	r.cancel()
+
+	waitOwner := func(want bool) {
+		t.Helper()
+		for i := 0; ; i++ {
+			r.ownerMu.Lock()
+			owning := r.owning
+			r.ownerMu.Unlock()
+			if owning == want {
+				return
+			}
+			assert.Assert(t, i < 500, "owning is %v, want %v", owning, want)
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	waitOwner(false)
+
+	// Commands sent after the room's closed still run.
+	ran := false
+	r.do(func() { ran = true })
+	assert.Assert(t, ran)
+	waitOwner(false)
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Turns are timed in two phases: the clue phase, from the start of the turn
+// until its clue is given, and the guess phase, from the clue until the turn
+// ends. A turn which ends without a clue only has a clue phase. The turn a new
+// game interrupts isn't counted, as it never finished. Undoing a reveal puts
+// the pacing back as it was before the reveal, so the turn carries on.
+
+// turnPacing times the current turn's phases, and totals those of the room's
+// finished turns. The totals have their own lock, so that stats can be read
+// without the room's.
+type turnPacing struct {
+	turnStart time.Time
+	clueGiven time.Time // Zero until the turn's clue is given.
+
+	mu         sync.Mutex
+	turns      int
+	guessTurns int
+	clue       time.Duration
+	guess      time.Duration
+}
+
+// PacingStats describe how long a room's turns take, over every turn it's
+// finished.
+type PacingStats struct {
+	Turns            int     `json:"turns"`
+	MeanClueSeconds  float64 `json:"meanClueSeconds"`
+	GuessTurns       int     `json:"guessTurns"` // Turns which got as far as a clue.
+	MeanGuessSeconds float64 `json:"meanGuessSeconds"`
+}
+
+// Must be called on the room's goroutine.
+func (p *turnPacing) start(now time.Time) {
+	p.turnStart = now
+	p.clueGiven = time.Time{}
+}
+
+// pacingUndo is the pacing as it was before a reveal, for an undo of the
+// reveal to restore.
+type pacingUndo struct {
+	turnStart  time.Time
+	clueGiven  time.Time
+	turns      int
+	guessTurns int
+	clue       time.Duration
+	guess      time.Duration
+}
+
+// Must be called on the room's goroutine.
+func (p *turnPacing) save() pacingUndo {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return pacingUndo{
+		turnStart:  p.turnStart,
+		clueGiven:  p.clueGiven,
+		turns:      p.turns,
+		guessTurns: p.guessTurns,
+		clue:       p.clue,
+		guess:      p.guess,
+	}
+}
+
+// restore puts the pacing back as it was saved, taking back any turn the
+// undone reveal finished. The metrics can't take back their samples, so they
+// keep it.
+//
+// Must be called on the room's goroutine.
+func (p *turnPacing) restore(u pacingUndo) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.turnStart = u.turnStart
+	p.clueGiven = u.clueGiven
+	p.turns = u.turns
+	p.guessTurns = u.guessTurns
+	p.clue = u.clue
+	p.guess = u.guess
+}
+
+func (p *turnPacing) add(clue, guess time.Duration, guessed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.turns++
+	p.clue += clue
+	if guessed {
+		p.guessTurns++
+		p.guess += guess
+	}
+}
+
+func (p *turnPacing) stats() PacingStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s := PacingStats{Turns: p.turns, GuessTurns: p.guessTurns}
+	if p.turns != 0 {
+		s.MeanClueSeconds = p.clue.Seconds() / float64(p.turns)
+	}
+	if p.guessTurns != 0 {
+		s.MeanGuessSeconds = p.guess.Seconds() / float64(p.guessTurns)
+	}
+	return s
+}
+
+// recordPacing times the turn phases which began or ended since the snapshot
+// was taken.
+//
+// Must be called on the room's goroutine.
+func (r *Room) recordPacing(before turnSnapshot) {
+	p := &r.pacing
+	now := r.clock.Now()
+
+	if before.games != r.room.Games {
+		p.start(now)
+		return
+	}
+	if before.won {
+		return
+	}
+
+	if !before.clued && r.room.Clue != nil && before.turn == r.room.Turn {
+		p.clueGiven = now
+	}
+
+	if before.turn != r.room.Turn || r.room.Winner != nil {
+		r.observeTurn(now)
+		p.start(now)
+	}
+}
+
+// observeTurn records the phases of the turn which just ended.
+//
+// Must be called on the room's goroutine.
+func (r *Room) observeTurn(now time.Time) {
+	p := &r.pacing
+
+	clue := now.Sub(p.turnStart)
+	var guess time.Duration
+	guessed := !p.clueGiven.IsZero()
+	if guessed {
+		clue = p.clueGiven.Sub(p.turnStart)
+		guess = now.Sub(p.clueGiven)
+	}
+
+	labels := prometheus.Labels{"board": r.boardSizeBucket(), "timed": strconv.FormatBool(r.timed)}
+	metricTurnClueSeconds.With(labels).Observe(clue.Seconds())
+	if guessed {
+		metricTurnGuessSeconds.With(labels).Observe(guess.Seconds())
+	}
+
+	p.add(clue, guess, guessed)
+}
+
+// boardSizeBucket groups the sizes of the boards in play, for metrics.
+//
+// Must be called on the room's goroutine.
+func (r *Room) boardSizeBucket() string {
+	if r.room.Tracker {
+		return "tracker"
+	}
+
+	switch tiles := r.room.Board.Rows * r.room.Board.Cols; {
+	case tiles < 25:
+		return "small"
+	case tiles == 25:
+		return "classic"
+	default:
+		return "large"
+	}
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func newPacingTestRoom(t *testing.T) (*Room, *fakeClock) {
+	t.Helper()
+
+	r := newTestRoom(t)
+	c := newFakeClock()
+	r.setClock(c)
+	r.addTestClient(t, "s0", 0, true)
+	r.addTestClient(t, "s1", 1, true)
+	r.addTestClient(t, "g0", 0, false)
+	r.addTestClient(t, "g1", 1, false)
+
+	r.setTurn(0)
+
+	// As when the room is created.
+	r.hold(func() {
+		r.pacing.start(c.Now())
+	})
+	return r, c
+}
+
+func samples(t *testing.T, h *prometheus.HistogramVec, board, timed string) uint64 {
+	t.Helper()
+
+	var m dto.Metric
+	assert.NilError(t, h.WithLabelValues(board, timed).(prometheus.Histogram).Write(&m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestTurnPacing(t *testing.T) {
+	r, c := newPacingTestRoom(t)
+	clues := samples(t, metricTurnClueSeconds, "classic", "false")
+	guesses := samples(t, metricTurnGuessSeconds, "classic", "false")
+
+	// A clue after 20s, then 40s of guessing.
+	c.Advance(20 * time.Second)
+	r.testNote(t, "s0", protocol.GiveClueMethod, &protocol.GiveClueParams{Word: "ANIMAL", Count: 2})
+	c.Advance(30 * time.Second)
+	r.revealOwn(t, "g0", 0)
+	c.Advance(10 * time.Second)
+	r.testNote(t, "g0", protocol.EndTurnMethod, &protocol.EndTurnParams{})
+
+	// A turn passed after 90s without a clue.
+	c.Advance(90 * time.Second)
+	r.testNote(t, "g1", protocol.EndTurnMethod, &protocol.EndTurnParams{})
+
+	// The game ends 5s after a clue given in 15s.
+	c.Advance(15 * time.Second)
+	r.testNote(t, "s0", protocol.GiveClueMethod, &protocol.GiveClueParams{Word: "OCEAN", Count: 1})
+	c.Advance(5 * time.Second)
+	r.revealBomb(t, "g0")
+
+	// Time spent looking at the finished board isn't a turn.
+	c.Advance(10 * time.Minute)
+	r.testNote(t, r.host(), protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+
+	assert.DeepEqual(t, r.stats().Pacing, PacingStats{
+		Turns:            3,
+		MeanClueSeconds:  (20 + 90 + 15) / 3.0,
+		GuessTurns:       2,
+		MeanGuessSeconds: (40 + 5) / 2.0,
+	})
+	assert.Equal(t, samples(t, metricTurnClueSeconds, "classic", "false"), clues+3)
+	assert.Equal(t, samples(t, metricTurnGuessSeconds, "classic", "false"), guesses+2)
+}
+
+func TestTurnPacingNewGame(t *testing.T) {
+	r, c := newPacingTestRoom(t)
+
+	// A turn cut short by a new game never finished.
+	c.Advance(20 * time.Second)
+	r.testNote(t, "s0", protocol.GiveClueMethod, &protocol.GiveClueParams{Word: "ANIMAL", Count: 2})
+	c.Advance(20 * time.Second)
+	r.testNote(t, r.host(), protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	assert.Equal(t, r.stats().Pacing.Turns, 0)
+
+	// The next game's first turn starts with it.
+	guesser := map[game.Team]game.PlayerID{0: "g0", 1: "g1"}[r.turn()]
+	c.Advance(30 * time.Second)
+	r.testNote(t, guesser, protocol.EndTurnMethod, &protocol.EndTurnParams{})
+	assert.DeepEqual(t, r.stats().Pacing, PacingStats{Turns: 1, MeanClueSeconds: 30})
+}
+
+func TestTurnPacingTimed(t *testing.T) {
+	r, c := newTimedTestRoom(t, 0)
+	r.hold(func() {
+		r.pacing.start(c.Now())
+	})
+	clues := samples(t, metricTurnClueSeconds, "classic", "true")
+
+	// An expired turn counts from when it started.
+	c.Advance(60 * time.Second)
+	assert.Equal(t, samples(t, metricTurnClueSeconds, "classic", "true"), clues+1)
+	assert.DeepEqual(t, r.stats().Pacing, PacingStats{Turns: 1, MeanClueSeconds: 60})
+}
+
+func TestTurnPacingUndo(t *testing.T) {
+	r, c := newPacingTestRoom(t)
+	clues := samples(t, metricTurnClueSeconds, "classic", "false")
+
+	// A clue after 20s, then a guess which ends the turn 30s later.
+	c.Advance(20 * time.Second)
+	r.testNote(t, "s0", protocol.GiveClueMethod, &protocol.GiveClueParams{Word: "ANIMAL", Count: 2})
+	c.Advance(30 * time.Second)
+	r.revealOwn(t, "g0", 1)
+	assert.Equal(t, r.stats().Pacing.Turns, 1)
+
+	// Undoing it carries the turn on, as though the guess was never made.
+	c.Advance(10 * time.Second)
+	r.testNote(t, r.host(), protocol.UndoMethod, &protocol.UndoParams{})
+	assert.Equal(t, r.stats().Pacing.Turns, 0)
+
+	c.Advance(10 * time.Second)
+	r.testNote(t, "g0", protocol.EndTurnMethod, &protocol.EndTurnParams{})
+	assert.DeepEqual(t, r.stats().Pacing, PacingStats{
+		Turns:            1,
+		MeanClueSeconds:  20,
+		GuessTurns:       1,
+		MeanGuessSeconds: 50,
+	})
+
+	// The histograms keep the undone turn; the undo itself adds nothing.
+	assert.Equal(t, samples(t, metricTurnClueSeconds, "classic", "false"), clues+2)
+}
+
+func TestTurnPacingUndoWin(t *testing.T) {
+	r, c := newPacingTestRoom(t)
+
+	// The game ends 5s after a clue given in 15s, and is reopened.
+	c.Advance(15 * time.Second)
+	r.testNote(t, "s0", protocol.GiveClueMethod, &protocol.GiveClueParams{Word: "OCEAN", Count: 1})
+	c.Advance(5 * time.Second)
+	r.revealBomb(t, "g0")
+	c.Advance(time.Minute)
+	r.testNote(t, r.host(), protocol.UndoMethod, &protocol.UndoParams{})
+
+	// The reopened turn's guessing still counts from its clue.
+	c.Advance(10 * time.Second)
+	r.testNote(t, "g0", protocol.EndTurnMethod, &protocol.EndTurnParams{})
+	assert.DeepEqual(t, r.stats().Pacing, PacingStats{
+		Turns:            1,
+		MeanClueSeconds:  15,
+		GuessTurns:       1,
+		MeanGuessSeconds: 75,
+	})
+}
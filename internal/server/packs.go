@@ -0,0 +1,76 @@
+package server
+
+import (
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+)
+
+// addPacks adds custom packs. Only the host may add packs. Each pack's words
+// are cleaned as a new room's are; if any pack is refused, none are added.
+//
+// Must be called on the room's goroutine.
+func (r *Room) addPacks(playerID game.PlayerID, params *protocol.AddPacksParams) error {
+	packs := make([]customPack, len(params.Packs))
+	for i, p := range params.Packs {
+		packs[i] = customPack{name: p.Name, words: p.Words}
+	}
+	return r.addCustomPacks(playerID, packs)
+}
+
+type customPack struct {
+	name  string
+	words []string
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) addCustomPacks(playerID game.PlayerID, packs []customPack, changes ...*protocol.AuditChange) error {
+	if playerID != r.room.Host {
+		return nil
+	}
+
+	cleaned := make([][]string, len(packs))
+	for i, p := range packs {
+		wds, err := game.CleanWords(p.words)
+		if err != nil {
+			return err
+		}
+		cleaned[i] = wds
+	}
+
+	before := len(r.room.WordLists)
+	for i, p := range packs {
+		if err := r.room.AddPack(p.name, cleaned[i]); err != nil {
+			lists := r.room.WordLists
+			for j := before; j < len(lists); j++ {
+				lists[j] = nil
+			}
+			r.room.WordLists = lists[:before]
+			return err
+		}
+	}
+
+	for _, p := range packs {
+		r.audit(auditAddPack, playerID, p.name, changes...)
+	}
+	return nil
+}
+
+// removePack removes a custom pack. Only the host may remove packs.
+//
+// Must be called on the room's goroutine.
+func (r *Room) removePack(playerID game.PlayerID, num int) error {
+	if playerID != r.room.Host || num < 0 || num >= len(r.room.WordLists) {
+		return nil
+	}
+
+	name := r.room.WordLists[num].Name
+	before := len(r.room.WordLists)
+	if err := r.room.RemovePack(num); err != nil {
+		return err
+	}
+
+	if len(r.room.WordLists) != before {
+		r.audit(auditRemovePack, playerID, name)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+type testPackParam struct {
+	Name  string   `json:"name"`
+	Words []string `json:"words"`
+}
+
+func packWords(prefix string, n int) []string {
+	wds := make([]string, n)
+	for i := range wds {
+		wds[i] = fmt.Sprintf("%s%d", prefix, i)
+	}
+	return wds
+}
+
+func addPacksParams(packs ...testPackParam) interface{} {
+	return map[string]interface{}{"packs": packs}
+}
+
+// packCount returns how many packs the room has, custom ones included.
+func (r *Room) packCount() int {
+	var n int
+	r.hold(func() {
+		n = len(r.room.WordLists)
+	})
+	return n
+}
+
+func TestAddPacks(t *testing.T) {
+	r := newTestRoom(t)
+	r.addTestClient(t, "host", 0, false)
+	other := r.addTestClient(t, "other", 1, false)
+	before := r.packCount()
+
+	r.testNote(t, "host", protocol.AddPacksMethod, addPacksParams(testPackParam{Name: "mine", Words: packWords("w", 30)}))
+
+	lists := other.lastState().RoomState.Lists
+	assert.Equal(t, len(lists), before+1)
+	assert.DeepEqual(t, lists[before], &protocol.StateWordList{Name: "mine", Count: 30, Custom: true})
+
+	entry := findAudit(r.AuditLog(), auditAddPack)
+	assert.Assert(t, entry != nil)
+	assert.Equal(t, entry.Target, "mine")
+
+	// New games draw from the packs as they are now.
+	for i := 0; i < before; i++ {
+		r.testNote(t, "host", protocol.ChangePackMethod, &protocol.ChangePackParams{Num: before, Enable: true})
+		r.testNote(t, "host", protocol.ChangePackMethod, &protocol.ChangePackParams{Num: i, Enable: false})
+	}
+	r.testNote(t, "host", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+
+	r.hold(func() {
+		b := r.room.Board
+		for row := 0; row < b.Rows; row++ {
+			for col := 0; col < b.Cols; col++ {
+				word := b.Get(row, col).Word
+				assert.Assert(t, word[0] == 'W', "%q isn't from the new pack", word)
+			}
+		}
+	})
+}
+
+func TestAddPacksHostOnly(t *testing.T) {
+	r := newTestRoom(t)
+	r.addTestClient(t, "host", 0, false)
+	r.addTestClient(t, "other", 1, false)
+	before := r.packCount()
+
+	r.testNote(t, "other", protocol.AddPacksMethod, addPacksParams(testPackParam{Name: "theirs", Words: packWords("w", 30)}))
+
+	r.hold(func() {
+		assert.Equal(t, len(r.room.WordLists), before)
+		assert.Equal(t, len(r.auditLog), 0)
+	})
+}
+
+func TestAddPacksInvalid(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+	before := r.packCount()
+
+	// One bad pack refuses them all.
+	r.testNote(t, "host", protocol.AddPacksMethod, addPacksParams(
+		testPackParam{Name: "good", Words: packWords("w", 30)},
+		testPackParam{Name: "bad", Words: packWords("w", 10)},
+	))
+
+	errs := host.errors()
+	assert.Equal(t, len(errs), 1)
+	assert.Equal(t, errs[0].Code, "tooFewWords")
+
+	// As does one which doesn't fit.
+	r.hold(func() {
+		r.room.PackBudget.Packs = 1
+	})
+	r.testNote(t, "host", protocol.AddPacksMethod, addPacksParams(
+		testPackParam{Name: "one", Words: packWords("w", 30)},
+		testPackParam{Name: "two", Words: packWords("v", 30)},
+	))
+
+	errs = host.errors()
+	assert.Equal(t, len(errs), 2)
+	assert.Equal(t, errs[1].Code, "packLimit")
+
+	r.hold(func() {
+		assert.Equal(t, len(r.room.WordLists), before)
+		assert.Equal(t, len(r.auditLog), 0)
+	})
+}
+
+func TestRemovePack(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+	other := r.addTestClient(t, "other", 1, false)
+	r.testNote(t, "host", protocol.AddPacksMethod, addPacksParams(testPackParam{Name: "mine", Words: packWords("w", 30)}))
+	custom := r.packCount() - 1
+
+	// With nothing else enabled, the pack is needed for the board.
+	r.testNote(t, "host", protocol.ChangePackMethod, &protocol.ChangePackParams{Num: custom, Enable: true})
+	for i := 0; i < custom; i++ {
+		r.testNote(t, "host", protocol.ChangePackMethod, &protocol.ChangePackParams{Num: i, Enable: false})
+	}
+
+	r.testNote(t, "host", protocol.RemovePackMethod, &protocol.RemovePackParams{Num: custom})
+	errs := host.errors()
+	assert.Equal(t, len(errs), 1)
+	assert.Equal(t, errs[0].Code, "packNeeded")
+	assert.Equal(t, len(other.lastState().RoomState.Lists), custom+1)
+
+	// Only the host may remove it.
+	r.testNote(t, "host", protocol.ChangePackMethod, &protocol.ChangePackParams{Num: 0, Enable: true})
+	r.testNote(t, "other", protocol.RemovePackMethod, &protocol.RemovePackParams{Num: custom})
+	assert.Equal(t, len(other.lastState().RoomState.Lists), custom+1)
+
+	// Once another pack covers the board, it goes, even enabled.
+	r.testNote(t, "host", protocol.RemovePackMethod, &protocol.RemovePackParams{Num: custom})
+	assert.Equal(t, len(other.lastState().RoomState.Lists), custom)
+
+	entry := findAudit(r.AuditLog(), auditRemovePack)
+	assert.Assert(t, entry != nil)
+	assert.Equal(t, entry.Target, "mine")
+}
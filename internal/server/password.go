@@ -0,0 +1,46 @@
+package server
+
+import (
+	"errors"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Room passwords are kept as bcrypt hashes, so a room's memory never holds the
+// password itself. bcrypt only uses the first 72 bytes of a password, so longer
+// ones are refused rather than cut short, which would let any password with the
+// same start in.
+var passwordCost = bcrypt.DefaultCost // Lowered in tests.
+
+// ErrPasswordTooLong is returned when creating a room with a password longer
+// than protocol.MaxRoomPass.
+var ErrPasswordTooLong = errors.New("server: room password too long")
+
+// passwordHash is a hashed room password, salt and cost included. The zero
+// value is a room without a password, which only the empty password matches.
+type passwordHash []byte
+
+func hashPassword(password string) (passwordHash, error) {
+	if password == "" {
+		return nil, nil
+	}
+	if len(password) > protocol.MaxRoomPass {
+		return nil, ErrPasswordTooLong
+	}
+
+	return bcrypt.GenerateFromPassword([]byte(password), passwordCost)
+}
+
+func (h passwordHash) check(password string) bool {
+	if h == nil {
+		return password == ""
+	}
+
+	return bcrypt.CompareHashAndPassword(h, []byte(password)) == nil
+}
+
+// CheckPassword reports whether password is the room's password.
+func (r *Room) CheckPassword(password string) bool {
+	return r.password.check(password)
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"golang.org/x/crypto/bcrypt"
+	"gotest.tools/v3/assert"
+)
+
+func TestMain(m *testing.M) {
+	// Every test room has a password, and hashing each at the real cost would
+	// slow the tests down for nothing.
+	passwordCost = bcrypt.MinCost
+	os.Exit(m.Run())
+}
+
+func TestPasswordHash(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		right    []string
+		wrong    []string
+	}{
+		{
+			name:     "ascii",
+			password: "hunter2",
+			right:    []string{"hunter2"},
+			wrong:    []string{"", "hunter", "hunter22", "Hunter2", " hunter2"},
+		},
+		{
+			name:     "unicode",
+			password: "pässwörd🔑",
+			right:    []string{"pässwörd🔑"},
+			// The same text, decomposed, is a different password.
+			wrong: []string{"", "password", "pässwörd", "pässwörd🔑"},
+		},
+		{
+			name:     "empty",
+			password: "",
+			right:    []string{""},
+			wrong:    []string{" ", "pass"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			h, err := hashPassword(test.password)
+			assert.NilError(t, err)
+			for _, p := range test.right {
+				assert.Assert(t, h.check(p), "%q should match", p)
+			}
+			for _, p := range test.wrong {
+				assert.Assert(t, !h.check(p), "%q shouldn't match", p)
+			}
+		})
+	}
+}
+
+func TestPasswordHashLong(t *testing.T) {
+	long := strings.Repeat("x", protocol.MaxRoomPass)
+
+	h, err := hashPassword(long)
+	assert.NilError(t, err)
+	assert.Assert(t, h.check(long))
+
+	// bcrypt would ignore the extra byte, letting in any password which
+	// starts the same.
+	_, err = hashPassword(long + "y")
+	assert.Equal(t, err, ErrPasswordTooLong)
+
+	s := newTestServer(t, nil)
+	_, err = s.CreateRoom(context.Background(), "room", long+"y")
+	assert.Equal(t, err, ErrPasswordTooLong)
+}
+
+func TestPasswordHashSalted(t *testing.T) {
+	a, err := hashPassword("pass")
+	assert.NilError(t, err)
+	b, err := hashPassword("pass")
+	assert.NilError(t, err)
+
+	assert.Assert(t, string(a) != string(b))
+	assert.Assert(t, !strings.Contains(string(a), "pass"))
+
+	cost, err := bcrypt.Cost(a)
+	assert.NilError(t, err)
+	assert.Equal(t, cost, passwordCost)
+}
+
+func TestRoomCheckPassword(t *testing.T) {
+	s := newTestServer(t, nil)
+
+	room, err := s.CreateRoom(context.Background(), "room", "secret")
+	assert.NilError(t, err)
+
+	assert.Assert(t, room.CheckPassword("secret"))
+	assert.Assert(t, !room.CheckPassword("Secret"))
+	assert.Assert(t, !room.CheckPassword(""))
+
+	// Nothing about the password makes it into the stats.
+	b, err := json.Marshal(s.Stats())
+	assert.NilError(t, err)
+	assert.Assert(t, !strings.Contains(string(b), "secret"))
+	assert.Assert(t, !strings.Contains(string(b), string(room.password)))
+}
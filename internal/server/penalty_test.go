@@ -0,0 +1,112 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+// newTimedTestRoom returns a room with one guesser per team, 60 second turns
+// driven by a fake clock, and the given penalty limit.
+func newTimedTestRoom(t *testing.T, limit int) (*Room, *fakeClock) {
+	t.Helper()
+
+	r := newTestRoom(t)
+	c := newFakeClock()
+	r.setClock(c)
+	r.addTestClient(t, "g0", 0, false)
+	r.addTestClient(t, "g1", 1, false)
+	r.setTurn(0)
+
+	r.testNote(t, "g0", protocol.ChangePenaltiesMethod, &protocol.ChangePenaltiesParams{Limit: limit})
+	r.testNote(t, "g0", protocol.ChangeTurnModeMethod, &protocol.ChangeTurnModeParams{Timed: true})
+	return r, c
+}
+
+func (r *Room) testPenalties(t *testing.T) *protocol.StatePenalties {
+	t.Helper()
+
+	var penalties *protocol.StatePenalties
+	r.hold(func() {
+		penalties = r.createRoomState(false).Penalties
+	})
+	return penalties
+}
+
+// revealOwn reveals one of the current team's words.
+func (r *Room) revealOwn(t *testing.T, id game.PlayerID, team game.Team) {
+	t.Helper()
+
+	row, col := r.ownTile(t, team)
+	r.testNote(t, id, protocol.RevealMethod, &protocol.RevealParams{Row: row, Col: col})
+}
+
+func TestPenaltyExpiryWithoutReveal(t *testing.T) {
+	r, c := newTimedTestRoom(t, 3)
+
+	c.Advance(59 * time.Second)
+	assert.Equal(t, r.turn(), game.Team(0))
+
+	c.Advance(time.Second)
+	assert.Equal(t, r.turn(), game.Team(1))
+	assert.DeepEqual(t, r.testPenalties(t), &protocol.StatePenalties{Limit: 3, Counts: []int{1, 0}})
+}
+
+func TestPenaltyExpiryWithReveal(t *testing.T) {
+	r, c := newTimedTestRoom(t, 3)
+
+	r.revealOwn(t, "g0", 0)
+	c.Advance(60 * time.Second)
+
+	assert.Equal(t, r.turn(), game.Team(1))
+	assert.DeepEqual(t, r.testPenalties(t).Counts, []int{0, 0})
+
+	// The reveal only counted for that turn.
+	c.Advance(60 * time.Second)
+	assert.DeepEqual(t, r.testPenalties(t).Counts, []int{0, 1})
+}
+
+func TestPenaltyForfeit(t *testing.T) {
+	r, c := newTimedTestRoom(t, 2)
+
+	for i := 0; i < 2; i++ {
+		c.Advance(60 * time.Second)
+		assert.Assert(t, r.winner() == nil)
+	}
+
+	// Team 0's second expired turn.
+	c.Advance(60 * time.Second)
+	assert.Assert(t, r.winner() != nil)
+	assert.Equal(t, *r.winner(), game.Team(1))
+	assert.DeepEqual(t, r.testPenalties(t), &protocol.StatePenalties{Limit: 2, Counts: []int{2, 1}, Forfeit: true})
+
+	// The timer stops with the game.
+	r.hold(func() {
+		assert.Assert(t, r.turnTimer == nil)
+	})
+
+	r.testNote(t, "g0", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	assert.DeepEqual(t, r.testPenalties(t), &protocol.StatePenalties{Limit: 2, Counts: []int{0, 0}})
+}
+
+func TestPenaltiesDisabled(t *testing.T) {
+	r, c := newTimedTestRoom(t, 0)
+
+	for i := 0; i < 5; i++ {
+		c.Advance(60 * time.Second)
+	}
+
+	assert.Assert(t, r.winner() == nil)
+	assert.Assert(t, r.testPenalties(t) == nil)
+}
+
+func TestPenaltyEndTurnNotPenalized(t *testing.T) {
+	r, _ := newTimedTestRoom(t, 1)
+
+	r.testNote(t, "g0", protocol.EndTurnMethod, &protocol.EndTurnParams{})
+	assert.Equal(t, r.turn(), game.Team(1))
+	assert.DeepEqual(t, r.testPenalties(t).Counts, []int{0, 0})
+}
@@ -0,0 +1,300 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+)
+
+// Polls are for the players to settle things between games, like whether to
+// play again. They have no effect on the game, so they aren't part of the
+// room's state, and don't change its version; each change is sent in a poll
+// note instead, as chat is.
+
+// roomPoll is a room's open poll.
+type roomPoll struct {
+	poll  protocol.Poll // Without Votes, Tallies, or Vote; see pollFor.
+	timer timer
+
+	votes map[game.PlayerID]int
+
+	// Votes of players who've left, by nickname, which they take back if they
+	// rejoin before the poll closes. They still count.
+	held map[string]int
+}
+
+var (
+	errPollOpen = &game.Error{
+		Code:    "pollOpen",
+		Message: "There's already a poll open.",
+	}
+
+	errNoPoll = &game.Error{
+		Code:    "noPoll",
+		Message: "That poll has closed.",
+	}
+)
+
+func invalidPoll(format string, args ...interface{}) *game.Error {
+	return &game.Error{
+		Code:    "invalidPoll",
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// handlePoll handles the poll commands. They're acked like any other command.
+//
+// Must be called on the room's goroutine.
+func (r *Room) handlePoll(playerID game.PlayerID, note *protocol.ClientNote) error {
+	var violation *game.Error
+
+	switch note.Method {
+	case protocol.OpenPollMethod:
+		var params protocol.OpenPollParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		violation = r.openPoll(playerID, &params)
+
+	case protocol.VoteMethod:
+		var params protocol.VoteParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		violation = r.vote(playerID, &params)
+
+	case protocol.ClosePollMethod:
+		var params protocol.ClosePollParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		if playerID == r.room.Host && r.poll != nil && r.poll.poll.ID == params.Poll {
+			r.closePoll(playerID)
+		}
+	}
+
+	if violation != nil {
+		if err := r.sendError(playerID, violation); err != nil {
+			return err
+		}
+	}
+	r.sendAck(playerID, note.ID, violation)
+	return nil
+}
+
+// openPoll opens a poll, if there isn't one open already.
+//
+// Must be called on the room's goroutine.
+func (r *Room) openPoll(playerID game.PlayerID, params *protocol.OpenPollParams) *game.Error {
+	if !r.openPolls && playerID != r.room.Host {
+		return invalidPoll("Only the host may open polls.")
+	}
+
+	if r.poll != nil {
+		return errPollOpen
+	}
+
+	question := strings.TrimSpace(params.Question)
+	if question == "" {
+		return invalidPoll("Polls need a question.")
+	}
+	if utf8.RuneCountInString(question) > protocol.MaxPollQuestionLength {
+		return invalidPoll("Questions may be at most %d characters.", protocol.MaxPollQuestionLength)
+	}
+
+	if n := len(params.Options); n < protocol.MinPollOptions || n > protocol.MaxPollOptions {
+		return invalidPoll("Polls need between %d and %d options.", protocol.MinPollOptions, protocol.MaxPollOptions)
+	}
+
+	options := make([]string, len(params.Options))
+	seen := make(map[string]bool, len(params.Options))
+	for i, option := range params.Options {
+		option = strings.TrimSpace(option)
+		switch {
+		case option == "":
+			return invalidPoll("Options can't be empty.")
+		case utf8.RuneCountInString(option) > protocol.MaxPollOptionLength:
+			return invalidPoll("Options may be at most %d characters.", protocol.MaxPollOptionLength)
+		case seen[option]:
+			return invalidPoll("Options must differ.")
+		}
+		seen[option] = true
+		options[i] = option
+	}
+
+	seconds := params.Seconds
+	if seconds == 0 {
+		seconds = protocol.DefaultPollSeconds
+	}
+	if seconds < 0 || seconds > protocol.MaxPollSeconds {
+		return invalidPoll("Polls may be open for at most %d seconds.", protocol.MaxPollSeconds)
+	}
+	dur := time.Duration(seconds) * time.Second
+
+	r.pollID++
+	id := r.pollID
+
+	r.poll = &roomPoll{
+		poll: protocol.Poll{
+			ID:       id,
+			Question: question,
+			Options:  options,
+			Hidden:   params.Hidden,
+			Opener:   playerID,
+			Closes:   r.clock.Now().Add(dur),
+		},
+		votes: make(map[game.PlayerID]int),
+		held:  make(map[string]int),
+	}
+	r.poll.timer = r.after(dur, func() { r.timerClosePoll(id) })
+
+	r.sendPollAll()
+	return nil
+}
+
+// vote records or replaces a player's vote in the open poll.
+//
+// Must be called on the room's goroutine.
+func (r *Room) vote(playerID game.PlayerID, params *protocol.VoteParams) *game.Error {
+	if r.poll == nil || r.poll.poll.ID != params.Poll {
+		return errNoPoll
+	}
+
+	if params.Option < 0 || params.Option >= len(r.poll.poll.Options) {
+		return invalidPoll("There's no such option.")
+	}
+
+	if prev, ok := r.poll.votes[playerID]; ok && prev == params.Option {
+		return nil
+	}
+	r.poll.votes[playerID] = params.Option
+
+	r.sendPollAll()
+	return nil
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) timerClosePoll(id int) {
+	if r.poll == nil || r.poll.poll.ID != id || r.poll.timer == nil {
+		// Closed already, or the room was pruned.
+		return
+	}
+	r.poll.timer = nil
+
+	r.closePoll("")
+}
+
+// closePoll closes the open poll, sending everyone the results and recording
+// them in the audit log. The actor is empty when the poll closes on its own.
+//
+// Must be called on the room's goroutine.
+func (r *Room) closePoll(actor game.PlayerID) {
+	r.stopPollTimer()
+
+	r.poll.poll.Closed = true
+	r.sendPollAll()
+
+	p := r.pollFor("")
+	changes := make([]*protocol.AuditChange, len(p.Options))
+	for i, option := range p.Options {
+		changes[i] = &protocol.AuditChange{Field: option, After: strconv.Itoa(p.Tallies[i])}
+	}
+	r.audit(auditPoll, actor, p.Question, changes...)
+
+	r.poll = nil
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) stopPollTimer() {
+	if r.poll != nil && r.poll.timer != nil {
+		r.poll.timer.Stop()
+		r.poll.timer = nil
+	}
+}
+
+// pollFor returns the open poll as a player sees it.
+//
+// Must be called on the room's goroutine.
+func (r *Room) pollFor(playerID game.PlayerID) *protocol.Poll {
+	p := r.poll.poll
+	p.Options = append([]string(nil), p.Options...)
+
+	tallies := make([]int, len(p.Options))
+	for _, option := range r.poll.votes {
+		tallies[option]++
+	}
+	for _, option := range r.poll.held {
+		tallies[option]++
+	}
+
+	p.Votes = len(r.poll.votes) + len(r.poll.held)
+	if !p.Hidden || p.Closed {
+		p.Tallies = tallies
+	}
+
+	if option, ok := r.poll.votes[playerID]; ok {
+		p.Vote = &option
+	}
+	return &p
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) sendPollAll() {
+	for playerID, sender := range r.players {
+		sender(priorityBroadcast, protocol.NewPollNote(r.pollFor(playerID)))
+	}
+}
+
+// sendPoll sends a player who's just joined the open poll, if there is one.
+// A player who left after voting gets their vote back if they rejoin under the
+// same nickname.
+//
+// Must be called on the room's goroutine.
+func (r *Room) sendPoll(playerID game.PlayerID, nickname string) {
+	sender := r.players[playerID]
+	if sender == nil || r.poll == nil {
+		return
+	}
+
+	if option, ok := r.poll.held[nickname]; ok {
+		delete(r.poll.held, nickname)
+		r.poll.votes[playerID] = option
+	}
+
+	sender(priorityTargeted, protocol.NewPollNote(r.pollFor(playerID)))
+}
+
+// holdVote keeps the vote of a player who's leaving, until they rejoin.
+//
+// Must be called on the room's goroutine.
+func (r *Room) holdVote(playerID game.PlayerID) {
+	if r.poll == nil {
+		return
+	}
+
+	option, ok := r.poll.votes[playerID]
+	if !ok {
+		return
+	}
+	delete(r.poll.votes, playerID)
+
+	if p := r.room.Players[playerID]; p != nil {
+		r.poll.held[p.Nickname] = option
+	}
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) changeOpenPolls(open bool) {
+	if r.openPolls == open {
+		return
+	}
+
+	r.openPolls = open
+	r.room.Version++
+}
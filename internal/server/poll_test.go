@@ -0,0 +1,185 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+// lastPoll returns the last poll a client was sent, or nil if none was.
+func (c *testClient) lastPoll() *protocol.Poll {
+	for i := len(c.notes) - 1; i >= 0; i-- {
+		if c.notes[i].Method == "poll" {
+			return c.notes[i].Params.(*protocol.Poll)
+		}
+	}
+	return nil
+}
+
+var rematch = &protocol.OpenPollParams{Question: " Rematch? ", Options: []string{"yes", "no"}}
+
+func TestPoll(t *testing.T) {
+	r := newTestRoom(t)
+	clock := newFakeClock()
+	r.setClock(clock)
+	host := r.addTestClient(t, "host", 0, false)
+	other := r.addTestClient(t, "other", 1, false)
+	version := r.version()
+
+	// Like chat, polls needn't be at the room's version, and don't change it.
+	r.testCommand(t, "host", "1", version-1, protocol.OpenPollMethod, rematch)
+	assert.DeepEqual(t, host.acks(), []*protocol.Ack{{ID: "1", OK: true, Version: version}})
+	assert.Equal(t, r.version(), version)
+
+	p := other.lastPoll()
+	assert.DeepEqual(t, p, &protocol.Poll{
+		ID:       1,
+		Question: "Rematch?",
+		Options:  []string{"yes", "no"},
+		Opener:   "host",
+		Closes:   clock.Now().Add(protocol.DefaultPollSeconds * time.Second),
+		Tallies:  []int{0, 0},
+	})
+
+	r.testNote(t, "host", protocol.VoteMethod, &protocol.VoteParams{Poll: 1, Option: 0})
+	r.testNote(t, "other", protocol.VoteMethod, &protocol.VoteParams{Poll: 1, Option: 0})
+
+	// Votes may be changed, and each player sees their own.
+	r.testNote(t, "other", protocol.VoteMethod, &protocol.VoteParams{Poll: 1, Option: 1})
+	assert.Equal(t, host.lastPoll().Votes, 2)
+	assert.DeepEqual(t, host.lastPoll().Tallies, []int{1, 1})
+	assert.Equal(t, *host.lastPoll().Vote, 0)
+	assert.Equal(t, *other.lastPoll().Vote, 1)
+
+	// Only the host may close it.
+	r.testNote(t, "other", protocol.ClosePollMethod, &protocol.ClosePollParams{Poll: 1})
+	assert.Assert(t, !other.lastPoll().Closed)
+
+	r.testNote(t, "host", protocol.ClosePollMethod, &protocol.ClosePollParams{Poll: 1})
+	assert.Assert(t, other.lastPoll().Closed)
+	assert.DeepEqual(t, other.lastPoll().Tallies, []int{1, 1})
+
+	e := findAudit(r.AuditLog(), auditPoll)
+	assert.Assert(t, e != nil)
+	assert.Equal(t, e.Actor, "host")
+	assert.Equal(t, e.Target, "Rematch?")
+	assert.DeepEqual(t, e.Changes, []*protocol.AuditChange{{Field: "yes", After: "1"}, {Field: "no", After: "1"}})
+
+	// Once closed, it can't be voted in, and another may be opened.
+	r.testNote(t, "other", protocol.VoteMethod, &protocol.VoteParams{Poll: 1, Option: 0})
+	errs := other.errors()
+	assert.Equal(t, len(errs), 1)
+	assert.Equal(t, errs[0].Code, "noPoll")
+
+	r.testNote(t, "host", protocol.OpenPollMethod, rematch)
+	assert.Equal(t, other.lastPoll().ID, 2)
+	assert.Assert(t, host.lastState() == nil)
+}
+
+func TestPollTimeout(t *testing.T) {
+	r := newTestRoom(t)
+	clock := newFakeClock()
+	r.setClock(clock)
+	r.addTestClient(t, "host", 0, false)
+	other := r.addTestClient(t, "other", 1, false)
+
+	r.testNote(t, "host", protocol.OpenPollMethod, &protocol.OpenPollParams{
+		Question: "Switch packs?",
+		Options:  []string{"yes", "no", "maybe"},
+		Hidden:   true,
+		Seconds:  30,
+	})
+	r.testNote(t, "other", protocol.VoteMethod, &protocol.VoteParams{Poll: 1, Option: 2})
+
+	// Hidden polls only say how many have voted until they close.
+	p := other.lastPoll()
+	assert.Equal(t, p.Votes, 1)
+	assert.Assert(t, p.Tallies == nil)
+	assert.Equal(t, *p.Vote, 2)
+
+	clock.Advance(29 * time.Second)
+	assert.Assert(t, !other.lastPoll().Closed)
+
+	clock.Advance(time.Second)
+	p = other.lastPoll()
+	assert.Assert(t, p.Closed)
+	assert.DeepEqual(t, p.Tallies, []int{0, 0, 1})
+
+	e := findAudit(r.AuditLog(), auditPoll)
+	assert.Assert(t, e != nil)
+	assert.Equal(t, e.Actor, "")
+
+	r.hold(func() {
+		assert.Assert(t, r.poll == nil)
+	})
+}
+
+func TestPollInvalid(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+	other := r.addTestClient(t, "other", 1, false)
+
+	for _, params := range []*protocol.OpenPollParams{
+		{Question: " ", Options: []string{"yes", "no"}},
+		{Question: "Rematch?", Options: []string{"yes"}},
+		{Question: "Rematch?", Options: []string{"1", "2", "3", "4", "5", "6"}},
+		{Question: "Rematch?", Options: []string{"yes", " "}},
+		{Question: "Rematch?", Options: []string{"yes", "yes "}},
+		{Question: "Rematch?", Options: []string{"yes", "no"}, Seconds: protocol.MaxPollSeconds + 1},
+	} {
+		r.testNote(t, "host", protocol.OpenPollMethod, params)
+	}
+	errs := host.errors()
+	assert.Equal(t, len(errs), 6)
+	for _, e := range errs {
+		assert.Equal(t, e.Code, "invalidPoll")
+	}
+	assert.Assert(t, host.lastPoll() == nil)
+
+	// Only the host may open polls, unless the room lets anyone.
+	r.testNote(t, "other", protocol.OpenPollMethod, rematch)
+	assert.Equal(t, other.errors()[0].Code, "invalidPoll")
+
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{OpenPolls: boolPtr(true)})
+	assert.Assert(t, host.lastState().RoomState.OpenPolls)
+	r.testNote(t, "other", protocol.OpenPollMethod, rematch)
+	assert.Equal(t, host.lastPoll().Opener, "other")
+
+	// There's only one open at a time.
+	r.testNote(t, "host", protocol.OpenPollMethod, rematch)
+	assert.Equal(t, host.errors()[6].Code, "pollOpen")
+
+	r.testNote(t, "other", protocol.VoteMethod, &protocol.VoteParams{Poll: 1, Option: 2})
+	assert.Equal(t, other.errors()[1].Code, "invalidPoll")
+}
+
+func TestPollRejoin(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.joinTestClient(t, "host", false)
+	r.joinTestClient(t, "voter", false)
+
+	r.testNote(t, "host", protocol.OpenPollMethod, rematch)
+	r.testNote(t, "voter", protocol.VoteMethod, &protocol.VoteParams{Poll: 1, Option: 1})
+
+	// A vote still counts after its player leaves, and is theirs again if
+	// they're back before the poll closes.
+	r.disconnect("voter", nil)
+	r.testNote(t, "host", protocol.VoteMethod, &protocol.VoteParams{Poll: 1, Option: 0})
+	assert.Equal(t, host.lastPoll().Votes, 2)
+	assert.DeepEqual(t, host.lastPoll().Tallies, []int{1, 1})
+
+	voter := r.joinTestClient(t, "voter", false)
+	p := voter.lastPoll()
+	assert.Assert(t, p != nil)
+	assert.Equal(t, *p.Vote, 1)
+	assert.Equal(t, p.Votes, 2)
+
+	r.testNote(t, "voter", protocol.VoteMethod, &protocol.VoteParams{Poll: 1, Option: 0})
+	assert.DeepEqual(t, host.lastPoll().Tallies, []int{2, 0})
+
+	// Someone new hasn't voted.
+	late := r.joinTestClient(t, "late", false)
+	assert.Assert(t, late.lastPoll().Vote == nil)
+}
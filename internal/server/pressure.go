@@ -0,0 +1,192 @@
+package server
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/zikaeroh/ctxlog"
+	"go.uber.org/zap"
+	"nhooyr.io/websocket"
+)
+
+// With context takeover, each connection keeps its compression dictionaries
+// for as long as it's open, which makes them the largest thing the server
+// keeps for each client. When the heap grows past the threshold, the server
+// gives memory up a step at a time, in the order players would notice least,
+// so that their games are the last thing affected:
+//
+//  1. Delayed mirror queues are cut to their newest state.
+//  2. Rooms nobody is in drop their cached states.
+//  3. Spectators and mirrors connect without context takeover, and idle
+//     spectators which have it are cycled to reconnect without it.
+//  4. Players connect without context takeover. Those connected keep it
+//     until they next reconnect; nobody is disconnected for it.
+//
+// Each check which finds the heap over the threshold takes the next step, and
+// takes those before it again, as queues and caches fill back up. Once the
+// heap is back under pressureRecovery of the threshold, new connections have
+// context takeover again.
+type pressureLevel int32
+
+const (
+	pressureNone pressureLevel = iota
+	pressureMirrorQueues
+	pressureIdleRooms
+	pressureSpectators
+	pressurePlayers
+)
+
+func (l pressureLevel) String() string {
+	switch l {
+	case pressureMirrorQueues:
+		return "mirrorQueues"
+	case pressureIdleRooms:
+		return "idleRooms"
+	case pressureSpectators:
+		return "spectators"
+	case pressurePlayers:
+		return "players"
+	default:
+		return "none"
+	}
+}
+
+const (
+	pressureInterval = 10 * time.Second
+	pressureRecovery = 0.8
+
+	// spectatorIdle is how long a spectator must have been sent nothing
+	// before it's cycled, so that it's unlikely to miss anything as it
+	// reconnects.
+	spectatorIdle = time.Minute
+)
+
+// closeCycled closes a spectator so that it reconnects without compression
+// context takeover.
+const closeCycled websocket.StatusCode = 4503
+
+func readHeap() uint64 {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return m.HeapAlloc
+}
+
+// CompressionMode returns the compression a new connection should be accepted
+// with. Connections which only watch, spectators and mirrors, lose context
+// takeover under memory pressure before players do.
+func (s *Server) CompressionMode(watching bool) websocket.CompressionMode {
+	level := pressureLevel(s.pressure.Load())
+	if level >= pressurePlayers || (watching && level >= pressureSpectators) {
+		metricPressureActions.WithLabelValues("noContextTakeover").Inc()
+		return websocket.CompressionNoContextTakeover
+	}
+	return websocket.CompressionContextTakeover
+}
+
+// checkPressure compares the heap to the threshold, taking the next step if
+// it's over.
+func (s *Server) checkPressure(ctx context.Context) {
+	heap := s.heapSize()
+	before := pressureLevel(s.pressure.Load())
+	level := before
+
+	switch {
+	case heap >= s.heapThreshold:
+		if level < pressurePlayers {
+			level++
+		}
+	case float64(heap) < float64(s.heapThreshold)*pressureRecovery:
+		level = pressureNone
+	}
+
+	if level != before {
+		s.pressure.Store(int32(level))
+		metricPressureLevel.Set(float64(level))
+		ctxlog.Warn(ctx, "memory pressure changed", zap.Uint64("heap", heap), zap.Uint64("threshold", s.heapThreshold), zap.Stringer("level", level))
+	}
+
+	if level != pressureNone {
+		s.relieve(ctx, level)
+	}
+}
+
+// relieve takes every step up to and including level.
+func (s *Server) relieve(ctx context.Context, level pressureLevel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var cut, hibernated, cycled int
+
+	for _, room := range s.rooms {
+		room.do(func() {
+			if room.cutMirrorQueue() {
+				cut++
+			}
+			if level >= pressureIdleRooms && room.hibernate() {
+				hibernated++
+			}
+			if level >= pressureSpectators {
+				cycled += room.cycleSpectators()
+			}
+		})
+	}
+
+	metricPressureActions.WithLabelValues("cutMirrorQueue").Add(float64(cut))
+	metricPressureActions.WithLabelValues("hibernateRoom").Add(float64(hibernated))
+	metricPressureActions.WithLabelValues("cycleSpectator").Add(float64(cycled))
+
+	if cut != 0 || hibernated != 0 || cycled != 0 {
+		ctxlog.Info(ctx, "relieved memory pressure", zap.Int("cutMirrorQueues", cut), zap.Int("hibernatedRooms", hibernated), zap.Int("cycledSpectators", cycled))
+	}
+}
+
+// cutMirrorQueue drops all but the newest state waiting to be sent to the
+// mirrors. It's still sent when it's due, so the mirrors skip the states in
+// between, but are never sent anything early.
+//
+// Must be called on the room's goroutine.
+func (r *Room) cutMirrorQueue() bool {
+	n := len(r.mirrorQueue)
+	if n <= 1 {
+		return false
+	}
+
+	r.mirrorQueue = append(r.mirrorQueue[:0:0], r.mirrorQueue[n-1])
+	return true
+}
+
+// hibernate drops the cached states of a room nobody is in. They're built
+// again once somebody needs them.
+//
+// Must be called on the room's goroutine.
+func (r *Room) hibernate() bool {
+	if r.state == nil || len(r.players) != 0 || len(r.mirrors) != 0 || len(r.spectators) != 0 {
+		return false
+	}
+
+	r.state = nil
+	return true
+}
+
+// cycleSpectators closes the connections of idle spectators with context
+// takeover, for them to reconnect without it. It returns how many it closed.
+//
+// Must be called on the room's goroutine.
+func (r *Room) cycleSpectators() int {
+	now := r.clock.Now()
+	cycled := 0
+
+	for _, m := range r.spectators {
+		if !m.takeover || now.Sub(m.sent) < spectatorIdle {
+			continue
+		}
+
+		// It's only removed once the connection ends.
+		m.takeover = false
+		m.w.close(closeCycled, "reconnect to save memory")
+		cycled++
+	}
+
+	return cycled
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/atomic"
+	"gotest.tools/v3/assert"
+	"nhooyr.io/websocket"
+)
+
+func TestPressureLadder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	var heap atomic.Uint64
+	s := NewServer(Options{HeapThreshold: 1000})
+	s.heapSize = heap.Load
+	go s.Run(ctx) //nolint:errcheck
+
+	delayed, err := s.CreateRoom(ctx, "delayed", "pass")
+	assert.NilError(t, err)
+	now := time.Now()
+	delayed.hold(func() {
+		delayed.mirrorQueue = []delayedNote{{due: now.Add(time.Second)}, {due: now.Add(2 * time.Second)}, {due: now.Add(3 * time.Second)}}
+	})
+
+	empty, err := s.CreateRoom(ctx, "empty", "pass")
+	assert.NilError(t, err)
+	empty.hold(func() {
+		empty.state = empty.createStateCache()
+	})
+
+	watched, err := s.CreateRoom(ctx, "watched", "pass")
+	assert.NilError(t, err)
+	c := newFakeClock()
+	watched.setClock(c)
+
+	player := dialTestRoom(t, watched, ConnOptions{Nickname: "player"})
+	readState(t, player)
+	idle := dialTestRoom(t, watched, ConnOptions{Spectate: true, ContextTakeover: true})
+	readState(t, idle)
+	plain := dialTestRoom(t, watched, ConnOptions{Spectate: true})
+	readState(t, plain)
+
+	c.Advance(spectatorInterval)
+	readState(t, idle)
+	readState(t, plain)
+	c.Advance(spectatorIdle)
+
+	// Sent its first state just now, so it's not idle.
+	busy := dialTestRoom(t, watched, ConnOptions{Spectate: true, ContextTakeover: true})
+	readState(t, busy)
+
+	cycled := testutil.ToFloat64(metricPressureActions.WithLabelValues("cycleSpectator"))
+
+	// Under the threshold, nothing is done.
+	heap.Store(999)
+	s.checkPressure(ctx)
+	assert.Equal(t, pressureLevel(s.pressure.Load()), pressureNone)
+	delayed.hold(func() {
+		assert.Equal(t, len(delayed.mirrorQueue), 3)
+	})
+
+	// First, the mirror queues are cut.
+	heap.Store(1000)
+	s.checkPressure(ctx)
+	assert.Equal(t, pressureLevel(s.pressure.Load()), pressureMirrorQueues)
+	delayed.hold(func() {
+		assert.Equal(t, len(delayed.mirrorQueue), 1)
+		assert.Equal(t, delayed.mirrorQueue[0].due, now.Add(3*time.Second))
+	})
+	empty.hold(func() {
+		assert.Assert(t, empty.state != nil)
+	})
+	assert.Equal(t, s.CompressionMode(true), websocket.CompressionContextTakeover)
+
+	// Then rooms nobody is in drop their states.
+	s.checkPressure(ctx)
+	assert.Equal(t, pressureLevel(s.pressure.Load()), pressureIdleRooms)
+	empty.hold(func() {
+		assert.Assert(t, empty.state == nil)
+	})
+	watched.hold(func() {
+		assert.Assert(t, watched.state != nil)
+	})
+	assert.Equal(t, s.CompressionMode(true), websocket.CompressionContextTakeover)
+	assert.Equal(t, testutil.ToFloat64(metricPressureActions.WithLabelValues("cycleSpectator")), cycled)
+
+	// Then spectators, only the idle one with context takeover being cycled.
+	s.checkPressure(ctx)
+	assert.Equal(t, pressureLevel(s.pressure.Load()), pressureSpectators)
+	assert.Equal(t, s.CompressionMode(true), websocket.CompressionNoContextTakeover)
+	assert.Equal(t, s.CompressionMode(false), websocket.CompressionContextTakeover)
+
+	code, hint := readCloseHint(t, idle)
+	assert.Equal(t, code, closeCycled)
+	assert.Assert(t, hint.Retry)
+	assert.Equal(t, testutil.ToFloat64(metricPressureActions.WithLabelValues("cycleSpectator")), cycled+1)
+
+	// Then players, who keep the connections they have.
+	s.checkPressure(ctx)
+	s.checkPressure(ctx)
+	assert.Equal(t, pressureLevel(s.pressure.Load()), pressurePlayers)
+	assert.Equal(t, s.CompressionMode(false), websocket.CompressionNoContextTakeover)
+	assert.Equal(t, testutil.ToFloat64(metricPressureActions.WithLabelValues("cycleSpectator")), cycled+1)
+	watched.hold(func() {
+		assert.Equal(t, len(watched.players), 1)
+	})
+
+	// Just under the threshold isn't enough to recover.
+	heap.Store(900)
+	s.checkPressure(ctx)
+	assert.Equal(t, pressureLevel(s.pressure.Load()), pressurePlayers)
+
+	heap.Store(799)
+	s.checkPressure(ctx)
+	assert.Equal(t, pressureLevel(s.pressure.Load()), pressureNone)
+	assert.Equal(t, s.CompressionMode(true), websocket.CompressionContextTakeover)
+	assert.Equal(t, testutil.ToFloat64(metricPressureLevel), 0.0)
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// PurgeFunc removes everything a store holds about a room, given its ID and
+// name. It's run as the room is torn down, with the server's lock held, so it
+// must not call back into the Server.
+type PurgeFunc func(id, name string)
+
+type purgeHook struct {
+	store string
+	fn    PurgeFunc
+}
+
+// RegisterPurge adds a store's hook to the cascade run as each room is torn
+// down, however it closed. Hooks run in the order they were registered, after
+// the server's own stores are purged.
+func (s *Server) RegisterPurge(store string, fn PurgeFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purges = append(s.purges, purgeHook{store: store, fn: fn})
+}
+
+// PurgeStores lists the stores purged as each room is torn down, in order.
+func (s *Server) PurgeStores() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stores := make([]string, len(s.purges))
+	for i, h := range s.purges {
+		stores[i] = h.store
+	}
+	return stores
+}
+
+// registerPurges registers the server's own stores which outlive a room.
+// Analytics and the closed rooms ring aren't among them; they only ever hold
+// hashes of the room ID.
+func (s *Server) registerPurges() {
+	s.RegisterPurge("stats", s.purgeStats)
+	s.RegisterPurge("wordSuggestionLimits", func(id, _ string) {
+		s.counters.words.purge("player:" + id + "/")
+	})
+}
+
+// Must be called with s.mu locked.
+func (s *Server) purgeRoom(room *Room) {
+	for _, h := range s.purges {
+		h.fn(room.ID, room.Name)
+	}
+}
+
+// purgeStats drops the room from the stats snapshot, rather than waiting for
+// the next one to be taken.
+func (s *Server) purgeStats(id, _ string) {
+	old := s.Stats()
+
+	details := make([]RoomStats, 0, len(old.Details))
+	for _, d := range old.Details {
+		if d.ID != id {
+			details = append(details, d)
+		}
+	}
+	if len(details) == len(old.Details) {
+		return
+	}
+
+	stats := *old
+	stats.Rooms = len(details)
+	stats.Details = details
+	s.stats.Store(&stats)
+}
+
+// purge drops the rate limits of players whose keys start with prefix.
+func (b *wordBox) purge(prefix string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for k := range b.limits {
+		if strings.HasPrefix(k, prefix) {
+			delete(b.limits, k)
+		}
+	}
+}
+
+// saltedHash is a short hash of s which can't be checked against a guess
+// without the salt.
+func saltedHash(salt []byte, s string) string {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(s)) //nolint:errcheck
+	return hex.EncodeToString(mac.Sum(nil)[:closedRoomHashPrefixBytes])
+}
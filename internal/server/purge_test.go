@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func TestPurgeCascade(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t, nil)
+
+	ran := map[string][]string{}
+	for _, store := range []string{"names", "snapshots"} {
+		store := store
+		s.RegisterPurge(store, func(id, name string) {
+			ran[store] = append(ran[store], id+" "+name)
+		})
+	}
+	assert.DeepEqual(t, s.PurgeStores(), []string{"stats", "wordSuggestionLimits", "names", "snapshots"})
+
+	deleted, err := s.CreateRoom(ctx, "deleted", "pass")
+	assert.NilError(t, err)
+	expired, err := s.CreateRoom(ctx, "expired", "pass")
+	assert.NilError(t, err)
+	live, err := s.CreateRoom(ctx, "live", "pass")
+	assert.NilError(t, err)
+
+	deleted.addTestClient(t, "p1", 0, false)
+	deleted.testNote(t, "p1", protocol.SuggestWordMethod, &protocol.SuggestWordParams{Word: "axolotl", Pack: "Base"})
+	s.refreshStats()
+	assert.Equal(t, s.Stats().Rooms, 3)
+
+	assert.Assert(t, s.DeleteRoom(ctx, deleted.ID))
+	expired.lastSeen.Store(time.Now().Add(-time.Hour))
+	s.prune(ctx)
+
+	// Every hook runs for each room, however it closed.
+	want := []string{deleted.ID + " deleted", expired.ID + " expired"}
+	assert.DeepEqual(t, ran["names"], want)
+	assert.DeepEqual(t, ran["snapshots"], want)
+
+	// The server's own stores are purged without waiting on their sweeps.
+	stats := s.Stats()
+	assert.Equal(t, stats.Rooms, 1)
+	assert.Equal(t, stats.Details[0].ID, live.ID)
+
+	s.counters.words.mu.Lock()
+	assert.Equal(t, len(s.counters.words.limits), 0)
+	s.counters.words.mu.Unlock()
+
+	// The suggestion itself isn't about the room, so it stays.
+	assert.Equal(t, len(s.WordSuggestions()), 1)
+}
+
+func TestPrivacyStrictClosedRooms(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	s := NewServer(Options{PrivacyStrict: true})
+	go s.Run(ctx) //nolint:errcheck
+
+	room, err := s.CreateRoom(ctx, "strict", "pass")
+	assert.NilError(t, err)
+	assert.Assert(t, s.DeleteRoom(ctx, room.ID))
+
+	// The room can still be looked up by ID, but its hash is salted.
+	closed, ok := s.RecentlyClosed(room.ID)
+	assert.Assert(t, ok)
+	assert.Equal(t, closed.Reason, CloseAdminDeleted)
+	assert.Assert(t, closed.IDHash != hashRoomID(room.ID))
+	assert.Equal(t, len(closed.IDHash), len(hashRoomID(room.ID)))
+}
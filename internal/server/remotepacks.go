@@ -0,0 +1,143 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/packs"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"github.com/zikaeroh/ctxlog"
+	"go.uber.org/zap"
+)
+
+var (
+	errRemotePacksDisabled = &game.Error{
+		Code:    "remotePacksDisabled",
+		Message: "This server doesn't add packs by URL.",
+	}
+	errPackFetching = &game.Error{
+		Code:    "packFetching",
+		Message: "Another pack is still being fetched.",
+	}
+)
+
+// addPackURL starts fetching a pack for the host, returning true if it did.
+// The fetch finishes in finishPackURL, which acks the command.
+//
+// Must be called on the room's goroutine.
+func (r *Room) addPackURL(playerID game.PlayerID, id string, params *protocol.AddPackURLParams) (bool, error) {
+	if playerID != r.room.Host {
+		return false, nil
+	}
+
+	fetcher := r.fetcher()
+	if fetcher == nil {
+		return false, errRemotePacksDisabled
+	}
+
+	if r.fetchingPack {
+		return false, errPackFetching
+	}
+
+	name := params.Name
+	if name == "" {
+		name = remotePackName(params.URL)
+	}
+
+	r.fetchingPack = true
+	go func() {
+		lines, err := fetcher.Fetch(r.ctx, params.URL)
+		r.finishPackURL(playerID, id, name, params.URL, lines, err)
+	}()
+	return true, nil
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) fetcher() *packs.Fetcher {
+	if r.server == nil {
+		return nil
+	}
+	return r.server.remote
+}
+
+// finishPackURL adds a fetched pack, then acks the command which asked for
+// it, as handleNote would have.
+func (r *Room) finishPackURL(playerID game.PlayerID, id, name, rawURL string, lines []string, err error) {
+	r.do(func() {
+		r.fetchingPack = false
+		if r.closing() || r.players[playerID] == nil {
+			return
+		}
+
+		before := r.room.Version
+		if err != nil {
+			ctxlog.Debug(r.ctx, "error fetching remote pack", zap.Error(err))
+			err = remotePackError(err)
+		} else {
+			source := &protocol.AuditChange{Field: "url", After: rawURL}
+			err = r.addCustomPacks(playerID, []customPack{{name: name, words: lines}}, source)
+		}
+		r.rolloverVersion()
+
+		var violation *game.Error
+		if errors.As(err, &violation) {
+			_ = r.sendError(playerID, violation)
+		}
+		r.sendAck(playerID, id, violation)
+
+		if r.room.Version != before {
+			r.sendAll()
+		}
+	})
+}
+
+// remotePackError describes a failed fetch to the host.
+func remotePackError(err error) *game.Error {
+	var statusErr *packs.StatusError
+
+	switch {
+	case errors.Is(err, packs.ErrRemoteURL):
+		return &game.Error{Code: "invalidPackURL", Message: "Pack URLs must start with http:// or https://."}
+	case errors.Is(err, packs.ErrRemoteAddress):
+		return &game.Error{Code: "invalidPackURL", Message: "Packs can't be fetched from private addresses."}
+	case errors.Is(err, packs.ErrRemoteTimeout):
+		return &game.Error{Code: "packFetchTimeout", Message: "The pack took too long to fetch."}
+	case errors.Is(err, packs.ErrRemoteTooBig):
+		max := packs.RemoteMaxBytes
+		return &game.Error{
+			Code:    "packTooLarge",
+			Message: fmt.Sprintf("Packs fetched by URL may be at most %d KB.", max>>10),
+			Limit:   &max,
+		}
+	case errors.Is(err, packs.ErrRemoteNotText):
+		return &game.Error{Code: "packNotText", Message: "The pack isn't a text file."}
+	case errors.As(err, &statusErr):
+		return &game.Error{
+			Code:    "packFetchFailed",
+			Message: fmt.Sprintf("Fetching the pack failed: %d %s.", statusErr.StatusCode, http.StatusText(statusErr.StatusCode)),
+		}
+	default:
+		return &game.Error{Code: "packFetchFailed", Message: "The pack couldn't be fetched."}
+	}
+}
+
+// remotePackName names a pack for the file in its URL, like a pack loaded
+// from a directory.
+func remotePackName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "Remote pack"
+	}
+
+	name := path.Base(u.Path)
+	name = strings.TrimSuffix(name, path.Ext(name))
+	if name == "" || name == "." || name == "/" {
+		return "Remote pack"
+	}
+	return name
+}
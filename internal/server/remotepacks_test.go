@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/packs"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"go.uber.org/atomic"
+	"gotest.tools/v3/assert"
+)
+
+func newRemotePackServer(t *testing.T) (*Server, string, *atomic.Int64) {
+	t.Helper()
+
+	var hits atomic.Int64
+	mux := http.NewServeMux()
+	mux.HandleFunc("/animals.txt", func(w http.ResponseWriter, r *http.Request) {
+		hits.Inc()
+		_, _ = w.Write([]byte(strings.Join(packWords("animal", 30), "\n")))
+	})
+	mux.HandleFunc("/small.txt", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("one\ntwo\nthree\n"))
+	})
+	mux.HandleFunc("/big.txt", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("word\n", packs.RemoteMaxBytes/5+1)))
+	})
+
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	s := NewServer(Options{RemotePacks: packs.NewFetcher(ts.Client())})
+	go s.Run(ctx) //nolint:errcheck
+	return s, ts.URL, &hits
+}
+
+// waitPackFetch waits for the room's pack fetch to finish.
+func (r *Room) waitPackFetch(t *testing.T) {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		var fetching bool
+		r.hold(func() {
+			fetching = r.fetchingPack
+		})
+
+		if !fetching {
+			return
+		}
+	}
+	t.Fatal("pack fetch didn't finish")
+}
+
+func TestAddPackURL(t *testing.T) {
+	ctx := context.Background()
+	s, base, hits := newRemotePackServer(t)
+
+	for _, name := range []string{"first", "second"} {
+		r, err := s.CreateRoom(ctx, name, "pass")
+		assert.NilError(t, err)
+		host := r.addTestClient(t, "host", 0, false)
+		before := r.packCount()
+
+		r.testCommand(t, "host", "1", r.version(), protocol.AddPackURLMethod, &protocol.AddPackURLParams{URL: base + "/animals.txt"})
+		r.waitPackFetch(t)
+
+		r.hold(func() {
+			assert.DeepEqual(t, host.acks(), []*protocol.Ack{{ID: "1", OK: true, Version: r.room.Version}})
+			assert.Equal(t, len(host.errors()), 0)
+			assert.Equal(t, len(host.lastState().RoomState.Lists), before+1)
+			assert.DeepEqual(t, host.lastState().RoomState.Lists[before], &protocol.StateWordList{Name: "animals", Count: 30, Custom: true})
+		})
+
+		entry := findAudit(r.AuditLog(), auditAddPack)
+		assert.Assert(t, entry != nil)
+		assert.DeepEqual(t, entry.Changes, []*protocol.AuditChange{{Field: "url", After: base + "/animals.txt"}})
+	}
+
+	// The second room used the first's fetch.
+	assert.Equal(t, hits.Load(), int64(1))
+}
+
+func TestAddPackURLErrors(t *testing.T) {
+	ctx := context.Background()
+	s, base, _ := newRemotePackServer(t)
+
+	tests := map[string]string{
+		base + "/small.txt":          "tooFewWords",
+		base + "/missing.txt":        "packFetchFailed",
+		base + "/big.txt":            "packTooLarge",
+		"ftp://example.com/pack.txt": "invalidPackURL",
+	}
+
+	for u, code := range tests {
+		r, err := s.CreateRoom(ctx, code, "pass")
+		assert.NilError(t, err)
+		host := r.addTestClient(t, "host", 0, false)
+		before := r.packCount()
+
+		r.testCommand(t, "host", "1", r.version(), protocol.AddPackURLMethod, &protocol.AddPackURLParams{Name: "pack", URL: u})
+		r.waitPackFetch(t)
+
+		r.hold(func() {
+			errs := host.errors()
+			assert.Equal(t, len(errs), 1, u)
+			assert.Equal(t, errs[0].Code, code, u)
+
+			acks := host.acks()
+			assert.Equal(t, len(acks), 1, u)
+			assert.Assert(t, !acks[0].OK, u)
+			assert.Equal(t, acks[0].Error.Code, code, u)
+			assert.Equal(t, len(r.room.WordLists), before, u)
+		})
+	}
+}
+
+func TestAddPackURLRefused(t *testing.T) {
+	s, base, hits := newRemotePackServer(t)
+	r, err := s.CreateRoom(context.Background(), "room", "pass")
+	assert.NilError(t, err)
+	host := r.addTestClient(t, "host", 0, false)
+	other := r.addTestClient(t, "other", 1, false)
+	params := &protocol.AddPackURLParams{URL: base + "/animals.txt"}
+
+	// Only the host may add packs.
+	r.testNote(t, "other", protocol.AddPackURLMethod, params)
+	assert.Equal(t, len(other.errors()), 0)
+
+	// One fetch at a time.
+	r.hold(func() {
+		r.fetchingPack = true
+	})
+	r.testNote(t, "host", protocol.AddPackURLMethod, params)
+	assert.Equal(t, host.errors()[0].Code, "packFetching")
+
+	// Nor at all, if the server doesn't allow it.
+	r = newTestRoom(t)
+	host = r.addTestClient(t, "host", 0, false)
+	r.testNote(t, "host", protocol.AddPackURLMethod, params)
+	assert.Equal(t, host.errors()[0].Code, "remotePacksDisabled")
+
+	assert.Equal(t, hits.Load(), int64(0))
+}
+
+func TestRemotePackName(t *testing.T) {
+	tests := map[string]string{
+		"https://gist.githubusercontent.com/u/abc/raw/def/animals.txt": "animals",
+		"https://example.com/packs/food":                               "food",
+		"https://example.com/":                                         "Remote pack",
+		"https://example.com":                                          "Remote pack",
+	}
+
+	for u, want := range tests {
+		assert.Equal(t, remotePackName(u), want, u)
+	}
+}
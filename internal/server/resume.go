@@ -0,0 +1,301 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/idgen"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"github.com/zikaeroh/ctxlog"
+	"go.uber.org/zap"
+)
+
+// Rooms may be kept across a restart without keeping their games. As the
+// server stops, it writes a manifest of its rooms: their names, passwords'
+// hashes, options, and who was seated where. Once it starts again, each room
+// is recreated with a fresh game, and holds its players' seats until they
+// reconnect with the token they were sent as they joined.
+
+// ResumeManifestVersion is the version of the manifest format. Manifests of
+// any other version aren't resumed.
+const ResumeManifestVersion = 2
+
+// resumeSeatTTL is how long a resumed room holds its players' seats, which is
+// as long as an empty room lasts before it's pruned.
+const resumeSeatTTL = 10 * time.Minute
+
+// ResumeManifest lists the rooms to resume after a restart.
+type ResumeManifest struct {
+	Version int           `json:"version"`
+	Written time.Time     `json:"written"`
+	Rooms   []*ResumeRoom `json:"rooms"`
+}
+
+// ResumeRoom is a room as it's resumed. There's no game state; the room
+// starts a new game.
+type ResumeRoom struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+
+	// The room's bcrypt password hash; empty for rooms without one.
+	PasswordHash []byte `json:"passwordHash,omitempty"`
+
+	// As the room was created with; see RoomOptions.
+	Tracker  bool     `json:"tracker,omitempty"`
+	Unlisted bool     `json:"unlisted,omitempty"`
+	Words    []string `json:"words,omitempty"`
+	Language string   `json:"language,omitempty"`
+
+	Options *protocol.UpdateOptionsParams `json:"options"`
+	Seats   []*ResumeSeat                 `json:"seats"`
+}
+
+// ResumeSeat is a player's seat, kept for whoever has the token it was
+// hashed from.
+type ResumeSeat struct {
+	TokenHash string    `json:"tokenHash"` // Hex SHA-256.
+	Nickname  string    `json:"nickname"`
+	Team      game.Team `json:"team"`
+}
+
+func hashResumeToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// sendResumeToken gives a player who's just joined a token for their seat,
+// on servers which resume rooms or hold seats. Only its hash is kept.
+//
+// Must be called on the room's goroutine.
+func (r *Room) sendResumeToken(playerID game.PlayerID) {
+	sender := r.players[playerID]
+	if sender == nil || r.server == nil || (!r.server.resume && r.seatGrace == 0) {
+		return
+	}
+
+	token := idgen.Token()
+	r.resumeTokens[playerID] = hashResumeToken(token)
+	sender(priorityTargeted, protocol.NewResumeTokenNote(token))
+}
+
+// takeResumeSeat claims the seat a resumed room holds for the token, if it
+// hasn't expired.
+//
+// Must be called on the room's goroutine.
+func (r *Room) takeResumeSeat(token string) (*mergeSeat, string) {
+	if token == "" {
+		return nil, ""
+	}
+
+	hash := hashResumeToken(token)
+	seat := r.resumeSeats[hash]
+	if seat == nil {
+		return nil, ""
+	}
+	delete(r.resumeSeats, hash)
+
+	if !r.clock.Now().Before(seat.expires) {
+		return nil, ""
+	}
+	return seat, hash
+}
+
+// resumeSeat returns a player's seat, if they have a token for it.
+//
+// Must be called on the room's goroutine.
+func (r *Room) resumeSeat(playerID game.PlayerID) *ResumeSeat {
+	hash := r.resumeTokens[playerID]
+	p := r.room.Players[playerID]
+	if hash == "" || p == nil {
+		return nil
+	}
+
+	return &ResumeSeat{
+		TokenHash: hash,
+		Nickname:  p.Nickname,
+		Team:      p.Team,
+	}
+}
+
+// keepStoppedSeat keeps the seat of a player who's leaving because the server
+// is stopping, so that it's in the manifest written once it has.
+//
+// Must be called on the room's goroutine.
+func (r *Room) keepStoppedSeat(playerID game.PlayerID) {
+	if r.closeReason != "" || r.ctx.Err() == nil {
+		return
+	}
+
+	if seat := r.resumeSeat(playerID); seat != nil {
+		r.stoppedSeats = append(r.stoppedSeats, seat)
+	}
+}
+
+// resumeRoom describes the room for a manifest. Rooms without anyone to come
+// back to them aren't resumed.
+//
+// Must be called on the room's goroutine.
+func (r *Room) resumeRoom() *ResumeRoom {
+	if r.closeReason != "" || r.mergedInto != "" {
+		return nil
+	}
+
+	seats := append([]*ResumeSeat(nil), r.stoppedSeats...)
+	for playerID := range r.players {
+		if seat := r.resumeSeat(playerID); seat != nil {
+			seats = append(seats, seat)
+		}
+	}
+	for playerID := range r.held {
+		if seat := r.resumeSeat(playerID); seat != nil {
+			seats = append(seats, seat)
+		}
+	}
+	if len(seats) == 0 {
+		return nil
+	}
+
+	sort.Slice(seats, func(i, j int) bool {
+		return seats[i].TokenHash < seats[j].TokenHash
+	})
+
+	return &ResumeRoom{
+		ID:           r.ID,
+		Name:         r.Name,
+		PasswordHash: r.password,
+		Tracker:      r.origin.Tracker,
+		Unlisted:     r.origin.Unlisted,
+		Words:        r.origin.Words,
+		Language:     r.origin.Language,
+		Options:      r.options().params(r.room.Tracker),
+		Seats:        seats,
+	}
+}
+
+// params returns the options as a command which sets every one of them.
+func (o options) params(tracker bool) *protocol.UpdateOptionsParams {
+	p := &protocol.UpdateOptionsParams{
+		Timed:          &o.timed,
+		TurnTime:       &o.turnTime,
+		PenaltyLimit:   &o.penaltyLimit,
+		SpymasterLimit: &o.spymasterLimit,
+		ConfirmClues:   &o.confirmClues,
+		MirrorDelay:    &o.mirrorDelay,
+		MinSpymasters:  &o.minSpymasters,
+		MinGuessers:    &o.minGuessers,
+		Feedback:       &o.feedback,
+		OpenPolls:      &o.openPolls,
+	}
+
+	if !tracker {
+		p.HideBomb = &o.hideBomb
+		p.BoundClues = &o.boundClues
+		p.EnforceGuessLimit = &o.enforceGuessLimit
+		p.SuggestClues = &o.suggestClues
+		p.BackfillWords = &o.backfillWords
+		p.Boards = &o.boards
+		p.Rows = &o.rows
+		p.Cols = &o.cols
+		p.Bombs = &o.bombs
+		p.HintBudget = o.hintBudget
+	}
+
+	return p
+}
+
+// restore applies what a manifest kept of the room. It's called as the room
+// is created, before any player could be its host, so host-only options may
+// be set.
+//
+// Must be called before the room is shared.
+func (r *Room) restore(rr *ResumeRoom) error {
+	r.password = rr.PasswordHash
+
+	if rr.Options != nil {
+		if _, err := r.updateOptions("", rr.Options); err != nil {
+			return err
+		}
+	}
+
+	expires := r.clock.Now().Add(resumeSeatTTL)
+	for _, seat := range rr.Seats {
+		if seat == nil || seat.TokenHash == "" {
+			continue
+		}
+		r.resumeSeats[seat.TokenHash] = &mergeSeat{
+			nickname: seat.Nickname,
+			team:     seat.Team,
+			expires:  expires,
+		}
+	}
+
+	return nil
+}
+
+// ResumeManifest describes the server's rooms, to be resumed once it restarts.
+// It's meant to be written once the server has stopped; players dropped as it
+// stopped keep their seats.
+func (s *Server) ResumeManifest() *ResumeManifest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := &ResumeManifest{
+		Version: ResumeManifestVersion,
+		Written: time.Now(),
+		Rooms:   []*ResumeRoom{},
+	}
+
+	for _, room := range s.rooms {
+		room.do(func() {
+			if rr := room.resumeRoom(); rr != nil {
+				m.Rooms = append(m.Rooms, rr)
+			}
+		})
+	}
+
+	sort.Slice(m.Rooms, func(i, j int) bool {
+		return m.Rooms[i].Name < m.Rooms[j].Name
+	})
+	return m
+}
+
+// Resume recreates the rooms in a manifest, returning how many were. A room
+// which can't be, like one whose language is no longer offered, is logged and
+// skipped.
+func (s *Server) Resume(ctx context.Context, m *ResumeManifest) (int, error) {
+	if m.Version != ResumeManifestVersion {
+		return 0, fmt.Errorf("resume manifest version %d, want %d", m.Version, ResumeManifestVersion)
+	}
+
+	<-s.ready
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resumed := 0
+	for _, rr := range m.Rooms {
+		if rr == nil || rr.Name == "" {
+			continue
+		}
+
+		_, err := s.createRoom(ctx, rr.Name, nil, RoomOptions{
+			Tracker:  rr.Tracker,
+			Unlisted: rr.Unlisted,
+			Words:    rr.Words,
+			Language: rr.Language,
+			restore:  rr,
+		})
+		if err != nil {
+			ctxlog.Warn(ctx, "error resuming room", zap.String("roomID", rr.ID), zap.Error(err))
+			continue
+		}
+		resumed++
+	}
+
+	return resumed, nil
+}
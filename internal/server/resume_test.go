@@ -0,0 +1,200 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+	"nhooyr.io/websocket"
+)
+
+func newResumeServer(t *testing.T) (*Server, context.CancelFunc) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	s := NewServer(Options{Resume: true})
+	go s.Run(ctx) //nolint:errcheck
+	return s, cancel
+}
+
+// dialResume joins the room, returning the connection and the resume token
+// it's sent.
+func dialResume(t *testing.T, r *Room, nickname, token string) (*websocket.Conn, string) {
+	t.Helper()
+
+	conn := dialTestRoom(t, r, ConnOptions{Nickname: nickname, ResumeToken: token})
+	var rt protocol.ResumeToken
+	readNote(t, conn, "resumeToken", &rt)
+	assert.Assert(t, rt.Token != "")
+	return conn, rt.Token
+}
+
+func (r *Room) playerCount() int {
+	var count int
+	r.hold(func() {
+		count = len(r.players)
+	})
+	return count
+}
+
+func (r *Room) nicknames() map[string]game.Team {
+	nicknames := make(map[string]game.Team)
+	r.hold(func() {
+		for _, p := range r.room.Players {
+			nicknames[p.Nickname] = p.Team
+		}
+	})
+	return nicknames
+}
+
+func TestResumeManifest(t *testing.T) {
+	s, stop := newResumeServer(t)
+	ctx := context.Background()
+
+	r, err := s.CreateRoom(ctx, "friday", "pass")
+	assert.NilError(t, err)
+	_, err = s.CreateRoom(ctx, "nobody", "")
+	assert.NilError(t, err)
+
+	_, alice := dialResume(t, r, "alice", "")
+	_, bob := dialResume(t, r, "bob", "")
+
+	r.hold(func() {
+		_, err = r.updateOptions(r.room.Host, &protocol.UpdateOptionsParams{TurnTime: intPtr(90), HideBomb: boolPtr(true)})
+	})
+	assert.NilError(t, err)
+
+	// Players dropped as the server stops keep their seats.
+	stop()
+	for deadline := time.Now().Add(5 * time.Second); r.playerCount() != 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("players didn't leave")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	m := s.ResumeManifest()
+	assert.Equal(t, m.Version, ResumeManifestVersion)
+	assert.Equal(t, len(m.Rooms), 1, "rooms without players aren't resumed")
+
+	rr := m.Rooms[0]
+	assert.Equal(t, rr.ID, r.ID)
+	assert.Equal(t, rr.Name, "friday")
+	assert.Assert(t, rr.PasswordHash != nil)
+	assert.Equal(t, *rr.Options.TurnTime, 90)
+	assert.Equal(t, *rr.Options.HideBomb, true)
+
+	seats := make(map[string]*ResumeSeat)
+	for _, seat := range rr.Seats {
+		seats[seat.Nickname] = seat
+	}
+	assert.Equal(t, len(seats), 2)
+	assert.Equal(t, seats["alice"].TokenHash, hashResumeToken(alice))
+	assert.Equal(t, seats["bob"].TokenHash, hashResumeToken(bob))
+	assert.Equal(t, seats["alice"].Team, game.Team(0))
+	assert.Equal(t, seats["bob"].Team, game.Team(1))
+}
+
+func TestResume(t *testing.T) {
+	m := &ResumeManifest{
+		Version: ResumeManifestVersion,
+		Rooms: []*ResumeRoom{{
+			ID:      "resumed-id",
+			Name:    "friday",
+			Options: &protocol.UpdateOptionsParams{TurnTime: intPtr(90), Feedback: boolPtr(true)},
+			Seats: []*ResumeSeat{
+				{TokenHash: hashResumeToken("alice-token"), Nickname: "alice", Team: 1},
+				{TokenHash: hashResumeToken("bob-token"), Nickname: "bob", Team: 0},
+			},
+		}},
+	}
+	hash, err := hashPassword("pass")
+	assert.NilError(t, err)
+	m.Rooms[0].PasswordHash = hash
+
+	s, _ := newResumeServer(t)
+	resumed, err := s.Resume(context.Background(), m)
+	assert.NilError(t, err)
+	assert.Equal(t, resumed, 1)
+
+	// The room is back, empty, under its ID, password, and options.
+	r := s.FindRoomByID("resumed-id")
+	assert.Assert(t, r != nil)
+	assert.Equal(t, s.FindRoom("friday"), r)
+	assert.Assert(t, r.CheckPassword("pass"))
+	assert.Assert(t, !r.CheckPassword(""))
+	assert.Equal(t, r.playerCount(), 0)
+	r.hold(func() {
+		assert.Equal(t, r.turnSeconds, 90)
+		assert.Assert(t, r.feedback.enabled)
+		assert.Equal(t, r.room.Winner, (*game.Team)(nil))
+	})
+
+	// A returning player gets their seat back, even if they'd changed their
+	// nickname, and a new token.
+	_, token := dialResume(t, r, "ally", "alice-token")
+	assert.Assert(t, token != "alice-token")
+	assert.DeepEqual(t, r.nicknames(), map[string]game.Team{"alice": 1})
+
+	// Each seat is only claimed once, and unknown tokens claim nothing.
+	dialResume(t, r, "mallory", "alice-token")
+	dialResume(t, r, "eve", "not-a-token")
+	assert.DeepEqual(t, r.nicknames(), map[string]game.Team{"alice": 1, "mallory": 0, "eve": 0})
+}
+
+func TestResumeSeatExpires(t *testing.T) {
+	s, _ := newResumeServer(t)
+	_, err := s.Resume(context.Background(), &ResumeManifest{
+		Version: ResumeManifestVersion,
+		Rooms: []*ResumeRoom{{
+			ID:    "resumed-id",
+			Name:  "friday",
+			Seats: []*ResumeSeat{{TokenHash: hashResumeToken("alice-token"), Nickname: "alice", Team: 1}},
+		}},
+	})
+	assert.NilError(t, err)
+
+	r := s.FindRoomByID("resumed-id")
+	r.hold(func() {
+		r.clock = &fakeClock{now: time.Now().Add(resumeSeatTTL)}
+	})
+
+	dialResume(t, r, "ally", "alice-token")
+	assert.DeepEqual(t, r.nicknames(), map[string]game.Team{"ally": 0})
+}
+
+func TestResumeSkips(t *testing.T) {
+	s, _ := newResumeServer(t)
+
+	_, err := s.Resume(context.Background(), &ResumeManifest{Version: ResumeManifestVersion + 1})
+	assert.ErrorContains(t, err, "version")
+
+	_, err = s.CreateRoom(context.Background(), "taken", "")
+	assert.NilError(t, err)
+
+	resumed, err := s.Resume(context.Background(), &ResumeManifest{
+		Version: ResumeManifestVersion,
+		Rooms: []*ResumeRoom{
+			{ID: "a", Name: "taken"},
+			{ID: "b", Name: "unknown language", Language: "xx"},
+			{ID: "c", Name: "fine"},
+		},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, resumed, 1)
+	assert.Assert(t, s.FindRoomByID("c") != nil)
+}
+
+func TestNoResumeTokens(t *testing.T) {
+	r := newTestRoom(t)
+	c := r.joinTestClient(t, "p1", false)
+
+	for _, n := range c.notes {
+		assert.Assert(t, n.Method != "resumeToken")
+	}
+}
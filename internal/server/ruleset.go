@@ -0,0 +1,182 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"github.com/zikaeroh/codies/internal/words"
+)
+
+// maxRulesets is the number of recently seen rulesets kept for
+// GET /api/ruleset/{hash}.
+const maxRulesets = 1000
+
+// mode names the room's kind of game.
+//
+// Must be called on the room's goroutine.
+func (r *Room) mode() string {
+	switch {
+	case r.room.Tracker:
+		return "tracker"
+	case len(r.room.Boards) > 1:
+		return "gauntlet"
+	default:
+		return "classic"
+	}
+}
+
+// Options which only matter in timed games, and those which only matter with
+// a board. Feedback prompts are left out of every ruleset, as they don't
+// change the game, and ratings are tallied by ruleset.
+var (
+	timedOptions = map[string]bool{"turnTime": true, "penaltyLimit": true}
+	boardOptions = map[string]bool{
+		"hideBomb": true, "boundClues": true, "enforceGuessLimit": true, "suggestClues": true, "backfillWords": true,
+		"boards": true, "rows": true, "cols": true, "bombs": true, "hintBudget": true,
+	}
+)
+
+// ruleset describes the rules of the game being started.
+//
+// Must be called on the room's goroutine.
+func (r *Room) ruleset() *protocol.Ruleset {
+	o := r.options()
+
+	rs := &protocol.Ruleset{
+		Version: protocol.RulesetVersion,
+		Mode:    r.mode(),
+		Packs:   []*protocol.RulesetPack{},
+	}
+
+	for _, v := range o.values() {
+		if v.Field == "feedback" || v.Field == "openPolls" || (!o.timed && timedOptions[v.Field]) || (r.room.Tracker && boardOptions[v.Field]) {
+			continue
+		}
+
+		value := v.After
+		if v.Field == "hintBudget" && noHints(o.hintBudget) {
+			value = "[]"
+		}
+
+		rs.Options = append(rs.Options, &protocol.RulesetOption{Name: v.Field, Value: value})
+	}
+
+	rs.Options = append(rs.Options, &protocol.RulesetOption{
+		Name:  "minRevealPlayers",
+		Value: fmt.Sprint(r.room.MinRevealPlayers),
+	})
+
+	sort.Slice(rs.Options, func(i, j int) bool {
+		return rs.Options[i].Name < rs.Options[j].Name
+	})
+
+	for _, w := range r.room.WordLists {
+		if w.Enabled {
+			rs.Packs = append(rs.Packs, &protocol.RulesetPack{Name: w.Name, Custom: w.Custom, Hash: hashPack(w.List)})
+		}
+	}
+
+	sort.Slice(rs.Packs, func(i, j int) bool {
+		a, b := rs.Packs[i], rs.Packs[j]
+		if a.Custom != b.Custom {
+			return !a.Custom
+		}
+		return a.Name < b.Name
+	})
+
+	return rs
+}
+
+func noHints(budget []int) bool {
+	for _, n := range budget {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// hashPack hashes a pack's words, ignoring their order.
+func hashPack(list words.List) string {
+	ws := make([]string, list.Len())
+	for i := range ws {
+		ws[i] = list.Get(i)
+	}
+	sort.Strings(ws)
+
+	sum := sha256.Sum256([]byte(strings.Join(ws, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashRuleset returns the hash of a ruleset's canonical encoding.
+func hashRuleset(rs *protocol.Ruleset) string {
+	b, err := json.Marshal(rs)
+	if err != nil {
+		panic(err)
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// startRuleset records the rules of the game being started, once its options
+// and packs are final.
+//
+// Must be called on the room's goroutine.
+func (r *Room) startRuleset() {
+	rs := r.ruleset()
+	r.rulesetHash = hashRuleset(rs)
+	r.counters.rulesets.add(r.rulesetHash, rs)
+}
+
+// rulesetCache is a fixed size ring of recently seen rulesets, by hash. The
+// zero value is ready to use.
+type rulesetCache struct {
+	mu     sync.Mutex
+	byHash map[string]*protocol.Ruleset
+	order  []string
+	next   int
+}
+
+func (c *rulesetCache) add(hash string, rs *protocol.Ruleset) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.byHash == nil {
+		c.byHash = make(map[string]*protocol.Ruleset)
+	}
+
+	if c.byHash[hash] != nil {
+		return
+	}
+	c.byHash[hash] = rs
+
+	if len(c.order) < maxRulesets {
+		c.order = append(c.order, hash)
+		return
+	}
+
+	delete(c.byHash, c.order[c.next])
+	c.order[c.next] = hash
+	c.next = (c.next + 1) % maxRulesets
+}
+
+func (c *rulesetCache) find(hash string) (*protocol.Ruleset, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rs, ok := c.byHash[hash]
+	return rs, ok
+}
+
+// Ruleset returns the ruleset with the given hash, if a game has started with
+// it recently.
+func (s *Server) Ruleset(hash string) (*protocol.Ruleset, bool) {
+	return s.counters.rulesets.find(hash)
+}
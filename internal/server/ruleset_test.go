@@ -0,0 +1,209 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"github.com/zikaeroh/codies/internal/words"
+	"gotest.tools/v3/assert"
+)
+
+func (r *Room) testRulesetHash() string {
+	var hash string
+	r.hold(func() {
+		hash = hashRuleset(r.ruleset())
+	})
+	return hash
+}
+
+// gameRulesetHash returns the hash of the rules the current game started with.
+func (r *Room) gameRulesetHash() string {
+	var hash string
+	r.hold(func() {
+		hash = r.rulesetHash
+	})
+	return hash
+}
+
+func newRulesetTestRoom(t *testing.T, opts ...*protocol.UpdateOptionsParams) *Room {
+	t.Helper()
+
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, true)
+	for _, o := range opts {
+		r.testNote(t, "host", protocol.UpdateOptionsMethod, o)
+	}
+	assert.Equal(t, len(host.errors()), 0)
+	return r
+}
+
+func (r *Room) addTestPack(name string, list ...string) {
+	r.hold(func() {
+		r.room.WordLists = append(r.room.WordLists, &game.WordList{Name: name, Custom: true, List: words.NewList(list), Enabled: true})
+	})
+}
+
+func TestRulesetHashStable(t *testing.T) {
+	a := newRulesetTestRoom(t,
+		&protocol.UpdateOptionsParams{Timed: boolPtr(true), TurnTime: intPtr(90)},
+		&protocol.UpdateOptionsParams{HideBomb: boolPtr(true), HintBudget: []int{1, 0}},
+	)
+	a.addTestPack("mine", "apple", "banana", "cherry")
+	a.addTestPack("yours", "dog", "emu")
+
+	// The same settings, applied in another order.
+	b := newRulesetTestRoom(t,
+		&protocol.UpdateOptionsParams{HintBudget: []int{1, 0}},
+		&protocol.UpdateOptionsParams{TurnTime: intPtr(90), HideBomb: boolPtr(true)},
+		&protocol.UpdateOptionsParams{Timed: boolPtr(true)},
+	)
+	b.addTestPack("yours", "emu", "dog")
+	b.addTestPack("mine", "cherry", "apple", "banana")
+
+	assert.Equal(t, a.testRulesetHash(), b.testRulesetHash())
+
+	// Options which can't matter don't count.
+	c := newRulesetTestRoom(t, &protocol.UpdateOptionsParams{TurnTime: intPtr(30), PenaltyLimit: intPtr(2), HintBudget: []int{0, 0}, Feedback: boolPtr(true)})
+	d := newRulesetTestRoom(t)
+	assert.Equal(t, c.testRulesetHash(), d.testRulesetHash())
+}
+
+func TestRulesetHashChanges(t *testing.T) {
+	timed := &protocol.UpdateOptionsParams{Timed: boolPtr(true)}
+	base := newRulesetTestRoom(t, timed).testRulesetHash()
+
+	changes := map[string]func(r *Room){}
+	option := func(name string, o *protocol.UpdateOptionsParams) {
+		changes[name] = func(r *Room) {
+			r.testNote(t, "host", protocol.UpdateOptionsMethod, o)
+		}
+	}
+
+	option("timed", &protocol.UpdateOptionsParams{Timed: boolPtr(false)})
+	option("turnTime", &protocol.UpdateOptionsParams{TurnTime: intPtr(61)})
+	option("hideBomb", &protocol.UpdateOptionsParams{HideBomb: boolPtr(true)})
+	option("boundClues", &protocol.UpdateOptionsParams{BoundClues: boolPtr(true)})
+	option("enforceGuessLimit", &protocol.UpdateOptionsParams{EnforceGuessLimit: boolPtr(true)})
+	option("suggestClues", &protocol.UpdateOptionsParams{SuggestClues: boolPtr(true)})
+	option("backfillWords", &protocol.UpdateOptionsParams{BackfillWords: boolPtr(true)})
+	option("penaltyLimit", &protocol.UpdateOptionsParams{PenaltyLimit: intPtr(2)})
+	option("spymasterLimit", &protocol.UpdateOptionsParams{SpymasterLimit: intPtr(2)})
+	option("confirmClues", &protocol.UpdateOptionsParams{ConfirmClues: boolPtr(true)})
+	option("boards", &protocol.UpdateOptionsParams{Boards: intPtr(2)})
+	option("rows", &protocol.UpdateOptionsParams{Rows: intPtr(4)})
+	option("cols", &protocol.UpdateOptionsParams{Cols: intPtr(4)})
+	option("bombs", &protocol.UpdateOptionsParams{Bombs: intPtr(2)})
+	option("minSpymasters", &protocol.UpdateOptionsParams{MinSpymasters: intPtr(2)})
+	option("minGuessers", &protocol.UpdateOptionsParams{MinGuessers: intPtr(2)})
+	option("hintBudget", &protocol.UpdateOptionsParams{HintBudget: []int{1, 0}})
+
+	changes["mirrorDelay"] = func(r *Room) {
+		r.testNote(t, "host", protocol.ChangeMirrorDelayMethod, &protocol.ChangeMirrorDelayParams{Seconds: minMirrorDelay})
+	}
+	changes["minRevealPlayers"] = func(r *Room) {
+		r.hold(func() {
+			r.room.MinRevealPlayers = 4
+		})
+	}
+	changes["pack enabled"] = func(r *Room) {
+		r.testNote(t, "host", protocol.ChangePackMethod, &protocol.ChangePackParams{Num: 1, Enable: true})
+	}
+	changes["pack words"] = func(r *Room) {
+		r.addTestPack("mine", "apple")
+	}
+	changes["tracker"] = func(r *Room) {
+		r.hold(func() {
+			r.room.Tracker = true
+		})
+	}
+
+	// Every option in the ruleset must be covered.
+	r := newRulesetTestRoom(t)
+	r.hold(func() {
+		for _, o := range r.ruleset().Options {
+			assert.Assert(t, changes[o.Name] != nil, "no change for %s", o.Name)
+		}
+	})
+
+	seen := map[string]string{base: "base"}
+	for name, change := range changes {
+		r := newRulesetTestRoom(t, timed)
+		change(r)
+
+		hash := r.testRulesetHash()
+		other, ok := seen[hash]
+		assert.Assert(t, !ok, "%s hashes the same as %s", name, other)
+		seen[hash] = name
+	}
+}
+
+func TestRulesetHashFormat(t *testing.T) {
+	r := newRulesetTestRoom(t)
+
+	var rs *protocol.Ruleset
+	r.hold(func() {
+		rs = r.ruleset()
+	})
+
+	assert.Equal(t, rs.Version, protocol.RulesetVersion)
+	assert.Equal(t, rs.Mode, "classic")
+
+	// What's hashed is exactly the JSON served for the hash.
+	b, err := json.Marshal(rs)
+	assert.NilError(t, err)
+
+	var decoded protocol.Ruleset
+	assert.NilError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, hashRuleset(&decoded), hashRuleset(rs))
+	assert.Equal(t, len(hashRuleset(rs)), 64)
+}
+
+func TestRulesetStartedGames(t *testing.T) {
+	s := newTestServer(t, nil)
+
+	room, err := s.CreateRoom(context.Background(), "room", "pass")
+	assert.NilError(t, err)
+
+	var first string
+	room.hold(func() {
+		first = room.rulesetHash
+		assert.Equal(t, room.createRoomState(false).Ruleset, first)
+	})
+
+	rs, ok := s.Ruleset(first)
+	assert.Assert(t, ok)
+	assert.Equal(t, hashRuleset(rs), first)
+
+	_, ok = s.Ruleset("unknown")
+	assert.Assert(t, !ok)
+
+	// Changed options apply to the next game.
+	room.addTestClient(t, "host", 0, true)
+	room.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{HideBomb: boolPtr(true)})
+	assert.Equal(t, room.gameRulesetHash(), first)
+
+	room.testNote(t, "host", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	assert.Assert(t, room.gameRulesetHash() != first)
+
+	_, ok = s.Ruleset(room.gameRulesetHash())
+	assert.Assert(t, ok)
+}
+
+func TestRulesetCacheBounded(t *testing.T) {
+	var c rulesetCache
+	rs := &protocol.Ruleset{}
+
+	for i := 0; i < maxRulesets+10; i++ {
+		c.add(fmt.Sprint(i), rs)
+	}
+
+	assert.Equal(t, len(c.byHash), maxRulesets)
+	_, ok := c.find("0")
+	assert.Assert(t, !ok)
+	_, ok = c.find(fmt.Sprint(maxRulesets + 9))
+	assert.Assert(t, ok)
+}
@@ -4,23 +4,32 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"strconv"
+	"fmt"
+	"io/ioutil"
+	"net"
 	"sync"
 	"time"
 
+	"github.com/zikaeroh/codies/internal/analytics"
+	"github.com/zikaeroh/codies/internal/fit"
 	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/gamefeed"
+	"github.com/zikaeroh/codies/internal/idgen"
+	"github.com/zikaeroh/codies/internal/packs"
 	"github.com/zikaeroh/codies/internal/protocol"
+	"github.com/zikaeroh/codies/internal/suggest"
 	"github.com/zikaeroh/codies/internal/uid"
+	"github.com/zikaeroh/codies/internal/webhook"
 	"github.com/zikaeroh/ctxjoin"
 	"github.com/zikaeroh/ctxlog"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 	"nhooyr.io/websocket"
-	"nhooyr.io/websocket/wsjson"
 )
 
-const maxRooms = 1000
+// MaxRooms is the default maximum number of rooms which may exist at once.
+const MaxRooms = 1000
 
 var (
 	ErrRoomExists   = errors.New("server: rooms exist")
@@ -28,33 +37,169 @@ var (
 )
 
 type Server struct {
-	clientCount atomic.Int64
-	roomCount   atomic.Int64
-	doPrune     chan struct{}
-	ready       chan struct{}
-
-	genRoomID *uid.Generator
+	counters counters
+	stats    atomic.Value // *Stats
+	doPrune  chan struct{}
+	ready    chan struct{}
+
+	seed       int64
+	packs      *packs.Registry
+	packBudget game.PackBudget
+	fit        fit.Thresholds
+	remote     *packs.Fetcher  // Nil if packs can't be added by URL.
+	webhooks   *webhook.Sender // Nil if rooms can't have webhooks.
+	resume     bool            // See resume.go.
+	minReveal  int
+	seatGrace  time.Duration
+	joins      joinLimiter // Each room's starts as a copy; see joinrate.go.
+	spectate   spectatorLimits
+	closed     *closedRooms
+	purges     []purgeHook // Guarded by mu.
+	ipConns    *ipConns
+	creates    *createLimiter
+	matches    *matchPool
+
+	heapThreshold uint64        // Zero if the heap isn't watched; see pressure.go.
+	heapSize      func() uint64 // Replaced in tests.
+	pressure      atomic.Int32  // A pressureLevel.
 
 	ctx context.Context
 
-	mu      sync.Mutex
-	rooms   map[string]*Room
-	roomIDs map[string]*Room
+	mu       sync.Mutex
+	rooms    map[string]*Room
+	roomIDs  map[string]*Room
+	maxRooms int
+	waiters  []chan struct{} // Creations waiting for capacity, oldest first.
+	reserved int             // Slots handed to waiters which haven't been used yet.
+	created  int64           // Rooms created so far.
 }
 
-func NewServer() *Server {
-	return &Server{
-		ready:     make(chan struct{}),
-		doPrune:   make(chan struct{}, 1),
-		genRoomID: uid.NewGenerator(salt()), // IDs are only valid for this server instance; ok to randomize salt.
-		rooms:     make(map[string]*Room),
-		roomIDs:   make(map[string]*Room),
-	}
+// Options configures a Server. The zero value is valid.
+type Options struct {
+	// Packs are the built-in packs. If nil, rooms use the static packs.
+	Packs *packs.Registry
+
+	// ClosedRoomsWindow is how long closed rooms are remembered. If zero, a
+	// default is used.
+	ClosedRoomsWindow time.Duration
+
+	// PackBudget limits each room's custom packs. The zero value is unlimited.
+	PackBudget game.PackBudget
+
+	// FitThresholds decide the display size of the words on each room's
+	// boards. If both are empty, fit.DefaultThresholds are used.
+	FitThresholds fit.Thresholds
+
+	// RemotePacks fetches packs hosts add by URL. If nil, they can't.
+	RemotePacks *packs.Fetcher
+
+	// Webhooks posts finished games to the webhooks hosts set. If nil, they
+	// can't set any.
+	Webhooks *webhook.Sender
+
+	// Resume sends players tokens to reclaim their seats with, should the
+	// server restart and resume their rooms; see ResumeManifest.
+	Resume bool
+
+	// MaxRooms is the maximum number of rooms. If zero, MaxRooms is used.
+	MaxRooms int
+
+	// MaxConnsPerIP is the maximum number of connections from a single IP.
+	// Zero is unlimited.
+	MaxConnsPerIP int
+
+	// ConnAllowlist are networks exempt from MaxConnsPerIP.
+	ConnAllowlist []*net.IPNet
+
+	// Analytics, if set, records anonymized usage events.
+	Analytics *analytics.Log
+
+	// GameFeed, if set, records each finished game, for archiving.
+	GameFeed *gamefeed.Feed
+
+	// MinRevealPlayers is the fewest players a room needs before the first
+	// tile of a game may be revealed, unless the host forces it. Zero disables
+	// the check.
+	MinRevealPlayers int
+
+	// Seed makes game randomness reproducible, for debugging. Each room is
+	// seeded with Seed plus the number of rooms created before it. If zero,
+	// each room is seeded from crypto/rand.
+	Seed int64
+
+	// MatchSize is how many players matchmaking puts in a room. Zero disables
+	// matchmaking.
+	MatchSize int
+
+	// PrivacyStrict hashes room IDs in the closed rooms ring with a salt
+	// drawn when the server starts, so that they can't be tied to the rooms
+	// once it restarts.
+	PrivacyStrict bool
+
+	// HeapThreshold is the heap size, in bytes, past which the server gives
+	// up compression and caches to save memory; see pressure.go. Zero
+	// disables it.
+	HeapThreshold uint64
+
+	// SeatGrace is how long a player's seat is held after their connection
+	// ends, for them to reconnect to; see grace.go. Zero removes players as
+	// soon as they disconnect.
+	SeatGrace time.Duration
+
+	// JoinBurst is how many connections a room admits at once, and
+	// JoinInterval how often it admits another once they're spent; see
+	// joinrate.go. If zero, defaults are used.
+	JoinBurst    int
+	JoinInterval time.Duration
+
+	// MaxSpectators is how many spectators a room sends every state to. If
+	// zero, a default is used.
+	MaxSpectators int
+
+	// SpectatorOverflow admits spectators past MaxSpectators, or past the
+	// join rate, to a tier which is sent the room's state less often,
+	// rather than turning them away; see spectate.go.
+	SpectatorOverflow bool
 }
 
-func salt() string {
-	x := time.Now().Unix()
-	return strconv.FormatInt(x, 10)
+func NewServer(opts Options) *Server {
+	if opts.MaxRooms == 0 {
+		opts.MaxRooms = MaxRooms
+	}
+
+	s := &Server{
+		maxRooms:   opts.MaxRooms,
+		packs:      opts.Packs,
+		packBudget: opts.PackBudget,
+		fit:        opts.FitThresholds,
+		remote:     opts.RemotePacks,
+		webhooks:   opts.Webhooks,
+		resume:     opts.Resume,
+		minReveal:  opts.MinRevealPlayers,
+		seatGrace:  opts.SeatGrace,
+		joins:      joinLimiter{burst: opts.JoinBurst, interval: opts.JoinInterval},
+		spectate:   spectatorLimits{max: opts.MaxSpectators, overflow: opts.SpectatorOverflow},
+		closed:     newClosedRooms(opts.ClosedRoomsWindow),
+		ipConns:    newIPConns(opts.MaxConnsPerIP, opts.ConnAllowlist),
+		creates:    newCreateLimiter(),
+		matches:    newMatchPool(opts.MatchSize),
+		ready:      make(chan struct{}),
+		doPrune:    make(chan struct{}, 1),
+		seed:       opts.Seed,
+		rooms:      make(map[string]*Room),
+		roomIDs:    make(map[string]*Room),
+
+		heapThreshold: opts.HeapThreshold,
+		heapSize:      readHeap,
+	}
+	if opts.PrivacyStrict {
+		s.closed.salt = []byte(idgen.Token())
+	}
+	s.counters.analytics = opts.Analytics
+	s.counters.feed = opts.GameFeed
+	s.stats.Store(&Stats{})
+	s.registerPurges()
+	return s
 }
 
 func (s *Server) Run(ctx context.Context) error {
@@ -64,6 +209,16 @@ func (s *Server) Run(ctx context.Context) error {
 	ticker := time.NewTicker(5 * time.Minute)
 	defer ticker.Stop()
 
+	statsTicker := time.NewTicker(statsInterval)
+	defer statsTicker.Stop()
+
+	var pressureTicks <-chan time.Time
+	if s.heapThreshold != 0 {
+		pressureTicker := time.NewTicker(pressureInterval)
+		defer pressureTicker.Stop()
+		pressureTicks = pressureTicker.C
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -74,6 +229,14 @@ func (s *Server) Run(ctx context.Context) error {
 
 		case <-ticker.C:
 			s.prune(ctx)
+
+		case <-statsTicker.C:
+			if s.counters.statsDirty.Load() {
+				s.refreshStats()
+			}
+
+		case <-pressureTicks:
+			s.checkPressure(ctx)
 		}
 	}
 }
@@ -94,57 +257,205 @@ func (s *Server) FindRoomByID(id string) *Room {
 	return s.roomIDs[id]
 }
 
+// RoomOptions are chosen when a room is created, and can't be changed.
+type RoomOptions struct {
+	// Tracker creates a room without a board; see game.NewTrackerRoom.
+	Tracker bool
+
+	// Unlisted hides the room from ListRooms.
+	Unlisted bool
+
+	// Words, if set, are used instead of the built-in packs, as a custom pack;
+	// see game.CleanWords. Ignored for trackers.
+	Words []string
+
+	// Language, if set, enables the built-in packs in that language in place
+	// of the default pack; see Packs. Ignored for trackers and with Words.
+	Language string
+
+	// Rows and Cols, if set, are the size of the room's first boards, in
+	// place of 5x5. Unlike the rest, the host may change them later. Ignored
+	// for trackers.
+	Rows, Cols int
+
+	restore *ResumeRoom // Set to resume a room; see Server.Resume.
+}
+
 func (s *Server) CreateRoom(ctx context.Context, name, password string) (*Room, error) {
 	<-s.ready
 
+	// Hashed before locking; bcrypt is slow on purpose.
+	hash, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.createRoom(ctx, name, hash, RoomOptions{})
+}
+
+// Must be called with s.mu locked.
+func (s *Server) createRoom(ctx context.Context, name string, password passwordHash, opts RoomOptions) (*Room, error) {
+	var custom []string
+	if len(opts.Words) > 0 && !opts.Tracker {
+		var err error
+		custom, err = game.CleanWords(opts.Words)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	room := s.rooms[name]
 	if room != nil {
 		return nil, ErrRoomExists
 	}
 
-	if len(s.rooms) >= maxRooms {
+	if s.full() {
 		return nil, ErrTooManyRooms
 	}
 
-	id, idRaw := s.genRoomID.Next()
-
-	roomCtx, roomCancel := context.WithCancel(s.ctx)
-
-	room = &Room{
-		Name:        name,
-		Password:    password,
-		ID:          id,
-		clientCount: &s.clientCount,
-		roomCount:   &s.roomCount,
-		genPlayerID: uid.NewGenerator(id),
-		ctx:         roomCtx,
-		cancel:      roomCancel,
-		room:        game.NewRoom(nil),
-		players:     make(map[game.PlayerID]noteSender),
-		turnSeconds: 60,
+	id := s.newRoomID()
+	if rr := opts.restore; rr != nil && rr.ID != "" && s.roomIDs[rr.ID] == nil {
+		id = rr.ID
+	}
+	s.created++
+
+	room = newRoom(s.ctx, name, password, id, &s.counters)
+	room.server = s
+	room.unlisted = opts.Unlisted
+	room.seatGrace = s.seatGrace
+	room.joins = s.joins
+	room.spectate = s.spectate
+	room.origin = RoomOptions{Tracker: opts.Tracker, Unlisted: opts.Unlisted, Words: custom}
+
+	var rand game.Rand
+	if s.seed != 0 {
+		rand = game.NewRand(s.seed + s.created - 1)
 	}
 
-	room.lastSeen.Store(time.Now())
+	if opts.Tracker {
+		room.room = game.NewTrackerRoom(rand)
+		room.role = roleTracker
+	} else {
+		if rand != nil {
+			room.room = game.NewRoom(rand)
+		}
+		room.room.WordLists = wordLists(s.packs.Packs())
+		room.room.PackBudget = s.packBudget
+		if len(s.fit.Text) != 0 || len(s.fit.Emoji) != 0 {
+			room.room.Fit = s.fit
+		}
+		room.room.MinRevealPlayers = s.minReveal
 
+		if custom != nil {
+			if err := room.useCustomWords(custom); err != nil {
+				room.cancel()
+				return nil, err
+			}
+		} else if opts.Language != "" {
+			if err := room.useLanguage(opts.Language); err != nil {
+				room.cancel()
+				return nil, err
+			}
+			room.origin.Language = opts.Language
+		}
+
+		if opts.Rows != 0 || opts.Cols != 0 {
+			if err := room.useBoardSize(opts.Rows, opts.Cols); err != nil {
+				room.cancel()
+				return nil, err
+			}
+		}
+	}
+	if opts.restore != nil {
+		if err := room.restore(opts.restore); err != nil {
+			room.cancel()
+			return nil, err
+		}
+	}
 	room.room.NewGame()
+	room.gameStart = room.clock.Now()
+	room.pacing.start(room.gameStart)
+	room.startRuleset()
+
+	g := room.analyticsGame()
+	s.counters.analytics.RoomCreated(id, g)
+	s.counters.analytics.GameStarted(id, g)
 
 	s.rooms[name] = room
 	s.roomIDs[room.ID] = room
-	s.roomCount.Inc()
+	s.counters.rooms.Inc()
+	s.counters.statsDirty.Store(true)
 	metricRooms.Inc()
 
-	ctxlog.Info(ctx, "created new room", zap.String("roomName", name), zap.String("roomID", room.ID))
+	ctxlog.Info(ctx, "created new room", zap.String("roomName", name), zap.String("roomID", room.ID), zap.Bool("tracker", opts.Tracker), zap.Bool("unlisted", opts.Unlisted))
 
-	if idRaw%100 == 0 {
+	if s.created%100 == 0 {
 		s.triggerPrune()
 	}
 
 	return room, nil
 }
 
+// newRoomID returns a room ID which isn't in use. Room IDs are what clients
+// connect with, so they come from idgen rather than a counter.
+//
+// Must be called with s.mu locked.
+func (s *Server) newRoomID() string {
+	for {
+		id := idgen.RoomID()
+		if s.roomIDs[id] == nil {
+			return id
+		}
+	}
+}
+
+// useBoardSize plays with boards of the given size; either side left zero
+// stays at the default.
+//
+// Must be called before the room is shared.
+func (r *Room) useBoardSize(rows, cols int) error {
+	if rows == 0 {
+		rows = r.room.Rows
+	}
+	if cols == 0 {
+		cols = r.room.Cols
+	}
+	return r.room.ChangeBoards(r.room.NumBoards, rows, cols, r.room.Bombs)
+}
+
+// customPackName names the pack made from the words a room was created with.
+const customPackName = "Custom"
+
+// useCustomWords adds a custom pack and plays with it alone. The built-in packs
+// are kept, disabled, so they may be selected later.
+//
+// Must be called before the room is shared.
+func (r *Room) useCustomWords(wds []string) error {
+	if err := r.room.AddPack(customPackName, wds); err != nil {
+		return err
+	}
+
+	for _, list := range r.room.WordLists {
+		list.Enabled = list.Custom
+	}
+	return nil
+}
+
+func wordLists(packs []*packs.Pack) []*game.WordList {
+	lists := make([]*game.WordList, len(packs))
+	for i, p := range packs {
+		lists[i] = &game.WordList{
+			Name:     p.Name,
+			Language: p.Language,
+			List:     p.List,
+			Enabled:  i == 0,
+		}
+	}
+	return lists
+}
+
 func (s *Server) triggerPrune() {
 	select {
 	case s.doPrune <- struct{}{}:
@@ -171,199 +482,900 @@ func (s *Server) prune(ctx context.Context) {
 
 	for _, name := range toRemove {
 		room := s.rooms[name]
-		room.mu.Lock()
-		room.stopTimer()
-		room.mu.Unlock()
-
-		room.cancel()
-		delete(s.rooms, name)
-		delete(s.roomIDs, room.ID)
-		s.roomCount.Dec()
-		metricRooms.Dec()
+		reason := CloseExpired
+		if !room.claimed.Load() {
+			reason = CloseUnclaimed
+		}
+		s.removeRoom(room, reason)
 	}
 
 	ctxlog.Info(ctx, "pruned rooms", zap.Int("count", len(toRemove)))
 }
 
-func (s *Server) Stats() (rooms, clients int) {
+// Must be called with s.mu locked.
+func (s *Server) removeRoom(room *Room, reason CloseReason) {
+	room.do(func() {
+		room.stopTimer()
+		room.stopMirrorTimer()
+		room.stopSpectatorTimer()
+		room.stopPollTimer()
+		room.clearBans()
+		room.releaseSeats()
+		room.shut(reason)
+	})
+
+	delete(s.rooms, room.Name)
+	delete(s.roomIDs, room.ID)
+	s.counters.rooms.Dec()
+	s.counters.statsDirty.Store(true)
+	metricRooms.Dec()
+
+	s.closed.add(room.ID, reason, time.Now())
+	s.counters.analytics.RoomClosed(room.ID, string(reason), time.Since(room.created))
+	s.purgeRoom(room)
+	s.capacityFreed()
+}
+
+// DeleteRoom deletes a room by ID, returning false if it didn't exist.
+func (s *Server) DeleteRoom(ctx context.Context, id string) bool {
+	<-s.ready
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	return len(s.rooms), int(s.clientCount.Load())
+
+	room := s.roomIDs[id]
+	if room == nil {
+		return false
+	}
+
+	s.removeRoom(room, CloseAdminDeleted)
+	ctxlog.Info(ctx, "deleted room", zap.String("roomName", room.Name), zap.String("roomID", room.ID))
+	return true
+}
+
+// RecentlyClosed returns the record for a room ID if it was recently closed.
+func (s *Server) RecentlyClosed(id string) (ClosedRoom, bool) {
+	return s.closed.find(id, time.Now())
+}
+
+// ClosedRooms lists the recently closed rooms, newest first.
+func (s *Server) ClosedRooms() []ClosedRoom {
+	return s.closed.list(time.Now())
 }
 
+// Room is a single room, and everything connected to it.
+//
+// A room's state belongs to its goroutine; see owner.go. Everything which
+// reads or changes the game, the connections, or the room's bookkeeping runs
+// there, as a command sent with do: connection readers handling notes, HTTP
+// and admin handlers, the server's sweeps, and timers, which are set with
+// after and check that they haven't been stopped. Functions which expect to
+// run on the room's goroutine say so; nothing else may touch the fields below
+// pending.
+//
+// The rest is safe to use from anywhere. Name, ID, and the fields set by
+// newRoom are never changed once the room is shared; the counters and the log
+// level are atomic. Exported methods send commands as needed, and what they
+// return is a copy, never the room's own data. Notes are sent through
+// connWriters, which queue them for their own goroutines rather than writing
+// on the room's.
+//
+// A command may wait on another room's goroutine only where that room could
+// never be waiting on it, and may be sent while holding the server's lock, but
+// the room's goroutine never takes it; the server sends rooms commands when
+// removing them. The matchmaking pool's lock comes before both.
+//
+// The server removes a room only after shutting it on its goroutine, so it
+// stops taking connections before it stops being found; a connection which
+// looked the room up just before is turned away with the reason it closed.
 type Room struct {
-	Name     string
-	Password string
-	ID       string
+	Name string
+	ID   string
+
+	password passwordHash
 
 	ctx         context.Context
 	cancel      context.CancelFunc
-	clientCount *atomic.Int64
-	roomCount   *atomic.Int64
+	server      *Server // Nil for rooms made outside of a Server, like in tests.
+	counters    *counters
+	created     time.Time
 	genPlayerID *uid.Generator
+	role        connRole // The role players connect with; fixed at creation.
+	unlisted    bool     // Hidden from ListRooms; fixed at creation.
+
+	claimed     atomic.Bool // Set once a client has connected.
+	clients     atomic.Int64
+	mirrorCount atomic.Int64
+
+	spectatorCount atomic.Int64
+	banCount       atomic.Int64
+
+	cmds    chan command // To the room's goroutine; see owner.go.
+	ownerMu sync.Mutex   // Guards owning and pending, not the room.
+	owning  bool
+	pending int // Commands sent, or about to be, which the owner hasn't received.
 
-	mu       sync.Mutex
 	room     *game.Room
 	players  map[game.PlayerID]noteSender
+	conns    map[game.PlayerID]*connWriter
+	deltas   map[game.PlayerID]bool // Players with the deltas capability.
+	bytes    map[game.PlayerID]*ByteCounter
+	addrs    map[game.PlayerID]net.IP
+	info     map[game.PlayerID]connInfo
 	state    *stateCache
 	lastSeen atomic.Value
 
+	mirrors      map[string]*mirror
+	mirrorTokens map[string]bool
+	mirrorDelay  int // In seconds.
+	mirrorQueue  []delayedNote
+	mirrorTimer  timer
+	mirrorLatest *protocol.ServerNote // The last state sent to mirrors.
+
+	spectators      map[string]*mirror // Sent what mirrors are; see spectate.go.
+	spectatorsShown int                // The count in the room's state.
+	spectatorTimer  timer
+	spectate        spectatorLimits
+	overflow        int // Spectators in the overflow tier.
+	overflowTimer   timer
+
+	bans []*ban // Oldest first; see ban.go.
+
+	fetchingPack bool // See remotepacks.go.
+
+	clock        clock
+	pingInterval time.Duration
 	timed        bool
 	turnSeconds  int
 	turnDeadline *time.Time
-	turnTimer    *time.Timer
+	turnTimer    timer
+	gameStart    time.Time
+	gameEnd      time.Time  // Set once the game is won.
+	pacing       turnPacing // See pacing.go.
+	revealPacing pacingUndo // The pacing before the reveal Undo would take back.
+	rulesetHash  string     // Of the current game.
+	joins        joinLimiter
+
+	hideBomb    bool
+	notify      notifyMask
+	feedback    feedbackPrompt // See feedback.go.
+	suggestions *suggest.Table
+
+	logLevel atomic.Value // *LogLevel
+
+	desyncs map[game.PlayerID]time.Time // Recent desync reports.
+
+	auditLog []*protocol.AuditEntry
+
+	webhook string // See webhook.go.
+
+	chatLog    []*protocol.ChatMessage // Oldest first; see chat.go.
+	chatLimits map[game.PlayerID]*tokenBucket
+
+	poll      *roomPoll // The open poll; see poll.go.
+	pollID    int       // Of the last poll opened.
+	openPolls bool
+
+	origin       RoomOptions              // As created; see resume.go.
+	resumeTokens map[game.PlayerID]string // Hashes of the tokens players were sent.
+	resumeSeats  map[string]*mergeSeat    // Seats held by a resumed room, by token hash.
+	stoppedSeats []*ResumeSeat            // Of players dropped as the server stopped.
+
+	seatGrace time.Duration           // See grace.go.
+	held      map[game.PlayerID]timer // Seats held for players to reconnect to, until the timers fire.
+
+	reveals     []*protocol.GameLogReveal // Of the current game; see gamelog.go.
+	gameLog     *protocol.GameLog         // Of the last finished game.
+	prevGameLog *protocol.GameLog         // Replaced by gameLog, in case an undo reopens its game.
+
+	mergeOffer   *mergeOffer             // This room's request to merge elsewhere.
+	mergePrompts map[string]*mergePrompt // Requests to merge into this room.
+	mergeSeats   map[string]*mergeSeat   // Seats kept for merged players, by token.
+	mergedInto   string                  // Set once the room's been merged away.
+
+	closeReason CloseReason // Set once the server has closed the room; see shut.
+}
+
+func newRoom(ctx context.Context, name string, password passwordHash, id string, counters *counters) *Room {
+	ctx, cancel := context.WithCancel(ctx)
+
+	room := &Room{
+		Name:         name,
+		ID:           id,
+		password:     password,
+		counters:     counters,
+		genPlayerID:  uid.NewGenerator(id),
+		ctx:          ctx,
+		cancel:       cancel,
+		cmds:         make(chan command),
+		room:         game.NewRoom(nil),
+		players:      make(map[game.PlayerID]noteSender),
+		conns:        make(map[game.PlayerID]*connWriter),
+		deltas:       make(map[game.PlayerID]bool),
+		bytes:        make(map[game.PlayerID]*ByteCounter),
+		addrs:        make(map[game.PlayerID]net.IP),
+		info:         make(map[game.PlayerID]connInfo),
+		mirrors:      make(map[string]*mirror),
+		mirrorTokens: make(map[string]bool),
+		spectators:   make(map[string]*mirror),
+		desyncs:      make(map[game.PlayerID]time.Time),
+		chatLimits:   make(map[game.PlayerID]*tokenBucket),
+		resumeTokens: make(map[game.PlayerID]string),
+		resumeSeats:  make(map[string]*mergeSeat),
+		held:         make(map[game.PlayerID]timer),
+		mergePrompts: make(map[string]*mergePrompt),
+		mergeSeats:   make(map[string]*mergeSeat),
+		suggestions:  suggest.Default,
+		clock:        realClock{},
+		pingInterval: pingInterval,
+		turnSeconds:  60,
+	}
+
+	room.ctx = room.withRoomLogger(ctx)
+	room.created = time.Now()
+	room.lastSeen.Store(room.created)
+	return room
+}
+
+type noteSender func(priority, protocol.ServerNote)
+
+// ConnOptions describes a client connection.
+type ConnOptions struct {
+	Nickname string
+
+	// Bandwidth opts the client into periodic bandwidth notes.
+	Bandwidth bool
+
+	// Deltas opts the client into roster deltas.
+	Deltas bool
+
+	// Counter, if set, counts the client's bytes on the wire.
+	Counter *ByteCounter
+
+	// MergeToken claims a seat kept for a player whose room was merged into
+	// this one.
+	MergeToken string
+
+	// ResumeToken claims a seat kept for a player by a room resumed after the
+	// server restarted, or one held for a player who disconnected.
+	ResumeToken string
+
+	// Spectate connects a spectator rather than a player; the other options
+	// are ignored, but for ContextTakeover.
+	Spectate bool
+
+	// ContextTakeover is set if the connection was accepted with compression
+	// context takeover; see CompressionMode.
+	ContextTakeover bool
+
+	// IP is the address the client connected from, which bans are kept by.
+	IP net.IP
 
-	hideBomb bool
+	// ClientVersion is the version the client claimed to be, if it was a
+	// valid one; see version.Clean. It's logged, and shown to operators.
+	ClientVersion string
+
+	team *game.Team // From the claimed seat, if any.
 }
 
-type noteSender func(protocol.ServerNote)
+// maxPenaltyLimit is the most expired turns which can be allowed before a
+// team forfeits.
+const maxPenaltyLimit = 10
+
+// bandwidthInterval is how often bandwidth notes are sent to clients which
+// opted in.
+const bandwidthInterval = 30 * time.Second
+
+func (r *Room) HandleConn(ctx context.Context, opts ConnOptions, c *websocket.Conn) {
+	if opts.Spectate {
+		r.handleSpectatorConn(ctx, opts, c)
+		return
+	}
 
-func (r *Room) HandleConn(ctx context.Context, nickname string, c *websocket.Conn) {
-	playerID, _ := r.genPlayerID.Next()
+	// A player reclaiming their held seat keeps the ID they had.
+	playerID, nickname := r.seatHolder(opts.ResumeToken)
+	reclaim := playerID != ""
+	if !reclaim {
+		nickname = opts.Nickname
+		playerID, _ = r.genPlayerID.Next()
+	}
+	r.claimed.Store(true)
 
 	ctx, cancel := ctxjoin.AddCancel(ctx, r.ctx)
 	defer cancel()
 
-	ctx = ctxlog.With(ctx, zap.String("roomName", r.Name), zap.String("roomID", r.ID), zap.String("playerID", playerID), zap.String("nickname", nickname))
+	ctx = ctxlog.With(r.withLogger(ctx), zap.String("playerID", playerID), zap.String("nickname", nickname), zap.String("clientVersion", opts.ClientVersion))
 
 	metricClients.Inc()
 	defer metricClients.Dec()
 
-	clientCount := r.clientCount.Inc()
-	ctxlog.Info(ctx, "client connected", zap.Int64("clientCount", clientCount), zap.Int64("roomCount", r.roomCount.Load()))
+	clientCount := r.counters.clients.Inc()
+	r.clients.Inc()
+	r.counters.statsDirty.Store(true)
+	r.counters.analytics.Players(int(clientCount))
+	ctxlog.Info(ctx, "client connected", zap.Int64("clientCount", clientCount), zap.Int64("roomCount", r.counters.rooms.Load()))
+
+	var reason disconnectReason
 
 	defer func() {
-		clientCount := r.clientCount.Dec()
-		ctxlog.Info(ctx, "client disconnected", zap.Int64("clientCount", clientCount), zap.Int64("roomCount", r.roomCount.Load()))
+		clientCount := r.counters.clients.Dec()
+		r.clients.Dec()
+		r.counters.statsDirty.Store(true)
+		metricDisconnects.WithLabelValues(string(reason)).Inc()
+		ctxlog.Info(ctx, "client disconnected", zap.String("reason", string(reason)), zap.Int64("clientCount", clientCount), zap.Int64("roomCount", r.counters.rooms.Load()))
 	}()
 
-	defer c.Close(websocket.StatusGoingAway, "going away")
+	w := newConnWriter(c)
+	w.resync = func() { r.resync(playerID) }
+	w.now = r.clock.Now
 
+	connCtx := ctx
 	g, ctx := errgroup.WithContext(ctx)
 
-	r.mu.Lock()
-	r.players[playerID] = func(s protocol.ServerNote) {
-		if ctx.Err() != nil {
+	// Should the connection be turned away, refuse says how, once the room's
+	// goroutine is free again.
+	var refuse func()
+	r.do(func() {
+		// The room may have been closed since the connection found it.
+		if r.closing() {
+			closed := r.closeReason
+			refuse = func() {
+				reason = disconnectServer
+				r.refuseClosed(ctx, w, closed)
+			}
+			return
+		}
+
+		if target := r.mergedInto; target != "" {
+			refuse = func() {
+				reason = disconnectRejected
+				r.redirectConn(ctx, w, target)
+			}
+			return
+		}
+
+		if r.banned(opts.IP) {
+			refuse = func() {
+				reason = disconnectRejected
+				r.rejectConn(ctx, w, closeBanned, errBanned)
+			}
 			return
 		}
 
-		// It's not safe to start more group goroutines concurrently; just use a regular
-		// goroutine and hope that errors here will be reflected later via ping/receive failures.
-		go func() {
-			ctx, cancel := context.WithTimeout(ctx, time.Second)
-			defer cancel()
-			if err := wsjson.Write(ctx, c, &s); err != nil {
+		if reclaim {
+			switch p := r.room.Players[playerID]; {
+			case p == nil:
+				// The grace ran out in the meantime, so they join afresh, under
+				// the name they had.
+				reclaim = false
+				opts.Nickname = nickname
+			case r.resumeTokens[playerID] != hashResumeToken(opts.ResumeToken):
+				refuse = func() {
+					reason = disconnectRejected
+					r.rejectConn(ctx, w, closeReplaced, errSeatReclaimed)
+				}
 				return
+			default:
+				opts.Nickname = p.Nickname
 			}
-			metricSent.Inc()
-		}()
-	}
-	r.room.AddPlayer(playerID, nickname)
-	r.sendAll()
-	r.mu.Unlock()
+		}
 
-	defer func() {
-		r.mu.Lock()
-		defer r.mu.Unlock()
-		delete(r.players, playerID)
-		r.room.RemovePlayer(playerID)
-		r.sendAll()
-	}()
+		if !reclaim {
+			// Players arriving from a merge were already let in by the host, so
+			// they aren't throttled; nor are those returning after a restart.
+			seat := r.takeMergeSeat(opts.MergeToken)
+			resumeHash := ""
+			if seat == nil {
+				seat, resumeHash = r.takeResumeSeat(opts.ResumeToken)
+			}
+			if seat != nil {
+				nickname = seat.nickname
+				opts.Nickname = seat.nickname
+				opts.team = &seat.team
+			} else if err := r.admitJoin(); err != nil {
+				refuse = func() {
+					reason = disconnectRejected
+					r.rejectConn(ctx, w, closeRoomBusy, err)
+				}
+				return
+			}
+
+			if err := r.room.NicknameAllowed(playerID, nickname); err != nil {
+				switch {
+				case resumeHash != "":
+					r.resumeSeats[resumeHash] = seat
+				case seat != nil:
+					r.mergeSeats[opts.MergeToken] = seat
+				}
+				refuse = func() {
+					reason = disconnectRejected
+					r.rejectConn(ctx, w, closeNicknameTaken, err)
+				}
+				return
+			}
+		}
+
+		g.Go(func() error {
+			return w.run(ctx)
+		})
 
+		if reclaim {
+			r.replaceConn(playerID)
+		}
+		r.join(playerID, w.send, opts)
+		r.conns[playerID] = w
+	})
+	if refuse != nil {
+		refuse()
+		return
+	}
+
+	// Disconnect as soon as the connection fails, rather than once everything
+	// has wound down; closing a connection to a client which has vanished can
+	// take a while, and the room shouldn't show them in the meantime.
 	g.Go(func() error {
 		<-ctx.Done()
-		return c.Close(websocket.StatusGoingAway, "going away")
+		r.disconnect(playerID, w)
+		return nil
 	})
 
 	g.Go(func() error {
-		ticker := time.NewTicker(time.Minute)
-		defer ticker.Stop()
+		return r.keepalive(ctx, w)
+	})
 
-		for {
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-ticker.C:
-			}
+	if opts.Bandwidth {
+		g.Go(func() error {
+			ticker := time.NewTicker(bandwidthInterval)
+			defer ticker.Stop()
 
-			if err := c.Ping(ctx); err != nil {
-				return err
-			}
+			for {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-ticker.C:
+				}
 
-			r.lastSeen.Store(time.Now())
-		}
-	})
+				r.do(func() {
+					if sender := r.players[playerID]; sender != nil {
+						sender(priorityTargeted, protocol.NewBandwidthNote(opts.Counter.Sent(), opts.Counter.Received()))
+					}
+				})
+			}
+		})
+	}
 
 	g.Go(func() error {
 		for {
 			var note protocol.ClientNote
 
-			if err := wsjson.Read(ctx, c, &note); err != nil {
+			if err := readClientNote(ctx, c, w, &note); err != nil {
 				return err
 			}
 
+			if r.answerTimeSync(w, &note) {
+				continue
+			}
+
 			ctx := ctxlog.With(ctx, zap.String("method", string(note.Method)))
 
+			if !allowed(r.role, note.Method) {
+				continue
+			}
+
+			ctxlog.Debug(ctx, "received note", zap.Int64("version", note.Version))
+
 			r.lastSeen.Store(time.Now())
+			r.counters.statsDirty.Store(true)
 			metricReceived.Inc()
 
-			if err := r.handleNote(ctx, playerID, &note); err != nil {
+			var err error
+			r.do(func() { err = r.handleNote(ctx, playerID, &note) })
+			if err != nil {
 				metricHandleErrors.Inc()
 				ctxlog.Error(ctx, "error handling note", zap.Error(err))
-				return err
+				return &handleError{err: err}
+			}
+		}
+	})
+
+	err := g.Wait()
+	reason = classifyDisconnect(connCtx, w, err)
+	ctxlog.Debug(ctx, "connection ended", zap.String("reason", string(reason)), zap.Error(err))
+}
+
+var errInvalidNote = errors.New("message isn't a note")
+
+// readClientNote reads the next note from the connection. Unlike wsjson.Read,
+// it doesn't close the connection itself when the message can't be decoded;
+// the close is queued on the writer instead, which owns all writes.
+func readClientNote(ctx context.Context, c *websocket.Conn, w *connWriter, note *protocol.ClientNote) error {
+	_, reader, err := c.Reader(ctx)
+	if err != nil {
+		return err
+	}
+
+	b, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(b, note); err != nil {
+		w.close(websocket.StatusInvalidFramePayloadData, errInvalidNote.Error())
+		return fmt.Errorf("%w: %v", errInvalidNote, err)
+	}
+
+	return nil
+}
+
+// answerTimeSync answers the note if it's a timeSync command, which is part
+// of the connection rather than the game, like a ping. It returns true if the
+// note was answered.
+func (r *Room) answerTimeSync(w *connWriter, note *protocol.ClientNote) bool {
+	if note.Method != protocol.TimeSyncMethod {
+		return false
+	}
+
+	received := r.clock.Now()
+
+	// A malformed command can't be answered meaningfully, and costs the room
+	// nothing, so it's dropped.
+	var params protocol.TimeSyncParams
+	if err := json.Unmarshal(note.Params, &params); err != nil {
+		return true
+	}
+
+	w.send(priorityAck, protocol.NewTimeSyncNote(params.ClientTime, received))
+	return true
+}
+
+// Connections are pinged every pingInterval. One whose client leaves
+// pingMisses pings in a row unanswered is reaped: closed, and its player
+// removed from the room. Some proxies let connections die without a word to
+// either side, and until a write fails, the room would keep a ghost player.
+const (
+	pingInterval = 30 * time.Second
+	pingMisses   = 2
+)
+
+// closeUnresponsive closes a reaped connection. It's unlikely the client is
+// there to read it, but if only its pongs were lost, it may reconnect.
+const closeUnresponsive websocket.StatusCode = 4504
+
+var errUnresponsive = errors.New("pings went unanswered")
+
+// keepalive pings the connection until ctx is done, or the connection is
+// reaped.
+func (r *Room) keepalive(ctx context.Context, w *connWriter) error {
+	ticker := time.NewTicker(r.pingInterval)
+	defer ticker.Stop()
+
+	var pong chan error
+	missed := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		if pong != nil {
+			select {
+			case err := <-pong:
+				if err != nil {
+					return err
+				}
+				missed = 0
+				r.lastSeen.Store(time.Now())
+				r.counters.statsDirty.Store(true)
+			default:
+				missed++
 			}
 		}
+
+		if missed >= pingMisses {
+			metricConnsReaped.Inc()
+			ctxlog.Info(ctx, "reaping unresponsive connection", zap.Int("missedPings", missed))
+			// The close is sent as the connection winds down; see goingAway.
+			w.close(closeUnresponsive, "pings went unanswered")
+			return errUnresponsive
+		}
+
+		pong = w.queuePing()
+	}
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) join(playerID game.PlayerID, sender noteSender, opts ConnOptions) {
+	r.players[playerID] = sender
+	r.bytes[playerID] = opts.Counter
+	if opts.IP != nil {
+		r.addrs[playerID] = opts.IP
+	}
+	r.info[playerID] = connInfo{clientVersion: opts.ClientVersion, connected: r.clock.Now()}
+	if opts.Deltas {
+		r.deltas[playerID] = true
+	}
+
+	team := game.Team(-1)
+	if opts.team != nil {
+		team = *opts.team
+	}
+
+	roster := r.currentState()
+	if r.room.Players[playerID] != nil {
+		// They're reclaiming their held seat, and may not have been marked
+		// disconnected yet; they get a fresh state either way.
+		r.room.SetDisconnected(playerID, false)
+		if r.room.Version == roster.version {
+			r.sendOne(playerID, sender, priorityBroadcast)
+		}
+	} else {
+		r.room.AddPlayerOnTeam(playerID, opts.Nickname, team)
+	}
+	r.sendRoster(roster, playerID)
+	r.sendChatHistory(playerID)
+	r.sendPoll(playerID, opts.Nickname)
+	r.sendResumeToken(playerID)
+}
+
+// removePlayer removes a player from the room, their connection included,
+// without sending anything. It's a no-op for players who aren't in the room.
+//
+// Must be called on the room's goroutine.
+func (r *Room) removePlayer(playerID game.PlayerID) {
+	delete(r.players, playerID)
+	delete(r.conns, playerID)
+	delete(r.bytes, playerID)
+	delete(r.deltas, playerID)
+	delete(r.addrs, playerID)
+	delete(r.info, playerID)
+	delete(r.feedback.pending, playerID)
+	delete(r.chatLimits, playerID)
+	delete(r.resumeTokens, playerID)
+	r.releaseSeat(playerID)
+	r.holdVote(playerID)
+
+	host := r.room.Host
+	name := ""
+	if p := r.room.Players[playerID]; p != nil {
+		name = p.Nickname
+	}
+
+	r.room.RemovePlayer(playerID)
+
+	if host == playerID && r.room.Host != "" {
+		change := &protocol.AuditChange{Field: "host", Before: name, After: r.room.Players[r.room.Host].Nickname}
+		r.audit(auditHostTransfer, "", r.room.Host, change)
+	}
+}
+
+// resync sends a player a fresh state, after their connection fell behind and
+// dropped broadcasts.
+func (r *Room) resync(playerID game.PlayerID) {
+	r.do(func() {
+		if sender := r.players[playerID]; sender != nil {
+			r.sendOne(playerID, sender, priorityBroadcast)
+		}
 	})
+}
+
+// Application-specific WebSocket close codes.
+const (
+	closeNicknameTaken websocket.StatusCode = 4409
+)
+
+// rejectConn sends a rule violation to a connection which was never added to
+// the room, then closes it.
+func (r *Room) rejectConn(ctx context.Context, w *connWriter, code websocket.StatusCode, err error) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = w.run(ctx)
+	}()
 
-	_ = g.Wait()
+	hint := newCloseHint(code, err.Error())
+
+	var gErr *game.Error
+	if errors.As(err, &gErr) {
+		note := protocol.NewErrorNote(protocolError(gErr))
+		_ = w.sendWait(ctx, priorityTargeted, note)
+
+		if hint.Retry && gErr.RetryAfter != nil {
+			hint.AfterMS = *gErr.RetryAfter * int(time.Second/time.Millisecond)
+		}
+	}
+
+	ctxlog.Info(ctx, "rejected client", zap.Error(err))
+	w.closeHinted(code, hint)
+	<-done
 }
 
 var errMissingPlayer = errors.New("missing player during handleNote")
 
+var errStaleVersion = &game.Error{
+	Code:    "staleVersion",
+	Message: "Room has changed since the command was sent.",
+}
+
+// Must be called on the room's goroutine.
+//
 //nolint:gocyclo
-func (r *Room) handleNote(ctx context.Context, playerID game.PlayerID, note *protocol.ClientNote) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+func (r *Room) handleNote(ctx context.Context, playerID game.PlayerID, note *protocol.ClientNote) (err error) {
+	// The player left while the note was on its way, so there's nobody to
+	// handle it for. Nor is there once the room is closing, and handling it
+	// could restart the timers the server stopped.
+	p := r.players[playerID]
+	if p == nil || r.closing() {
+		return nil
+	}
+
+	// Chat doesn't depend on the game, so it's sent whatever the version.
+	if note.Method == protocol.ChatMethod {
+		return r.handleChat(playerID, note)
+	}
+
+	// Nor do polls.
+	switch note.Method {
+	case protocol.OpenPollMethod, protocol.VoteMethod, protocol.ClosePollMethod:
+		return r.handlePoll(playerID, note)
+	}
 
 	// The client's version was wrong; reject and send them the current state.
 	if note.Version != r.room.Version {
-		p := r.players[playerID]
-		if p == nil {
-			return errMissingPlayer
-		}
-		r.sendOne(playerID, p)
+		r.sendAck(playerID, note.ID, errStaleVersion)
+		r.sendOne(playerID, p, priorityTargeted)
 		return nil
 	}
 
-	before := r.room.Version
-	resetTimer := false
+	before := r.room.Version
+	snap := r.snapshotTurn()
+	resetTimer := false
+
+	// Option changes are applied together after the switch.
+	var opts *protocol.UpdateOptionsParams
+	var optionsChanged []string
+
+	// Set for changes which should only affect the player list.
+	var roster *stateCache
+
+	// Set for commands which finish later, and ack then.
+	var ackLater bool
+
+	// Set when an undo restored the pacing, which has nothing left to record.
+	var pacingRestored bool
+
+	defer func() {
+		// Roll over before acking, so the ack carries the version the
+		// following state will have.
+		rolledOver := r.rolloverVersion()
+
+		var violation *game.Error
+		if errors.As(err, &violation) {
+			err = r.sendError(playerID, violation)
+		}
+		if err == nil && !ackLater {
+			r.sendAck(playerID, note.ID, violation)
+		}
+
+		if r.room.Version != before {
+			if snap.games != r.room.Games {
+				r.startRuleset()
+			}
+			if r.timed && (resetTimer || r.room.Winner != nil) {
+				r.startTimer()
+			}
+			// Recorded first, so the state has the finished game's length.
+			r.recordGame(snap)
+			if roster != nil && !rolledOver {
+				r.sendRoster(roster, "")
+			} else {
+				r.sendAll()
+			}
+			r.sendNotifications(r.notifications(snap))
+			if !pacingRestored {
+				r.recordPacing(snap)
+			}
+			r.promptFeedback(snap)
+		}
+
+		if len(optionsChanged) != 0 {
+			r.sendOptionsChanged(optionsChanged)
+		}
+	}()
+
+	switch note.Method {
+	case protocol.RevealMethod:
+		var params protocol.RevealParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		if err := r.room.CheckReveal(); err != nil {
+			if !params.Force || playerID != r.room.Host {
+				ctxlog.Info(ctx, "rejected first reveal", zap.Int("players", len(r.room.Players)))
+				return err
+			}
+			r.audit(auditForceReveal, playerID, "")
+		}
+		prevTurn := r.room.Turn
+		pacing := r.pacing.save()
+		r.room.Reveal(playerID, params.Board, params.Row, params.Col)
+		resetTimer = prevTurn != r.room.Turn
+		if r.room.Version != before {
+			r.revealPacing = pacing
+			r.logReveal(playerID, params.Board, params.Row, params.Col, false)
+		}
+
+	case protocol.NewGameMethod:
+		var params protocol.NewGameParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		// Not even the host can deal boards without enough words.
+		if err := r.room.CheckWords(); err != nil {
+			return err
+		}
+		if err := r.room.CheckStart(); err != nil {
+			if !params.Force || playerID != r.room.Host {
+				return err
+			}
+			r.audit(auditForceNewGame, playerID, "")
+		}
+		resetTimer = true
+		r.room.NewGame()
+
+	case protocol.UseHintMethod:
+		var params protocol.UseHintParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		hidden := r.hiddenTiles(params.Board)
+		if err := r.room.UseHint(playerID, params.Board); err != nil {
+			return err
+		}
+		if r.room.Version != before {
+			r.logHint(playerID, params.Board, hidden)
+			ctxlog.Info(ctx, "hint used", zap.Int("team", int(r.room.Turn)), zap.Ints("used", r.room.HintsUsed))
+		}
+
+	case protocol.ScoreMethod:
+		var params protocol.ScoreParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		r.room.Score(playerID, params.Team, params.Delta)
 
-	defer func() {
-		if r.room.Version != before {
-			if r.timed && resetTimer {
-				r.startTimer()
-			}
-			r.sendAll()
+	case protocol.DeclareWinnerMethod:
+		var params protocol.DeclareWinnerParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
 		}
-	}()
+		r.room.DeclareWinner(playerID, params.Team)
 
-	switch note.Method {
-	case protocol.RevealMethod:
-		var params protocol.RevealParams
+	case protocol.ResetWinsMethod:
+		var params protocol.ResetWinsParams
 		if err := json.Unmarshal(note.Params, &params); err != nil {
 			return err
 		}
-		prevTurn := r.room.Turn
-		r.room.Reveal(playerID, params.Row, params.Col)
-		resetTimer = prevTurn != r.room.Turn
+		r.room.ResetWins(playerID)
 
-	case protocol.NewGameMethod:
-		var params protocol.NewGameParams
+	case protocol.UndoMethod:
+		var params protocol.UndoParams
 		if err := json.Unmarshal(note.Params, &params); err != nil {
 			return err
 		}
-		resetTimer = true
-		r.room.NewGame()
+		prevTurn := r.room.Turn
+		if err := r.room.Undo(playerID); err != nil {
+			return err
+		}
+		if r.room.Version != before {
+			r.audit(auditUndo, playerID, "")
+			r.unlogReveal(snap.won)
+			r.pacing.restore(r.revealPacing)
+			pacingRestored = true
+		}
+		// A reopened game's timer was stopped when it was won.
+		resetTimer = prevTurn != r.room.Turn || snap.won
 
 	case protocol.EndTurnMethod:
 		var params protocol.EndTurnParams
@@ -380,6 +1392,15 @@ func (r *Room) handleNote(ctx context.Context, playerID game.PlayerID, note *pro
 		}
 		r.room.RandomizeTeams()
 
+	case protocol.ChangeTeamConstraintsMethod:
+		var params protocol.ChangeTeamConstraintsParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		if err := r.changeTeamConstraints(playerID, params.Constraints); err != nil {
+			return err
+		}
+
 	case protocol.ChangeTeamMethod:
 		var params protocol.ChangeTeamParams
 		if err := json.Unmarshal(note.Params, &params); err != nil {
@@ -398,14 +1419,19 @@ func (r *Room) handleNote(ctx context.Context, playerID game.PlayerID, note *pro
 			return nil
 		}
 
-		r.room.AddPlayer(playerID, params.Nickname)
+		roster = r.currentState()
+		if err := r.room.ChangeNickname(playerID, params.Nickname); err != nil {
+			return err
+		}
 
 	case protocol.ChangeRoleMethod:
 		var params protocol.ChangeRoleParams
 		if err := json.Unmarshal(note.Params, &params); err != nil {
 			return err
 		}
-		r.room.ChangeRole(playerID, params.Spymaster)
+		if err := r.room.ChangeRole(playerID, params.Spymaster); err != nil {
+			return err
+		}
 
 	case protocol.ChangePackMethod:
 		var params protocol.ChangePackParams
@@ -419,63 +1445,327 @@ func (r *Room) handleNote(ctx context.Context, playerID game.PlayerID, note *pro
 		if err := json.Unmarshal(note.Params, &params); err != nil {
 			return err
 		}
-		r.changeTurnMode(params.Timed)
+		opts = &protocol.UpdateOptionsParams{Timed: &params.Timed}
 
 	case protocol.ChangeTurnTimeMethod:
 		var params protocol.ChangeTurnTimeParams
 		if err := json.Unmarshal(note.Params, &params); err != nil {
 			return err
 		}
-		r.changeTurnTime(params.Seconds)
+		opts = &protocol.UpdateOptionsParams{TurnTime: &params.Seconds}
+
+	case protocol.ChangePenaltiesMethod:
+		var params protocol.ChangePenaltiesParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		opts = &protocol.UpdateOptionsParams{PenaltyLimit: &params.Limit}
 
 	case protocol.AddPacksMethod:
 		var params protocol.AddPacksParams
 		if err := json.Unmarshal(note.Params, &params); err != nil {
 			return err
 		}
-		for _, p := range params.Packs {
-			if len(p.Words) < 25 {
-				continue
-			}
-			r.room.AddPack(p.Name, p.Words)
+		if err := r.addPacks(playerID, &params); err != nil {
+			return err
+		}
+
+	case protocol.AddPackURLMethod:
+		var params protocol.AddPackURLParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		started, err := r.addPackURL(playerID, note.ID, &params)
+		if err != nil {
+			return err
 		}
+		ackLater = started
 
 	case protocol.RemovePackMethod:
 		var params protocol.RemovePackParams
 		if err := json.Unmarshal(note.Params, &params); err != nil {
 			return err
 		}
-		r.room.RemovePack(params.Num)
+		if err := r.removePack(playerID, params.Num); err != nil {
+			return err
+		}
+
+	case protocol.RateGameMethod:
+		var params protocol.RateGameParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		if err := r.rateGame(playerID, &params); err != nil {
+			return err
+		}
+
+	case protocol.SuggestWordMethod:
+		var params protocol.SuggestWordParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		if err := r.suggestWord(playerID, &params); err != nil {
+			return err
+		}
 
 	case protocol.ChangeHideBombMethod:
 		var params protocol.ChangeHideBombParams
 		if err := json.Unmarshal(note.Params, &params); err != nil {
 			return err
 		}
-		r.changeHideBomb(params.HideBomb)
+		opts = &protocol.UpdateOptionsParams{HideBomb: &params.HideBomb}
+
+	case protocol.ChangeMirrorDelayMethod:
+		var params protocol.ChangeMirrorDelayParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		opts = &protocol.UpdateOptionsParams{MirrorDelay: &params.Seconds}
+
+	case protocol.GiveClueMethod:
+		var params protocol.GiveClueParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		if err := r.room.GiveClue(playerID, params.Board, params.Word, params.Count); err != nil {
+			return err
+		}
+
+	case protocol.ChangeBoundCluesMethod:
+		var params protocol.ChangeBoundCluesParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		opts = &protocol.UpdateOptionsParams{BoundClues: &params.BoundClues}
+
+	case protocol.ChangeSuggestCluesMethod:
+		var params protocol.ChangeSuggestCluesParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		opts = &protocol.UpdateOptionsParams{SuggestClues: &params.SuggestClues}
+
+	case protocol.ChangeSpymastersMethod:
+		var params protocol.ChangeSpymastersParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		opts = &protocol.UpdateOptionsParams{SpymasterLimit: &params.Limit, ConfirmClues: &params.ConfirmClues}
+
+	case protocol.UpdateOptionsMethod:
+		opts = &protocol.UpdateOptionsParams{}
+		if err := json.Unmarshal(note.Params, opts); err != nil {
+			return err
+		}
+
+	case protocol.ChangeNotificationsMethod:
+		var params protocol.ChangeNotificationsParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		r.changeNotifications(params.Off, params.Disabled)
+
+	case protocol.MintMirrorTokenMethod:
+		if err := r.mintMirrorToken(playerID); err != nil {
+			return err
+		}
+
+	case protocol.RevokeMirrorTokenMethod:
+		var params protocol.RevokeMirrorTokenParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		r.revokeMirrorToken(playerID, params.Token)
+
+	case protocol.ReportDesyncMethod:
+		var params protocol.ReportDesyncParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		r.reportDesync(ctx, playerID, params.Hash)
+
+	case protocol.KickMethod:
+		var params protocol.KickParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		roster = r.currentState()
+		r.kick(playerID, params.PlayerID)
+
+	case protocol.BanMethod:
+		var params protocol.BanParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		roster = r.currentState()
+		if err := r.ban(playerID, params.PlayerID); err != nil {
+			return err
+		}
+
+	case protocol.UnbanMethod:
+		var params protocol.UnbanParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		r.unban(playerID, params.PlayerID)
+
+	case protocol.GetBansMethod:
+		r.sendBans(playerID)
+
+	case protocol.GetAuditLogMethod:
+		r.sendAuditLog(playerID)
+
+	case protocol.SetWebhookMethod:
+		var params protocol.SetWebhookParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		if err := r.setWebhook(playerID, params.URL); err != nil {
+			return err
+		}
+
+	case protocol.GetWebhookMethod:
+		r.sendWebhookURL(playerID)
+
+	case protocol.RequestMergeMethod:
+		var params protocol.RequestMergeParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		if err := r.requestMerge(playerID, params.RoomID); err != nil {
+			return err
+		}
+
+	case protocol.AnswerMergeMethod:
+		var params protocol.AnswerMergeParams
+		if err := json.Unmarshal(note.Params, &params); err != nil {
+			return err
+		}
+		if err := r.answerMerge(playerID, params.ID, params.Accept); err != nil {
+			return err
+		}
+
+	case protocol.DebugInfoMethod:
+		if sender := r.players[playerID]; sender != nil {
+			counter := r.bytes[playerID]
+			sender(priorityTargeted, protocol.NewDebugInfoNote(playerID, counter.Sent(), counter.Received()))
+		}
 
 	default:
 		ctxlog.Warn(ctx, "unhandled method")
 	}
 
+	if opts != nil {
+		optionsChanged, err = r.updateOptions(playerID, opts)
+		return err
+	}
+
 	return nil
 }
 
-// Must be called with r.mu locked.
+// Must be called on the room's goroutine.
 func (r *Room) sendAll() {
+	r.rolloverVersion()
+
+	ctxlog.Debug(r.ctx, "sending state", zap.Int64("version", r.room.Version), zap.Int("players", len(r.players)), zap.Int("mirrors", len(r.mirrors)))
+
 	for playerID, sender := range r.players {
-		r.sendOne(playerID, sender)
+		r.sendOne(playerID, sender, priorityBroadcast)
 	}
+	r.sendMirrors()
 }
 
-// Must be called with r.mu locked.
-func (r *Room) sendOne(playerID game.PlayerID, sender noteSender) {
+// rolloverVersion restarts the room's version if it's run past what clients
+// can represent. Delta clients can't apply changes across a rollover, so the
+// next broadcast must be a full state; sendAll and sendRoster check this
+// before sending anything.
+//
+// Must be called on the room's goroutine.
+func (r *Room) rolloverVersion() bool {
+	if !r.room.RolloverVersion() {
+		return false
+	}
+	metricVersionRollovers.Inc()
+	ctxlog.Warn(r.ctx, "room version rolled over")
+	r.state = nil
+	return true
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) sendOne(playerID game.PlayerID, sender noteSender, p priority) {
 	state := r.createStateFor(playerID)
 	note := protocol.NewStateNote(playerID, state)
-	sender(note)
+	sender(p, note)
+
+	if suggestions := r.suggestionsFor(playerID); suggestions != nil {
+		sender(priorityTargeted, protocol.NewClueSuggestionsNote(suggestions))
+	}
+}
+
+// sendError reports a rule violation to only the player who caused it. Errors
+// which aren't rule violations are returned as-is.
+//
+// Must be called on the room's goroutine.
+func (r *Room) sendError(playerID game.PlayerID, err error) error {
+	var gErr *game.Error
+	if !errors.As(err, &gErr) {
+		return err
+	}
+
+	sender := r.players[playerID]
+	if sender == nil {
+		return errMissingPlayer
+	}
+
+	sender(priorityTargeted, protocol.NewErrorNote(protocolError(gErr)))
+	return nil
+}
+
+func protocolError(gErr *game.Error) *protocol.Error {
+	e := &protocol.Error{
+		Code:       gErr.Code,
+		Message:    gErr.Message,
+		Limit:      gErr.Limit,
+		RetryAfter: gErr.RetryAfter,
+		Conflicts:  protocolConstraints(gErr.Conflicts),
+	}
+
+	for _, s := range gErr.Shortfalls {
+		e.Shortfalls = append(e.Shortfalls, &protocol.ErrorShortfall{
+			Team:      s.Team,
+			Spymaster: s.Spymaster,
+			Have:      s.Have,
+			Need:      s.Need,
+		})
+	}
+
+	return e
+}
+
+// sendAck acknowledges a command which carried an ID. It goes out before the
+// broadcast of any change the command made, and carries the version of the
+// room after the command was handled.
+//
+// Must be called on the room's goroutine.
+func (r *Room) sendAck(playerID game.PlayerID, id string, violation *game.Error) {
+	if id == "" {
+		return
+	}
+
+	sender := r.players[playerID]
+	if sender == nil {
+		return
+	}
+
+	var e *protocol.Error
+	if violation != nil {
+		e = protocolError(violation)
+	}
+
+	sender(priorityAck, protocol.NewAckNote(id, r.room.Version, e))
 }
 
-// Must be called with r.mu locked.
+// Must be called on the room's goroutine.
 func (r *Room) createStateFor(playerID game.PlayerID) *protocol.RoomState {
 	if r.state == nil || r.state.version != r.room.Version {
 		r.state = r.createStateCache()
@@ -494,31 +1784,122 @@ func (r *Room) createStateFor(playerID game.PlayerID) *protocol.RoomState {
 }
 
 type stateCache struct {
-	version   int
+	version   int64
 	guesser   *protocol.RoomState
 	spymaster *protocol.RoomState
+	boardHash string // The full BoardHash, which desync reports carry.
+
+	// Suggested clues for the spymasters whose turn it is, or nil.
+	suggestions []*protocol.ClueSuggestion
 }
 
+// createStateCache builds the states for the room's current version. The
+// board hash is computed once, from the guessers' state, and shared with the
+// spymasters', whose extra views it doesn't cover. Each state is then encoded
+// once, for every player it's sent to.
 func (r *Room) createStateCache() *stateCache {
-	return &stateCache{
-		version:   r.room.Version,
-		guesser:   r.createRoomState(false),
-		spymaster: r.createRoomState(true),
+	c := &stateCache{
+		version:     r.room.Version,
+		guesser:     r.createRoomState(false),
+		spymaster:   r.createRoomState(true),
+		suggestions: r.createSuggestions(),
 	}
+
+	c.boardHash = protocol.BoardHash(c.guesser)
+	c.guesser.BoardHash = c.boardHash[:protocol.ShortBoardHashLength]
+	c.spymaster.BoardHash = c.guesser.BoardHash
+
+	metricStateBytes.WithLabelValues("guesser").Observe(float64(c.guesser.Encode()))
+	metricStateBytes.WithLabelValues("spymaster").Observe(float64(c.spymaster.Encode()))
+	return c
 }
 
 func (r *Room) createRoomState(spymaster bool) *protocol.RoomState {
 	room := r.room
 
 	s := &protocol.RoomState{
-		Version:   room.Version,
-		Teams:     make([][]*protocol.StatePlayer, len(room.Teams)),
-		Turn:      room.Turn,
-		Winner:    room.Winner,
-		Board:     make([][]*protocol.StateTile, room.Board.Rows),
-		WordsLeft: room.Board.WordCounts,
-		Lists:     make([]*protocol.StateWordList, len(room.WordLists)),
-		HideBomb:  r.hideBomb,
+		Version:      room.Version,
+		Teams:        make([][]*protocol.StatePlayer, len(room.Teams)),
+		Turn:         room.Turn,
+		Winner:       room.Winner,
+		Wins:         append([]int(nil), room.Wins...),
+		HideBomb:     r.hideBomb,
+		Feedback:     r.feedback.enabled,
+		OpenPolls:    r.openPolls,
+		MirrorDelay:  r.mirrorDelay,
+		Spectators:   r.spectatorsShown,
+		BoundClues:   room.BoundClues,
+		SuggestClues: room.SuggestClues,
+		Ruleset:      r.rulesetHash,
+		Spymasters: &protocol.StateSpymasters{
+			Limit:        room.SpymasterLimit,
+			ConfirmClues: room.ConfirmClues,
+		},
+		Start: &protocol.StateStart{
+			MinSpymasters: room.MinSpymasters,
+			MinGuessers:   room.MinGuessers,
+		},
+		TeamConstraints: protocolConstraints(room.TeamConstraints),
+	}
+
+	s.Notifications = &protocol.StateNotifications{
+		Off:      r.notify.off,
+		Disabled: []protocol.NotificationEvent{},
+	}
+	for _, event := range protocol.NotificationEvents {
+		if r.notify.disabled[event] {
+			s.Notifications.Disabled = append(s.Notifications.Disabled, event)
+		}
+	}
+
+	if room.Clue != nil {
+		s.Clue = &protocol.StateClue{
+			Board: room.Clue.Board,
+			Word:  room.Clue.Word,
+			Count: room.Clue.Count,
+		}
+	}
+
+	s.EnforceGuessLimit = room.EnforceGuessLimit
+	if left, ok := room.GuessesLeft(); ok {
+		s.GuessesLeft = &left
+	}
+	s.CanUndo = room.CanUndo()
+	s.Webhook = r.webhook != ""
+
+	if spymaster && !room.Tracker {
+		s.KeyChecksum = room.KeyChecksum()
+	}
+
+	// Guessers only see a clue once it's been confirmed.
+	if pc := room.PendingClue; pc != nil && spymaster {
+		s.Spymasters.Pending = &protocol.StatePendingClue{
+			Board:     pc.Board,
+			Word:      pc.Word,
+			Count:     pc.Count,
+			Confirmed: append([]game.PlayerID(nil), pc.Confirmed...),
+		}
+	}
+
+	if room.PenaltyLimit > 0 || room.Forfeit {
+		s.Penalties = &protocol.StatePenalties{
+			Limit:   room.PenaltyLimit,
+			Counts:  append([]int(nil), room.Penalties...),
+			Forfeit: room.Forfeit,
+		}
+	}
+
+	if room.HintsEnabled() {
+		s.Hints = &protocol.StateHints{
+			Budget: make([]int, len(room.Teams)),
+			Left:   append([]int(nil), room.Hints...),
+			Used:   append([]int(nil), room.HintsUsed...),
+		}
+		copy(s.Hints.Budget, room.HintBudget)
+	}
+
+	if room.Winner != nil {
+		s.GameOver = r.createGameOver()
 	}
 
 	if r.turnDeadline != nil {
@@ -532,9 +1913,13 @@ func (r *Room) createRoomState(spymaster bool) *protocol.RoomState {
 		for _, id := range members {
 			p := room.Players[id]
 			s.Teams[team] = append(s.Teams[team], &protocol.StatePlayer{
-				PlayerID:  id,
-				Nickname:  p.Nickname,
-				Spymaster: p.Spymaster,
+				PlayerID:     id,
+				Nickname:     p.Nickname,
+				Spymaster:    p.Spymaster,
+				Color:        p.Color,
+				Host:         id == room.Host,
+				Impersonator: p.Impersonator,
+				Disconnected: p.Disconnected,
 			})
 		}
 
@@ -543,15 +1928,94 @@ func (r *Room) createRoomState(spymaster bool) *protocol.RoomState {
 		}
 	}
 
-	for row := range s.Board {
-		tiles := make([]*protocol.StateTile, room.Board.Cols)
+	if room.Tracker {
+		s.Tracker = &protocol.StateTracker{
+			Scores: append([]int(nil), room.Scores...),
+			Clues:  make([]*protocol.StateLoggedClue, len(room.ClueLog)),
+		}
+		for i, c := range room.ClueLog {
+			s.Tracker.Clues[i] = protocolLoggedClue(c)
+		}
+		return s
+	}
+
+	if len(room.ClueLog) != 0 {
+		s.ClueLog = make([]*protocol.StateLoggedClue, len(room.ClueLog))
+		for i, c := range room.ClueLog {
+			s.ClueLog[i] = protocolLoggedClue(c)
+		}
+	}
+
+	s.Board = r.createBoardState(room.Board, spymaster)
+	// Word counts change in place as cards are revealed, and states are
+	// encoded off the room's goroutine, so they're copied like everything
+	// else.
+	s.WordsLeft = append([]int(nil), room.Board.WordCounts...)
+	s.TurnBoard = room.TurnBoard
+	s.BoardOptions = &protocol.StateBoardOptions{
+		Count: room.NumBoards,
+		Rows:  room.Rows,
+		Cols:  room.Cols,
+		Bombs: room.Bombs,
+	}
+	s.BackfillWords = room.BackfillWords
+	if b := room.Backfill; b != nil {
+		s.Backfill = &protocol.StateBackfill{Pack: b.Pack, Language: b.Language, Count: b.Count}
+	}
+
+	if len(room.Boards) > 1 {
+		s.Boards = make([]*protocol.StateBoard, len(room.Boards))
+		for i, b := range room.Boards {
+			board := s.Board
+			if i != 0 {
+				board = r.createBoardState(b, spymaster)
+			}
+			s.Boards[i] = &protocol.StateBoard{
+				Board:     board,
+				WordsLeft: append([]int(nil), b.WordCounts...),
+			}
+		}
+	}
+
+	s.Lists = make([]*protocol.StateWordList, len(room.WordLists))
+	for i, wl := range room.WordLists {
+		s.Lists[i] = &protocol.StateWordList{
+			Name:     wl.Name,
+			Language: wl.Language,
+			Count:    wl.List.Len(),
+			Custom:   wl.Custom,
+			Enabled:  wl.Enabled,
+		}
+	}
+
+	return s
+}
+
+func protocolLoggedClue(c *game.LoggedClue) *protocol.StateLoggedClue {
+	return &protocol.StateLoggedClue{Team: c.Team, Board: c.Board, Word: c.Word, Count: c.Count}
+}
+
+func (r *Room) createBoardState(b *game.Board, spymaster bool) [][]*protocol.StateTile {
+	room := r.room
+	board := make([][]*protocol.StateTile, b.Rows)
+
+	for row := range board {
+		tiles := make([]*protocol.StateTile, b.Cols)
 		for col := range tiles {
-			tile := room.Board.Get(row, col)
+			tile := b.Get(row, col)
 			sTile := &protocol.StateTile{
 				Word:     tile.Word,
 				Revealed: tile.Revealed,
 			}
 
+			if tile.Fit.Class != "" {
+				sTile.Fit = &protocol.StateFit{
+					Class:  string(tile.Fit.Class),
+					Length: tile.Fit.Length,
+					Size:   tile.Fit.Size,
+				}
+			}
+
 			if spymaster || tile.Revealed || room.Winner != nil {
 				view := &protocol.StateView{
 					Team:    tile.Team,
@@ -570,22 +2034,13 @@ func (r *Room) createRoomState(spymaster bool) *protocol.RoomState {
 			tiles[col] = sTile
 		}
 
-		s.Board[row] = tiles
-	}
-
-	for i, wl := range room.WordLists {
-		s.Lists[i] = &protocol.StateWordList{
-			Name:    wl.Name,
-			Count:   wl.List.Len(),
-			Custom:  wl.Custom,
-			Enabled: wl.Enabled,
-		}
+		board[row] = tiles
 	}
 
-	return s
+	return board
 }
 
-// Must be called with r.mu locked.
+// Must be called on the room's goroutine.
 func (r *Room) changeTurnMode(timed bool) {
 	if r.timed == timed {
 		return
@@ -602,7 +2057,7 @@ func (r *Room) changeTurnMode(timed bool) {
 	r.room.Version++
 }
 
-// Must be called with r.mu locked.
+// Must be called on the room's goroutine.
 func (r *Room) changeTurnTime(seconds int) {
 	if seconds <= 0 || r.turnSeconds == seconds {
 		return
@@ -617,10 +2072,8 @@ func (r *Room) changeTurnTime(seconds int) {
 	r.room.Version++
 }
 
+// Must be called on the room's goroutine.
 func (r *Room) timerEndTurn() {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
 	stopped := r.stopTimer()
 	if !stopped {
 		// Room was pruned.
@@ -634,12 +2087,53 @@ func (r *Room) timerEndTurn() {
 		return
 	}
 
-	r.room.ForceEndTurn()
-	r.startTimer()
+	snap := r.snapshotTurn()
+	r.room.ExpireTurn()
+	if r.room.Winner == nil {
+		r.startTimer()
+	}
+	r.recordGame(snap)
 	r.sendAll()
+	r.sendNotifications(r.notifications(snap))
+	r.recordPacing(snap)
+	r.promptFeedback(snap)
+}
+
+// createGameOver summarizes the room's finished game. Every player gets the
+// whole key, as everyone's board shows it once the game is won.
+//
+// Must be called on the room's goroutine.
+func (r *Room) createGameOver() *protocol.StateGameOver {
+	room := r.room
+	over := &protocol.StateGameOver{
+		Winner:  *room.Winner,
+		Reason:  string(r.outcome()),
+		Seconds: int64(r.gameEnd.Sub(r.gameStart) / time.Second),
+	}
+
+	if room.Tracker {
+		return over
+	}
+
+	over.WordsLeft = make([][]int, len(room.Boards))
+	over.Key = make([][][]*protocol.StateView, len(room.Boards))
+	for i, b := range room.Boards {
+		over.WordsLeft[i] = append([]int(nil), b.WordCounts...)
+
+		key := make([][]*protocol.StateView, b.Rows)
+		for row := range key {
+			key[row] = make([]*protocol.StateView, b.Cols)
+			for col := range key[row] {
+				tile := b.Get(row, col)
+				key[row][col] = &protocol.StateView{Team: tile.Team, Neutral: tile.Neutral, Bomb: tile.Bomb}
+			}
+		}
+		over.Key[i] = key
+	}
+	return over
 }
 
-// Must be called with r.mu locked.
+// Must be called on the room's goroutine.
 func (r *Room) stopTimer() (stopped bool) {
 	if r.turnTimer != nil {
 		r.turnTimer.Stop()
@@ -650,23 +2144,41 @@ func (r *Room) stopTimer() (stopped bool) {
 	return stopped
 }
 
-// Must be called with r.mu locked.
+// startTimer starts the current turn's timer over. Finished games have no
+// turns to time, so their timer is stopped instead, until a new game starts.
+//
+// Must be called on the room's goroutine.
 func (r *Room) startTimer() {
 	if !r.timed {
 		panic("startTimer called on non-timed room")
 	}
 
+	if r.room.Winner != nil {
+		r.stopTimer()
+		return
+	}
+
 	if r.turnTimer != nil {
 		r.turnTimer.Stop()
 	}
 
 	dur := time.Second * time.Duration(r.turnSeconds)
-	deadline := time.Now().Add(dur)
+	deadline := r.clock.Now().Add(dur)
 	r.turnDeadline = &deadline
-	r.turnTimer = time.AfterFunc(dur, r.timerEndTurn)
+	r.turnTimer = r.after(dur, r.timerEndTurn)
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) changeFeedback(enabled bool) {
+	if r.feedback.enabled == enabled {
+		return
+	}
+
+	r.feedback.enabled = enabled
+	r.room.Version++
 }
 
-// Must be called with r.mu locked.
+// Must be called on the room's goroutine.
 func (r *Room) changeHideBomb(hideBomb bool) {
 	if r.hideBomb == hideBomb {
 		return
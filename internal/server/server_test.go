@@ -0,0 +1,619 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/fit"
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/packs"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+type testClient struct {
+	notes []protocol.ServerNote
+}
+
+func (c *testClient) errors() []*protocol.Error {
+	var errs []*protocol.Error
+	for _, n := range c.notes {
+		if n.Method == "error" {
+			errs = append(errs, n.Params.(*protocol.Error))
+		}
+	}
+	return errs
+}
+
+func newTestRoom(t testing.TB) *Room {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	r := newRoom(ctx, "test", nil, "test", &counters{})
+	r.room = game.NewRoom(rand.New(rand.NewSource(1))) //nolint:gosec
+	r.room.NewGame()
+	return r
+}
+
+func (r *Room) addTestClient(t *testing.T, id game.PlayerID, team game.Team, spymaster bool) *testClient {
+	t.Helper()
+
+	c := &testClient{}
+
+	r.hold(func() {
+		r.players[id] = func(_ priority, note protocol.ServerNote) {
+			c.notes = append(c.notes, note)
+		}
+		r.room.AddPlayer(id, string(id))
+		r.room.ChangeTeam(id, team)
+		assert.NilError(t, r.room.ChangeRole(id, spymaster))
+	})
+	return c
+}
+
+func (r *Room) testNote(t *testing.T, id game.PlayerID, method protocol.ClientMethod, params interface{}) {
+	t.Helper()
+
+	raw, err := json.Marshal(params)
+	assert.NilError(t, err)
+
+	r.hold(func() {
+		err = r.handleNote(context.Background(), id, &protocol.ClientNote{
+			Method:  method,
+			Version: r.room.Version,
+			Params:  raw,
+		})
+	})
+	assert.NilError(t, err)
+}
+
+// setTurn makes it team's turn.
+func (r *Room) setTurn(team game.Team) {
+	r.hold(func() {
+		r.room.Turn = team
+	})
+}
+
+// setClock replaces the room's clock.
+func (r *Room) setClock(c clock) {
+	r.hold(func() {
+		r.clock = c
+	})
+}
+
+// turn returns the team whose turn it is.
+func (r *Room) turn() game.Team {
+	var team game.Team
+	r.hold(func() {
+		team = r.room.Turn
+	})
+	return team
+}
+
+// winner returns the team which won the game, or nil.
+func (r *Room) winner() *game.Team {
+	var winner *game.Team
+	r.hold(func() {
+		if r.room.Winner != nil {
+			team := *r.room.Winner
+			winner = &team
+		}
+	})
+	return winner
+}
+
+// games returns how many games the room has started.
+func (r *Room) games() int {
+	var games int
+	r.hold(func() {
+		games = r.room.Games
+	})
+	return games
+}
+
+// host returns the room's host.
+func (r *Room) host() game.PlayerID {
+	var host game.PlayerID
+	r.hold(func() {
+		host = r.room.Host
+	})
+	return host
+}
+
+func TestClueRejectionOnlyToSpymaster(t *testing.T) {
+	r := newTestRoom(t)
+	spy := r.addTestClient(t, "spy", 0, true)
+	guesser := r.addTestClient(t, "guesser", 0, false)
+	other := r.addTestClient(t, "other", 1, false)
+	r.setTurn(0)
+
+	r.testNote(t, "spy", protocol.ChangeBoundCluesMethod, &protocol.ChangeBoundCluesParams{BoundClues: true})
+
+	var max int
+	r.hold(func() {
+		max = r.room.Board.WordCounts[0]
+	})
+	r.testNote(t, "spy", protocol.GiveClueMethod, &protocol.GiveClueParams{Word: "ANIMAL", Count: max + 1})
+
+	errs := spy.errors()
+	assert.Equal(t, len(errs), 1)
+	assert.Equal(t, errs[0].Code, "clueTooHigh")
+	assert.Equal(t, *errs[0].Limit, max)
+
+	assert.Equal(t, len(guesser.errors()), 0)
+	assert.Equal(t, len(other.errors()), 0)
+	r.hold(func() {
+		assert.Assert(t, r.room.Clue == nil)
+	})
+}
+
+func TestClueLogState(t *testing.T) {
+	r := newTestRoom(t)
+	spy0 := r.addTestClient(t, "spy0", 0, true)
+	r.addTestClient(t, "spy1", 1, true)
+	guesser := r.addTestClient(t, "guesser", 0, false)
+	r.setTurn(0)
+
+	// Words still on the board can't be clues.
+	var word string
+	r.hold(func() {
+		word = r.room.Board.Get(0, 0).Word
+	})
+	r.testNote(t, "spy0", protocol.GiveClueMethod, &protocol.GiveClueParams{Word: strings.ToLower(word), Count: 1})
+	errs := spy0.errors()
+	assert.Equal(t, len(errs), 1)
+	assert.Equal(t, errs[0].Code, "clueOnBoard")
+
+	r.testNote(t, "spy0", protocol.GiveClueMethod, &protocol.GiveClueParams{Word: "ANIMAL", Count: 2})
+	r.testNote(t, "guesser", protocol.EndTurnMethod, &protocol.EndTurnParams{})
+	r.testNote(t, "spy1", protocol.GiveClueMethod, &protocol.GiveClueParams{Word: "OCEAN", Count: 1})
+
+	state := guesser.lastState().RoomState
+	assert.DeepEqual(t, state.Clue, &protocol.StateClue{Word: "OCEAN", Count: 1})
+	assert.DeepEqual(t, state.ClueLog, []*protocol.StateLoggedClue{
+		{Team: 0, Word: "ANIMAL", Count: 2},
+		{Team: 1, Word: "OCEAN", Count: 1},
+	})
+
+	r.testNote(t, "spy0", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	assert.Assert(t, guesser.lastState().RoomState.ClueLog == nil)
+}
+
+func (c *testClient) notifications() []*protocol.Notification {
+	var notes []*protocol.Notification
+	for _, n := range c.notes {
+		if n.Method == "notification" {
+			notes = append(notes, n.Params.(*protocol.Notification))
+		}
+	}
+	return notes
+}
+
+func TestNotificationsYourTurn(t *testing.T) {
+	r := newTestRoom(t)
+	c0 := r.addTestClient(t, "g0", 0, false)
+	c1 := r.addTestClient(t, "g1", 1, false)
+	r.setTurn(0)
+
+	r.testNote(t, "g0", protocol.EndTurnMethod, &protocol.EndTurnParams{})
+
+	assert.Equal(t, len(c0.notifications()), 0)
+	notes := c1.notifications()
+	assert.Equal(t, len(notes), 1)
+	assert.Equal(t, notes[0].Event, protocol.NotifyYourTurn)
+	assert.Equal(t, notes[0].Team, game.Team(1))
+}
+
+func TestNotificationsMasked(t *testing.T) {
+	r := newTestRoom(t)
+	r.addTestClient(t, "g0", 0, false)
+	r.hold(func() {
+		r.room.Turn = 0
+		r.room.Board.WordCounts[1] = 2
+	})
+
+	r.hold(func() {
+		snap := r.snapshotTurn()
+		r.room.ForceEndTurn()
+		r.room.Board.WordCounts[1] = 1
+
+		assert.Equal(t, len(r.notifications(snap)), 2)
+
+		r.changeNotifications(false, []protocol.NotificationEvent{protocol.NotifyYourTurn, "bogus"})
+		notes := r.notifications(snap)
+		assert.Equal(t, len(notes), 1)
+		assert.Equal(t, notes[0].event, protocol.NotifyOneCardLeft)
+
+		state := r.createRoomState(false)
+		assert.DeepEqual(t, state.Notifications.Disabled, []protocol.NotificationEvent{protocol.NotifyYourTurn})
+
+		r.changeNotifications(true, nil)
+		assert.Equal(t, len(r.notifications(snap)), 0)
+		assert.Assert(t, r.createRoomState(false).Notifications.Off)
+	})
+}
+
+func newTestServer(t testing.TB, reg *packs.Registry) *Server {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	s := NewServer(Options{Packs: reg})
+	go s.Run(ctx) //nolint:errcheck
+	return s
+}
+
+func TestCreateRoomDuringPackReload(t *testing.T) {
+	dir := t.TempDir()
+	writeTestPack(t, dir, "animals")
+
+	reg, err := packs.NewRegistry(dir)
+	assert.NilError(t, err)
+
+	s := newTestServer(t, reg)
+
+	before, err := s.CreateRoom(context.Background(), "before", "pass")
+	assert.NilError(t, err)
+
+	assert.NilError(t, os.Remove(filepath.Join(dir, "animals.txt")))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := s.CreateRoom(context.Background(), "room"+strconv.Itoa(i), "pass")
+			assert.Check(t, err)
+		}(i)
+	}
+	assert.NilError(t, reg.Reload())
+	wg.Wait()
+
+	// The room which had the pack keeps it.
+	builtin := len((*packs.Registry)(nil).Packs())
+	before.hold(func() {
+		assert.Equal(t, len(before.room.WordLists), builtin+1)
+		assert.Equal(t, before.room.WordLists[builtin].Name, "animals")
+		before.room.ChangePack(builtin, true)
+		before.room.NewGame()
+
+		after, err := s.CreateRoom(context.Background(), "after", "pass")
+		assert.NilError(t, err)
+		assert.Equal(t, len(after.room.WordLists), builtin)
+	})
+}
+
+// newHostedRooms creates n rooms on the server, each with a host.
+func newHostedRooms(tb testing.TB, s *Server, n int) []*Room {
+	tb.Helper()
+
+	rooms := make([]*Room, n)
+	for i := range rooms {
+		r, err := s.CreateRoom(context.Background(), "room"+strconv.Itoa(i), "pass")
+		if err != nil {
+			tb.Fatal(err)
+		}
+
+		r.hold(func() {
+			r.players["host"] = func(priority, protocol.ServerNote) {}
+			r.room.AddPlayer("host", "host")
+		})
+
+		rooms[i] = r
+	}
+	return rooms
+}
+
+// startGames has each room's host force a new game, all at once, returning
+// how long each room took to deal its board.
+func startGames(tb testing.TB, rooms []*Room) []time.Duration {
+	raw, err := json.Marshal(&protocol.NewGameParams{Force: true})
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	took := make([]time.Duration, len(rooms))
+	start := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i, r := range rooms {
+		wg.Add(1)
+		go func(i int, r *Room) {
+			defer wg.Done()
+			<-start
+
+			var err error
+			begin := time.Now()
+			r.do(func() {
+				err = r.handleNote(context.Background(), "host", &protocol.ClientNote{
+					Method:  protocol.NewGameMethod,
+					Version: r.room.Version,
+					Params:  raw,
+				})
+			})
+			took[i] = time.Since(begin)
+
+			if err != nil {
+				tb.Error(err)
+			}
+		}(i, r)
+	}
+
+	close(start)
+	wg.Wait()
+	return took
+}
+
+func TestNewGamesDuringPackReload(t *testing.T) {
+	dir := t.TempDir()
+	writeTestPack(t, dir, "animals")
+
+	reg, err := packs.NewRegistry(dir)
+	assert.NilError(t, err)
+
+	s := newTestServer(t, reg)
+	rooms := newHostedRooms(t, s, 50)
+
+	// Deal only from the loaded pack, so every board reads the registry's.
+	builtin := len((*packs.Registry)(nil).Packs())
+	for _, r := range rooms {
+		r.hold(func() {
+			r.room.ChangePack(builtin, true)
+			for i := 0; i < builtin; i++ {
+				r.room.ChangePack(i, false)
+			}
+		})
+	}
+
+	games := rooms[0].room.Games
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			writeTestPack(t, dir, "plants"+strconv.Itoa(i))
+			assert.Check(t, reg.Reload())
+		}
+	}()
+	startGames(t, rooms)
+	<-done
+
+	for _, r := range rooms {
+		r.hold(func() {
+			assert.Equal(t, r.room.Games, games+1)
+			b := r.room.Board
+			for row := 0; row < b.Rows; row++ {
+				for col := 0; col < b.Cols; col++ {
+					word := b.Get(row, col).Word
+					assert.Assert(t, strings.HasPrefix(word, "ANIMALS"), word)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkConcurrentNewGames starts a game in each of 200 rooms at once.
+// Rooms share the registry's packs, but nothing else, so the slowest room
+// should take about as long as the typical one; were boards dealt one at a
+// time, the slowest would wait on all the others.
+func BenchmarkConcurrentNewGames(b *testing.B) {
+	const n = 200
+
+	s := newTestServer(b, nil)
+	rooms := newHostedRooms(b, s, n)
+
+	var typical, slowest time.Duration
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		took := startGames(b, rooms)
+		sort.Slice(took, func(i, j int) bool { return took[i] < took[j] })
+		typical += took[n/2]
+		slowest += took[n-1]
+	}
+
+	b.ReportMetric(float64(typical.Nanoseconds())/float64(b.N), "p50-ns/game")
+	b.ReportMetric(float64(slowest.Nanoseconds())/float64(b.N), "max-ns/game")
+}
+
+func TestCreateRoomCustomWords(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t, nil)
+
+	wds := make([]string, 0, 30)
+	for i := 0; i < 25; i++ {
+		wds = append(wds, fmt.Sprintf(" word%d ", i))
+	}
+	wds = append(wds, "WORD0", "word1")
+
+	room, err := s.CreateRoomWait(ctx, "custom", "pass", RoomOptions{Words: wds}, 0)
+	assert.NilError(t, err)
+
+	room.hold(func() {
+		lists := room.room.WordLists
+		custom := lists[len(lists)-1]
+		assert.Equal(t, custom.Name, customPackName)
+		assert.Assert(t, custom.Custom)
+		assert.Equal(t, custom.List.Len(), 25)
+		for _, list := range lists[:len(lists)-1] {
+			assert.Assert(t, !list.Enabled, "%s is enabled", list.Name)
+		}
+
+		// Only the custom words are on the board.
+		board := room.room.Board
+		for row := 0; row < board.Rows; row++ {
+			for col := 0; col < board.Cols; col++ {
+				word := board.Get(row, col).Word
+				assert.Assert(t, strings.HasPrefix(word, "WORD"), word)
+			}
+		}
+
+		// Built-in packs may be picked afterwards, and the custom one dropped.
+		room.room.ChangePack(0, true)
+		room.room.ChangePack(len(lists)-1, false)
+		assert.Assert(t, lists[0].Enabled)
+		assert.Assert(t, !custom.Enabled)
+	})
+}
+
+func TestCreateRoomCustomWordsInvalid(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t, nil)
+
+	_, err := s.CreateRoomWait(ctx, "custom", "pass", RoomOptions{Words: []string{"one", "ONE", "two"}}, 0)
+	var gErr *game.Error
+	assert.Assert(t, errors.As(err, &gErr))
+	assert.Equal(t, gErr.Code, "tooFewWords")
+
+	// Nothing was created, so the name is still free.
+	assert.Assert(t, s.FindRoom("custom") == nil)
+	_, err = s.CreateRoom(ctx, "custom", "pass")
+	assert.NilError(t, err)
+	assert.Equal(t, s.counters.rooms.Load(), int64(1))
+}
+
+func writeTestPack(t *testing.T, dir, name string) {
+	t.Helper()
+
+	var b strings.Builder
+	for i := 0; i < packs.MinWords; i++ {
+		fmt.Fprintf(&b, "%s%d\n", name, i)
+	}
+
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, name+".txt"), []byte(b.String()), 0o600))
+}
+
+func TestCreateRoomLanguage(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t, nil)
+
+	room, err := s.CreateRoomWait(ctx, "german", "pass", RoomOptions{Language: "de"}, 0)
+	assert.NilError(t, err)
+
+	room.hold(func() {
+		for _, list := range room.room.WordLists {
+			assert.Equal(t, list.Enabled, list.Language == "de", list.Name)
+		}
+
+		lists := room.createRoomState(false).Lists
+		assert.Equal(t, lists[0].Language, "en")
+		assert.Equal(t, lists[3].Language, "de")
+	})
+
+	_, err = s.CreateRoomWait(ctx, "klingon", "pass", RoomOptions{Language: "tlh"}, 0)
+	assert.Equal(t, err, ErrUnknownLanguage)
+	assert.Assert(t, s.FindRoom("klingon") == nil)
+
+	// Custom words win over the language.
+	wds := packWords("word", 25)
+	room, err = s.CreateRoomWait(ctx, "custom", "pass", RoomOptions{Words: wds, Language: "tlh"}, 0)
+	assert.NilError(t, err)
+	room.hold(func() {
+		assert.Assert(t, room.room.WordLists[len(room.room.WordLists)-1].Enabled)
+	})
+}
+
+func TestBackfillWords(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t, nil)
+
+	room, err := s.CreateRoomWait(ctx, "german", "pass", RoomOptions{Language: "de"}, 0)
+	assert.NilError(t, err)
+
+	room.hold(func() {
+		assert.NilError(t, room.room.AddPack("kurz", packWords("wort", 20)))
+		room.room.ChangePack(len(room.room.WordLists)-1, true)
+		room.room.ChangePack(3, false)
+		assert.ErrorContains(t, room.room.CheckWords(), "The enabled packs have 20 words")
+
+		_, err = room.updateOptions("", &protocol.UpdateOptionsParams{BackfillWords: boolPtr(true)})
+		assert.NilError(t, err)
+		assert.NilError(t, room.room.CheckWords())
+
+		room.room.NewGame()
+		state := room.createRoomState(false)
+		assert.Assert(t, state.BackfillWords)
+		assert.DeepEqual(t, state.Backfill, &protocol.StateBackfill{Pack: "Deutsch", Language: "de", Count: 5})
+	})
+}
+
+func TestCreateRoomBoardSize(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t, nil)
+
+	room, err := s.CreateRoomWait(ctx, "big", "pass", RoomOptions{Rows: 6, Cols: 6}, 0)
+	assert.NilError(t, err)
+	room.hold(func() {
+		assert.Equal(t, room.room.Board.Rows, 6)
+		assert.Equal(t, room.room.Board.Cols, 6)
+		assert.DeepEqual(t, room.createRoomState(false).BoardOptions, &protocol.StateBoardOptions{Count: 1, Rows: 6, Cols: 6, Bombs: 1})
+	})
+
+	// A side left out keeps the default.
+	room, err = s.CreateRoomWait(ctx, "wide", "pass", RoomOptions{Rows: 4}, 0)
+	assert.NilError(t, err)
+	room.hold(func() {
+		assert.Equal(t, room.room.Board.Rows, 4)
+		assert.Equal(t, room.room.Board.Cols, 5)
+	})
+
+	_, err = s.CreateRoomWait(ctx, "huge", "pass", RoomOptions{Rows: 9, Cols: 9}, 0)
+	var gErr *game.Error
+	assert.Assert(t, errors.As(err, &gErr))
+	assert.Equal(t, gErr.Code, "invalidBoards")
+	assert.Assert(t, s.FindRoom("huge") == nil)
+
+	// The custom words must fill the board.
+	_, err = s.CreateRoomWait(ctx, "custom", "pass", RoomOptions{Words: packWords("word", 25), Rows: 6, Cols: 6}, 0)
+	assert.Assert(t, errors.As(err, &gErr))
+	assert.ErrorContains(t, err, "have 25 words, but the boards need 36")
+	assert.Assert(t, s.FindRoom("custom") == nil)
+}
+
+func TestCreateRoomFit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := NewServer(Options{FitThresholds: fit.Thresholds{Emoji: []int{1}}})
+	go s.Run(ctx) //nolint:errcheck
+
+	// Pairs of fruit, like "🍇🍈", which are two clusters.
+	words := make([]string, 25)
+	for i := range words {
+		words[i] = string([]rune{0x1F347 + rune(i), 0x1F347 + rune(i+1)})
+	}
+
+	room, err := s.CreateRoomWait(ctx, "fruit", "pass", RoomOptions{Words: words}, 0)
+	assert.NilError(t, err)
+	room.hold(func() {
+		for _, row := range room.createRoomState(false).Board {
+			for _, tile := range row {
+				assert.DeepEqual(t, tile.Fit, &protocol.StateFit{Class: "emoji", Length: 2, Size: 1})
+			}
+		}
+	})
+}
+
+func TestServerPacks(t *testing.T) {
+	s := newTestServer(t, nil)
+
+	builtin := s.Packs()
+	assert.Equal(t, len(builtin), len((*packs.Registry)(nil).Packs()))
+	assert.DeepEqual(t, builtin[0], &protocol.BuiltinPack{Name: "Base", Language: "en", Count: 400})
+	assert.DeepEqual(t, builtin[3], &protocol.BuiltinPack{Name: "Deutsch", Language: "de", Count: 100})
+}
@@ -0,0 +1,259 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"github.com/zikaeroh/ctxjoin"
+	"github.com/zikaeroh/ctxlog"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"nhooyr.io/websocket"
+)
+
+// Spectators watch a room without being players, for groups which stream
+// their games. Anyone who can connect may spectate, without a token. They're
+// sent exactly what mirrors are: the guessers' view, never the key, delayed by
+// the room's mirror delay so that spectating can't get around it. Anything
+// but timeSync which a spectator sends is answered with an error.
+//
+// The number of spectators is shown in the room's state. It's updated at most
+// once every spectatorInterval, without changing the room's version, so that
+// viewers coming and going neither flood players with states nor make their
+// commands stale.
+//
+// A room sends every state to at most defaultMaxSpectators, unless configured
+// otherwise. Past that, or past the join rate, spectators are turned away,
+// unless the room has an overflow tier. Spectators in it are admitted
+// regardless, but are only sent the latest state once every overflowInterval,
+// so that a crowd costs the room one send each per interval, however often the
+// state changes and however fast the crowd arrives. They stay in the tier until
+// they reconnect.
+const (
+	defaultMaxSpectators = 100
+	spectatorInterval    = 5 * time.Second
+	overflowInterval     = 10 * time.Second
+)
+
+// spectatorLimits configures a room's spectators. The zero value uses the
+// default, without an overflow tier.
+type spectatorLimits struct {
+	max      int // If zero, defaultMaxSpectators.
+	overflow bool
+}
+
+func (l spectatorLimits) limit() int {
+	if l.max == 0 {
+		return defaultMaxSpectators
+	}
+	return l.max
+}
+
+var errSpectating = &game.Error{
+	Code:    "spectating",
+	Message: "Spectators can only watch.",
+}
+
+// handleSpectatorConn serves a spectator; see HandleConn.
+func (r *Room) handleSpectatorConn(ctx context.Context, opts ConnOptions, c *websocket.Conn) {
+	spectatorID, _ := r.genPlayerID.Next()
+
+	ctx, cancel := ctxjoin.AddCancel(ctx, r.ctx)
+	defer cancel()
+
+	ctx = ctxlog.With(r.withLogger(ctx), zap.String("spectatorID", spectatorID), zap.String("clientVersion", opts.ClientVersion))
+
+	w := newConnWriter(c)
+	w.now = r.clock.Now
+
+	var refuse func()
+	var m *mirror
+	overflow := false
+	r.do(func() {
+		if r.closing() {
+			closed := r.closeReason
+			refuse = func() { r.refuseClosed(ctx, w, closed) }
+			return
+		}
+
+		if target := r.mergedInto; target != "" {
+			refuse = func() { r.redirectConn(ctx, w, target) }
+			return
+		}
+
+		if max := r.spectate.limit(); len(r.spectators)-r.overflow >= max {
+			if !r.spectate.overflow {
+				refuse = func() {
+					r.rejectConn(ctx, w, closeRoomBusy, &game.Error{
+						Code:    "spectatorLimit",
+						Message: fmt.Sprintf("Rooms may have at most %d spectators.", max),
+						Limit:   &max,
+					})
+				}
+				return
+			}
+			overflow = true
+		}
+
+		switch {
+		case overflow:
+			// Already past the cap, so the join rate makes no difference.
+		case r.spectate.overflow:
+			_, ok := r.joins.admit(r.clock.Now())
+			overflow = !ok
+		default:
+			if err := r.admitJoin(); err != nil {
+				refuse = func() { r.rejectConn(ctx, w, closeRoomBusy, err) }
+				return
+			}
+		}
+
+		m = &mirror{
+			w:        w,
+			send:     w.send,
+			takeover: opts.ContextTakeover,
+			overflow: overflow,
+			info:     connInfo{clientVersion: opts.ClientVersion, connected: r.clock.Now()},
+		}
+		r.spectators[spectatorID] = m
+		r.spectatorCount.Inc()
+		r.counters.spectators.Inc()
+		r.counters.statsDirty.Store(true)
+		r.spectatorsChanged()
+		if overflow {
+			// Counted as sent, so that it isn't idle before its first state.
+			m.sent = r.clock.Now()
+			r.overflow++
+			r.overflowChanged()
+			metricSpectatorsOverflowed.Inc()
+		} else {
+			r.sendMirror(m)
+		}
+	})
+	if refuse != nil {
+		refuse()
+		return
+	}
+
+	ctxlog.Info(ctx, "spectator connected", zap.Bool("overflow", overflow))
+
+	var reason disconnectReason
+
+	// Spectators aren't in the game, so leaving only changes the count.
+	defer func() {
+		r.do(func() {
+			delete(r.spectators, spectatorID)
+			if m.overflow {
+				r.overflow--
+			}
+			r.spectatorsChanged()
+		})
+
+		r.spectatorCount.Dec()
+		r.counters.spectators.Dec()
+		r.counters.statsDirty.Store(true)
+		metricDisconnects.WithLabelValues(string(reason)).Inc()
+		ctxlog.Info(ctx, "spectator disconnected", zap.String("reason", string(reason)))
+	}()
+
+	connCtx := ctx
+	g, ctx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		return w.run(ctx)
+	})
+
+	g.Go(func() error {
+		return r.keepalive(ctx, w)
+	})
+
+	g.Go(func() error {
+		for {
+			var note protocol.ClientNote
+
+			if err := readClientNote(ctx, c, w, &note); err != nil {
+				return err
+			}
+
+			if r.answerTimeSync(w, &note) {
+				continue
+			}
+
+			ctxlog.Debug(ctx, "rejected note from spectator", zap.String("method", string(note.Method)))
+			w.send(priorityTargeted, protocol.NewErrorNote(protocolError(errSpectating)))
+		}
+	})
+
+	err := g.Wait()
+	reason = classifyDisconnect(connCtx, w, err)
+	ctxlog.Debug(ctx, "connection ended", zap.String("reason", string(reason)), zap.Error(err))
+}
+
+// spectatorsChanged schedules an update of the spectator count, unless one is
+// already coming.
+//
+// Must be called on the room's goroutine.
+func (r *Room) spectatorsChanged() {
+	if r.spectatorTimer == nil && !r.closing() {
+		r.spectatorTimer = r.after(spectatorInterval, r.timerSendSpectators)
+	}
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) timerSendSpectators() {
+	if r.spectatorTimer == nil {
+		// Room was pruned.
+		return
+	}
+	r.spectatorTimer = nil
+
+	if n := len(r.spectators); n != r.spectatorsShown {
+		r.spectatorsShown = n
+		r.state = nil
+		r.sendAll()
+	}
+}
+
+// overflowChanged schedules a send to the overflow tier, unless one is
+// already coming or the tier is empty.
+//
+// Must be called on the room's goroutine.
+func (r *Room) overflowChanged() {
+	if r.overflowTimer == nil && r.overflow != 0 && !r.closing() {
+		r.overflowTimer = r.after(overflowInterval, r.timerSendOverflow)
+	}
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) timerSendOverflow() {
+	if r.overflowTimer == nil {
+		// Room was pruned.
+		return
+	}
+	r.overflowTimer = nil
+
+	note := r.currentMirrorNote()
+	now := r.clock.Now()
+	for _, m := range r.spectators {
+		if m.overflow {
+			m.sent = now
+			m.send(priorityBroadcast, *note)
+		}
+	}
+	r.overflowChanged()
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) stopSpectatorTimer() {
+	if r.spectatorTimer != nil {
+		r.spectatorTimer.Stop()
+		r.spectatorTimer = nil
+	}
+	if r.overflowTimer != nil {
+		r.overflowTimer.Stop()
+		r.overflowTimer = nil
+	}
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func TestSpectator(t *testing.T) {
+	s := newTestServer(t, nil)
+	r, err := s.CreateRoom(context.Background(), "room", "pass")
+	assert.NilError(t, err)
+	c := newFakeClock()
+	r.setClock(c)
+
+	player := dialTestRoom(t, r, ConnOptions{Nickname: "player"})
+	state := readState(t, player)
+	writeNote(t, player, protocol.ChangeRoleMethod, state.RoomState.Version, &protocol.ChangeRoleParams{Spymaster: true})
+	state = readState(t, player)
+
+	spectator := dialTestRoom(t, r, ConnOptions{Spectate: true})
+	seen := readState(t, spectator)
+	assert.Equal(t, seen.PlayerID, "")
+	assert.Equal(t, seen.RoomState.Version, state.RoomState.Version)
+	assert.Equal(t, seen.RoomState.KeyChecksum, "")
+	for _, row := range seen.RoomState.Board {
+		for _, tile := range row {
+			assert.Assert(t, tile.Revealed || tile.View == nil, "spectator sees the key")
+		}
+	}
+
+	// Spectators aren't players.
+	p, _ := findStatePlayer(seen, "")
+	assert.Assert(t, p == nil)
+	r.hold(func() {
+		assert.Equal(t, len(r.room.Players), 1)
+	})
+
+	// Nor can they act like one.
+	writeNote(t, spectator, protocol.EndTurnMethod, seen.RoomState.Version, &protocol.EndTurnParams{})
+	var e protocol.Error
+	readNote(t, spectator, "error", &e)
+	assert.Equal(t, e.Code, "spectating")
+
+	// They're counted, without changing the room's version.
+	c.Advance(spectatorInterval)
+	counted := readState(t, player)
+	assert.Equal(t, counted.RoomState.Spectators, 1)
+	assert.Equal(t, counted.RoomState.Version, state.RoomState.Version)
+	assert.Equal(t, readState(t, spectator).RoomState.Spectators, 1)
+
+	s.refreshStats()
+	stats := s.Stats()
+	assert.Equal(t, stats.Spectators, 1)
+	assert.Equal(t, stats.Clients, 1)
+	assert.Equal(t, stats.Details[0].Spectators, 1)
+}
+
+func TestSpectatorLeaves(t *testing.T) {
+	r := newTestRoom(t)
+	c := newFakeClock()
+	r.setClock(c)
+
+	player := dialTestRoom(t, r, ConnOptions{Nickname: "player"})
+	state := readState(t, player)
+	writeNote(t, player, protocol.ChangeTurnModeMethod, state.RoomState.Version, &protocol.ChangeTurnModeParams{Timed: true})
+	state = readState(t, player)
+
+	spectator := dialTestRoom(t, r, ConnOptions{Spectate: true})
+	readState(t, spectator)
+	c.Advance(spectatorInterval)
+	state = readState(t, player)
+	assert.Equal(t, state.RoomState.Spectators, 1)
+
+	var deadline time.Time
+	r.hold(func() {
+		deadline = *r.turnDeadline
+	})
+
+	assert.NilError(t, spectator.Close(1000, ""))
+
+	// Leaving only changes the count; the turn and its timer carry on.
+	until := time.Now().Add(5 * time.Second)
+	for r.spectatorCount.Load() != 0 {
+		assert.Assert(t, time.Now().Before(until), "spectator never left")
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Advance(spectatorInterval)
+
+	left := readState(t, player)
+	assert.Equal(t, left.RoomState.Spectators, 0)
+	assert.Equal(t, left.RoomState.Version, state.RoomState.Version)
+	assert.Equal(t, left.RoomState.Turn, state.RoomState.Turn)
+	assert.Equal(t, left.RoomState.Timer.TurnEnd, deadline)
+
+	// Commands made against the version from before still go through.
+	writeNote(t, player, protocol.ChangeNicknameMethod, state.RoomState.Version, &protocol.ChangeNicknameParams{Nickname: "renamed"})
+	p, _ := findStatePlayer(readState(t, player), "renamed")
+	assert.Assert(t, p != nil)
+}
+
+func TestSpectatorLimit(t *testing.T) {
+	r := newTestRoom(t)
+
+	r.hold(func() {
+		for i := 0; i < defaultMaxSpectators; i++ {
+			r.spectators[strconv.Itoa(i)] = &mirror{}
+		}
+	})
+
+	conn := dialTestRoom(t, r, ConnOptions{Spectate: true})
+	var e protocol.Error
+	readNote(t, conn, "error", &e)
+	assert.Equal(t, e.Code, "spectatorLimit")
+	assert.Equal(t, readCloseError(t, conn).Code, closeRoomBusy)
+}
+
+func TestSpectatorOverflow(t *testing.T) {
+	r := newTestRoom(t)
+	c := newFakeClock()
+	r.setClock(c)
+	r.hold(func() {
+		r.spectate = spectatorLimits{max: 1, overflow: true}
+	})
+
+	player := dialTestRoom(t, r, ConnOptions{Nickname: "player"})
+	readState(t, player)
+	first := dialTestRoom(t, r, ConnOptions{Spectate: true})
+	readState(t, first)
+
+	// Past the cap, spectators are let in rather than turned away.
+	late := dialTestRoom(t, r, ConnOptions{Spectate: true})
+	waitOverflow(t, r, 1)
+
+	// But they're only sent the latest state, once an interval.
+	var version int64
+	for i := 0; i < 3; i++ {
+		r.hold(func() {
+			r.room.Version++
+			version = r.room.Version
+			r.sendAll()
+		})
+		assert.Equal(t, readState(t, first).RoomState.Version, version)
+	}
+
+	c.Advance(overflowInterval)
+	assert.Equal(t, readState(t, late).RoomState.Version, version)
+
+	// With room under the cap again, overflowed spectators don't take it up,
+	// and past the join rate, spectators overflow too.
+	r.hold(func() {
+		for i := 0; i < defaultJoinBurst; i++ {
+			r.joins.admit(c.Now())
+		}
+	})
+	assert.NilError(t, first.Close(1000, ""))
+	waitSpectators(t, r, 1)
+
+	throttled := dialTestRoom(t, r, ConnOptions{Spectate: true})
+	waitOverflow(t, r, 2)
+
+	assert.NilError(t, late.Close(1000, ""))
+	assert.NilError(t, throttled.Close(1000, ""))
+	waitOverflow(t, r, 0)
+}
+
+func waitOverflow(t *testing.T, r *Room, want int) {
+	t.Helper()
+
+	until := time.Now().Add(5 * time.Second)
+	for {
+		var n int
+		r.hold(func() {
+			n = r.overflow
+		})
+		if n == want {
+			return
+		}
+		assert.Assert(t, time.Now().Before(until), "%d spectators overflowed, want %d", n, want)
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func waitSpectators(t *testing.T, r *Room, want int64) {
+	t.Helper()
+
+	until := time.Now().Add(5 * time.Second)
+	for r.spectatorCount.Load() != want {
+		assert.Assert(t, time.Now().Before(until), "%d spectators, want %d", r.spectatorCount.Load(), want)
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func (c *testClient) lastState() *protocol.State {
+	for i := len(c.notes) - 1; i >= 0; i-- {
+		if c.notes[i].Method == "state" {
+			return c.notes[i].Params.(*protocol.State)
+		}
+	}
+	return nil
+}
+
+func TestPendingClueOnlyToSpymasters(t *testing.T) {
+	r := newTestRoom(t)
+	spy0 := r.addTestClient(t, "spy0", 0, true)
+	spy1 := r.addTestClient(t, "spy1", 0, true)
+	guesser := r.addTestClient(t, "guesser", 0, false)
+	r.setTurn(0)
+
+	r.testNote(t, "spy0", protocol.ChangeSpymastersMethod, &protocol.ChangeSpymastersParams{Limit: 2, ConfirmClues: true})
+	r.testNote(t, "spy0", protocol.GiveClueMethod, &protocol.GiveClueParams{Word: "ANIMAL", Count: 2})
+
+	for _, c := range []*testClient{spy0, spy1} {
+		state := c.lastState().RoomState
+		assert.Assert(t, state.Clue == nil)
+		assert.DeepEqual(t, state.Spymasters, &protocol.StateSpymasters{
+			Limit:        2,
+			ConfirmClues: true,
+			Pending:      &protocol.StatePendingClue{Word: "ANIMAL", Count: 2, Confirmed: []string{"spy0"}},
+		})
+	}
+
+	state := guesser.lastState().RoomState
+	assert.Assert(t, state.Clue == nil)
+	assert.Assert(t, state.Spymasters.Pending == nil)
+
+	r.testNote(t, "spy1", protocol.GiveClueMethod, &protocol.GiveClueParams{Word: "ANIMAL", Count: 2})
+	state = guesser.lastState().RoomState
+	assert.DeepEqual(t, state.Clue, &protocol.StateClue{Word: "ANIMAL", Count: 2})
+}
+
+func TestSpymasterLimitRejected(t *testing.T) {
+	r := newTestRoom(t)
+	r.addTestClient(t, "spy0", 0, true)
+	guesser := r.addTestClient(t, "guesser", 0, false)
+
+	r.testNote(t, "spy0", protocol.ChangeSpymastersMethod, &protocol.ChangeSpymastersParams{Limit: 1})
+	r.testNote(t, "guesser", protocol.ChangeRoleMethod, &protocol.ChangeRoleParams{Spymaster: true})
+
+	errs := guesser.errors()
+	assert.Equal(t, len(errs), 1)
+	assert.Equal(t, errs[0].Code, "spymasterLimit")
+}
+
+func TestKeyChecksum(t *testing.T) {
+	r := newTestRoom(t)
+	spy0 := r.addTestClient(t, "spy0", 0, true)
+	spy1 := r.addTestClient(t, "spy1", 1, true)
+	guesser := r.addTestClient(t, "guesser", 0, false)
+	r.testNote(t, "guesser", protocol.ChangeNicknameMethod, &protocol.ChangeNicknameParams{Nickname: "renamed"})
+
+	sum := spy0.lastState().RoomState.KeyChecksum
+	assert.Equal(t, len(sum), 6)
+	assert.Equal(t, spy1.lastState().RoomState.KeyChecksum, sum)
+	assert.Equal(t, guesser.lastState().RoomState.KeyChecksum, "")
+
+	r.testNote(t, "spy0", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	r.testNote(t, "spy0", protocol.ChangeRoleMethod, &protocol.ChangeRoleParams{Spymaster: true})
+	r.testNote(t, "spy1", protocol.ChangeRoleMethod, &protocol.ChangeRoleParams{Spymaster: true})
+
+	next := spy0.lastState().RoomState.KeyChecksum
+	assert.Assert(t, next != sum)
+	assert.Equal(t, spy1.lastState().RoomState.KeyChecksum, next)
+}
@@ -0,0 +1,188 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func TestNewGameTeamsNotReady(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, true)
+	r.addTestClient(t, "guesser", 0, false)
+	r.addTestClient(t, "other", 1, false)
+	version := r.version()
+
+	r.testNote(t, "host", protocol.NewGameMethod, &protocol.NewGameParams{})
+	assert.Equal(t, r.version(), version)
+
+	errs := host.errors()
+	assert.Equal(t, len(errs), 1)
+	assert.Equal(t, errs[0].Code, "teamsNotReady")
+	assert.DeepEqual(t, errs[0].Shortfalls, []*protocol.ErrorShortfall{
+		{Team: 1, Spymaster: true, Have: 0, Need: 1},
+	})
+}
+
+func TestNewGameForce(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+	other := r.addTestClient(t, "other", 1, false)
+	version := r.version()
+
+	// Only the host may force a new game.
+	r.testNote(t, "other", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	assert.Equal(t, r.version(), version)
+	assert.Equal(t, len(other.errors()), 1)
+	assert.Equal(t, len(r.AuditLog()), 0)
+
+	r.testNote(t, "host", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	assert.Assert(t, r.version() != version)
+	assert.Equal(t, len(host.errors()), 0)
+
+	log := r.AuditLog()
+	assert.Equal(t, len(log), 1)
+	assert.Equal(t, log[0].Action, auditForceNewGame)
+}
+
+func TestNewGameReadyNotAudited(t *testing.T) {
+	r := newTestRoom(t)
+	r.addTestClient(t, "host", 0, true)
+	r.addTestClient(t, "guesser0", 0, false)
+	r.addTestClient(t, "spy1", 1, true)
+	r.addTestClient(t, "guesser1", 1, false)
+	version := r.version()
+
+	r.testNote(t, "host", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	assert.Assert(t, r.version() != version)
+	assert.Equal(t, len(r.AuditLog()), 0)
+}
+
+func TestStartMinimumOptions(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{
+		MinSpymasters: intPtr(0),
+		MinGuessers:   intPtr(2),
+	})
+	assert.DeepEqual(t, host.lastState().RoomState.Start, &protocol.StateStart{MinSpymasters: 0, MinGuessers: 2})
+	assert.DeepEqual(t, host.optionsChanged(), [][]string{{"minSpymasters", "minGuessers"}})
+
+	invalid := []*protocol.UpdateOptionsParams{
+		{MinSpymasters: intPtr(-1)},
+		{MinGuessers: intPtr(6)},
+		{MinSpymasters: intPtr(2), SpymasterLimit: intPtr(1)},
+	}
+	for _, params := range invalid {
+		r.testNote(t, "host", protocol.UpdateOptionsMethod, params)
+	}
+
+	errs := host.errors()
+	assert.Equal(t, len(errs), len(invalid))
+	for _, e := range errs {
+		assert.Equal(t, e.Code, "invalidOptions")
+	}
+}
+
+// ownTile returns one of a team's unrevealed words.
+func (r *Room) ownTile(t *testing.T, team game.Team) (row, col int) {
+	t.Helper()
+
+	found := false
+	r.hold(func() {
+		for row = 0; row < r.room.Board.Rows; row++ {
+			for col = 0; col < r.room.Board.Cols; col++ {
+				tile := r.room.Board.Get(row, col)
+				if !tile.Revealed && !tile.Neutral && !tile.Bomb && tile.Team == team {
+					found = true
+					return
+				}
+			}
+		}
+	})
+	if !found {
+		t.Fatal("no word to reveal")
+	}
+	return row, col
+}
+
+func newRevealTestRoom(t *testing.T) *Room {
+	t.Helper()
+
+	r := newTestRoom(t)
+	r.hold(func() {
+		r.room.MinRevealPlayers = 4
+		r.room.Turn = 0
+	})
+	return r
+}
+
+func TestFirstRevealNeedsPlayers(t *testing.T) {
+	r := newRevealTestRoom(t)
+	r.addTestClient(t, "spy0", 0, true)
+	guesser := r.addTestClient(t, "guesser0", 0, false)
+	r.addTestClient(t, "spy1", 1, true)
+	version := r.version()
+
+	row, col := r.ownTile(t, 0)
+	r.testNote(t, "guesser0", protocol.RevealMethod, &protocol.RevealParams{Row: row, Col: col})
+	assert.Equal(t, r.version(), version)
+
+	errs := guesser.errors()
+	assert.Equal(t, len(errs), 1)
+	assert.Equal(t, errs[0].Code, "notEnoughPlayers")
+	assert.Equal(t, *errs[0].Limit, 4)
+
+	r.addTestClient(t, "guesser1", 1, false)
+	version = r.version()
+	r.testNote(t, "guesser0", protocol.RevealMethod, &protocol.RevealParams{Row: row, Col: col})
+	assert.Assert(t, r.version() != version)
+	assert.Equal(t, len(guesser.errors()), 1)
+
+	// Only the first reveal of a game is checked.
+	r.disconnect("guesser1", nil)
+	version = r.version()
+	row, col = r.ownTile(t, 0)
+	r.testNote(t, "guesser0", protocol.RevealMethod, &protocol.RevealParams{Row: row, Col: col})
+	assert.Assert(t, r.version() != version)
+	assert.Equal(t, len(guesser.errors()), 1)
+}
+
+func TestFirstRevealForce(t *testing.T) {
+	r := newRevealTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+	other := r.addTestClient(t, "other", 0, false)
+	version := r.version()
+
+	// Only the host may force the first reveal.
+	row, col := r.ownTile(t, 0)
+	r.testNote(t, "other", protocol.RevealMethod, &protocol.RevealParams{Row: row, Col: col, Force: true})
+	assert.Equal(t, r.version(), version)
+	assert.Equal(t, len(other.errors()), 1)
+	assert.Equal(t, len(r.AuditLog()), 0)
+
+	r.testNote(t, "host", protocol.RevealMethod, &protocol.RevealParams{Row: row, Col: col, Force: true})
+	assert.Assert(t, r.version() != version)
+	assert.Equal(t, len(host.errors()), 0)
+
+	log := r.AuditLog()
+	assert.Equal(t, len(log), 1)
+	assert.Equal(t, log[0].Action, auditForceReveal)
+}
+
+func TestFirstRevealMinimumDisabled(t *testing.T) {
+	r := newTestRoom(t)
+	r.hold(func() {
+		r.room.Turn = 0
+	})
+	guesser := r.addTestClient(t, "guesser", 0, false)
+	version := r.version()
+
+	row, col := r.ownTile(t, 0)
+	r.testNote(t, "guesser", protocol.RevealMethod, &protocol.RevealParams{Row: row, Col: col})
+	assert.Assert(t, r.version() != version)
+	assert.Equal(t, len(guesser.errors()), 0)
+}
@@ -0,0 +1,189 @@
+package server
+
+import (
+	"sort"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/analytics"
+	"github.com/zikaeroh/codies/internal/gamefeed"
+	"go.uber.org/atomic"
+)
+
+const statsInterval = time.Second
+
+// counters are shared between a server and its rooms.
+type counters struct {
+	clients    atomic.Int64
+	mirrors    atomic.Int64
+	spectators atomic.Int64
+	rooms      atomic.Int64
+
+	// statsDirty is set when the stats snapshot is out of date.
+	statsDirty atomic.Bool
+
+	// analytics records usage events; nil if disabled.
+	analytics *analytics.Log
+
+	// feed records finished games; nil if disabled.
+	feed *gamefeed.Feed
+
+	// rulesets are the rulesets games have recently started with.
+	rulesets rulesetCache
+
+	// feedback tallies the ratings games were given; see feedback.go.
+	feedback feedbackTallies
+
+	// words are the words players have suggested; see wordbox.go.
+	words wordBox
+}
+
+// Stats is an immutable snapshot of the server's state.
+type Stats struct {
+	Taken       time.Time   `json:"taken"`
+	Rooms       int         `json:"rooms"`
+	Clients     int         `json:"clients"`
+	Mirrors     int         `json:"mirrors"`
+	Spectators  int         `json:"spectators"`
+	Matchmaking int         `json:"matchmaking"` // Players waiting to be matched.
+	Details     []RoomStats `json:"details"`
+}
+
+// RoomStats describes a single room in a stats snapshot.
+type RoomStats struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Clients    int       `json:"clients"`
+	Mirrors    int       `json:"mirrors"`
+	Spectators int       `json:"spectators"`
+	Bans       int       `json:"bans"`
+	LastSeen   time.Time `json:"lastSeen"`
+	LogLevel   *LogLevel `json:"logLevel,omitempty"`
+	Unlisted   bool      `json:"unlisted,omitempty"`
+
+	Pacing PacingStats `json:"pacing"`
+}
+
+func (r *Room) stats() RoomStats {
+	return RoomStats{
+		ID:         r.ID,
+		Name:       r.Name,
+		Clients:    int(r.clients.Load()),
+		Mirrors:    int(r.mirrorCount.Load()),
+		Spectators: int(r.spectatorCount.Load()),
+		Bans:       int(r.banCount.Load()),
+		LastSeen:   r.lastSeen.Load().(time.Time),
+		LogLevel:   r.LogLevel(),
+		Unlisted:   r.unlisted,
+		Pacing:     r.pacing.stats(),
+	}
+}
+
+// RoomStats describes a single room as it is now, rather than as of the last
+// snapshot.
+func (s *Server) RoomStats(id string) (RoomStats, bool) {
+	room := s.FindRoomByID(id)
+	if room == nil {
+		return RoomStats{}, false
+	}
+	return room.stats(), true
+}
+
+// ConnStats describes one of a room's connections, so that operators helping
+// a player can see what they're running. IDs are those in the logs: player
+// IDs for players, spectator IDs for spectators.
+type ConnStats struct {
+	ID            string    `json:"id"`
+	Nickname      string    `json:"nickname,omitempty"`
+	Spectator     bool      `json:"spectator,omitempty"`
+	ClientVersion string    `json:"clientVersion,omitempty"`
+	Connected     time.Time `json:"connected"`
+}
+
+// connInfo is what's kept about a connection for its ConnStats.
+type connInfo struct {
+	clientVersion string
+	connected     time.Time
+}
+
+// RoomConns returns the connections of the room with the given ID, players
+// by nickname and then spectators by when they connected.
+func (s *Server) RoomConns(id string) ([]ConnStats, bool) {
+	room := s.FindRoomByID(id)
+	if room == nil {
+		return nil, false
+	}
+	return room.connStats(), true
+}
+
+func (r *Room) connStats() []ConnStats {
+	var conns []ConnStats
+	r.do(func() {
+		conns = make([]ConnStats, 0, len(r.players)+len(r.spectators))
+		for playerID := range r.players {
+			c := ConnStats{
+				ID:            playerID,
+				ClientVersion: r.info[playerID].clientVersion,
+				Connected:     r.info[playerID].connected,
+			}
+			if p := r.room.Players[playerID]; p != nil {
+				c.Nickname = p.Nickname
+			}
+			conns = append(conns, c)
+		}
+		for spectatorID, m := range r.spectators {
+			conns = append(conns, ConnStats{
+				ID:            spectatorID,
+				Spectator:     true,
+				ClientVersion: m.info.clientVersion,
+				Connected:     m.info.connected,
+			})
+		}
+	})
+
+	sort.Slice(conns, func(i, j int) bool {
+		a, b := conns[i], conns[j]
+		switch {
+		case a.Spectator != b.Spectator:
+			return !a.Spectator
+		case a.Spectator && !a.Connected.Equal(b.Connected):
+			return a.Connected.Before(b.Connected)
+		case !a.Spectator && a.Nickname != b.Nickname:
+			return a.Nickname < b.Nickname
+		}
+		return a.ID < b.ID
+	})
+	return conns
+}
+
+// Stats returns the latest stats snapshot, which is at most statsInterval
+// old. It never contends with the rooms.
+func (s *Server) Stats() *Stats {
+	return s.stats.Load().(*Stats)
+}
+
+// refreshStats takes a new snapshot. Only the server's lock is held; room
+// fields are read atomically.
+func (s *Server) refreshStats() {
+	s.counters.statsDirty.Store(false)
+
+	s.mu.Lock()
+	details := make([]RoomStats, 0, len(s.rooms))
+	for _, room := range s.rooms {
+		details = append(details, room.stats())
+	}
+	s.mu.Unlock()
+
+	sort.Slice(details, func(i, j int) bool {
+		return details[i].ID < details[j].ID
+	})
+
+	s.stats.Store(&Stats{
+		Taken:       time.Now(),
+		Rooms:       len(details),
+		Clients:     int(s.counters.clients.Load()),
+		Mirrors:     int(s.counters.mirrors.Load()),
+		Spectators:  int(s.counters.spectators.Load()),
+		Matchmaking: int(s.matches.waiting.Load()),
+		Details:     details,
+	})
+}
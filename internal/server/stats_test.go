@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestStatsSnapshot(t *testing.T) {
+	s := newTestServer(t, nil)
+	assert.Equal(t, s.Stats().Rooms, 0)
+
+	room, err := s.CreateRoom(context.Background(), "room", "pass")
+	assert.NilError(t, err)
+
+	room.clients.Inc()
+	s.refreshStats()
+
+	stats := s.Stats()
+	assert.Equal(t, stats.Rooms, 1)
+	assert.Equal(t, len(stats.Details), 1)
+	assert.Equal(t, stats.Details[0].ID, room.ID)
+	assert.Equal(t, stats.Details[0].Name, "room")
+	assert.Equal(t, stats.Details[0].Clients, 1)
+}
+
+func TestStatsDoesNotLock(t *testing.T) {
+	s := newTestServer(t, nil)
+	room, err := s.CreateRoom(context.Background(), "room", "pass")
+	assert.NilError(t, err)
+	s.refreshStats()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	room.hold(func() {
+		done := make(chan *Stats)
+		go func() {
+			done <- s.Stats()
+		}()
+
+		select {
+		case stats := <-done:
+			assert.Equal(t, stats.Rooms, 1)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Stats blocked on a held lock")
+		}
+	})
+}
+
+func TestStatsRefreshedByRun(t *testing.T) {
+	s := newTestServer(t, nil)
+
+	room, err := s.CreateRoom(context.Background(), "room", "pass")
+	assert.NilError(t, err)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for s.Stats().Rooms != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("stats were not refreshed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.Equal(t, s.Stats().Details[0].ID, room.ID)
+}
+
+func TestRoomConns(t *testing.T) {
+	s := newTestServer(t, nil)
+	room, err := s.CreateRoom(context.Background(), "room", "pass")
+	assert.NilError(t, err)
+	c := newFakeClock()
+	room.setClock(c)
+	start := c.Now()
+
+	readState(t, dialTestRoom(t, room, ConnOptions{Nickname: "zed", ClientVersion: "v1.2.3"}))
+	readState(t, dialTestRoom(t, room, ConnOptions{Nickname: "amy"}))
+	c.Advance(time.Second)
+	readState(t, dialTestRoom(t, room, ConnOptions{Spectate: true, ClientVersion: "v1.2.2"}))
+
+	conns, ok := s.RoomConns(room.ID)
+	assert.Assert(t, ok)
+	assert.Equal(t, len(conns), 3)
+
+	for _, conn := range conns {
+		assert.Assert(t, conn.ID != "")
+	}
+
+	assert.Equal(t, conns[0].Nickname, "amy")
+	assert.Equal(t, conns[0].ClientVersion, "")
+	assert.Equal(t, conns[1].Nickname, "zed")
+	assert.Equal(t, conns[1].ClientVersion, "v1.2.3")
+	assert.Equal(t, conns[1].Connected, start)
+	assert.Assert(t, conns[2].Spectator)
+	assert.Equal(t, conns[2].ClientVersion, "v1.2.2")
+	assert.Equal(t, conns[2].Connected, start.Add(time.Second))
+
+	_, ok = s.RoomConns("missing")
+	assert.Assert(t, !ok)
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"github.com/zikaeroh/codies/internal/suggest"
+)
+
+const maxSuggestions = 3
+
+// Must be called on the room's goroutine.
+func (r *Room) suggestionsFor(playerID game.PlayerID) []*protocol.ClueSuggestion {
+	player := r.room.Players[playerID]
+	if player == nil || !player.Spymaster || player.Team != r.room.Turn {
+		return nil
+	}
+
+	r.createStateFor(playerID) // Ensure the cache is current.
+	return r.state.suggestions
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) createSuggestions() []*protocol.ClueSuggestion {
+	room := r.room
+	if !room.SuggestClues || room.Tracker || room.Winner != nil || room.Clue != nil {
+		return nil
+	}
+
+	// Until the turn's board is chosen, suggest clues for the first.
+	board := 0
+	if room.TurnBoard != nil {
+		board = *room.TurnBoard
+	}
+	tiles := room.Boards[board]
+
+	var b suggest.Board
+	for row := 0; row < tiles.Rows; row++ {
+		for col := 0; col < tiles.Cols; col++ {
+			tile := tiles.Get(row, col)
+			b.All = append(b.All, tile.Word)
+
+			switch {
+			case tile.Revealed:
+			case tile.Bomb:
+				b.Bombs = append(b.Bombs, tile.Word)
+			case !tile.Neutral && tile.Team == room.Turn:
+				b.Targets = append(b.Targets, tile.Word)
+			default:
+				b.Avoid = append(b.Avoid, tile.Word)
+			}
+		}
+	}
+
+	found := r.suggestions.Suggest(b, maxSuggestions)
+	suggestions := make([]*protocol.ClueSuggestion, len(found))
+	for i, s := range found {
+		suggestions[i] = &protocol.ClueSuggestion{
+			Board: board,
+			Word:  s.Word,
+			Count: s.Count,
+		}
+	}
+	return suggestions
+}
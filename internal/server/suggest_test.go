@@ -0,0 +1,85 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"github.com/zikaeroh/codies/internal/suggest"
+	"gotest.tools/v3/assert"
+)
+
+func (c *testClient) suggestions() []*protocol.ClueSuggestions {
+	var notes []*protocol.ClueSuggestions
+	for _, n := range c.notes {
+		if n.Method == "clueSuggestions" {
+			notes = append(notes, n.Params.(*protocol.ClueSuggestions))
+		}
+	}
+	return notes
+}
+
+// useTestSuggestions relates every word on the board to a single clue.
+func (r *Room) useTestSuggestions(t *testing.T) {
+	t.Helper()
+
+	r.hold(func() {
+		var b strings.Builder
+		for row := 0; row < r.room.Board.Rows; row++ {
+			for col := 0; col < r.room.Board.Cols; col++ {
+				tile := r.room.Board.Get(row, col)
+				if !tile.Bomb && !tile.Neutral && tile.Team == 0 {
+					b.WriteString(tile.Word + ": CLUE\n")
+				}
+			}
+		}
+
+		table, err := suggest.Parse(strings.NewReader(b.String()))
+		assert.NilError(t, err)
+		r.suggestions = table
+	})
+}
+
+func TestSuggestionsOnlyToTurnSpymaster(t *testing.T) {
+	r := newTestRoom(t)
+	r.useTestSuggestions(t)
+	host := r.addTestClient(t, "host", 0, false)
+	spy0 := r.addTestClient(t, "spy0", 0, true)
+	spy1 := r.addTestClient(t, "spy1", 1, true)
+	r.setTurn(0)
+
+	r.testNote(t, "host", protocol.ChangeSuggestCluesMethod, &protocol.ChangeSuggestCluesParams{SuggestClues: true})
+
+	notes := spy0.suggestions()
+	assert.Equal(t, len(notes), 1)
+	r.hold(func() {
+		assert.DeepEqual(t, notes[0].Suggestions, []*protocol.ClueSuggestion{
+			{Word: "CLUE", Count: r.room.Board.WordCounts[0]},
+		})
+	})
+
+	assert.Equal(t, len(host.suggestions()), 0)
+	assert.Equal(t, len(spy1.suggestions()), 0)
+}
+
+func TestSuggestionsDisabled(t *testing.T) {
+	r := newTestRoom(t)
+	r.useTestSuggestions(t)
+	r.addTestClient(t, "host", 0, false)
+	spy0 := r.addTestClient(t, "spy0", 0, true)
+	r.setTurn(0)
+
+	// Off by default, and only the host can turn it on.
+	r.testNote(t, "spy0", protocol.ChangeSuggestCluesMethod, &protocol.ChangeSuggestCluesParams{SuggestClues: true})
+	r.hold(func() {
+		assert.Assert(t, !r.room.SuggestClues)
+	})
+	assert.Equal(t, len(spy0.suggestions()), 0)
+
+	r.testNote(t, "host", protocol.ChangeSuggestCluesMethod, &protocol.ChangeSuggestCluesParams{SuggestClues: true})
+	assert.Equal(t, len(spy0.suggestions()), 1)
+
+	// Nothing more once a clue has been given.
+	r.testNote(t, "spy0", protocol.GiveClueMethod, &protocol.GiveClueParams{Word: "CLUE", Count: 1})
+	assert.Equal(t, len(spy0.suggestions()), 1)
+}
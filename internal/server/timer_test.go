@@ -0,0 +1,90 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func (r *Room) testTimer(t *testing.T) *protocol.StateTimer {
+	t.Helper()
+
+	var timer *protocol.StateTimer
+	r.hold(func() {
+		timer = r.createRoomState(false).Timer
+	})
+	return timer
+}
+
+func TestTurnTimerDeadline(t *testing.T) {
+	r, c := newTimedTestRoom(t, 0)
+	obs := r.addTestClient(t, "obs", 1, false)
+
+	// Clients are sent the absolute deadline, not the time left.
+	deadline := c.Now().Add(60 * time.Second)
+	assert.DeepEqual(t, r.testTimer(t), &protocol.StateTimer{TurnTime: 60, TurnEnd: deadline})
+
+	// On expiry, the server ends the turn itself, and times the next.
+	c.Advance(60 * time.Second)
+	assert.Equal(t, r.turn(), game.Team(1))
+	state := obs.lastState().RoomState
+	assert.Equal(t, state.Turn, game.Team(1))
+	assert.DeepEqual(t, state.Timer, &protocol.StateTimer{TurnTime: 60, TurnEnd: deadline.Add(60 * time.Second)})
+}
+
+func TestTurnTimerResetOnTurnChange(t *testing.T) {
+	r, c := newTimedTestRoom(t, 0)
+
+	c.Advance(45 * time.Second)
+	r.testNote(t, "g0", protocol.EndTurnMethod, &protocol.EndTurnParams{})
+	assert.Equal(t, r.turn(), game.Team(1))
+	assert.DeepEqual(t, r.testTimer(t).TurnEnd, c.Now().Add(60*time.Second))
+
+	// The old deadline passes without ending the new turn.
+	c.Advance(15 * time.Second)
+	assert.Equal(t, r.turn(), game.Team(1))
+
+	// A reveal which keeps the turn keeps its deadline, too.
+	r.revealOwn(t, "g1", 1)
+	assert.DeepEqual(t, r.testTimer(t).TurnEnd, c.Now().Add(45*time.Second))
+	c.Advance(45 * time.Second)
+	assert.Equal(t, r.turn(), game.Team(0))
+}
+
+func TestTurnTimerGameOver(t *testing.T) {
+	r, c := newTimedTestRoom(t, 0)
+
+	r.revealBomb(t, "g0")
+	assert.Assert(t, r.winner() != nil)
+	assert.Assert(t, r.testTimer(t) == nil)
+
+	turn := r.turn()
+	c.Advance(10 * time.Minute)
+	assert.Equal(t, r.turn(), turn)
+
+	// Changing the turn time leaves the finished game untimed.
+	r.testNote(t, "g0", protocol.ChangeTurnTimeMethod, &protocol.ChangeTurnTimeParams{Seconds: 30})
+	assert.Assert(t, r.testTimer(t) == nil)
+
+	// The next game is timed again.
+	r.testNote(t, "g0", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	assert.DeepEqual(t, r.testTimer(t), &protocol.StateTimer{TurnTime: 30, TurnEnd: c.Now().Add(30 * time.Second)})
+}
+
+func TestTurnTimerDisabled(t *testing.T) {
+	r, c := newTimedTestRoom(t, 0)
+
+	c.Advance(30 * time.Second)
+	r.testNote(t, "g0", protocol.ChangeTurnModeMethod, &protocol.ChangeTurnModeParams{Timed: false})
+	assert.Assert(t, r.testTimer(t) == nil)
+
+	c.Advance(10 * time.Minute)
+	assert.Equal(t, r.turn(), game.Team(0))
+
+	// Enabling it again starts the turn's clock over.
+	r.testNote(t, "g0", protocol.ChangeTurnModeMethod, &protocol.ChangeTurnModeParams{Timed: true})
+	assert.DeepEqual(t, r.testTimer(t).TurnEnd, c.Now().Add(60*time.Second))
+}
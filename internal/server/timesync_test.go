@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+)
+
+// stampedNote is a server note as it arrives, with its send time.
+type stampedNote struct {
+	Method protocol.ServerMethod `json:"method"`
+	Params json.RawMessage       `json:"params"`
+	Sent   *time.Time            `json:"sent"`
+}
+
+// readStamped reads notes until one with the given method arrives.
+func readStamped(t *testing.T, c *websocket.Conn, method protocol.ServerMethod, params interface{}) *stampedNote {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for {
+		var note stampedNote
+		assert.NilError(t, wsjson.Read(ctx, c, &note))
+		if note.Method == method {
+			assert.NilError(t, json.Unmarshal(note.Params, params))
+			return &note
+		}
+	}
+}
+
+func TestTimeSync(t *testing.T) {
+	r := newTestRoom(t)
+	c := newFakeClock()
+	r.setClock(c)
+
+	conn := dialTestRoom(t, r, ConnOptions{Nickname: "player"})
+	var state protocol.State
+	readNote(t, conn, "state", &state)
+
+	c.Advance(time.Second)
+	writeNote(t, conn, protocol.TimeSyncMethod, 0, &protocol.TimeSyncParams{ClientTime: 42})
+
+	var reply protocol.TimeSync
+	note := readStamped(t, conn, "timeSync", &reply)
+	assert.Equal(t, reply.ClientTime, int64(42))
+	assert.Equal(t, reply.Received, c.Now())
+	assert.Assert(t, note.Sent != nil)
+	assert.Assert(t, !note.Sent.Before(reply.Received))
+
+	// Answering doesn't touch the room.
+	r.hold(func() {
+		assert.Equal(t, r.room.Version, state.RoomState.Version)
+	})
+}
+
+func TestTimeSyncMirror(t *testing.T) {
+	r := newTestRoom(t)
+	host := r.addTestClient(t, "host", 0, false)
+	token := r.testMirrorToken(t, host)
+
+	conn := dialTestMirror(t, r, token)
+	readNote(t, conn, "state", nil)
+
+	writeNote(t, conn, protocol.TimeSyncMethod, 0, &protocol.TimeSyncParams{ClientTime: 7})
+
+	var reply protocol.TimeSync
+	note := readStamped(t, conn, "timeSync", &reply)
+	assert.Equal(t, reply.ClientTime, int64(7))
+	assert.Assert(t, note.Sent != nil)
+}
+
+func TestTimerNotesStamped(t *testing.T) {
+	r := newTestRoom(t)
+	c := newFakeClock()
+	r.setClock(c)
+
+	conn := dialTestRoom(t, r, ConnOptions{Nickname: "player"})
+
+	// Untimed states have nothing to stamp.
+	var state protocol.State
+	note := readStamped(t, conn, "state", &state)
+	assert.Assert(t, state.RoomState.Timer == nil)
+	assert.Assert(t, note.Sent == nil)
+
+	writeNote(t, conn, protocol.ChangeTurnModeMethod, state.RoomState.Version, &protocol.ChangeTurnModeParams{Timed: true})
+
+	var last time.Time
+	for i := 0; i < 3; i++ {
+		note := readStamped(t, conn, "state", &state)
+		timer := state.RoomState.Timer
+		assert.Assert(t, timer != nil)
+		assert.Assert(t, note.Sent != nil, "state %d has a timer but no send time", i)
+		assert.Assert(t, !note.Sent.Before(last), "send times went backward")
+		assert.Assert(t, !note.Sent.After(timer.TurnEnd), "sent after the deadline")
+		last = *note.Sent
+
+		c.Advance(5 * time.Second)
+		writeNote(t, conn, protocol.ChangeNicknameMethod, state.RoomState.Version, &protocol.ChangeNicknameParams{Nickname: "player" + string(rune('a'+i))})
+	}
+
+	// Deadlines are the server's own, whatever the client's clock says.
+	r.hold(func() {
+		assert.Equal(t, *r.turnDeadline, state.RoomState.Timer.TurnEnd)
+	})
+}
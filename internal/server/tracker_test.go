@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func newTestTrackerRoom(t *testing.T) *Room {
+	t.Helper()
+
+	s := newTestServer(t, nil)
+	r, err := s.CreateRoomWait(context.Background(), "tracker", "pass", RoomOptions{Tracker: true}, 0)
+	assert.NilError(t, err)
+	return r
+}
+
+func TestTrackerState(t *testing.T) {
+	r := newTestTrackerRoom(t)
+	host := r.addTestClient(t, "host", 0, true)
+	r.addTestClient(t, "other", 1, false)
+	r.setTurn(0)
+
+	r.testNote(t, "host", protocol.GiveClueMethod, &protocol.GiveClueParams{Word: "ANIMAL", Count: 2})
+	r.testNote(t, "other", protocol.ScoreMethod, &protocol.ScoreParams{Team: 0, Delta: 1})
+
+	state := host.lastState().RoomState
+	assert.DeepEqual(t, state.Tracker, &protocol.StateTracker{
+		Scores: []int{1, 0},
+		Clues:  []*protocol.StateLoggedClue{{Team: 0, Word: "ANIMAL", Count: 2}},
+	})
+	assert.Equal(t, state.KeyChecksum, "")
+
+	// The board section is left out entirely.
+	raw, err := json.Marshal(state)
+	assert.NilError(t, err)
+	var fields map[string]json.RawMessage
+	assert.NilError(t, json.Unmarshal(raw, &fields))
+	for _, field := range []string{"board", "wordsLeft", "boards", "turnBoard", "boardOptions", "lists"} {
+		_, ok := fields[field]
+		assert.Assert(t, !ok, "state has %s", field)
+	}
+
+	r.testNote(t, "host", protocol.DeclareWinnerMethod, &protocol.DeclareWinnerParams{Team: 1})
+	assert.Equal(t, *host.lastState().RoomState.Winner, game.Team(1))
+}
+
+func TestTrackerMethods(t *testing.T) {
+	r := newTestTrackerRoom(t)
+	assert.Equal(t, r.role, roleTracker)
+
+	for _, method := range []protocol.ClientMethod{
+		protocol.RevealMethod,
+		protocol.UseHintMethod,
+		protocol.AddPacksMethod,
+		protocol.RemovePackMethod,
+		protocol.ChangePackMethod,
+		protocol.ChangeHideBombMethod,
+		protocol.ChangeBoundCluesMethod,
+		protocol.ChangeSuggestCluesMethod,
+	} {
+		assert.Assert(t, !allowed(roleTracker, method), "tracker players may send %s", method)
+		assert.Assert(t, allowed(rolePlayer, method))
+	}
+
+	for _, method := range []protocol.ClientMethod{
+		protocol.NewGameMethod,
+		protocol.EndTurnMethod,
+		protocol.GiveClueMethod,
+		protocol.ScoreMethod,
+		protocol.DeclareWinnerMethod,
+		protocol.UpdateOptionsMethod,
+		protocol.SetWebhookMethod,
+	} {
+		assert.Assert(t, allowed(roleTracker, method), "tracker players may not send %s", method)
+	}
+}
+
+func TestTrackerOptions(t *testing.T) {
+	r := newTestTrackerRoom(t)
+	c := r.addTestClient(t, "host", 0, false)
+
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{Boards: intPtr(2)})
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{HideBomb: boolPtr(true)})
+
+	errs := c.errors()
+	assert.Equal(t, len(errs), 2)
+	assert.Equal(t, errs[0].Code, "invalidOptions")
+
+	// Options which don't involve the board still work.
+	r.testNote(t, "host", protocol.UpdateOptionsMethod, &protocol.UpdateOptionsParams{Timed: boolPtr(true)})
+	assert.Equal(t, len(c.errors()), 2)
+	assert.Assert(t, c.lastState().RoomState.Timer != nil)
+}
+
+func TestTrackerNewGame(t *testing.T) {
+	r := newTestTrackerRoom(t)
+	c := r.addTestClient(t, "host", 0, false)
+	other := r.addTestClient(t, "other", 1, false)
+	r.testNote(t, "other", protocol.ScoreMethod, &protocol.ScoreParams{Team: 1, Delta: 1})
+
+	r.testNote(t, "host", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	assert.DeepEqual(t, c.lastState().RoomState.Tracker.Scores, []int{0, 0})
+
+	// Without a board, new games are still noticed.
+	notified := c
+	if r.turn() == 1 {
+		notified = other
+	}
+	assert.Equal(t, len(notified.notifications()), 1)
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func TestUndoReopensGame(t *testing.T) {
+	r := newTestRoom(t)
+	r.setClock(newFakeClock())
+	host := r.addTestClient(t, "g0", 0, false)
+	r.addTestClient(t, "g1", 1, false)
+	obs := r.addTestClient(t, "obs", 1, false)
+	r.setTurn(0)
+	r.testNote(t, "g0", protocol.ChangeTurnModeMethod, &protocol.ChangeTurnModeParams{Timed: true})
+
+	r.revealBomb(t, "g0")
+	state := obs.lastState().RoomState
+	assert.Assert(t, state.Winner != nil)
+	assert.Assert(t, state.CanUndo)
+	assert.Assert(t, state.Timer == nil)
+
+	// Only the host may undo.
+	r.testNote(t, "obs", protocol.UndoMethod, &protocol.UndoParams{})
+	assert.Assert(t, r.winner() != nil)
+
+	r.testNote(t, "g0", protocol.UndoMethod, &protocol.UndoParams{})
+	state = obs.lastState().RoomState
+	assert.Assert(t, state.Winner == nil)
+	assert.DeepEqual(t, state.Wins, []int{0, 0})
+	assert.Equal(t, state.Turn, game.Team(0))
+	assert.Assert(t, !state.CanUndo)
+	assert.Assert(t, state.Timer != nil)
+	for _, row := range state.Board {
+		for _, tile := range row {
+			assert.Assert(t, !tile.Revealed)
+		}
+	}
+
+	r.testNote(t, "g0", protocol.GetAuditLogMethod, &protocol.GetAuditLogParams{})
+	entries := host.auditLogs()[0].Entries
+	assert.Equal(t, entries[len(entries)-1].Action, auditUndo)
+
+	// There's only the one reveal to undo.
+	r.testNote(t, "g0", protocol.UndoMethod, &protocol.UndoParams{})
+	errs := host.errors()
+	assert.Equal(t, len(errs), 1)
+	assert.Equal(t, errs[0].Code, "nothingToUndo")
+}
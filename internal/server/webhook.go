@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/url"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"github.com/zikaeroh/codies/internal/webhook"
+	"github.com/zikaeroh/ctxlog"
+	"go.uber.org/zap"
+)
+
+var (
+	errWebhooksDisabled = &game.Error{
+		Code:    "webhooksDisabled",
+		Message: "This server doesn't send webhooks.",
+	}
+	errWebhookURL = &game.Error{
+		Code:    "invalidWebhookURL",
+		Message: "Webhook URLs must start with http:// or https://.",
+	}
+	errWebhookNotAllowed = &game.Error{
+		Code:    "webhookNotAllowed",
+		Message: "This server doesn't send webhooks to that site.",
+	}
+)
+
+// Must be called on the room's goroutine.
+func (r *Room) webhookSender() *webhook.Sender {
+	if r.server == nil {
+		return nil
+	}
+	return r.server.webhooks
+}
+
+// setWebhook sets or, if rawURL is empty, clears the room's webhook. The URL
+// is never logged or audited, as webhook URLs usually hold a secret.
+//
+// Must be called on the room's goroutine.
+func (r *Room) setWebhook(playerID game.PlayerID, rawURL string) error {
+	if playerID != r.room.Host || rawURL == r.webhook {
+		return nil
+	}
+
+	if rawURL != "" {
+		sender := r.webhookSender()
+		if sender == nil {
+			return errWebhooksDisabled
+		}
+
+		if err := sender.Check(rawURL); err != nil {
+			if errors.Is(err, webhook.ErrNotAllowed) {
+				return errWebhookNotAllowed
+			}
+			return errWebhookURL
+		}
+	}
+
+	change := &protocol.AuditChange{Field: "webhook", Before: webhookAudit(r.webhook), After: webhookAudit(rawURL)}
+	r.audit(auditWebhook, playerID, "", change)
+
+	r.webhook = rawURL
+	r.room.Version++
+	return nil
+}
+
+func webhookAudit(rawURL string) string {
+	if rawURL == "" {
+		return ""
+	}
+	return "set"
+}
+
+// sendWebhookURL sends the host the room's webhook URL; nobody else may read
+// it.
+//
+// Must be called on the room's goroutine.
+func (r *Room) sendWebhookURL(playerID game.PlayerID) {
+	if playerID != r.room.Host {
+		return
+	}
+
+	if sender := r.players[playerID]; sender != nil {
+		sender(priorityTargeted, protocol.NewWebhookNote(r.webhook))
+	}
+}
+
+// deliverWebhook posts the finished game to the room's webhook, if it has
+// one. Nothing waits for the delivery; it's counted, and failures are logged.
+//
+// Must be called on the room's goroutine.
+func (r *Room) deliverWebhook() {
+	sender := r.webhookSender()
+	if r.webhook == "" || sender == nil {
+		return
+	}
+
+	room := r.room
+	over := r.createGameOver()
+	p := &webhook.Payload{
+		Room:     r.Name,
+		Winner:   int(over.Winner),
+		Reason:   over.Reason,
+		Seconds:  over.Seconds,
+		Wins:     append([]int(nil), room.Wins...),
+		Finished: r.gameEnd,
+	}
+	if room.Tracker {
+		p.Scores = append([]int(nil), room.Scores...)
+	}
+
+	// The delivery outlives the room if it must; the sender bounds it.
+	rawURL, logCtx := r.webhook, r.ctx
+	go func() {
+		if err := sender.Send(context.Background(), rawURL, p); err != nil {
+			metricWebhooks.WithLabelValues("failure").Inc()
+			ctxlog.Warn(logCtx, "webhook delivery failed", zap.Error(webhookError(err)))
+			return
+		}
+		metricWebhooks.WithLabelValues("success").Inc()
+	}()
+}
+
+// webhookError drops the URL from errors which have it, like those of the
+// HTTP client, so it isn't logged.
+func webhookError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
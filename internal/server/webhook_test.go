@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"github.com/zikaeroh/codies/internal/webhook"
+	"gotest.tools/v3/assert"
+)
+
+func (c *testClient) webhooks() []string {
+	var urls []string
+	for _, n := range c.notes {
+		if n.Method == "webhook" {
+			urls = append(urls, n.Params.(*protocol.Webhook).URL)
+		}
+	}
+	return urls
+}
+
+// newWebhookServer starts a server whose rooms may only send webhooks to the
+// test server, which responds with status and passes on what it's sent.
+func newWebhookServer(t *testing.T, status int) (*Server, string, chan *webhook.Payload) {
+	t.Helper()
+
+	received := make(chan *webhook.Payload, 4)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := &webhook.Payload{}
+		if err := json.NewDecoder(r.Body).Decode(p); err == nil {
+			received <- p
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(ts.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	s := NewServer(Options{Webhooks: webhook.NewSender(ts.Client(), []string{"127.0.0.1"})})
+	go s.Run(ctx) //nolint:errcheck
+	return s, ts.URL + "/hook", received
+}
+
+func receivePayload(t *testing.T, received chan *webhook.Payload) *webhook.Payload {
+	t.Helper()
+
+	select {
+	case p := <-received:
+		return p
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook wasn't delivered")
+		return nil
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	s, hook, received := newWebhookServer(t, http.StatusNoContent)
+	r, err := s.CreateRoom(context.Background(), "league", "pass")
+	assert.NilError(t, err)
+	host := r.addTestClient(t, "host", 0, false)
+	other := r.addTestClient(t, "other", 1, false)
+
+	// Only the host may set it.
+	r.testNote(t, "other", protocol.SetWebhookMethod, &protocol.SetWebhookParams{URL: hook})
+	assert.Assert(t, other.lastState() == nil)
+
+	r.testNote(t, "host", protocol.SetWebhookMethod, &protocol.SetWebhookParams{URL: hook})
+	assert.Assert(t, other.lastState().RoomState.Webhook)
+	assert.Assert(t, host.lastState().RoomState.Webhook)
+
+	// Only the host may read it, and it's never in a state.
+	r.testNote(t, "other", protocol.GetWebhookMethod, &protocol.GetWebhookParams{})
+	r.testNote(t, "host", protocol.GetWebhookMethod, &protocol.GetWebhookParams{})
+	assert.DeepEqual(t, host.webhooks(), []string{hook})
+	assert.Assert(t, other.webhooks() == nil)
+	b, err := json.Marshal(other.notes)
+	assert.NilError(t, err)
+	assert.Assert(t, !strings.Contains(string(b), hook))
+
+	succeeded := testutil.ToFloat64(metricWebhooks.WithLabelValues("success"))
+
+	r.hold(func() {
+		r.room.Turn = 0
+	})
+	r.revealBomb(t, "host")
+
+	p := receivePayload(t, received)
+	assert.Equal(t, p.Room, "league")
+	assert.Equal(t, p.Winner, 1)
+	assert.Equal(t, p.Reason, "bomb")
+	assert.DeepEqual(t, p.Wins, []int{0, 1})
+	assert.Assert(t, p.Scores == nil)
+
+	for deadline := time.Now().Add(5 * time.Second); testutil.ToFloat64(metricWebhooks.WithLabelValues("success")) == succeeded; {
+		if time.Now().After(deadline) {
+			t.Fatal("delivery wasn't counted")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Cleared, there's nothing to deliver.
+	r.testNote(t, "host", protocol.SetWebhookMethod, &protocol.SetWebhookParams{})
+	assert.Assert(t, !other.lastState().RoomState.Webhook)
+	r.testNote(t, "host", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	r.hold(func() {
+		r.room.Turn = 0
+	})
+	r.revealBomb(t, "host")
+
+	select {
+	case <-received:
+		t.Fatal("webhook delivered after clearing it")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// The URL is audited as set or not, never by value.
+	entry := findAudit(r.AuditLog(), auditWebhook)
+	assert.DeepEqual(t, entry.Changes, []*protocol.AuditChange{{Field: "webhook", After: "set"}})
+}
+
+func TestWebhookFailure(t *testing.T) {
+	s, hook, received := newWebhookServer(t, http.StatusInternalServerError)
+	r, err := s.CreateRoom(context.Background(), "league", "pass")
+	assert.NilError(t, err)
+	r.addTestClient(t, "host", 0, false)
+	r.addTestClient(t, "other", 1, false)
+	r.testNote(t, "host", protocol.SetWebhookMethod, &protocol.SetWebhookParams{URL: hook})
+
+	failed := testutil.ToFloat64(metricWebhooks.WithLabelValues("failure"))
+
+	r.hold(func() {
+		r.room.Turn = 0
+	})
+	r.revealBomb(t, "host")
+
+	// It's retried once after a server error.
+	receivePayload(t, received)
+	receivePayload(t, received)
+
+	for deadline := time.Now().Add(5 * time.Second); testutil.ToFloat64(metricWebhooks.WithLabelValues("failure")) == failed; {
+		if time.Now().After(deadline) {
+			t.Fatal("failure wasn't counted")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWebhookErrors(t *testing.T) {
+	s, _, _ := newWebhookServer(t, http.StatusNoContent)
+	r, err := s.CreateRoom(context.Background(), "league", "pass")
+	assert.NilError(t, err)
+	host := r.addTestClient(t, "host", 0, false)
+
+	for _, u := range []string{"ftp://127.0.0.1/hook", "https://discord.com/api/webhooks/1/abc"} {
+		r.testNote(t, "host", protocol.SetWebhookMethod, &protocol.SetWebhookParams{URL: u})
+	}
+
+	var codes []string
+	for _, e := range host.errors() {
+		codes = append(codes, e.Code)
+	}
+	assert.DeepEqual(t, codes, []string{"invalidWebhookURL", "webhookNotAllowed"})
+	assert.Assert(t, host.lastState() == nil)
+
+	// Rooms outside a server which sends webhooks can't set one.
+	r = newTestRoom(t)
+	host = r.addTestClient(t, "host", 0, false)
+	r.testNote(t, "host", protocol.SetWebhookMethod, &protocol.SetWebhookParams{URL: "https://discord.com/api/webhooks/1/abc"})
+	assert.Equal(t, host.errors()[0].Code, "webhooksDisabled")
+}
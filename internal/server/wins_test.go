@@ -0,0 +1,33 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func TestWins(t *testing.T) {
+	r, _ := newTimedTestRoom(t, 0)
+	obs := r.addTestClient(t, "obs", 1, false)
+	completed := testutil.ToFloat64(metricGamesCompleted)
+
+	r.revealBomb(t, "g0")
+	assert.DeepEqual(t, obs.lastState().RoomState.Wins, []int{0, 1})
+	assert.Equal(t, testutil.ToFloat64(metricGamesCompleted), completed+1)
+
+	// The tally carries into the next game.
+	r.testNote(t, "g0", protocol.NewGameMethod, &protocol.NewGameParams{Force: true})
+	assert.DeepEqual(t, obs.lastState().RoomState.Wins, []int{0, 1})
+	assert.Equal(t, testutil.ToFloat64(metricGamesCompleted), completed+1)
+
+	// Only the host may reset it.
+	r.testNote(t, "obs", protocol.ResetWinsMethod, &protocol.ResetWinsParams{})
+	r.hold(func() {
+		assert.DeepEqual(t, r.room.Wins, []int{0, 1})
+	})
+
+	r.testNote(t, r.host(), protocol.ResetWinsMethod, &protocol.ResetWinsParams{})
+	assert.DeepEqual(t, obs.lastState().RoomState.Wins, []int{0, 0})
+}
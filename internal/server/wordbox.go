@@ -0,0 +1,261 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+)
+
+// Players may suggest words for the built-in packs. Suggestions are collected
+// server-wide, counted per word ignoring case, and exported by operators to
+// curate the packs by hand; nothing suggested is ever played.
+//
+// maxWordSuggestions bounds the words kept. Once full, the least suggested
+// word, seen longest ago, makes way for a new one.
+const maxWordSuggestions = 10000
+
+// Each player may suggest protocol.MaxWordSuggestions words at once, then
+// another every suggestWordInterval, so that many a day.
+const (
+	suggestWordInterval = 24 * time.Hour / protocol.MaxWordSuggestions
+	suggestWordRefill   = 24 * time.Hour
+)
+
+// WordSuggestion is a word suggested for a pack, with how often it was.
+type WordSuggestion struct {
+	Word     string    `json:"word"` // Upper case.
+	Pack     string    `json:"pack"`
+	Language string    `json:"language,omitempty"` // The room's, if its packs had one.
+	Count    int       `json:"count"`
+	First    time.Time `json:"first"`
+	Last     time.Time `json:"last"`
+}
+
+type wordSuggestionKey struct {
+	word, pack, language string
+}
+
+func (s *WordSuggestion) key() wordSuggestionKey {
+	return wordSuggestionKey{word: s.Word, pack: s.Pack, language: s.Language}
+}
+
+// wordBox holds the suggestions, and limits how often each player may make
+// them. The zero value is ready to use.
+type wordBox struct {
+	mu     sync.Mutex
+	words  map[wordSuggestionKey]*WordSuggestion
+	limits map[string]*tokenBucket
+	swept  time.Time
+}
+
+// admit takes a suggestion for the player, or returns how long until one is
+// available.
+func (b *wordBox) admit(player string, now time.Time) (retryAfter time.Duration, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.limits == nil {
+		b.limits = make(map[string]*tokenBucket)
+	}
+
+	// As with room creation, full buckets are dropped every so often.
+	if now.Sub(b.swept) >= suggestWordRefill {
+		for k, l := range b.limits {
+			if now.Sub(l.last) >= suggestWordRefill {
+				delete(b.limits, k)
+			}
+		}
+		b.swept = now
+	}
+
+	l := b.limits[player]
+	if l == nil {
+		l = &tokenBucket{}
+		b.limits[player] = l
+	}
+	return l.take(now, protocol.MaxWordSuggestions, suggestWordInterval)
+}
+
+func (b *wordBox) add(word, pack, language string, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.addLocked(&WordSuggestion{
+		Word:     strings.ToUpper(word),
+		Pack:     pack,
+		Language: language,
+		Count:    1,
+		First:    now,
+		Last:     now,
+	})
+}
+
+// Must be called with b.mu locked.
+func (b *wordBox) addLocked(s *WordSuggestion) {
+	if b.words == nil {
+		b.words = make(map[wordSuggestionKey]*WordSuggestion)
+	}
+
+	k := s.key()
+	have := b.words[k]
+	if have == nil {
+		if len(b.words) >= maxWordSuggestions {
+			b.evict()
+		}
+		c := *s
+		b.words[k] = &c
+		return
+	}
+
+	have.Count += s.Count
+	if s.First.Before(have.First) {
+		have.First = s.First
+	}
+	if s.Last.After(have.Last) {
+		have.Last = s.Last
+	}
+}
+
+// Must be called with b.mu locked.
+func (b *wordBox) evict() {
+	var fewest *WordSuggestion
+	for _, s := range b.words {
+		if fewest == nil || s.Count < fewest.Count || (s.Count == fewest.Count && s.Last.Before(fewest.Last)) {
+			fewest = s
+		}
+	}
+	delete(b.words, fewest.key())
+}
+
+// list copies the suggestions, most suggested first.
+func (b *wordBox) list() []*WordSuggestion {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	list := make([]*WordSuggestion, 0, len(b.words))
+	for _, s := range b.words {
+		c := *s
+		list = append(list, &c)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		switch {
+		case a.Count != b.Count:
+			return a.Count > b.Count
+		case a.Word != b.Word:
+			return a.Word < b.Word
+		case a.Pack != b.Pack:
+			return a.Pack < b.Pack
+		default:
+			return a.Language < b.Language
+		}
+	})
+	return list
+}
+
+// WordSuggestions returns the words players have suggested, most suggested
+// first.
+func (s *Server) WordSuggestions() []*WordSuggestion {
+	return s.counters.words.list()
+}
+
+// RestoreWordSuggestions adds suggestions saved from WordSuggestions, as when
+// the server restarts. Suggestions of words already held are merged.
+func (s *Server) RestoreWordSuggestions(list []*WordSuggestion) {
+	b := &s.counters.words
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, ws := range list {
+		if ws == nil || ws.Word == "" || ws.Count <= 0 {
+			continue
+		}
+		b.addLocked(ws)
+	}
+}
+
+// suggestWord records a word a player suggested for a built-in pack.
+//
+// Must be called on the room's goroutine.
+func (r *Room) suggestWord(playerID game.PlayerID, params *protocol.SuggestWordParams) error {
+	word := strings.TrimSpace(params.Word)
+	if word == "" {
+		return &game.Error{
+			Code:    "invalidWord",
+			Message: "Suggest a word.",
+		}
+	}
+
+	if max := game.MaxWordLength; utf8.RuneCountInString(word) > max {
+		return &game.Error{
+			Code:    "wordTooLong",
+			Message: fmt.Sprintf("Words may be at most %d characters.", max),
+			Limit:   &max,
+		}
+	}
+
+	if !r.hasBuiltinPack(params.Pack) {
+		return &game.Error{
+			Code:    "unknownPack",
+			Message: "Words may only be suggested for the room's built-in packs.",
+		}
+	}
+
+	// Player IDs only last as long as a connection, so players are told apart
+	// by address where it's known.
+	player := "player:" + r.ID + "/" + playerID
+	if ip := r.addrs[playerID]; ip != nil {
+		player = "ip:" + ip.String()
+	}
+
+	retryAfter, ok := r.counters.words.admit(player, r.clock.Now())
+	if !ok {
+		max := protocol.MaxWordSuggestions
+		seconds := int(math.Ceil(retryAfter.Seconds()))
+		return &game.Error{
+			Code:       "tooManySuggestions",
+			Message:    fmt.Sprintf("Players may suggest %d words a day.", max),
+			Limit:      &max,
+			RetryAfter: &seconds,
+		}
+	}
+
+	r.counters.words.add(word, params.Pack, r.language(), r.clock.Now())
+	return nil
+}
+
+// Must be called on the room's goroutine.
+func (r *Room) hasBuiltinPack(name string) bool {
+	for _, list := range r.room.WordLists {
+		if !list.Custom && list.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// language returns the language of the room's enabled built-in packs, if
+// they share one.
+//
+// Must be called on the room's goroutine.
+func (r *Room) language() string {
+	language := ""
+	for _, list := range r.room.WordLists {
+		if !list.Enabled || list.Custom || list.Language == "" {
+			continue
+		}
+		if language != "" && list.Language != language {
+			return ""
+		}
+		language = list.Language
+	}
+	return language
+}
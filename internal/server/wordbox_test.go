@@ -0,0 +1,137 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+)
+
+func TestSuggestWord(t *testing.T) {
+	r := newTestRoom(t)
+	clock := newFakeClock()
+	r.setClock(clock)
+	host := r.addTestClient(t, "host", 0, false)
+	r.addTestClient(t, "other", 1, false)
+
+	r.testNote(t, "host", protocol.SuggestWordMethod, &protocol.SuggestWordParams{Word: "Axolotl", Pack: "Base"})
+	r.testNote(t, "other", protocol.SuggestWordMethod, &protocol.SuggestWordParams{Word: " axolotl ", Pack: "Base"})
+	r.testNote(t, "host", protocol.SuggestWordMethod, &protocol.SuggestWordParams{Word: "axolotl", Pack: "Duet"})
+
+	r.testNote(t, "host", protocol.SuggestWordMethod, &protocol.SuggestWordParams{Word: "  ", Pack: "Base"})
+	r.testNote(t, "host", protocol.SuggestWordMethod, &protocol.SuggestWordParams{Word: "PNEUMONOULTRAMICROSCOPICSILICOVOLCANOCONIOSIS", Pack: "Base"})
+	r.testNote(t, "host", protocol.SuggestWordMethod, &protocol.SuggestWordParams{Word: "AXOLOTL", Pack: "Animals"})
+
+	var codes []string
+	for _, e := range host.errors() {
+		codes = append(codes, e.Code)
+	}
+	assert.DeepEqual(t, codes, []string{"invalidWord", "wordTooLong", "unknownPack"})
+
+	now := clock.Now()
+	assert.DeepEqual(t, r.counters.words.list(), []*WordSuggestion{
+		{Word: "AXOLOTL", Pack: "Base", Language: "en", Count: 2, First: now, Last: now},
+		{Word: "AXOLOTL", Pack: "Duet", Language: "en", Count: 1, First: now, Last: now},
+	})
+
+	// Suggestions never reach the packs.
+	r.hold(func() {
+		for _, list := range r.room.WordLists {
+			for i := 0; i < list.List.Len(); i++ {
+				assert.Assert(t, list.List.Get(i) != "AXOLOTL")
+			}
+		}
+	})
+}
+
+func TestSuggestWordRateLimit(t *testing.T) {
+	r := newTestRoom(t)
+	clock := newFakeClock()
+	r.setClock(clock)
+	host := r.addTestClient(t, "host", 0, false)
+	r.addTestClient(t, "other", 1, false)
+
+	suggest := func(id string, n int) {
+		r.testNote(t, id, protocol.SuggestWordMethod, &protocol.SuggestWordParams{Word: fmt.Sprintf("word%d", n), Pack: "Base"})
+	}
+
+	for i := 0; i < protocol.MaxWordSuggestions; i++ {
+		suggest("host", i)
+	}
+	assert.Equal(t, len(host.errors()), 0)
+
+	suggest("host", -1)
+	errs := host.errors()
+	assert.Equal(t, len(errs), 1)
+	assert.Equal(t, errs[0].Code, "tooManySuggestions")
+	assert.Equal(t, *errs[0].RetryAfter, int(suggestWordInterval/time.Second))
+
+	// Others have their own limit, and the player gets one back in time.
+	suggest("other", 0)
+	clock.Advance(suggestWordInterval)
+	suggest("host", -1)
+	assert.Equal(t, len(host.errors()), 1)
+	assert.Equal(t, len(r.counters.words.list()), protocol.MaxWordSuggestions+1)
+}
+
+func TestSuggestWordRateLimitByAddress(t *testing.T) {
+	r := newTestRoom(t)
+	r.setClock(newFakeClock())
+	host := r.addTestClient(t, "host", 0, false)
+	r.addTestClient(t, "other", 1, false)
+
+	// Players behind one address, or reconnecting as someone new, share it.
+	ip := net.ParseIP("192.0.2.1")
+	r.hold(func() {
+		r.addrs["host"] = ip
+		r.addrs["other"] = ip
+	})
+
+	for i := 0; i < protocol.MaxWordSuggestions; i++ {
+		r.testNote(t, "other", protocol.SuggestWordMethod, &protocol.SuggestWordParams{Word: fmt.Sprintf("word%d", i), Pack: "Base"})
+	}
+	r.testNote(t, "host", protocol.SuggestWordMethod, &protocol.SuggestWordParams{Word: "penguin", Pack: "Base"})
+	assert.Equal(t, len(host.errors()), 1)
+}
+
+func TestWordBoxEvict(t *testing.T) {
+	var b wordBox
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	b.add("popular", "Base", "en", start)
+	b.add("popular", "Base", "en", start)
+	for i := 0; i < maxWordSuggestions-1; i++ {
+		b.add(fmt.Sprintf("word%d", i), "Base", "en", start.Add(time.Duration(i)*time.Second))
+	}
+
+	// The least suggested word, seen longest ago, makes way.
+	b.add("new", "Base", "en", start.Add(time.Hour))
+	list := b.list()
+	assert.Equal(t, len(list), maxWordSuggestions)
+	assert.Equal(t, list[0].Word, "POPULAR")
+	for _, s := range list {
+		assert.Assert(t, s.Word != "WORD0")
+	}
+}
+
+func TestRestoreWordSuggestions(t *testing.T) {
+	s := newTestServer(t, nil)
+	first := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	last := first.Add(time.Hour)
+
+	s.counters.words.add("penguin", "Base", "en", last)
+	s.RestoreWordSuggestions([]*WordSuggestion{
+		{Word: "PENGUIN", Pack: "Base", Language: "en", Count: 3, First: first, Last: first},
+		{Word: "OTTER", Pack: "Base", Count: 1, First: first, Last: first},
+		{Word: "", Pack: "Base", Count: 1},
+		nil,
+	})
+
+	assert.DeepEqual(t, s.WordSuggestions(), []*WordSuggestion{
+		{Word: "PENGUIN", Pack: "Base", Language: "en", Count: 4, First: first, Last: last},
+		{Word: "OTTER", Pack: "Base", Count: 1, First: first, Last: first},
+	})
+}
@@ -0,0 +1,405 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/protocol"
+	"nhooyr.io/websocket"
+)
+
+// priority orders the writes queued on a connWriter. When more than one write
+// is pending, the highest priority is always written first; writes of the same
+// priority are written in the order they were queued.
+//
+// A close preempts everything: once it's dequeued the connection is closed and
+// any writes still pending are dropped. Acks (including pings, which are
+// answered by the client) go before targeted messages, like errors sent to a
+// single player, which go before broadcasts to the whole room.
+type priority int
+
+const (
+	priorityBroadcast priority = iota
+	priorityTargeted
+	priorityAck
+	priorityClose
+	numPriorities
+)
+
+const (
+	writerQueueSize = 64
+	writeTimeout    = time.Second
+)
+
+// A connection whose broadcast queue reaches queueHighWatermark is degraded:
+// rather than queueing more broadcasts and eventually being closed, only the
+// latest state is kept, and anything else broadcast is dropped. Once the queue
+// drains to queueLowWatermark, the connection is restored, and if anything was
+// dropped, it's given a fresh state.
+const (
+	queueHighWatermark = writerQueueSize * 3 / 4
+	queueLowWatermark  = writerQueueSize / 8
+)
+
+// closeTooSlow closes a client which has fallen so far behind that one of its
+// queues overflowed, so that it can't hold up the rest of the room.
+const closeTooSlow websocket.StatusCode = 4408
+
+var errWriterClosed = errors.New("connection writer closed")
+
+// noteBuffers holds the buffers notes are encoded into as they're written.
+var noteBuffers = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+type write struct {
+	note   *protocol.ServerNote
+	ping   bool
+	code   websocket.StatusCode
+	reason string
+	done   chan error // Optional; receives the result of the write.
+}
+
+// connWriter owns all writes to a websocket connection. nhooyr's Conn doesn't
+// allow concurrent writers, so writes are queued and performed by a single
+// goroutine, run. The connection itself is intentionally not reachable from a
+// connWriter; nothing outside of run may write to it.
+type connWriter struct {
+	c      *websocket.Conn
+	queues [numPriorities]chan write
+	wake   chan struct{}
+	done   chan struct{}
+
+	// resync, if set, sends the connection a fresh state. It's called by run
+	// without any of the writer's locks held.
+	resync func()
+
+	// now stamps notes which carry a timer as they're written.
+	now func() time.Time
+
+	mu       sync.Mutex
+	degraded bool
+	latest   *write               // The latest state broadcast while degraded.
+	dropped  bool                 // Set if any other broadcast was dropped while degraded.
+	closed   websocket.StatusCode // The code of a queued close, once it's been dequeued.
+	evicted  bool                 // Set once a queue has overflowed.
+}
+
+func newConnWriter(c *websocket.Conn) *connWriter {
+	w := &connWriter{
+		c:    c,
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+		now:  time.Now,
+	}
+	for i := range w.queues {
+		w.queues[i] = make(chan write, writerQueueSize)
+	}
+	return w
+}
+
+// send queues a note. It never blocks; if the client has fallen so far behind
+// that the queue is full, the connection is closed instead.
+func (w *connWriter) send(p priority, note protocol.ServerNote) {
+	w.enqueue(p, write{note: &note})
+}
+
+// sendWait queues a note and waits until it's been written.
+func (w *connWriter) sendWait(ctx context.Context, p priority, note protocol.ServerNote) error {
+	done := make(chan error, 1)
+	w.enqueue(p, write{note: &note, done: done})
+	return w.wait(ctx, done)
+}
+
+// ping queues a ping and waits for the client's pong.
+func (w *connWriter) ping(ctx context.Context) error {
+	return w.wait(ctx, w.queuePing())
+}
+
+// queuePing queues a ping without waiting. The returned channel receives nil
+// once the client's pong arrives, or an error if the connection ends first.
+func (w *connWriter) queuePing() chan error {
+	done := make(chan error, 1)
+	w.enqueue(priorityAck, write{ping: true, done: done})
+	return done
+}
+
+// close queues a close frame, hinted for its code. Pending writes are dropped.
+func (w *connWriter) close(code websocket.StatusCode, reason string) {
+	w.closeHinted(code, newCloseHint(code, reason))
+}
+
+// closeHinted is close, with a hint of the caller's own.
+func (w *connWriter) closeHinted(code websocket.StatusCode, hint protocol.CloseHint) {
+	w.enqueue(priorityClose, write{code: code, reason: hint.Encode()})
+}
+
+func (w *connWriter) enqueue(p priority, wr write) {
+	select {
+	case <-w.done:
+		if wr.done != nil {
+			wr.done <- errWriterClosed
+		}
+		return
+	default:
+	}
+
+	if p == priorityBroadcast && w.coalesce(wr) {
+		w.wakeRun()
+		return
+	}
+
+	select {
+	case w.queues[p] <- wr:
+	default:
+		if wr.done != nil {
+			wr.done <- errWriterClosed
+		}
+		if p != priorityClose {
+			w.evict()
+		}
+	}
+
+	w.wakeRun()
+}
+
+// evict closes a connection whose queue overflowed. It's only counted once,
+// however many more writes overflow before the close is written.
+func (w *connWriter) evict() {
+	w.mu.Lock()
+	evicted := w.evicted
+	w.evicted = true
+	w.mu.Unlock()
+
+	if evicted {
+		return
+	}
+
+	metricSlowEvicted.Inc()
+	w.close(closeTooSlow, "too slow")
+}
+
+func (w *connWriter) wakeRun() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// coalesce degrades the connection if its broadcast queue is too deep, and
+// while it's degraded, takes the broadcast in place of the queue. It returns
+// true if it took the write. Writes which are waited on are always queued.
+func (w *connWriter) coalesce(wr write) bool {
+	if wr.done != nil {
+		return false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.degraded {
+		if len(w.queues[priorityBroadcast]) < queueHighWatermark {
+			return false
+		}
+		w.degraded = true
+		metricQualityDegraded.Inc()
+		w.sendQuality(true)
+	}
+
+	if wr.note != nil && wr.note.Method == "state" {
+		w.latest = &wr
+	} else {
+		w.dropped = true
+	}
+
+	return true
+}
+
+// Must be called with w.mu locked.
+func (w *connWriter) sendQuality(degraded bool) {
+	note := protocol.NewQualityNote(degraded)
+	select {
+	case w.queues[priorityTargeted] <- write{note: &note}:
+	default:
+	}
+}
+
+// restore restores a degraded connection once its broadcast queue has
+// drained. The latest state goes to the back of the queue, so broadcasts stay
+// in order.
+func (w *connWriter) restore() {
+	w.mu.Lock()
+
+	if !w.degraded || len(w.queues[priorityBroadcast]) > queueLowWatermark {
+		w.mu.Unlock()
+		return
+	}
+
+	w.degraded = false
+	if w.latest != nil {
+		select {
+		case w.queues[priorityBroadcast] <- *w.latest:
+		default:
+			// Writes which were already on their way filled the queue.
+			w.dropped = true
+		}
+		w.latest = nil
+	}
+	dropped := w.dropped
+	w.dropped = false
+	w.sendQuality(false)
+	w.mu.Unlock()
+
+	if dropped && w.resync != nil {
+		w.resync()
+	}
+}
+
+// closedWith returns the code of the close the writer sent on its own, or zero
+// if it hasn't.
+func (w *connWriter) closedWith() websocket.StatusCode {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.closed
+}
+
+func (w *connWriter) wait(ctx context.Context, done chan error) error {
+	select {
+	case err := <-done:
+		return err
+	case <-w.done:
+		select {
+		case err := <-done:
+			return err
+		default:
+			return errWriterClosed
+		}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// next dequeues the highest priority pending write.
+func (w *connWriter) next() (write, bool) {
+	for p := numPriorities - 1; p >= 0; p-- {
+		select {
+		case wr := <-w.queues[p]:
+			return wr, true
+		default:
+		}
+	}
+	return write{}, false
+}
+
+// run performs the queued writes until the connection is closed, either by a
+// queued close or by ctx being canceled. It must be the only caller of any
+// writing method on the connection.
+func (w *connWriter) run(ctx context.Context) error {
+	defer close(w.done)
+
+	for {
+		w.restore()
+
+		wr, ok := w.next()
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return w.goingAway()
+			case <-w.wake:
+				continue
+			}
+		}
+
+		if wr.note == nil && !wr.ping {
+			return w.closeWith(wr)
+		}
+
+		if ctx.Err() != nil {
+			return w.goingAway()
+		}
+
+		if wr.ping {
+			w.startPing(wr.done)
+			continue
+		}
+
+		err := w.write(ctx, wr)
+		if wr.done != nil {
+			wr.done <- err
+		}
+		if err != nil {
+			_ = w.c.Close(websocket.StatusInternalError, newCloseHint(websocket.StatusInternalError, "write failed").Encode())
+			return err
+		}
+	}
+}
+
+// startPing sends a ping, leaving its pong to be waited on apart from the
+// writes, so that a client slow to answer doesn't hold them up. nhooyr's Conn
+// allows a ping alongside other writes. There's no timeout: a ping which times
+// out closes the connection without a word, so keepalive decides when the
+// client has taken too long, and the wait ends once the connection is closed.
+func (w *connWriter) startPing(done chan error) {
+	go func() {
+		done <- w.c.Ping(context.Background())
+	}()
+}
+
+func (w *connWriter) write(ctx context.Context, wr write) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	if wr.note.HasTimer() {
+		sent := w.now()
+		wr.note.Sent = &sent
+	}
+
+	buf := noteBuffers.Get().(*bytes.Buffer)
+	defer func() {
+		buf.Reset()
+		noteBuffers.Put(buf)
+	}()
+
+	if _, err := wr.note.WriteTo(buf); err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	// A single write, so the message is framed and compressed as it would be
+	// by wsjson.
+	mw, err := w.c.Writer(ctx, websocket.MessageText)
+	if err != nil {
+		return err
+	}
+	if _, err := mw.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	if err := mw.Close(); err != nil {
+		return err
+	}
+
+	metricSent.Inc()
+	return nil
+}
+
+// goingAway closes the connection once ctx is canceled. A close queued along
+// with the cancel, like a closing room's, is sent in place of the generic one,
+// so the client learns why.
+func (w *connWriter) goingAway() error {
+	select {
+	case wr := <-w.queues[priorityClose]:
+		return w.closeWith(wr)
+	default:
+		return w.c.Close(websocket.StatusGoingAway, newCloseHint(websocket.StatusGoingAway, "going away").Encode())
+	}
+}
+
+func (w *connWriter) closeWith(wr write) error {
+	w.mu.Lock()
+	w.closed = wr.code
+	w.mu.Unlock()
+	return w.c.Close(wr.code, wr.reason)
+}
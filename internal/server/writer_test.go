@@ -0,0 +1,415 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"gotest.tools/v3/assert"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+)
+
+// dialTestWriter connects to a server whose only job is to hand out a
+// connWriter. The writer isn't running; start is called to run it.
+func dialTestWriter(t *testing.T) (w *connWriter, start func() <-chan error, c *websocket.Conn) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	writers := make(chan *connWriter, 1)
+	done := make(chan struct{})
+
+	hs := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		defer close(done)
+
+		c, err := websocket.Accept(rw, r, testAcceptOptions)
+		if err != nil {
+			return
+		}
+
+		// Pings need a concurrent reader for their pongs.
+		ctx := c.CloseRead(ctx)
+
+		writers <- newConnWriter(c)
+		<-ctx.Done()
+	}))
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		hs.Close()
+	})
+
+	url := "ws" + strings.TrimPrefix(hs.URL, "http")
+	c, _, err := websocket.Dial(ctx, url, &websocket.DialOptions{
+		CompressionMode: websocket.CompressionContextTakeover,
+	})
+	assert.NilError(t, err)
+	t.Cleanup(func() { c.Close(websocket.StatusNormalClosure, "") })
+
+	w = <-writers
+	start = func() <-chan error {
+		errc := make(chan error, 1)
+		go func() { errc <- w.run(ctx) }()
+		return errc
+	}
+
+	return w, start, c
+}
+
+func testWriterNote(method string) protocol.ServerNote {
+	return protocol.ServerNote{Method: protocol.ServerMethod(method)}
+}
+
+func readMethod(t *testing.T, c *websocket.Conn) (protocol.ServerMethod, error) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var note protocol.ServerNote
+	err := wsjson.Read(ctx, c, &note)
+	return note.Method, err
+}
+
+func TestConnWriterPriority(t *testing.T) {
+	w, start, c := dialTestWriter(t)
+
+	w.send(priorityBroadcast, testWriterNote("broadcast1"))
+	w.send(priorityTargeted, testWriterNote("targeted"))
+	w.send(priorityBroadcast, testWriterNote("broadcast2"))
+	w.send(priorityAck, testWriterNote("ack"))
+	start()
+
+	for _, want := range []string{"ack", "targeted", "broadcast1", "broadcast2"} {
+		method, err := readMethod(t, c)
+		assert.NilError(t, err)
+		assert.Equal(t, string(method), want)
+	}
+}
+
+func TestConnWriterCloseDropsPending(t *testing.T) {
+	w, start, c := dialTestWriter(t)
+
+	w.send(priorityBroadcast, testWriterNote("broadcast"))
+	w.send(priorityTargeted, testWriterNote("targeted"))
+	w.close(closeNicknameTaken, "closed")
+	errc := start()
+
+	_, err := readMethod(t, c)
+	assert.Equal(t, websocket.CloseStatus(err), closeNicknameTaken)
+	<-errc
+
+	// Writes after the close are dropped without blocking.
+	w.send(priorityBroadcast, testWriterNote("late"))
+	assert.Assert(t, errors.Is(w.sendWait(context.Background(), priorityTargeted, testWriterNote("late")), errWriterClosed))
+}
+
+func TestConnWriterSendWait(t *testing.T) {
+	w, start, c := dialTestWriter(t)
+	start()
+
+	go func() {
+		for {
+			if _, err := readMethod(t, c); err != nil {
+				return
+			}
+		}
+	}()
+
+	assert.NilError(t, w.sendWait(context.Background(), priorityTargeted, testWriterNote("targeted")))
+	assert.NilError(t, w.ping(context.Background()))
+}
+
+func TestConnWriterStress(t *testing.T) {
+	w, start, c := dialTestWriter(t)
+	errc := start()
+
+	received := make(chan error, 1)
+	go func() {
+		for {
+			if _, err := readMethod(t, c); err != nil {
+				received <- err
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				w.send(priorityBroadcast, testWriterNote("broadcast"))
+				if j%10 == 0 {
+					w.send(priorityTargeted, testWriterNote("targeted"))
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				_ = w.ping(context.Background())
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		time.Sleep(10 * time.Millisecond)
+		w.close(websocket.StatusNormalClosure, "done")
+		w.close(websocket.StatusNormalClosure, "done again")
+	}()
+
+	wg.Wait()
+
+	select {
+	case <-errc:
+	case <-time.After(5 * time.Second):
+		t.Fatal("writer did not stop")
+	}
+
+	err := <-received
+	assert.Assert(t, err != nil)
+}
+
+func testStateNote(n int) protocol.ServerNote {
+	return protocol.ServerNote{Method: "state", Params: map[string]int{"n": n}}
+}
+
+func TestConnWriterDegrades(t *testing.T) {
+	w, start, c := dialTestWriter(t)
+
+	var resyncs int32
+	w.resync = func() {
+		atomic.AddInt32(&resyncs, 1)
+		w.send(priorityBroadcast, testStateNote(-1))
+	}
+
+	for i := 0; i < queueHighWatermark; i++ {
+		w.send(priorityBroadcast, testWriterNote("broadcast"))
+	}
+
+	// The queue is at the high watermark; later broadcasts are coalesced.
+	w.send(priorityBroadcast, testStateNote(1))
+	w.send(priorityBroadcast, testWriterNote("playerJoined"))
+	w.send(priorityBroadcast, testStateNote(2))
+	start()
+
+	read := func() (string, int) {
+		t.Helper()
+		n := map[string]int{}
+		method := nextNote(t, c, &n)
+		return string(method), n["n"]
+	}
+
+	method, _ := read()
+	assert.Equal(t, method, "qualityDegraded")
+
+	for i := 0; i < queueHighWatermark-queueLowWatermark; i++ {
+		method, _ := read()
+		assert.Equal(t, method, "broadcast")
+	}
+
+	// Drained to the low watermark; the rest of the queue, then the latest
+	// state, then a fresh state for what was dropped.
+	method, _ = read()
+	assert.Equal(t, method, "qualityRestored")
+
+	for i := 0; i < queueLowWatermark; i++ {
+		method, _ := read()
+		assert.Equal(t, method, "broadcast")
+	}
+
+	method, n := read()
+	assert.Equal(t, method, "state")
+	assert.Equal(t, n, 2)
+
+	method, n = read()
+	assert.Equal(t, method, "state")
+	assert.Equal(t, n, -1)
+	assert.Equal(t, atomic.LoadInt32(&resyncs), int32(1))
+}
+
+func TestConnWriterDegradedStatesOnly(t *testing.T) {
+	w, start, c := dialTestWriter(t)
+
+	var resyncs int32
+	w.resync = func() { atomic.AddInt32(&resyncs, 1) }
+
+	for i := 0; i < queueHighWatermark; i++ {
+		w.send(priorityBroadcast, testWriterNote("broadcast"))
+	}
+	for i := 0; i < 100; i++ {
+		w.send(priorityBroadcast, testStateNote(i))
+	}
+	start()
+
+	readNote(t, c, "qualityRestored", nil)
+
+	for {
+		n := map[string]int{}
+		if method := nextNote(t, c, &n); method == "state" {
+			assert.Equal(t, n["n"], 99)
+			break
+		}
+	}
+
+	// Only states were coalesced, and the latest went out, so there's
+	// nothing to resync.
+	assert.Equal(t, atomic.LoadInt32(&resyncs), int32(0))
+}
+
+func TestDegradedClientConverges(t *testing.T) {
+	r := newTestRoom(t)
+	w, start, c := dialTestWriter(t)
+	w.resync = func() { r.resync("slow") }
+
+	r.hold(func() {
+		r.join("slow", w.send, ConnOptions{Nickname: "slow", Deltas: true})
+	})
+
+	// Far more roster deltas than the queue holds.
+	for i := 0; i < 2*writerQueueSize; i++ {
+		r.joinTestClient(t, game.PlayerID("p"+strconv.Itoa(i)), false)
+	}
+	start()
+
+	var view roster
+	degraded, restored := false, false
+
+	for !restored || view.version != r.version() {
+		method, raw := readRawNote(t, c)
+
+		switch method {
+		case "qualityDegraded":
+			degraded = true
+			continue
+		case "qualityRestored":
+			restored = true
+			continue
+		}
+
+		note := protocol.ServerNote{Method: method}
+		switch method {
+		case "state":
+			note.Params = &protocol.State{}
+		case "playerJoined":
+			note.Params = &protocol.PlayerJoined{}
+		default:
+			continue
+		}
+		assert.NilError(t, json.Unmarshal(raw, note.Params))
+		view.apply(t, []protocol.ServerNote{note})
+	}
+
+	assert.Assert(t, degraded)
+	r.hold(func() {
+		assert.DeepEqual(t, view.teams, r.currentState().guesser.Teams)
+	})
+}
+
+func TestSlowClientEvicted(t *testing.T) {
+	r := newTestRoom(t)
+	fast := r.joinTestClient(t, "fast", false)
+
+	// The writer isn't running, as if the client had stopped reading.
+	w, start, c := dialTestWriter(t)
+	r.hold(func() {
+		r.join("slow", w.send, ConnOptions{Nickname: "slow"})
+	})
+
+	evicted := testutil.ToFloat64(metricSlowEvicted)
+
+	// Its acks can't be coalesced like broadcasts, so its queue overflows,
+	// yet the room carries on without waiting for it.
+	for i := 0; i < 2*writerQueueSize; i++ {
+		cmdID := strconv.Itoa(i)
+		r.testCommand(t, "slow", cmdID, r.version(), protocol.ChangeTeamMethod, &protocol.ChangeTeamParams{Team: game.Team(i % 2)})
+		r.testCommand(t, "fast", cmdID, r.version(), protocol.ChangeTeamMethod, &protocol.ChangeTeamParams{Team: game.Team(i % 2)})
+		assert.Equal(t, fast.lastState().RoomState.Version, r.version())
+	}
+	assert.Equal(t, len(fast.acks()), 2*writerQueueSize)
+
+	// Closed once, however many writes overflowed.
+	assert.Equal(t, testutil.ToFloat64(metricSlowEvicted), evicted+1)
+
+	start()
+	code, hint := readCloseHint(t, c)
+	assert.Equal(t, code, closeTooSlow)
+	assert.DeepEqual(t, hint, protocol.CloseHint{Retry: true, AfterMS: 1000, Reason: "too slow"})
+}
+
+const broadcastSize = 40
+
+// BenchmarkBroadcast measures encoding a state broadcast to a full room, as
+// each connection's writer would, against encoding the whole note for each
+// player as wsjson did.
+func BenchmarkBroadcast(b *testing.B) {
+	encoders := []struct {
+		name   string
+		encode func(*bytes.Buffer, *protocol.ServerNote) error
+	}{
+		{"once", func(buf *bytes.Buffer, note *protocol.ServerNote) error {
+			_, err := note.WriteTo(buf)
+			return err
+		}},
+		{"perPlayer", func(buf *bytes.Buffer, note *protocol.ServerNote) error {
+			return json.NewEncoder(buf).Encode(note)
+		}},
+	}
+
+	for _, e := range encoders {
+		encode := e.encode
+		b.Run(e.name, func(b *testing.B) {
+			r := newTestRoom(b)
+
+			var buf bytes.Buffer
+			r.hold(func() {
+				for i := 0; i < broadcastSize; i++ {
+					id := game.PlayerID("p" + strconv.Itoa(i))
+					r.players[id] = func(_ priority, note protocol.ServerNote) {
+						buf.Reset()
+						if err := encode(&buf, &note); err != nil {
+							b.Fatal(err)
+						}
+					}
+					r.room.AddPlayer(id, string(id))
+					if i < 4 {
+						_ = r.room.ChangeRole(id, true)
+					}
+				}
+			})
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				r.hold(func() {
+					r.state = nil
+					r.sendAll()
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+// Package suggest offers clue suggestions to spymasters from a precomputed
+// table of word associations.
+package suggest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/zikaeroh/codies/internal/pkger"
+)
+
+// Default is the bundled association table.
+var Default = load("/associations.txt")
+
+func load(filename string) *Table {
+	f, err := pkger.Dir("/internal/suggest/data").Open(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	t, err := Parse(f)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Table maps board words to related clue words.
+type Table struct {
+	related map[string][]string // Most related first.
+}
+
+// Parse reads a table where each line is a board word, a colon, and a comma
+// separated list of related words, most related first. Blank lines and lines
+// starting with # are ignored. Words are case-insensitive.
+func Parse(r io.Reader) (*Table, error) {
+	t := &Table{related: make(map[string][]string)}
+	scanner := bufio.NewScanner(r)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		i := strings.IndexByte(text, ':')
+		if i < 0 {
+			return nil, fmt.Errorf("line %d: missing colon", line)
+		}
+
+		word := normalize(text[:i])
+		if word == "" {
+			return nil, fmt.Errorf("line %d: missing word", line)
+		}
+		if _, ok := t.related[word]; ok {
+			return nil, fmt.Errorf("line %d: duplicate word %s", line, word)
+		}
+
+		var related []string
+		seen := map[string]bool{word: true}
+		for _, r := range strings.Split(text[i+1:], ",") {
+			r = normalize(r)
+			if r == "" || seen[r] {
+				continue
+			}
+			seen[r] = true
+			related = append(related, r)
+		}
+		t.related[word] = related
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func normalize(s string) string {
+	return strings.ToUpper(strings.TrimSpace(s))
+}
+
+// Board is the spymaster's view of the board.
+type Board struct {
+	Targets []string // Unrevealed words of the spymaster's team.
+	Avoid   []string // Unrevealed opponent and neutral words.
+	Bombs   []string // Unrevealed bombs.
+	All     []string // Every word on the board, revealed or not.
+}
+
+// Suggestion is a candidate clue.
+type Suggestion struct {
+	Word  string
+	Count int // The number of target words the clue relates to.
+}
+
+// Suggest returns up to n clues for the board, best first. Clues which relate
+// to a bomb or which are (or contain, or are contained in) a board word are
+// never suggested; clues relating to more words to avoid than targets are
+// dropped.
+func (t *Table) Suggest(b Board, n int) []Suggestion {
+	type candidate struct {
+		word  string
+		count int
+		avoid int
+		bomb  bool
+		rank  int // Lower is more related.
+	}
+
+	candidates := make(map[string]*candidate)
+	for _, target := range b.Targets {
+		for rank, word := range t.related[normalize(target)] {
+			c := candidates[word]
+			if c == nil {
+				c = &candidate{word: word}
+				candidates[word] = c
+			}
+			c.count++
+			c.rank += rank
+		}
+	}
+
+	mark := func(words []string, fn func(*candidate)) {
+		for _, w := range words {
+			for _, word := range t.related[normalize(w)] {
+				if c := candidates[word]; c != nil {
+					fn(c)
+				}
+			}
+		}
+	}
+
+	mark(b.Avoid, func(c *candidate) { c.avoid++ })
+	mark(b.Bombs, func(c *candidate) { c.bomb = true })
+
+	list := make([]*candidate, 0, len(candidates))
+	for _, c := range candidates {
+		if c.bomb || c.count-c.avoid < 1 || onBoard(c.word, b.All) {
+			continue
+		}
+		list = append(list, c)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.count-a.avoid != b.count-b.avoid {
+			return a.count-a.avoid > b.count-b.avoid
+		}
+		if a.rank != b.rank {
+			return a.rank < b.rank
+		}
+		return a.word < b.word
+	})
+
+	if len(list) > n {
+		list = list[:n]
+	}
+
+	suggestions := make([]Suggestion, len(list))
+	for i, c := range list {
+		suggestions[i] = Suggestion{Word: c.word, Count: c.count}
+	}
+	return suggestions
+}
+
+// onBoard reports whether a clue would be invalid because it overlaps with a
+// word on the board.
+func onBoard(clue string, all []string) bool {
+	for _, w := range all {
+		w = normalize(w)
+		if strings.Contains(w, clue) || strings.Contains(clue, w) {
+			return true
+		}
+	}
+	return false
+}
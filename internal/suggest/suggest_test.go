@@ -0,0 +1,70 @@
+package suggest
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+const testTable = `
+# Comment.
+LION: CAT, SAFARI, KING
+TIGER: cat, stripes, Safari
+CROWN: KING, JEWEL
+BOMB: EXPLOSION, STRIPES
+`
+
+func TestParse(t *testing.T) {
+	table, err := Parse(strings.NewReader(testTable))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, table.related["TIGER"], []string{"CAT", "STRIPES", "SAFARI"})
+
+	_, err = Parse(strings.NewReader("LION CAT"))
+	assert.ErrorContains(t, err, "line 1: missing colon")
+
+	_, err = Parse(strings.NewReader("LION: CAT\nlion: KING"))
+	assert.ErrorContains(t, err, "line 2: duplicate word LION")
+}
+
+func TestSuggest(t *testing.T) {
+	table, err := Parse(strings.NewReader(testTable))
+	assert.NilError(t, err)
+
+	b := Board{
+		Targets: []string{"LION", "TIGER"},
+		Avoid:   []string{"CROWN"},
+		Bombs:   []string{"BOMB"},
+		All:     []string{"LION", "TIGER", "CROWN", "BOMB"},
+	}
+
+	// STRIPES relates to the bomb, KING to a word to avoid.
+	assert.DeepEqual(t, table.Suggest(b, 3), []Suggestion{
+		{Word: "CAT", Count: 2},
+		{Word: "SAFARI", Count: 2},
+	})
+	assert.DeepEqual(t, table.Suggest(b, 1), []Suggestion{{Word: "CAT", Count: 2}})
+}
+
+func TestSuggestNeverBoardWord(t *testing.T) {
+	table, err := Parse(strings.NewReader(testTable))
+	assert.NilError(t, err)
+
+	b := Board{
+		Targets: []string{"LION"},
+		All:     []string{"LION", "CATFISH", "KING"},
+	}
+
+	assert.DeepEqual(t, table.Suggest(b, 3), []Suggestion{{Word: "SAFARI", Count: 1}})
+}
+
+func TestDefault(t *testing.T) {
+	b := Board{
+		Targets: []string{"Jupiter", "Saturn"},
+		All:     []string{"Jupiter", "Saturn"},
+	}
+
+	suggestions := Default.Suggest(b, 3)
+	assert.Assert(t, len(suggestions) > 0)
+	assert.Equal(t, suggestions[0].Count, 2)
+}
@@ -0,0 +1,140 @@
+package version
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Staleness buckets a client's claimed version relative to the running one.
+// The set is fixed, so it's safe to use as a metric label.
+type Staleness string
+
+const (
+	Current  Staleness = "current"
+	Previous Staleness = "previous" // The release just before this one.
+	Older    Staleness = "older"
+	Newer    Staleness = "newer" // Possible while a rollout is in progress.
+	Unknown  Staleness = "unknown"
+)
+
+// Classify compares a version claimed by a client against Version.
+func Classify(claimed string) Staleness {
+	return classify(claimed, version)
+}
+
+func classify(claimed, current string) Staleness {
+	if claimed != "" && claimed == current {
+		return Current
+	}
+
+	got, ok := parse(claimed)
+	if !ok {
+		return Unknown
+	}
+
+	want, ok := parse(current)
+	if !ok {
+		return Unknown
+	}
+
+	switch cmp := compare(got, want); {
+	case cmp == 0:
+		// Same release, different build metadata.
+		return Current
+	case cmp > 0:
+		return Newer
+	case isPrevious(got, want):
+		return Previous
+	default:
+		return Older
+	}
+}
+
+type semver [3]int
+
+// parse accepts MAJOR.MINOR.PATCH, with an optional leading "v" and any
+// pre-release or build suffix (like that added by git describe) ignored.
+func parse(s string) (semver, bool) {
+	s = strings.TrimPrefix(s, "v")
+	if i := strings.IndexAny(s, "-+"); i >= 0 {
+		s = s[:i]
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return semver{}, false
+	}
+
+	var v semver
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return semver{}, false
+		}
+		v[i] = n
+	}
+	return v, true
+}
+
+func compare(a, b semver) int {
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// isPrevious reports whether got could be the release immediately before want.
+// Without a list of releases this is a guess: a lower patch of the same minor
+// release, or for a new minor (or major) release, anything in the minor (or
+// major) release before it.
+func isPrevious(got, want semver) bool {
+	switch {
+	case want[2] > 0:
+		return got[0] == want[0] && got[1] == want[1] && got[2] == want[2]-1
+	case want[1] > 0:
+		return got[0] == want[0] && got[1] == want[1]-1
+	default:
+		return got[0] == want[0]-1
+	}
+}
+
+// maxCleanLength is the longest version Clean accepts.
+const maxCleanLength = 64
+
+// Clean returns a version claimed by a client if it's in the format releases
+// are versioned with, as parse accepts it, with a suffix of only letters,
+// digits, dots, dashes, and pluses. The running version and "(devel)" are
+// always accepted, whatever their format. Anything else returns "", so that
+// claims are only logged or kept once they're known to be versions.
+func Clean(claimed string) string {
+	return clean(claimed, version)
+}
+
+func clean(claimed, current string) string {
+	if claimed == "" || len(claimed) > maxCleanLength {
+		return ""
+	}
+
+	if claimed == current || claimed == devel {
+		return claimed
+	}
+
+	if _, ok := parse(claimed); !ok {
+		return ""
+	}
+
+	for _, r := range claimed {
+		switch {
+		case r >= '0' && r <= '9', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case r == '.', r == '-', r == '+':
+		default:
+			return ""
+		}
+	}
+	return claimed
+}
@@ -0,0 +1,68 @@
+package version
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		claimed string
+		current string
+		want    Staleness
+	}{
+		{"v1.4.2", "v1.4.2", Current},
+		{"1.4.2", "v1.4.2", Current},
+		{"v1.4.2-3-gabcdef", "v1.4.2", Current},
+		{"v1.4.1", "v1.4.2", Previous},
+		{"v1.4.0", "v1.4.2", Older},
+		{"v1.3.9", "v1.4.2", Older},
+		{"v1.3.7", "v1.4.0", Previous},
+		{"v1.2.7", "v1.4.0", Older},
+		{"v1.9.3", "v2.0.0", Previous},
+		{"v0.9.3", "v2.0.0", Older},
+		{"v1.4.3", "v1.4.2", Newer},
+		{"v2.0.0", "v1.4.2", Newer},
+		{"", "v1.4.2", Unknown},
+		{"(devel)", "v1.4.2", Unknown},
+		{"abcdef0", "v1.4.2", Unknown},
+		{"v1.4", "v1.4.2", Unknown},
+		{"v1.x.2", "v1.4.2", Unknown},
+		{"v1.4.1", "", Unknown},
+		{"v1.4.1", "(devel)", Unknown},
+		{"abcdef0", "abcdef0", Current},
+	}
+
+	for _, test := range tests {
+		got := classify(test.claimed, test.current)
+		assert.Equal(t, got, test.want, "claimed %q, current %q", test.claimed, test.current)
+	}
+}
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		claimed string
+		current string
+		want    string
+	}{
+		{"v1.4.2", "v1.4.2", "v1.4.2"},
+		{"1.4.1", "v1.4.2", "1.4.1"},
+		{"v1.4.2-3-gabcdef", "v1.4.2", "v1.4.2-3-gabcdef"},
+		{"v1.4.2+build.7", "v1.4.2", "v1.4.2+build.7"},
+		{"(devel)", "v1.4.2", "(devel)"},
+		{"abcdef0", "abcdef0", "abcdef0"},
+		{"abcdef0", "v1.4.2", ""},
+		{"", "", ""},
+		{"v1.4", "v1.4.2", ""},
+		{"v1.4.2-<script>", "v1.4.2", ""},
+		{"v1.4.2-\n", "v1.4.2", ""},
+		{"v1.4.2-" + strings.Repeat("a", maxCleanLength), "v1.4.2", ""},
+	}
+
+	for _, test := range tests {
+		got := clean(test.claimed, test.current)
+		assert.Equal(t, got, test.want, "claimed %q, current %q", test.claimed, test.current)
+	}
+}
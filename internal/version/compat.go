@@ -0,0 +1,57 @@
+package version
+
+import "time"
+
+// Acceptance is the result of a compatibility check.
+type Acceptance int
+
+const (
+	Rejected Acceptance = iota
+	Exact               // The client runs this version.
+	Accepted            // The client runs an explicitly accepted version.
+	Grace               // The client runs another version, but the server only just started.
+)
+
+// OK returns true if the client should be allowed through.
+func (a Acceptance) OK() bool {
+	return a != Rejected
+}
+
+// Compat describes which client versions are compatible with the server,
+// to smooth over deploys where clients and servers briefly disagree.
+type Compat struct {
+	// Accept lists versions accepted in addition to Version.
+	Accept []string
+
+	// Any version is accepted for Grace after Start.
+	Start time.Time
+	Grace time.Duration
+}
+
+// Check checks a version claimed by a client at the given time.
+func (c *Compat) Check(claimed string, now time.Time) Acceptance {
+	return c.check(claimed, Version(), now)
+}
+
+func (c *Compat) check(claimed, current string, now time.Time) Acceptance {
+	if claimed == current {
+		return Exact
+	}
+
+	for _, v := range c.Accept {
+		if v != "" && claimed == v {
+			return Accepted
+		}
+	}
+
+	if c.InGrace(now) {
+		return Grace
+	}
+
+	return Rejected
+}
+
+// InGrace returns true if now is within the grace period after start.
+func (c *Compat) InGrace(now time.Time) bool {
+	return c.Grace > 0 && now.Before(c.Start.Add(c.Grace))
+}
@@ -0,0 +1,65 @@
+package version
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCompatAccept(t *testing.T) {
+	c := &Compat{Accept: []string{"v1.4.1", ""}}
+	now := time.Now()
+
+	tests := []struct {
+		claimed string
+		want    Acceptance
+	}{
+		{"v1.4.2", Exact},
+		{"v1.4.1", Accepted},
+		{"v1.4.0", Rejected},
+		{"v1.4.3", Rejected},
+		{"", Rejected},
+	}
+
+	for _, test := range tests {
+		got := c.check(test.claimed, "v1.4.2", now)
+		assert.Equal(t, got, test.want, "claimed %q", test.claimed)
+		assert.Equal(t, got.OK(), test.want != Rejected)
+	}
+}
+
+func TestCompatGrace(t *testing.T) {
+	start := time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+	c := &Compat{Accept: []string{"v1.4.1"}, Start: start, Grace: 2 * time.Minute}
+
+	tests := []struct {
+		claimed string
+		at      time.Duration
+		want    Acceptance
+	}{
+		{"v1.4.0", 0, Grace},
+		{"v1.4.0", time.Minute, Grace},
+		{"", time.Minute, Grace},
+		{"v1.4.0", 2 * time.Minute, Rejected},
+		{"v1.4.0", time.Hour, Rejected},
+
+		// Exact and listed versions take precedence, and outlast the grace period.
+		{"v1.4.2", time.Minute, Exact},
+		{"v1.4.1", time.Minute, Accepted},
+		{"v1.4.1", time.Hour, Accepted},
+	}
+
+	for _, test := range tests {
+		got := c.check(test.claimed, "v1.4.2", start.Add(test.at))
+		assert.Equal(t, got, test.want, "claimed %q at %v", test.claimed, test.at)
+	}
+}
+
+func TestCompatNoGrace(t *testing.T) {
+	start := time.Now()
+	c := &Compat{Start: start}
+
+	assert.Assert(t, !c.InGrace(start))
+	assert.Equal(t, c.check("v1.4.0", "v1.4.2", start), Rejected)
+}
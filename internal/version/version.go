@@ -3,10 +3,13 @@ package version
 
 var version string
 
+// devel is the version of builds without one.
+const devel = "(devel)"
+
 // Version returns a compile time version string, or "(devel)" if unset.
 func Version() string {
 	if version == "" {
-		return "(devel)"
+		return devel
 	}
 	return version
 }
@@ -15,3 +18,10 @@ func Version() string {
 func IsSet() bool {
 	return version != ""
 }
+
+var buildTime string
+
+// BuildTime returns the compile time build timestamp, or "" if unset.
+func BuildTime() string {
+	return buildTime
+}
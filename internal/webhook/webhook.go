@@ -0,0 +1,203 @@
+// Package webhook posts the results of finished games to URLs set by room
+// hosts, so that they can be announced elsewhere, like in a chat channel.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/netguard"
+)
+
+const (
+	// MaxURLLength is the longest URL a webhook may have.
+	MaxURLLength = 2048
+
+	// Timeout bounds each delivery attempt.
+	Timeout = 5 * time.Second
+
+	retryDelay = time.Second
+)
+
+var (
+	ErrURL        = errors.New("webhook: URL must be http or https")
+	ErrNotAllowed = errors.New("webhook: URL's host is not allowed")
+	ErrAddress    = errors.New("webhook: address is not public")
+)
+
+// StatusError is returned when the webhook's server responds with anything
+// but a 2xx status.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("webhook: delivery returned %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Payload is the JSON body posted when a game finishes. Wins tallies the
+// games won in the room, by team, including this one; tracker games also
+// have the teams' final Scores.
+type Payload struct {
+	Room     string    `json:"room"`
+	Winner   int       `json:"winner"`
+	Reason   string    `json:"reason"`
+	Seconds  int64     `json:"seconds"`
+	Wins     []int     `json:"wins"`
+	Scores   []int     `json:"scores,omitempty"`
+	Finished time.Time `json:"finished"`
+}
+
+// Sender delivers payloads. It's safe for concurrent use.
+type Sender struct {
+	client     *http.Client
+	allow      []string
+	retryDelay time.Duration
+}
+
+// NewSender creates a sender. If allow isn't empty, webhooks may only be sent
+// to its hosts and their subdomains. If client is nil, one is used which times
+// out and only connects to public addresses, so that a webhook can't be used
+// to reach the server's own network.
+func NewSender(client *http.Client, allow []string) *Sender {
+	if client == nil {
+		dialer := &net.Dialer{Timeout: Timeout, Control: netguard.Control}
+		client = &http.Client{
+			Timeout: Timeout,
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: Timeout,
+			},
+			// A redirect could lead anywhere, past the allowlist.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+	}
+
+	hosts := make([]string, 0, len(allow))
+	for _, h := range allow {
+		if h = strings.Trim(strings.ToLower(strings.TrimSpace(h)), "."); h != "" {
+			hosts = append(hosts, h)
+		}
+	}
+
+	return &Sender{
+		client:     client,
+		allow:      hosts,
+		retryDelay: retryDelay,
+	}
+}
+
+// Check returns an error if rawURL can't be used for a webhook.
+func (s *Sender) Check(rawURL string) error {
+	_, err := s.parse(rawURL)
+	return err
+}
+
+func (s *Sender) parse(rawURL string) (*url.URL, error) {
+	if len(rawURL) > MaxURLLength {
+		return nil, ErrURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return nil, ErrURL
+	}
+
+	if !s.allowed(strings.ToLower(u.Hostname())) {
+		return nil, ErrNotAllowed
+	}
+	return u, nil
+}
+
+func (s *Sender) allowed(host string) bool {
+	if len(s.allow) == 0 {
+		return true
+	}
+
+	for _, a := range s.allow {
+		if host == a || strings.HasSuffix(host, "."+a) {
+			return true
+		}
+	}
+	return false
+}
+
+// Send posts the payload to rawURL, trying once more if the first attempt
+// fails in a way which might not happen again. It blocks until it's done, so
+// callers which don't wait for the result should run it in a goroutine.
+func (s *Sender) Send(ctx context.Context, rawURL string, p *Payload) error {
+	u, err := s.parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	err = s.post(ctx, u.String(), body)
+	if !retryable(err) {
+		return err
+	}
+
+	select {
+	case <-time.After(s.retryDelay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return s.post(ctx, u.String(), body)
+}
+
+func (s *Sender) post(ctx context.Context, rawURL string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return ErrURL
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		if errors.Is(err, netguard.ErrPrivate) {
+			return ErrAddress
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 4<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{StatusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+// retryable returns true for errors which a second attempt might not get:
+// anything but a refused address or a client error, other than being rate
+// limited.
+func retryable(err error) bool {
+	if err == nil || errors.Is(err, ErrAddress) || errors.Is(err, ErrURL) {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 || statusErr.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
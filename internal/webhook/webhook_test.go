@@ -0,0 +1,122 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/atomic"
+	"gotest.tools/v3/assert"
+)
+
+var testPayload = &Payload{
+	Room:     "room",
+	Winner:   1,
+	Reason:   "bomb",
+	Seconds:  90,
+	Wins:     []int{0, 1},
+	Finished: time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC),
+}
+
+// newWebhookServer serves webhooks which respond with each of statuses in
+// turn, then 204.
+func newWebhookServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int64, chan *Payload) {
+	t.Helper()
+
+	var hits atomic.Int64
+	received := make(chan *Payload, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(hits.Inc())
+
+		p := &Payload{}
+		if err := json.NewDecoder(r.Body).Decode(p); err != nil || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		received <- p
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits, received
+}
+
+func newTestSender(srv *httptest.Server, allow ...string) *Sender {
+	s := NewSender(srv.Client(), allow)
+	s.retryDelay = 0
+	return s
+}
+
+func TestSend(t *testing.T) {
+	srv, hits, received := newWebhookServer(t)
+
+	assert.NilError(t, newTestSender(srv).Send(context.Background(), srv.URL+"/hook", testPayload))
+	assert.Equal(t, hits.Load(), int64(1))
+	assert.DeepEqual(t, <-received, testPayload)
+}
+
+func TestSendRetry(t *testing.T) {
+	srv, hits, received := newWebhookServer(t, http.StatusBadGateway)
+
+	assert.NilError(t, newTestSender(srv).Send(context.Background(), srv.URL, testPayload))
+	assert.Equal(t, hits.Load(), int64(2))
+	<-received
+}
+
+func TestSendRetriesOnce(t *testing.T) {
+	srv, hits, _ := newWebhookServer(t, http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+
+	err := newTestSender(srv).Send(context.Background(), srv.URL, testPayload)
+	assert.DeepEqual(t, err, &StatusError{StatusCode: http.StatusServiceUnavailable})
+	assert.Equal(t, hits.Load(), int64(2))
+}
+
+func TestSendNoRetryClientError(t *testing.T) {
+	srv, hits, _ := newWebhookServer(t, http.StatusNotFound)
+
+	err := newTestSender(srv).Send(context.Background(), srv.URL, testPayload)
+	assert.DeepEqual(t, err, &StatusError{StatusCode: http.StatusNotFound})
+	assert.Equal(t, hits.Load(), int64(1))
+}
+
+func TestSendRefusesPrivate(t *testing.T) {
+	srv, hits, _ := newWebhookServer(t)
+
+	s := NewSender(nil, nil)
+	s.retryDelay = 0
+	assert.Equal(t, s.Send(context.Background(), srv.URL, testPayload), ErrAddress)
+	assert.Equal(t, hits.Load(), int64(0))
+}
+
+func TestCheck(t *testing.T) {
+	open := NewSender(nil, nil)
+	allow := NewSender(nil, []string{" Discord.com ", "", "hooks.example.org."})
+
+	tests := []struct {
+		url         string
+		open, allow error
+	}{
+		{"https://discord.com/api/webhooks/1/abc", nil, nil},
+		{"https://canary.discord.com/api/webhooks/1/abc", nil, nil},
+		{"http://HOOKS.example.org:8080/x", nil, nil},
+		{"https://example.org/x", nil, ErrNotAllowed},
+		{"https://notdiscord.com/x", nil, ErrNotAllowed},
+		{"ftp://discord.com/x", ErrURL, ErrURL},
+		{"discord.com/x", ErrURL, ErrURL},
+		{"https:///x", ErrURL, ErrURL},
+		{"https://discord.com/" + strings.Repeat("x", MaxURLLength), ErrURL, ErrURL},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, open.Check(test.url), test.open, test.url)
+		assert.Equal(t, allow.Check(test.url), test.allow, test.url)
+	}
+}
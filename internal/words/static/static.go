@@ -5,14 +5,21 @@ import (
 	"github.com/zikaeroh/codies/internal/words"
 )
 
+// Dir holds the built-in packs: a directory for each language, named by its
+// code, of files which are each a pack. See packs.NewRegistry.
+var Dir = pkger.Dir("/internal/words/static/codenames")
+
+// DefaultLanguage is the language of Default, Duet, and Undercover.
+const DefaultLanguage = "en"
+
 var (
-	Default    = load("/default.txt")
-	Duet       = load("/duet.txt")
-	Undercover = load("/undercover.txt")
+	Default    = load("/en/Base.txt")
+	Duet       = load("/en/Duet.txt")
+	Undercover = load("/en/Undercover.txt")
 )
 
 func load(filename string) words.List {
-	f, err := pkger.Dir("/internal/words/static/codenames").Open(filename)
+	f, err := Dir.Open(filename)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/zikaeroh/codies/internal/adminapi"
+	"github.com/zikaeroh/codies/internal/idgen"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxLogEntries is the number of warnings and errors kept for /admin/overview.
+const maxLogEntries = 20
+
+// redacted replaces sensitive field values in the log ring.
+const redacted = "[redacted]"
+
+// sensitiveKeys are substrings of field names whose values never appear in
+// the log ring.
+var sensitiveKeys = []string{"token", "password", "secret", "authorization", "cookie"}
+
+// identifyingKeys are fields which name a room or a player. With
+// --privacy-strict, the log ring only keeps salted hashes of them.
+var identifyingKeys = map[string]bool{
+	"roomID":       true,
+	"roomName":     true,
+	"targetRoomID": true,
+	"playerID":     true,
+	"nickname":     true,
+	"mirrorID":     true,
+	"spectatorID":  true,
+}
+
+// roomKeys are the identifying fields matched when a room is purged.
+var roomKeys = []string{"roomID", "roomName", "targetRoomID"}
+
+// logRing keeps the most recent warnings and errors, with sensitive fields
+// redacted, so operators can see them without reading the logs.
+type logRing struct {
+	redactIPs bool
+	salt      []byte // If set, identifying fields are hashed with it.
+
+	mu      sync.Mutex
+	entries []*adminapi.LogEntry
+	next    int
+}
+
+func newLogRing(redactIPs bool) *logRing {
+	return &logRing{redactIPs: redactIPs}
+}
+
+// hashIdentifiers makes the ring keep salted hashes of identifying fields in
+// place of their values, with a salt drawn for this process.
+func (l *logRing) hashIdentifiers() {
+	l.salt = []byte(idgen.Token())
+}
+
+// identify returns what the ring keeps for an identifying value.
+func (l *logRing) identify(v string) string {
+	if l.salt == nil {
+		return v
+	}
+
+	mac := hmac.New(sha256.New, l.salt)
+	mac.Write([]byte(v)) //nolint:errcheck
+	return hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+// core returns a zapcore.Core which tees into the ring; use it with
+// zapcore.NewTee alongside the logger's own core.
+func (l *logRing) core() zapcore.Core {
+	return &ringCore{ring: l}
+}
+
+func (l *logRing) add(e *adminapi.LogEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.entries) < maxLogEntries {
+		l.entries = append(l.entries, e)
+		return
+	}
+
+	l.entries[l.next] = e
+	l.next = (l.next + 1) % maxLogEntries
+}
+
+// list returns the entries, oldest first.
+func (l *logRing) list() []*adminapi.LogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := make([]*adminapi.LogEntry, 0, len(l.entries))
+	entries = append(entries, l.entries[l.next:]...)
+	entries = append(entries, l.entries[:l.next]...)
+	return entries
+}
+
+// purge drops the entries about a room; it's registered with the server to
+// run as rooms are torn down.
+func (l *logRing) purge(id, name string) {
+	id, name = l.identify(id), l.identify(name)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Keep the rest oldest first, so the ring fills from the end again.
+	kept := make([]*adminapi.LogEntry, 0, len(l.entries))
+	for _, entries := range [][]*adminapi.LogEntry{l.entries[l.next:], l.entries[:l.next]} {
+		for _, e := range entries {
+			if !logEntryAbout(e, id, name) {
+				kept = append(kept, e)
+			}
+		}
+	}
+
+	l.entries = kept
+	l.next = 0
+}
+
+// logEntryAbout returns true if the entry names the room.
+func logEntryAbout(e *adminapi.LogEntry, id, name string) bool {
+	for _, k := range roomKeys {
+		if v, ok := e.Fields[k].(string); ok && (v == id || v == name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *logRing) sanitize(fields map[string]interface{}) {
+	for k, v := range fields {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			l.sanitize(v)
+			continue
+		case string:
+			if l.salt != nil && identifyingKeys[k] {
+				fields[k] = l.identify(v)
+				continue
+			}
+			fields[k] = l.redactString(v)
+		}
+
+		if l.sensitive(k) {
+			fields[k] = redacted
+		}
+	}
+}
+
+func (l *logRing) sensitive(key string) bool {
+	key = strings.ToLower(key)
+
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+
+	return l.redactIPs && (key == "ip" || strings.HasSuffix(key, "addr"))
+}
+
+// maybeIP matches runs of characters which could be an IP, with or without a
+// port.
+var maybeIP = regexp.MustCompile(`[0-9A-Fa-f:.]{3,}`)
+
+// redactString removes any IPs from s, if configured to.
+func (l *logRing) redactString(s string) string {
+	if !l.redactIPs {
+		return s
+	}
+
+	return maybeIP.ReplaceAllStringFunc(s, func(m string) string {
+		if isIP(strings.TrimRight(m, ".:")) {
+			return redacted
+		}
+		return m
+	})
+}
+
+// isIP returns true for IPs, with or without a port.
+func isIP(s string) bool {
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	return net.ParseIP(s) != nil
+}
+
+type ringCore struct {
+	ring   *logRing
+	fields []zapcore.Field
+}
+
+var _ zapcore.Core = (*ringCore)(nil)
+
+func (c *ringCore) Enabled(level zapcore.Level) bool {
+	return level >= zapcore.WarnLevel
+}
+
+func (c *ringCore) With(fields []zapcore.Field) zapcore.Core {
+	return &ringCore{
+		ring:   c.ring,
+		fields: append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+func (c *ringCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *ringCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	e := &adminapi.LogEntry{
+		Time:    ent.Time,
+		Level:   ent.Level.String(),
+		Message: c.ring.redactString(ent.Message),
+	}
+
+	if ent.Caller.Defined {
+		e.Caller = ent.Caller.TrimmedPath()
+	}
+
+	if len(enc.Fields) != 0 {
+		c.ring.sanitize(enc.Fields)
+		e.Fields = enc.Fields
+	}
+
+	c.ring.add(e)
+	return nil
+}
+
+func (c *ringCore) Sync() error {
+	return nil
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gotest.tools/v3/assert"
+)
+
+func newRingLogger(logs *logRing) *zap.Logger {
+	return zap.New(logs.core())
+}
+
+func TestLogRingLevels(t *testing.T) {
+	logs := newLogRing(false)
+	logger := newRingLogger(logs)
+
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Error("error")
+
+	entries := logs.list()
+	assert.Equal(t, len(entries), 2)
+	assert.Equal(t, entries[0].Message, "warn")
+	assert.Equal(t, entries[0].Level, "warn")
+	assert.Equal(t, entries[1].Message, "error")
+}
+
+func TestLogRingBounded(t *testing.T) {
+	logs := newLogRing(false)
+	logger := newRingLogger(logs)
+
+	for i := 0; i < maxLogEntries+5; i++ {
+		logger.Warn(strconv.Itoa(i))
+	}
+
+	entries := logs.list()
+	assert.Equal(t, len(entries), maxLogEntries)
+	assert.Equal(t, entries[0].Message, "5")
+	assert.Equal(t, entries[len(entries)-1].Message, strconv.Itoa(maxLogEntries+4))
+}
+
+func TestLogRingFields(t *testing.T) {
+	logs := newLogRing(false)
+	logger := newRingLogger(logs).With(zap.String("roomID", "abc"))
+
+	logger.Warn("failed", zap.Int("count", 3), zap.Error(errors.New("broken")))
+
+	fields := logs.list()[0].Fields
+	assert.Equal(t, fields["roomID"], "abc")
+	assert.Equal(t, fields["count"], int64(3))
+	assert.Equal(t, fields["error"], "broken")
+}
+
+func TestLogRingSanitizes(t *testing.T) {
+	secrets := []zapcore.Field{
+		zap.String("token", "tok-secret"),
+		zap.String("adminToken", "tok-admin"),
+		zap.String("password", "hunter2"),
+		zap.String("Authorization", "Bearer tok-bearer"),
+		zap.Any("query", map[string]interface{}{"password": "nested-secret"}),
+	}
+
+	for _, redactIPs := range []bool{false, true} {
+		logs := newLogRing(redactIPs)
+		logger := newRingLogger(logs)
+
+		logger.Error("request from 203.0.113.9:5000 failed",
+			append(secrets,
+				zap.String("ip", "203.0.113.9"),
+				zap.String("remoteAddr", "[2001:db8::1]:443"),
+				zap.Error(errors.New("dial tcp 198.51.100.7:80: refused")),
+				zap.String("version", "v1.2.3"),
+			)...,
+		)
+
+		buf, err := json.Marshal(logs.list())
+		assert.NilError(t, err)
+		feed := string(buf)
+
+		for _, s := range []string{"tok-secret", "tok-admin", "hunter2", "tok-bearer", "nested-secret"} {
+			assert.Assert(t, !strings.Contains(feed, s), "feed contains %q: %s", s, feed)
+		}
+
+		ips := []string{"203.0.113.9", "2001:db8::1", "198.51.100.7"}
+		for _, ip := range ips {
+			assert.Equal(t, !strings.Contains(feed, ip), redactIPs, "ip %q in %s", ip, feed)
+		}
+
+		// Things that merely look numeric are left alone.
+		assert.Assert(t, strings.Contains(feed, "v1.2.3"), feed)
+	}
+}
+
+func TestLogRingHashIdentifiers(t *testing.T) {
+	logs := newLogRing(false)
+	logs.hashIdentifiers()
+	logger := newRingLogger(logs).With(zap.String("roomID", "room-abc"))
+
+	logger.Warn("joined", zap.String("nickname", "Wanderer"), zap.String("method", "reveal"))
+	logger.Warn("left", zap.String("nickname", "Wanderer"))
+
+	entries := logs.list()
+	nickname := entries[0].Fields["nickname"].(string)
+	assert.Assert(t, nickname != "Wanderer")
+	assert.Equal(t, len(nickname), 16)
+	assert.Assert(t, entries[0].Fields["roomID"] != "room-abc")
+
+	// The same value hashes the same, so entries can still be tied together.
+	assert.Equal(t, entries[1].Fields["nickname"], nickname)
+	assert.Equal(t, entries[0].Fields["method"], "reveal")
+}
+
+func TestLogRingPurge(t *testing.T) {
+	for _, hashed := range []bool{false, true} {
+		logs := newLogRing(false)
+		if hashed {
+			logs.hashIdentifiers()
+		}
+		logger := newRingLogger(logs)
+
+		for i := 0; i < maxLogEntries; i++ {
+			logger.Warn(strconv.Itoa(i))
+		}
+		logger.Warn("by id", zap.String("roomID", "gone"))
+		logger.Warn("by name", zap.String("roomName", "Gone Room"))
+		logger.Warn("merged", zap.String("targetRoomID", "gone"))
+		logger.Warn("other", zap.String("roomID", "stays"))
+
+		logs.purge("gone", "Gone Room")
+
+		entries := logs.list()
+		assert.Equal(t, len(entries), maxLogEntries-3, "hashed %v", hashed)
+		assert.Equal(t, entries[0].Message, "4")
+		assert.Equal(t, entries[len(entries)-1].Message, "other")
+
+		// The ring keeps filling, oldest first.
+		for i := 0; i < 4; i++ {
+			logger.Warn("new")
+		}
+		entries = logs.list()
+		assert.Equal(t, len(entries), maxLogEntries)
+		assert.Equal(t, entries[0].Message, "5")
+	}
+}
@@ -3,26 +3,37 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"math/rand"
+	"math"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/go-chi/chi"
-	"github.com/go-chi/chi/middleware"
 	"github.com/jessevdk/go-flags"
 	"github.com/posener/ctxutil"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/tomwright/queryparam/v4"
-	"github.com/zikaeroh/codies/internal/pkger"
+	"github.com/zikaeroh/codies/internal/analytics"
+	"github.com/zikaeroh/codies/internal/fit"
+	"github.com/zikaeroh/codies/internal/game"
+	"github.com/zikaeroh/codies/internal/gamefeed"
+	"github.com/zikaeroh/codies/internal/packs"
 	"github.com/zikaeroh/codies/internal/protocol"
 	"github.com/zikaeroh/codies/internal/responder"
 	"github.com/zikaeroh/codies/internal/server"
 	"github.com/zikaeroh/codies/internal/version"
+	"github.com/zikaeroh/codies/internal/webhook"
 	"github.com/zikaeroh/ctxlog"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"golang.org/x/sync/errgroup"
 	"nhooyr.io/websocket"
 )
@@ -32,10 +43,78 @@ var args = struct {
 	Origins []string `long:"origins" env:"CODIES_ORIGINS" env-delim:"," description:"Additional valid origins for WebSocket connections"`
 	Prod    bool     `long:"prod" env:"CODIES_PROD" description:"Enables production mode"`
 	Debug   bool     `long:"debug" env:"CODIES_DEBUG" description:"Enables debug mode"`
+
+	SkipVersionCheck bool `long:"skip-version-check" env:"CODIES_SKIP_VERSION_CHECK" description:"In production mode, accept clients of any version; for private deployments built without a version"`
+
+	PacksDir string `long:"packs-dir" env:"CODIES_PACKS_DIR" description:"Directory of additional word packs (one word per line in *.txt), reloaded on SIGHUP"`
+
+	DisableRemotePacks bool `long:"disable-remote-packs" env:"CODIES_DISABLE_REMOTE_PACKS" description:"Don't let hosts add word packs by URL"`
+
+	WebhookAllowlist []string `long:"webhook-allowlist" env:"CODIES_WEBHOOK_ALLOWLIST" env-delim:"," description:"Hosts (and their subdomains) rooms may send webhooks to; any public host if unset"`
+
+	AdminToken        string        `long:"admin-token" env:"CODIES_ADMIN_TOKEN" description:"Bearer token for the admin API; the admin API is disabled if unset"`
+	ClosedRoomsWindow time.Duration `long:"closed-rooms-window" env:"CODIES_CLOSED_ROOMS_WINDOW" description:"How long recently closed rooms are remembered" default:"1h"`
+
+	MaxRooms int `long:"max-rooms" env:"CODIES_MAX_ROOMS" description:"Maximum number of rooms" default:"1000"`
+
+	HeapThreshold uint64 `long:"heap-threshold" env:"CODIES_HEAP_THRESHOLD" description:"Heap size, in bytes, past which the server gives up compression contexts and caches to save memory, spectators before players (0 to disable)" default:"0"`
+
+	ReadHeaderTimeout time.Duration `long:"read-header-timeout" env:"CODIES_READ_HEADER_TIMEOUT" description:"How long a client may take to send a request's headers" default:"10s"`
+	IdleTimeout       time.Duration `long:"idle-timeout" env:"CODIES_IDLE_TIMEOUT" description:"How long a kept-alive connection may wait for its next request" default:"2m"`
+	MaxHeaderBytes    int           `long:"max-header-bytes" env:"CODIES_MAX_HEADER_BYTES" description:"Maximum size of a request's headers" default:"32768"`
+	H2C               bool          `long:"h2c" env:"CODIES_H2C" description:"Accept HTTP/2 without TLS, for proxies which speak it; WebSockets still use HTTP/1.1"`
+
+	MaxConnsPerIP int      `long:"max-conns-per-ip" env:"CODIES_MAX_CONNS_PER_IP" description:"Maximum concurrent WebSocket connections from one IP (0 for unlimited)" default:"50"`
+	ConnAllowlist []string `long:"conn-allowlist" env:"CODIES_CONN_ALLOWLIST" env-delim:"," description:"Networks (CIDRs or IPs) exempt from --max-conns-per-ip"`
+	RealIP        bool     `long:"real-ip" env:"CODIES_REAL_IP" description:"Trust X-Forwarded-For and X-Real-IP for client IPs; only use behind a proxy"`
+	RedactIPs     bool     `long:"redact-ips" env:"CODIES_REDACT_IPS" description:"Redact client IPs from /admin/overview"`
+	PrivacyStrict bool     `long:"privacy-strict" env:"CODIES_PRIVACY_STRICT" description:"Keep only salted hashes of room and player identifiers outside of live rooms, and purge what's left about a room when it closes"`
+
+	MaxCustomPacks     int `long:"max-custom-packs" env:"CODIES_MAX_CUSTOM_PACKS" description:"Maximum custom packs per room (0 for unlimited)" default:"3"`
+	MaxCustomPackBytes int `long:"max-custom-pack-bytes" env:"CODIES_MAX_CUSTOM_PACK_BYTES" description:"Maximum total size of a room's custom packs (0 for unlimited)" default:"102400"`
+
+	FitTextThresholds  []int `long:"fit-text-thresholds" env:"CODIES_FIT_TEXT_THRESHOLDS" env-delim:"," description:"Word lengths, in columns, past which cards show text a size smaller" default:"8" default:"11" default:"14"`
+	FitEmojiThresholds []int `long:"fit-emoji-thresholds" env:"CODIES_FIT_EMOJI_THRESHOLDS" env-delim:"," description:"Emoji counts past which cards show emoji a size smaller" default:"3" default:"5"`
+
+	AcceptVersions []string      `long:"accept-version" env:"CODIES_ACCEPT_VERSIONS" env-delim:"," description:"Client version to accept in addition to this build's, like the previous release during a deploy (repeatable)"`
+	VersionGrace   time.Duration `long:"version-grace" env:"CODIES_VERSION_GRACE" description:"Accept any client version for this long after starting (0 to disable)" default:"0"`
+
+	MinRevealPlayers int `long:"min-reveal-players" env:"CODIES_MIN_REVEAL_PLAYERS" description:"Players a room needs before the first card of a game is revealed, unless the host forces it (0 to disable)" default:"4"`
+
+	SeatGrace time.Duration `long:"seat-grace" env:"CODIES_SEAT_GRACE" description:"How long a disconnected player's seat is held for them to reconnect to (0 to disable)" default:"60s"`
+
+	JoinBurst         int           `long:"join-burst" env:"CODIES_JOIN_BURST" description:"Connections a room admits at once before spacing them out" default:"20"`
+	JoinInterval      time.Duration `long:"join-interval" env:"CODIES_JOIN_INTERVAL" description:"How often a room admits another connection once the burst is spent" default:"200ms"`
+	MaxSpectators     int           `long:"max-spectators" env:"CODIES_MAX_SPECTATORS" description:"Spectators a room sends every state to" default:"100"`
+	SpectatorOverflow bool          `long:"spectator-overflow" env:"CODIES_SPECTATOR_OVERFLOW" description:"Admit spectators past the limit or the join rate, sending them the state every few seconds"`
+
+	MatchSize int `long:"match-size" env:"CODIES_MATCH_SIZE" description:"Players matchmaking puts in each room (0 to disable matchmaking)" default:"6"`
+
+	AnalyticsFile string `long:"analytics-file" env:"CODIES_ANALYTICS_FILE" description:"Append anonymized usage events to this file, for cmd/analyze; disabled if unset"`
+
+	GameFeedFile     string `long:"game-feed-file" env:"CODIES_GAME_FEED_FILE" description:"Append a line for every finished game to this file, for archiving and cmd/analyze --feed; disabled if unset"`
+	GameFeedMaxBytes int64  `long:"game-feed-max-bytes" env:"CODIES_GAME_FEED_MAX_BYTES" description:"Rotate the game feed file before it grows past this size (0 to never rotate)" default:"67108864"`
+	GameFeedKeep     int    `long:"game-feed-keep" env:"CODIES_GAME_FEED_KEEP" description:"Rotated game feed files to keep" default:"4"`
+	GameFeedSync     string `long:"game-feed-sync" env:"CODIES_GAME_FEED_SYNC" description:"When to sync the game feed file to disk: always, rotate (before each rotation and on exit), or never" default:"rotate"`
+
+	WordSuggestionsFile string `long:"word-suggestions-file" env:"CODIES_WORD_SUGGESTIONS_FILE" description:"Keep the words players suggest for the built-in packs in this file across restarts; they're only kept in memory if unset"`
+
+	ResumeManifest string `long:"resume-manifest" env:"CODIES_RESUME_MANIFEST" description:"Keep rooms across graceful restarts: write their names, options, and players' seats to this file on shutdown, and recreate them with fresh games from it on startup; rooms are lost on restart if unset"`
+
+	Seed int64 `long:"seed" env:"CODIES_SEED" description:"Seed game randomness so games are reproducible, for debugging (0 for a random seed per room); never affects room IDs or tokens"`
+
+	PrintConfig bool `long:"print-config" description:"Print the effective configuration and exit"`
 }{
 	Addr: ":5000",
 }
 
+// metricsAddr is where Prometheus metrics are served in production.
+const metricsAddr = ":2112"
+
+// gameFeedBuffer is the number of finished games which may wait to be written
+// to the game feed before more are dropped.
+const gameFeedBuffer = 256
+
 var wsOpts *websocket.AcceptOptions
 
 func main() {
@@ -44,255 +123,699 @@ func main() {
 		return
 	}
 
-	rand.Seed(time.Now().Unix())
-
 	if _, err := flags.Parse(&args); err != nil {
 		// Default flag parser prints messages, so just exit.
 		os.Exit(1)
 	}
 
-	if !args.Prod && !args.Debug {
-		log.Fatal("missing required option --prod or --debug")
-	} else if args.Prod && args.Debug {
-		log.Fatal("must specify either --prod or --debug")
+	mode, err := parseRunMode(args.Prod, args.Debug, args.SkipVersionCheck, version.IsSet())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	connAllowlist, err := parseCIDRs(args.ConnAllowlist)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	feedSync, err := gamefeed.ParseSyncPolicy(args.GameFeedSync)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	start := time.Now()
+	cfg := effectiveConfig(mode)
+
+	if args.PrintConfig {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "    ")
+		if err := enc.Encode(cfg); err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
 
 	ctx := ctxutil.Interrupt()
 
-	logger := ctxlog.New(args.Debug)
+	logs := newLogRing(args.RedactIPs)
+	if args.PrivacyStrict {
+		logs.hashIdentifiers()
+	}
+	logger := ctxlog.New(mode.DebugLogging).WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(c, logs.core())
+	}))
 	defer zap.RedirectStdLog(logger)()
 	ctx = ctxlog.WithLogger(ctx, logger)
 
-	ctxlog.Info(ctx, "starting", zap.String("version", version.Version()))
+	ln, err := probeListen("--addr", args.Addr)
+	if err != nil {
+		ctxlog.Fatal(ctx, "cannot listen", zap.Error(err))
+	}
+
+	var metricsLn net.Listener
+	if mode.Metrics {
+		metricsLn, err = probeListen("metrics listener", metricsAddr)
+		if err != nil {
+			ctxlog.Fatal(ctx, "cannot listen", zap.Error(err))
+		}
+	}
+
+	if args.PacksDir != "" {
+		if err := probeReadableDir("--packs-dir", args.PacksDir); err != nil {
+			ctxlog.Fatal(ctx, "cannot load packs", zap.Error(err))
+		}
+	}
+
+	// Analytics are optional, so they're dropped rather than stopping the
+	// server from starting.
+	analyticsFile := args.AnalyticsFile
+	if analyticsFile != "" {
+		if err := probeWritableFile("--analytics-file", analyticsFile); err != nil {
+			ctxlog.Warn(ctx, "analytics disabled", zap.Error(err))
+			analyticsFile = ""
+			cfg.Register("analytics", false)
+		}
+	}
+
+	// As are game feeds.
+	feedFile := args.GameFeedFile
+	if feedFile != "" {
+		if err := probeWritableFile("--game-feed-file", feedFile); err != nil {
+			ctxlog.Warn(ctx, "game feed disabled", zap.Error(err))
+			feedFile = ""
+			cfg.Register("gameFeed", false)
+		}
+	}
+
+	if args.WordSuggestionsFile != "" {
+		if err := probeWritableFile("--word-suggestions-file", args.WordSuggestionsFile); err != nil {
+			ctxlog.Fatal(ctx, "cannot keep word suggestions", zap.Error(err))
+		}
+	}
+
+	if args.ResumeManifest != "" {
+		if err := probeWritableFile("--resume-manifest", args.ResumeManifest); err != nil {
+			ctxlog.Fatal(ctx, "cannot resume rooms", zap.Error(err))
+		}
+	}
+
+	ctxlog.Info(ctx, "starting", zap.String("version", version.Version()), zap.Reflect("config", cfg))
 
 	wsOpts = &websocket.AcceptOptions{
 		OriginPatterns:  args.Origins,
 		CompressionMode: websocket.CompressionContextTakeover,
 	}
 
-	if args.Debug {
+	if !mode.OriginCheck {
 		ctxlog.Info(ctx, "starting in debug mode, allowing any WebSocket origin host")
 		wsOpts.InsecureSkipVerify = true
-	} else if !version.IsSet() {
-		ctxlog.Fatal(ctx, "running production build without version set")
 	}
 
-	g, ctx := errgroup.WithContext(ctx)
+	if mode.Name == "prod" && !mode.VersionCheck {
+		ctxlog.Warn(ctx, "client version checking is disabled by --skip-version-check; clients running any version will be accepted and may misbehave after upgrades")
+	}
 
-	srv := server.NewServer()
+	// Interrupted is only done once the server's asked to stop; it's not by
+	// another part of it failing.
+	interrupted := ctx
+	g, ctx := errgroup.WithContext(ctx)
 
-	r := chi.NewMux()
+	reg, err := packs.NewRegistry(args.PacksDir)
+	if err != nil {
+		ctxlog.Fatal(ctx, "error loading packs", zap.Error(err))
+	}
 
-	r.Use(func(next http.Handler) http.Handler {
-		return promhttp.InstrumentHandlerCounter(metricRequest, next)
+	g.Go(func() error {
+		reloadPacks(ctx, reg)
+		return nil
 	})
 
-	r.Use(middleware.Heartbeat("/ping"))
-	r.Use(middleware.Recoverer)
-	r.NotFound(staticHandler().ServeHTTP)
-
-	r.Group(func(r chi.Router) {
-		r.Use(middleware.NoCache)
-
-		r.Get("/api/time", func(w http.ResponseWriter, r *http.Request) {
-			responder.Respond(w, responder.Body(&protocol.TimeResponse{Time: time.Now()}))
-		})
+	var events *analytics.Log
+	if analyticsFile != "" {
+		events, err = analytics.Open(analyticsFile)
+		if err != nil {
+			ctxlog.Fatal(ctx, "error opening analytics file", zap.Error(err))
+		}
+	}
 
-		r.Get("/api/stats", func(w http.ResponseWriter, r *http.Request) {
-			rooms, clients := srv.Stats()
-			responder.Respond(w,
-				responder.Body(&protocol.StatsResponse{
-					Rooms:   rooms,
-					Clients: clients,
-				}),
-				responder.Pretty(true),
-			)
+	var feed *gamefeed.Feed
+	if feedFile != "" {
+		feed, err = gamefeed.Open(feedFile, gamefeed.Options{
+			MaxBytes: args.GameFeedMaxBytes,
+			Keep:     args.GameFeedKeep,
+			Sync:     feedSync,
+			Buffer:   gameFeedBuffer,
 		})
+		if err != nil {
+			ctxlog.Fatal(ctx, "error opening game feed file", zap.Error(err))
+		}
+		registerGameFeedMetrics(feed)
+	}
 
-		r.Group(func(r chi.Router) {
-			if !args.Debug {
-				r.Use(checkVersion)
-			}
+	var remote *packs.Fetcher
+	if !args.DisableRemotePacks {
+		remote = packs.NewFetcher(nil)
+	}
 
-			r.Get("/api/exists", func(w http.ResponseWriter, r *http.Request) {
-				query := &protocol.ExistsQuery{}
-				if err := queryparam.Parse(r.URL.Query(), query); err != nil {
-					responder.Respond(w, responder.Status(http.StatusBadRequest))
-					return
-				}
+	srv := server.NewServer(server.Options{
+		Packs:             reg,
+		RemotePacks:       remote,
+		Webhooks:          webhook.NewSender(nil, args.WebhookAllowlist),
+		Resume:            args.ResumeManifest != "",
+		ClosedRoomsWindow: args.ClosedRoomsWindow,
+		MaxRooms:          args.MaxRooms,
+		MaxConnsPerIP:     args.MaxConnsPerIP,
+		ConnAllowlist:     connAllowlist,
+		MinRevealPlayers:  args.MinRevealPlayers,
+		MatchSize:         args.MatchSize,
+		Seed:              args.Seed,
+		Analytics:         events,
+		GameFeed:          feed,
+		PrivacyStrict:     args.PrivacyStrict,
+		HeapThreshold:     args.HeapThreshold,
+		SeatGrace:         args.SeatGrace,
+		JoinBurst:         args.JoinBurst,
+		JoinInterval:      args.JoinInterval,
+		MaxSpectators:     args.MaxSpectators,
+		SpectatorOverflow: args.SpectatorOverflow,
+		PackBudget: game.PackBudget{
+			Packs: args.MaxCustomPacks,
+			Bytes: args.MaxCustomPackBytes,
+		},
+		FitThresholds: fit.Thresholds{
+			Text:  args.FitTextThresholds,
+			Emoji: args.FitEmojiThresholds,
+		},
+	})
 
-				room := srv.FindRoomByID(query.RoomID)
-				if room == nil {
-					responder.Respond(w, responder.Status(http.StatusNotFound))
-				} else {
-					responder.Respond(w, responder.Status(http.StatusOK))
-				}
-			})
+	// Otherwise, warnings stay in the ring for operators to read after the
+	// room they're about has closed.
+	if args.PrivacyStrict {
+		srv.RegisterPurge("logRing", logs.purge)
+	}
 
-			r.Post("/api/room", func(w http.ResponseWriter, r *http.Request) {
-				defer r.Body.Close()
+	if args.WordSuggestionsFile != "" {
+		if err := loadWordSuggestions(srv, args.WordSuggestionsFile); err != nil {
+			ctxlog.Fatal(ctx, "error loading word suggestions", zap.Error(err))
+		}
 
-				req := &protocol.RoomRequest{}
-				if err := json.NewDecoder(r.Body).Decode(req); err != nil {
-					responder.Respond(w, responder.Status(http.StatusBadRequest))
-					return
-				}
+		g.Go(func() error {
+			keepWordSuggestions(ctx, srv, args.WordSuggestionsFile)
+			return nil
+		})
+	}
 
-				if msg, valid := req.Valid(); !valid {
-					responder.Respond(w,
-						responder.Status(http.StatusBadRequest),
-						responder.Body(&protocol.RoomResponse{
-							Error: stringPtr(msg),
-						}),
-					)
-					return
-				}
+	stale := newStaleVersions()
+	seen := newSeenVersions()
+	compat := &version.Compat{
+		Accept: args.AcceptVersions,
+		Start:  start,
+		Grace:  args.VersionGrace,
+	}
 
-				var room *server.Room
-				if req.Create {
-					var err error
-					room, err = srv.CreateRoom(ctx, req.RoomName, req.RoomPass)
-					if err != nil {
-						switch err {
-						case server.ErrRoomExists:
-							responder.Respond(w,
-								responder.Status(http.StatusBadRequest),
-								responder.Body(&protocol.RoomResponse{
-									Error: stringPtr("Room already exists."),
-								}),
-							)
-						case server.ErrTooManyRooms:
-							responder.Respond(w,
-								responder.Status(http.StatusServiceUnavailable),
-								responder.Body(&protocol.RoomResponse{
-									Error: stringPtr("Too many rooms."),
-								}),
-							)
-						default:
-							responder.Respond(w,
-								responder.Status(http.StatusInternalServerError),
-								responder.Body(&protocol.RoomResponse{
-									Error: stringPtr("An unknown error occurred."),
-								}),
-							)
-						}
-						return
-					}
-				} else {
-					room = srv.FindRoom(req.RoomName)
-					if room == nil || room.Password != req.RoomPass {
-						responder.Respond(w,
-							responder.Status(http.StatusNotFound),
-							responder.Body(&protocol.RoomResponse{
-								Error: stringPtr("Room not found or password does not match."),
-							}),
-						)
-						return
-					}
-				}
+	var admin http.Handler
+	if args.AdminToken != "" {
+		admin = adminHandler(srv, stale, seen, logs, start, args.AdminToken)
+	}
 
-				responder.Respond(w, responder.Body(&protocol.RoomResponse{
-					ID: &room.ID,
-				}))
+	r := newRouter(defaultMiddlewares(ctx, mode, stale, seen, compat), &routes{
+		files: staticFiles(),
+		admin: admin,
+		api: func(r chi.Router) {
+			r.Get("/api/time", func(w http.ResponseWriter, r *http.Request) {
+				responder.Respond(w, responder.Body(&protocol.TimeResponse{Time: time.Now()}))
 			})
 
-			r.Get("/api/ws", func(w http.ResponseWriter, r *http.Request) {
-				query := &protocol.WSQuery{}
-				if err := queryparam.Parse(r.URL.Query(), query); err != nil {
-					responder.Respond(w, responder.Status(http.StatusBadRequest))
-					return
-				}
+			r.Get("/api/info", infoHandler(cfg, start))
 
-				if _, valid := query.Valid(); !valid {
-					responder.Respond(w, responder.Status(http.StatusBadRequest))
-					return
-				}
+			r.Get("/api/stats", statsHandler(srv))
 
-				room := srv.FindRoomByID(query.RoomID)
-				if room == nil {
-					responder.Respond(w, responder.Status(http.StatusBadRequest))
-					return
-				}
+			r.Get("/api/rooms", roomsHandler(srv))
 
-				c, err := websocket.Accept(w, r, wsOpts)
-				if err != nil {
-					return
-				}
+			r.Get("/api/packs", packsHandler(srv))
 
-				g.Go(func() error {
-					room.HandleConn(ctx, query.Nickname, c)
-					return nil
-				})
-			})
-		})
+			r.Get("/api/ruleset/{hash}", rulesetHandler(srv))
+
+			r.Get("/api/room/{roomID}/log", gameLogHandler(srv))
+		},
+		client: func(r chi.Router) {
+			r.Get("/api/exists", existsHandler(srv))
+
+			r.Post("/api/room", roomHandler(ctx, srv))
+
+			matchmakeRoutes(ctx, r, srv)
+		},
+		ws: wsHandler(ctx, g, srv),
 	})
 
 	g.Go(func() error {
 		return srv.Run(ctx)
 	})
 
-	runServer(ctx, g, args.Addr, r)
+	// Rooms are resumed before anyone can connect, so that nobody can take
+	// a resumed room's name first.
+	if args.ResumeManifest != "" {
+		if err := loadResumeManifest(ctx, srv, args.ResumeManifest); err != nil {
+			ctxlog.Error(ctx, "error resuming rooms", zap.Error(err))
+		}
+	}
 
-	if args.Prod {
-		runServer(ctx, g, ":2112", prometheusHandler())
+	httpOpts := argsHTTPOptions()
+	runServer(ctx, g, ln, newHTTPServer(r, httpOpts))
+
+	if metricsLn != nil {
+		httpOpts.H2C = false
+		runServer(ctx, g, metricsLn, newHTTPServer(prometheusHandler(), httpOpts))
 	}
 
 	exitErr := g.Wait()
+
+	// Every connection has ended by now, so every seat has been kept. A server
+	// which didn't stop gracefully writes no manifest.
+	if args.ResumeManifest != "" && interrupted.Err() != nil {
+		if err := saveResumeManifest(srv, args.ResumeManifest); err != nil {
+			ctxlog.Error(ctx, "error writing resume manifest", zap.Error(err))
+		}
+	}
+
+	// Fatal skips deferred calls; the last day's peak must still be written.
+	if err := events.Close(); err != nil {
+		ctxlog.Error(ctx, "error writing analytics", zap.Error(err))
+	}
+	if err := feed.Close(); err != nil {
+		ctxlog.Error(ctx, "error writing game feed", zap.Error(err))
+	}
+	if dropped := feed.Dropped(); dropped != 0 {
+		ctxlog.Warn(ctx, "game feed entries dropped", zap.Int64("dropped", dropped))
+	}
 	ctxlog.Fatal(ctx, "exited", zap.Error(exitErr))
 }
 
-func staticHandler() http.Handler {
-	fs := pkger.Dir("/frontend/build")
-	fsh := http.FileServer(fs)
+func wsHandler(ctx context.Context, g *errgroup.Group, srv *server.Server) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := &protocol.WSQuery{}
+		if err := queryparam.Parse(r.URL.Query(), query); err != nil {
+			responder.Respond(w, responder.Status(http.StatusBadRequest))
+			return
+		}
 
-	r := chi.NewMux()
-	r.Use(middleware.Compress(5))
+		if _, valid := query.Valid(); !valid {
+			responder.Respond(w, responder.Status(http.StatusBadRequest))
+			return
+		}
 
-	r.Handle("/static/*", fsh)
-	r.Handle("/favicon/*", fsh)
+		room := srv.FindRoomByID(query.RoomID)
+		if room == nil {
+			responder.Respond(w, responder.Status(http.StatusBadRequest))
+			return
+		}
 
-	r.Group(func(r chi.Router) {
-		r.Use(middleware.NoCache)
-		r.Handle("/*", fsh)
-	})
+		release, err := srv.AcquireConn(clientIP(r))
+		if err != nil {
+			responder.Respond(w,
+				responder.Status(http.StatusTooManyRequests),
+				responder.Body(&protocol.TooManyConnsResponse{
+					Error: "Too many connections from this address.",
+					Limit: srv.MaxConnsPerIP(),
+				}),
+			)
+			return
+		}
+
+		// Under memory pressure, connections are accepted without context
+		// takeover, which can't be changed once they're open.
+		var acceptOpts websocket.AcceptOptions
+		if wsOpts != nil {
+			acceptOpts = *wsOpts
+		}
+		acceptOpts.CompressionMode = srv.CompressionMode(query.View == protocol.ViewMirror || query.Spectate)
+
+		c, counter, err := server.Accept(w, r, &acceptOpts)
+		if err != nil {
+			release()
+			return
+		}
 
-	return r
+		if query.View == protocol.ViewMirror {
+			g.Go(func() error {
+				defer release()
+				room.HandleMirrorConn(ctx, query.Token, c)
+				return nil
+			})
+			return
+		}
+
+		opts := server.ConnOptions{
+			Nickname:    query.Nickname,
+			Bandwidth:   query.Has(protocol.CapabilityBandwidth),
+			Deltas:      query.Has(protocol.CapabilityDeltas),
+			Counter:     counter,
+			MergeToken:  query.Merge,
+			ResumeToken: query.Resume,
+			Spectate:    query.Spectate,
+			IP:          clientIP(r),
+
+			ClientVersion:   version.Clean(query.Version),
+			ContextTakeover: acceptOpts.CompressionMode == websocket.CompressionContextTakeover,
+		}
+
+		g.Go(func() error {
+			defer release()
+			room.HandleConn(ctx, opts, c)
+			return nil
+		})
+	}
 }
 
-func checkVersion(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		want := version.Version()
+// clientIP returns the IP a request came from. With --real-ip, proxy headers
+// have already been applied to the remote address.
+func clientIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return net.ParseIP(host)
+}
 
-		toCheck := []string{
-			r.Header.Get("X-CODIES-VERSION"),
-			r.URL.Query().Get("codiesVersion"),
+// parseCIDRs parses networks in CIDR notation; bare IPs are single addresses.
+func parseCIDRs(list []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(list))
+	for _, s := range list {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
 		}
 
-		for _, got := range toCheck {
-			if got == want {
-				next.ServeHTTP(w, r)
-				return
+		if ip := net.ParseIP(s); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
 			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
 		}
 
-		reason := fmt.Sprintf("client version too old, please reload to get %s", want)
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %q: %w", s, err)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+func statsHandler(srv *server.Server) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		stats := srv.Stats()
+		responder.Respond(w,
+			responder.Body(&protocol.StatsResponse{
+				Rooms:       stats.Rooms,
+				Clients:     stats.Clients,
+				Spectators:  stats.Spectators,
+				Matchmaking: stats.Matchmaking,
+			}),
+			responder.Pretty(true),
+		)
+	}
+}
+
+func roomsHandler(srv *server.Server) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		responder.Respond(w, responder.Body(srv.ListRooms()), responder.Pretty(true))
+	}
+}
+
+func packsHandler(srv *server.Server) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		responder.Respond(w, responder.Body(srv.Packs()), responder.Pretty(true))
+	}
+}
+
+func rulesetHandler(srv *server.Server) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rs, ok := srv.Ruleset(chi.URLParam(r, "hash"))
+		if !ok {
+			responder.Respond(w, responder.Status(http.StatusNotFound))
+			return
+		}
+
+		responder.Respond(w, responder.Body(rs), responder.Pretty(true))
+	}
+}
+
+func gameLogHandler(srv *server.Server) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := &protocol.GameLogQuery{}
+		if err := queryparam.Parse(r.URL.Query(), query); err != nil {
+			responder.Respond(w, responder.Status(http.StatusBadRequest))
+			return
+		}
+
+		room := srv.FindRoomByID(chi.URLParam(r, "roomID"))
+		if room == nil {
+			responder.Respond(w, responder.Status(http.StatusNotFound))
+			return
+		}
+
+		gameLog, inRoom := room.GameLog(game.PlayerID(query.PlayerID))
+		if !inRoom {
+			responder.Respond(w, responder.Status(http.StatusForbidden))
+			return
+		}
+		if gameLog == nil {
+			responder.Respond(w, responder.Status(http.StatusNotFound))
+			return
+		}
+
+		responder.Respond(w, responder.Body(gameLog), responder.Pretty(true))
+	}
+}
+
+func existsHandler(srv *server.Server) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := &protocol.ExistsQuery{}
+		if err := queryparam.Parse(r.URL.Query(), query); err != nil {
+			responder.Respond(w, responder.Status(http.StatusBadRequest))
+			return
+		}
+
+		if room := srv.FindRoomByID(query.RoomID); room != nil {
+			responder.Respond(w, responder.Status(http.StatusOK))
+			return
+		}
+
+		if closed, ok := srv.RecentlyClosed(query.RoomID); ok {
+			responder.Respond(w,
+				responder.Status(http.StatusGone),
+				responder.Body(&protocol.ClosedResponse{
+					Reason: string(closed.Reason),
+				}),
+			)
+			return
+		}
+
+		responder.Respond(w, responder.Status(http.StatusNotFound))
+	}
+}
+
+func roomHandler(ctx context.Context, srv *server.Server) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+
+		req := &protocol.RoomRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			responder.Respond(w, responder.Status(http.StatusBadRequest))
+			return
+		}
+
+		if errs, valid := req.Valid(); !valid {
+			responder.Respond(w,
+				responder.Status(http.StatusBadRequest),
+				responder.Body(&protocol.RoomResponse{
+					Error:  stringPtr(errs[0].Message),
+					Errors: errs,
+				}),
+			)
+			return
+		}
 
-		if r.Header.Get("Upgrade") == "websocket" {
-			c, err := websocket.Accept(w, r, wsOpts)
+		var room *server.Room
+		if req.Create {
+			ip := clientIP(r)
+			if retryAfter, ok := srv.AdmitCreate(ip); !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				responder.Respond(w,
+					responder.Status(http.StatusTooManyRequests),
+					responder.Body(&protocol.RoomResponse{
+						Error: stringPtr("Too many rooms created from this address; try again later."),
+					}),
+				)
+				return
+			}
+
+			var err error
+			var wait time.Duration
+			if req.Wait {
+				wait = server.MaxCreateWait
+			}
+
+			// Waited on with the request's context, so a client which gives
+			// up stops holding its place in line.
+			reqCtx := ctxlog.WithLogger(r.Context(), ctxlog.FromContext(ctx))
+			opts := server.RoomOptions{Tracker: req.Tracker, Unlisted: req.Unlisted, Words: req.Words, Language: req.Language, Rows: req.Rows, Cols: req.Cols}
+			room, err = srv.CreateRoomWait(reqCtx, req.RoomName, req.RoomPass, opts, wait)
 			if err != nil {
+				// Only rooms actually created count against the address.
+				srv.RefundCreate(ip)
+
+				var gErr *game.Error
+				if errors.As(err, &gErr) {
+					// Only the custom words, the language, or the board size
+					// can be wrong here.
+					field := "words"
+					switch {
+					case gErr == server.ErrUnknownLanguage:
+						field = "language"
+					case gErr.Code == "invalidBoards":
+						field = "rows"
+					}
+
+					responder.Respond(w,
+						responder.Status(http.StatusBadRequest),
+						responder.Body(&protocol.RoomResponse{
+							Error:  stringPtr(gErr.Message),
+							Errors: []*protocol.FieldError{{Field: field, Code: gErr.Code, Message: gErr.Message}},
+						}),
+					)
+					return
+				}
+
+				switch err {
+				case server.ErrRoomExists:
+					responder.Respond(w,
+						responder.Status(http.StatusBadRequest),
+						responder.Body(&protocol.RoomResponse{
+							Error: stringPtr("Room already exists."),
+						}),
+					)
+				case server.ErrTooManyRooms:
+					responder.Respond(w,
+						responder.Status(http.StatusServiceUnavailable),
+						responder.Body(&protocol.RoomResponse{
+							Error: stringPtr("Too many rooms."),
+						}),
+					)
+				default:
+					responder.Respond(w,
+						responder.Status(http.StatusInternalServerError),
+						responder.Body(&protocol.RoomResponse{
+							Error: stringPtr("An unknown error occurred."),
+						}),
+					)
+				}
+				return
+			}
+		} else {
+			room = srv.FindRoom(req.RoomName)
+			if room == nil || !room.CheckPassword(req.RoomPass) {
+				responder.Respond(w,
+					responder.Status(http.StatusNotFound),
+					responder.Body(&protocol.RoomResponse{
+						Error: stringPtr("Room not found or password does not match."),
+					}),
+				)
+				return
+			}
+		}
+
+		responder.Respond(w, responder.Body(&protocol.RoomResponse{
+			ID: &room.ID,
+		}))
+	}
+}
+
+// checkVersion rejects clients whose version isn't compatible with the
+// server's, recording the version they claimed in stale. Mismatched clients
+// let through by the grace period after startup are logged, but not recorded.
+func checkVersion(ctx context.Context, stale *staleVersions, compat *version.Compat) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			want := version.Version()
+			now := time.Now()
+
+			toCheck := []string{
+				r.Header.Get("X-CODIES-VERSION"),
+				r.URL.Query().Get("codiesVersion"),
+			}
+
+			claimed := ""
+			grace := false
+			for _, got := range toCheck {
+				switch compat.Check(got, now) {
+				case version.Exact, version.Accepted:
+					next.ServeHTTP(w, r)
+					return
+				case version.Grace:
+					grace = true
+				}
+				if claimed == "" {
+					claimed = got
+				}
+			}
+
+			staleness := version.Classify(claimed)
+
+			if grace {
+				metricVersionGrace.WithLabelValues(string(staleness)).Inc()
+				ctxlog.Info(ctx, "accepting mismatched client version during startup grace period", zap.String("claimed", claimed), zap.String("staleness", string(staleness)))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			metricVersionMismatch.WithLabelValues(string(staleness)).Inc()
+			stale.record(claimed, staleness, now)
+
+			reason := fmt.Sprintf("client version too old, please reload to get %s", want)
+
+			if r.Header.Get("Upgrade") == "websocket" {
+				c, err := websocket.Accept(w, r, wsOpts)
+				if err != nil {
+					return
+				}
+				c.Close(4418, protocol.CloseHint{Reason: reason}.Encode())
 				return
 			}
-			c.Close(4418, reason)
+
+			w.WriteHeader(http.StatusTeapot)
+			fmt.Fprint(w, reason)
+		})
+	}
+}
+
+func reloadPacks(ctx context.Context, reg *packs.Registry) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
 			return
+		case <-hup:
 		}
 
-		w.WriteHeader(http.StatusTeapot)
-		fmt.Fprint(w, reason)
-	})
+		if err := reg.Reload(); err != nil {
+			metricPackReloads.WithLabelValues("error").Inc()
+			ctxlog.Error(ctx, "error reloading packs, keeping previous packs", zap.Error(err))
+			continue
+		}
+
+		metricPackReloads.WithLabelValues("success").Inc()
+		ctxlog.Info(ctx, "reloaded packs", zap.Int("count", len(reg.Packs())))
+	}
 }
 
-func runServer(ctx context.Context, g *errgroup.Group, addr string, handler http.Handler) {
-	httpSrv := http.Server{Addr: addr, Handler: handler}
+func runServer(ctx context.Context, g *errgroup.Group, ln net.Listener, httpSrv *http.Server) {
 
 	g.Go(func() error {
 		<-ctx.Done()
@@ -303,7 +826,9 @@ func runServer(ctx context.Context, g *errgroup.Group, addr string, handler http
 		return httpSrv.Shutdown(ctx)
 	})
 
-	g.Go(httpSrv.ListenAndServe)
+	g.Go(func() error {
+		return httpSrv.Serve(ln)
+	})
 }
 
 func prometheusHandler() http.Handler {
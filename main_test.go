@@ -0,0 +1,770 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/zikaeroh/codies/internal/adminapi"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"github.com/zikaeroh/codies/internal/server"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"gotest.tools/v3/assert"
+	"nhooyr.io/websocket"
+)
+
+func postRoom(t *testing.T, h http.Handler, body string) (int, *protocol.RoomResponse) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/room", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	resp := &protocol.RoomResponse{}
+	assert.NilError(t, json.NewDecoder(rec.Body).Decode(resp))
+	return rec.Code, resp
+}
+
+func newTestServer(t *testing.T) *server.Server {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	srv := server.NewServer(server.Options{})
+	go srv.Run(ctx) //nolint:errcheck
+	return srv
+}
+
+func TestRoomHandlerMultipleErrors(t *testing.T) {
+	h := roomHandler(context.Background(), newTestServer(t))
+
+	code, resp := postRoom(t, h, `{"roomName": "this room name is much too long", "roomPass": "", "create": true}`)
+	assert.Equal(t, code, http.StatusBadRequest)
+	assert.Assert(t, resp.ID == nil)
+
+	assert.Equal(t, len(resp.Errors), 2)
+	assert.Equal(t, resp.Errors[0].Field, "roomName")
+	assert.Equal(t, resp.Errors[0].Code, protocol.ErrorTooLong)
+	assert.Equal(t, resp.Errors[1].Field, "roomPass")
+	assert.Equal(t, resp.Errors[1].Code, protocol.ErrorRequired)
+
+	// Older clients only look at the error string.
+	assert.Assert(t, resp.Error != nil)
+	assert.Equal(t, *resp.Error, resp.Errors[0].Message)
+}
+
+func TestRoomHandlerInvalidChars(t *testing.T) {
+	h := roomHandler(context.Background(), newTestServer(t))
+
+	code, resp := postRoom(t, h, `{"roomName": "bad\u0000name", "roomPass": "pass", "create": true}`)
+	assert.Equal(t, code, http.StatusBadRequest)
+	assert.Equal(t, len(resp.Errors), 1)
+	assert.Equal(t, resp.Errors[0].Code, protocol.ErrorInvalidChars)
+}
+
+func TestRoomHandlerValid(t *testing.T) {
+	h := roomHandler(context.Background(), newTestServer(t))
+
+	code, resp := postRoom(t, h, `{"roomName": "room", "roomPass": "pass", "create": true}`)
+	assert.Equal(t, code, http.StatusOK)
+	assert.Assert(t, resp.ID != nil)
+	assert.Assert(t, resp.Error == nil)
+	assert.Equal(t, len(resp.Errors), 0)
+}
+
+func TestRoomHandlerWords(t *testing.T) {
+	h := roomHandler(context.Background(), newTestServer(t))
+
+	code, resp := postRoom(t, h, `{"roomName": "room", "roomPass": "pass", "create": true, "words": ["a", "A", " a ", "b"]}`)
+	assert.Equal(t, code, http.StatusBadRequest)
+	assert.Assert(t, resp.ID == nil)
+	assert.Equal(t, len(resp.Errors), 1)
+	assert.Equal(t, resp.Errors[0].Field, "words")
+	assert.Equal(t, resp.Errors[0].Code, "tooFewWords")
+	assert.Equal(t, *resp.Error, "Custom word lists need at least 25 different words, but only 2 were given after removing 2 duplicates.")
+
+	words := make([]string, 25)
+	for i := range words {
+		words[i] = fmt.Sprintf("%q", fmt.Sprintf("word%d", i))
+	}
+	body := fmt.Sprintf(`{"roomName": "room", "roomPass": "pass", "create": true, "words": [%s]}`, strings.Join(words, ","))
+	code, resp = postRoom(t, h, body)
+	assert.Equal(t, code, http.StatusOK)
+	assert.Assert(t, resp.ID != nil)
+}
+
+func TestRoomHandlerLanguage(t *testing.T) {
+	h := roomHandler(context.Background(), newTestServer(t))
+
+	code, resp := postRoom(t, h, `{"roomName": "room", "roomPass": "pass", "create": true, "language": "tlh"}`)
+	assert.Equal(t, code, http.StatusBadRequest)
+	assert.Equal(t, len(resp.Errors), 1)
+	assert.Equal(t, resp.Errors[0].Field, "language")
+	assert.Equal(t, resp.Errors[0].Code, "unknownLanguage")
+
+	code, resp = postRoom(t, h, `{"roomName": "room", "roomPass": "pass", "create": true, "language": "fr"}`)
+	assert.Equal(t, code, http.StatusOK)
+	assert.Assert(t, resp.ID != nil)
+}
+
+func TestRoomHandlerBoardSize(t *testing.T) {
+	h := roomHandler(context.Background(), newTestServer(t))
+
+	code, resp := postRoom(t, h, `{"roomName": "room", "roomPass": "pass", "create": true, "rows": 2, "cols": 2}`)
+	assert.Equal(t, code, http.StatusBadRequest)
+	assert.Equal(t, len(resp.Errors), 1)
+	assert.Equal(t, resp.Errors[0].Field, "rows")
+	assert.Equal(t, resp.Errors[0].Code, "invalidBoards")
+
+	code, resp = postRoom(t, h, `{"roomName": "room", "roomPass": "pass", "create": true, "rows": 6, "cols": 6}`)
+	assert.Equal(t, code, http.StatusOK)
+	assert.Assert(t, resp.ID != nil)
+}
+
+func TestPacksHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	packsHandler(newTestServer(t)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/packs", nil))
+	assert.Equal(t, rec.Code, http.StatusOK)
+
+	var packs []*protocol.BuiltinPack
+	assert.NilError(t, json.Unmarshal(rec.Body.Bytes(), &packs))
+
+	languages := make(map[string]int)
+	for _, p := range packs {
+		languages[p.Language]++
+	}
+	assert.DeepEqual(t, languages, map[string]int{"en": 3, "de": 1, "es": 1, "fr": 1})
+	assert.DeepEqual(t, packs[0], &protocol.BuiltinPack{Name: "Base", Language: "en", Count: 400})
+}
+
+func TestRoomHandlerJoin(t *testing.T) {
+	h := roomHandler(context.Background(), newTestServer(t))
+
+	code, created := postRoom(t, h, `{"roomName": "room", "roomPass": "pässwörd🔑", "create": true}`)
+	assert.Equal(t, code, http.StatusOK)
+
+	code, joined := postRoom(t, h, `{"roomName": "room", "roomPass": "pässwörd🔑"}`)
+	assert.Equal(t, code, http.StatusOK)
+	assert.Equal(t, *joined.ID, *created.ID)
+
+	code, resp := postRoom(t, h, `{"roomName": "room", "roomPass": "password"}`)
+	assert.Equal(t, code, http.StatusNotFound)
+	assert.Assert(t, resp.ID == nil)
+
+	code, _ = postRoom(t, h, `{"roomName": "missing", "roomPass": "pässwörd🔑"}`)
+	assert.Equal(t, code, http.StatusNotFound)
+}
+
+func TestRoomHandlerCreateLimit(t *testing.T) {
+	h := roomHandler(context.Background(), newTestServer(t))
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/room", strings.NewReader(body))
+		req.RemoteAddr = "192.0.2.1:1234"
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	var i int
+	for ; ; i++ {
+		rec := post(fmt.Sprintf(`{"roomName": "room%d", "roomPass": "pass", "create": true}`, i))
+		if rec.Code == http.StatusTooManyRequests {
+			resp := &protocol.RoomResponse{}
+			assert.NilError(t, json.NewDecoder(rec.Body).Decode(resp))
+			assert.Assert(t, resp.ID == nil)
+			assert.Assert(t, resp.Error != nil)
+			assert.Assert(t, rec.Header().Get("Retry-After") != "")
+			break
+		}
+		assert.Equal(t, rec.Code, http.StatusOK)
+		assert.Assert(t, i < 100, "creations were never limited")
+	}
+
+	// Joining isn't limited.
+	for j := 0; j < i; j++ {
+		rec := post(fmt.Sprintf(`{"roomName": "room%d", "roomPass": "pass"}`, j))
+		assert.Equal(t, rec.Code, http.StatusOK)
+	}
+}
+
+func TestRoomHandlerCreateLimitFailures(t *testing.T) {
+	h := roomHandler(context.Background(), newTestServer(t))
+
+	post := func(body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/room", strings.NewReader(body))
+		req.RemoteAddr = "192.0.2.1:1234"
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Creations which fail don't count against the address; there are more
+	// here than the burst allows.
+	assert.Equal(t, post(`{"roomName": "taken", "roomPass": "pass", "create": true}`), http.StatusOK)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, post(`{"roomName": "taken", "roomPass": "pass", "create": true}`), http.StatusBadRequest)
+		assert.Equal(t, post(`{"roomName": "other", "roomPass": "pass", "create": true, "language": "tlh"}`), http.StatusBadRequest)
+	}
+	assert.Equal(t, post(`{"roomName": "other", "roomPass": "pass", "create": true}`), http.StatusOK)
+}
+
+func TestRoomHandlerCreateWaitCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	srv := server.NewServer(server.Options{MaxRooms: 1})
+	go srv.Run(ctx) //nolint:errcheck
+	h := roomHandler(ctx, srv)
+
+	code, _ := postRoom(t, h, `{"roomName": "first", "roomPass": "pass", "create": true}`)
+	assert.Equal(t, code, http.StatusOK)
+
+	// A client which gives up while waiting for space stops waiting then,
+	// not once the wait runs out.
+	reqCtx, reqCancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer reqCancel()
+	req := httptest.NewRequest(http.MethodPost, "/api/room", strings.NewReader(`{"roomName": "second", "roomPass": "pass", "create": true, "wait": true}`))
+	start := time.Now()
+	h.ServeHTTP(httptest.NewRecorder(), req.WithContext(reqCtx))
+	assert.Assert(t, time.Since(start) < server.MaxCreateWait/2)
+}
+
+func TestRoomsHandler(t *testing.T) {
+	srv := newTestServer(t)
+	create := roomHandler(context.Background(), srv)
+
+	code, listed := postRoom(t, create, `{"roomName": "listed", "roomPass": "secret", "create": true}`)
+	assert.Equal(t, code, http.StatusOK)
+	code, _ = postRoom(t, create, `{"roomName": "unlisted", "roomPass": "secret", "create": true, "unlisted": true}`)
+	assert.Equal(t, code, http.StatusOK)
+
+	h := roomsHandler(srv)
+	var body string
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/rooms", nil))
+		assert.Equal(t, rec.Code, http.StatusOK)
+		body = rec.Body.String()
+
+		if strings.Contains(body, "listed") || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	var rooms []*protocol.ListedRoom
+	assert.NilError(t, json.Unmarshal([]byte(body), &rooms))
+	assert.DeepEqual(t, rooms, []*protocol.ListedRoom{{Name: "listed", PasswordRequired: true}})
+
+	assert.Assert(t, !strings.Contains(body, "secret"))
+	assert.Assert(t, !strings.Contains(body, *listed.ID))
+}
+
+func TestInfoHandler(t *testing.T) {
+	cfg := &protocol.Config{Version: "v1", ProtocolVersion: protocol.Version, Mode: "prod"}
+	cfg.Register("metrics", true)
+
+	h := infoHandler(cfg, time.Now().Add(-time.Minute))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/info", nil))
+	assert.Equal(t, rec.Code, http.StatusOK)
+
+	resp := &protocol.InfoResponse{}
+	assert.NilError(t, json.NewDecoder(rec.Body).Decode(resp))
+	assert.Equal(t, resp.Version, "v1")
+	assert.Equal(t, resp.ProtocolVersion, protocol.Version)
+	assert.Equal(t, resp.Features["metrics"], true)
+	assert.Assert(t, resp.UptimeSeconds >= 60)
+}
+
+func TestExistsHandler(t *testing.T) {
+	srv := newTestServer(t)
+	h := existsHandler(srv)
+
+	room, err := srv.CreateRoom(context.Background(), "room", "pass")
+	assert.NilError(t, err)
+
+	get := func(id string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/exists?roomID="+id, nil))
+		return rec
+	}
+
+	assert.Equal(t, get(room.ID).Code, http.StatusOK)
+	assert.Equal(t, get("unknown").Code, http.StatusNotFound)
+
+	assert.Assert(t, srv.DeleteRoom(context.Background(), room.ID))
+
+	rec := get(room.ID)
+	assert.Equal(t, rec.Code, http.StatusGone)
+	resp := &protocol.ClosedResponse{}
+	assert.NilError(t, json.NewDecoder(rec.Body).Decode(resp))
+	assert.Equal(t, resp.Reason, string(server.CloseAdminDeleted))
+}
+
+func TestGameLogHandler(t *testing.T) {
+	srv := newTestServer(t)
+	router := chi.NewRouter()
+	router.Get("/api/room/{roomID}/log", gameLogHandler(srv))
+
+	room, err := srv.CreateRoom(context.Background(), "room", "pass")
+	assert.NilError(t, err)
+
+	get := func(path string) int {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	assert.Equal(t, get("/api/room/unknown/log?playerID=p"), http.StatusNotFound)
+	assert.Equal(t, get("/api/room/"+room.ID+"/log?playerID=p"), http.StatusForbidden)
+}
+
+func TestMatchmakeHandler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	srv := server.NewServer(server.Options{MatchSize: 2})
+	go srv.Run(ctx) //nolint:errcheck
+
+	r := chi.NewMux()
+	matchmakeRoutes(ctx, r, srv)
+
+	do := func(method, target, body string) (int, *protocol.MatchmakeResponse) {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+
+		resp := &protocol.MatchmakeResponse{}
+		if rec.Code == http.StatusOK && method != http.MethodDelete {
+			assert.NilError(t, json.NewDecoder(rec.Body).Decode(resp))
+		}
+		return rec.Code, resp
+	}
+
+	code, _ := do(http.MethodPost, "/api/matchmake", `{"nickname": ""}`)
+	assert.Equal(t, code, http.StatusBadRequest)
+
+	code, first := do(http.MethodPost, "/api/matchmake", `{"nickname": "first"}`)
+	assert.Equal(t, code, http.StatusOK)
+	assert.Equal(t, first.Status, protocol.MatchWaiting)
+
+	code, polled := do(http.MethodGet, "/api/matchmake?ticket="+first.Ticket, "")
+	assert.Equal(t, code, http.StatusOK)
+	assert.Equal(t, polled.Waiting, 1)
+
+	code, second := do(http.MethodPost, "/api/matchmake", `{"nickname": "second"}`)
+	assert.Equal(t, code, http.StatusOK)
+	assert.Equal(t, second.Status, protocol.MatchFound)
+
+	code, polled = do(http.MethodGet, "/api/matchmake?ticket="+first.Ticket, "")
+	assert.Equal(t, code, http.StatusOK)
+	assert.Equal(t, polled.Status, protocol.MatchFound)
+	assert.Equal(t, *polled.RoomID, *second.RoomID)
+
+	code, _ = do(http.MethodDelete, "/api/matchmake?ticket="+first.Ticket, "")
+	assert.Equal(t, code, http.StatusOK)
+	code, _ = do(http.MethodGet, "/api/matchmake?ticket="+first.Ticket, "")
+	assert.Equal(t, code, http.StatusNotFound)
+	code, _ = do(http.MethodGet, "/api/matchmake", "")
+	assert.Equal(t, code, http.StatusBadRequest)
+}
+
+func TestAdminToken(t *testing.T) {
+	h := adminHandler(newTestServer(t), newStaleVersions(), newSeenVersions(), newLogRing(false), time.Now(), "secret")
+
+	for auth, want := range map[string]int{
+		"":              http.StatusUnauthorized,
+		"Bearer wrong":  http.StatusUnauthorized,
+		"secret":        http.StatusUnauthorized,
+		"Bearer secret": http.StatusOK,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/rooms/closed", nil)
+		req.Header.Set("Authorization", auth)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, rec.Code, want, "auth %q", auth)
+	}
+}
+
+func TestAdminOverview(t *testing.T) {
+	logs := newLogRing(true)
+	zap.New(logs.core()).Warn("rejected", zap.String("ip", "192.0.2.1"), zap.String("token", "mirror-secret"))
+
+	start := time.Now().Add(-time.Minute)
+	h := adminHandler(newTestServer(t), newStaleVersions(), newSeenVersions(), logs, start, "secret")
+
+	req := httptest.NewRequest(http.MethodGet, "/overview", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, rec.Code, http.StatusUnauthorized)
+
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, rec.Code, http.StatusOK)
+
+	body := rec.Body.String()
+	assert.Assert(t, !strings.Contains(body, "192.0.2.1"))
+	assert.Assert(t, !strings.Contains(body, "mirror-secret"))
+
+	o := &adminapi.Overview{}
+	assert.NilError(t, json.NewDecoder(strings.NewReader(body)).Decode(o))
+	assert.Assert(t, o.Process.UptimeSeconds >= 60)
+	assert.Assert(t, o.Process.Goroutines > 0)
+	assert.Assert(t, o.Stats != nil)
+	assert.Equal(t, len(o.Errors), 1)
+	assert.Equal(t, o.Errors[0].Message, "rejected")
+}
+
+func TestStatsHandlerConcurrent(t *testing.T) {
+	srv := newTestServer(t)
+	h := statsHandler(srv)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				room, err := srv.CreateRoom(context.Background(), fmt.Sprintf("room%d-%d", i, j), "pass")
+				assert.NilError(t, err)
+				if j%2 == 0 {
+					srv.DeleteRoom(context.Background(), room.ID)
+				}
+			}
+		}(i)
+	}
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/stats", nil))
+				assert.Equal(t, rec.Code, http.StatusOK)
+
+				resp := &protocol.StatsResponse{}
+				assert.NilError(t, json.NewDecoder(rec.Body).Decode(resp))
+				assert.Assert(t, resp.Rooms >= 0 && resp.Rooms <= 200)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func BenchmarkStatsHandler(b *testing.B) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := server.NewServer(server.Options{})
+	go srv.Run(ctx) //nolint:errcheck
+
+	for i := 0; i < 100; i++ {
+		if _, err := srv.CreateRoom(ctx, fmt.Sprintf("room%d", i), "pass"); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	h := statsHandler(srv)
+	req := httptest.NewRequest(http.MethodGet, "/api/stats", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			h.ServeHTTP(httptest.NewRecorder(), req)
+		}
+	})
+}
+
+func TestAdminRoomLogLevel(t *testing.T) {
+	srv := newTestServer(t)
+	h := adminHandler(srv, newStaleVersions(), newSeenVersions(), newLogRing(false), time.Now(), "secret")
+
+	room, err := srv.CreateRoom(context.Background(), "room", "pass")
+	assert.NilError(t, err)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	path := "/rooms/" + room.ID + "/loglevel"
+
+	assert.Equal(t, do(http.MethodPost, "/rooms/missing/loglevel", `{"level":"debug"}`).Code, http.StatusNotFound)
+	assert.Equal(t, do(http.MethodPost, path, `{"level":"loud"}`).Code, http.StatusBadRequest)
+	assert.Equal(t, do(http.MethodPost, path, `{"level":"debug","duration":"-1m"}`).Code, http.StatusBadRequest)
+	assert.Equal(t, do(http.MethodPost, path, `{"level":"debug","duration":"10m"}`).Code, http.StatusOK)
+
+	rec := do(http.MethodGet, "/rooms/"+room.ID, "")
+	assert.Equal(t, rec.Code, http.StatusOK)
+
+	var stats server.RoomStats
+	assert.NilError(t, json.NewDecoder(rec.Body).Decode(&stats))
+	assert.Assert(t, stats.LogLevel != nil)
+	assert.Equal(t, stats.LogLevel.Level.String(), "debug")
+
+	assert.Equal(t, do(http.MethodPost, path, `{}`).Code, http.StatusOK)
+	assert.Assert(t, room.LogLevel() == nil)
+
+	// Both overrides were audited.
+	rec = do(http.MethodGet, "/rooms/"+room.ID+"/audit", "")
+	assert.Equal(t, rec.Code, http.StatusOK)
+
+	var audit []*protocol.AuditEntry
+	assert.NilError(t, json.NewDecoder(rec.Body).Decode(&audit))
+	assert.Equal(t, len(audit), 2)
+	assert.DeepEqual(t, audit[0].Changes, []*protocol.AuditChange{{Field: "level", Before: "", After: "debug"}})
+	assert.DeepEqual(t, audit[1].Changes, []*protocol.AuditChange{{Field: "level", Before: "debug", After: ""}})
+
+	assert.Equal(t, do(http.MethodGet, "/rooms/missing/audit", "").Code, http.StatusNotFound)
+}
+
+func TestAdminClient(t *testing.T) {
+	srv := newTestServer(t)
+	mux := chi.NewMux()
+	mux.Mount("/admin", adminHandler(srv, newStaleVersions(), newSeenVersions(), newLogRing(false), time.Now(), "secret"))
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ctx := context.Background()
+	c := adminapi.NewClient(ts.URL+"/", "secret", ts.Client())
+
+	room, err := srv.CreateRoom(ctx, "room", "pass")
+	assert.NilError(t, err)
+
+	// Stats are snapshots, taken every second.
+	var stats *server.Stats
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		stats, err = c.Stats(ctx)
+		assert.NilError(t, err)
+		if stats.Rooms != 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, stats.Rooms, 1)
+	assert.Equal(t, stats.Details[0].Name, "room")
+
+	assert.NilError(t, c.SetLogLevel(ctx, room.ID, &adminapi.LogLevelRequest{Level: "debug"}))
+	rs, err := c.Room(ctx, room.ID)
+	assert.NilError(t, err)
+	assert.Equal(t, rs.ID, room.ID)
+	assert.Equal(t, rs.LogLevel.Level.String(), "debug")
+
+	audit, err := c.Audit(ctx, room.ID)
+	assert.NilError(t, err)
+	assert.Equal(t, len(audit), 1)
+
+	conns, err := c.Conns(ctx, room.ID)
+	assert.NilError(t, err)
+	assert.Equal(t, len(conns), 0)
+
+	o, err := c.Overview(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, o.Stats.Rooms, 1)
+
+	assert.NilError(t, c.DeleteRoom(ctx, room.ID))
+	_, err = c.Room(ctx, room.ID)
+	assert.Assert(t, errors.Is(err, adminapi.ErrNotFound))
+	assert.Assert(t, errors.Is(c.DeleteRoom(ctx, room.ID), adminapi.ErrNotFound))
+
+	_, err = adminapi.NewClient(ts.URL, "wrong", ts.Client()).Stats(ctx)
+	var status *adminapi.StatusError
+	assert.Assert(t, errors.As(err, &status))
+	assert.Equal(t, status.Code, http.StatusUnauthorized)
+}
+
+func TestWSConnLimit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := server.NewServer(server.Options{MaxConnsPerIP: 2})
+	go srv.Run(ctx) //nolint:errcheck
+
+	room, err := srv.CreateRoom(ctx, "room", "pass")
+	assert.NilError(t, err)
+
+	g, gctx := errgroup.WithContext(ctx)
+	hs := httptest.NewServer(wsHandler(gctx, g, srv))
+	defer hs.Close()
+
+	url := "ws" + strings.TrimPrefix(hs.URL, "http") + "?roomID=" + room.ID + "&nickname="
+	dial := func(nickname string) (*websocket.Conn, *http.Response, error) {
+		return websocket.Dial(ctx, url+nickname, nil)
+	}
+
+	c1, _, err := dial("one")
+	assert.NilError(t, err)
+	c2, _, err := dial("two")
+	assert.NilError(t, err)
+	defer c2.Close(websocket.StatusNormalClosure, "")
+
+	_, resp, err := dial("three")
+	assert.Assert(t, err != nil)
+	assert.Equal(t, resp.StatusCode, http.StatusTooManyRequests)
+
+	body := &protocol.TooManyConnsResponse{}
+	assert.NilError(t, json.NewDecoder(resp.Body).Decode(body))
+	assert.Equal(t, body.Limit, 2)
+
+	assert.DeepEqual(t, srv.TopIPs(10), []server.IPConns{{IP: "127.0.0.1", Conns: 2}})
+
+	// Closing a connection frees its slot once it's torn down.
+	c1.Close(websocket.StatusNormalClosure, "")
+	deadline := time.Now().Add(5 * time.Second)
+	for srv.TopIPs(1)[0].Conns != 1 {
+		assert.Assert(t, time.Now().Before(deadline), "connection was never released")
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	c3, _, err := dial("three")
+	assert.NilError(t, err)
+	c3.Close(websocket.StatusNormalClosure, "")
+}
+
+func TestWSClientVersion(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := server.NewServer(server.Options{})
+	go srv.Run(ctx) //nolint:errcheck
+
+	room, err := srv.CreateRoom(ctx, "room", "pass")
+	assert.NilError(t, err)
+
+	g, gctx := errgroup.WithContext(ctx)
+	hs := httptest.NewServer(wsHandler(gctx, g, srv))
+	defer hs.Close()
+
+	url := "ws" + strings.TrimPrefix(hs.URL, "http") + "?roomID=" + room.ID
+	for _, query := range []string{"&nickname=one&codiesVersion=v0.0.1", "&nickname=two&codiesVersion=%3Cb%3E"} {
+		c, _, err := websocket.Dial(ctx, url+query, nil)
+		assert.NilError(t, err)
+		defer c.Close(websocket.StatusNormalClosure, "")
+
+		// Joined once it's sent something.
+		_, _, err = c.Read(ctx)
+		assert.NilError(t, err)
+	}
+
+	// Only valid versions are kept.
+	conns, ok := srv.RoomConns(room.ID)
+	assert.Assert(t, ok)
+	assert.Equal(t, len(conns), 2)
+	assert.Equal(t, conns[0].ClientVersion, "v0.0.1")
+	assert.Equal(t, conns[1].ClientVersion, "")
+}
+
+func TestPrivacyStrictTeardown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logs := newLogRing(false)
+	logs.hashIdentifiers()
+	logger := zap.New(logs.core())
+
+	srv := server.NewServer(server.Options{PrivacyStrict: true})
+	srv.RegisterPurge("logRing", logs.purge)
+	go srv.Run(ctx) //nolint:errcheck
+
+	room, err := srv.CreateRoom(ctx, "Hidden Lake", "pass")
+	assert.NilError(t, err)
+
+	g, gctx := errgroup.WithContext(ctx)
+	hs := httptest.NewServer(wsHandler(gctx, g, srv))
+	defer hs.Close()
+
+	c, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(hs.URL, "http")+"?roomID="+room.ID+"&nickname=Wanderer", nil)
+	assert.NilError(t, err)
+	defer c.Close(websocket.StatusNormalClosure, "")
+
+	logger.Warn("slow client",
+		zap.String("roomID", room.ID), zap.String("roomName", room.Name), zap.String("nickname", "Wanderer"))
+	logger.Warn("unrelated")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for srv.Stats().Clients != 1 {
+		assert.Assert(t, time.Now().Before(deadline), "player never joined")
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	admin := adminHandler(srv, newStaleVersions(), newSeenVersions(), logs, time.Now(), "secret")
+	get := func(h http.Handler, path string) string {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Body.String()
+	}
+
+	// Everything reachable once the room is gone: the admin API, the public
+	// API, and the log ring itself.
+	reachable := func() string {
+		var b strings.Builder
+		for _, path := range []string{
+			"/overview", "/stats", "/conns/top", "/versions/stale", "/rooms/closed", "/feedback",
+			"/suggestions.csv", "/rooms/" + room.ID, "/rooms/" + room.ID + "/audit",
+		} {
+			b.WriteString(get(admin, path))
+		}
+		b.WriteString(get(statsHandler(srv), "/api/stats"))
+		b.WriteString(get(roomsHandler(srv), "/api/rooms"))
+
+		entries, err := json.Marshal(logs.list())
+		assert.NilError(t, err)
+		b.Write(entries)
+		return b.String()
+	}
+
+	// While it's live, operators can still see the room.
+	assert.Assert(t, strings.Contains(reachable(), "Hidden Lake"))
+
+	req := httptest.NewRequest(http.MethodDelete, "/rooms/"+room.ID, nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	admin.ServeHTTP(rec, req)
+	assert.Equal(t, rec.Code, http.StatusOK)
+
+	after := reachable()
+	for _, plain := range []string{"Hidden Lake", "Wanderer", room.ID} {
+		assert.Assert(t, !strings.Contains(after, plain), "%q survived teardown", plain)
+	}
+
+	// Only what wasn't about the room is left.
+	entries := logs.list()
+	assert.Equal(t, len(entries), 1)
+	assert.Equal(t, entries[0].Message, "unrelated")
+}
+
+func TestParseCIDRs(t *testing.T) {
+	nets, err := parseCIDRs([]string{"192.0.2.0/24", " 198.51.100.7 ", "", "2001:db8::1"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(nets), 3)
+	assert.Equal(t, nets[0].String(), "192.0.2.0/24")
+	assert.Equal(t, nets[1].String(), "198.51.100.7/32")
+	assert.Equal(t, nets[2].String(), "2001:db8::1/128")
+
+	_, err = parseCIDRs([]string{"nope"})
+	assert.ErrorContains(t, err, "nope")
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/tomwright/queryparam/v4"
+	"github.com/zikaeroh/codies/internal/protocol"
+	"github.com/zikaeroh/codies/internal/responder"
+	"github.com/zikaeroh/codies/internal/server"
+)
+
+// matchmakeRoutes serves /api/matchmake: POST enters the pool, GET polls a
+// ticket, and DELETE cancels one.
+func matchmakeRoutes(ctx context.Context, r chi.Router, srv *server.Server) {
+	r.Post("/api/matchmake", func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+
+		req := &protocol.MatchmakeRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			responder.Respond(w, responder.Status(http.StatusBadRequest))
+			return
+		}
+
+		if errs, valid := req.Valid(); !valid {
+			responder.Respond(w,
+				responder.Status(http.StatusBadRequest),
+				responder.Body(&protocol.MatchmakeResponse{
+					Error:  stringPtr(errs[0].Message),
+					Errors: errs,
+				}),
+			)
+			return
+		}
+
+		resp, err := srv.Matchmake(ctx, req.Nickname)
+		switch err {
+		case nil:
+			responder.Respond(w, responder.Body(resp))
+		case server.ErrMatchmakingDisabled:
+			responder.Respond(w,
+				responder.Status(http.StatusNotFound),
+				responder.Body(&protocol.MatchmakeResponse{
+					Error: stringPtr("Matchmaking is disabled."),
+				}),
+			)
+		case server.ErrMatchPoolFull:
+			responder.Respond(w,
+				responder.Status(http.StatusServiceUnavailable),
+				responder.Body(&protocol.MatchmakeResponse{
+					Error: stringPtr("Too many players waiting; try again later."),
+				}),
+			)
+		default:
+			responder.Respond(w,
+				responder.Status(http.StatusInternalServerError),
+				responder.Body(&protocol.MatchmakeResponse{
+					Error: stringPtr("An unknown error occurred."),
+				}),
+			)
+		}
+	})
+
+	r.Get("/api/matchmake", func(w http.ResponseWriter, r *http.Request) {
+		query := &protocol.MatchmakeQuery{}
+		if err := queryparam.Parse(r.URL.Query(), query); err != nil || query.Ticket == "" {
+			responder.Respond(w, responder.Status(http.StatusBadRequest))
+			return
+		}
+
+		resp, ok := srv.MatchStatus(ctx, query.Ticket)
+		if !ok {
+			responder.Respond(w, responder.Status(http.StatusNotFound))
+			return
+		}
+		responder.Respond(w, responder.Body(resp))
+	})
+
+	r.Delete("/api/matchmake", func(w http.ResponseWriter, r *http.Request) {
+		query := &protocol.MatchmakeQuery{}
+		if err := queryparam.Parse(r.URL.Query(), query); err != nil || query.Ticket == "" {
+			responder.Respond(w, responder.Status(http.StatusBadRequest))
+			return
+		}
+
+		if !srv.CancelMatch(query.Ticket) {
+			responder.Respond(w, responder.Status(http.StatusNotFound))
+			return
+		}
+		responder.Respond(w, responder.Status(http.StatusOK))
+	})
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/zikaeroh/codies/internal/gamefeed"
 )
 
 var metricRequest = promauto.NewCounterVec(prometheus.CounterOpts{
@@ -11,3 +12,43 @@ var metricRequest = promauto.NewCounterVec(prometheus.CounterOpts{
 	Name:      "request_total",
 	Help:      "Total number of HTTP requests.",
 }, []string{"code", "method"})
+
+var metricPanics = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: "codies",
+	Subsystem: "codies",
+	Name:      "panic_total",
+	Help:      "Total number of panics recovered while serving API requests.",
+})
+
+var metricPackReloads = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "codies",
+	Subsystem: "codies",
+	Name:      "pack_reload_total",
+	Help:      "Total number of pack directory reloads.",
+}, []string{"result"})
+
+var metricVersionMismatch = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "codies",
+	Subsystem: "codies",
+	Name:      "version_mismatch_total",
+	Help:      "Total number of requests rejected for a client version mismatch.",
+}, []string{"claimed"})
+
+var metricVersionGrace = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "codies",
+	Subsystem: "codies",
+	Name:      "version_grace_total",
+	Help:      "Total number of requests with a mismatched client version accepted during the startup grace period.",
+}, []string{"claimed"})
+
+// registerGameFeedMetrics exports the game feed's count of dropped entries.
+func registerGameFeedMetrics(feed *gamefeed.Feed) {
+	promauto.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: "codies",
+		Subsystem: "codies",
+		Name:      "game_feed_dropped_total",
+		Help:      "Total number of finished games dropped from the game feed because its queue was full.",
+	}, func() float64 {
+		return float64(feed.Dropped())
+	})
+}
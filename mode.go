@@ -0,0 +1,56 @@
+package main
+
+import "errors"
+
+// runMode is the set of behaviors selected by --prod, --debug, and
+// --skip-version-check. Everything that differs between modes should key off
+// one of these fields rather than the flags themselves.
+type runMode struct {
+	Name string
+
+	// DebugLogging enables development logging.
+	DebugLogging bool
+
+	// VersionCheck rejects API requests from clients running another version.
+	VersionCheck bool
+
+	// OriginCheck verifies the origin of WebSocket connections.
+	OriginCheck bool
+
+	// Metrics serves Prometheus metrics on their own listener.
+	Metrics bool
+}
+
+// parseRunMode validates the mode flags. versionSet reports whether the build
+// has a version; version checking is meaningless without one, so production
+// builds without a version must explicitly skip it.
+func parseRunMode(prod, debug, skipVersionCheck, versionSet bool) (*runMode, error) {
+	switch {
+	case prod && debug:
+		return nil, errors.New("must specify either --prod or --debug")
+	case debug && skipVersionCheck:
+		return nil, errors.New("--skip-version-check cannot be used with --debug, which already skips version checking")
+	case debug:
+		return &runMode{
+			Name:         "debug",
+			DebugLogging: true,
+		}, nil
+	case !prod:
+		return nil, errors.New("missing required option --prod or --debug")
+	case skipVersionCheck:
+		return &runMode{
+			Name:        "prod",
+			OriginCheck: true,
+			Metrics:     true,
+		}, nil
+	case !versionSet:
+		return nil, errors.New("running production build without version set; use --skip-version-check to run it anyway")
+	default:
+		return &runMode{
+			Name:         "prod",
+			VersionCheck: true,
+			OriginCheck:  true,
+			Metrics:      true,
+		}, nil
+	}
+}
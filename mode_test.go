@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseRunMode(t *testing.T) {
+	prod := &runMode{Name: "prod", VersionCheck: true, OriginCheck: true, Metrics: true}
+	unversioned := &runMode{Name: "prod", OriginCheck: true, Metrics: true}
+	debug := &runMode{Name: "debug", DebugLogging: true}
+
+	tests := []struct {
+		name                    string
+		prod, debug, skip, vset bool
+		want                    *runMode
+		err                     string
+	}{
+		{name: "prod", prod: true, vset: true, want: prod},
+		{name: "prod unversioned", prod: true, err: "running production build without version set; use --skip-version-check to run it anyway"},
+		{name: "prod skip", prod: true, skip: true, want: unversioned},
+		{name: "prod skip versioned", prod: true, skip: true, vset: true, want: unversioned},
+		{name: "debug", debug: true, want: debug},
+		{name: "debug versioned", debug: true, vset: true, want: debug},
+		{name: "debug skip", debug: true, skip: true, err: "--skip-version-check cannot be used with --debug, which already skips version checking"},
+		{name: "both", prod: true, debug: true, err: "must specify either --prod or --debug"},
+		{name: "both skip", prod: true, debug: true, skip: true, err: "must specify either --prod or --debug"},
+		{name: "neither", err: "missing required option --prod or --debug"},
+		{name: "skip only", skip: true, err: "missing required option --prod or --debug"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseRunMode(test.prod, test.debug, test.skip, test.vset)
+			if test.err != "" {
+				assert.Error(t, err, test.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, test.want)
+		})
+	}
+}
+
+func TestEffectiveConfigMode(t *testing.T) {
+	mode, err := parseRunMode(true, false, true, false)
+	assert.NilError(t, err)
+
+	cfg := effectiveConfig(mode)
+	assert.Equal(t, cfg.Mode, "prod")
+	assert.Equal(t, cfg.Features["versionCheck"], false)
+	assert.Equal(t, cfg.Features["originCheck"], true)
+	assert.Equal(t, cfg.Features["metrics"], true)
+
+	mode, err = parseRunMode(false, true, false, false)
+	assert.NilError(t, err)
+
+	cfg = effectiveConfig(mode)
+	assert.Equal(t, cfg.Mode, "debug")
+	assert.Equal(t, cfg.Features["versionCheck"], false)
+	assert.Equal(t, cfg.Features["originCheck"], false)
+	assert.Equal(t, cfg.Features["metrics"], false)
+}
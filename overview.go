@@ -0,0 +1,67 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/adminapi"
+	"github.com/zikaeroh/codies/internal/responder"
+	"github.com/zikaeroh/codies/internal/server"
+	"github.com/zikaeroh/codies/internal/version"
+)
+
+// overviewTopIPs is the number of IPs listed by /admin/overview.
+const overviewTopIPs = 5
+
+func overviewHandler(srv *server.Server, stale *staleVersions, seen *seenVersions, logs *logRing, start time.Time) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var mem runtime.MemStats
+		runtime.ReadMemStats(&mem)
+
+		o := &adminapi.Overview{
+			Process: adminapi.ProcessInfo{
+				Version:       version.Version(),
+				StartTime:     start,
+				UptimeSeconds: int64(time.Since(start) / time.Second),
+				Goroutines:    runtime.NumGoroutine(),
+				HeapAlloc:     mem.HeapAlloc,
+				HeapObjects:   mem.HeapObjects,
+			},
+			Stats:          srv.Stats(),
+			Conns:          connsPressure(srv, logs),
+			StaleVersions:  stale.list(),
+			ClientVersions: seen.list(time.Now()),
+			Errors:         logs.list(),
+		}
+
+		responder.Respond(w, responder.Body(o), responder.Pretty(true))
+	}
+}
+
+func connsPressure(srv *server.Server, logs *logRing) adminapi.ConnsPressure {
+	all := srv.TopIPs(math.MaxInt32)
+
+	p := adminapi.ConnsPressure{
+		MaxPerIP: srv.MaxConnsPerIP(),
+		IPs:      len(all),
+	}
+
+	for _, c := range all {
+		if p.MaxPerIP > 0 && c.Conns >= p.MaxPerIP && !c.Exempt {
+			p.AtLimit++
+		}
+	}
+
+	if len(all) > overviewTopIPs {
+		all = all[:overviewTopIPs]
+	}
+
+	for i := range all {
+		all[i].IP = logs.redactString(all[i].IP)
+	}
+	p.Top = all
+
+	return p
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+)
+
+// Startup probes check what the environment actually allows before anything
+// relies on it. Containers are the usual culprit: read-only filesystems and
+// non-root users turn flags which work on a laptop into confusing failures
+// much later. Each probe's error names the flag and the path or address
+// involved.
+
+// probeListen listens at addr, given by flag.
+func probeListen(flag, addr string) (net.Listener, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, listenError(flag, addr, err)
+	}
+	return ln, nil
+}
+
+func listenError(flag, addr string, err error) error {
+	_, portStr, _ := net.SplitHostPort(addr)
+	port, _ := strconv.Atoi(portStr)
+
+	switch {
+	case errors.Is(err, syscall.EACCES) && port > 0 && port < 1024:
+		return fmt.Errorf("%s %s: not permitted to listen on port %d; ports below 1024 need root or CAP_NET_BIND_SERVICE, so use a higher port like %s :%d and map it to %d instead", flag, addr, port, flag, port+5000, port)
+	case errors.Is(err, syscall.EACCES):
+		return fmt.Errorf("%s %s: not permitted to listen: %w", flag, addr, err)
+	case errors.Is(err, syscall.EADDRINUSE):
+		return fmt.Errorf("%s %s: port %s is already in use", flag, addr, portStr)
+	default:
+		return fmt.Errorf("%s %s: %w", flag, addr, err)
+	}
+}
+
+// probeReadableDir checks that dir, given by flag, is a directory which can
+// be listed.
+func probeReadableDir(flag, dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return pathError(flag, dir, "directory can't be opened", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return pathError(flag, dir, "directory can't be opened", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s %s: not a directory", flag, dir)
+	}
+
+	if _, err := f.Readdirnames(1); err != nil && !errors.Is(err, io.EOF) {
+		return pathError(flag, dir, "directory can't be listed", err)
+	}
+
+	return nil
+}
+
+// probeWritableFile checks that path, given by flag, can be appended to, and
+// that new files can be created beside it.
+func probeWritableFile(flag, path string) error {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return fmt.Errorf("%s %s: is a directory, not a file", flag, path)
+	}
+
+	dir := filepath.Dir(path)
+
+	tmp, err := ioutil.TempFile(dir, ".codies-probe-*")
+	if err != nil {
+		return pathError(flag, dir, "directory isn't writable", err)
+	}
+	tmp.Close()
+	os.Remove(tmp.Name())
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil
+	case err != nil:
+		return pathError(flag, path, "file isn't writable", err)
+	default:
+		return f.Close()
+	}
+}
+
+func pathError(flag, path, what string, err error) error {
+	switch {
+	case errors.Is(err, syscall.EROFS):
+		what += " (read-only filesystem)"
+	case errors.Is(err, os.ErrPermission):
+		what += fmt.Sprintf(" (permission denied for uid %d)", os.Geteuid())
+	case errors.Is(err, os.ErrNotExist):
+		what += " (does not exist)"
+	default:
+		return fmt.Errorf("%s %s: %s: %w", flag, path, what, err)
+	}
+	return fmt.Errorf("%s %s: %s", flag, path, what)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+// skipIfRoot skips tests which rely on permissions, which root ignores.
+func skipIfRoot(t *testing.T) {
+	t.Helper()
+	if os.Geteuid() == 0 {
+		t.Skip("permissions don't apply to root")
+	}
+}
+
+func TestProbeListen(t *testing.T) {
+	ln, err := probeListen("--addr", "127.0.0.1:0")
+	assert.NilError(t, err)
+	defer ln.Close()
+
+	_, err = probeListen("--addr", ln.Addr().String())
+	assert.ErrorContains(t, err, "--addr "+ln.Addr().String())
+	assert.ErrorContains(t, err, "already in use")
+}
+
+func TestListenErrorPermission(t *testing.T) {
+	denied := &net.OpError{Op: "listen", Net: "tcp", Err: os.NewSyscallError("bind", syscall.EACCES)}
+
+	err := listenError("--addr", ":80", denied)
+	assert.Error(t, err, "--addr :80: not permitted to listen on port 80; ports below 1024 need root or CAP_NET_BIND_SERVICE, so use a higher port like --addr :5080 and map it to 80 instead")
+
+	err = listenError("--addr", ":8080", denied)
+	assert.ErrorContains(t, err, "--addr :8080: not permitted to listen")
+	assert.Assert(t, !strings.Contains(err.Error(), "1024"))
+}
+
+func TestProbeReadableDir(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, probeReadableDir("--packs-dir", dir))
+
+	missing := filepath.Join(dir, "missing")
+	assert.Error(t, probeReadableDir("--packs-dir", missing), "--packs-dir "+missing+": directory can't be opened (does not exist)")
+
+	file := filepath.Join(dir, "file.txt")
+	assert.NilError(t, ioutil.WriteFile(file, []byte("word"), 0o600))
+	assert.Error(t, probeReadableDir("--packs-dir", file), "--packs-dir "+file+": not a directory")
+}
+
+func TestProbeReadableDirPermission(t *testing.T) {
+	skipIfRoot(t)
+
+	dir := t.TempDir()
+	assert.NilError(t, os.Chmod(dir, 0o100))
+	defer os.Chmod(dir, 0o700) //nolint:errcheck
+
+	err := probeReadableDir("--packs-dir", dir)
+	assert.ErrorContains(t, err, "--packs-dir "+dir)
+	assert.ErrorContains(t, err, "permission denied")
+}
+
+func TestProbeWritableFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "events.jsonl")
+
+	// Neither a new file nor an existing one is a problem, and nothing is
+	// left behind.
+	assert.NilError(t, probeWritableFile("--analytics-file", path))
+	assert.NilError(t, ioutil.WriteFile(path, nil, 0o600))
+	assert.NilError(t, probeWritableFile("--analytics-file", path))
+
+	files, err := ioutil.ReadDir(dir)
+	assert.NilError(t, err)
+	assert.Equal(t, len(files), 1)
+
+	missing := filepath.Join(dir, "missing", "events.jsonl")
+	assert.Error(t, probeWritableFile("--analytics-file", missing), "--analytics-file "+filepath.Dir(missing)+": directory isn't writable (does not exist)")
+
+	assert.Error(t, probeWritableFile("--analytics-file", dir), "--analytics-file "+dir+": is a directory, not a file")
+}
+
+func TestProbeWritableFilePermission(t *testing.T) {
+	skipIfRoot(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "events.jsonl")
+	assert.NilError(t, ioutil.WriteFile(path, nil, 0o400))
+
+	err := probeWritableFile("--analytics-file", path)
+	assert.ErrorContains(t, err, "--analytics-file "+path+": file isn't writable (permission denied")
+
+	assert.NilError(t, os.Chmod(dir, 0o500))
+	defer os.Chmod(dir, 0o700) //nolint:errcheck
+
+	err = probeWritableFile("--analytics-file", path)
+	assert.ErrorContains(t, err, "--analytics-file "+dir+": directory isn't writable (permission denied")
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+
+	"github.com/zikaeroh/codies/internal/server"
+	"github.com/zikaeroh/ctxlog"
+	"go.uber.org/zap"
+)
+
+// loadResumeManifest resumes the rooms in the manifest at path, then removes
+// it, so that if the server crashes later, it doesn't resume them again. A
+// missing manifest, as after a crash, resumes nothing.
+func loadResumeManifest(ctx context.Context, srv *server.Server, path string) error {
+	b, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var m server.ResumeManifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+
+	resumed, err := srv.Resume(ctx, &m)
+	if err != nil {
+		return err
+	}
+	ctxlog.Info(ctx, "resumed rooms", zap.Int("resumed", resumed), zap.Int("rooms", len(m.Rooms)), zap.Time("written", m.Written))
+
+	return os.Remove(path)
+}
+
+// saveResumeManifest writes a manifest of the server's rooms to path,
+// replacing it. It should only be called once the server has stopped.
+func saveResumeManifest(srv *server.Server, path string) error {
+	b, err := json.Marshal(srv.ResumeManifest())
+	if err != nil {
+		return err
+	}
+	return replaceFile(path, ".codies-resume-*", b)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zikaeroh/codies/internal/server"
+	"gotest.tools/v3/assert"
+)
+
+func TestResumeManifestFile(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "resume.json")
+
+	// A missing manifest, as after a crash, resumes nothing.
+	srv := newTestServer(t)
+	assert.NilError(t, loadResumeManifest(ctx, srv, path))
+
+	assert.NilError(t, saveResumeManifest(srv, path))
+	var saved server.ResumeManifest
+	b, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	assert.NilError(t, json.Unmarshal(b, &saved))
+	assert.Equal(t, saved.Version, server.ResumeManifestVersion)
+
+	m := &server.ResumeManifest{
+		Version: server.ResumeManifestVersion,
+		Rooms:   []*server.ResumeRoom{{ID: "resumed-id", Name: "friday"}},
+	}
+	b, err = json.Marshal(m)
+	assert.NilError(t, err)
+	assert.NilError(t, ioutil.WriteFile(path, b, 0o600))
+
+	// Once resumed, the manifest is gone, so a crash can't resume it again.
+	restarted := newTestServer(t)
+	assert.NilError(t, loadResumeManifest(ctx, restarted, path))
+	assert.Assert(t, restarted.FindRoomByID("resumed-id") != nil)
+	_, err = os.Stat(path)
+	assert.Assert(t, os.IsNotExist(err))
+
+	assert.NilError(t, ioutil.WriteFile(path, []byte("not json"), 0o600))
+	assert.Assert(t, loadResumeManifest(ctx, restarted, path) != nil)
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/zikaeroh/codies/internal/pkger"
+	"github.com/zikaeroh/codies/internal/version"
+	"github.com/zikaeroh/ctxlog"
+	"go.uber.org/zap"
+)
+
+type middlewareFunc = func(http.Handler) http.Handler
+
+// wsPath is where clients connect their WebSockets.
+const wsPath = "/api/ws"
+
+// middlewares are the main router's middlewares, by what they do. Which run
+// for a path, and in what order, is up to newRouter alone; see
+// TestRouterMiddleware.
+type middlewares struct {
+	realIP       middlewareFunc // Nil unless proxy headers are trusted.
+	heartbeat    middlewareFunc
+	count        middlewareFunc
+	accessLog    middlewareFunc
+	noCache      middlewareFunc
+	versions     middlewareFunc
+	compress     middlewareFunc
+	checkVersion middlewareFunc // Nil if client versions aren't checked.
+	recoverer    middlewareFunc
+}
+
+func defaultMiddlewares(ctx context.Context, mode *runMode, stale *staleVersions, seen *seenVersions, compat *version.Compat) *middlewares {
+	mw := &middlewares{
+		heartbeat: middleware.Heartbeat("/ping"),
+		count: func(next http.Handler) http.Handler {
+			return promhttp.InstrumentHandlerCounter(metricRequest, next)
+		},
+		accessLog: accessLog(ctx),
+		noCache:   middleware.NoCache,
+		versions:  trackVersions(seen),
+		compress:  middleware.Compress(5),
+		recoverer: recoverer(ctx),
+	}
+
+	if args.RealIP {
+		mw.realIP = middleware.RealIP
+	}
+
+	if mode.VersionCheck {
+		mw.checkVersion = checkVersion(ctx, stale, compat)
+	}
+
+	return mw
+}
+
+// routes are the main router's handlers. API routes are registered by their
+// full paths.
+type routes struct {
+	files  http.Handler // The frontend's build.
+	admin  http.Handler // Nil if the admin API is disabled.
+	api    func(r chi.Router)
+	client func(r chi.Router) // API routes only compatible clients may use.
+	ws     http.Handler
+}
+
+// newRouter builds the main router.
+//
+// Counting and access logs are for the API alone; static files would swamp
+// both. Panics are recovered innermost, so that the request they fail is
+// counted and logged like any other. WebSocket connections compress their
+// own messages, so they're never wrapped by a compressing writer. Hashed
+// assets may be cached forever; anything else from the frontend may not.
+func newRouter(mw *middlewares, rt *routes) http.Handler {
+	r := chi.NewMux()
+
+	if mw.realIP != nil {
+		r.Use(mw.realIP)
+	}
+	r.Use(mw.heartbeat)
+
+	r.NotFound(staticRoutes(mw, rt.files).ServeHTTP)
+
+	if rt.admin != nil {
+		r.With(mw.recoverer).Mount("/admin", rt.admin)
+	}
+
+	var checked []middlewareFunc
+	if mw.checkVersion != nil {
+		checked = append(checked, mw.checkVersion)
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Use(mw.count, mw.accessLog, mw.noCache, mw.versions)
+
+		r.With(mw.compress).Group(func(r chi.Router) {
+			r.With(mw.recoverer).Group(rt.api)
+			r.With(checked...).With(mw.recoverer).Group(rt.client)
+		})
+
+		r.With(checked...).With(mw.recoverer).Handle(wsPath, rt.ws)
+	})
+
+	return r
+}
+
+func staticRoutes(mw *middlewares, files http.Handler) http.Handler {
+	r := chi.NewMux()
+	r.Use(mw.compress)
+
+	r.Handle("/static/*", files)
+	r.Handle("/favicon/*", files)
+
+	r.Group(func(r chi.Router) {
+		r.Use(mw.noCache)
+		r.Handle("/*", files)
+	})
+
+	return r
+}
+
+func staticFiles() http.Handler {
+	return http.FileServer(pkger.Dir("/frontend/build"))
+}
+
+// accessLog logs API requests at debug level. Only paths are logged, as query
+// strings carry nicknames and tokens.
+func accessLog(ctx context.Context) middlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r)
+
+			ctxlog.Debug(ctx, "request",
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.Int("status", ww.Status()),
+				zap.Duration("duration", time.Since(start)),
+			)
+		})
+	}
+}
+
+// recoverer recovers panics, logging and counting them, and responds with an
+// internal server error. It replaces middleware.Recoverer, whose stack
+// printing itself panics on stacks from newer versions of Go.
+func recoverer(ctx context.Context) middlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rvr := recover()
+				if rvr == nil {
+					return
+				}
+
+				if rvr == http.ErrAbortHandler { //nolint:errorlint
+					panic(rvr)
+				}
+
+				metricPanics.Inc()
+				ctxlog.Error(ctx, "panic serving request", zap.Any("panic", rvr), zap.String("path", r.URL.Path), zap.Stack("stack"))
+				w.WriteHeader(http.StatusInternalServerError)
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gotest.tools/v3/assert"
+)
+
+// recordingRouter builds the main router with middlewares and handlers which
+// record that they ran, in order.
+func recordingRouter(admin, versionCheck bool) (http.Handler, *[]string) {
+	var ran []string
+
+	record := func(name string) middlewareFunc {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ran = append(ran, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	handler := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			ran = append(ran, name)
+		}
+	}
+
+	mw := &middlewares{
+		realIP: record("realIP"),
+		heartbeat: func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ran = append(ran, "heartbeat")
+				if r.URL.Path != "/ping" {
+					next.ServeHTTP(w, r)
+				}
+			})
+		},
+		count:     record("count"),
+		accessLog: record("accessLog"),
+		noCache:   record("noCache"),
+		versions:  record("versions"),
+		compress:  record("compress"),
+		recoverer: record("recoverer"),
+	}
+	if versionCheck {
+		mw.checkVersion = record("checkVersion")
+	}
+
+	rt := &routes{
+		files: handler("files"),
+		api: func(r chi.Router) {
+			r.Get("/api/time", handler("api"))
+		},
+		client: func(r chi.Router) {
+			r.Get("/api/exists", handler("client"))
+			r.Post("/api/room", handler("client"))
+		},
+		ws: handler("ws"),
+	}
+	if admin {
+		rt.admin = handler("admin")
+	}
+
+	return newRouter(mw, rt), &ran
+}
+
+func TestRouterMiddleware(t *testing.T) {
+	api := []string{"realIP", "heartbeat", "count", "accessLog", "noCache", "versions"}
+	with := func(base []string, names ...string) []string {
+		return append(append([]string(nil), base...), names...)
+	}
+
+	tests := []struct {
+		method       string
+		path         string
+		admin        bool
+		versionCheck bool
+		want         []string
+	}{
+		{"GET", "/ping", true, true, []string{"realIP", "heartbeat"}},
+		{"GET", "/", true, true, []string{"realIP", "heartbeat", "compress", "noCache", "files"}},
+		{"GET", "/index.html", true, true, []string{"realIP", "heartbeat", "compress", "noCache", "files"}},
+		{"GET", "/static/js/main.0123abcd.js", true, true, []string{"realIP", "heartbeat", "compress", "files"}},
+		{"GET", "/favicon/favicon.ico", true, true, []string{"realIP", "heartbeat", "compress", "files"}},
+		{"GET", "/api/time", true, true, with(api, "compress", "recoverer", "api")},
+		{"GET", "/api/exists", true, true, with(api, "compress", "checkVersion", "recoverer", "client")},
+		{"POST", "/api/room", true, true, with(api, "compress", "checkVersion", "recoverer", "client")},
+		{"GET", "/api/ws", true, true, with(api, "checkVersion", "recoverer", "ws")},
+		{"GET", "/api/exists", true, false, with(api, "compress", "recoverer", "client")},
+		{"GET", "/api/ws", true, false, with(api, "recoverer", "ws")},
+		{"GET", "/admin/stats", true, true, []string{"realIP", "heartbeat", "recoverer", "admin"}},
+		{"GET", "/admin/stats", false, true, []string{"realIP", "heartbeat", "compress", "noCache", "files"}},
+		{"GET", "/api/missing", true, true, []string{"realIP", "heartbeat", "compress", "noCache", "files"}},
+	}
+
+	for _, test := range tests {
+		h, ran := recordingRouter(test.admin, test.versionCheck)
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(test.method, test.path, nil))
+		assert.DeepEqual(t, *ran, test.want)
+	}
+}
+
+func TestRouterPanics(t *testing.T) {
+	mw := defaultMiddlewares(context.Background(), &runMode{}, newStaleVersions(), newSeenVersions(), nil)
+	h := newRouter(mw, &routes{
+		files: http.NotFoundHandler(),
+		api: func(r chi.Router) {
+			r.Get("/api/time", func(w http.ResponseWriter, r *http.Request) {
+				panic("oops")
+			})
+		},
+		client: func(r chi.Router) {},
+		ws:     http.NotFoundHandler(),
+	})
+
+	panics := testutil.ToFloat64(metricPanics)
+	served := testutil.ToFloat64(metricRequest.WithLabelValues("500", "get"))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/time", nil))
+	assert.Equal(t, rec.Code, http.StatusInternalServerError)
+
+	assert.Equal(t, testutil.ToFloat64(metricPanics), panics+1)
+	assert.Equal(t, testutil.ToFloat64(metricRequest.WithLabelValues("500", "get")), served+1)
+
+	// Static files aren't counted.
+	before := testutil.ToFloat64(metricRequest.WithLabelValues("404", "get"))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/static/missing.js", nil))
+	assert.Equal(t, testutil.ToFloat64(metricRequest.WithLabelValues("404", "get")), before)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/adminapi"
+	"github.com/zikaeroh/codies/internal/version"
+)
+
+const (
+	maxStaleVersions      = 20
+	maxStaleVersionLength = 64
+)
+
+// staleVersions remembers the most recently seen distinct stale versions.
+type staleVersions struct {
+	mu       sync.Mutex
+	versions map[string]*adminapi.StaleVersion
+}
+
+func newStaleVersions() *staleVersions {
+	return &staleVersions{
+		versions: make(map[string]*adminapi.StaleVersion),
+	}
+}
+
+func (s *staleVersions) record(claimed string, staleness version.Staleness, now time.Time) {
+	if len(claimed) > maxStaleVersionLength {
+		claimed = claimed[:maxStaleVersionLength]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v := s.versions[claimed]
+	if v == nil {
+		if len(s.versions) >= maxStaleVersions {
+			s.evictOldest()
+		}
+		v = &adminapi.StaleVersion{Version: claimed, Staleness: staleness}
+		s.versions[claimed] = v
+	}
+
+	v.Count++
+	v.LastSeen = now
+}
+
+// Must be called with s.mu locked.
+func (s *staleVersions) evictOldest() {
+	var oldest *adminapi.StaleVersion
+	for _, v := range s.versions {
+		if oldest == nil || v.LastSeen.Before(oldest.LastSeen) {
+			oldest = v
+		}
+	}
+	if oldest != nil {
+		delete(s.versions, oldest.Version)
+	}
+}
+
+// list returns the stale versions, most recently seen first.
+func (s *staleVersions) list() []adminapi.StaleVersion {
+	s.mu.Lock()
+	list := make([]adminapi.StaleVersion, 0, len(s.versions))
+	for _, v := range s.versions {
+		list = append(list, *v)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].LastSeen.Equal(list[j].LastSeen) {
+			return list[i].LastSeen.After(list[j].LastSeen)
+		}
+		return list[i].Version < list[j].Version
+	})
+	return list
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/adminapi"
+	"github.com/zikaeroh/codies/internal/version"
+	"gotest.tools/v3/assert"
+)
+
+func TestStaleVersionsEvictsOldest(t *testing.T) {
+	s := newStaleVersions()
+	start := time.Now()
+
+	for i := 0; i < maxStaleVersions+5; i++ {
+		s.record(fmt.Sprintf("v1.0.%d", i), version.Older, start.Add(time.Duration(i)*time.Second))
+	}
+	s.record("v1.0.10", version.Older, start.Add(time.Hour))
+
+	list := s.list()
+	assert.Equal(t, len(list), maxStaleVersions)
+	assert.Equal(t, list[0].Version, "v1.0.10")
+	assert.Equal(t, list[0].Count, int64(2))
+
+	for _, v := range list {
+		assert.Assert(t, v.Version != "v1.0.0", "oldest version should have been evicted")
+	}
+}
+
+func TestStaleVersionsTruncates(t *testing.T) {
+	s := newStaleVersions()
+	long := string(make([]byte, 1000))
+	s.record(long, version.Unknown, time.Now())
+	s.record(long+"x", version.Unknown, time.Now())
+
+	list := s.list()
+	assert.Equal(t, len(list), 1)
+	assert.Equal(t, len(list[0].Version), maxStaleVersionLength)
+	assert.Equal(t, list[0].Count, int64(2))
+}
+
+func TestCheckVersionRecordsMismatch(t *testing.T) {
+	stale := newStaleVersions()
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := checkVersion(context.Background(), stale, &version.Compat{})(ok)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/exists", nil)
+	req.Header.Set("X-CODIES-VERSION", version.Version())
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, rec.Code, http.StatusOK)
+	assert.Equal(t, len(stale.list()), 0)
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/api/exists?codiesVersion=v0.0.1", nil)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, rec.Code, http.StatusTeapot)
+	}
+
+	adm := adminHandler(newTestServer(t), stale, newSeenVersions(), newLogRing(false), time.Now(), "secret")
+	req = httptest.NewRequest(http.MethodGet, "/versions/stale", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	adm.ServeHTTP(rec, req)
+	assert.Equal(t, rec.Code, http.StatusOK)
+
+	var list []adminapi.StaleVersion
+	assert.NilError(t, json.NewDecoder(rec.Body).Decode(&list))
+	assert.Equal(t, len(list), 1)
+	assert.Equal(t, list[0].Version, "v0.0.1")
+	assert.Equal(t, list[0].Staleness, version.Unknown) // The test binary has no version.
+	assert.Equal(t, list[0].Count, int64(2))
+}
+
+func TestCheckVersionCompat(t *testing.T) {
+	stale := newStaleVersions()
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	compat := &version.Compat{Accept: []string{"v0.0.1"}, Start: time.Now()}
+	h := checkVersion(context.Background(), stale, compat)(ok)
+
+	check := func(claimed string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/exists", nil)
+		req.Header.Set("X-CODIES-VERSION", claimed)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, check(version.Version()), http.StatusOK)
+	assert.Equal(t, check("v0.0.1"), http.StatusOK)
+	assert.Equal(t, check("v0.0.2"), http.StatusTeapot)
+
+	// Everything is accepted during the grace period, and isn't recorded as stale.
+	compat.Grace = time.Hour
+	assert.Equal(t, check("v0.0.3"), http.StatusOK)
+
+	list := stale.list()
+	assert.Equal(t, len(list), 1)
+	assert.Equal(t, list[0].Version, "v0.0.2")
+}
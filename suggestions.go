@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/server"
+	"github.com/zikaeroh/ctxlog"
+	"go.uber.org/zap"
+)
+
+// suggestionsInterval is how often word suggestions are saved to
+// --word-suggestions-file, besides when the server stops.
+const suggestionsInterval = 5 * time.Minute
+
+// wordSuggestionsHandler exports the word suggestions as CSV, most suggested
+// first, for curating the built-in packs.
+func wordSuggestionsHandler(srv *server.Server) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="suggestions.csv"`)
+
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"word", "pack", "language", "count", "first", "last"}) //nolint:errcheck
+		for _, s := range srv.WordSuggestions() {
+			cw.Write([]string{ //nolint:errcheck
+				s.Word,
+				s.Pack,
+				s.Language,
+				strconv.Itoa(s.Count),
+				s.First.UTC().Format(time.RFC3339),
+				s.Last.UTC().Format(time.RFC3339),
+			})
+		}
+		cw.Flush()
+	}
+}
+
+// loadWordSuggestions restores the suggestions saved to path. A missing file
+// has none.
+func loadWordSuggestions(srv *server.Server, path string) error {
+	b, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var list []*server.WordSuggestion
+	if err := json.Unmarshal(b, &list); err != nil {
+		return err
+	}
+	srv.RestoreWordSuggestions(list)
+	return nil
+}
+
+// saveWordSuggestions writes the suggestions to path, replacing it.
+func saveWordSuggestions(srv *server.Server, path string) error {
+	b, err := json.Marshal(srv.WordSuggestions())
+	if err != nil {
+		return err
+	}
+	return replaceFile(path, ".codies-suggestions-*", b)
+}
+
+// replaceFile writes b to a temporary file named by pattern, then renames it
+// to path, so that a crash while writing never leaves a partial file.
+func replaceFile(path, pattern string, b []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), pattern)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// keepWordSuggestions saves the suggestions every suggestionsInterval, and
+// once more when ctx is done.
+func keepWordSuggestions(ctx context.Context, srv *server.Server, path string) {
+	ticker := time.NewTicker(suggestionsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := saveWordSuggestions(srv, path); err != nil {
+				ctxlog.Error(ctx, "error saving word suggestions", zap.Error(err))
+			}
+			return
+		case <-ticker.C:
+			if err := saveWordSuggestions(srv, path); err != nil {
+				ctxlog.Error(ctx, "error saving word suggestions", zap.Error(err))
+			}
+		}
+	}
+}
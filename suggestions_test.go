@@ -0,0 +1,64 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/server"
+	"gotest.tools/v3/assert"
+)
+
+func TestAdminWordSuggestions(t *testing.T) {
+	srv := newTestServer(t)
+	h := adminHandler(srv, newStaleVersions(), newSeenVersions(), newLogRing(false), time.Now(), "secret")
+
+	first := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	srv.RestoreWordSuggestions([]*server.WordSuggestion{
+		{Word: "OTTER", Pack: "Base", Count: 1, First: first, Last: first},
+		{Word: "AXOLOTL, PINK", Pack: "Base", Language: "en", Count: 3, First: first, Last: first.Add(time.Hour)},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/suggestions.csv", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, rec.Code, http.StatusOK)
+	assert.Equal(t, rec.Header().Get("Content-Type"), "text/csv; charset=utf-8")
+	assert.Equal(t, rec.Body.String(), ""+
+		"word,pack,language,count,first,last\n"+
+		"\"AXOLOTL, PINK\",Base,en,3,2020-01-01T00:00:00Z,2020-01-01T01:00:00Z\n"+
+		"OTTER,Base,,1,2020-01-01T00:00:00Z,2020-01-01T00:00:00Z\n")
+}
+
+func TestWordSuggestionsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "suggestions.json")
+
+	// A missing file has nothing to restore.
+	srv := newTestServer(t)
+	assert.NilError(t, loadWordSuggestions(srv, path))
+	assert.Equal(t, len(srv.WordSuggestions()), 0)
+
+	first := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	want := []*server.WordSuggestion{
+		{Word: "AXOLOTL", Pack: "Base", Language: "en", Count: 3, First: first, Last: first},
+	}
+	srv.RestoreWordSuggestions(want)
+	assert.NilError(t, saveWordSuggestions(srv, path))
+
+	restarted := newTestServer(t)
+	assert.NilError(t, loadWordSuggestions(restarted, path))
+	assert.DeepEqual(t, restarted.WordSuggestions(), want)
+
+	// Nothing is left beside it.
+	files, err := ioutil.ReadDir(filepath.Dir(path))
+	assert.NilError(t, err)
+	assert.Equal(t, len(files), 1)
+
+	assert.NilError(t, ioutil.WriteFile(path, []byte("not json"), 0o600))
+	assert.Assert(t, loadWordSuggestions(restarted, path) != nil)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/adminapi"
+	"github.com/zikaeroh/codies/internal/version"
+)
+
+const (
+	maxSeenVersions = 20
+	seenWindow      = time.Hour
+	seenBuckets     = 60
+	seenBucket      = seenWindow / seenBuckets
+)
+
+// seenVersions counts the versions clients have claimed in the last hour.
+// Counts are kept in buckets of a minute, which age out as the window moves.
+// Only versions which pass version.Clean are counted, and only so many are
+// kept at once, so clients can't make it grow.
+type seenVersions struct {
+	mu       sync.Mutex
+	versions map[string]*seenVersion
+}
+
+type seenVersion struct {
+	counts   [seenBuckets]int64
+	buckets  [seenBuckets]int64 // Which bucket each count is for.
+	lastSeen time.Time
+}
+
+func newSeenVersions() *seenVersions {
+	return &seenVersions{
+		versions: make(map[string]*seenVersion),
+	}
+}
+
+// trackVersions echoes the server's version on every response, and counts the
+// version each request claims, so that bug reports can capture both sides.
+func trackVersions(seen *seenVersions) middlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-CODIES-SERVER-VERSION", version.Version())
+
+			claimed := r.Header.Get("X-CODIES-VERSION")
+			if claimed == "" {
+				claimed = r.URL.Query().Get("codiesVersion")
+			}
+			seen.record(claimed, time.Now())
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func (s *seenVersions) record(claimed string, now time.Time) {
+	claimed = version.Clean(claimed)
+	if claimed == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v := s.versions[claimed]
+	if v == nil {
+		s.expire(now)
+		if len(s.versions) >= maxSeenVersions {
+			s.evictOldest()
+		}
+		v = &seenVersion{}
+		s.versions[claimed] = v
+	}
+
+	b := now.UnixNano() / int64(seenBucket)
+	i := b % seenBuckets
+	if v.buckets[i] != b {
+		v.buckets[i] = b
+		v.counts[i] = 0
+	}
+	v.counts[i]++
+	v.lastSeen = now
+}
+
+// Must be called with s.mu locked.
+func (s *seenVersions) expire(now time.Time) {
+	for claimed, v := range s.versions {
+		if now.Sub(v.lastSeen) >= seenWindow {
+			delete(s.versions, claimed)
+		}
+	}
+}
+
+// Must be called with s.mu locked.
+func (s *seenVersions) evictOldest() {
+	oldest := ""
+	for claimed, v := range s.versions {
+		if oldest == "" || v.lastSeen.Before(s.versions[oldest].lastSeen) {
+			oldest = claimed
+		}
+	}
+	delete(s.versions, oldest)
+}
+
+// list returns the versions seen in the last hour, most often seen first.
+func (s *seenVersions) list(now time.Time) []adminapi.ClientVersion {
+	b := now.UnixNano() / int64(seenBucket)
+
+	s.mu.Lock()
+	s.expire(now)
+	list := make([]adminapi.ClientVersion, 0, len(s.versions))
+	for claimed, v := range s.versions {
+		cv := adminapi.ClientVersion{
+			Version:   claimed,
+			Staleness: version.Classify(claimed),
+			LastSeen:  v.lastSeen,
+		}
+		for i, count := range v.counts {
+			if b-v.buckets[i] < seenBuckets {
+				cv.Count += count
+			}
+		}
+		if cv.Count != 0 {
+			list = append(list, cv)
+		}
+	}
+	s.mu.Unlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Version < list[j].Version
+	})
+	return list
+}
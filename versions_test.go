@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zikaeroh/codies/internal/adminapi"
+	"github.com/zikaeroh/codies/internal/version"
+	"gotest.tools/v3/assert"
+)
+
+func TestSeenVersionsWindow(t *testing.T) {
+	s := newSeenVersions()
+	start := time.Now()
+
+	s.record("v1.0.0", start)
+	s.record("v1.0.0", start)
+	s.record("v1.0.1", start.Add(30*time.Minute))
+	s.record("v1.0.0", start.Add(40*time.Minute))
+
+	list := s.list(start.Add(45 * time.Minute))
+	assert.Equal(t, len(list), 2)
+	assert.Equal(t, list[0].Version, "v1.0.0")
+	assert.Equal(t, list[0].Count, int64(3))
+	assert.Equal(t, list[0].LastSeen, start.Add(40*time.Minute))
+	assert.Equal(t, list[1].Version, "v1.0.1")
+	assert.Equal(t, list[1].Count, int64(1))
+
+	// The first two age out of the window, then everything does.
+	list = s.list(start.Add(65 * time.Minute))
+	assert.Equal(t, len(list), 2)
+	assert.Equal(t, list[0].Count, int64(1))
+	assert.Equal(t, list[1].Count, int64(1))
+
+	assert.Equal(t, len(s.list(start.Add(2*time.Hour))), 0)
+}
+
+func TestSeenVersionsBounded(t *testing.T) {
+	s := newSeenVersions()
+	start := time.Now()
+
+	for _, claimed := range []string{"", "abcdef0", "v1.0.0-<script>", "v1.0"} {
+		s.record(claimed, start)
+	}
+	assert.Equal(t, len(s.list(start)), 0)
+
+	for i := 0; i < maxSeenVersions+5; i++ {
+		s.record(fmt.Sprintf("v1.0.%d", i), start.Add(time.Duration(i)*time.Second))
+	}
+
+	list := s.list(start.Add(time.Minute))
+	assert.Equal(t, len(list), maxSeenVersions)
+	for _, v := range list {
+		assert.Assert(t, v.Version != "v1.0.0", "oldest version should have been evicted")
+	}
+}
+
+func TestTrackVersions(t *testing.T) {
+	seen := newSeenVersions()
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := trackVersions(seen)(ok)
+
+	// The header is sent with API calls, the query with WebSockets.
+	for _, claimed := range []struct{ target, header string }{
+		{"/api/exists", "v0.0.1"},
+		{"/api/ws?codiesVersion=v0.0.1", ""},
+		{"/api/time", version.Version()},
+	} {
+		req := httptest.NewRequest(http.MethodGet, claimed.target, nil)
+		if claimed.header != "" {
+			req.Header.Set("X-CODIES-VERSION", claimed.header)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, rec.Header().Get("X-CODIES-SERVER-VERSION"), version.Version())
+	}
+
+	adm := adminHandler(newTestServer(t), newStaleVersions(), seen, newLogRing(false), time.Now(), "secret")
+	req := httptest.NewRequest(http.MethodGet, "/overview", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	adm.ServeHTTP(rec, req)
+	assert.Equal(t, rec.Code, http.StatusOK)
+
+	o := &adminapi.Overview{}
+	assert.NilError(t, json.NewDecoder(rec.Body).Decode(o))
+	assert.DeepEqual(t, versionCounts(o.ClientVersions), map[string]int64{"v0.0.1": 2, version.Version(): 1})
+}
+
+func versionCounts(list []adminapi.ClientVersion) map[string]int64 {
+	counts := make(map[string]int64, len(list))
+	for _, v := range list {
+		counts[v.Version] = v.Count
+	}
+	return counts
+}